@@ -24,6 +24,13 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/cloud-provider-vsphere/cmd/vcpctl/provision"
+
+	// register the cloud config sections read by the cloud provider, so that
+	// they are not reported as unknown
+	_ "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	_ "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+	_ "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/route/config"
+	_ "k8s.io/cloud-provider-vsphere/pkg/nsxt/config"
 )
 
 func main() {
@@ -20,9 +20,12 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"context"
 	"flag"
 	goflag "flag"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"os/signal"
@@ -30,10 +33,18 @@ import (
 	"syscall"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	k8s "k8s.io/cloud-provider-vsphere/pkg/common/kubernetes"
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util"
+	"k8s.io/cloud-provider-vsphere/pkg/util/debugdump"
+	"k8s.io/cloud-provider-vsphere/pkg/util/health"
+	"k8s.io/cloud-provider-vsphere/pkg/util/secureapi"
 	"k8s.io/cloud-provider/app"
 	appconfig "k8s.io/cloud-provider/app/config"
 	"k8s.io/cloud-provider/names"
@@ -64,6 +75,7 @@ var version string
 func main() {
 	loadbalancer.Version = version
 	loadbalancer.AppName = AppName
+	vclib.Version = version
 
 	rand.Seed(time.Now().UTC().UnixNano())
 
@@ -72,6 +84,12 @@ func main() {
 		klog.Fatalf("unable to initialize command options: %v", err)
 	}
 
+	// the webhook validating the cloud config is served only once enabled
+	// with --webhooks and the CloudControllerManagerWebhook feature gate
+	app.AllWebhooks = []string{vsphere.CloudConfigWebhookName}
+	app.DisabledByDefaultWebhooks = []string{vsphere.CloudConfigWebhookName}
+	app.WebhooksDisabledByDefault.Insert(vsphere.CloudConfigWebhookName)
+
 	var controllerInitializers map[string]app.InitFunc
 	command := &cobra.Command{
 		Use:  AppName,
@@ -89,12 +107,39 @@ func main() {
 	fs := command.Flags()
 	namedFlagSets := ccmOptions.Flags(app.ControllerNames(app.DefaultInitFuncConstructors), app.ControllersDisabledByDefault.List(), names.CCMControllerAliases(), app.AllWebhooks, app.DisabledByDefaultWebhooks)
 	verflag.AddFlags(namedFlagSets.FlagSet("global"))
+	var checksumObject string
+	namedFlagSets.FlagSet("generic").StringVar(&checksumObject, "cloud-config-checksum-object", "",
+		"ConfigMap or Lease, as configmap/<namespace>/<name> or lease/<namespace>/<name>, annotated with "+
+			k8s.CloudConfigChecksumAnnotation+" set to the checksum of the active cloud config, and with "+k8s.CloudConfigReloadAnnotation+
+			" set to whether the last loaded cloud config was applied, partially applied or rejected. A missing Lease is created.")
+	var profileName string
+	namedFlagSets.FlagSet("generic").StringVar(&profileName, "profile", string(vsphere.ProfileFull),
+		"Preset of the controllers to run, full, node-only or lb-only. node-only and lb-only select the controllers "+
+			"instead of --controllers and skip the initialization of the subsystems they do not use, for split deployments.")
+	var healthBindAddress string
+	namedFlagSets.FlagSet("generic").StringVar(&healthBindAddress, "health-bind-address", "",
+		"Address, such as :10260, of the /healthz and /readyz endpoints checking the vCenter sessions, NSX-T if the load balancer "+
+			"is enabled, and the supervisor apiserver in paravirtual mode. The endpoints are not served if empty.")
+	var secureAPIBindAddress string
+	namedFlagSets.FlagSet("generic").StringVar(&secureAPIBindAddress, "secure-api-bind-address", "",
+		"Address, such as :10259, of the read-only API of the node mappings, the node discovery state and the debug dump, served with the "+
+			"certificate of the secure port and authorized per endpoint with SubjectAccessReviews of its non-resource URL. "+
+			"The API is not served if empty.")
+	var dumpEffectiveConfig bool
+	namedFlagSets.FlagSet("generic").BoolVar(&dumpEffectiveConfig, "dump-effective-config", false,
+		"Print the effective cloud config, once the VSPHERE_* environment variables and the defaults are applied, "+
+			"with the secrets redacted, and exit. It is also served at /debug/dump?source=vsphere.effectiveConfig "+
+			"of the secure API bind address.")
+	var convertLegacyParavirtual bool
+	namedFlagSets.FlagSet("generic").BoolVar(&convertLegacyParavirtual, vsphereparavirtual.ConvertLegacyParavirtualFlag, false,
+		"Print the command line arguments equivalent to the current ones without the deprecated --"+vsphereparavirtual.LegacyParavirtualFlag+
+			" flag, and the labels the VMs must carry once it is dropped, and exit.")
 	globalflag.AddGlobalFlags(namedFlagSets.FlagSet("global"), command.Name())
 
-	if flag.CommandLine.Lookup("is-legacy-paravirtual") != nil {
+	if flag.CommandLine.Lookup(vsphereparavirtual.LegacyParavirtualFlag) != nil {
 		// hoist this flag from the global flagset to preserve the commandline until
 		// the legcay paravirtual mode is removed.
-		globalflag.Register(namedFlagSets.FlagSet("generic"), "is-legacy-paravirtual")
+		globalflag.Register(namedFlagSets.FlagSet("generic"), vsphereparavirtual.LegacyParavirtualFlag)
 	}
 
 	for _, f := range namedFlagSets.FlagSets {
@@ -181,20 +226,77 @@ func main() {
 
 		completedConfig := c.Complete()
 
-		cloud := initializeCloud(completedConfig, cloudProvider)
+		if convertLegacyParavirtual {
+			if err := vsphereparavirtual.WriteLegacyParavirtualConversion(os.Stdout, vsphereparavirtual.ClusterName, os.Args[1:]); err != nil {
+				klog.Fatalf("cannot convert the legacy paravirtual config: %v", err)
+			}
+			os.Exit(0)
+		}
+
+		if dumpEffectiveConfig {
+			if err := dumpConfig(completedConfig, cloudProvider); err != nil {
+				klog.Fatalf("cannot dump the effective cloud config: %v", err)
+			}
+			os.Exit(0)
+		}
+
+		cloudConfig := completedConfig.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile
+		checksum := ""
+		if byConfig, err := vcfg.ReadConfigFiles(cloudConfig); err == nil {
+			checksum = vcfg.Checksum(byConfig)
+			klog.Infof("cloud config checksum: %s", checksum)
+		} else {
+			klog.Warningf("fail to read cloud config file %s for its checksum: %v", cloudConfig, err)
+		}
+		if checksumObject != "" {
+			if _, _, _, err := k8s.ParseChecksumObject(checksumObject); err != nil {
+				klog.Fatalf("invalid cloud-config-checksum-object: %v", err)
+			}
+		}
+
+		cloud, err := initializeCloud(completedConfig, cloudProvider)
+		if err != nil {
+			// published before exiting, as the cloud provider never runs
+			// with a rejected config
+			publishConfigReload(completedConfig, checksumObject, k8s.ConfigReloadStatus{
+				Result:   k8s.ConfigReloadRejected,
+				Reason:   err.Error(),
+				Checksum: checksum,
+				Time:     metav1.Now(),
+			})
+			klog.Fatalf("Cloud provider could not be initialized: %v", err)
+		}
+		go publishConfigReload(completedConfig, checksumObject, configReloadStatus(cloud, checksum))
+
 		controllerInitializers = app.ConstructControllerInitializers(app.DefaultInitFuncConstructors, completedConfig, cloud)
 		webhookConfig := make(map[string]app.WebhookConfig)
+		if cloudProvider == vsphere.RegisteredProviderName {
+			webhookConfig[vsphere.CloudConfigWebhookName] = app.WebhookConfig{
+				Path:             vsphere.CloudConfigWebhookPath,
+				AdmissionHandler: vsphere.AdmitCloudConfig,
+			}
+		}
 		webhookHandlers := app.NewWebhookHandlers(webhookConfig, completedConfig, cloud)
 
 		// initialize a notifier for cloud config update
-		cloudConfig := completedConfig.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile
 		klog.Infof("initialize notifier on configmap and service token update %s\n", cloudConfig)
 
+		// each file of a composed cloud config is watched with its own checksum
 		pathsToMonitor := []string{cloudConfig}
+		fileChecksums := map[string]string{cloudConfig: checksum}
+		if files, err := vcfg.ResolveConfigFiles(cloudConfig); err == nil {
+			pathsToMonitor = files
+			fileChecksums = map[string]string{}
+			for _, file := range files {
+				if byConfig, err := os.ReadFile(file); err == nil {
+					fileChecksums[file] = vcfg.Checksum(byConfig)
+				}
+			}
+		}
 		if cloudProvider == vsphereparavirtual.RegisteredProviderName {
 			pathsToMonitor = append(pathsToMonitor, SupervisorServiceAccountPath)
 		}
-		watch, stop, err := initializeWatch(completedConfig, pathsToMonitor)
+		watch, stop, err := initializeWatch(completedConfig, pathsToMonitor, fileChecksums)
 		if err != nil {
 			klog.Fatalf("fail to initialize watch on config map %s: %v\n", cloudConfig, err)
 		}
@@ -212,6 +314,22 @@ func main() {
 			close(stop)
 		}()
 
+		// Log the internal state of the cloud provider on SIGUSR1 to diagnose
+		// hangs without restarting the pod
+		debugdump.DumpOnSignal(stop, syscall.SIGUSR1)
+
+		if healthBindAddress != "" {
+			health.Serve(healthBindAddress, stop)
+		}
+
+		if secureAPIBindAddress != "" {
+			secureapi.Register(debugdump.HandlerPath, debugdump.Handler())
+			if err := secureapi.Serve(secureAPIBindAddress, stop, completedConfig.SecureServing,
+				&completedConfig.Authentication, &completedConfig.Authorization); err != nil {
+				klog.Fatalf("cannot serve the secure API: %v", err)
+			}
+		}
+
 		if err := app.Run(completedConfig, cloud, controllerInitializers, webhookHandlers, stop); err != nil {
 			// explicitly ignore the error by Fprintf, exiting anyway due to app error
 			// We don't call SessionLogout here since errors after initialization aren't bubbled up to here
@@ -230,7 +348,16 @@ func main() {
 		if clusterNameFlag != nil {
 			loadbalancer.ClusterName = (*clusterNameFlag).String()
 			vsphereparavirtual.ClusterName = (*clusterNameFlag).String()
+			vclib.ClusterName = (*clusterNameFlag).String()
+		}
+		profile, err := vsphere.ParseProfile(profileName)
+		if err != nil {
+			klog.Fatalf("invalid profile: %v", err)
 		}
+		if err := applyProfile(profile, cmd.Flags().Lookup("controllers")); err != nil {
+			klog.Fatalf("invalid profile: %v", err)
+		}
+		vsphere.ActiveProfile = profile
 		// if route controller is enabled in vsphereparavirtual cloud provider, set routeEnabled to true
 		if shouldEnableRouteController(controllersFlag, cloudProviderFlag) {
 			vsphereparavirtual.RouteEnabled = true
@@ -254,10 +381,61 @@ func shouldEnableRouteController(controllersFlag, cloudProviderFlag *pflag.Value
 		vsphereparavirtual.RegisteredProviderName == (*cloudProviderFlag).String()
 }
 
+// applyProfile sets the controllers flag to the controllers of the profile.
+// The controllers of a preset cannot be combined with --controllers.
+func applyProfile(profile vsphere.Profile, controllersFlag *pflag.Flag) error {
+	controllers := profile.Controllers()
+	if controllers == nil || controllersFlag == nil {
+		return nil
+	}
+	if controllersFlag.Changed {
+		return fmt.Errorf("profile %s selects the controllers, it cannot be combined with --controllers", profile)
+	}
+	klog.Infof("profile %s runs the controllers %s", profile, strings.Join(controllers, ", "))
+	return controllersFlag.Value.Set(strings.Join(controllers, ","))
+}
+
+// configReloadStatus returns the status of the cloud config the cloud
+// provider was initialized with, partial if the vsphere cloud provider runs
+// without some of its sections.
+func configReloadStatus(cloud cloudprovider.Interface, checksum string) k8s.ConfigReloadStatus {
+	status := k8s.ConfigReloadStatus{
+		Result:   k8s.ConfigReloadApplied,
+		Checksum: checksum,
+		Time:     metav1.Now(),
+	}
+	if vs, ok := cloud.(*vsphere.VSphere); ok {
+		if ignored := vs.IgnoredConfigSections(); len(ignored) > 0 {
+			status.Result = k8s.ConfigReloadPartial
+			status.Reason = "ignored sections " + strings.Join(ignored, "; ")
+		}
+	}
+	return status
+}
+
+// publishConfigReload records the status of the cloud config in the metric,
+// and annotates the checksum object with it if set.
+func publishConfigReload(config *appconfig.CompletedConfig, object string, status k8s.ConfigReloadStatus) {
+	klog.Infof("cloud config %s: %s", strings.ToLower(string(status.Result)), status.Reason)
+	k8s.RecordConfigReload(status)
+	if object == "" {
+		return
+	}
+	err := util.RetryOnError(util.DefaultBackoff, func(error) bool { return true }, func() error {
+		return k8s.AnnotateConfigReloadStatus(context.Background(), config.Client, object, status)
+	})
+	if err != nil {
+		klog.Errorf("fail to annotate %s with the cloud config status: %v", object, err)
+		return
+	}
+	klog.Infof("annotated %s with the cloud config status", object)
+}
+
 // set up a filesystem watcher for the mounted files
 // which include cloud-config and projected service account.
-// reboot the app whenever there is an update via the returned stopCh.
-func initializeWatch(_ *appconfig.CompletedConfig, paths []string) (watch *fsnotify.Watcher, stopCh chan struct{}, err error) {
+// reboot the app whenever there is an update via the returned stopCh,
+// unless the update leaves the checksum of a file in checksums unchanged.
+func initializeWatch(_ *appconfig.CompletedConfig, paths []string, checksums map[string]string) (watch *fsnotify.Watcher, stopCh chan struct{}, err error) {
 	stopCh = make(chan struct{})
 	watch, err = fsnotify.NewWatcher()
 	if err != nil {
@@ -269,12 +447,22 @@ func initializeWatch(_ *appconfig.CompletedConfig, paths []string) (watch *fsnot
 			case err := <-watch.Errors:
 				klog.Warningf("watcher receives err: %v\n", err)
 			case event := <-watch.Events:
-				if event.Op != fsnotify.Chmod {
-					klog.Fatalf("restarting pod because received event %v\n", event)
-					stopCh <- struct{}{}
-				} else {
+				if event.Op == fsnotify.Chmod {
 					klog.V(5).Infof("watcher receives %s on the mounted file %s\n", event.Op.String(), event.Name)
+					continue
+				}
+				if unchanged(event.Name, checksums[event.Name]) {
+					klog.Infof("ignoring event %v because the effective content of %s is unchanged\n", event, event.Name)
+					// ConfigMap updates replace the mounted file, which drops it from the watch
+					if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+						if err := watch.Add(event.Name); err != nil {
+							klog.Fatalf("restarting pod because watching %s again failed: %v\n", event.Name, err)
+						}
+					}
+					continue
 				}
+				klog.Fatalf("restarting pod because received event %v\n", event)
+				stopCh <- struct{}{}
 			}
 		}
 	}()
@@ -287,16 +475,52 @@ func initializeWatch(_ *appconfig.CompletedConfig, paths []string) (watch *fsnot
 	return
 }
 
-func initializeCloud(config *appconfig.CompletedConfig, cloudProvider string) cloudprovider.Interface {
+// unchanged returns true if the file at path has the given checksum.
+func unchanged(path, checksum string) bool {
+	if checksum == "" {
+		return false
+	}
+	byConfig, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return vcfg.Checksum(byConfig) == checksum
+}
+
+// dumpConfig prints the effective cloud config of the vsphere cloud provider.
+func dumpConfig(config *appconfig.CompletedConfig, cloudProvider string) error {
+	if cloudProvider != vsphere.RegisteredProviderName {
+		return fmt.Errorf("only the %s cloud provider reads a cloud config", vsphere.RegisteredProviderName)
+	}
+	cloudConfig := config.ComponentConfig.KubeCloudShared.CloudProvider.CloudConfigFile
+	byConfig, err := vcfg.ReadConfigFiles(cloudConfig)
+	if err != nil {
+		return fmt.Errorf("couldn't read cloud config %s: %v", cloudConfig, err)
+	}
+	return vsphere.DumpEffectiveConfig(os.Stdout, byConfig)
+}
+
+// initializeCloud initializes the cloud provider with the cloud config, and
+// returns an error if the cloud config is rejected.
+func initializeCloud(config *appconfig.CompletedConfig, cloudProvider string) (cloudprovider.Interface, error) {
 	cloudConfig := config.ComponentConfig.KubeCloudShared.CloudProvider
 
-	// initialize cloud provider with the cloud provider name and config file provided
-	cloud, err := cloudprovider.InitCloudProvider(cloudProvider, cloudConfig.CloudConfigFile)
+	// initialize cloud provider with the cloud provider name and the merged
+	// config files provided
+	var configReader io.Reader
+	if cloudConfig.CloudConfigFile != "" {
+		byConfig, err := vcfg.ReadConfigFiles(cloudConfig.CloudConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read cloud config %s: %w", cloudConfig.CloudConfigFile, err)
+		}
+		configReader = bytes.NewReader(byConfig)
+	}
+	cloud, err := cloudprovider.GetCloudProvider(cloudProvider, configReader)
 	if err != nil {
-		klog.Fatalf("Cloud provider could not be initialized: %v", err)
+		return nil, err
 	}
 	if cloud == nil {
-		klog.Fatalf("Cloud provider is nil")
+		return nil, fmt.Errorf("cloud provider is nil")
 	}
 
 	if !cloud.HasClusterID() {
@@ -307,5 +531,5 @@ func initializeCloud(config *appconfig.CompletedConfig, cloudProvider string) cl
 		}
 	}
 
-	return cloud
+	return cloud, nil
 }
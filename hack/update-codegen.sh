@@ -42,3 +42,10 @@ bash "${CODEGEN_PKG}"/generate-groups.sh all \
   "$CUSTOM_RESOURCE_PACKAGE:$CUSTOM_RESOURCE_VERSION" \
   --output-base "$(dirname "${BASH_SOURCE[0]}")/../../.." \
   --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate.go.txt
+
+# The cloud provider status API is written with the dynamic client, only its
+# deepcopy functions are generated
+go run k8s.io/code-generator/cmd/deepcopy-gen \
+  --output-file zz_generated.deepcopy.go \
+  --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate.go.txt \
+  k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/apis/cloudprovider/v1alpha1
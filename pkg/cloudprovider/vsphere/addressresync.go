@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	cloudproviderapi "k8s.io/cloud-provider/api"
+	v1helper "k8s.io/cloud-provider/node/helpers"
+	klog "k8s.io/klog/v2"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// resyncNodeAddresses discovers again the registered nodes and updates the
+// addresses in their status when VMware Tools reports new or different IPs,
+// for instance after a vMotion or a DHCP lease renewal.
+func (nm *NodeManager) resyncNodeAddresses(ctx context.Context) {
+	nm.nodeRegInfoLock.RLock()
+	nodeNames := make(map[string]string, len(nm.nodeRegUUIDMap))
+	for uuid, node := range nm.nodeRegUUIDMap {
+		nodeNames[uuid] = node.Name
+	}
+	nm.nodeRegInfoLock.RUnlock()
+
+	for uuid, nodeName := range nodeNames {
+		if err := nm.discoverNode(uuid, cm.FindVMByUUID, nodeName); err != nil {
+			klog.Warningf("Failed to discover the addresses of node %s again, keeping the previous ones: %v", nodeName, err)
+			continue
+		}
+
+		nm.nodeInfoLock.RLock()
+		nodeInfo, ok := nm.nodeUUIDMap[uuid]
+		var addrs []v1.NodeAddress
+		if ok {
+			addrs = nodeInfo.NodeAddresses
+		}
+		nm.nodeInfoLock.RUnlock()
+		if !ok || nm.kubeClient == nil {
+			continue
+		}
+		if err := nm.updateNodeAddresses(ctx, nodeName, addrs); err != nil {
+			klog.Errorf("Failed to update the addresses of node %s: %v", nodeName, err)
+		}
+	}
+}
+
+// updateNodeAddresses sets the addresses in the status of the node to addrs,
+// unless they are unchanged. Like the cloud node controller, only the address
+// given to the kubelet with --node-ip is kept if the node has one.
+func (nm *NodeManager) updateNodeAddresses(ctx context.Context, nodeName string, addrs []v1.NodeAddress) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := nm.kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		desired := addrs
+		if nodeIP, ok := node.Annotations[cloudproviderapi.AnnotationAlphaProvidedIPAddr]; ok {
+			desired, err = v1helper.GetNodeAddressesFromNodeIP(nodeIP, addrs)
+			if err != nil {
+				return err
+			}
+		}
+		if len(desired) == 0 || reflect.DeepEqual(node.Status.Addresses, desired) {
+			return nil
+		}
+
+		logging.V(logging.NodeManager, 2).Infof("Updating the addresses of node %s from %v to %v", nodeName, node.Status.Addresses, desired)
+		node.Status.Addresses = desired
+		_, err = nm.kubeClient.CoreV1().Nodes().UpdateStatus(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+}
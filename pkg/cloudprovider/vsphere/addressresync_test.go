@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	cloudproviderapi "k8s.io/cloud-provider/api"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func internalIPs(addrs []v1.NodeAddress) []string {
+	var ips []string
+	for _, addr := range addrs {
+		if addr.Type == v1.NodeInternalIP {
+			ips = append(ips, addr.Address)
+		}
+	}
+	return ips
+}
+
+func TestResyncNodeAddresses(t *testing.T) {
+	cfg, ok := configFromEnvOrSim(true)
+	defer ok()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	nm := newNodeManager(&ccfg.CPIConfig{}, connMgr)
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+
+	err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP])
+	if err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: vm.Name},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: ConvertK8sUUIDtoNormal(vm.Config.Uuid)},
+		},
+	}
+	nm.kubeClient = fake.NewSimpleClientset(node)
+	nm.RegisterNode(node)
+
+	nm.resyncNodeAddresses(context.Background())
+	updated, err := nm.kubeClient.CoreV1().Nodes().Get(context.Background(), vm.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ips := internalIPs(updated.Status.Addresses); strings.Join(ips, ",") != "10.0.0.1" {
+		t.Errorf("Unexpected internal IPs %v", ips)
+	}
+
+	// the DHCP lease changed
+	vm.Guest.Net[0].IpAddress = []string{"10.0.0.2"}
+	nm.resyncNodeAddresses(context.Background())
+	updated, err = nm.kubeClient.CoreV1().Nodes().Get(context.Background(), vm.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ips := internalIPs(updated.Status.Addresses); strings.Join(ips, ",") != "10.0.0.2" {
+		t.Errorf("Internal IP should be updated to 10.0.0.2, got %v", ips)
+	}
+
+	// the addresses are kept while the VM cannot be discovered
+	vm.Guest.Net = nil
+	nm.resyncNodeAddresses(context.Background())
+	updated, err = nm.kubeClient.CoreV1().Nodes().Get(context.Background(), vm.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ips := internalIPs(updated.Status.Addresses); strings.Join(ips, ",") != "10.0.0.2" {
+		t.Errorf("Internal IP should be kept, got %v", ips)
+	}
+}
+
+func TestUpdateNodeAddressesProvidedNodeIP(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node",
+			Annotations: map[string]string{cloudproviderapi.AnnotationAlphaProvidedIPAddr: "10.0.0.2"},
+		},
+	}
+	nm := newNodeManager(nil, nil)
+	nm.kubeClient = fake.NewSimpleClientset(node)
+
+	addrs := []v1.NodeAddress{
+		{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+		{Type: v1.NodeInternalIP, Address: "10.0.0.2"},
+	}
+	if err := nm.updateNodeAddresses(context.Background(), "node", addrs); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := nm.kubeClient.CoreV1().Nodes().Get(context.Background(), "node", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ips := internalIPs(updated.Status.Addresses); strings.Join(ips, ",") != "10.0.0.2" {
+		t.Errorf("Only the provided node IP should be kept, got %v", ips)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	v1helper "k8s.io/cloud-provider/node/helpers"
+	klog "k8s.io/klog/v2"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// maxAddressReviewResponseSize is the maximum size of a response of the
+// address webhook.
+const maxAddressReviewResponseSize = 1 << 20
+
+// addressReview is the body of the requests to the address webhook.
+type addressReview struct {
+	// Name of the node
+	Node string `json:"node"`
+	// VM of the node
+	VM addressReviewVM `json:"vm"`
+	// Addresses of the VM that are not local-only, statically configured
+	// ones first
+	Candidates []addressCandidate `json:"candidates"`
+	// Addresses the node would be published with
+	Addresses []v1.NodeAddress `json:"addresses"`
+}
+
+// addressReviewVM identifies the VM of the node of an addressReview.
+type addressReviewVM struct {
+	UUID       string `json:"uuid"`
+	VCenter    string `json:"vcenter"`
+	Datacenter string `json:"datacenter"`
+}
+
+// addressCandidate is an address of the VM of the node of an addressReview.
+type addressCandidate struct {
+	Address string `json:"address"`
+	Network string `json:"network"`
+}
+
+// addressReviewResponse is the body of the responses of the address webhook.
+type addressReviewResponse struct {
+	// Addresses the node is published with, in order
+	Addresses []v1.NodeAddress `json:"addresses"`
+}
+
+// addressWebhook calls the webhook adjusting the addresses of the nodes.
+type addressWebhook struct {
+	url           string
+	tokenFile     string
+	failurePolicy string
+	client        *http.Client
+}
+
+// newAddressWebhook returns the address webhook of the Nodes section, nil if
+// AddressWebhookURL is unset.
+func newAddressWebhook(nodes *ccfg.Nodes) (*addressWebhook, error) {
+	if nodes.AddressWebhookURL == "" {
+		return nil, nil
+	}
+	timeout, err := nodes.AddressWebhookTimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if nodes.AddressWebhookCAFile != "" {
+		pem, err := os.ReadFile(nodes.AddressWebhookCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the address webhook CA file: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in the address webhook CA file %s", nodes.AddressWebhookCAFile)
+		}
+	}
+	failurePolicy := nodes.AddressWebhookFailurePolicy
+	if failurePolicy == "" {
+		failurePolicy = ccfg.AddressWebhookFailurePolicyIgnore
+	}
+	klog.Infof("Node addresses are reviewed by the webhook %s, failure policy %s", nodes.AddressWebhookURL, failurePolicy)
+	return &addressWebhook{
+		url:           nodes.AddressWebhookURL,
+		tokenFile:     nodes.AddressWebhookTokenFile,
+		failurePolicy: failurePolicy,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// review sends review to the webhook and returns the addresses of its
+// response, which must be addresses of review.
+func (w *addressWebhook) review(ctx context.Context, review *addressReview) ([]v1.NodeAddress, error) {
+	body, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.tokenFile != "" {
+		token, err := os.ReadFile(w.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the address webhook token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxAddressReviewResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("address webhook returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	var response addressReviewResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("invalid address webhook response: %v", err)
+	}
+	return validateReviewedAddresses(review, response.Addresses)
+}
+
+// validateReviewedAddresses returns the addresses returned by the webhook for
+// review. The IP addresses must be candidates of the review and the hostnames
+// addresses of the review, as the webhook only reorders or filters them.
+func validateReviewedAddresses(review *addressReview, reviewed []v1.NodeAddress) ([]v1.NodeAddress, error) {
+	if len(reviewed) == 0 {
+		return nil, fmt.Errorf("address webhook returned no address")
+	}
+	addrs := []v1.NodeAddress{}
+	for _, addr := range reviewed {
+		switch addr.Type {
+		case v1.NodeInternalIP, v1.NodeExternalIP:
+			if !isAddressCandidate(review.Candidates, addr.Address) {
+				return nil, fmt.Errorf("address webhook returned %s %s, which is not an address of the VM", addr.Type, addr.Address)
+			}
+		case v1.NodeHostName:
+			if !isNodeAddress(review.Addresses, addr) {
+				return nil, fmt.Errorf("address webhook returned Hostname %s, which is not the hostname of the node", addr.Address)
+			}
+		default:
+			return nil, fmt.Errorf("address webhook returned an address of unsupported type %q", addr.Type)
+		}
+		v1helper.AddToNodeAddresses(&addrs, addr)
+	}
+	return addrs, nil
+}
+
+func isAddressCandidate(candidates []addressCandidate, address string) bool {
+	ip := parseAddr(address)
+	for _, candidate := range candidates {
+		if ip.IsValid() && parseAddr(candidate.Address) == ip {
+			return true
+		}
+	}
+	return false
+}
+
+func isNodeAddress(addrs []v1.NodeAddress, addr v1.NodeAddress) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewAddresses returns the addresses of the node adjusted by the address
+// webhook, addrs if it is not configured. When the webhook fails, addrs are
+// returned with the ignore failure policy, an error with the fail one.
+func (nm *NodeManager) reviewAddresses(ctx context.Context, nodeName string, vmDI *cm.VMDiscoveryInfo,
+	candidates []*ipAddrNetworkName, addrs []v1.NodeAddress) ([]v1.NodeAddress, error) {
+	if nm.addressWebhook == nil {
+		return addrs, nil
+	}
+	review := &addressReview{
+		Node:      nodeName,
+		VM:        addressReviewVM{UUID: vmDI.UUID, VCenter: vmDI.VcServer, Datacenter: vmDI.DataCenter.Name()},
+		Addresses: addrs,
+	}
+	for _, candidate := range candidates {
+		review.Candidates = append(review.Candidates, addressCandidate{Address: candidate.ipAddr, Network: candidate.networkName})
+	}
+
+	reviewed, err := nm.addressWebhook.review(ctx, review)
+	if err != nil {
+		if nm.addressWebhook.failurePolicy == ccfg.AddressWebhookFailurePolicyFail {
+			return nil, fmt.Errorf("address webhook failed for node %s: %w", nodeName, err)
+		}
+		klog.Warningf("Address webhook failed for node %s, publishing the discovered addresses: %v", nodeName, err)
+		return addrs, nil
+	}
+	logging.V(logging.NodeManager, 2).Infof("Address webhook adjusted the addresses of node %s from %v to %v", nodeName, addrs, reviewed)
+	return reviewed, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+// newTestAddressWebhook returns an address webhook calling a TLS server
+// serving handler, with the failure policy.
+func newTestAddressWebhook(t *testing.T, handler http.HandlerFunc, failurePolicy string) *addressWebhook {
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newAddressWebhook(&ccfg.Nodes{
+		AddressWebhookURL:           server.URL,
+		AddressWebhookFailurePolicy: failurePolicy,
+		AddressWebhookCAFile:        caFile,
+		AddressWebhookTokenFile:     tokenFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestReviewAddresses(t *testing.T) {
+	candidates := []*ipAddrNetworkName{
+		{ipAddr: "10.0.0.1", networkName: "internal"},
+		{ipAddr: "192.168.0.1", networkName: "storage"},
+	}
+	discovered := []v1.NodeAddress{
+		{Type: v1.NodeHostName, Address: "node-1"},
+		{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+	}
+	vmDI := &cm.VMDiscoveryInfo{UUID: "uuid-1", VcServer: "vc-1", DataCenter: newStatusTestDatacenter("dc-1")}
+
+	testcases := []struct {
+		name          string
+		response      string
+		status        int
+		failurePolicy string
+		expected      []v1.NodeAddress
+		err           bool
+	}{
+		{
+			name:     "reordered and filtered",
+			response: `{"addresses":[{"type":"InternalIP","address":"192.168.0.1"},{"type":"Hostname","address":"node-1"}]}`,
+			expected: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "192.168.0.1"},
+				{Type: v1.NodeHostName, Address: "node-1"},
+			},
+		},
+		{
+			name:     "not an address of the VM is ignored",
+			response: `{"addresses":[{"type":"InternalIP","address":"172.16.0.1"}]}`,
+			expected: discovered,
+		},
+		{
+			name:          "not an address of the VM fails",
+			response:      `{"addresses":[{"type":"InternalIP","address":"172.16.0.1"}]}`,
+			failurePolicy: ccfg.AddressWebhookFailurePolicyFail,
+			err:           true,
+		},
+		{
+			name:          "no address fails",
+			response:      `{"addresses":[]}`,
+			failurePolicy: ccfg.AddressWebhookFailurePolicyFail,
+			err:           true,
+		},
+		{
+			name:          "webhook error fails",
+			status:        http.StatusInternalServerError,
+			failurePolicy: ccfg.AddressWebhookFailurePolicyFail,
+			err:           true,
+		},
+		{
+			name:     "webhook error is ignored",
+			status:   http.StatusInternalServerError,
+			expected: discovered,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			var review addressReview
+			w := newTestAddressWebhook(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					http.Error(w, "unauthorized", http.StatusUnauthorized)
+					return
+				}
+				if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				if testcase.status != 0 {
+					http.Error(w, "failed", testcase.status)
+					return
+				}
+				_, _ = w.Write([]byte(testcase.response))
+			}, testcase.failurePolicy)
+			nm := &NodeManager{addressWebhook: w}
+
+			addrs, err := nm.reviewAddresses(context.Background(), "node-1", vmDI, candidates, discovered)
+			if (err != nil) != testcase.err {
+				t.Fatalf("expected error %t, got %v", testcase.err, err)
+			}
+			if !reflect.DeepEqual(addrs, testcase.expected) {
+				t.Errorf("expected %v, got %v", testcase.expected, addrs)
+			}
+			if review.Node != "node-1" || review.VM.UUID != "uuid-1" || len(review.Candidates) != 2 || len(review.Addresses) != 2 || review.VM.Datacenter != "dc-1" {
+				t.Errorf("unexpected review %+v", review)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VSphereCloudProviderStatus summarizes the configuration and the state of
+// the vSphere cloud provider. It is maintained by the cloud controller
+// manager and is read-only for users.
+type VSphereCloudProviderStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Status VSphereCloudProviderStatusStatus `json:"status,omitempty"`
+}
+
+// VSphereCloudProviderStatusStatus is the reported state of the vSphere
+// cloud provider.
+type VSphereCloudProviderStatusStatus struct {
+	// VCenters are the vCenters of the cloud config.
+	VCenters []VCenterStatus `json:"vcenters,omitempty"`
+	// LoadBalancerClasses are the names of the NSX-T load balancer classes,
+	// empty if load balancer support is disabled.
+	LoadBalancerClasses []string `json:"loadBalancerClasses,omitempty"`
+	// LastReconcileErrors are the last errors of the node and load balancer
+	// reconciles, the most recent first. An error is removed once the
+	// reconcile of its object succeeds.
+	LastReconcileErrors []ReconcileError `json:"lastReconcileErrors,omitempty"`
+	// LastUpdateTime is the time the status was last reported.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// VCenterStatus is the state of a vCenter of the cloud config.
+type VCenterStatus struct {
+	// Name is the name of the vCenter in the cloud config.
+	Name string `json:"name"`
+	// Server is the host name of the vCenter.
+	Server string `json:"server,omitempty"`
+	// Port is the port of the vCenter.
+	Port string `json:"port,omitempty"`
+	// Connected is true if the cloud provider has a session with the vCenter.
+	Connected bool `json:"connected"`
+	// Datacenters are the datacenters of the vCenter with managed nodes.
+	Datacenters []DatacenterStatus `json:"datacenters,omitempty"`
+	// Nodes is the number of managed nodes in the vCenter.
+	Nodes int `json:"nodes"`
+}
+
+// DatacenterStatus is the state of a datacenter with managed nodes.
+type DatacenterStatus struct {
+	// Name is the name of the datacenter.
+	Name string `json:"name"`
+	// Nodes is the number of managed nodes in the datacenter.
+	Nodes int `json:"nodes"`
+}
+
+// ReconcileError is the last error of the reconcile of an object.
+type ReconcileError struct {
+	// Kind is the kind of the reconciled object, Node or Service.
+	Kind string `json:"kind"`
+	// Name is the name of the reconciled object, namespace/name for
+	// namespaced objects.
+	Name string `json:"name"`
+	// Message is the error message.
+	Message string `json:"message"`
+	// Time is the time the error occurred.
+	Time metav1.Time `json:"time"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VSphereCloudProviderStatusList is a list of VSphereCloudProviderStatus.
+type VSphereCloudProviderStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VSphereCloudProviderStatus `json:"items"`
+}
@@ -17,13 +17,17 @@ limitations under the License.
 package vsphere
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	klog "k8s.io/klog/v2"
 
 	cloudprovider "k8s.io/cloud-provider"
@@ -35,10 +39,12 @@ import (
 	lcfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/route"
 	rcfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/route/config"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
 	k8s "k8s.io/cloud-provider-vsphere/pkg/common/kubernetes"
 	"k8s.io/cloud-provider-vsphere/pkg/nsxt"
 	ncfg "k8s.io/cloud-provider-vsphere/pkg/nsxt/config"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 const (
@@ -59,6 +65,10 @@ const (
 var (
 	logoutCh chan struct{}
 	logoutWG sync.WaitGroup
+
+	// statusInterval is the interval at which the VSphereCloudProviderStatus
+	// object is reported, 0 to not report it.
+	statusInterval time.Duration
 )
 
 func init() {
@@ -74,23 +84,37 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
+		if err := logging.SetModuleVerbosity(cfg.Logging.ModuleVerbosity); err != nil {
+			return nil, err
+		}
+		var ignored []string
 		nsxtcfg, err := ncfg.ReadNsxtConfig(byConfig)
 		if err != nil {
 			klog.Errorf("ReadNsxtConfig failed: %s", err)
 			nsxtcfg = nil
+			ignored = ignoreConfigSection(ignored, byConfig, "nsxt", err)
 		}
 		lbcfg, err := lcfg.ReadLBConfig(byConfig)
 		if err != nil {
 			lbcfg = nil //Error reading LBConfig, explicitly set to nil
+			ignored = ignoreConfigSection(ignored, byConfig, "loadBalancer", err)
 		}
 		routecfg, err := rcfg.ReadRouteConfig(byConfig)
 		if err != nil {
 			klog.Errorf("ReadRouteConfig failed: %s", err)
 			routecfg = nil
+			ignored = ignoreConfigSection(ignored, byConfig, "route", err)
 		}
 
-		return newVSphere(cfg, nsxtcfg, lbcfg, routecfg, true)
+		vs, err := newVSphere(cfg, nsxtcfg, lbcfg, routecfg, true)
+		if err != nil {
+			return nil, err
+		}
+		vs.ignoredConfigSections = ignored
+		return vs, nil
 	})
+
+	flag.DurationVar(&statusInterval, "vsphere-status-interval", 0, "Interval at which the state of the vSphere cloud provider is reported to the VSphereCloudProviderStatus object "+StatusName+", such as 1m. 0 disables the reporting.")
 }
 
 var _ cloudprovider.Interface = &VSphere{}
@@ -140,12 +164,51 @@ func (vs *VSphere) Initialize(clientBuilder cloudprovider.ControllerClientBuilde
 			logoutWG.Done()
 		}()
 
-		vs.informMgr.AddNodeListener(vs.nodeAdded, vs.nodeDeleted, nil)
+		if vs.profile.runsNodes() {
+			vs.informMgr.AddNodeListener(vs.nodeAdded, vs.nodeDeleted, nil)
+
+			vs.nodeManager.kubeClient = client
+			vs.nodeManager.nodeLister = vs.informMgr.GetNodeLister()
+			vs.nodeManager.recorder = newNodeEventRecorder(client, stop)
+			if vs.nodeManager.instanceTypeTTL > 0 {
+				go wait.Until(func() {
+					vs.nodeManager.refreshInstanceTypes(context.Background())
+				}, vs.nodeManager.instanceTypeTTL, stop)
+			}
+			if vs.nodeManager.addressResyncPeriod > 0 {
+				go wait.Until(func() {
+					vs.nodeManager.resyncNodeAddresses(context.Background())
+				}, vs.nodeManager.addressResyncPeriod, stop)
+			}
+			if vs.nodeManager.vmLabelResyncPeriod > 0 {
+				go wait.Until(func() {
+					vs.nodeManager.syncVMLabels(context.Background())
+				}, vs.nodeManager.vmLabelResyncPeriod, stop)
+			}
+		} else {
+			klog.Infof("profile %s, the VMs of the nodes are not discovered", vs.profile)
+		}
 
 		vs.informMgr.Listen()
+		vs.startNodeCleanupJanitor(stop)
+
+		// the keep-alive period is validated when reading the config
+		if period, _ := vs.cfg.SessionKeepAlivePeriodDuration(); period > 0 {
+			go connMgr.KeepAlive(wait.ContextForChannel(stop), period)
+		}
+		if vs.cfg.Global.CacheVMProperties && vs.profile.runsNodes() {
+			go connMgr.CacheVMProperties(wait.ContextForChannel(stop))
+		}
 
 		// if running secrets, init them
-		connMgr.InitializeSecretLister()
+		if vs.profile.runsNodes() {
+			connMgr.InitializeSecretLister()
+			vs.watchTenantConfigs()
+		}
+
+		if statusInterval > 0 {
+			vs.startStatusReporter(clientBuilder, stop)
+		}
 	} else {
 		klog.Errorf("Kubernetes Client Init Failed: %v", err)
 	}
@@ -156,10 +219,31 @@ func (vs *VSphere) Initialize(clientBuilder cloudprovider.ControllerClientBuilde
 		}
 		vs.loadbalancer.Initialize(loadbalancer.ClusterName, client, stop)
 	}
-	err = vs.nsxtConnectorMgr.AddSecretListener(vs.informMgr.GetSecretInformer(vs.nsxtSecretNamespace))
-	if err != nil {
-		klog.Warningf("Adding NSXT secret listener failed: %v", err)
+	if vs.nsxtConnectorMgr.GetConnector() != nil || vs.profile == ProfileFull {
+		err = vs.nsxtConnectorMgr.AddSecretListener(vs.informMgr.GetSecretInformer(vs.nsxtSecretNamespace))
+		if err != nil {
+			klog.Warningf("Adding NSXT secret listener failed: %v", err)
+		}
+	}
+	vs.registerDebugSources()
+	vs.registerHealthChecks()
+	vs.registerSecureAPI()
+}
+
+// watchTenantConfigs adds the vCenters of the tenant ConfigMaps to the
+// connection manager, if Global.TenantConfigMapSelector is set.
+func (vs *VSphere) watchTenantConfigs() {
+	selector := vs.cfg.Global.TenantConfigMapSelector
+	if selector == "" {
+		return
 	}
+	namespace := vs.cfg.Global.TenantConfigMapNamespace
+	if namespace == "" {
+		namespace = vcfg.DefaultTenantConfigMapNamespace
+	}
+	klog.Infof("Watching the tenant configs of the ConfigMaps of namespace %s matching %q", namespace, selector)
+	vs.connectionManager.WatchTenantConfigs(vs.informMgr.NewConfigMapInformer(namespace, selector))
+	vs.informMgr.Listen()
 }
 
 func (vs *VSphere) isLoadBalancerSupportEnabled() bool {
@@ -177,17 +261,26 @@ func (vs *VSphere) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
 }
 
 // Instances returns an instances interface. Also returns true if the
-// interface is supported, false otherwise.
+// interface is supported, false otherwise. The interface is not supported
+// when --vsphere-instances-v1 is disabled, see NewInstancesV1.
 func (vs *VSphere) Instances() (cloudprovider.Instances, bool) {
+	if !instancesV1Enabled {
+		klog.Warning("The v1 Instances interface of the vSphere cloud provider is disabled")
+		return nil, false
+	}
 	klog.V(6).Info("Calling the Instances interface on vSphere cloud provider")
 	return vs.instances, true
 }
 
-// InstancesV2 returns an implementation of cloudprovider.InstancesV2.
-//
-//	TODO: implement this for v1.20
+// InstancesV2 returns an implementation of cloudprovider.InstancesV2. The
+// interface is only supported when --vsphere-instances-v2 is enabled, see
+// NewInstancesV2.
 func (vs *VSphere) InstancesV2() (cloudprovider.InstancesV2, bool) {
-	return nil, false
+	if !instancesV2Enabled {
+		return nil, false
+	}
+	klog.V(6).Info("Calling the InstancesV2 interface on vSphere cloud provider")
+	return vs.instancesV2, true
 }
 
 // Zones returns a zones interface. Also returns true if the interface
@@ -234,6 +327,18 @@ func (vs *VSphere) HasClusterID() bool {
 func buildVSphereFromConfig(cfg *ccfg.CPIConfig, nsxtcfg *ncfg.Config, lbcfg *lcfg.LBConfig, routecfg *rcfg.Config) (*VSphere, error) {
 	nm := newNodeManager(cfg, nil)
 
+	profile := ActiveProfile
+	if !profile.runsLoadBalancers() {
+		lbcfg = nil
+	}
+	if !profile.runsRoutes() {
+		routecfg = nil
+	}
+	if profile != ProfileFull && lbcfg == nil && routecfg == nil && cfg.Nodes.NSXAddressSource == "" {
+		// the controllers of the profile do not use the NSX-T connector
+		nsxtcfg = nil
+	}
+
 	ncm, err := nsxt.NewConnectorManager(nsxtcfg)
 	if err != nil {
 		return nil, err
@@ -249,6 +354,31 @@ func buildVSphereFromConfig(cfg *ccfg.CPIConfig, nsxtcfg *ncfg.Config, lbcfg *lc
 		return nil, err
 	}
 
+	if cfg.Nodes.NSXAddressSource != "" {
+		if ncm.GetConnector() == nil {
+			return nil, fmt.Errorf("NSX address source %q requires the NSXT config", cfg.Nodes.NSXAddressSource)
+		}
+		nm.nsxtBroker = newNsxtAddressBroker(ncm.GetConnector())
+	}
+
+	if nm.addressWebhook, err = newAddressWebhook(&cfg.Nodes); err != nil {
+		return nil, err
+	}
+	if nm.instanceTypeTTL, err = cfg.Nodes.InstanceTypeTTLDuration(); err != nil {
+		return nil, err
+	}
+	if nm.addressResyncPeriod, err = cfg.Nodes.AddressResyncPeriodDuration(); err != nil {
+		return nil, err
+	}
+	if nm.vmNotFoundGracePeriod, err = cfg.Nodes.VMNotFoundGracePeriodDuration(); err != nil {
+		return nil, err
+	}
+	if cfg.Nodes.VMLabelsEnabled() {
+		if nm.vmLabelResyncPeriod, err = cfg.Nodes.VMLabelResyncPeriodDuration(); err != nil {
+			return nil, err
+		}
+	}
+
 	// redirect vapi logging from the NSX-T GO SDK to klog
 	log.SetLogger(NewKlogBridge())
 
@@ -257,6 +387,11 @@ func buildVSphereFromConfig(cfg *ccfg.CPIConfig, nsxtcfg *ncfg.Config, lbcfg *lc
 		return nil, err
 	}
 
+	nodeCleanup := newNodeCleanupRegistry()
+	if routes != nil {
+		nodeCleanup.Register("route", routes)
+	}
+
 	nsxtSecretNamespace := v1.NamespaceAll
 	if nsxtcfg != nil {
 		nsxtSecretNamespace = nsxtcfg.SecretNamespace
@@ -264,14 +399,18 @@ func buildVSphereFromConfig(cfg *ccfg.CPIConfig, nsxtcfg *ncfg.Config, lbcfg *lc
 
 	vs := VSphere{
 		cfg:                 cfg,
+		effectiveConfig:     newEffectiveConfig(cfg, nsxtcfg, lbcfg, routecfg),
+		profile:             profile,
 		cfgLB:               lbcfg,
 		nodeManager:         nm,
 		nsxtConnectorMgr:    ncm,
 		nsxtSecretNamespace: nsxtSecretNamespace,
+		nodeCleanup:         nodeCleanup,
 		loadbalancer:        lb,
 		routes:              routes,
 		instances:           newInstances(nm),
-		zones:               newZones(nm, cfg.Labels.Zone, cfg.Labels.Region),
+		instancesV2:         newInstancesV2(nm, cfg.Labels),
+		zones:               newZones(nm, cfg.Labels.Zone, cfg.Labels.Region, cfg.Labels.Disabled),
 	}
 	return &vs, nil
 }
@@ -325,4 +464,5 @@ func (vs *VSphere) nodeDeleted(obj interface{}) {
 	if vs.routes != nil {
 		vs.routes.DeleteNode(node)
 	}
+	go vs.cleanupDeletedNode(node.Name)
 }
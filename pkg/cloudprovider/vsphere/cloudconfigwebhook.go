@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+const (
+	// CloudConfigWebhookName is the name of the webhook validating the cloud
+	// config, enabled with --webhooks.
+	CloudConfigWebhookName = "cloud-config"
+	// CloudConfigWebhookPath is the path the webhook validating the cloud
+	// config is served at.
+	CloudConfigWebhookPath = "/validate-cloud-config"
+	// CloudConfigKey is the key of the cloud config in the ConfigMaps and
+	// Secrets validated by the webhook.
+	CloudConfigKey = "vsphere.conf"
+)
+
+// AdmitCloudConfig is the admission handler of the webhook validating the
+// cloud config held by a ConfigMap or a Secret under CloudConfigKey. The
+// objects without this key are admitted.
+func AdmitCloudConfig(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return &admissionv1.AdmissionResponse{Allowed: true}, nil
+	}
+
+	byConfig, found, err := cloudConfigOf(req)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &admissionv1.AdmissionResponse{Allowed: true}, nil
+	}
+	if err := ValidateCloudConfig(byConfig); err != nil {
+		klog.Warningf("Rejecting the cloud config of %s %s/%s: %v", req.Kind.Kind, req.Namespace, req.Name, err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("invalid %s: %v", CloudConfigKey, err),
+			},
+		}, nil
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}, nil
+}
+
+// cloudConfigOf returns the cloud config of the ConfigMap or Secret of an
+// admission request, and false if the object does not hold one.
+func cloudConfigOf(req *admissionv1.AdmissionRequest) ([]byte, bool, error) {
+	switch req.Kind.Kind {
+	case "ConfigMap":
+		var configMap v1.ConfigMap
+		if err := json.Unmarshal(req.Object.Raw, &configMap); err != nil {
+			return nil, false, fmt.Errorf("decoding ConfigMap %s/%s failed: %v", req.Namespace, req.Name, err)
+		}
+		if data, ok := configMap.Data[CloudConfigKey]; ok {
+			return []byte(data), true, nil
+		}
+		data, ok := configMap.BinaryData[CloudConfigKey]
+		return data, ok, nil
+	case "Secret":
+		var secret v1.Secret
+		if err := json.Unmarshal(req.Object.Raw, &secret); err != nil {
+			return nil, false, fmt.Errorf("decoding Secret %s/%s failed: %v", req.Namespace, req.Name, err)
+		}
+		if data, ok := secret.StringData[CloudConfigKey]; ok {
+			return []byte(data), true, nil
+		}
+		data, ok := secret.Data[CloudConfigKey]
+		return data, ok, nil
+	}
+	return nil, false, nil
+}
+
+// ValidateCloudConfig parses and validates a cloud config the way the cloud
+// provider does when it is initialized, checking the credentials of the
+// vCenters and the Nodes section among others, and also checks the IP
+// families of the vCenters, which are otherwise only found invalid once the
+// nodes are discovered. The environment variables of the webhook do not
+// override the submitted config.
+func ValidateCloudConfig(byConfig []byte) error {
+	cfg, err := ccfg.ParseCPIConfig(byConfig)
+	if err != nil {
+		return err
+	}
+
+	for tenantRef, vcConfig := range cfg.VirtualCenter {
+		for _, ipFamily := range vcConfig.IPFamilyPriority {
+			if ipFamily != vcfg.IPv4Family && ipFamily != vcfg.IPv6Family {
+				return fmt.Errorf("invalid IP family %q of vCenter %s, must be %q or %q",
+					ipFamily, tenantRef, vcfg.IPv4Family, vcfg.IPv6Family)
+			}
+		}
+	}
+	return validateDualStack(cfg)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+)
+
+const webhookCloudConfig = `
+global:
+  user: user
+  password: password
+  insecureFlag: true
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - dc1
+    ipFamily:
+      - %s
+
+nodes:
+  internalNetworkSubnetCidr: %s
+`
+
+func webhookConfig(ipFamily, cidr string) string {
+	return strings.Replace(strings.Replace(webhookCloudConfig, "%s", ipFamily, 1), "%s", cidr, 1)
+}
+
+func TestValidateCloudConfig(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config string
+		valid  bool
+	}{
+		{name: "valid", config: webhookConfig("ipv4", "192.0.2.0/24,198.51.100.0/24"), valid: true},
+		{name: "invalid CIDR", config: webhookConfig("ipv4", "192.0.2.0/33")},
+		{name: "invalid IP family", config: webhookConfig("ipv5", "192.0.2.0/24")},
+		{name: "missing password", config: strings.Replace(webhookConfig("ipv4", "192.0.2.0/24"), "  password: password\n", "", 1)},
+		{name: "empty", config: ""},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := ValidateCloudConfig([]byte(testcase.config))
+			if testcase.valid && err != nil {
+				t.Errorf("expected a valid config, got %v", err)
+			}
+			if !testcase.valid && err == nil {
+				t.Error("expected an invalid config")
+			}
+		})
+	}
+}
+
+func TestValidateCloudConfigIgnoresEnv(t *testing.T) {
+	t.Setenv("VSPHERE_NODES_INTERNAL_NETWORK_SUBNET_CIDR", "garbage")
+
+	config := []byte(webhookConfig("ipv4", "192.0.2.0/24"))
+	if _, err := ccfg.ReadCPIConfig(config); err == nil {
+		t.Fatal("expected the environment to override the config of the cloud provider")
+	}
+	if err := ValidateCloudConfig(config); err != nil {
+		t.Errorf("expected the submitted config to be validated without the environment, got %v", err)
+	}
+}
+
+func TestAdmitCloudConfig(t *testing.T) {
+	admissionRequest := func(kind string, obj runtime.Object) *admissionv1.AdmissionRequest {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: kind},
+			Namespace: "kube-system",
+			Name:      "vsphere-cloud-config",
+			Operation: admissionv1.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+		}
+	}
+
+	testcases := []struct {
+		name    string
+		req     *admissionv1.AdmissionRequest
+		allowed bool
+	}{
+		{
+			name:    "valid ConfigMap",
+			req:     admissionRequest("ConfigMap", &v1.ConfigMap{Data: map[string]string{CloudConfigKey: webhookConfig("ipv6", "2001:db8::/64")}}),
+			allowed: true,
+		},
+		{
+			name: "invalid ConfigMap",
+			req:  admissionRequest("ConfigMap", &v1.ConfigMap{Data: map[string]string{CloudConfigKey: webhookConfig("ipv4", "192.0.2.1")}}),
+		},
+		{
+			name:    "ConfigMap without cloud config",
+			req:     admissionRequest("ConfigMap", &v1.ConfigMap{Data: map[string]string{"other.conf": "invalid"}}),
+			allowed: true,
+		},
+		{
+			name:    "valid Secret",
+			req:     admissionRequest("Secret", &v1.Secret{Data: map[string][]byte{CloudConfigKey: []byte(webhookConfig("ipv4", "192.0.2.0/24"))}}),
+			allowed: true,
+		},
+		{
+			name: "invalid Secret",
+			req:  admissionRequest("Secret", &v1.Secret{StringData: map[string]string{CloudConfigKey: webhookConfig("IPv4", "192.0.2.0/24")}}),
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			resp, err := AdmitCloudConfig(testcase.req)
+			if err != nil {
+				t.Fatalf("AdmitCloudConfig failed: %v", err)
+			}
+			if resp.Allowed != testcase.allowed {
+				t.Errorf("expected allowed %v, got %v", testcase.allowed, resp.Allowed)
+			}
+			if !resp.Allowed && (resp.Result == nil || !strings.Contains(resp.Result.Message, CloudConfigKey)) {
+				t.Errorf("expected the rejection to explain the invalid cloud config, got %+v", resp.Result)
+			}
+		})
+	}
+}
@@ -17,12 +17,68 @@ limitations under the License.
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 	klog "k8s.io/klog/v2"
 )
 
+const (
+	// NSXAddressSourceMerge adds the addresses NSX-T realized on the segment
+	// ports of a node VM after the ones reported by VMware Tools.
+	NSXAddressSourceMerge = "merge"
+	// NSXAddressSourcePrefer puts the addresses NSX-T realized on the segment
+	// ports of a node VM before the ones reported by VMware Tools.
+	NSXAddressSourcePrefer = "prefer"
+
+	// AddressWebhookFailurePolicyIgnore publishes the discovered addresses
+	// of a node when the address webhook fails.
+	AddressWebhookFailurePolicyIgnore = "ignore"
+	// AddressWebhookFailurePolicyFail fails the discovery of a node when the
+	// address webhook fails.
+	AddressWebhookFailurePolicyFail = "fail"
+
+	// DefaultAddressWebhookTimeout is the timeout of a call to the address
+	// webhook if AddressWebhookTimeout is unset.
+	DefaultAddressWebhookTimeout = 10 * time.Second
+
+	// DefaultVMLabelPrefix is the prefix of the node labels applied from the
+	// custom attributes and tags of the VMs if VMLabelPrefix is unset.
+	DefaultVMLabelPrefix = "vm.vsphere.vmware.com/"
+	// DefaultVMLabelResyncPeriod is the period at which the node labels are
+	// applied from the VMs if VMLabelResyncPeriod is unset.
+	DefaultVMLabelResyncPeriod = 10 * time.Minute
+
+	// DefaultMaxGuestInfoSize is the maximum size of the decoded guestinfo
+	// metadata of a VM if MaxGuestInfoSize is unset.
+	DefaultMaxGuestInfoSize = 1 << 20
+
+	// VMLookupByName searches the VM of a node by its DNS name.
+	VMLookupByName = "name"
+	// VMLookupByIP searches the VM of a node by its IP address.
+	VMLookupByIP = "ip"
+)
+
+// DefaultVMLookupOrder is the order in which the VM of a node looked up by
+// name is searched if VMLookupOrder is unset.
+var DefaultVMLookupOrder = []string{VMLookupByName, VMLookupByIP}
+
+func init() {
+	vcfg.RegisterConfigSchema(CPIConfigINI{}, CPIConfigYAML{})
+}
+
 // FromCPIEnv initializes the provided configuration object with values
 // obtained from environment variables. If an environment variable is set
 // for a property that's already initialized, the environment variable's value
@@ -45,10 +101,421 @@ func (cfg *CPIConfig) FromCPIEnv() error {
 	if v := os.Getenv("VSPHERE_NODES_EXTERNAL_VM_NETWORK_NAME"); v != "" {
 		cfg.Nodes.ExternalVMNetworkName = v
 	}
+	if v := os.Getenv("VSPHERE_NODES_INTERNAL_VM_NETWORK_NAME_PATTERN"); v != "" {
+		cfg.Nodes.InternalVMNetworkNamePattern = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_EXTERNAL_VM_NETWORK_NAME_PATTERN"); v != "" {
+		cfg.Nodes.ExternalVMNetworkNamePattern = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_NSX_ADDRESS_SOURCE"); v != "" {
+		cfg.Nodes.NSXAddressSource = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_INSTANCE_TYPE_TTL"); v != "" {
+		cfg.Nodes.InstanceTypeTTL = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_UPDATE_INSTANCE_TYPE_LABELS"); v != "" {
+		updateLabels, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_UPDATE_INSTANCE_TYPE_LABELS: %s", err)
+		} else {
+			cfg.Nodes.UpdateInstanceTypeLabels = updateLabels
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_SOURCE_TEMPLATE_KEY"); v != "" {
+		cfg.Nodes.SourceTemplateKey = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_USE_NODE_NAME_AS_HOSTNAME"); v != "" {
+		useNodeName, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_USE_NODE_NAME_AS_HOSTNAME: %s", err)
+		} else {
+			cfg.Nodes.UseNodeNameAsHostname = useNodeName
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_ADDRESS_RESYNC_PERIOD"); v != "" {
+		cfg.Nodes.AddressResyncPeriod = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_VM_NOT_FOUND_GRACE_PERIOD"); v != "" {
+		cfg.Nodes.VMNotFoundGracePeriod = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ADDRESS_WEBHOOK_URL"); v != "" {
+		cfg.Nodes.AddressWebhookURL = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ADDRESS_WEBHOOK_TIMEOUT"); v != "" {
+		cfg.Nodes.AddressWebhookTimeout = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ADDRESS_WEBHOOK_FAILURE_POLICY"); v != "" {
+		cfg.Nodes.AddressWebhookFailurePolicy = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ADDRESS_WEBHOOK_CA_FILE"); v != "" {
+		cfg.Nodes.AddressWebhookCAFile = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ADDRESS_WEBHOOK_TOKEN_FILE"); v != "" {
+		cfg.Nodes.AddressWebhookTokenFile = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ALLOW_EMPTY_GUEST_HOSTNAME"); v != "" {
+		allowEmpty, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_ALLOW_EMPTY_GUEST_HOSTNAME: %s", err)
+		} else {
+			cfg.Nodes.AllowEmptyGuestHostname = allowEmpty
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_PUBLISH_GUEST_HOSTNAME"); v != "" {
+		publish, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_PUBLISH_GUEST_HOSTNAME: %s", err)
+		} else {
+			cfg.Nodes.PublishGuestHostname = &publish
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_SUPPRESS_EXTERNAL_IP"); v != "" {
+		suppress, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_SUPPRESS_EXTERNAL_IP: %s", err)
+		} else {
+			cfg.Nodes.SuppressExternalIP = suppress
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_PUBLISH_ALL_MATCHING_IPS"); v != "" {
+		publishAll, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_PUBLISH_ALL_MATCHING_IPS: %s", err)
+		} else {
+			cfg.Nodes.PublishAllMatchingIPs = publishAll
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_VM_LABEL_CUSTOM_ATTRIBUTES"); v != "" {
+		customAttributes, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_VM_LABEL_CUSTOM_ATTRIBUTES: %s", err)
+		} else {
+			cfg.Nodes.VMLabelCustomAttributes = customAttributes
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_VM_LABEL_TAG_CATEGORY"); v != "" {
+		cfg.Nodes.VMLabelTagCategory = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_VM_LABEL_PREFIX"); v != "" {
+		cfg.Nodes.VMLabelPrefix = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_VM_LABEL_RESYNC_PERIOD"); v != "" {
+		cfg.Nodes.VMLabelResyncPeriod = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_ENABLE_PLACEMENT_LABELS"); v != "" {
+		enable, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_ENABLE_PLACEMENT_LABELS: %s", err)
+		} else {
+			cfg.Nodes.EnablePlacementLabels = enable
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_VM_LOOKUP_ORDER"); v != "" {
+		cfg.Nodes.VMLookupOrder = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_MAX_GUESTINFO_SIZE"); v != "" {
+		maxSize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_MAX_GUESTINFO_SIZE: %s", err)
+		} else {
+			cfg.Nodes.MaxGuestInfoSize = maxSize
+		}
+	}
+	if v := os.Getenv("VSPHERE_NODES_NODE_POOL_LABEL"); v != "" {
+		cfg.Nodes.NodePoolLabel = v
+	}
+	if v := os.Getenv("VSPHERE_NODES_NODE_POOL_VCENTERS"); v != "" {
+		nodePoolVCenters, err := parseNodePoolVCenters(v)
+		if err != nil {
+			return fmt.Errorf("failed to parse VSPHERE_NODES_NODE_POOL_VCENTERS: %v", err)
+		}
+		cfg.Nodes.NodePoolVCenters = nodePoolVCenters
+	}
+	if v := os.Getenv("VSPHERE_NODES_REQUIRE_UUID_MATCH"); v != "" {
+		requireMatch, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_NODES_REQUIRE_UUID_MATCH: %s", err)
+		} else {
+			cfg.Nodes.RequireUUIDMatch = requireMatch
+		}
+	}
+
+	if v := os.Getenv("VSPHERE_LOGGING_MODULE_VERBOSITY"); v != "" {
+		moduleVerbosity, err := parseModuleVerbosity(v)
+		if err != nil {
+			return fmt.Errorf("failed to parse VSPHERE_LOGGING_MODULE_VERBOSITY: %v", err)
+		}
+		cfg.Logging.ModuleVerbosity = moduleVerbosity
+	}
+
+	return nil
+}
+
+// parseModuleVerbosity parses a comma separated list of module=level.
+func parseModuleVerbosity(s string) (map[string]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	moduleVerbosity := make(map[string]int)
+	for _, entry := range strings.Split(s, ",") {
+		module, level, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid module verbosity %q, must be module=level", entry)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("invalid module verbosity %q: %v", entry, err)
+		}
+		moduleVerbosity[strings.TrimSpace(module)] = v
+	}
+	return moduleVerbosity, nil
+}
+
+// parseNodePoolVCenters parses a comma separated list of pool=vcenter or
+// pool=vcenter/datacenter.
+func parseNodePoolVCenters(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	nodePoolVCenters := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		pool, vcenter, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid node pool vCenter %q, must be pool=vcenter or pool=vcenter/datacenter", entry)
+		}
+		nodePoolVCenters[strings.TrimSpace(pool)] = strings.TrimSpace(vcenter)
+	}
+	return nodePoolVCenters, nil
+}
+
+// validateNodes checks the values of the Nodes section.
+func (cfg *CPIConfig) validateNodes() error {
+	switch cfg.Nodes.NSXAddressSource {
+	case "", NSXAddressSourceMerge, NSXAddressSourcePrefer:
+	default:
+		return fmt.Errorf("invalid NSX address source %q, must be %q or %q",
+			cfg.Nodes.NSXAddressSource, NSXAddressSourceMerge, NSXAddressSourcePrefer)
+	}
+	if _, err := cfg.Nodes.InstanceTypeTTLDuration(); err != nil {
+		return err
+	}
+	if _, err := cfg.Nodes.AddressResyncPeriodDuration(); err != nil {
+		return err
+	}
+	if _, err := cfg.Nodes.VMNotFoundGracePeriodDuration(); err != nil {
+		return err
+	}
+	if err := cfg.Nodes.validateVMLabels(); err != nil {
+		return err
+	}
+	if _, err := cfg.Nodes.VMLookupMethods(); err != nil {
+		return err
+	}
+	if err := cfg.Nodes.validateNodePoolVCenters(); err != nil {
+		return err
+	}
+	if err := cfg.Nodes.validateNetworkSelectors(); err != nil {
+		return err
+	}
+	if cfg.Nodes.MaxGuestInfoSize < 0 {
+		return fmt.Errorf("invalid max guestinfo size %d: must not be negative", cfg.Nodes.MaxGuestInfoSize)
+	}
+	return cfg.Nodes.validateAddressWebhook()
+}
+
+// GuestHostnamePublished returns true unless publishing the guest hostname
+// as the Hostname address of the nodes is disabled.
+func (n *Nodes) GuestHostnamePublished() bool {
+	return n.PublishGuestHostname == nil || *n.PublishGuestHostname
+}
+
+// validateNodePoolVCenters checks that every node pool is mapped to a
+// vCenter, and that the node pools are read from a node label.
+func (n *Nodes) validateNodePoolVCenters() error {
+	if len(n.NodePoolVCenters) == 0 {
+		return nil
+	}
+	if n.NodePoolLabel == "" {
+		return errors.New("node pool vCenters require a node pool label")
+	}
+	for pool, vcenter := range n.NodePoolVCenters {
+		if pool == "" || strings.HasPrefix(vcenter, "/") || strings.TrimSpace(vcenter) == "" {
+			return fmt.Errorf("invalid node pool vCenter %q=%q, must be pool=vcenter or pool=vcenter/datacenter", pool, vcenter)
+		}
+	}
+	return nil
+}
 
+// validateNetworkSelectors checks the subnets and the VM network name patterns
+// selecting the node addresses, so that a node is not discovered with an
+// invalid one.
+func (n *Nodes) validateNetworkSelectors() error {
+	for _, cidrs := range []string{
+		n.InternalNetworkSubnetCIDR, n.ExternalNetworkSubnetCIDR,
+		n.ExcludeInternalNetworkSubnetCIDR, n.ExcludeExternalNetworkSubnetCIDR,
+	} {
+		if cidrs == "" {
+			continue
+		}
+		for _, cidr := range strings.Split(cidrs, ",") {
+			if _, err := netip.ParsePrefix(cidr); err != nil {
+				return fmt.Errorf("invalid CIDR address %q: %v", cidr, err)
+			}
+		}
+	}
+	for _, pattern := range []string{n.InternalVMNetworkNamePattern, n.ExternalVMNetworkNamePattern} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid VM network name pattern %q: %v", pattern, err)
+		}
+	}
 	return nil
 }
 
+// MaxGuestInfoSizeOrDefault returns MaxGuestInfoSize, DefaultMaxGuestInfoSize
+// if unset.
+func (n *Nodes) MaxGuestInfoSizeOrDefault() int64 {
+	if n.MaxGuestInfoSize > 0 {
+		return n.MaxGuestInfoSize
+	}
+	return DefaultMaxGuestInfoSize
+}
+
+// VMLookupMethods returns the parsed VMLookupOrder, DefaultVMLookupOrder if
+// unset.
+func (n *Nodes) VMLookupMethods() ([]string, error) {
+	if strings.TrimSpace(n.VMLookupOrder) == "" {
+		return slices.Clone(DefaultVMLookupOrder), nil
+	}
+	var methods []string
+	for _, method := range strings.Split(n.VMLookupOrder, ",") {
+		method = strings.ToLower(strings.TrimSpace(method))
+		switch method {
+		case VMLookupByName, VMLookupByIP:
+		default:
+			return nil, fmt.Errorf("invalid VM lookup order %q: %q must be %q or %q",
+				n.VMLookupOrder, method, VMLookupByName, VMLookupByIP)
+		}
+		if slices.Contains(methods, method) {
+			return nil, fmt.Errorf("invalid VM lookup order %q: %q is listed twice", n.VMLookupOrder, method)
+		}
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
+
+// VMLabelsEnabled returns true if node labels are applied from the custom
+// attributes or the tags of the VMs.
+func (n *Nodes) VMLabelsEnabled() bool {
+	return n.VMLabelCustomAttributes || n.VMLabelTagCategory != ""
+}
+
+// VMLabelPrefixOrDefault returns VMLabelPrefix, DefaultVMLabelPrefix if
+// unset.
+func (n *Nodes) VMLabelPrefixOrDefault() string {
+	if n.VMLabelPrefix != "" {
+		return n.VMLabelPrefix
+	}
+	return DefaultVMLabelPrefix
+}
+
+// validateVMLabels checks the prefix and the resync period of the node labels
+// applied from the VMs, which are ignored unless VMLabelsEnabled.
+func (n *Nodes) validateVMLabels() error {
+	if !n.VMLabelsEnabled() {
+		return nil
+	}
+	// the prefix must make a valid label key with any valid name after it
+	if errs := validation.IsQualifiedName(n.VMLabelPrefixOrDefault() + "x"); len(errs) > 0 {
+		return fmt.Errorf("invalid VM label prefix %q: %s", n.VMLabelPrefix, strings.Join(errs, ", "))
+	}
+	_, err := n.VMLabelResyncPeriodDuration()
+	return err
+}
+
+// VMLabelResyncPeriodDuration returns the parsed VMLabelResyncPeriod,
+// DefaultVMLabelResyncPeriod if unset.
+func (n *Nodes) VMLabelResyncPeriodDuration() (time.Duration, error) {
+	d, err := parseNodesDuration("VM label resync period", n.VMLabelResyncPeriod)
+	if err != nil || d > 0 {
+		return d, err
+	}
+	return DefaultVMLabelResyncPeriod, nil
+}
+
+// validateAddressWebhook checks the settings of the address webhook, which
+// are ignored if AddressWebhookURL is unset.
+func (n *Nodes) validateAddressWebhook() error {
+	if n.AddressWebhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(n.AddressWebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid address webhook URL %q: %v", n.AddressWebhookURL, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid address webhook URL %q: must be an https URL", n.AddressWebhookURL)
+	}
+	switch n.AddressWebhookFailurePolicy {
+	case "", AddressWebhookFailurePolicyIgnore, AddressWebhookFailurePolicyFail:
+	default:
+		return fmt.Errorf("invalid address webhook failure policy %q, must be %q or %q",
+			n.AddressWebhookFailurePolicy, AddressWebhookFailurePolicyIgnore, AddressWebhookFailurePolicyFail)
+	}
+	_, err = n.AddressWebhookTimeoutDuration()
+	return err
+}
+
+// AddressWebhookTimeoutDuration returns the parsed AddressWebhookTimeout,
+// DefaultAddressWebhookTimeout if unset.
+func (n *Nodes) AddressWebhookTimeoutDuration() (time.Duration, error) {
+	d, err := parseNodesDuration("address webhook timeout", n.AddressWebhookTimeout)
+	if err != nil || d > 0 {
+		return d, err
+	}
+	return DefaultAddressWebhookTimeout, nil
+}
+
+// InstanceTypeTTLDuration returns the parsed InstanceTypeTTL, 0 if unset.
+func (n *Nodes) InstanceTypeTTLDuration() (time.Duration, error) {
+	return parseNodesDuration("instance type TTL", n.InstanceTypeTTL)
+}
+
+// AddressResyncPeriodDuration returns the parsed AddressResyncPeriod, 0 if
+// unset.
+func (n *Nodes) AddressResyncPeriodDuration() (time.Duration, error) {
+	return parseNodesDuration("address resync period", n.AddressResyncPeriod)
+}
+
+// VMNotFoundGracePeriodDuration returns the parsed VMNotFoundGracePeriod, 0
+// if unset.
+func (n *Nodes) VMNotFoundGracePeriodDuration() (time.Duration, error) {
+	return parseNodesDuration("VM not found grace period", n.VMNotFoundGracePeriod)
+}
+
+// parseNodesDuration parses a non-negative duration of the Nodes section, 0
+// if unset.
+func parseNodesDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %v", name, value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", name, value)
+	}
+	return d, nil
+}
+
+// Redacted returns a copy of the config whose passwords are replaced by
+// vcfg.RedactedValue, to be logged or dumped.
+func (cfg *CPIConfig) Redacted() *CPIConfig {
+	redacted := *cfg
+	redacted.Config = *cfg.Config.Redacted()
+	return &redacted
+}
+
 /*
 	TODO:
 	When the INI based cloud-config is deprecated, the references to the
@@ -58,6 +525,17 @@ func (cfg *CPIConfig) FromCPIEnv() error {
 // ReadCPIConfig parses vSphere cloud config file and stores it into CPIConfig.
 // Environment variables are also checked
 func ReadCPIConfig(byConfig []byte) (*CPIConfig, error) {
+	return readCPIConfig(byConfig, true)
+}
+
+// ParseCPIConfig parses and validates a vSphere cloud config file like
+// ReadCPIConfig, without the overrides of the environment variables, to check
+// a config submitted by someone else than the cloud provider.
+func ParseCPIConfig(byConfig []byte) (*CPIConfig, error) {
+	return readCPIConfig(byConfig, false)
+}
+
+func readCPIConfig(byConfig []byte, fromEnv bool) (*CPIConfig, error) {
 	if len(byConfig) == 0 {
 		err := fmt.Errorf("no vSphere cloud provider config file given")
 		klog.Error("config is nil")
@@ -80,8 +558,50 @@ func ReadCPIConfig(byConfig []byte) (*CPIConfig, error) {
 	}
 
 	// Env Vars should override config file entries if present
-	if err := cfg.FromCPIEnv(); err != nil {
-		klog.Errorf("FromEnv failed: %s", err)
+	if fromEnv {
+		if err := cfg.FromCPIEnv(); err != nil {
+			klog.Errorf("FromEnv failed: %s", err)
+			return nil, err
+		}
+	}
+
+	if err := cfg.validateNodes(); err != nil {
+		klog.Errorf("validateNodes failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateUnlistedDatacenterPolicies(); err != nil {
+		klog.Errorf("ValidateUnlistedDatacenterPolicies failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateTLSSettings(); err != nil {
+		klog.Errorf("ValidateTLSSettings failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateEndpoints(); err != nil {
+		klog.Errorf("ValidateEndpoints failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateTenantConfigMapSelector(); err != nil {
+		klog.Errorf("ValidateTenantConfigMapSelector failed: %s", err)
+		return nil, err
+	}
+
+	if _, err := cfg.SessionKeepAlivePeriodDuration(); err != nil {
+		klog.Errorf("SessionKeepAlivePeriodDuration failed: %s", err)
+		return nil, err
+	}
+
+	if err := vcfg.CheckUnknownKeys(byConfig, cfg.Global.StrictConfig); err != nil {
+		klog.Errorf("CheckUnknownKeys failed: %s", err)
+		return nil, err
+	}
+
+	if err := logging.ValidateModuleVerbosity(cfg.Logging.ModuleVerbosity); err != nil {
+		klog.Errorf("ValidateModuleVerbosity failed: %s", err)
 		return nil, err
 	}
 
@@ -32,6 +32,11 @@ import (
 // CreateConfig generates a common Config object based on what other structs and funcs
 // are already dependent upon in other packages.
 func (cci *CPIConfigINI) CreateConfig() *CPIConfig {
+	// invalid module verbosities are rejected by ReadCPIConfigINI
+	moduleVerbosity, _ := parseModuleVerbosity(cci.Logging.ModuleVerbosity)
+	// invalid node pool vCenters are rejected by ReadCPIConfigINI
+	nodePoolVCenters, _ := parseNodePoolVCenters(cci.Nodes.NodePoolVCenters)
+
 	cfg := &CPIConfig{
 		*cci.CommonConfigINI.CreateConfig(),
 		Nodes{
@@ -39,8 +44,39 @@ func (cci *CPIConfigINI) CreateConfig() *CPIConfig {
 			ExternalNetworkSubnetCIDR:        cci.Nodes.ExternalNetworkSubnetCIDR,
 			InternalVMNetworkName:            cci.Nodes.InternalVMNetworkName,
 			ExternalVMNetworkName:            cci.Nodes.ExternalVMNetworkName,
+			InternalVMNetworkNamePattern:     cci.Nodes.InternalVMNetworkNamePattern,
+			ExternalVMNetworkNamePattern:     cci.Nodes.ExternalVMNetworkNamePattern,
 			ExcludeInternalNetworkSubnetCIDR: cci.Nodes.ExcludeInternalNetworkSubnetCIDR,
 			ExcludeExternalNetworkSubnetCIDR: cci.Nodes.ExcludeExternalNetworkSubnetCIDR,
+			NSXAddressSource:                 cci.Nodes.NSXAddressSource,
+			InstanceTypeTTL:                  cci.Nodes.InstanceTypeTTL,
+			UpdateInstanceTypeLabels:         cci.Nodes.UpdateInstanceTypeLabels,
+			SourceTemplateKey:                cci.Nodes.SourceTemplateKey,
+			UseNodeNameAsHostname:            cci.Nodes.UseNodeNameAsHostname,
+			AllowEmptyGuestHostname:          cci.Nodes.AllowEmptyGuestHostname,
+			PublishGuestHostname:             cci.Nodes.PublishGuestHostname,
+			SuppressExternalIP:               cci.Nodes.SuppressExternalIP,
+			PublishAllMatchingIPs:            cci.Nodes.PublishAllMatchingIPs,
+			AddressResyncPeriod:              cci.Nodes.AddressResyncPeriod,
+			VMNotFoundGracePeriod:            cci.Nodes.VMNotFoundGracePeriod,
+			AddressWebhookURL:                cci.Nodes.AddressWebhookURL,
+			AddressWebhookTimeout:            cci.Nodes.AddressWebhookTimeout,
+			AddressWebhookFailurePolicy:      cci.Nodes.AddressWebhookFailurePolicy,
+			AddressWebhookCAFile:             cci.Nodes.AddressWebhookCAFile,
+			AddressWebhookTokenFile:          cci.Nodes.AddressWebhookTokenFile,
+			VMLabelCustomAttributes:          cci.Nodes.VMLabelCustomAttributes,
+			VMLabelTagCategory:               cci.Nodes.VMLabelTagCategory,
+			VMLabelPrefix:                    cci.Nodes.VMLabelPrefix,
+			VMLabelResyncPeriod:              cci.Nodes.VMLabelResyncPeriod,
+			EnablePlacementLabels:            cci.Nodes.EnablePlacementLabels,
+			VMLookupOrder:                    cci.Nodes.VMLookupOrder,
+			RequireUUIDMatch:                 cci.Nodes.RequireUUIDMatch,
+			NodePoolLabel:                    cci.Nodes.NodePoolLabel,
+			NodePoolVCenters:                 nodePoolVCenters,
+			MaxGuestInfoSize:                 cci.Nodes.MaxGuestInfoSize,
+		},
+		Logging{
+			ModuleVerbosity: moduleVerbosity,
 		},
 	}
 
@@ -68,7 +104,15 @@ func ReadCPIConfigINI(byConfig []byte) (*CPIConfig, error) {
 		return nil, err
 	}
 
-	cfg := &CPIConfigINI{*vCFG, cfgOLD.Nodes}
+	if _, err := parseModuleVerbosity(cfgOLD.Logging.ModuleVerbosity); err != nil {
+		return nil, err
+	}
+
+	if _, err := parseNodePoolVCenters(cfgOLD.Nodes.NodePoolVCenters); err != nil {
+		return nil, err
+	}
+
+	cfg := &CPIConfigINI{*vCFG, cfgOLD.Nodes, cfgOLD.Logging}
 
 	return cfg.CreateConfig(), nil
 }
@@ -14,6 +14,8 @@ limitations under the License.
 package config
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -50,6 +52,8 @@ ca-file = /some/path/to/a/ca.pem
 [Nodes]
 internal-vm-network-name = "Internal K8s Traffic"
 external-vm-network-name = "External/Outbound Traffic"
+internal-vm-network-name-pattern = "^k8s-internal-.*$"
+external-vm-network-name-pattern = "^k8s-external-.*$"
 `
 
 const excludeSubnetINIConfig = `
@@ -108,6 +112,14 @@ func TestReadINIConfigNetworkName(t *testing.T) {
 	if cfg.Nodes.ExternalVMNetworkName != "External/Outbound Traffic" {
 		t.Errorf("incorrect internal vm network name: %s", cfg.Nodes.ExternalVMNetworkName)
 	}
+
+	if cfg.Nodes.InternalVMNetworkNamePattern != "^k8s-internal-.*$" {
+		t.Errorf("incorrect internal vm network name pattern: %s", cfg.Nodes.InternalVMNetworkNamePattern)
+	}
+
+	if cfg.Nodes.ExternalVMNetworkNamePattern != "^k8s-external-.*$" {
+		t.Errorf("incorrect external vm network name pattern: %s", cfg.Nodes.ExternalVMNetworkNamePattern)
+	}
 }
 
 func TestReadINIConfigExcludeSubnetCidr(t *testing.T) {
@@ -124,3 +136,160 @@ func TestReadINIConfigExcludeSubnetCidr(t *testing.T) {
 		t.Errorf("incorrect exclude external network subnet cidrs: %s", cfg.Nodes.ExcludeExternalNetworkSubnetCIDR)
 	}
 }
+
+func TestReadCPIConfigStrictINI(t *testing.T) {
+	config := `
+[Global]
+server = 0.0.0.0
+port = 443
+user = user
+password = password
+insecure-flag = true
+datacenters = us-west
+strict-config = error
+
+[Nodes]
+internal-network-subnet-cdir = "192.0.2.0/24"
+`
+
+	_, err := ReadCPIConfig([]byte(config))
+	if err == nil || !strings.Contains(err.Error(), `did you mean "internal-network-subnet-cidr"?`) {
+		t.Errorf("Should fail on unknown keys, got: %v", err)
+	}
+
+	cfg, err := ReadCPIConfig([]byte(strings.Replace(config, "cdir", "cidr", 1)))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.InternalNetworkSubnetCIDR != "192.0.2.0/24" {
+		t.Errorf("incorrect internal network subnet cidr: %s", cfg.Nodes.InternalNetworkSubnetCIDR)
+	}
+}
+
+func TestReadINIConfigInstanceType(t *testing.T) {
+	cfg, err := ReadCPIConfigINI([]byte(`
+[Global]
+server = 0.0.0.0
+user = user
+password = password
+datacenters = us-west
+
+[Nodes]
+instance-type-ttl = 1h
+update-instance-type-labels = true
+source-template-key = image.template
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.InstanceTypeTTL != "1h" {
+		t.Errorf("incorrect instance type ttl: %s", cfg.Nodes.InstanceTypeTTL)
+	}
+	if !cfg.Nodes.UpdateInstanceTypeLabels {
+		t.Error("update instance type labels should be set")
+	}
+	if cfg.Nodes.SourceTemplateKey != "image.template" {
+		t.Errorf("incorrect source template key: %s", cfg.Nodes.SourceTemplateKey)
+	}
+}
+
+func TestReadINIConfigHostname(t *testing.T) {
+	cfg, err := ReadCPIConfigINI([]byte(`
+[Global]
+server = 0.0.0.0
+user = user
+password = password
+datacenters = us-west
+
+[Nodes]
+use-node-name-as-hostname = true
+allow-empty-guest-hostname = true
+suppress-external-ip = true
+publish-all-matching-ips = true
+publish-guest-hostname = false
+vm-lookup-order = name
+require-uuid-match = true
+max-guestinfo-size = 65536
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if !cfg.Nodes.UseNodeNameAsHostname {
+		t.Error("use node name as hostname should be set")
+	}
+	if !cfg.Nodes.AllowEmptyGuestHostname {
+		t.Error("allow empty guest hostname should be set")
+	}
+	if !cfg.Nodes.SuppressExternalIP {
+		t.Error("suppress external IP should be set")
+	}
+	if !cfg.Nodes.PublishAllMatchingIPs {
+		t.Error("publish all matching IPs should be set")
+	}
+	if cfg.Nodes.GuestHostnamePublished() {
+		t.Error("publish guest hostname should be unset")
+	}
+	if cfg.Nodes.VMLookupOrder != "name" {
+		t.Errorf("incorrect VM lookup order: %s", cfg.Nodes.VMLookupOrder)
+	}
+	if !cfg.Nodes.RequireUUIDMatch {
+		t.Error("require UUID match should be set")
+	}
+	if cfg.Nodes.MaxGuestInfoSize != 65536 {
+		t.Errorf("incorrect max guestinfo size: %d", cfg.Nodes.MaxGuestInfoSize)
+	}
+}
+
+func TestReadINIConfigNodePoolVCenters(t *testing.T) {
+	config := `
+[Global]
+server = 0.0.0.0
+user = user
+password = password
+datacenters = us-west
+
+[Nodes]
+node-pool-label = example.com/pool
+node-pool-vcenters = "%s"
+`
+
+	cfg, err := ReadCPIConfigINI([]byte(strings.ReplaceAll(config, "%s", "pool-a=vc1.example.com, pool-b=vc2.example.com/dc2")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	expected := map[string]string{"pool-a": "vc1.example.com", "pool-b": "vc2.example.com/dc2"}
+	if cfg.Nodes.NodePoolLabel != "example.com/pool" || !reflect.DeepEqual(cfg.Nodes.NodePoolVCenters, expected) {
+		t.Errorf("incorrect node pool vCenters: %s %v", cfg.Nodes.NodePoolLabel, cfg.Nodes.NodePoolVCenters)
+	}
+
+	if _, err := ReadCPIConfigINI([]byte(strings.ReplaceAll(config, "%s", "pool-a"))); err == nil {
+		t.Error("Should fail on invalid node pool vCenters")
+	}
+}
+
+func TestReadINIConfigLogging(t *testing.T) {
+	config := `
+[Global]
+server = 0.0.0.0
+user = user
+password = password
+datacenters = us-west
+
+[Logging]
+module-verbosity = "%s"
+`
+
+	cfg, err := ReadCPIConfigINI([]byte(strings.ReplaceAll(config, "%s", "loadbalancer=6, nodemanager=2")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Logging.ModuleVerbosity["loadbalancer"] != 6 || cfg.Logging.ModuleVerbosity["nodemanager"] != 2 {
+		t.Errorf("incorrect module verbosity: %v", cfg.Logging.ModuleVerbosity)
+	}
+
+	for _, moduleVerbosity := range []string{"loadbalancer", "loadbalancer=high"} {
+		if _, err := ReadCPIConfigINI([]byte(strings.ReplaceAll(config, "%s", moduleVerbosity))); err == nil {
+			t.Errorf("Should fail on an invalid module verbosity %q", moduleVerbosity)
+		}
+	}
+}
@@ -40,8 +40,39 @@ func (ccy *CPIConfigYAML) CreateConfig() *CPIConfig {
 			ExternalNetworkSubnetCIDR:        ccy.Nodes.ExternalNetworkSubnetCIDR,
 			InternalVMNetworkName:            ccy.Nodes.InternalVMNetworkName,
 			ExternalVMNetworkName:            ccy.Nodes.ExternalVMNetworkName,
+			InternalVMNetworkNamePattern:     ccy.Nodes.InternalVMNetworkNamePattern,
+			ExternalVMNetworkNamePattern:     ccy.Nodes.ExternalVMNetworkNamePattern,
 			ExcludeInternalNetworkSubnetCIDR: ccy.Nodes.ExcludeInternalNetworkSubnetCIDR,
 			ExcludeExternalNetworkSubnetCIDR: ccy.Nodes.ExcludeExternalNetworkSubnetCIDR,
+			NSXAddressSource:                 ccy.Nodes.NSXAddressSource,
+			InstanceTypeTTL:                  ccy.Nodes.InstanceTypeTTL,
+			UpdateInstanceTypeLabels:         ccy.Nodes.UpdateInstanceTypeLabels,
+			SourceTemplateKey:                ccy.Nodes.SourceTemplateKey,
+			UseNodeNameAsHostname:            ccy.Nodes.UseNodeNameAsHostname,
+			AllowEmptyGuestHostname:          ccy.Nodes.AllowEmptyGuestHostname,
+			PublishGuestHostname:             ccy.Nodes.PublishGuestHostname,
+			SuppressExternalIP:               ccy.Nodes.SuppressExternalIP,
+			PublishAllMatchingIPs:            ccy.Nodes.PublishAllMatchingIPs,
+			AddressResyncPeriod:              ccy.Nodes.AddressResyncPeriod,
+			VMNotFoundGracePeriod:            ccy.Nodes.VMNotFoundGracePeriod,
+			AddressWebhookURL:                ccy.Nodes.AddressWebhookURL,
+			AddressWebhookTimeout:            ccy.Nodes.AddressWebhookTimeout,
+			AddressWebhookFailurePolicy:      ccy.Nodes.AddressWebhookFailurePolicy,
+			AddressWebhookCAFile:             ccy.Nodes.AddressWebhookCAFile,
+			AddressWebhookTokenFile:          ccy.Nodes.AddressWebhookTokenFile,
+			VMLabelCustomAttributes:          ccy.Nodes.VMLabelCustomAttributes,
+			VMLabelTagCategory:               ccy.Nodes.VMLabelTagCategory,
+			VMLabelPrefix:                    ccy.Nodes.VMLabelPrefix,
+			VMLabelResyncPeriod:              ccy.Nodes.VMLabelResyncPeriod,
+			EnablePlacementLabels:            ccy.Nodes.EnablePlacementLabels,
+			VMLookupOrder:                    ccy.Nodes.VMLookupOrder,
+			RequireUUIDMatch:                 ccy.Nodes.RequireUUIDMatch,
+			NodePoolLabel:                    ccy.Nodes.NodePoolLabel,
+			NodePoolVCenters:                 ccy.Nodes.NodePoolVCenters,
+			MaxGuestInfoSize:                 ccy.Nodes.MaxGuestInfoSize,
+		},
+		Logging{
+			ModuleVerbosity: ccy.Logging.ModuleVerbosity,
 		},
 	}
 
@@ -67,7 +98,7 @@ func ReadCPIConfigYAML(byConfig []byte) (*CPIConfig, error) {
 		return nil, err
 	}
 
-	cfg := &CPIConfigYAML{*vCFG, cfgOLD.Nodes}
+	cfg := &CPIConfigYAML{*vCFG, cfgOLD.Nodes, cfgOLD.Logging}
 
 	return cfg.CreateConfig(), nil
 }
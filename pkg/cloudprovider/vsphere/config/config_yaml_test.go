@@ -17,7 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 /*
@@ -55,6 +58,8 @@ global:
 nodes:
   internalVmNetworkName: Internal K8s Traffic
   externalVmNetworkName: External/Outbound Traffic
+  internalVmNetworkNamePattern: ^k8s-internal-.*$
+  externalVmNetworkNamePattern: ^k8s-external-.*$
 `
 
 const excludeSubnetCidrYAMLConfig = `
@@ -114,6 +119,14 @@ func TestReadYAMLConfigNetworkName(t *testing.T) {
 	if cfg.Nodes.ExternalVMNetworkName != "External/Outbound Traffic" {
 		t.Errorf("incorrect internal vm network name: %s", cfg.Nodes.ExternalVMNetworkName)
 	}
+
+	if cfg.Nodes.InternalVMNetworkNamePattern != "^k8s-internal-.*$" {
+		t.Errorf("incorrect internal vm network name pattern: %s", cfg.Nodes.InternalVMNetworkNamePattern)
+	}
+
+	if cfg.Nodes.ExternalVMNetworkNamePattern != "^k8s-external-.*$" {
+		t.Errorf("incorrect external vm network name pattern: %s", cfg.Nodes.ExternalVMNetworkNamePattern)
+	}
 }
 
 func TestReadYAMLConfigExcludeSubnetCidr(t *testing.T) {
@@ -130,3 +143,558 @@ func TestReadYAMLConfigExcludeSubnetCidr(t *testing.T) {
 		t.Errorf("incorrect exclude external network subnet cidrs: %s", cfg.Nodes.ExcludeExternalNetworkSubnetCIDR)
 	}
 }
+
+func TestReadCPIConfigNSXAddressSource(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  nsxAddressSource: %s
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", NSXAddressSourcePrefer)))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.NSXAddressSource != NSXAddressSourcePrefer {
+		t.Errorf("incorrect nsx address source: %s", cfg.Nodes.NSXAddressSource)
+	}
+
+	if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "nsx"))); err == nil {
+		t.Error("Should fail on an invalid nsx address source")
+	}
+
+	t.Setenv("VSPHERE_NODES_NSX_ADDRESS_SOURCE", NSXAddressSourceMerge)
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", NSXAddressSourcePrefer)))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.NSXAddressSource != NSXAddressSourceMerge {
+		t.Errorf("incorrect nsx address source from environment: %s", cfg.Nodes.NSXAddressSource)
+	}
+}
+
+func TestReadCPIConfigInstanceType(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  instanceTypeTtl: %s
+  updateInstanceTypeLabels: true
+  sourceTemplateKey: image.template
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "10m")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if ttl, err := cfg.Nodes.InstanceTypeTTLDuration(); err != nil || ttl != 10*time.Minute {
+		t.Errorf("incorrect instance type ttl: %s %v", cfg.Nodes.InstanceTypeTTL, err)
+	}
+	if !cfg.Nodes.UpdateInstanceTypeLabels {
+		t.Error("update instance type labels should be set")
+	}
+	if cfg.Nodes.SourceTemplateKey != "image.template" {
+		t.Errorf("incorrect source template key: %s", cfg.Nodes.SourceTemplateKey)
+	}
+
+	for _, ttl := range []string{"ten", "-1m"} {
+		if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", ttl))); err == nil {
+			t.Errorf("Should fail on an invalid instance type ttl %q", ttl)
+		}
+	}
+
+	t.Setenv("VSPHERE_NODES_UPDATE_INSTANCE_TYPE_LABELS", "false")
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "10m")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.UpdateInstanceTypeLabels {
+		t.Error("update instance type labels should be unset from the environment")
+	}
+}
+
+func TestReadCPIConfigAddressResyncPeriod(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  addressResyncPeriod: %s
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "5m")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if period, err := cfg.Nodes.AddressResyncPeriodDuration(); err != nil || period != 5*time.Minute {
+		t.Errorf("incorrect address resync period: %s %v", cfg.Nodes.AddressResyncPeriod, err)
+	}
+
+	for _, period := range []string{"five", "-1m"} {
+		if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", period))); err == nil {
+			t.Errorf("Should fail on an invalid address resync period %q", period)
+		}
+	}
+
+	t.Setenv("VSPHERE_NODES_ADDRESS_RESYNC_PERIOD", "1h")
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "5m")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.AddressResyncPeriod != "1h" {
+		t.Errorf("address resync period should be set from the environment, got %s", cfg.Nodes.AddressResyncPeriod)
+	}
+}
+
+func TestReadCPIConfigVMNotFoundGracePeriod(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  vmNotFoundGracePeriod: %s
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "2m")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if period, err := cfg.Nodes.VMNotFoundGracePeriodDuration(); err != nil || period != 2*time.Minute {
+		t.Errorf("incorrect VM not found grace period: %s %v", cfg.Nodes.VMNotFoundGracePeriod, err)
+	}
+
+	if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "-1m"))); err == nil {
+		t.Error("Should fail on a negative VM not found grace period")
+	}
+}
+
+func TestReadCPIConfigMaxGuestInfoSize(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  maxGuestInfoSize: %s
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "65536")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if size := cfg.Nodes.MaxGuestInfoSizeOrDefault(); size != 65536 {
+		t.Errorf("incorrect max guestinfo size: %d", size)
+	}
+
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "0")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if size := cfg.Nodes.MaxGuestInfoSizeOrDefault(); size != DefaultMaxGuestInfoSize {
+		t.Errorf("max guestinfo size should default to %d, got %d", DefaultMaxGuestInfoSize, size)
+	}
+
+	if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "-1"))); err == nil {
+		t.Error("Should fail on a negative max guestinfo size")
+	}
+}
+
+func TestReadCPIConfigNetworkSelectors(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  %s
+`
+
+	for _, nodes := range []string{
+		"excludeInternalNetworkSubnetCidr: 192.0.2.0/24,2001:db8::/64",
+		"externalVmNetworkNamePattern: ^vlan-[0-9]+$",
+	} {
+		if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", nodes))); err != nil {
+			t.Errorf("Should succeed when a valid config is provided: %s", err)
+		}
+	}
+
+	for _, nodes := range []string{
+		"internalNetworkSubnetCidr: 192.0.2.0/24,garbage",
+		"excludeExternalNetworkSubnetCidr: 198.51.100.1",
+		"internalVmNetworkNamePattern: \"(\"",
+	} {
+		if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", nodes))); err == nil {
+			t.Errorf("Should fail on %s", nodes)
+		}
+	}
+}
+
+func TestReadCPIConfigAddressWebhook(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  addressWebhookURL: %s
+  addressWebhookFailurePolicy: %p
+  addressWebhookTimeout: %t
+  addressWebhookTokenFile: /etc/webhook/token
+`
+	withWebhook := func(url, policy, timeout string) []byte {
+		return []byte(strings.NewReplacer("%s", url, "%p", policy, "%t", timeout).Replace(config))
+	}
+
+	cfg, err := ReadCPIConfig(withWebhook("https://webhook.example.com/addresses", "fail", `""`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.AddressWebhookURL != "https://webhook.example.com/addresses" ||
+		cfg.Nodes.AddressWebhookFailurePolicy != AddressWebhookFailurePolicyFail ||
+		cfg.Nodes.AddressWebhookTokenFile != "/etc/webhook/token" {
+		t.Errorf("incorrect address webhook: %+v", cfg.Nodes)
+	}
+	if timeout, err := cfg.Nodes.AddressWebhookTimeoutDuration(); err != nil || timeout != DefaultAddressWebhookTimeout {
+		t.Errorf("address webhook timeout should default to %s, got %s %v", DefaultAddressWebhookTimeout, timeout, err)
+	}
+
+	for _, invalid := range [][]string{
+		{"http://webhook.example.com/addresses", "fail", "5s"},
+		{"https://webhook.example.com/addresses", "retry", "5s"},
+		{"https://webhook.example.com/addresses", "fail", "-1s"},
+	} {
+		if _, err := ReadCPIConfig(withWebhook(invalid[0], invalid[1], invalid[2])); err == nil {
+			t.Errorf("Should fail on an invalid address webhook %q", invalid)
+		}
+	}
+}
+
+func TestReadCPIConfigVMLabels(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  vmLabelCustomAttributes: true
+  vmLabelTagCategory: k8s-labels
+  vmLabelPrefix: %p
+  vmLabelResyncPeriod: %s
+`
+	withLabels := func(prefix, period string) []byte {
+		return []byte(strings.NewReplacer("%p", prefix, "%s", period).Replace(config))
+	}
+
+	cfg, err := ReadCPIConfig(withLabels(`""`, `""`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if !cfg.Nodes.VMLabelsEnabled() || cfg.Nodes.VMLabelTagCategory != "k8s-labels" {
+		t.Errorf("incorrect VM labels: %+v", cfg.Nodes)
+	}
+	if prefix := cfg.Nodes.VMLabelPrefixOrDefault(); prefix != DefaultVMLabelPrefix {
+		t.Errorf("VM label prefix should default to %s, got %s", DefaultVMLabelPrefix, prefix)
+	}
+	if period, err := cfg.Nodes.VMLabelResyncPeriodDuration(); err != nil || period != DefaultVMLabelResyncPeriod {
+		t.Errorf("VM label resync period should default to %s, got %s %v", DefaultVMLabelResyncPeriod, period, err)
+	}
+
+	cfg, err = ReadCPIConfig(withLabels("hardware.example.com/", "5m"))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if period, err := cfg.Nodes.VMLabelResyncPeriodDuration(); err != nil || period != 5*time.Minute {
+		t.Errorf("incorrect VM label resync period: %s %v", cfg.Nodes.VMLabelResyncPeriod, err)
+	}
+
+	for _, invalid := range [][]string{
+		{"hardware.example.com/rack/", "5m"},
+		{"-hardware.example.com/", "5m"},
+		{"hardware.example.com/", "-1m"},
+	} {
+		if _, err := ReadCPIConfig(withLabels(invalid[0], invalid[1])); err == nil {
+			t.Errorf("Should fail on invalid VM labels %q", invalid)
+		}
+	}
+}
+
+func TestReadCPIConfigHostname(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  useNodeNameAsHostname: true
+  allowEmptyGuestHostname: true
+  suppressExternalIP: true
+  publishAllMatchingIPs: true
+`
+
+	cfg, err := ReadCPIConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if !cfg.Nodes.UseNodeNameAsHostname {
+		t.Error("use node name as hostname should be set")
+	}
+	if !cfg.Nodes.AllowEmptyGuestHostname {
+		t.Error("allow empty guest hostname should be set")
+	}
+	if !cfg.Nodes.SuppressExternalIP {
+		t.Error("suppress external IP should be set")
+	}
+	if !cfg.Nodes.PublishAllMatchingIPs {
+		t.Error("publish all matching IPs should be set")
+	}
+	if !cfg.Nodes.GuestHostnamePublished() {
+		t.Error("guest hostname should be published by default")
+	}
+
+	t.Setenv("VSPHERE_NODES_ALLOW_EMPTY_GUEST_HOSTNAME", "false")
+	t.Setenv("VSPHERE_NODES_PUBLISH_GUEST_HOSTNAME", "false")
+	cfg, err = ReadCPIConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Nodes.AllowEmptyGuestHostname {
+		t.Error("allow empty guest hostname should be unset from the environment")
+	}
+	if cfg.Nodes.GuestHostnamePublished() {
+		t.Error("publish guest hostname should be unset from the environment")
+	}
+}
+
+func TestReadCPIConfigVMLookup(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  vmLookupOrder: "%s"
+  requireUUIDMatch: true
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "ip, Name")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if methods, err := cfg.Nodes.VMLookupMethods(); err != nil || !reflect.DeepEqual(methods, []string{VMLookupByIP, VMLookupByName}) {
+		t.Errorf("incorrect VM lookup order: %v %v", methods, err)
+	}
+	if !cfg.Nodes.RequireUUIDMatch {
+		t.Error("require UUID match should be set")
+	}
+
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if methods, err := cfg.Nodes.VMLookupMethods(); err != nil || !reflect.DeepEqual(methods, DefaultVMLookupOrder) {
+		t.Errorf("VM lookup order should default to %v, got %v %v", DefaultVMLookupOrder, methods, err)
+	}
+
+	for _, order := range []string{"dns", "name,name"} {
+		if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", order))); err == nil {
+			t.Errorf("Should fail on the invalid VM lookup order %q", order)
+		}
+	}
+
+	t.Setenv("VSPHERE_NODES_VM_LOOKUP_ORDER", VMLookupByName)
+	t.Setenv("VSPHERE_NODES_REQUIRE_UUID_MATCH", "false")
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "ip,name")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if methods, err := cfg.Nodes.VMLookupMethods(); err != nil || !reflect.DeepEqual(methods, []string{VMLookupByName}) {
+		t.Errorf("incorrect VM lookup order from environment: %v %v", methods, err)
+	}
+	if cfg.Nodes.RequireUUIDMatch {
+		t.Error("require UUID match should be unset from the environment")
+	}
+}
+
+func TestReadCPIConfigNodePoolVCenters(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+nodes:
+  nodePoolLabel: %s
+  nodePoolVCenters:
+    pool-a: vc1.example.com
+    pool-b: vc2.example.com/dc2
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "example.com/pool")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	expected := map[string]string{"pool-a": "vc1.example.com", "pool-b": "vc2.example.com/dc2"}
+	if cfg.Nodes.NodePoolLabel != "example.com/pool" || !reflect.DeepEqual(cfg.Nodes.NodePoolVCenters, expected) {
+		t.Errorf("incorrect node pool vCenters: %s %v", cfg.Nodes.NodePoolLabel, cfg.Nodes.NodePoolVCenters)
+	}
+
+	if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", `""`))); err == nil {
+		t.Error("Should fail on node pool vCenters without node pool label")
+	}
+
+	t.Setenv("VSPHERE_NODES_NODE_POOL_VCENTERS", "pool-c=vc3.example.com")
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "example.com/pool")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if expected := map[string]string{"pool-c": "vc3.example.com"}; !reflect.DeepEqual(cfg.Nodes.NodePoolVCenters, expected) {
+		t.Errorf("incorrect node pool vCenters from environment: %v", cfg.Nodes.NodePoolVCenters)
+	}
+
+	t.Setenv("VSPHERE_NODES_NODE_POOL_VCENTERS", "pool-c")
+	if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "example.com/pool"))); err == nil {
+		t.Error("Should fail on invalid node pool vCenters from environment")
+	}
+}
+
+func TestReadCPIConfigLogging(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  insecureFlag: true
+  datacenters:
+    - us-west
+
+logging:
+  moduleVerbosity:
+    %s: 6
+    nodemanager: 2
+`
+
+	cfg, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "loadbalancer")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if cfg.Logging.ModuleVerbosity["loadbalancer"] != 6 || cfg.Logging.ModuleVerbosity["nodemanager"] != 2 {
+		t.Errorf("incorrect module verbosity: %v", cfg.Logging.ModuleVerbosity)
+	}
+
+	if _, err := ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "nsx"))); err == nil {
+		t.Error("Should fail on an unknown logging module")
+	}
+
+	t.Setenv("VSPHERE_LOGGING_MODULE_VERBOSITY", "connectionmanager=4")
+	cfg, err = ReadCPIConfig([]byte(strings.ReplaceAll(config, "%s", "loadbalancer")))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if len(cfg.Logging.ModuleVerbosity) != 1 || cfg.Logging.ModuleVerbosity["connectionmanager"] != 4 {
+		t.Errorf("module verbosity should be replaced from the environment: %v", cfg.Logging.ModuleVerbosity)
+	}
+}
+
+func TestReadCPIConfigJSON(t *testing.T) {
+	config := `{
+	"global": {
+		"user": "user",
+		"password": "password",
+		"insecureFlag": true
+	},
+	"vcenter": {
+		"tenant1": {
+			"server": "10.0.0.1",
+			"datacenters": ["us-west", "us-east"]
+		}
+	},
+	"labels": {
+		"region": "k8s-region",
+		"zone": "k8s-zone"
+	},
+	"nodes": {
+		"internalNetworkSubnetCidr": "192.0.2.0/24",
+		"vmLookupOrder": "ip,name"
+	}
+}`
+
+	cfg, err := ReadCPIConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	vcConfig, ok := cfg.VirtualCenter["tenant1"]
+	if !ok {
+		t.Fatalf("vcenter tenant1 should be configured: %v", cfg.VirtualCenter)
+	}
+	if vcConfig.VCenterIP != "10.0.0.1" || vcConfig.User != "user" || !vcConfig.InsecureFlag || vcConfig.Datacenters != "us-west,us-east" {
+		t.Errorf("incorrect vcenter config: %+v", vcConfig)
+	}
+	if cfg.Labels.Region != "k8s-region" || cfg.Labels.Zone != "k8s-zone" {
+		t.Errorf("incorrect labels: %+v", cfg.Labels)
+	}
+	if cfg.Nodes.InternalNetworkSubnetCIDR != "192.0.2.0/24" || cfg.Nodes.VMLookupOrder != "ip,name" {
+		t.Errorf("incorrect nodes config: %+v", cfg.Nodes)
+	}
+}
@@ -38,15 +38,125 @@ type Nodes struct {
 	// only have a single IP address assigned to it.
 	InternalVMNetworkName string
 	ExternalVMNetworkName string
+	// Regular expressions matched against the VirtualMachine's VM Network names,
+	// in addition to InternalVMNetworkName and ExternalVMNetworkName.
+	InternalVMNetworkNamePattern string
+	ExternalVMNetworkNamePattern string
 	// IP addresses in these subnet ranges will be excluded when selecting
 	// the IP address from the VirtualMachine's VM for use in the
 	// status.addresses fields.
 	ExcludeInternalNetworkSubnetCIDR string
 	ExcludeExternalNetworkSubnetCIDR string
+	// Source of node addresses in addition to VMware Tools: with "merge" the
+	// addresses NSX-T realized on the VM's segment ports are added after
+	// the ones reported by the guest, with "prefer" they take precedence.
+	// Requires the NSXT section.
+	NSXAddressSource string
+	// How long the instance type of a node, derived from the VM's CPUs,
+	// memory and guest OS, is cached before the VM is read again, as a Go
+	// duration such as "10m". Unset caches it until restart.
+	InstanceTypeTTL string
+	// Write refreshed instance types to the node's instance type labels,
+	// which are otherwise only set when the node is initialized.
+	UpdateInstanceTypeLabels bool
+	// Key of the VM's extraConfig holding the name of the template the VM
+	// was built from, published as the node's source template annotation.
+	// Defaults to "guestinfo.source-template".
+	SourceTemplateKey string
+	// Publish the name of the Kubernetes node as its Hostname address
+	// instead of the hostname reported by VMware Tools, which may differ.
+	UseNodeNameAsHostname bool
+	// Discover nodes by UUID even if VMware Tools does not report a guest
+	// hostname. Without UseNodeNameAsHostname, no Hostname address is
+	// published for them.
+	AllowEmptyGuestHostname bool
+	// Publish the hostname reported by VMware Tools as the Hostname address of
+	// the node. Defaults to true, disable it when a guest hostname differing
+	// from the node name breaks the certificates of the kubelet or webhooks.
+	PublishGuestHostname *bool
+	// Publish only the InternalIP and Hostname addresses of the nodes, never
+	// an ExternalIP, regardless of the external network settings and the
+	// external IP annotation.
+	SuppressExternalIP bool
+	// Publish every address matching the internal and external network
+	// settings, rather than only the most preferred one of each type and IP
+	// family.
+	PublishAllMatchingIPs bool
+	// Period at which the addresses of the registered nodes are discovered
+	// again and published in the node's status, so that IP changes after a
+	// vMotion or a DHCP lease renewal are picked up, as a Go duration such as
+	// "5m". Unset discovers them only when the node registers.
+	AddressResyncPeriod string
+	// How long a node whose VM was discovered before is still reported as
+	// existing while its VM cannot be found, so that the inventory lag after
+	// a cross vCenter vMotion or a re-registration does not delete the node,
+	// as a Go duration such as "2m". Unset reports it as deleted right away.
+	VMNotFoundGracePeriod string
+	// HTTPS URL of a webhook receiving the address candidates of each
+	// discovered node, which may reorder or filter the addresses published.
+	AddressWebhookURL string
+	// Timeout of a call to the address webhook, as a Go duration such as
+	// "5s". Defaults to 10s.
+	AddressWebhookTimeout string
+	// What happens when the address webhook cannot be called or returns an
+	// invalid response: "ignore" (default) publishes the discovered
+	// addresses, "fail" fails the discovery of the node.
+	AddressWebhookFailurePolicy string
+	// CA bundle verifying the certificate of the address webhook. The system
+	// roots are used if unset.
+	AddressWebhookCAFile string
+	// File holding the bearer token sent to the address webhook, read before
+	// each call so that it can be rotated.
+	AddressWebhookTokenFile string
+	// Apply the vSphere custom attributes of the node's VM as node labels,
+	// named VMLabelPrefix followed by the attribute name.
+	VMLabelCustomAttributes bool
+	// Category of the vSphere tags of the node's VM applied as node labels:
+	// a tag named "key=value" becomes the label VMLabelPrefix + key with
+	// that value, any other tag a label named after it with the value "true".
+	VMLabelTagCategory string
+	// Prefix of the node labels applied from the custom attributes and tags,
+	// labels with this prefix the VM no longer has are removed. Defaults to
+	// "vm.vsphere.vmware.com/".
+	VMLabelPrefix string
+	// Period at which the labels applied from the custom attributes and tags
+	// are read again from the VMs, as a Go duration such as "5m". Defaults
+	// to 10m.
+	VMLabelResyncPeriod string
+	// Report the ESXi host, the compute cluster and the resource pool of the
+	// node's VM as node labels through InstanceMetadata, which is only used
+	// with --vsphere-instances-v2.
+	EnablePlacementLabels bool
+	// Comma separated order in which the VM of a node looked up by name is
+	// searched: "name" by its DNS name, "ip" by its IP address. Defaults to
+	// "name,ip", set it to "name" where IP addresses overlap.
+	VMLookupOrder string
+	// Discover a node looked up by name only if the UUID of the VM found
+	// matches the SystemUUID of the node, or its VM UUID annotation.
+	RequireUUIDMatch bool
+	// Label of the nodes holding the name of their node pool, whose VMs are
+	// searched first in the vCenter NodePoolVCenters maps the pool to.
+	NodePoolLabel string
+	// vCenter, written as vcenter or vcenter/datacenter, the VMs of the nodes
+	// of each node pool are searched in first, before the other vCenters.
+	NodePoolVCenters map[string]string
+	// Maximum size in bytes of the decoded, and decompressed, guestinfo
+	// metadata of a VM, whose statically configured addresses are preferred.
+	// Larger metadata fails the discovery of the node. Defaults to 1 MiB.
+	MaxGuestInfoSize int64
+}
+
+// Logging captures the verbosity overrides of the logging modules
+type Logging struct {
+	// Verbosity of the modules (nodemanager, loadbalancer, paravirtual and
+	// connectionmanager) overriding the klog -v flag, so that one module can
+	// log at a higher verbosity than the others.
+	ModuleVerbosity map[string]int
 }
 
 // CPIConfig is used to read and store information (related only to the CPI) from the cloud configuration file
 type CPIConfig struct {
 	vcfg.Config
-	Nodes Nodes
+	Nodes   Nodes
+	Logging Logging
 }
@@ -37,15 +37,124 @@ type NodesINI struct {
 	// only have a single IP address assigned to it.
 	InternalVMNetworkName string `gcfg:"internal-vm-network-name"`
 	ExternalVMNetworkName string `gcfg:"external-vm-network-name"`
+	// Regular expressions matched against the VirtualMachine's VM Network names,
+	// in addition to InternalVMNetworkName and ExternalVMNetworkName.
+	InternalVMNetworkNamePattern string `gcfg:"internal-vm-network-name-pattern"`
+	ExternalVMNetworkNamePattern string `gcfg:"external-vm-network-name-pattern"`
 	// IP addresses in these subnet ranges will be excluded when selecting
 	// the IP address from the VirtualMachine's VM for use in the
 	// status.addresses fields.
 	ExcludeInternalNetworkSubnetCIDR string `gcfg:"exclude-internal-network-subnet-cidr"`
 	ExcludeExternalNetworkSubnetCIDR string `gcfg:"exclude-external-network-subnet-cidr"`
+	// Source of node addresses in addition to VMware Tools: with "merge" the
+	// addresses NSX-T realized on the VM's segment ports are added after
+	// the ones reported by the guest, with "prefer" they take precedence.
+	// Requires the NSXT section.
+	NSXAddressSource string `gcfg:"nsx-address-source"`
+	// How long the instance type of a node, derived from the VM's CPUs,
+	// memory and guest OS, is cached before the VM is read again, as a Go
+	// duration such as "10m". Unset caches it until restart.
+	InstanceTypeTTL string `gcfg:"instance-type-ttl"`
+	// Write refreshed instance types to the node's instance type labels,
+	// which are otherwise only set when the node is initialized.
+	UpdateInstanceTypeLabels bool `gcfg:"update-instance-type-labels"`
+	// Key of the VM's extraConfig holding the name of the template the VM
+	// was built from, published as the node's source template annotation.
+	// Defaults to "guestinfo.source-template".
+	SourceTemplateKey string `gcfg:"source-template-key"`
+	// Publish the name of the Kubernetes node as its Hostname address
+	// instead of the hostname reported by VMware Tools, which may differ.
+	UseNodeNameAsHostname bool `gcfg:"use-node-name-as-hostname"`
+	// Discover nodes by UUID even if VMware Tools does not report a guest
+	// hostname. Without UseNodeNameAsHostname, no Hostname address is
+	// published for them.
+	AllowEmptyGuestHostname bool `gcfg:"allow-empty-guest-hostname"`
+	// Publish the hostname reported by VMware Tools as the Hostname address of
+	// the node. Defaults to true, disable it when a guest hostname differing
+	// from the node name breaks the certificates of the kubelet or webhooks.
+	PublishGuestHostname *bool `gcfg:"publish-guest-hostname"`
+	// Publish only the InternalIP and Hostname addresses of the nodes, never
+	// an ExternalIP, regardless of the external network settings and the
+	// external IP annotation.
+	SuppressExternalIP bool `gcfg:"suppress-external-ip"`
+	// Publish every address matching the internal and external network
+	// settings, rather than only the most preferred one of each type and IP
+	// family.
+	PublishAllMatchingIPs bool `gcfg:"publish-all-matching-ips"`
+	// Period at which the addresses of the registered nodes are discovered
+	// again and published in the node's status, so that IP changes after a
+	// vMotion or a DHCP lease renewal are picked up, as a Go duration such as
+	// "5m". Unset discovers them only when the node registers.
+	AddressResyncPeriod string `gcfg:"address-resync-period"`
+	// How long a node whose VM was discovered before is still reported as
+	// existing while its VM cannot be found, so that the inventory lag after
+	// a cross vCenter vMotion or a re-registration does not delete the node,
+	// as a Go duration such as "2m". Unset reports it as deleted right away.
+	VMNotFoundGracePeriod string `gcfg:"vm-not-found-grace-period"`
+	// HTTPS URL of a webhook receiving the address candidates of each
+	// discovered node, which may reorder or filter the addresses published.
+	AddressWebhookURL string `gcfg:"address-webhook-url"`
+	// Timeout of a call to the address webhook, as a Go duration such as
+	// "5s". Defaults to 10s.
+	AddressWebhookTimeout string `gcfg:"address-webhook-timeout"`
+	// What happens when the address webhook cannot be called or returns an
+	// invalid response: "ignore" (default) publishes the discovered
+	// addresses, "fail" fails the discovery of the node.
+	AddressWebhookFailurePolicy string `gcfg:"address-webhook-failure-policy"`
+	// CA bundle verifying the certificate of the address webhook. The system
+	// roots are used if unset.
+	AddressWebhookCAFile string `gcfg:"address-webhook-ca-file"`
+	// File holding the bearer token sent to the address webhook, read before
+	// each call so that it can be rotated.
+	AddressWebhookTokenFile string `gcfg:"address-webhook-token-file"`
+	// Apply the vSphere custom attributes of the node's VM as node labels,
+	// named VMLabelPrefix followed by the attribute name.
+	VMLabelCustomAttributes bool `gcfg:"vm-label-custom-attributes"`
+	// Category of the vSphere tags of the node's VM applied as node labels:
+	// a tag named "key=value" becomes the label VMLabelPrefix + key with
+	// that value, any other tag a label named after it with the value "true".
+	VMLabelTagCategory string `gcfg:"vm-label-tag-category"`
+	// Prefix of the node labels applied from the custom attributes and tags,
+	// labels with this prefix the VM no longer has are removed. Defaults to
+	// "vm.vsphere.vmware.com/".
+	VMLabelPrefix string `gcfg:"vm-label-prefix"`
+	// Period at which the labels applied from the custom attributes and tags
+	// are read again from the VMs, as a Go duration such as "5m". Defaults
+	// to 10m.
+	VMLabelResyncPeriod string `gcfg:"vm-label-resync-period"`
+	// Report the ESXi host, the compute cluster and the resource pool of the
+	// node's VM as node labels through InstanceMetadata, which is only used
+	// with --vsphere-instances-v2.
+	EnablePlacementLabels bool `gcfg:"enable-placement-labels"`
+	// Comma separated order in which the VM of a node looked up by name is
+	// searched: "name" by its DNS name, "ip" by its IP address. Defaults to
+	// "name,ip", set it to "name" where IP addresses overlap.
+	VMLookupOrder string `gcfg:"vm-lookup-order"`
+	// Discover a node looked up by name only if the UUID of the VM found
+	// matches the SystemUUID of the node, or its VM UUID annotation.
+	RequireUUIDMatch bool `gcfg:"require-uuid-match"`
+	// Label of the nodes holding the name of their node pool, whose VMs are
+	// searched first in the vCenter NodePoolVCenters maps the pool to.
+	NodePoolLabel string `gcfg:"node-pool-label"`
+	// vCenter the VMs of the nodes of each node pool are searched in first,
+	// as a comma separated list of pool=vcenter or pool=vcenter/datacenter.
+	NodePoolVCenters string `gcfg:"node-pool-vcenters"`
+	// Maximum size in bytes of the decoded, and decompressed, guestinfo
+	// metadata of a VM, whose statically configured addresses are preferred.
+	// Larger metadata fails the discovery of the node. Defaults to 1 MiB.
+	MaxGuestInfoSize int64 `gcfg:"max-guestinfo-size"`
+}
+
+// LoggingINI captures the verbosity overrides of the logging modules
+type LoggingINI struct {
+	// Verbosity of the modules overriding the klog -v flag, as a comma
+	// separated list of module=level, such as "loadbalancer=6,nodemanager=2".
+	ModuleVerbosity string `gcfg:"module-verbosity"`
 }
 
 // CPIConfigINI is the INI representation
 type CPIConfigINI struct {
 	vcfg.CommonConfigINI
-	Nodes NodesINI
+	Nodes   NodesINI
+	Logging LoggingINI
 }
@@ -41,15 +41,124 @@ type NodesYAML struct {
 	// only have a single IP address assigned to it.
 	InternalVMNetworkName string `yaml:"internalVmNetworkName"`
 	ExternalVMNetworkName string `yaml:"externalVmNetworkName"`
+	// Regular expressions matched against the VirtualMachine's VM Network names,
+	// in addition to InternalVMNetworkName and ExternalVMNetworkName.
+	InternalVMNetworkNamePattern string `yaml:"internalVmNetworkNamePattern"`
+	ExternalVMNetworkNamePattern string `yaml:"externalVmNetworkNamePattern"`
 	// IP addresses in these subnet ranges will be excluded when selecting
 	// the IP address from the VirtualMachine's VM for use in the
 	// status.addresses fields.
 	ExcludeInternalNetworkSubnetCIDR string `yaml:"excludeInternalNetworkSubnetCidr"`
 	ExcludeExternalNetworkSubnetCIDR string `yaml:"excludeExternalNetworkSubnetCidr"`
+	// Source of node addresses in addition to VMware Tools: with "merge" the
+	// addresses NSX-T realized on the VM's segment ports are added after
+	// the ones reported by the guest, with "prefer" they take precedence.
+	// Requires the NSXT section.
+	NSXAddressSource string `yaml:"nsxAddressSource"`
+	// How long the instance type of a node, derived from the VM's CPUs,
+	// memory and guest OS, is cached before the VM is read again, as a Go
+	// duration such as "10m". Unset caches it until restart.
+	InstanceTypeTTL string `yaml:"instanceTypeTtl"`
+	// Write refreshed instance types to the node's instance type labels,
+	// which are otherwise only set when the node is initialized.
+	UpdateInstanceTypeLabels bool `yaml:"updateInstanceTypeLabels"`
+	// Key of the VM's extraConfig holding the name of the template the VM
+	// was built from, published as the node's source template annotation.
+	// Defaults to "guestinfo.source-template".
+	SourceTemplateKey string `yaml:"sourceTemplateKey"`
+	// Publish the name of the Kubernetes node as its Hostname address
+	// instead of the hostname reported by VMware Tools, which may differ.
+	UseNodeNameAsHostname bool `yaml:"useNodeNameAsHostname"`
+	// Discover nodes by UUID even if VMware Tools does not report a guest
+	// hostname. Without UseNodeNameAsHostname, no Hostname address is
+	// published for them.
+	AllowEmptyGuestHostname bool `yaml:"allowEmptyGuestHostname"`
+	// Publish the hostname reported by VMware Tools as the Hostname address of
+	// the node. Defaults to true, disable it when a guest hostname differing
+	// from the node name breaks the certificates of the kubelet or webhooks.
+	PublishGuestHostname *bool `yaml:"publishGuestHostname"`
+	// Publish only the InternalIP and Hostname addresses of the nodes, never
+	// an ExternalIP, regardless of the external network settings and the
+	// external IP annotation.
+	SuppressExternalIP bool `yaml:"suppressExternalIP"`
+	// Publish every address matching the internal and external network
+	// settings, rather than only the most preferred one of each type and IP
+	// family.
+	PublishAllMatchingIPs bool `yaml:"publishAllMatchingIPs"`
+	// Period at which the addresses of the registered nodes are discovered
+	// again and published in the node's status, so that IP changes after a
+	// vMotion or a DHCP lease renewal are picked up, as a Go duration such as
+	// "5m". Unset discovers them only when the node registers.
+	AddressResyncPeriod string `yaml:"addressResyncPeriod"`
+	// How long a node whose VM was discovered before is still reported as
+	// existing while its VM cannot be found, so that the inventory lag after
+	// a cross vCenter vMotion or a re-registration does not delete the node,
+	// as a Go duration such as "2m". Unset reports it as deleted right away.
+	VMNotFoundGracePeriod string `yaml:"vmNotFoundGracePeriod"`
+	// HTTPS URL of a webhook receiving the address candidates of each
+	// discovered node, which may reorder or filter the addresses published.
+	AddressWebhookURL string `yaml:"addressWebhookURL"`
+	// Timeout of a call to the address webhook, as a Go duration such as
+	// "5s". Defaults to 10s.
+	AddressWebhookTimeout string `yaml:"addressWebhookTimeout"`
+	// What happens when the address webhook cannot be called or returns an
+	// invalid response: "ignore" (default) publishes the discovered
+	// addresses, "fail" fails the discovery of the node.
+	AddressWebhookFailurePolicy string `yaml:"addressWebhookFailurePolicy"`
+	// CA bundle verifying the certificate of the address webhook. The system
+	// roots are used if unset.
+	AddressWebhookCAFile string `yaml:"addressWebhookCAFile"`
+	// File holding the bearer token sent to the address webhook, read before
+	// each call so that it can be rotated.
+	AddressWebhookTokenFile string `yaml:"addressWebhookTokenFile"`
+	// Apply the vSphere custom attributes of the node's VM as node labels,
+	// named VMLabelPrefix followed by the attribute name.
+	VMLabelCustomAttributes bool `yaml:"vmLabelCustomAttributes"`
+	// Category of the vSphere tags of the node's VM applied as node labels:
+	// a tag named "key=value" becomes the label VMLabelPrefix + key with
+	// that value, any other tag a label named after it with the value "true".
+	VMLabelTagCategory string `yaml:"vmLabelTagCategory"`
+	// Prefix of the node labels applied from the custom attributes and tags,
+	// labels with this prefix the VM no longer has are removed. Defaults to
+	// "vm.vsphere.vmware.com/".
+	VMLabelPrefix string `yaml:"vmLabelPrefix"`
+	// Period at which the labels applied from the custom attributes and tags
+	// are read again from the VMs, as a Go duration such as "5m". Defaults
+	// to 10m.
+	VMLabelResyncPeriod string `yaml:"vmLabelResyncPeriod"`
+	// Report the ESXi host, the compute cluster and the resource pool of the
+	// node's VM as node labels through InstanceMetadata, which is only used
+	// with --vsphere-instances-v2.
+	EnablePlacementLabels bool `yaml:"enablePlacementLabels"`
+	// Comma separated order in which the VM of a node looked up by name is
+	// searched: "name" by its DNS name, "ip" by its IP address. Defaults to
+	// "name,ip", set it to "name" where IP addresses overlap.
+	VMLookupOrder string `yaml:"vmLookupOrder"`
+	// Discover a node looked up by name only if the UUID of the VM found
+	// matches the SystemUUID of the node, or its VM UUID annotation.
+	RequireUUIDMatch bool `yaml:"requireUUIDMatch"`
+	// Label of the nodes holding the name of their node pool, whose VMs are
+	// searched first in the vCenter NodePoolVCenters maps the pool to.
+	NodePoolLabel string `yaml:"nodePoolLabel"`
+	// vCenter, written as vcenter or vcenter/datacenter, the VMs of the nodes
+	// of each node pool are searched in first, before the other vCenters.
+	NodePoolVCenters map[string]string `yaml:"nodePoolVCenters"`
+	// Maximum size in bytes of the decoded, and decompressed, guestinfo
+	// metadata of a VM, whose statically configured addresses are preferred.
+	// Larger metadata fails the discovery of the node. Defaults to 1 MiB.
+	MaxGuestInfoSize int64 `yaml:"maxGuestInfoSize"`
+}
+
+// LoggingYAML captures the verbosity overrides of the logging modules
+type LoggingYAML struct {
+	// Verbosity of the modules (nodemanager, loadbalancer, paravirtual and
+	// connectionmanager) overriding the klog -v flag.
+	ModuleVerbosity map[string]int `yaml:"moduleVerbosity"`
 }
 
 // CPIConfigYAML is the YAML representation
 type CPIConfigYAML struct {
 	vcfg.CommonConfigYAML
-	Nodes NodesYAML
+	Nodes   NodesYAML
+	Logging LoggingYAML
 }
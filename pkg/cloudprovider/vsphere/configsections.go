@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// iniSectionPattern matches the name of the INI section headers.
+var iniSectionPattern = regexp.MustCompile(`(?m)^\s*\[\s*([A-Za-z]+)`)
+
+// hasConfigSection returns whether the cloud config has the top-level YAML
+// key or the INI section name, compared case-insensitively.
+func hasConfigSection(byConfig []byte, name string) bool {
+	var yamlConfig map[string]interface{}
+	if err := yaml.Unmarshal(byConfig, &yamlConfig); err == nil {
+		for key := range yamlConfig {
+			if strings.EqualFold(key, name) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, match := range iniSectionPattern.FindAllSubmatch(byConfig, -1) {
+		if strings.EqualFold(string(match[1]), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreConfigSection appends the section and the error it could not be read
+// with to ignored, unless the cloud config does not have the section. The
+// readers of the optional sections fail on configs without them.
+func ignoreConfigSection(ignored []string, byConfig []byte, name string, err error) []string {
+	if !hasConfigSection(byConfig, name) {
+		return ignored
+	}
+	return append(ignored, fmt.Sprintf("%s: %v", name, err))
+}
+
+// IgnoredConfigSections returns the optional sections of the cloud config,
+// such as the load balancer, that are configured but could not be read, each
+// with its error. The cloud provider runs without them.
+func (vs *VSphere) IgnoredConfigSections() []string {
+	return vs.ignoredConfigSections
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIgnoreConfigSection(t *testing.T) {
+	yamlConfig := []byte(`
+global:
+  user: user
+vcenter:
+  vc:
+    server: 10.0.0.1
+loadBalancer:
+  ipPoolName: pool
+`)
+	iniConfig := []byte(`
+[Global]
+user = user
+
+[ LoadBalancer ]
+ipPoolName = pool
+`)
+	err := errors.New("invalid")
+	for name, byConfig := range map[string][]byte{"yaml": yamlConfig, "ini": iniConfig} {
+		ignored := ignoreConfigSection(nil, byConfig, "loadBalancer", err)
+		if len(ignored) != 1 || ignored[0] != "loadBalancer: invalid" {
+			t.Errorf("%s: configured section should be ignored, got %v", name, ignored)
+		}
+		if ignored := ignoreConfigSection(nil, byConfig, "nsxt", err); len(ignored) != 0 {
+			t.Errorf("%s: missing section should not be ignored, got %v", name, ignored)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/debugdump"
+)
+
+// nodeState is a discovered node as reported in debug dumps and by the
+// secure API.
+type nodeState struct {
+	NodeName   string           `json:"nodeName"`
+	UUID       string           `json:"uuid"`
+	VCenter    string           `json:"vcenter"`
+	TenantRef  string           `json:"tenantRef"`
+	Datacenter string           `json:"datacenter"`
+	NodeType   string           `json:"nodeType"`
+	Addresses  []v1.NodeAddress `json:"addresses"`
+}
+
+func newNodeState(node *NodeInfo) nodeState {
+	s := nodeState{
+		NodeName:  node.NodeName,
+		UUID:      node.UUID,
+		VCenter:   node.vcServer,
+		TenantRef: node.tenantRef,
+		NodeType:  node.NodeType,
+		Addresses: node.NodeAddresses,
+	}
+	if node.dataCenter != nil {
+		s.Datacenter = node.dataCenter.Name()
+	}
+	return s
+}
+
+// nodeManagerState is the content of the node manager caches as reported
+// in debug dumps. A cache whose lock is held is reported busy instead, as
+// the dump may be taken while a discovery hangs.
+type nodeManagerState struct {
+	Nodes           []nodeState `json:"nodes"`
+	NodesBusy       bool        `json:"nodesBusy"`
+	RegisteredNodes []string    `json:"registeredNodes"`
+	RegisteredBusy  bool        `json:"registeredBusy"`
+}
+
+func (nm *NodeManager) debugState() nodeManagerState {
+	state := nodeManagerState{}
+
+	if nm.nodeInfoLock.TryRLock() {
+		for _, node := range nm.nodeNameMap {
+			state.Nodes = append(state.Nodes, newNodeState(node))
+		}
+		nm.nodeInfoLock.RUnlock()
+		sort.Slice(state.Nodes, func(i, j int) bool {
+			return state.Nodes[i].NodeName < state.Nodes[j].NodeName
+		})
+	} else {
+		state.NodesBusy = true
+	}
+
+	if nm.nodeRegInfoLock.TryRLock() {
+		for _, node := range nm.nodeRegUUIDMap {
+			state.RegisteredNodes = append(state.RegisteredNodes, node.Name)
+		}
+		nm.nodeRegInfoLock.RUnlock()
+		sort.Strings(state.RegisteredNodes)
+	} else {
+		state.RegisteredBusy = true
+	}
+
+	return state
+}
+
+// registerDebugSources adds the effective config, the node manager caches,
+// the vCenter connection states and the pending load balancer reconciles to
+// the debug dump.
+func (vs *VSphere) registerDebugSources() {
+	if vs.effectiveConfig != nil {
+		debugdump.Register("vsphere.effectiveConfig", func() interface{} {
+			return vs.effectiveConfig
+		})
+	}
+	debugdump.Register("vsphere.nodeManager", func() interface{} {
+		return vs.nodeManager.debugState()
+	})
+	if vs.connectionManager != nil {
+		debugdump.Register("vsphere.connections", func() interface{} {
+			return vs.connectionManager.ConnectionStates()
+		})
+	}
+	if vs.isLoadBalancerSupportEnabled() {
+		debugdump.Register("vsphere.loadBalancer.pendingReconciles", func() interface{} {
+			return vs.loadbalancer.PendingReconciles()
+		})
+	}
+}
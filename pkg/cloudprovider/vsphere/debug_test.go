@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeManagerDebugState(t *testing.T) {
+	nm := newNodeManager(nil, nil)
+	nm.nodeNameMap["node-b"] = &NodeInfo{NodeName: "node-b", UUID: "uuid-b", vcServer: "vc"}
+	nm.nodeNameMap["node-a"] = &NodeInfo{NodeName: "node-a", UUID: "uuid-a", vcServer: "vc"}
+	nm.addNode("uuid-a", &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+
+	state := nm.debugState()
+	if len(state.Nodes) != 2 || state.Nodes[0].NodeName != "node-a" || state.Nodes[1].UUID != "uuid-b" {
+		t.Errorf("Unexpected nodes %+v", state.Nodes)
+	}
+	if len(state.RegisteredNodes) != 1 || state.RegisteredNodes[0] != "node-a" {
+		t.Errorf("Unexpected registered nodes %v", state.RegisteredNodes)
+	}
+
+	// a discovery holding the lock must not block the dump
+	nm.nodeInfoLock.Lock()
+	state = nm.debugState()
+	nm.nodeInfoLock.Unlock()
+	if !state.NodesBusy || state.Nodes != nil {
+		t.Errorf("Nodes should be reported busy, got %+v", state)
+	}
+	if state.RegisteredBusy {
+		t.Error("Registered nodes should not be reported busy")
+	}
+}
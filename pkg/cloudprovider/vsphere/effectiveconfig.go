@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	lcfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+	rcfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/route/config"
+	ncfg "k8s.io/cloud-provider-vsphere/pkg/nsxt/config"
+)
+
+// EffectiveConfig is the configuration of the cloud provider once the
+// VSPHERE_* environment variables and the defaults are applied, with its
+// secrets redacted. A section that is not configured is omitted.
+type EffectiveConfig struct {
+	CPI          *ccfg.CPIConfig `json:"cpi"`
+	NSXT         *ncfg.Config    `json:"nsxt,omitempty"`
+	LoadBalancer *lcfg.LBConfig  `json:"loadBalancer,omitempty"`
+	Route        *rcfg.Config    `json:"route,omitempty"`
+}
+
+func newEffectiveConfig(cfg *ccfg.CPIConfig, nsxtcfg *ncfg.Config, lbcfg *lcfg.LBConfig, routecfg *rcfg.Config) *EffectiveConfig {
+	ec := &EffectiveConfig{
+		CPI:   cfg.Redacted(),
+		Route: routecfg,
+	}
+	if nsxtcfg != nil {
+		ec.NSXT = nsxtcfg.Redacted()
+	}
+	if lbcfg != nil {
+		ec.LoadBalancer = lbcfg.Redacted()
+	}
+	return ec
+}
+
+// ReadEffectiveConfig reads the cloud config as the cloud provider does when
+// it is initialized, and returns its effective config.
+func ReadEffectiveConfig(byConfig []byte) (*EffectiveConfig, error) {
+	cfg, err := ccfg.ReadCPIConfig(byConfig)
+	if err != nil {
+		return nil, err
+	}
+	nsxtcfg, err := ncfg.ReadNsxtConfig(byConfig)
+	if err != nil {
+		nsxtcfg = nil
+	}
+	lbcfg, err := lcfg.ReadLBConfig(byConfig)
+	if err != nil {
+		lbcfg = nil
+	}
+	routecfg, err := rcfg.ReadRouteConfig(byConfig)
+	if err != nil {
+		routecfg = nil
+	}
+	return newEffectiveConfig(cfg, nsxtcfg, lbcfg, routecfg), nil
+}
+
+// DumpEffectiveConfig writes the effective config of the cloud config as
+// YAML to w.
+func DumpEffectiveConfig(w io.Writer, byConfig []byte) error {
+	ec, err := ReadEffectiveConfig(byConfig)
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(ec)
+	if err != nil {
+		return fmt.Errorf("failed to encode the effective config: %v", err)
+	}
+	_, err = w.Write(b)
+	return err
+}
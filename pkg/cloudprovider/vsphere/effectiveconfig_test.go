@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDumpEffectiveConfig(t *testing.T) {
+	t.Setenv("VSPHERE_NODES_NSX_ADDRESS_SOURCE", "prefer")
+	config := []byte(`
+global:
+  server: 10.0.0.1
+  user: user
+  password: global-secret
+  datacenters:
+    - dc0
+
+vcenter:
+  tenant1:
+    server: 10.0.0.2
+    user: user
+    password: tenant1-secret
+    datacenters:
+      - dc1
+
+nsxt:
+  host: nsxt.local
+  user: admin
+  password: nsxt-secret
+`)
+
+	var out bytes.Buffer
+	if err := DumpEffectiveConfig(&out, config); err != nil {
+		t.Fatal(err)
+	}
+	dump := out.String()
+	for _, secret := range []string{"global-secret", "tenant1-secret", "nsxt-secret"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("secret %s should be redacted:\n%s", secret, dump)
+		}
+	}
+	if strings.Count(dump, "<redacted>") != 4 {
+		t.Errorf("passwords of Global, both vCenters and NSX-T should be redacted:\n%s", dump)
+	}
+	if !strings.Contains(dump, "NSXAddressSource: prefer") {
+		t.Errorf("environment overrides should be applied:\n%s", dump)
+	}
+
+	// the effective config registered in the debug dump is redacted as well
+	ec, err := ReadEffectiveConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ec.CPI.VirtualCenter["tenant1"].Password != "<redacted>" || ec.NSXT.Password != "<redacted>" {
+		t.Errorf("effective config should be redacted, got %+v", ec)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// Reasons of the rejections of guestinfo metadata, used as label of
+// guestInfoRejectionsMetric
+const (
+	guestInfoTooLarge  = "too_large"
+	guestInfoMalformed = "malformed"
+)
+
+// errGuestInfoTooLarge is returned by decodeGuestInfo for data larger than
+// allowed once decoded.
+var errGuestInfoTooLarge = errors.New("guestinfo metadata too large")
+
+// guestInfoRejectionsMetric counts the guestinfo metadata of VMs which could
+// not be decoded, as the metadata is set from within the guest.
+var guestInfoRejectionsMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "guestinfo_metadata_rejections",
+		Help: "Guestinfo metadata of VMs rejected as too large or malformed",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(guestInfoRejectionsMetric)
+}
+
+// GuestInfoError is returned when the guestinfo metadata of the VM of a node
+// cannot be decoded.
+type GuestInfoError struct {
+	// NodeName is the name of the node
+	NodeName string
+	// VM is the managed object ID of the VM
+	VM string
+	// VCenter is the vCenter of the VM
+	VCenter string
+	// Err is the decoding error
+	Err error
+}
+
+func (e *GuestInfoError) Error() string {
+	return fmt.Sprintf("invalid guestinfo metadata of vm=%s in vc=%s for node %s: %v", e.VM, e.VCenter, e.NodeName, e.Err)
+}
+
+func (e *GuestInfoError) Unwrap() error {
+	return e.Err
+}
+
+// newGuestInfoError returns the GuestInfoError of the VM of the node, and
+// counts the rejected metadata.
+func newGuestInfoError(nodeName string, vmDI *cm.VMDiscoveryInfo, err error) *GuestInfoError {
+	reason := guestInfoMalformed
+	if errors.Is(err, errGuestInfoTooLarge) {
+		reason = guestInfoTooLarge
+	}
+	guestInfoRejectionsMetric.WithLabelValues(reason).Inc()
+
+	guestInfoErr := &GuestInfoError{NodeName: nodeName, VCenter: vmDI.VcServer, Err: err}
+	if vmDI.VM != nil {
+		guestInfoErr.VM = vmDI.VM.Reference().Value
+	}
+	return guestInfoErr
+}
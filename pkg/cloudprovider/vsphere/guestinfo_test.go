@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestDecodeGuestInfoMaxSize(t *testing.T) {
+	const maxSize = 1024
+	gzipBase64 := func(data []byte) string {
+		buf := bytes.NewBuffer(nil)
+		gw := gzip.NewWriter(buf)
+		if _, err := gw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := gw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	testcases := []struct {
+		name     string
+		data     string
+		encoding string
+		tooLarge bool
+	}{
+		{name: "raw", data: strings.Repeat("a", maxSize), encoding: ""},
+		{name: "raw too large", data: strings.Repeat("a", maxSize+1), encoding: "", tooLarge: true},
+		{name: "base64 too large", data: base64.StdEncoding.EncodeToString(make([]byte, maxSize+1)), encoding: "base64", tooLarge: true},
+		{name: "gzip", data: gzipBase64(make([]byte, maxSize)), encoding: "gzip+base64"},
+		// a few KiB of compressed zeros decompress to 16 MiB
+		{name: "gzip bomb", data: gzipBase64(make([]byte, 16<<20)), encoding: "gz+b64", tooLarge: true},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			value, err := decodeGuestInfo(testcase.data, testcase.encoding, maxSize)
+			if testcase.tooLarge {
+				if !errors.Is(err, errGuestInfoTooLarge) {
+					t.Errorf("expected the data to be too large, got %v", err)
+				}
+				return
+			}
+			if err != nil || len(value) != maxSize {
+				t.Errorf("expected %d bytes, got %d bytes and %v", maxSize, len(value), err)
+			}
+		})
+	}
+}
+
+func TestSortStaticallyConfiguredAddressesFirstMaxSize(t *testing.T) {
+	extraConfig := []vimtypes.BaseOptionValue{
+		&vimtypes.OptionValue{Key: "guestinfo.metadata", Value: guestInfoWithAddresses("192.168.1.20/24")},
+	}
+	ips := []*ipAddrNetworkName{{ipAddr: "192.168.1.10", networkName: "VM Network"}}
+	if _, err := sortStaticallyConfiguredAddressesFirst(extraConfig, ips, 16); !errors.Is(err, errGuestInfoTooLarge) {
+		t.Errorf("expected the metadata to be too large, got %v", err)
+	}
+}
+
+func TestNewGuestInfoError(t *testing.T) {
+	vmDI := &cm.VMDiscoveryInfo{VcServer: "vc1"}
+	tooLarge := testutil.ToFloat64(guestInfoRejectionsMetric.WithLabelValues(guestInfoTooLarge))
+	malformed := testutil.ToFloat64(guestInfoRejectionsMetric.WithLabelValues(guestInfoMalformed))
+
+	err := newGuestInfoError("node1", vmDI, errGuestInfoTooLarge)
+	if !errors.Is(err, errGuestInfoTooLarge) || err.NodeName != "node1" || err.VCenter != "vc1" {
+		t.Errorf("unexpected error %#v", err)
+	}
+	if !strings.Contains(err.Error(), "node1") {
+		t.Errorf("expected the node in the error, got %q", err.Error())
+	}
+	newGuestInfoError("node1", vmDI, base64.CorruptInputError(0))
+
+	if count := testutil.ToFloat64(guestInfoRejectionsMetric.WithLabelValues(guestInfoTooLarge)) - tooLarge; count != 1 {
+		t.Errorf("expected 1 too large rejection, got %v", count)
+	}
+	if count := testutil.ToFloat64(guestInfoRejectionsMetric.WithLabelValues(guestInfoMalformed)) - malformed; count != 1 {
+		t.Errorf("expected 1 malformed rejection, got %v", count)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/health"
+)
+
+// registerHealthChecks adds a check of the session of each vCenter of the
+// cloud config, unless the profile does not discover nodes, and a check of
+// NSX-T if the load balancer support is enabled to the health endpoints.
+func (vs *VSphere) registerHealthChecks() {
+	if vs.connectionManager != nil && vs.profile.runsNodes() {
+		for tenantRef, vsi := range vs.connectionManager.Instances() {
+			// the vCenters of the tenant configs come and go at runtime
+			if vs.connectionManager.IsTenantVCenter(tenantRef) {
+				continue
+			}
+			health.Register("vcenter-"+tenantRef, vcenterSessionCheck(vs.connectionManager, vsi))
+		}
+	}
+	if vs.isLoadBalancerSupportEnabled() {
+		health.Register("nsxt", vs.loadbalancer.CheckHealth)
+	}
+}
+
+// vcenterSessionCheck returns a check of the session of the vCenter, which
+// logs in again if the session is no longer valid.
+func vcenterSessionCheck(connMgr *cm.ConnectionManager, vsi *cm.VSphereInstance) health.Check {
+	return func(ctx context.Context) error {
+		return connMgr.Connect(ctx, vsi)
+	}
+}
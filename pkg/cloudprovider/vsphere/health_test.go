@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/health"
+)
+
+func TestRegisterHealthChecks(t *testing.T) {
+	initCfg, cleanup := configFromEnvOrSim(false)
+	cfg := &ccfg.CPIConfig{}
+	cfg.Config = *initCfg
+
+	vs, err := newVSphere(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct vSphere: %s", err)
+	}
+	vs.connectionManager = cm.NewConnectionManager(&cfg.Config, nil, nil)
+	defer vs.connectionManager.Logout()
+	vs.registerHealthChecks()
+
+	readyz := func() (int, string) {
+		recorder := httptest.NewRecorder()
+		health.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz?verbose", nil))
+		return recorder.Code, recorder.Body.String()
+	}
+	check := "vcenter-" + cfg.Global.VCenterIP
+	if code, body := readyz(); code != http.StatusOK || !strings.Contains(body, "[+]"+check+" ok") {
+		t.Errorf("readyz should pass with the vCenter session, got %d %q", code, body)
+	}
+
+	// the vCenter goes away
+	cleanup()
+	vs.connectionManager.VsphereInstanceMap[cfg.Global.VCenterIP].Conn.Client = nil
+	if code, body := readyz(); code != http.StatusInternalServerError || !strings.Contains(body, "[-]"+check+" failed") {
+		t.Errorf("readyz should fail without vCenter, got %d %q", code, body)
+	}
+}
@@ -121,7 +121,7 @@ func (i *instances) InstanceID(ctx context.Context, nodeName types.NodeName) (st
 func (i *instances) InstanceType(ctx context.Context, name types.NodeName) (string, error) {
 	klog.V(4).Info("instances.InstanceType() called")
 	if nodeInfo, ok := i.nodeManager.nodeNameMap[string(name)]; ok {
-		return nodeInfo.NodeType, nil
+		return i.nodeManager.instanceType(ctx, nodeInfo), nil
 	}
 	return "", fmt.Errorf("cannot find node with nodeName %s in nodeNameMap", name)
 }
@@ -131,7 +131,7 @@ func (i *instances) InstanceTypeByProviderID(ctx context.Context, providerID str
 	klog.V(4).Info("instances.InstanceTypeByProviderID() called")
 	uid := GetUUIDFromProviderID(providerID)
 	if nodeInfo, ok := i.nodeManager.nodeUUIDMap[uid]; ok {
-		return nodeInfo.NodeType, nil
+		return i.nodeManager.instanceType(ctx, nodeInfo), nil
 	}
 	return "", fmt.Errorf("cannot find node with providerID %s in nodeUUIDMap", providerID)
 }
@@ -186,9 +186,16 @@ func (i *instances) InstanceExistsByProviderID(ctx context.Context, providerID s
 
 	if exist {
 		klog.V(2).Infof("instances.InstanceExistsByProviderID() found node uid '%q' by using vm-id '%q'", uid, i.nodeManager.nodeUUIDMap[uid].vm.Reference())
+		i.nodeManager.resetVMNotFound(uid)
 		return true, nil
 	}
 
+	// the inventory may not have caught up with a vMotion yet
+	if err := i.nodeManager.checkVMNotFoundGracePeriod(uid); err != nil {
+		klog.V(2).Infof("instances.InstanceExistsByProviderID() NOT FOUND with %q, not signaling deletion yet: %v", uid, err)
+		return false, err
+	}
+
 	klog.V(4).Info("instances.InstanceExistsByProviderID() NOT FOUND with ", uid, ". Signaling deletion.")
 	return false, nil
 }
@@ -18,9 +18,12 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator"
 	vimtypes "github.com/vmware/govmomi/vim25/types"
 
@@ -203,3 +206,75 @@ func TestInvalidInstance(t *testing.T) {
 		t.Error("InstanceExistsByProviderID excepted not exists")
 	}
 }
+
+func TestInstanceVMNotFoundGracePeriod(t *testing.T) {
+	cfg, ok := configFromEnvOrSim(true)
+	defer ok()
+
+	ctx := context.Background()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+	nm := newMyNodeManager(connMgr)
+	nm.vmNotFoundGracePeriod = time.Hour
+	instances := newInstances(&nm.NodeManager)
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	name := strings.ToLower(vm.Name)
+	vm.Guest.HostName = name
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+	UUID := strings.ToUpper(vm.Config.Uuid)
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: ConvertK8sUUIDtoNormal(UUID)},
+		},
+	}
+	nm.RegisterNode(node)
+	providerID := ProviderPrefix + UUID
+	uid := GetUUIDFromProviderID(providerID)
+
+	// the VM disappears from the inventory, as during a cross vCenter vMotion
+	vsi := connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]
+	obj := object.NewVirtualMachine(vsi.Conn.Client, vm.Reference())
+	task, err := obj.PowerOff(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := task.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	task, err = obj.Destroy(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := task.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	exists, err := instances.InstanceExistsByProviderID(ctx, providerID)
+	if !errors.Is(err, ErrVMNotFoundWithinGracePeriod) {
+		t.Errorf("InstanceExistsByProviderID should fail within the grace period, err=%v", err)
+	}
+	if exists {
+		t.Error("InstanceExistsByProviderID should not report the VM as existing")
+	}
+
+	// nodes that were never discovered are not given a grace period
+	exists, err = instances.InstanceExistsByProviderID(ctx, ProviderPrefix+"423AE7DC-A9D5-4E11-9DC4-1D3A7E8F0B2C")
+	if err != nil || exists {
+		t.Errorf("InstanceExistsByProviderID of an unknown node should be false, actual=%t err=%v", exists, err)
+	}
+
+	// the node is reported as deleted once the grace period elapsed
+	nm.vmNotFoundSince[uid] = time.Now().Add(-2 * time.Hour)
+	exists, err = instances.InstanceExistsByProviderID(ctx, providerID)
+	if err != nil || exists {
+		t.Errorf("InstanceExistsByProviderID after the grace period should be false, actual=%t err=%v", exists, err)
+	}
+}
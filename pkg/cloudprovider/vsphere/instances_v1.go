@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"errors"
+	"flag"
+
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+// The v1 cloudprovider.Instances interface is deprecated by the cloud-provider
+// library in favor of InstancesV2. The vSphere cloud provider still serves it
+// for the cloud-node controllers and for the projects embedding the provider,
+// through the adapter below, so that they can migrate gradually.
+//
+// Instances() only returns the adapter while --vsphere-instances-v1 is set,
+// which is the default. Projects embedding the provider that serve the
+// instances of their nodes themselves can disable it and still call the
+// adapter explicitly through NewInstancesV1.
+
+// ErrNotVSphere is returned by NewInstancesV1 when the cloud provider is not
+// the vSphere one.
+var ErrNotVSphere = errors.New("cloud provider is not the vSphere cloud provider")
+
+// instancesV1Enabled tells whether Instances() serves the v1 adapter.
+var instancesV1Enabled = true
+
+func init() {
+	flag.BoolVar(&instancesV1Enabled, "vsphere-instances-v1", true, "Serve the deprecated v1 Instances interface of the vSphere cloud provider. Disable it only if the instances of the nodes are served by an embedding project.")
+}
+
+// NewInstancesV1 returns the v1 cloudprovider.Instances adapter of a vSphere
+// cloud provider, as returned by cloudprovider.InitCloudProvider. The adapter
+// is backed by the node manager of the provider, so that the nodes it
+// discovers are shared with the other interfaces. It is available whether or
+// not --vsphere-instances-v1 is set.
+func NewInstancesV1(cloud cloudprovider.Interface) (cloudprovider.Instances, error) {
+	vs, ok := cloud.(*VSphere)
+	if !ok || vs == nil || vs.nodeManager == nil {
+		return nil, ErrNotVSphere
+	}
+	return newInstances(vs.nodeManager), nil
+}
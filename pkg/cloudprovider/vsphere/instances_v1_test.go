@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cloudprovider "k8s.io/cloud-provider"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestNewInstancesV1(t *testing.T) {
+	initCfg, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+	cfg := &ccfg.CPIConfig{}
+	cfg.Config = *initCfg
+
+	vs, err := newVSphere(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct vSphere: %s", err)
+	}
+	vs.connectionManager = cm.NewConnectionManager(&cfg.Config, nil, nil)
+	defer vs.connectionManager.Logout()
+	vs.nodeManager.connectionManager = vs.connectionManager
+
+	instances, err := NewInstancesV1(vs)
+	if err != nil {
+		t.Fatalf("NewInstancesV1 failed err=%v", err)
+	}
+
+	// nodes registered with the provider are known to the adapter
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	name := strings.ToLower(vm.Name)
+	vm.Guest.HostName = name
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+	UUID := strings.ToUpper(vm.Config.Uuid)
+	vs.nodeAdded(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: ConvertK8sUUIDtoNormal(UUID)},
+		},
+	})
+
+	myUUID, err := instances.InstanceID(context.Background(), types.NodeName(name))
+	if err != nil {
+		t.Errorf("InstanceID failed err=%v", err)
+	}
+	if !strings.EqualFold(myUUID, UUID) {
+		t.Errorf("InstanceID mismatch %s != %s", myUUID, UUID)
+	}
+
+	// the adapter is available while Instances() is disabled
+	instancesV1Enabled = false
+	defer func() { instancesV1Enabled = true }()
+	if _, ok := vs.Instances(); ok {
+		t.Error("Instances should not be supported when disabled")
+	}
+	if _, err := NewInstancesV1(vs); err != nil {
+		t.Errorf("NewInstancesV1 failed err=%v", err)
+	}
+
+	var other cloudprovider.Interface
+	if _, err := NewInstancesV1(other); !errors.Is(err, ErrNotVSphere) {
+		t.Errorf("expected ErrNotVSphere, but got %v", err)
+	}
+}
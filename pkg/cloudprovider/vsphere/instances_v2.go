@@ -0,0 +1,179 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	v1 "k8s.io/api/core/v1"
+	cloudprovider "k8s.io/cloud-provider"
+	klog "k8s.io/klog/v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+// The cloud-node controllers use the InstancesV2 interface instead of the
+// Instances and Zones interfaces when the cloud provider serves it. Its
+// InstanceMetadata resolves the zone and the region of the nodes from the
+// vSphere tags directly, without going through the Zones interface.
+//
+// InstancesV2() only returns it while --vsphere-instances-v2 is set, which is
+// not the default, so that clusters switch to it explicitly. Projects
+// embedding the provider can call it through NewInstancesV2.
+
+// instancesV2Enabled tells whether InstancesV2() serves instancesV2.
+var instancesV2Enabled = false
+
+func init() {
+	flag.BoolVar(&instancesV2Enabled, "vsphere-instances-v2", false, "Serve the InstancesV2 interface of the vSphere cloud provider, which the cloud-node controllers then use instead of the Instances and Zones interfaces.")
+}
+
+type instancesV2 struct {
+	instances *instances
+	topology  topology
+}
+
+func newInstancesV2(nodeManager *NodeManager, labels vcfg.Labels) cloudprovider.InstancesV2 {
+	return &instancesV2{
+		instances: &instances{nodeManager},
+		topology: topology{
+			nodeManager: nodeManager,
+			zone:        labels.Zone,
+			region:      labels.Region,
+			disabled:    labels.Disabled,
+		},
+	}
+}
+
+var _ cloudprovider.InstancesV2 = &instancesV2{}
+
+// NewInstancesV2 returns the cloudprovider.InstancesV2 implementation of a
+// vSphere cloud provider, as returned by cloudprovider.InitCloudProvider. It
+// shares the node manager of the provider with the other interfaces and is
+// available whether or not --vsphere-instances-v2 is set.
+func NewInstancesV2(cloud cloudprovider.Interface) (cloudprovider.InstancesV2, error) {
+	vs, ok := cloud.(*VSphere)
+	if !ok || vs == nil || vs.nodeManager == nil {
+		return nil, ErrNotVSphere
+	}
+	var labels vcfg.Labels
+	if vs.cfg != nil {
+		labels = vs.cfg.Labels
+	}
+	return newInstancesV2(vs.nodeManager, labels), nil
+}
+
+// discoverNode returns the VM of the node, looked up by the provider ID of
+// the node if it is set, else by its annotated VM UUID, else by its name.
+func (i *instancesV2) discoverNode(node *v1.Node) (*NodeInfo, error) {
+	nm := i.instances.nodeManager
+	var uid string
+	if node.Spec.ProviderID != "" {
+		uid = GetUUIDFromProviderID(node.Spec.ProviderID)
+	} else {
+		uid = nm.annotatedVMUUID(node)
+	}
+	if uid != "" {
+		if err := nm.DiscoverNode(uid, cm.FindVMByUUID); err != nil {
+			return nil, err
+		}
+		if nodeInfo, ok := nm.nodeUUIDMap[uid]; ok {
+			return nodeInfo, nil
+		}
+		return nil, ErrNodeNotFound
+	}
+
+	if err := nm.DiscoverNode(node.Name, cm.FindVMByName); err != nil {
+		return nil, err
+	}
+	if nodeInfo, ok := nm.nodeNameMap[node.Name]; ok {
+		return nodeInfo, nil
+	}
+	klog.Errorf("DiscoverNode succeeded, but CACHE missed for node=%s. If this is a Linux VM, hostnames are case sensitive. Make sure they match.", node.Name)
+	return nil, ErrNodeNotFound
+}
+
+// InstanceExists returns true if the VM of the node exists.
+func (i *instancesV2) InstanceExists(ctx context.Context, node *v1.Node) (bool, error) {
+	klog.V(4).Info("instancesV2.InstanceExists() called with ", node.Name)
+
+	if node.Spec.ProviderID != "" {
+		return i.instances.InstanceExistsByProviderID(ctx, node.Spec.ProviderID)
+	}
+
+	_, err := i.discoverNode(node)
+	if errors.Is(err, vclib.ErrNoVMFound) {
+		klog.V(4).Info("instancesV2.InstanceExists() NOT FOUND with ", node.Name)
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// InstanceShutdown returns true if the VM of the node is powered off.
+func (i *instancesV2) InstanceShutdown(ctx context.Context, node *v1.Node) (bool, error) {
+	klog.V(4).Info("instancesV2.InstanceShutdown() called with ", node.Name)
+
+	providerID := node.Spec.ProviderID
+	if providerID == "" {
+		nodeInfo, err := i.discoverNode(node)
+		if err != nil {
+			return false, err
+		}
+		providerID = ProviderPrefix + nodeInfo.UUID
+	}
+	return i.instances.InstanceShutdownByProviderID(ctx, providerID)
+}
+
+// InstanceMetadata returns the provider ID, the instance type, the addresses
+// and the topology of the VM of the node. The zone and the region are empty
+// if the Labels are not configured or disabled. With
+// Nodes.EnablePlacementLabels, the host, cluster and resource pool of the VM
+// are returned as additional labels.
+func (i *instancesV2) InstanceMetadata(ctx context.Context, node *v1.Node) (*cloudprovider.InstanceMetadata, error) {
+	klog.V(4).Info("instancesV2.InstanceMetadata() called with ", node.Name)
+
+	nodeInfo, err := i.discoverNode(node)
+	if err != nil {
+		klog.V(4).Infof("instancesV2.InstanceMetadata() failed for %s with err: %v", node.Name, err)
+		return nil, err
+	}
+
+	zone, err := i.topology.lookup(ctx, nodeInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &cloudprovider.InstanceMetadata{
+		ProviderID:    ProviderPrefix + nodeInfo.UUID,
+		InstanceType:  i.instances.nodeManager.instanceType(ctx, nodeInfo),
+		NodeAddresses: nodeInfo.NodeAddresses,
+		Zone:          zone.FailureDomain,
+		Region:        zone.Region,
+	}
+
+	nm := i.instances.nodeManager
+	if nm.cfg != nil && nm.cfg.Nodes.EnablePlacementLabels {
+		if metadata.AdditionalLabels, err = nm.placementLabels(ctx, nodeInfo); err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestInstancesV2(t *testing.T) {
+	ctx := context.Background()
+
+	initCfg, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+	cfg := &ccfg.CPIConfig{}
+	cfg.Config = *initCfg
+
+	vs, err := newVSphere(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct vSphere: %s", err)
+	}
+	vs.connectionManager = cm.NewConnectionManager(&cfg.Config, nil, nil)
+	defer vs.connectionManager.Logout()
+	vs.nodeManager.connectionManager = vs.connectionManager
+
+	if _, ok := vs.InstancesV2(); ok {
+		t.Error("InstancesV2 should not be supported by default")
+	}
+	instancesV2Enabled = true
+	defer func() { instancesV2Enabled = false }()
+	instances, ok := vs.InstancesV2()
+	if !ok {
+		t.Fatal("InstancesV2 should be supported when enabled")
+	}
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	name := strings.ToLower(vm.Name)
+	vm.Guest.HostName = name
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+	UUID := strings.ToUpper(vm.Config.Uuid)
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: ConvertK8sUUIDtoNormal(UUID)},
+		},
+	}
+	vs.nodeAdded(node)
+	nodeInfo := vs.nodeManager.nodeNameMap[name]
+	if nodeInfo == nil {
+		t.Fatalf("node %s was not discovered", name)
+	}
+
+	// Tag the zone on the host of the VM and the region on its datacenter
+	vsi := vs.connectionManager.VsphereInstanceMap[cfg.Global.VCenterIP]
+	c := rest.NewClient(vsi.Conn.Client)
+	if err := c.Login(ctx, url.UserPassword(vsi.Conn.Username, vsi.Conn.Password)); err != nil {
+		t.Fatalf("Rest login failed. err=%v", err)
+	}
+	m := tags.NewManager(c)
+	host, err := nodeInfo.vm.HostSystem(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attach := func(category, tag string, ref vimtypes.ManagedObjectReference) {
+		categoryID, err := m.CreateCategory(ctx, &tags.Category{Name: category})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tagID, err := m.CreateTag(ctx, &tags.Tag{CategoryID: categoryID, Name: tag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.AttachTag(ctx, tagID, ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	attach(cfg.Labels.Zone, "k8s-zone-US-CA1", host.Reference())
+	attach(cfg.Labels.Region, "k8s-region-US", nodeInfo.dataCenter.Reference())
+
+	metadata, err := instances.InstanceMetadata(ctx, node)
+	if err != nil {
+		t.Fatalf("InstanceMetadata failed err=%v", err)
+	}
+	if !strings.EqualFold(metadata.ProviderID, ProviderPrefix+UUID) {
+		t.Errorf("ProviderID mismatch %s != %s", metadata.ProviderID, ProviderPrefix+UUID)
+	}
+	if metadata.Zone != "k8s-zone-US-CA1" || metadata.Region != "k8s-region-US" {
+		t.Errorf("unexpected topology zone=%s region=%s", metadata.Zone, metadata.Region)
+	}
+	if len(metadata.NodeAddresses) == 0 {
+		t.Error("NodeAddresses should not be empty")
+	}
+	if metadata.AdditionalLabels != nil {
+		t.Errorf("placement labels should not be set by default, actual=%v", metadata.AdditionalLabels)
+	}
+
+	// the provider ID is used once it is set on the node
+	node.Spec.ProviderID = metadata.ProviderID
+	exists, err := instances.InstanceExists(ctx, node)
+	if err != nil || !exists {
+		t.Errorf("InstanceExists should be true, actual=%t err=%v", exists, err)
+	}
+	shutdown, err := instances.InstanceShutdown(ctx, node)
+	if err != nil || shutdown {
+		t.Errorf("InstanceShutdown should be false, actual=%t err=%v", shutdown, err)
+	}
+
+	// the topology is not looked up when the labels are disabled
+	cfg.Labels.Disabled = true
+	disabled, err := NewInstancesV2(vs)
+	if err != nil {
+		t.Fatalf("NewInstancesV2 failed err=%v", err)
+	}
+	metadata, err = disabled.InstanceMetadata(ctx, node)
+	if err != nil {
+		t.Fatalf("InstanceMetadata failed err=%v", err)
+	}
+	if metadata.Zone != "" || metadata.Region != "" {
+		t.Errorf("topology should be empty, actual zone=%s region=%s", metadata.Zone, metadata.Region)
+	}
+
+	// the host, cluster and resource pool of the VM are reported as labels
+	cfg.Nodes.EnablePlacementLabels = true
+	metadata, err = disabled.InstanceMetadata(ctx, node)
+	if err != nil {
+		t.Fatalf("InstanceMetadata failed err=%v", err)
+	}
+	vmHost := simulator.Map.Get(*vm.Runtime.Host).(*simulator.HostSystem)
+	pool := simulator.Map.Get(*vm.ResourcePool).(*simulator.ResourcePool)
+	expected := map[string]string{
+		LabelPlacementHost:         sanitizeLabel(vmHost.Name),
+		LabelPlacementResourcePool: sanitizeLabel(pool.Name),
+	}
+	// the VM may run on a standalone host, which has no cluster
+	if cluster, ok := simulator.Map.Get(*vmHost.Parent).(*simulator.ClusterComputeResource); ok {
+		expected[LabelPlacementCluster] = sanitizeLabel(cluster.Name)
+	}
+	if !reflect.DeepEqual(metadata.AdditionalLabels, expected) {
+		t.Errorf("placement labels mismatch %v != %v", metadata.AdditionalLabels, expected)
+	}
+
+	missing := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "missing"}}
+	exists, err = instances.InstanceExists(ctx, missing)
+	if err != nil || exists {
+		t.Errorf("InstanceExists should be false for a missing VM, actual=%t err=%v", exists, err)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// instanceTypeFromConfig returns the instance type of a VM, made of its
+// CPUs, memory and guest OS.
+func instanceTypeFromConfig(config types.VirtualMachineConfigSummary) string {
+	os := "unknown"
+	if g, ok := GuestOSLookup[config.GuestId]; ok {
+		os = g
+	}
+
+	return fmt.Sprintf("vsphere-vm.cpu-%d.mem-%dgb.os-%s",
+		config.NumCpu,
+		(config.MemorySizeMB / 1024),
+		os,
+	)
+}
+
+// instanceType returns the instance type of the node. When the cached one
+// is older than the instance type TTL it is read again from the VM, so that
+// CPU and memory hot-adds are picked up. The cached instance type is
+// returned if the VM cannot be read.
+func (nm *NodeManager) instanceType(ctx context.Context, nodeInfo *NodeInfo) string {
+	nm.nodeInfoLock.RLock()
+	nodeType, nodeTypeTime := nodeInfo.NodeType, nodeInfo.nodeTypeTime
+	nm.nodeInfoLock.RUnlock()
+
+	if nm.instanceTypeTTL == 0 || nodeInfo.vm == nil || time.Since(nodeTypeTime) < nm.instanceTypeTTL {
+		return nodeType
+	}
+
+	vmMoList, err := nodeInfo.vm.Datacenter.GetVMMoList(ctx, []*vclib.VirtualMachine{nodeInfo.vm}, []string{"summary.config"})
+	if err != nil {
+		klog.Warningf("Failed to refresh the instance type of node %s, using %s: %v", nodeInfo.NodeName, nodeType, err)
+		return nodeType
+	}
+	refreshed := instanceTypeFromConfig(vmMoList[0].Summary.Config)
+
+	nm.nodeInfoLock.Lock()
+	nodeInfo.NodeType = refreshed
+	nodeInfo.nodeTypeTime = time.Now()
+	nm.nodeInfoLock.Unlock()
+
+	if refreshed != nodeType {
+		klog.Infof("Instance type of node %s changed from %s to %s", nodeInfo.NodeName, nodeType, refreshed)
+	}
+	return refreshed
+}
+
+// refreshInstanceTypes reads again the instance types of the registered
+// nodes whose cached instance type expired and, if Nodes.UpdateInstanceTypeLabels
+// is set, updates their instance type labels.
+func (nm *NodeManager) refreshInstanceTypes(ctx context.Context) {
+	nm.nodeRegInfoLock.RLock()
+	nodeNames := make(map[string]string, len(nm.nodeRegUUIDMap))
+	for uuid, node := range nm.nodeRegUUIDMap {
+		nodeNames[uuid] = node.Name
+	}
+	nm.nodeRegInfoLock.RUnlock()
+
+	for uuid, nodeName := range nodeNames {
+		nm.nodeInfoLock.RLock()
+		nodeInfo, ok := nm.nodeUUIDMap[uuid]
+		nm.nodeInfoLock.RUnlock()
+		if !ok {
+			continue
+		}
+
+		instanceType := nm.instanceType(ctx, nodeInfo)
+		if nm.cfg == nil || !nm.cfg.Nodes.UpdateInstanceTypeLabels || nm.kubeClient == nil {
+			continue
+		}
+		if err := nm.updateInstanceTypeLabels(ctx, nodeName, instanceType); err != nil {
+			klog.Errorf("Failed to update the instance type labels of node %s: %v", nodeName, err)
+		}
+	}
+}
+
+// updateInstanceTypeLabels sets the instance type labels of the node to
+// instanceType. Labels the node does not have are not added, they are set
+// by the cloud node controller when it initializes the node.
+func (nm *NodeManager) updateInstanceTypeLabels(ctx context.Context, nodeName string, instanceType string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := nm.kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		for _, label := range []string{v1.LabelInstanceTypeStable, v1.LabelInstanceType} {
+			if value, ok := node.Labels[label]; ok && value != instanceType {
+				node.Labels[label] = instanceType
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		logging.V(logging.NodeManager, 2).Infof("Updating the instance type labels of node %s to %s", nodeName, instanceType)
+		_, err = nm.kubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestInstanceTypeFromConfig(t *testing.T) {
+	config := vimtypes.VirtualMachineConfigSummary{NumCpu: 4, MemorySizeMB: 8192, GuestId: "ubuntu64Guest"}
+	if instanceType := instanceTypeFromConfig(config); instanceType != "vsphere-vm.cpu-4.mem-8gb.os-ubuntu" {
+		t.Errorf("Unexpected instance type %s", instanceType)
+	}
+
+	config.GuestId = "notAGuest"
+	if instanceType := instanceTypeFromConfig(config); instanceType != "vsphere-vm.cpu-4.mem-8gb.os-unknown" {
+		t.Errorf("Unexpected instance type %s", instanceType)
+	}
+}
+
+func TestRefreshInstanceTypes(t *testing.T) {
+	cfg, ok := configFromEnvOrSim(true)
+	defer ok()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	cpiCfg := &ccfg.CPIConfig{}
+	cpiCfg.Nodes.UpdateInstanceTypeLabels = true
+	nm := newNodeManager(cpiCfg, connMgr)
+	nm.instanceTypeTTL = time.Hour
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+	vm.Summary.Config.NumCpu = 2
+	vm.Summary.Config.MemorySizeMB = 4096
+	vm.Summary.Config.GuestId = "ubuntu64Guest"
+
+	err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP])
+	if err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+	if err := nm.DiscoverNode(vm.Name, cm.FindVMByName); err != nil {
+		t.Fatalf("Failed DiscoverNode: %s", err)
+	}
+	var nodeInfo *NodeInfo
+	for _, n := range nm.nodeUUIDMap {
+		nodeInfo = n
+	}
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeInfo.NodeName,
+			Labels: map[string]string{
+				v1.LabelInstanceTypeStable: nodeInfo.NodeType,
+			},
+		},
+	}
+	nm.kubeClient = fake.NewSimpleClientset(node)
+	nm.addNode(nodeInfo.UUID, node)
+
+	// hot-add CPU and memory
+	vm.Summary.Config.NumCpu = 4
+	vm.Summary.Config.MemorySizeMB = 8192
+
+	nm.refreshInstanceTypes(context.Background())
+	if nodeInfo.NodeType != "vsphere-vm.cpu-2.mem-4gb.os-ubuntu" {
+		t.Errorf("Instance type should be cached until the TTL expires, got %s", nodeInfo.NodeType)
+	}
+
+	nodeInfo.nodeTypeTime = time.Now().Add(-2 * time.Hour)
+	nm.refreshInstanceTypes(context.Background())
+	if nodeInfo.NodeType != "vsphere-vm.cpu-4.mem-8gb.os-ubuntu" {
+		t.Errorf("Instance type should be refreshed once the TTL expired, got %s", nodeInfo.NodeType)
+	}
+
+	updated, err := nm.kubeClient.CoreV1().Nodes().Get(context.Background(), nodeInfo.NodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label := updated.Labels[v1.LabelInstanceTypeStable]; label != "vsphere-vm.cpu-4.mem-8gb.os-ubuntu" {
+		t.Errorf("Unexpected instance type label %s", label)
+	}
+	if _, ok := updated.Labels[v1.LabelInstanceType]; ok {
+		t.Error("Missing instance type labels should not be added")
+	}
+}
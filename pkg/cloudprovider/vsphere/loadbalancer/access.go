@@ -18,6 +18,9 @@ package loadbalancer
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,6 +29,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
 )
@@ -43,6 +47,19 @@ const (
 	ScopeIPPoolID = "ippoolid"
 	// ScopeLBClass is the load balancer class scope
 	ScopeLBClass = "lbclass"
+	// ScopeIPAllocationName is the IP address allocation name scope
+	ScopeIPAllocationName = "ipallocationname"
+	// ScopeReleased is the scope of the release time of a held IP address allocation
+	ScopeReleased = "released"
+	// ScopeIPFamily is the IP family scope, only set on IPv6 virtual servers
+	ScopeIPFamily = "ipfamily"
+	// ScopeZone is the zone scope of the zonal load balancer services and
+	// their virtual servers
+	ScopeZone = "zone"
+
+	// defaultLocaleServicesID is the id of the locale services created for a
+	// T1 gateway without any
+	defaultLocaleServicesID = "default"
 )
 
 type access struct {
@@ -50,6 +67,29 @@ type access struct {
 	config       *config.LBConfig
 	ownerTag     model.Tag
 	standardTags Tags
+	// description is the template of the descriptions of the virtual
+	// servers, pools and TCP monitor profiles, nil for the default ones
+	description *template.Template
+
+	// appProfilePaths caches the application profiles resolved by name
+	appProfilePaths appProfileCache
+}
+
+// descriptionData is the data of the description template
+type descriptionData struct {
+	// Kind is the kind of the NSX-T object, such as "virtual server"
+	Kind string
+	// Cluster is the name of the cluster
+	Cluster string
+	// Namespace is the namespace of the Service
+	Namespace string
+	// Name is the name of the Service
+	Name string
+	// Port is the service port of a virtual server, the member port of a
+	// pool or TCP monitor profile
+	Port int
+	// App is the name of the application creating the object
+	App string
 }
 
 var _ NSXTAccess = &access{}
@@ -62,11 +102,30 @@ func NewNSXTAccess(broker NsxtBroker, config *config.LBConfig) (NSXTAccess, erro
 	for k, v := range config.LoadBalancer.AdditionalTags {
 		standardTags[k] = newTag(k, v)
 	}
+	if config.LoadBalancer.EdgeClusterPath != "" || config.LoadBalancer.FailoverMode != "" {
+		if _, err := parseTier1Path(config.LoadBalancer.Tier1GatewayPath); err != nil {
+			return nil, err
+		}
+	}
+	if config.LoadBalancer.EdgeClusterPath != "" {
+		siteID, enforcementPointID, id, err := parseEdgeClusterPath(config.LoadBalancer.EdgeClusterPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := broker.ReadEdgeCluster(siteID, enforcementPointID, id); err != nil {
+			return nil, errors.Wrapf(err, "reading edge cluster %s failed", config.LoadBalancer.EdgeClusterPath)
+		}
+	}
+	description, err := parseDescriptionTemplate(config)
+	if err != nil {
+		return nil, err
+	}
 	return &access{
 		broker:       broker,
 		config:       config,
 		ownerTag:     standardTags[ScopeOwner],
 		standardTags: standardTags,
+		description:  description,
 	}, nil
 }
 
@@ -83,7 +142,23 @@ func (a *access) FindIPPoolByName(poolName string) (string, error) {
 	return "", fmt.Errorf("load balancer IP pool named %s not found", poolName)
 }
 
+func (a *access) GetIPPoolUsage(ipPoolID string) (*model.PolicyPoolUsage, error) {
+	list, err := a.broker.ListIPPools()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing IP pools failed")
+	}
+	for _, item := range list {
+		if item.Id != nil && *item.Id == ipPoolID {
+			return item.PoolUsage, nil
+		}
+	}
+	return nil, fmt.Errorf("load balancer IP pool %s not found", ipPoolID)
+}
+
 func (a *access) CreateLoadBalancerService(clusterName string) (*model.LBService, error) {
+	if err := a.placeTier1Gateway(); err != nil {
+		return nil, errors.Wrapf(err, "placing T1 gateway %s failed", a.config.LoadBalancer.Tier1GatewayPath)
+	}
 	lbService := model.LBService{
 		Description:      strptr(fmt.Sprintf("virtual server pool for cluster %s created by %s", clusterName, AppName)),
 		DisplayName:      displayName(clusterName),
@@ -99,9 +174,81 @@ func (a *access) CreateLoadBalancerService(clusterName string) (*model.LBService
 	return &result, nil
 }
 
+// placeTier1Gateway sets the configured failover mode and edge cluster of
+// the T1 gateway, as the LbService runs on the edges of its T1 gateway.
+func (a *access) placeTier1Gateway() error {
+	edgeClusterPath := a.config.LoadBalancer.EdgeClusterPath
+	failoverMode := a.config.LoadBalancer.FailoverMode
+	if edgeClusterPath == "" && failoverMode == "" {
+		return nil
+	}
+	tier1ID, err := parseTier1Path(a.config.LoadBalancer.Tier1GatewayPath)
+	if err != nil {
+		return err
+	}
+
+	if failoverMode != "" {
+		tier1, err := a.broker.ReadTier1(tier1ID)
+		if err != nil {
+			return err
+		}
+		if !safeEquals(tier1.FailoverMode, &failoverMode) {
+			klog.Infof("setting failover mode %s of T1 gateway %s", failoverMode, tier1ID)
+			if err := a.broker.PatchTier1(tier1ID, model.Tier1{FailoverMode: strptr(failoverMode)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if edgeClusterPath != "" {
+		list, err := a.broker.ListTier1LocaleServices(tier1ID)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			list = []model.LocaleServices{{Id: strptr(defaultLocaleServicesID)}}
+		}
+		for _, localeServices := range list {
+			if safeEquals(localeServices.EdgeClusterPath, &edgeClusterPath) {
+				continue
+			}
+			klog.Infof("setting edge cluster %s of T1 gateway %s locale services %s", edgeClusterPath, tier1ID, *localeServices.Id)
+			err := a.broker.PatchTier1LocaleServices(tier1ID, *localeServices.Id, model.LocaleServices{EdgeClusterPath: strptr(edgeClusterPath)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CreateZonalLoadBalancerService creates the LbService of a zone on the T1
+// gateway of the zone
+func (a *access) CreateZonalLoadBalancerService(clusterName, zone, tier1GatewayPath string) (*model.LBService, error) {
+	lbService := model.LBService{
+		Description:      strptr(fmt.Sprintf("virtual server pool for cluster %s in zone %s created by %s", clusterName, zone, AppName)),
+		DisplayName:      strptr(fmt.Sprintf("cluster:%s:%s", clusterName, zone)),
+		Tags:             a.standardTags.Append(clusterTag(clusterName), zoneTag(zone)).Normalize(),
+		Size:             strptr(a.config.LoadBalancer.Size),
+		Enabled:          boolptr(true),
+		ConnectivityPath: strptr(tier1GatewayPath),
+	}
+	result, err := a.broker.CreateLoadBalancerService(lbService)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating load balancer service failed for cluster %s in zone %s", clusterName, zone)
+	}
+	return &result, nil
+}
+
+// FindZonalLoadBalancerService finds the LbService of a zone by cluster name
+// and zone, nil if there is none
+func (a *access) FindZonalLoadBalancerService(clusterName, zone string) (*model.LBService, error) {
+	return a.findLoadBalancerService(zone, a.ownerTag, clusterTag(clusterName), zoneTag(zone))
+}
+
 func (a *access) FindLoadBalancerService(clusterName string, id string) (*model.LBService, error) {
 	if id == "" {
-		return a.findLoadBalancerService(a.ownerTag, clusterTag(clusterName))
+		return a.findLoadBalancerService("", a.ownerTag, clusterTag(clusterName))
 	}
 
 	result, err := a.broker.ReadLoadBalancerService(id)
@@ -122,13 +269,18 @@ func (a *access) FindLoadBalancerService(clusterName string, id string) (*model.
 	return &result, nil
 }
 
-func (a *access) findLoadBalancerService(tags ...model.Tag) (*model.LBService, error) {
+// findLoadBalancerService finds the LbService of the zone, the zonal ones are
+// skipped if zone is empty
+func (a *access) findLoadBalancerService(zone string, tags ...model.Tag) (*model.LBService, error) {
 	list, err := a.broker.ListLoadBalancerServices()
 	if err != nil {
 		return nil, errors.Wrapf(err, "listing load balancer services failed")
 	}
 	for _, item := range list {
-		if a.config.LoadBalancer.Tier1GatewayPath != "" && item.ConnectivityPath != nil && *item.ConnectivityPath == a.config.LoadBalancer.Tier1GatewayPath {
+		if getTag(item.Tags, ScopeZone) != zone {
+			continue
+		}
+		if zone == "" && a.config.LoadBalancer.Tier1GatewayPath != "" && item.ConnectivityPath != nil && *item.ConnectivityPath == a.config.LoadBalancer.Tier1GatewayPath {
 			return &item, nil
 		}
 		if checkTags(item.Tags, tags...) {
@@ -205,27 +357,39 @@ func (a *access) GetAppProfilePath(class LBClass, protocol corev1.Protocol) (str
 	default:
 		return "", fmt.Errorf("Unsupported protocol %s", protocol)
 	}
-	return a.findAppProfilePathByName(profileReference.Name, resourceType)
+	if path, ok := a.appProfilePaths.get(profileReference.Name, resourceType); ok {
+		return path, nil
+	}
+	path, err := a.findAppProfilePathByName(profileReference.Name, resourceType)
+	if err != nil {
+		return "", err
+	}
+	a.appProfilePaths.put(profileReference.Name, resourceType, path)
+	return path, nil
 }
 
-func (a *access) CreateVirtualServer(clusterName string, objectName types.NamespacedName, class LBClass, ipAddress string,
-	mapping Mapping, lbServicePath, applicationProfilePath string, poolPath *string) (*model.LBVirtualServer, error) {
+func (a *access) CreateVirtualServer(clusterName string, objectName types.NamespacedName, lbName string, class LBClass, ipAddress string,
+	mapping Mapping, lbServicePath, applicationProfilePath string, poolPath *string, clientSSL *model.LBClientSslProfileBinding) (*model.LBVirtualServer, error) {
 	allTags := append(class.Tags(), clusterTag(clusterName), serviceTag(objectName), portTag(mapping))
 	virtualServer := model.LBVirtualServer{
-		Description: strptr(fmt.Sprintf("virtual server for cluster %s, service %s created by %s",
-			clusterName, objectName, AppName)),
-		DisplayName:            displayNameObject(clusterName, objectName),
-		Tags:                   a.standardTags.Append(allTags...).Normalize(),
-		DefaultPoolMemberPorts: []string{fmt.Sprintf("%d", mapping.NodePort)},
-		Enabled:                boolptr(true),
-		IpAddress:              strptr(ipAddress),
-		ApplicationProfilePath: strptr(applicationProfilePath),
-		PoolPath:               poolPath,
-		Ports:                  []string{fmt.Sprintf("%d", mapping.SourcePort)},
-		LbServicePath:          strptr(lbServicePath),
+		Description: a.describe(fmt.Sprintf("virtual server for cluster %s, service %s created by %s",
+			clusterName, objectName, AppName), "virtual server", clusterName, objectName, mapping.SourcePort),
+		DisplayName:             a.prefixed(lbName),
+		Tags:                    a.standardTags.Append(allTags...).Normalize(),
+		DefaultPoolMemberPorts:  []string{fmt.Sprintf("%d", mapping.MemberPort)},
+		Enabled:                 boolptr(true),
+		IpAddress:               strptr(ipAddress),
+		ApplicationProfilePath:  strptr(applicationProfilePath),
+		PoolPath:                poolPath,
+		Ports:                   []string{fmt.Sprintf("%d", mapping.SourcePort)},
+		LbServicePath:           strptr(lbServicePath),
+		ClientSslProfileBinding: clientSSL,
 	}
 	result, err := a.broker.CreateLoadBalancerVirtualServer(virtualServer)
 	if err != nil {
+		// the broker flattens the NotFound error of a deleted application
+		// profile, so that the profile is resolved again on any failure
+		a.appProfilePaths.invalidate(applicationProfilePath)
 		return nil, errors.Wrapf(err, "creating virtual server failed for %s:%s with IP address %s", clusterName, objectName, ipAddress)
 	}
 	return &result, nil
@@ -257,6 +421,9 @@ func (a *access) listVirtualServers(tags ...model.Tag) ([]*model.LBVirtualServer
 func (a *access) UpdateVirtualServer(server *model.LBVirtualServer) error {
 	_, err := a.broker.UpdateLoadBalancerVirtualServer(*server)
 	if err != nil {
+		if server.ApplicationProfilePath != nil {
+			a.appProfilePaths.invalidate(*server.ApplicationProfilePath)
+		}
 		return errors.Wrapf(err, "updating load balancer virtual server %s (%s) failed", *server.DisplayName, *server.Id)
 	}
 	return nil
@@ -273,23 +440,16 @@ func (a *access) DeleteVirtualServer(id string) error {
 	return nil
 }
 
-func (a *access) CreatePool(clusterName string, objectName types.NamespacedName, mapping Mapping, members []model.LBPoolMember, activeMonitorPaths []string) (*model.LBPool, error) {
-	var snatTranslation *data.StructValue
-	var err error
-	if a.config.LoadBalancer.SnatDisabled {
-		snatTranslation, err = newNsxtTypeConverter().createLBSnatDisabled()
-		if err != nil {
-			return nil, errors.Wrapf(err, "creating pool failed on preparing LBSnatDisabled failed")
-		}
-	} else {
-		snatTranslation, err = newNsxtTypeConverter().createLBSnatAutoMap()
-		if err != nil {
-			return nil, errors.Wrapf(err, "creating pool failed on preparing LBSnatAutoMap failed")
-		}
+func (a *access) CreatePool(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping, members []model.LBPoolMember,
+	activeMonitorPaths []string, preserveClientIP bool) (*model.LBPool, error) {
+	snatTranslation, err := a.SnatTranslation(preserveClientIP)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating pool failed")
 	}
 	pool := model.LBPool{
-		Description:        strptr(fmt.Sprintf("pool for cluster %s, service %s created by %s", clusterName, objectName, AppName)),
-		DisplayName:        displayNameObject(clusterName, objectName),
+		Description: a.describe(fmt.Sprintf("pool for cluster %s, service %s created by %s",
+			clusterName, objectName, AppName), "pool", clusterName, objectName, mapping.MemberPort),
+		DisplayName:        a.prefixed(lbName),
 		Tags:               a.standardTags.Append(clusterTag(clusterName), serviceTag(objectName), portTag(mapping)).Normalize(),
 		SnatTranslation:    snatTranslation,
 		Members:            members,
@@ -302,6 +462,21 @@ func (a *access) CreatePool(clusterName string, objectName types.NamespacedName,
 	return &result, nil
 }
 
+func (a *access) SnatTranslation(preserveClientIP bool) (*data.StructValue, error) {
+	if preserveClientIP || a.config.LoadBalancer.SnatDisabled {
+		snatTranslation, err := newNsxtTypeConverter().createLBSnatDisabled()
+		if err != nil {
+			return nil, errors.Wrapf(err, "preparing LBSnatDisabled failed")
+		}
+		return snatTranslation, nil
+	}
+	snatTranslation, err := newNsxtTypeConverter().createLBSnatAutoMap()
+	if err != nil {
+		return nil, errors.Wrapf(err, "preparing LBSnatAutoMap failed")
+	}
+	return snatTranslation, nil
+}
+
 func (a *access) GetPool(id string) (*model.LBPool, error) {
 	pool, err := a.broker.ReadLoadBalancerPool(id)
 	if err != nil {
@@ -366,17 +541,17 @@ func (a *access) DeletePool(id string) error {
 	return nil
 }
 
-func (a *access) CreateTCPMonitorProfile(clusterName string, objectName types.NamespacedName, mapping Mapping) (*model.LBTcpMonitorProfile, error) {
+func (a *access) CreateTCPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping) (*model.LBTcpMonitorProfile, error) {
 	profile := model.LBTcpMonitorProfile{
-		Description: strptr(fmt.Sprintf("tcp monitor for cluster %s, service %s, port %d created by %s",
-			clusterName, objectName, mapping.NodePort, AppName)),
-		DisplayName: displayNameMapping(clusterName, objectName, mapping),
+		Description: a.describe(fmt.Sprintf("tcp monitor for cluster %s, service %s, port %d created by %s",
+			clusterName, objectName, mapping.MemberPort, AppName), "tcp monitor", clusterName, objectName, mapping.MemberPort),
+		DisplayName: a.prefixed(fmt.Sprintf("%s:%d", lbName, mapping.MemberPort)),
 		Tags:        a.standardTags.Append(clusterTag(clusterName), serviceTag(objectName), portTag(mapping)).Normalize(),
-		MonitorPort: int64ptr(int64(mapping.NodePort)),
+		MonitorPort: int64ptr(int64(mapping.MemberPort)),
 	}
 	monitor, err := a.broker.CreateLoadBalancerTCPMonitorProfile(profile)
 	if err != nil {
-		return nil, errors.Wrapf(err, "creating tcp monitor failed for %s:%s:%d", clusterName, objectName, mapping.NodePort)
+		return nil, errors.Wrapf(err, "creating tcp monitor failed for %s:%s:%d", clusterName, objectName, mapping.MemberPort)
 	}
 	return &monitor, nil
 }
@@ -439,6 +614,193 @@ func (a *access) DeleteTCPMonitorProfile(id string) error {
 	return nil
 }
 
+func (a *access) CreateUDPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping, healthCheck udpHealthCheck) (*model.LBUdpMonitorProfile, error) {
+	profile := model.LBUdpMonitorProfile{
+		Description: a.describe(fmt.Sprintf("udp monitor for cluster %s, service %s, port %d created by %s",
+			clusterName, objectName, mapping.MemberPort, AppName), "udp monitor", clusterName, objectName, mapping.MemberPort),
+		DisplayName: a.prefixed(fmt.Sprintf("%s:%d", lbName, mapping.MemberPort)),
+		Tags:        a.standardTags.Append(clusterTag(clusterName), serviceTag(objectName), portTag(mapping)).Normalize(),
+		MonitorPort: int64ptr(int64(mapping.MemberPort)),
+		Send:        strptr(healthCheck.send),
+		Receive:     strptr(healthCheck.receive),
+	}
+	monitor, err := a.broker.CreateLoadBalancerUDPMonitorProfile(profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating udp monitor failed for %s:%s:%d", clusterName, objectName, mapping.MemberPort)
+	}
+	return &monitor, nil
+}
+
+func (a *access) FindUDPMonitorProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBUdpMonitorProfile, error) {
+	return a.listUDPMonitorProfiles(a.ownerTag, clusterTag(clusterName), serviceTag(objectName))
+}
+
+func (a *access) ListUDPMonitorProfiles(clusterName string) ([]*model.LBUdpMonitorProfile, error) {
+	return a.listUDPMonitorProfiles(a.ownerTag, clusterTag(clusterName))
+}
+
+func (a *access) listUDPMonitorProfiles(tags ...model.Tag) ([]*model.LBUdpMonitorProfile, error) {
+	list, err := a.broker.ListLoadBalancerMonitorProfiles()
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing load balancer monitors failed")
+	}
+	result := []*model.LBUdpMonitorProfile{}
+	converter := newNsxtTypeConverter()
+	for _, item := range list {
+		resourceType, err := item.String("resource_type")
+		if err != nil || resourceType != model.LBMonitorProfile_RESOURCE_TYPE_LBUDPMONITORPROFILE {
+			continue
+		}
+		profile, err := converter.convertStructValueToLBUDPMonitorProfile(item)
+		if err != nil {
+			return nil, err
+		}
+		if checkTags(profile.Tags, tags...) {
+			result = append(result, &profile)
+		}
+	}
+	return result, nil
+}
+
+func (a *access) UpdateUDPMonitorProfile(monitor *model.LBUdpMonitorProfile) error {
+	_, err := a.broker.UpdateLoadBalancerUDPMonitorProfile(*monitor)
+	if err != nil {
+		return errors.Wrapf(err, "updating load balancer UDP monitor %s (%s) failed", *monitor.DisplayName, *monitor.Id)
+	}
+	return nil
+}
+
+func (a *access) DeleteUDPMonitorProfile(id string) error {
+	return a.DeleteTCPMonitorProfile(id)
+}
+
+func (a *access) CreateHTTPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, healthCheckNodePort int) (*model.LBHttpMonitorProfile, error) {
+	profile := model.LBHttpMonitorProfile{
+		Description: a.describe(fmt.Sprintf("http monitor for cluster %s, service %s, health check node port %d created by %s",
+			clusterName, objectName, healthCheckNodePort, AppName), "http monitor", clusterName, objectName, healthCheckNodePort),
+		DisplayName:         a.prefixed(fmt.Sprintf("%s:%d", lbName, healthCheckNodePort)),
+		Tags:                a.standardTags.Append(clusterTag(clusterName), serviceTag(objectName)).Normalize(),
+		MonitorPort:         int64ptr(int64(healthCheckNodePort)),
+		RequestMethod:       strptr(model.LBHttpMonitorProfile_REQUEST_METHOD_GET),
+		RequestUrl:          strptr(healthCheckPath),
+		ResponseStatusCodes: []int64{http.StatusOK},
+	}
+	monitor, err := a.broker.CreateLoadBalancerHTTPMonitorProfile(profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating http monitor failed for %s:%s:%d", clusterName, objectName, healthCheckNodePort)
+	}
+	return &monitor, nil
+}
+
+func (a *access) FindHTTPMonitorProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBHttpMonitorProfile, error) {
+	return a.listHTTPMonitorProfiles(a.ownerTag, clusterTag(clusterName), serviceTag(objectName))
+}
+
+func (a *access) ListHTTPMonitorProfiles(clusterName string) ([]*model.LBHttpMonitorProfile, error) {
+	return a.listHTTPMonitorProfiles(a.ownerTag, clusterTag(clusterName))
+}
+
+func (a *access) listHTTPMonitorProfiles(tags ...model.Tag) ([]*model.LBHttpMonitorProfile, error) {
+	list, err := a.broker.ListLoadBalancerMonitorProfiles()
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing load balancer monitors failed")
+	}
+	result := []*model.LBHttpMonitorProfile{}
+	converter := newNsxtTypeConverter()
+	for _, item := range list {
+		resourceType, err := item.String("resource_type")
+		if err != nil || resourceType != model.LBMonitorProfile_RESOURCE_TYPE_LBHTTPMONITORPROFILE {
+			continue
+		}
+		profile, err := converter.convertStructValueToLBHTTPMonitorProfile(item)
+		if err != nil {
+			return nil, err
+		}
+		if checkTags(profile.Tags, tags...) {
+			result = append(result, &profile)
+		}
+	}
+	return result, nil
+}
+
+func (a *access) UpdateHTTPMonitorProfile(monitor *model.LBHttpMonitorProfile) error {
+	_, err := a.broker.UpdateLoadBalancerHTTPMonitorProfile(*monitor)
+	if err != nil {
+		return errors.Wrapf(err, "updating load balancer HTTP monitor %s (%s) failed", *monitor.DisplayName, *monitor.Id)
+	}
+	return nil
+}
+
+func (a *access) DeleteHTTPMonitorProfile(id string) error {
+	return a.DeleteTCPMonitorProfile(id)
+}
+
+func (a *access) CreateHTTPAppProfile(clusterName string, objectName types.NamespacedName, lbName string, options httpProfileOptions) (*model.LBHttpProfile, error) {
+	profile := model.LBHttpProfile{
+		Description: a.describe(fmt.Sprintf("http profile for cluster %s, service %s created by %s",
+			clusterName, objectName, AppName), "http profile", clusterName, objectName, 0),
+		DisplayName:     a.prefixed(lbName),
+		Tags:            a.standardTags.Append(clusterTag(clusterName), serviceTag(objectName)).Normalize(),
+		XForwardedFor:   options.xForwardedForPtr(),
+		ServerKeepAlive: boolptr(options.serverKeepAlive),
+	}
+	created, err := a.broker.CreateLoadBalancerHTTPAppProfile(profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating http profile failed for %s:%s", clusterName, objectName)
+	}
+	return &created, nil
+}
+
+func (a *access) FindHTTPAppProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBHttpProfile, error) {
+	return a.listHTTPAppProfiles(a.ownerTag, clusterTag(clusterName), serviceTag(objectName))
+}
+
+func (a *access) ListHTTPAppProfiles(clusterName string) ([]*model.LBHttpProfile, error) {
+	return a.listHTTPAppProfiles(a.ownerTag, clusterTag(clusterName))
+}
+
+func (a *access) listHTTPAppProfiles(tags ...model.Tag) ([]*model.LBHttpProfile, error) {
+	list, err := a.broker.ListAppProfiles()
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing load balancer application profiles failed")
+	}
+	result := []*model.LBHttpProfile{}
+	converter := newNsxtTypeConverter()
+	for _, item := range list {
+		resourceType, err := item.String("resource_type")
+		if err != nil || resourceType != model.LBAppProfile_RESOURCE_TYPE_LBHTTPPROFILE {
+			continue
+		}
+		profile, err := converter.convertStructValueToLBHTTPProfile(item)
+		if err != nil {
+			return nil, err
+		}
+		if checkTags(profile.Tags, tags...) {
+			result = append(result, &profile)
+		}
+	}
+	return result, nil
+}
+
+func (a *access) UpdateHTTPAppProfile(profile *model.LBHttpProfile) error {
+	_, err := a.broker.UpdateLoadBalancerHTTPAppProfile(*profile)
+	if err != nil {
+		return errors.Wrapf(err, "updating load balancer HTTP profile %s (%s) failed", *profile.DisplayName, *profile.Id)
+	}
+	return nil
+}
+
+func (a *access) DeleteHTTPAppProfile(id string) error {
+	err := a.broker.DeleteLoadBalancerAppProfile(id)
+	if isNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "deleting application profile %s failed", id)
+	}
+	return nil
+}
+
 func (a *access) AllocateExternalIPAddress(ipPoolID string, clusterName string, objectName types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
 	allocation := model.IpAddressAllocation{
 		Tags: a.standardTags.Append(clusterTag(clusterName), serviceTag(objectName)).Normalize(),
@@ -450,11 +812,45 @@ func (a *access) AllocateExternalIPAddress(ipPoolID string, clusterName string,
 	return &allocated, &ipAdress, nil
 }
 
+func (a *access) AllocateNamedExternalIPAddress(ipPoolID string, clusterName string, allocationName string, objectName types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	allocation := model.IpAddressAllocation{
+		DisplayName: strptr(allocationName),
+		Tags:        a.standardTags.Append(clusterTag(clusterName), ipAllocationNameTag(allocationName), serviceTag(objectName)).Normalize(),
+	}
+	allocated, ipAdress, err := a.broker.AllocateFromIPPool(ipPoolID, allocation)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "allocating external IP address %s failed", allocationName)
+	}
+	return &allocated, &ipAdress, nil
+}
+
 func (a *access) FindExternalIPAddressForObject(ipPoolID string, clusterName string, objectName types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
 	results, err := a.findExternalIPAddresses(ipPoolID, a.ownerTag, clusterTag(clusterName), serviceTag(objectName))
 	if err != nil {
 		return nil, nil, err
 	}
+	// the named allocations bound to the service are found by their name
+	unnamed := []*model.IpAddressAllocation{}
+	for _, item := range results {
+		if getTag(item.Tags, ScopeIPAllocationName) == "" {
+			unnamed = append(unnamed, item)
+		}
+	}
+	return a.realizedExternalIPAddress(ipPoolID, unnamed)
+}
+
+func (a *access) FindNamedExternalIPAddress(ipPoolID string, clusterName string, allocationName string) (*model.IpAddressAllocation, *string, error) {
+	results, err := a.findExternalIPAddresses(ipPoolID, a.ownerTag, clusterTag(clusterName), ipAllocationNameTag(allocationName))
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.realizedExternalIPAddress(ipPoolID, results)
+}
+
+// realizedExternalIPAddress returns the single IP address allocation of
+// results and its IP address, waiting for it to be realized if needed
+func (a *access) realizedExternalIPAddress(ipPoolID string, results []*model.IpAddressAllocation) (*model.IpAddressAllocation, *string, error) {
+	var err error
 	if len(results) == 0 {
 		return nil, nil, nil
 	}
@@ -504,6 +900,69 @@ func (a *access) ReleaseExternalIPAddress(ipPoolID string, id string) error {
 	return nil
 }
 
+// HoldExternalIPAddress keeps an allocated IP address out of the IP pool after
+// its service is deleted. The service tag is replaced by the release time, so
+// that the allocation is no longer found for the service and can be released
+// once the quarantine has expired.
+func (a *access) HoldExternalIPAddress(ipPoolID string, allocation *model.IpAddressAllocation, releasedAt time.Time) error {
+	held := *allocation
+	held.Tags = []model.Tag{}
+	for _, tag := range allocation.Tags {
+		if tag.Scope == nil || *tag.Scope != ScopeService {
+			held.Tags = append(held.Tags, tag)
+		}
+	}
+	held.Tags = append(held.Tags, releasedTag(releasedAt))
+	err := a.broker.UpdateIPPoolAllocation(ipPoolID, held)
+	if isNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "holding external IP address allocation id=%s failed", *allocation.Id)
+	}
+	return nil
+}
+
+func (a *access) UpdateExternalIPAddress(ipPoolID string, allocation *model.IpAddressAllocation) error {
+	err := a.broker.UpdateIPPoolAllocation(ipPoolID, *allocation)
+	if err != nil {
+		return errors.Wrapf(err, "updating external IP address allocation id=%s failed", *allocation.Id)
+	}
+	return nil
+}
+
+func parseDescriptionTemplate(cfg *config.LBConfig) (*template.Template, error) {
+	return config.ParseDescriptionTemplate(cfg.LoadBalancer.DescriptionTemplate)
+}
+
+// describe returns the description of an NSX-T object rendered by the
+// description template, defaultDescription if there is none or it fails.
+func (a *access) describe(defaultDescription, kind, clusterName string, objectName types.NamespacedName, port int) *string {
+	if a.description == nil {
+		return strptr(defaultDescription)
+	}
+	var description strings.Builder
+	err := a.description.Execute(&description, descriptionData{
+		Kind:      kind,
+		Cluster:   clusterName,
+		Namespace: objectName.Namespace,
+		Name:      objectName.Name,
+		Port:      port,
+		App:       AppName,
+	})
+	if err != nil {
+		klog.Warningf("rendering description of %s %s failed: %v", kind, objectName, err)
+		return strptr(defaultDescription)
+	}
+	return strptr(description.String())
+}
+
+// prefixed returns the display name with the configured prefix, truncated
+// to the length limit of NSX-T
+func (a *access) prefixed(displayName string) *string {
+	return strptr(truncateDisplayName(a.config.LoadBalancer.DisplayNamePrefix + displayName))
+}
+
 func displayName(clusterName string) *string {
 	return strptr(fmt.Sprintf("cluster:%s", clusterName))
 }
@@ -511,7 +970,3 @@ func displayName(clusterName string) *string {
 func displayNameObject(clusterName string, objectName types.NamespacedName) *string {
 	return strptr(fmt.Sprintf("cluster:%s:%s", clusterName, objectName))
 }
-
-func displayNameMapping(clusterName string, objectName types.NamespacedName, mapping Mapping) *string {
-	return strptr(fmt.Sprintf("cluster:%s:%s:%d", clusterName, objectName, mapping.NodePort))
-}
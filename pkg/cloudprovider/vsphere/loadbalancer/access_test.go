@@ -0,0 +1,270 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+)
+
+// placementBroker knows a T1 gateway t1 and an edge cluster ec1. Methods not
+// needed for the placement panic.
+type placementBroker struct {
+	NsxtBroker
+	tier1          model.Tier1
+	localeServices []model.LocaleServices
+	calls          []string
+}
+
+func (b *placementBroker) ReadTier1(id string) (model.Tier1, error) {
+	if id != "t1" {
+		return model.Tier1{}, fmt.Errorf("NotFound")
+	}
+	return b.tier1, nil
+}
+
+func (b *placementBroker) PatchTier1(id string, tier1 model.Tier1) error {
+	b.calls = append(b.calls, fmt.Sprintf("patch %s failover mode %s", id, *tier1.FailoverMode))
+	return nil
+}
+
+func (b *placementBroker) ListTier1LocaleServices(string) ([]model.LocaleServices, error) {
+	return b.localeServices, nil
+}
+
+func (b *placementBroker) PatchTier1LocaleServices(tier1ID string, id string, localeServices model.LocaleServices) error {
+	b.calls = append(b.calls, fmt.Sprintf("patch %s/%s edge cluster %s", tier1ID, id, *localeServices.EdgeClusterPath))
+	return nil
+}
+
+func (b *placementBroker) ReadEdgeCluster(siteID, enforcementPointID, id string) (model.PolicyEdgeCluster, error) {
+	if siteID != "default" || enforcementPointID != "default" || id != "ec1" {
+		return model.PolicyEdgeCluster{}, fmt.Errorf("NotFound")
+	}
+	return model.PolicyEdgeCluster{Id: strptr(id)}, nil
+}
+
+func (b *placementBroker) CreateLoadBalancerService(service model.LBService) (model.LBService, error) {
+	b.calls = append(b.calls, "create lb service")
+	service.Id = strptr("lbs1")
+	return service, nil
+}
+
+func TestCreateLoadBalancerServicePlacement(t *testing.T) {
+	ec1 := "/infra/sites/default/enforcement-points/default/edge-clusters/ec1"
+	ec2 := "/infra/sites/default/enforcement-points/default/edge-clusters/ec2"
+
+	testCases := []struct {
+		name            string
+		edgeClusterPath string
+		failoverMode    string
+		tier1           model.Tier1
+		localeServices  []model.LocaleServices
+		expectedErr     bool
+		expectedCalls   []string
+	}{
+		{
+			name:          "no placement",
+			expectedCalls: []string{"create lb service"},
+		},
+		{
+			name:            "placed already",
+			edgeClusterPath: ec1,
+			failoverMode:    model.Tier1_FAILOVER_MODE_PREEMPTIVE,
+			tier1:           model.Tier1{FailoverMode: strptr(model.Tier1_FAILOVER_MODE_PREEMPTIVE)},
+			localeServices:  []model.LocaleServices{{Id: strptr("ls1"), EdgeClusterPath: strptr(ec1)}},
+			expectedCalls:   []string{"create lb service"},
+		},
+		{
+			name:            "placement changed",
+			edgeClusterPath: ec1,
+			failoverMode:    model.Tier1_FAILOVER_MODE_PREEMPTIVE,
+			tier1:           model.Tier1{FailoverMode: strptr(model.Tier1_FAILOVER_MODE_NON_PREEMPTIVE)},
+			localeServices:  []model.LocaleServices{{Id: strptr("ls1"), EdgeClusterPath: strptr(ec2)}},
+			expectedCalls: []string{
+				"patch t1 failover mode PREEMPTIVE",
+				"patch t1/ls1 edge cluster " + ec1,
+				"create lb service",
+			},
+		},
+		{
+			name:            "no locale services",
+			edgeClusterPath: ec1,
+			expectedCalls: []string{
+				"patch t1/default edge cluster " + ec1,
+				"create lb service",
+			},
+		},
+		{
+			name:            "unknown edge cluster",
+			edgeClusterPath: ec2,
+			expectedErr:     true,
+		},
+		{
+			name:            "invalid edge cluster path",
+			edgeClusterPath: "ec1",
+			expectedErr:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			broker := &placementBroker{tier1: testCase.tier1, localeServices: testCase.localeServices}
+			cfg := &config.LBConfig{}
+			cfg.LoadBalancer.Tier1GatewayPath = "/infra/tier-1s/t1"
+			cfg.LoadBalancer.EdgeClusterPath = testCase.edgeClusterPath
+			cfg.LoadBalancer.FailoverMode = testCase.failoverMode
+
+			access, err := NewNSXTAccess(broker, cfg)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			lbService, err := access.CreateLoadBalancerService("cluster1")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if *lbService.ConnectivityPath != "/infra/tier-1s/t1" {
+				t.Errorf("unexpected connectivity path %s", *lbService.ConnectivityPath)
+			}
+			if !reflect.DeepEqual(broker.calls, testCase.expectedCalls) {
+				t.Errorf("expected calls %v, but got %v", testCase.expectedCalls, broker.calls)
+			}
+		})
+	}
+}
+
+// namingBroker returns the objects to create. Methods not needed for the
+// naming panic.
+type namingBroker struct {
+	NsxtBroker
+}
+
+func (b *namingBroker) CreateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
+	return server, nil
+}
+
+func (b *namingBroker) CreateLoadBalancerPool(pool model.LBPool) (model.LBPool, error) {
+	return pool, nil
+}
+
+func (b *namingBroker) CreateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error) {
+	return monitor, nil
+}
+
+func TestNSXTObjectNaming(t *testing.T) {
+	objectName := types.NamespacedName{Namespace: "default", Name: "web"}
+	lbName := *displayNameObject("cluster1", objectName)
+	mapping := Mapping{SourcePort: 80, NodePort: 30080, MemberPort: 30080, Protocol: corev1.ProtocolTCP}
+
+	testCases := []struct {
+		name                string
+		displayNamePrefix   string
+		descriptionTemplate string
+		expectedDisplayName string
+		expectedDescription []string
+	}{
+		{
+			name:                "defaults",
+			expectedDisplayName: "cluster:cluster1:default/web",
+			expectedDescription: []string{
+				"virtual server for cluster cluster1, service default/web created by " + AppName,
+				"pool for cluster cluster1, service default/web created by " + AppName,
+				"tcp monitor for cluster cluster1, service default/web, port 30080 created by " + AppName,
+			},
+		},
+		{
+			name:                "prefix and template",
+			displayNamePrefix:   "team-a:",
+			descriptionTemplate: "{{.Kind}} of {{.Namespace}}/{{.Name}}:{{.Port}} in {{.Cluster}}",
+			expectedDisplayName: "team-a:cluster:cluster1:default/web",
+			expectedDescription: []string{
+				"virtual server of default/web:80 in cluster1",
+				"pool of default/web:30080 in cluster1",
+				"tcp monitor of default/web:30080 in cluster1",
+			},
+		},
+		{
+			name:                "failing template",
+			descriptionTemplate: "{{.Owner}}",
+			expectedDisplayName: "cluster:cluster1:default/web",
+			expectedDescription: []string{
+				"virtual server for cluster cluster1, service default/web created by " + AppName,
+				"pool for cluster cluster1, service default/web created by " + AppName,
+				"tcp monitor for cluster cluster1, service default/web, port 30080 created by " + AppName,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cfg := &config.LBConfig{}
+			cfg.LoadBalancer.DisplayNamePrefix = testCase.displayNamePrefix
+			cfg.LoadBalancer.DescriptionTemplate = testCase.descriptionTemplate
+			access, err := NewNSXTAccess(&namingBroker{}, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			server, err := access.CreateVirtualServer("cluster1", objectName, lbName, &loadBalancerClass{}, "1.2.3.4", mapping, "lbs", "profile", nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			pool, err := access.CreatePool("cluster1", objectName, lbName, mapping, nil, nil, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			monitor, err := access.CreateTCPMonitorProfile("cluster1", objectName, lbName, mapping)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			displayNames := []string{*server.DisplayName, *pool.DisplayName}
+			for _, displayName := range displayNames {
+				if displayName != testCase.expectedDisplayName {
+					t.Errorf("expected display name %s, but got %s", testCase.expectedDisplayName, displayName)
+				}
+			}
+			if *monitor.DisplayName != testCase.expectedDisplayName+":30080" {
+				t.Errorf("unexpected monitor display name %s", *monitor.DisplayName)
+			}
+			descriptions := []string{*server.Description, *pool.Description, *monitor.Description}
+			if !reflect.DeepEqual(descriptions, testCase.expectedDescription) {
+				t.Errorf("expected descriptions %q, but got %q", testCase.expectedDescription, descriptions)
+			}
+		})
+	}
+
+	cfg := &config.LBConfig{}
+	cfg.LoadBalancer.DescriptionTemplate = "{{.Kind"
+	if _, err := NewNSXTAccess(&namingBroker{}, cfg); err == nil {
+		t.Error("expected error")
+	}
+}
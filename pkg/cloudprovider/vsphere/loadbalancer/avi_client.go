@@ -0,0 +1,359 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+const (
+	// aviRequestTimeout is the timeout of the requests to the Avi controller
+	aviRequestTimeout = 60 * time.Second
+	// aviPageSize is the number of objects listed per request
+	aviPageSize = 200
+
+	aviKindVirtualService = "virtualservice"
+	aviKindPool           = "pool"
+	aviKindVsVip          = "vsvip"
+	aviKindCloud          = "cloud"
+)
+
+// aviError is the error of a request rejected by the Avi controller
+type aviError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *aviError) Error() string {
+	return fmt.Sprintf("avi %s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// isAviNotFound returns true if the Avi object of the request does not exist
+func isAviNotFound(err error) bool {
+	var aviErr *aviError
+	return errors.As(err, &aviErr) && aviErr.StatusCode == http.StatusNotFound
+}
+
+// aviCloud is an Avi cloud
+type aviCloud struct {
+	UUID string `json:"uuid,omitempty"`
+	Name string `json:"name"`
+}
+
+// aviMarker is a marker of an Avi object, the equivalent of an NSX-T tag
+type aviMarker struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values,omitempty"`
+}
+
+// aviIPAddr is an IP address of the Avi API
+type aviIPAddr struct {
+	Addr string `json:"addr"`
+	Type string `json:"type"`
+}
+
+// aviServer is a member of an Avi pool
+type aviServer struct {
+	IP       aviIPAddr `json:"ip"`
+	Port     int       `json:"port,omitempty"`
+	Hostname string    `json:"hostname,omitempty"`
+}
+
+// aviPool is an Avi pool, the equivalent of an NSX-T LbPool
+type aviPool struct {
+	UUID              string      `json:"uuid,omitempty"`
+	Name              string      `json:"name"`
+	CloudRef          string      `json:"cloud_ref,omitempty"`
+	DefaultServerPort int         `json:"default_server_port,omitempty"`
+	Servers           []aviServer `json:"servers"`
+	HealthMonitorRefs []string    `json:"health_monitor_refs,omitempty"`
+	Markers           []aviMarker `json:"markers,omitempty"`
+	CreatedBy         string      `json:"created_by,omitempty"`
+}
+
+// aviIPAMNetworkSubnet is the network a virtual IP is allocated from
+type aviIPAMNetworkSubnet struct {
+	NetworkRef string `json:"network_ref,omitempty"`
+}
+
+// aviVip is a virtual IP of an Avi VsVip
+type aviVip struct {
+	VipID             string                `json:"vip_id"`
+	AutoAllocateIP    bool                  `json:"auto_allocate_ip"`
+	IPAddress         *aviIPAddr            `json:"ip_address,omitempty"`
+	IPAMNetworkSubnet *aviIPAMNetworkSubnet `json:"ipam_network_subnet,omitempty"`
+}
+
+// aviVsVip holds the virtual IP shared by the virtual services of a Service,
+// the equivalent of an NSX-T IP address allocation
+type aviVsVip struct {
+	UUID          string      `json:"uuid,omitempty"`
+	Name          string      `json:"name"`
+	CloudRef      string      `json:"cloud_ref,omitempty"`
+	VrfContextRef string      `json:"vrf_context_ref,omitempty"`
+	Vip           []aviVip    `json:"vip"`
+	Markers       []aviMarker `json:"markers,omitempty"`
+}
+
+// aviServicePort is a port of an Avi virtual service
+type aviServicePort struct {
+	Port int `json:"port"`
+}
+
+// aviVirtualService is an Avi virtual service, the equivalent of an NSX-T
+// virtual server
+type aviVirtualService struct {
+	UUID                  string           `json:"uuid,omitempty"`
+	Name                  string           `json:"name"`
+	CloudRef              string           `json:"cloud_ref,omitempty"`
+	SeGroupRef            string           `json:"se_group_ref,omitempty"`
+	VsVipRef              string           `json:"vsvip_ref"`
+	PoolRef               string           `json:"pool_ref"`
+	ApplicationProfileRef string           `json:"application_profile_ref,omitempty"`
+	NetworkProfileRef     string           `json:"network_profile_ref,omitempty"`
+	Services              []aviServicePort `json:"services"`
+	Markers               []aviMarker      `json:"markers,omitempty"`
+	CreatedBy             string           `json:"created_by,omitempty"`
+}
+
+// aviList is a page of a list of Avi objects
+type aviList[T any] struct {
+	Count   int    `json:"count"`
+	Results []T    `json:"results"`
+	Next    string `json:"next,omitempty"`
+}
+
+// aviClient is a client of the REST API of the Avi controller, logging in
+// with a session cookie that is renewed once it expires
+type aviClient struct {
+	baseURL    string
+	user       string
+	password   string
+	tenant     string
+	version    string
+	httpClient *http.Client
+
+	lock     sync.Mutex
+	loggedIn bool
+}
+
+// newAviClient creates a client of the Avi controller of the config
+func newAviClient(cfg *config.AviConfig) (*aviClient, error) {
+	minVersion, err := vcfg.ParseTLSMinVersion(cfg.TLSMinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := vcfg.ParseTLSCipherSuites(cfg.TLSCipherSuites)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureFlag,
+		MinVersion:         minVersion,
+		CipherSuites:       cipherSuites,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading avi CA file %s failed", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("avi CA file %s contains no certificate", cfg.CAFile)
+		}
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := strings.TrimSuffix(cfg.Controller, "/")
+	if !strings.HasPrefix(baseURL, "https://") && !strings.HasPrefix(baseURL, "http://") {
+		baseURL = "https://" + baseURL
+	}
+	return &aviClient{
+		baseURL:  baseURL,
+		user:     cfg.User,
+		password: cfg.Password,
+		tenant:   cfg.Tenant,
+		version:  cfg.Version,
+		httpClient: &http.Client{
+			Jar:       jar,
+			Timeout:   aviRequestTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// login starts a session unless it is already started
+func (c *aviClient) login(renew bool) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.loggedIn && !renew {
+		return nil
+	}
+	c.loggedIn = false
+	credentials := map[string]string{"username": c.user, "password": c.password}
+	if _, err := c.send(http.MethodPost, "/login", credentials, nil); err != nil {
+		return errors.Wrap(err, "avi login failed")
+	}
+	c.loggedIn = true
+	return nil
+}
+
+// do sends a request of the session, logging in again if the session expired
+func (c *aviClient) do(method, path string, in, out interface{}) error {
+	if err := c.login(false); err != nil {
+		return err
+	}
+	status, err := c.send(method, path, in, out)
+	if status != http.StatusUnauthorized {
+		return err
+	}
+	if err := c.login(true); err != nil {
+		return err
+	}
+	_, err = c.send(method, path, in, out)
+	return err
+}
+
+// send sends a request to the controller, decoding the response into out
+func (c *aviClient) send(method, path string, in, out interface{}) (int, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Referer", c.baseURL)
+	req.Header.Set("X-Avi-Version", c.version)
+	req.Header.Set("X-Avi-Tenant", c.tenant)
+	for _, cookie := range c.httpClient.Jar.Cookies(req.URL) {
+		if cookie.Name == "csrftoken" {
+			req.Header.Set("X-CSRFToken", cookie.Value)
+		}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "avi %s %s failed", method, path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return resp.StatusCode, &aviError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, errors.Wrapf(err, "decoding avi %s %s response failed", method, path)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// listAviObjects lists all objects of a kind, following the pages of the list
+func listAviObjects[T any](c *aviClient, kind string, query url.Values) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page_size", fmt.Sprint(aviPageSize))
+	path := "/api/" + kind + "?" + query.Encode()
+	var result []T
+	for path != "" {
+		var page aviList[T]
+		if err := c.do(http.MethodGet, path, nil, &page); err != nil {
+			return nil, errors.Wrapf(err, "listing avi %s objects failed", kind)
+		}
+		result = append(result, page.Results...)
+		path = ""
+		if page.Next != "" {
+			next, err := url.Parse(page.Next)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid next page of avi %s objects", kind)
+			}
+			path = next.RequestURI()
+		}
+	}
+	return result, nil
+}
+
+// createAviObject creates an object of a kind and returns it as created
+func createAviObject[T any](c *aviClient, kind string, obj *T) (*T, error) {
+	var created T
+	if err := c.do(http.MethodPost, "/api/"+kind, obj, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// replaceAviFields replaces the given fields of an object, keeping the others
+func (c *aviClient) replaceAviFields(kind, uuid string, fields map[string]interface{}) error {
+	return c.do(http.MethodPatch, "/api/"+kind+"/"+uuid, map[string]interface{}{"replace": fields}, nil)
+}
+
+// deleteAviObject deletes an object, ignoring it if it does not exist
+func (c *aviClient) deleteAviObject(kind, uuid string) error {
+	err := c.do(http.MethodDelete, "/api/"+kind+"/"+uuid, nil, nil)
+	if isAviNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// aviRefByName returns the reference of the object of a kind by its name
+func aviRefByName(kind, name string) string {
+	return "/api/" + kind + "/?name=" + url.QueryEscape(name)
+}
+
+// aviRefByUUID returns the reference of the object of a kind by its UUID
+func aviRefByUUID(kind, uuid string) string {
+	return "/api/" + kind + "/" + uuid
+}
+
+// aviRefUUID returns the UUID of the object of a reference, such as
+// https://controller/api/pool/pool-1234
+func aviRefUUID(ref string) string {
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
@@ -0,0 +1,630 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	klog "k8s.io/klog/v2"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+)
+
+const (
+	// aviApplicationProfile is the application profile of the virtual
+	// services, passing the TCP and UDP traffic through
+	aviApplicationProfile = "System-L4-Application"
+	// aviTCPNetworkProfile is the network profile of the TCP virtual services
+	aviTCPNetworkProfile = "System-TCP-Proxy"
+	// aviUDPNetworkProfile is the network profile of the UDP virtual services
+	aviUDPNetworkProfile = "System-UDP-Fast-Path"
+	// aviTCPHealthMonitor is the health monitor of the pools of TCP ports
+	aviTCPHealthMonitor = "System-TCP"
+)
+
+// aviUnsupportedAnnotations are the annotations of the services changing the
+// traffic of the NSX-T load balancers, which the avi backend does not
+// implement. Services with them are not provisioned instead of serving their
+// traffic differently.
+var aviUnsupportedAnnotations = []string{
+	IPAllocationNameAnnotation,
+	XForwardedForAnnotation,
+	ServerKeepAliveAnnotation,
+	TLSCertificateAnnotation,
+	TLSPortsAnnotation,
+	ClientSSLProfileAnnotation,
+}
+
+// aviLBProvider is the LBProvider of the avi backend. The virtual IP of a
+// Service is held by a VsVip, each of its TCP and UDP ports gets a virtual
+// service and a pool. They are marked like the objects of the NSX-T backend
+// with the owner, cluster, service and port, so that the orphans are found by
+// the cleanup.
+type aviLBProvider struct {
+	client *aviClient
+	avi    config.AviConfig
+	// networkRefs are the references of the Avi networks the virtual IPs of
+	// the load balancer classes are allocated from, keyed by class name
+	networkRefs map[string]string
+	// classPrefix is the prefix of the spec.loadBalancerClass of the
+	// services reconciled by the class controller
+	classPrefix  string
+	nameTemplate *template.Template
+	standardTags Tags
+	ownerTag     model.Tag
+	keyLock      *keyLock
+}
+
+var _ LBProvider = &aviLBProvider{}
+
+// newAviLBProvider creates the LBProvider of the avi backend
+func newAviLBProvider(cfg *config.LBConfig) (LBProvider, error) {
+	client, err := newAviClient(&cfg.Avi)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating avi client failed")
+	}
+	nameTemplate, err := config.ParseNameTemplate(cfg.LoadBalancer.NameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	networkRefs := map[string]string{}
+	if ref := aviNetworkRef(cfg.LoadBalancer.IPPoolName, cfg.LoadBalancer.IPPoolID); ref != "" {
+		networkRefs[config.DefaultLoadBalancerClass] = ref
+	}
+	for name, class := range cfg.LoadBalancerClass {
+		if ref := aviNetworkRef(class.IPPoolName, class.IPPoolID); ref != "" {
+			networkRefs[name] = ref
+		}
+	}
+	standardTags := Tags{
+		ScopeOwner: newTag(ScopeOwner, AppName),
+	}
+	for k, v := range cfg.LoadBalancer.AdditionalTags {
+		standardTags[k] = newTag(k, v)
+	}
+	klog.Infof("load balancers are provisioned by the avi controller %s, cloud %s", cfg.Avi.Controller, cfg.Avi.Cloud)
+	return &aviLBProvider{
+		client:       client,
+		avi:          cfg.Avi,
+		networkRefs:  networkRefs,
+		classPrefix:  cfg.LoadBalancer.ClassPrefix,
+		nameTemplate: nameTemplate,
+		standardTags: standardTags,
+		ownerTag:     standardTags[ScopeOwner],
+		keyLock:      newKeyLock(),
+	}, nil
+}
+
+// aviNetworkRef returns the reference of the Avi network given by the IP pool
+// name or ID of a load balancer class, empty if neither is set
+func aviNetworkRef(name, id string) string {
+	if id != "" {
+		return aviRefByUUID("network", id)
+	}
+	if name != "" {
+		return aviRefByName("network", name)
+	}
+	return ""
+}
+
+// aviMarkers converts the tags into the markers of an Avi object
+func aviMarkers(tags []model.Tag) []aviMarker {
+	markers := make([]aviMarker, 0, len(tags))
+	for _, tag := range tags {
+		markers = append(markers, aviMarker{Key: *tag.Scope, Values: []string{*tag.Tag}})
+	}
+	return markers
+}
+
+// aviTags converts the markers of an Avi object into tags
+func aviTags(markers []aviMarker) []model.Tag {
+	tags := make([]model.Tag, 0, len(markers))
+	for _, marker := range markers {
+		value := ""
+		if len(marker.Values) > 0 {
+			value = marker.Values[0]
+		}
+		tags = append(tags, newTag(marker.Key, value))
+	}
+	return tags
+}
+
+// Initialize starts the cleanup of the orphaned Avi objects if the cluster
+// name is given, and the class controller if the class prefix is given
+func (p *aviLBProvider) Initialize(clusterName string, client clientset.Interface, stop <-chan struct{}) {
+	if clusterName != "" {
+		go runCleanup(p, clusterName, client.CoreV1().Services(""), stop)
+	}
+	if p.classPrefix != "" {
+		factory := informers.NewSharedInformerFactory(client, classResyncPeriod)
+		controller := newClassController(p, p.ownsLoadBalancerClass, clusterName, client, factory)
+		go controller.run(stop)
+		factory.Start(stop)
+	}
+}
+
+// ownsLoadBalancerClass returns true if the spec.loadBalancerClass is
+// reconciled by the class controller.
+func (p *aviLBProvider) ownsLoadBalancerClass(loadBalancerClass string) bool {
+	_, ok := trimClassPrefix(p.classPrefix, loadBalancerClass)
+	return ok
+}
+
+// PendingReconciles returns the number of reconciles running or waiting for
+// each Service, keyed by namespace/name
+func (p *aviLBProvider) PendingReconciles() map[string]int {
+	return p.keyLock.Pending()
+}
+
+// ClassNames returns the sorted names of the load balancer classes
+func (p *aviLBProvider) ClassNames() []string {
+	names := make([]string, 0, len(p.networkRefs))
+	for name := range p.networkRefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckHealth returns an error if the cloud cannot be read from the Avi
+// controller, because it is not reachable or rejects the credentials
+func (p *aviLBProvider) CheckHealth(_ context.Context) error {
+	clouds, err := listAviObjects[aviCloud](p.client, aviKindCloud, url.Values{"name": {p.avi.Cloud}})
+	if err != nil {
+		return err
+	}
+	if len(clouds) == 0 {
+		return fmt.Errorf("avi cloud %s not found", p.avi.Cloud)
+	}
+	return nil
+}
+
+// GetLoadBalancer returns the LoadBalancerStatus of the VsVip of the service
+func (p *aviLBProvider) GetLoadBalancer(_ context.Context, clusterName string, service *corev1.Service) (*corev1.LoadBalancerStatus, bool, error) {
+	vsvips, err := p.listVsVips(clusterTag(clusterName), serviceTag(namespacedNameFromService(service)))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(vsvips) == 0 {
+		return nil, false, nil
+	}
+	return newLoadBalancerStatus(aviVsVipAddress(vsvips[0])), true, nil
+}
+
+// GetLoadBalancerName returns the name of the load balancer, the name of the
+// VsVip and the base of the names of the virtual services and pools
+func (p *aviLBProvider) GetLoadBalancerName(_ context.Context, clusterName string, service *corev1.Service) string {
+	return truncateDisplayName(renderLoadBalancerName(p.nameTemplate, clusterName, service))
+}
+
+// EnsureLoadBalancer creates or updates the VsVip, virtual services and pools
+// of the service, and deletes the ones of the ports it no longer has
+func (p *aviLBProvider) EnsureLoadBalancer(ctx context.Context, clusterName string, service *corev1.Service, nodes []*corev1.Node) (*corev1.LoadBalancerStatus, error) {
+	objectName := namespacedNameFromService(service)
+	key := objectName.String()
+	p.keyLock.Lock(key)
+	defer p.keyLock.Unlock(key)
+
+	mappings, err := newMappings(service)
+	if err != nil {
+		return nil, err
+	}
+	mappings, unsupported := splitSupportedMappings(mappings)
+	for _, mapping := range unsupported {
+		klog.Warningf("%s: ignoring port %s, Avi load balancers only support TCP and UDP", objectName, mapping)
+	}
+	if len(mappings) == 0 && len(unsupported) > 0 {
+		return nil, fmt.Errorf("no port of the service has a protocol supported by Avi load balancers, only TCP and UDP are supported")
+	}
+	if len(mappings) > 0 {
+		for _, annotation := range aviUnsupportedAnnotations {
+			if _, ok := service.GetAnnotations()[annotation]; ok {
+				return nil, fmt.Errorf("annotation %s is not supported by Avi load balancers", annotation)
+			}
+		}
+	}
+
+	required := []model.Tag{clusterTag(clusterName), serviceTag(objectName)}
+	vsvips, err := p.listVsVips(required...)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := p.listVirtualServices(required...)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := p.listPools(required...)
+	if err != nil {
+		return nil, err
+	}
+
+	var vsvip *aviVsVip
+	if len(vsvips) > 0 {
+		vsvip = vsvips[0]
+	}
+	validPorts := sets.NewString()
+	if len(mappings) > 0 {
+		if vsvip == nil {
+			vsvip, err = p.createVsVip(ctx, clusterName, service)
+			if err != nil {
+				return nil, err
+			}
+		}
+		lbName := p.GetLoadBalancerName(ctx, clusterName, service)
+		members := aviPoolServers(nodes, service)
+		for _, mapping := range mappings {
+			port := *portTag(mapping).Tag
+			validPorts.Insert(port)
+			pool := findAviObjectByPort(pools, port, func(pool *aviPool) []aviMarker { return pool.Markers })
+			if pool == nil {
+				pool, err = p.createPool(clusterName, objectName, lbName, mapping, members)
+			} else {
+				err = p.updatePoolServers(objectName, pool, mapping.MemberPort, members)
+			}
+			if err != nil {
+				return nil, err
+			}
+			server := findAviObjectByPort(servers, port, func(server *aviVirtualService) []aviMarker { return server.Markers })
+			if server == nil {
+				if _, err = p.createVirtualService(clusterName, objectName, lbName, mapping, vsvip, pool); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// the virtual services are deleted before the pools they reference
+	for _, server := range servers {
+		if port := getTag(aviTags(server.Markers), ScopePort); !validPorts.Has(port) {
+			klog.Infof("%s: deleting avi virtual service %s for %s", objectName, server.Name, port)
+			if err := p.client.deleteAviObject(aviKindVirtualService, server.UUID); err != nil {
+				return nil, errors.Wrapf(err, "deleting avi virtual service %s failed", server.Name)
+			}
+		}
+	}
+	for _, pool := range pools {
+		if port := getTag(aviTags(pool.Markers), ScopePort); !validPorts.Has(port) {
+			klog.Infof("%s: deleting avi pool %s for %s", objectName, pool.Name, port)
+			if err := p.client.deleteAviObject(aviKindPool, pool.UUID); err != nil {
+				return nil, errors.Wrapf(err, "deleting avi pool %s failed", pool.Name)
+			}
+		}
+	}
+	if len(mappings) == 0 {
+		for _, vsvip := range vsvips {
+			klog.Infof("%s: deleting avi VsVip %s", objectName, vsvip.Name)
+			if err := p.client.deleteAviObject(aviKindVsVip, vsvip.UUID); err != nil {
+				return nil, errors.Wrapf(err, "deleting avi VsVip %s failed", vsvip.Name)
+			}
+		}
+		return newLoadBalancerStatus(), nil
+	}
+
+	address := aviVsVipAddress(vsvip)
+	if address == nil {
+		return nil, fmt.Errorf("virtual IP of avi VsVip %s not allocated yet", vsvip.Name)
+	}
+	return newLoadBalancerStatus(address), nil
+}
+
+// UpdateLoadBalancer updates the servers of the pools of the service
+func (p *aviLBProvider) UpdateLoadBalancer(_ context.Context, clusterName string, service *corev1.Service, nodes []*corev1.Node) error {
+	objectName := namespacedNameFromService(service)
+	key := objectName.String()
+	p.keyLock.Lock(key)
+	defer p.keyLock.Unlock(key)
+
+	pools, err := p.listPools(clusterTag(clusterName), serviceTag(objectName))
+	if err != nil {
+		return err
+	}
+	members := aviPoolServers(nodes, service)
+	for _, pool := range pools {
+		if err := p.updatePoolServers(objectName, pool, pool.DefaultServerPort, members); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureLoadBalancerDeleted deletes the virtual services, pools and VsVip of
+// the service
+func (p *aviLBProvider) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *corev1.Service) error {
+	emptyService := service.DeepCopy()
+	emptyService.Spec.Ports = nil
+	_, err := p.EnsureLoadBalancer(ctx, clusterName, emptyService, nil)
+	return err
+}
+
+// CleanupServices deletes the Avi objects of the cluster whose Service is
+// not one of the valid services
+func (p *aviLBProvider) CleanupServices(clusterName string, validServices map[types.NamespacedName]corev1.Service, _ bool) error {
+	required := []model.Tag{p.ownerTag, clusterTag(clusterName)}
+	var markers [][]aviMarker
+	servers, err := p.listVirtualServices(required...)
+	if err != nil {
+		return err
+	}
+	for _, server := range servers {
+		markers = append(markers, server.Markers)
+	}
+	pools, err := p.listPools(required...)
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		markers = append(markers, pool.Markers)
+	}
+	vsvips, err := p.listVsVips(required...)
+	if err != nil {
+		return err
+	}
+	for _, vsvip := range vsvips {
+		markers = append(markers, vsvip.Markers)
+	}
+
+	orphans := map[types.NamespacedName]struct{}{}
+	for _, m := range markers {
+		tag := getTag(aviTags(m), ScopeService)
+		if tag == "" {
+			continue
+		}
+		objectName := parseNamespacedName(tag)
+		if _, ok := validServices[objectName]; !ok {
+			orphans[objectName] = struct{}{}
+		}
+	}
+	for objectName := range orphans {
+		klog.Infof("cleanup of avi objects of %s", objectName)
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: objectName.Namespace, Name: objectName.Name}}
+		if err := p.EnsureLoadBalancerDeleted(context.TODO(), clusterName, service); err != nil {
+			return errors.Wrapf(err, "cleanup of avi objects of %s failed", objectName)
+		}
+	}
+	return nil
+}
+
+// networkRef returns the reference of the Avi network of the load balancer
+// class of the service
+func (p *aviLBProvider) networkRef(service *corev1.Service) (string, error) {
+	var name string
+	if service.Spec.LoadBalancerClass != nil {
+		var ok bool
+		name, ok = trimClassPrefix(p.classPrefix, *service.Spec.LoadBalancerClass)
+		if !ok {
+			return "", fmt.Errorf("load balancer class %s is not managed by this controller", *service.Spec.LoadBalancerClass)
+		}
+	} else {
+		name = strings.TrimSpace(service.GetAnnotations()[LoadBalancerClassAnnotation])
+	}
+	if name == "" {
+		name = config.DefaultLoadBalancerClass
+	}
+	ref, ok := p.networkRefs[name]
+	if !ok {
+		return "", fmt.Errorf("invalid load balancer class %s", name)
+	}
+	return ref, nil
+}
+
+func (p *aviLBProvider) createVsVip(ctx context.Context, clusterName string, service *corev1.Service) (*aviVsVip, error) {
+	networkRef, err := p.networkRef(service)
+	if err != nil {
+		return nil, err
+	}
+	objectName := namespacedNameFromService(service)
+	vsvip := &aviVsVip{
+		Name:     p.GetLoadBalancerName(ctx, clusterName, service),
+		CloudRef: aviRefByName(aviKindCloud, p.avi.Cloud),
+		Vip: []aviVip{{
+			VipID:             "0",
+			AutoAllocateIP:    true,
+			IPAMNetworkSubnet: &aviIPAMNetworkSubnet{NetworkRef: networkRef},
+		}},
+		Markers: aviMarkers(p.standardTags.Append(clusterTag(clusterName), serviceTag(objectName)).Normalize()),
+	}
+	if p.avi.VRFContext != "" {
+		vsvip.VrfContextRef = aviRefByName("vrfcontext", p.avi.VRFContext)
+	}
+	klog.Infof("%s: creating avi VsVip %s", objectName, vsvip.Name)
+	created, err := createAviObject(p.client, aviKindVsVip, vsvip)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating avi VsVip for %s:%s failed", clusterName, objectName)
+	}
+	if aviVsVipAddress(created) == nil {
+		// the IP address may be allocated after the VsVip is created
+		if err := p.client.do(http.MethodGet, aviRefByUUID(aviKindVsVip, created.UUID), nil, created); err != nil {
+			return nil, errors.Wrapf(err, "reading avi VsVip %s failed", created.Name)
+		}
+	}
+	return created, nil
+}
+
+func (p *aviLBProvider) createPool(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping, members []aviServer) (*aviPool, error) {
+	pool := &aviPool{
+		Name:              truncateDisplayName(fmt.Sprintf("%s-%s-%d", lbName, strings.ToLower(string(mapping.Protocol)), mapping.SourcePort)),
+		CloudRef:          aviRefByName(aviKindCloud, p.avi.Cloud),
+		DefaultServerPort: mapping.MemberPort,
+		Servers:           withAviServerPort(members, mapping.MemberPort),
+		Markers:           aviMarkers(p.standardTags.Append(clusterTag(clusterName), serviceTag(objectName), portTag(mapping)).Normalize()),
+		CreatedBy:         AppName,
+	}
+	if mapping.Protocol == corev1.ProtocolTCP {
+		pool.HealthMonitorRefs = []string{aviRefByName("healthmonitor", aviTCPHealthMonitor)}
+	}
+	klog.Infof("%s: creating avi pool %s", objectName, pool.Name)
+	created, err := createAviObject(p.client, aviKindPool, pool)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating avi pool for %s:%s failed", clusterName, objectName)
+	}
+	return created, nil
+}
+
+// updatePoolServers replaces the servers of the pool if they differ
+func (p *aviLBProvider) updatePoolServers(objectName types.NamespacedName, pool *aviPool, port int, members []aviServer) error {
+	servers := withAviServerPort(members, port)
+	if pool.DefaultServerPort == port && equalAviServers(pool.Servers, servers) {
+		return nil
+	}
+	klog.Infof("%s: updating servers of avi pool %s", objectName, pool.Name)
+	err := p.client.replaceAviFields(aviKindPool, pool.UUID, map[string]interface{}{
+		"default_server_port": port,
+		"servers":             servers,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "updating avi pool %s failed", pool.Name)
+	}
+	pool.DefaultServerPort = port
+	pool.Servers = servers
+	return nil
+}
+
+func (p *aviLBProvider) createVirtualService(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping,
+	vsvip *aviVsVip, pool *aviPool) (*aviVirtualService, error) {
+	networkProfile := aviTCPNetworkProfile
+	if mapping.Protocol == corev1.ProtocolUDP {
+		networkProfile = aviUDPNetworkProfile
+	}
+	server := &aviVirtualService{
+		Name:                  truncateDisplayName(fmt.Sprintf("%s-%s-%d", lbName, strings.ToLower(string(mapping.Protocol)), mapping.SourcePort)),
+		CloudRef:              aviRefByName(aviKindCloud, p.avi.Cloud),
+		SeGroupRef:            aviRefByName("serviceenginegroup", p.avi.ServiceEngineGroup),
+		VsVipRef:              aviRefByUUID(aviKindVsVip, vsvip.UUID),
+		PoolRef:               aviRefByUUID(aviKindPool, pool.UUID),
+		ApplicationProfileRef: aviRefByName("applicationprofile", aviApplicationProfile),
+		NetworkProfileRef:     aviRefByName("networkprofile", networkProfile),
+		Services:              []aviServicePort{{Port: mapping.SourcePort}},
+		Markers:               aviMarkers(p.standardTags.Append(clusterTag(clusterName), serviceTag(objectName), portTag(mapping)).Normalize()),
+		CreatedBy:             AppName,
+	}
+	klog.Infof("%s: creating avi virtual service %s", objectName, server.Name)
+	created, err := createAviObject(p.client, aviKindVirtualService, server)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating avi virtual service for %s:%s failed", clusterName, objectName)
+	}
+	return created, nil
+}
+
+func (p *aviLBProvider) listVsVips(required ...model.Tag) ([]*aviVsVip, error) {
+	list, err := listAviObjects[aviVsVip](p.client, aviKindVsVip, nil)
+	if err != nil {
+		return nil, err
+	}
+	return filterAviObjects(list, func(vsvip *aviVsVip) []aviMarker { return vsvip.Markers }, append(required, p.ownerTag)), nil
+}
+
+func (p *aviLBProvider) listVirtualServices(required ...model.Tag) ([]*aviVirtualService, error) {
+	list, err := listAviObjects[aviVirtualService](p.client, aviKindVirtualService, nil)
+	if err != nil {
+		return nil, err
+	}
+	return filterAviObjects(list, func(server *aviVirtualService) []aviMarker { return server.Markers }, append(required, p.ownerTag)), nil
+}
+
+func (p *aviLBProvider) listPools(required ...model.Tag) ([]*aviPool, error) {
+	list, err := listAviObjects[aviPool](p.client, aviKindPool, nil)
+	if err != nil {
+		return nil, err
+	}
+	return filterAviObjects(list, func(pool *aviPool) []aviMarker { return pool.Markers }, append(required, p.ownerTag)), nil
+}
+
+// filterAviObjects returns the objects having the required markers
+func filterAviObjects[T any](list []T, markers func(*T) []aviMarker, required []model.Tag) []*T {
+	var result []*T
+	for i := range list {
+		if checkTags(aviTags(markers(&list[i])), required...) {
+			result = append(result, &list[i])
+		}
+	}
+	return result
+}
+
+// findAviObjectByPort returns the object marked with the port, nil if none is
+func findAviObjectByPort[T any](list []*T, port string, markers func(*T) []aviMarker) *T {
+	for _, obj := range list {
+		if getTag(aviTags(markers(obj)), ScopePort) == port {
+			return obj
+		}
+	}
+	return nil
+}
+
+// aviVsVipAddress returns the virtual IP address of the VsVip, nil if it is
+// not allocated
+func aviVsVipAddress(vsvip *aviVsVip) *string {
+	for _, vip := range vsvip.Vip {
+		if vip.IPAddress != nil && vip.IPAddress.Addr != "" {
+			return strptr(vip.IPAddress.Addr)
+		}
+	}
+	return nil
+}
+
+// aviPoolServers returns the servers of the nodes, sorted by IP address
+func aviPoolServers(nodes []*corev1.Node, service *corev1.Service) []aviServer {
+	family := serviceIPFamily(service)
+	addrType := "V4"
+	if family == corev1.IPv6Protocol {
+		addrType = "V6"
+	}
+	var servers []aviServer
+	for address, nodeName := range collectNodeInternalAddresses(nodes, family) {
+		servers = append(servers, aviServer{IP: aviIPAddr{Addr: address, Type: addrType}, Hostname: nodeName})
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].IP.Addr < servers[j].IP.Addr })
+	return servers
+}
+
+// withAviServerPort returns a copy of the servers with the port
+func withAviServerPort(members []aviServer, port int) []aviServer {
+	servers := make([]aviServer, len(members))
+	for i, member := range members {
+		member.Port = port
+		servers[i] = member
+	}
+	return servers
+}
+
+// equalAviServers returns true if both lists have the same addresses and ports
+func equalAviServers(a, b []aviServer) bool {
+	key := func(server aviServer) string { return fmt.Sprintf("%s:%d", server.IP.Addr, server.Port) }
+	keys := sets.NewString()
+	for _, server := range a {
+		keys.Insert(key(server))
+	}
+	other := sets.NewString()
+	for _, server := range b {
+		other.Insert(key(server))
+	}
+	return keys.Equal(other)
+}
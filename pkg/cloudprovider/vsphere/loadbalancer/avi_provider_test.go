@@ -0,0 +1,484 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+)
+
+// fakeAviController is an Avi controller keeping its objects in memory,
+// listing them by pages of two objects
+type fakeAviController struct {
+	lock    sync.Mutex
+	objects map[string]map[string]map[string]interface{}
+	nextID  int
+	session string
+	logins  int
+}
+
+func newFakeAviController() *fakeAviController {
+	return &fakeAviController{
+		objects: map[string]map[string]map[string]interface{}{
+			aviKindCloud: {"cloud-1": {"uuid": "cloud-1", "name": config.DefaultAviCloud}},
+		},
+	}
+}
+
+// expireSession makes the requests of the current session unauthorized
+func (f *fakeAviController) expireSession() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.session = ""
+}
+
+func (f *fakeAviController) list(kind string) []map[string]interface{} {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var list []map[string]interface{}
+	for _, obj := range f.objects[kind] {
+		list = append(list, obj)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i]["uuid"].(string) < list[j]["uuid"].(string) })
+	return list
+}
+
+func (f *fakeAviController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if r.URL.Path == "/login" {
+		f.logins++
+		f.session = fmt.Sprintf("session-%d", f.logins)
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: f.session, Path: "/"})
+		http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: "csrf-" + f.session, Path: "/"})
+		return
+	}
+	if cookie, err := r.Cookie("sessionid"); err != nil || f.session == "" || cookie.Value != f.session {
+		http.Error(w, "session expired", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet && r.Header.Get("X-CSRFToken") != "csrf-"+f.session {
+		http.Error(w, "CSRF token missing", http.StatusForbidden)
+		return
+	}
+	if r.Header.Get("X-Avi-Tenant") != config.DefaultAviTenant || r.Header.Get("X-Avi-Version") != config.DefaultAviVersion {
+		http.Error(w, "missing tenant or version", http.StatusBadRequest)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	kind := parts[0]
+	if f.objects[kind] == nil {
+		f.objects[kind] = map[string]map[string]interface{}{}
+	}
+	objects := f.objects[kind]
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			f.serveList(w, r, kind)
+		case http.MethodPost:
+			var obj map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.nextID++
+			obj["uuid"] = fmt.Sprintf("%s-%d", kind, f.nextID)
+			if kind == aviKindVsVip {
+				vip := obj["vip"].([]interface{})[0].(map[string]interface{})
+				vip["ip_address"] = map[string]interface{}{"addr": fmt.Sprintf("10.0.0.%d", f.nextID), "type": "V4"}
+			}
+			objects[obj["uuid"].(string)] = obj
+			_ = json.NewEncoder(w).Encode(obj)
+		default:
+			http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	obj, ok := objects[parts[1]]
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(obj)
+	case http.MethodPatch:
+		var patch map[string]map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for k, v := range patch["replace"] {
+			obj[k] = v
+		}
+		_ = json.NewEncoder(w).Encode(obj)
+	case http.MethodDelete:
+		// objects still referenced cannot be deleted
+		for _, server := range f.objects[aviKindVirtualService] {
+			for _, ref := range []string{"pool_ref", "vsvip_ref"} {
+				if aviRefUUID(server[ref].(string)) == parts[1] {
+					http.Error(w, "object in use", http.StatusConflict)
+					return
+				}
+			}
+		}
+		delete(objects, parts[1])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeAviController) serveList(w http.ResponseWriter, r *http.Request, kind string) {
+	var list []map[string]interface{}
+	for _, obj := range f.objects[kind] {
+		if name := r.URL.Query().Get("name"); name == "" || obj["name"] == name {
+			list = append(list, obj)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i]["uuid"].(string) < list[j]["uuid"].(string) })
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page == 0 {
+		page = 1
+	}
+	start, end := 2*(page-1), 2*page
+	result := map[string]interface{}{"count": len(list), "results": []interface{}{}}
+	if start < len(list) {
+		if end < len(list) {
+			query := r.URL.Query()
+			query.Set("page", strconv.Itoa(page+1))
+			result["next"] = "https://" + r.Host + r.URL.Path + "?" + query.Encode()
+		} else {
+			end = len(list)
+		}
+		result["results"] = list[start:end]
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func newTestAviLBProvider(t *testing.T, controller *fakeAviController) *aviLBProvider {
+	server := httptest.NewTLSServer(controller)
+	t.Cleanup(server.Close)
+	cfg := &config.LBConfig{
+		LoadBalancer: config.LoadBalancerConfig{
+			LoadBalancerClassConfig: config.LoadBalancerClassConfig{IPPoolName: "vip-network"},
+			AdditionalTags:          map[string]string{"team": "blue"},
+		},
+		LoadBalancerClass: map[string]*config.LoadBalancerClassConfig{
+			"public": {IPPoolID: "network-public"},
+		},
+		Avi: config.AviConfig{
+			Controller:         server.URL,
+			User:               "admin",
+			Password:           "secret",
+			Tenant:             config.DefaultAviTenant,
+			Version:            config.DefaultAviVersion,
+			Cloud:              config.DefaultAviCloud,
+			ServiceEngineGroup: config.DefaultAviServiceEngineGroup,
+			InsecureFlag:       true,
+		},
+	}
+	provider, err := newAviLBProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider.(*aviLBProvider)
+}
+
+func newAviTestService(name string, ports ...corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: ports,
+		},
+	}
+}
+
+func newAviTestNode(name, address string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+		},
+	}
+}
+
+func aviObjectMarker(obj map[string]interface{}, key string) string {
+	markers, _ := obj["markers"].([]interface{})
+	for _, m := range markers {
+		marker := m.(map[string]interface{})
+		if marker["key"] == key {
+			return marker["values"].([]interface{})[0].(string)
+		}
+	}
+	return ""
+}
+
+func aviPoolAddresses(pool map[string]interface{}) []string {
+	var addresses []string
+	for _, s := range pool["servers"].([]interface{}) {
+		server := s.(map[string]interface{})
+		addresses = append(addresses, fmt.Sprintf("%s:%v", server["ip"].(map[string]interface{})["addr"], server["port"]))
+	}
+	return addresses
+}
+
+func TestAviEnsureLoadBalancer(t *testing.T) {
+	controller := newFakeAviController()
+	provider := newTestAviLBProvider(t, controller)
+	ctx := context.Background()
+
+	service := newAviTestService("web",
+		corev1.ServicePort{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080},
+		corev1.ServicePort{Name: "dns", Protocol: corev1.ProtocolUDP, Port: 53, NodePort: 30053},
+		corev1.ServicePort{Name: "sctp", Protocol: corev1.ProtocolSCTP, Port: 9, NodePort: 30009},
+	)
+	nodes := []*corev1.Node{newAviTestNode("node1", "192.168.0.1"), newAviTestNode("node2", "192.168.0.2")}
+	status, err := provider.EnsureLoadBalancer(ctx, "cluster1", service, nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Ingress) != 1 || status.Ingress[0].IP != "10.0.0.1" {
+		t.Fatalf("unexpected status %v", status)
+	}
+
+	vsvips := controller.list(aviKindVsVip)
+	if len(vsvips) != 1 {
+		t.Fatalf("expected one VsVip, got %v", vsvips)
+	}
+	network := vsvips[0]["vip"].([]interface{})[0].(map[string]interface{})["ipam_network_subnet"].(map[string]interface{})["network_ref"]
+	if network != "/api/network/?name=vip-network" {
+		t.Errorf("unexpected network %v", network)
+	}
+	servers := controller.list(aviKindVirtualService)
+	pools := controller.list(aviKindPool)
+	if len(servers) != 2 || len(pools) != 2 {
+		t.Fatalf("expected two virtual services and pools, got %v and %v", servers, pools)
+	}
+	for _, obj := range append(servers, pools...) {
+		if aviObjectMarker(obj, ScopeCluster) != "cluster1" || aviObjectMarker(obj, ScopeService) != "default/web" ||
+			aviObjectMarker(obj, ScopeOwner) != AppName || aviObjectMarker(obj, "team") != "blue" {
+			t.Errorf("unexpected markers of %s: %v", obj["name"], obj["markers"])
+		}
+	}
+	for _, pool := range pools {
+		port := aviObjectMarker(pool, ScopePort)
+		expected := map[string][]string{
+			"TCP/80": {"192.168.0.1:30080", "192.168.0.2:30080"},
+			"UDP/53": {"192.168.0.1:30053", "192.168.0.2:30053"},
+		}[port]
+		if addresses := aviPoolAddresses(pool); fmt.Sprint(addresses) != fmt.Sprint(expected) {
+			t.Errorf("unexpected servers of pool %s: %v", port, addresses)
+		}
+	}
+
+	// the servers follow the nodes, even after the session expired
+	controller.expireSession()
+	if err := provider.UpdateLoadBalancer(ctx, "cluster1", service, nodes[:1]); err != nil {
+		t.Fatal(err)
+	}
+	for _, pool := range controller.list(aviKindPool) {
+		if addresses := aviPoolAddresses(pool); len(addresses) != 1 || !strings.HasPrefix(addresses[0], "192.168.0.1:") {
+			t.Errorf("unexpected servers of pool %s: %v", pool["name"], addresses)
+		}
+	}
+
+	// reconciling again keeps the objects
+	status, err = provider.EnsureLoadBalancer(ctx, "cluster1", service, nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Ingress) != 1 || status.Ingress[0].IP != "10.0.0.1" || len(controller.list(aviKindVirtualService)) != 2 {
+		t.Fatalf("unexpected status %v or virtual services %v", status, controller.list(aviKindVirtualService))
+	}
+	if status, exists, err := provider.GetLoadBalancer(ctx, "cluster1", service); err != nil || !exists || status.Ingress[0].IP != "10.0.0.1" {
+		t.Errorf("unexpected load balancer %v, %v", status, err)
+	}
+
+	// the objects of a removed port are deleted
+	service.Spec.Ports = service.Spec.Ports[:1]
+	if _, err := provider.EnsureLoadBalancer(ctx, "cluster1", service, nodes); err != nil {
+		t.Fatal(err)
+	}
+	servers = controller.list(aviKindVirtualService)
+	if len(servers) != 1 || aviObjectMarker(servers[0], ScopePort) != "TCP/80" || len(controller.list(aviKindPool)) != 1 {
+		t.Fatalf("unexpected virtual services %v", servers)
+	}
+
+	if err := provider.EnsureLoadBalancerDeleted(ctx, "cluster1", service); err != nil {
+		t.Fatal(err)
+	}
+	if len(controller.list(aviKindVirtualService))+len(controller.list(aviKindPool))+len(controller.list(aviKindVsVip)) != 0 {
+		t.Errorf("expected all objects to be deleted")
+	}
+	if _, exists, err := provider.GetLoadBalancer(ctx, "cluster1", service); err != nil || exists {
+		t.Errorf("expected no load balancer, got %v, %v", exists, err)
+	}
+}
+
+func TestAviLoadBalancerClass(t *testing.T) {
+	controller := newFakeAviController()
+	provider := newTestAviLBProvider(t, controller)
+
+	service := newAviTestService("web", corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080})
+	service.Annotations = map[string]string{LoadBalancerClassAnnotation: "public"}
+	if _, err := provider.EnsureLoadBalancer(context.Background(), "cluster1", service, nil); err != nil {
+		t.Fatal(err)
+	}
+	vsvips := controller.list(aviKindVsVip)
+	network := vsvips[0]["vip"].([]interface{})[0].(map[string]interface{})["ipam_network_subnet"].(map[string]interface{})["network_ref"]
+	if network != "/api/network/network-public" {
+		t.Errorf("unexpected network %v", network)
+	}
+
+	service.Annotations[LoadBalancerClassAnnotation] = "private"
+	service.Name = "other"
+	if _, err := provider.EnsureLoadBalancer(context.Background(), "cluster1", service, nil); err == nil {
+		t.Errorf("expected error for unknown class")
+	}
+	if names := provider.ClassNames(); fmt.Sprint(names) != "[default public]" {
+		t.Errorf("unexpected class names %v", names)
+	}
+
+	// spec.loadBalancerClass names the class after the class prefix
+	provider.classPrefix = "avi.cpi.vsphere/"
+	service = newAviTestService("api", corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30081})
+	service.Spec.LoadBalancerClass = strptr("avi.cpi.vsphere/public")
+	if !provider.ownsLoadBalancerClass(*service.Spec.LoadBalancerClass) {
+		t.Errorf("expected class %s to be owned", *service.Spec.LoadBalancerClass)
+	}
+	if _, err := provider.EnsureLoadBalancer(context.Background(), "cluster1", service, nil); err != nil {
+		t.Fatal(err)
+	}
+	vsvips = controller.list(aviKindVsVip)
+	network = vsvips[len(vsvips)-1]["vip"].([]interface{})[0].(map[string]interface{})["ipam_network_subnet"].(map[string]interface{})["network_ref"]
+	if network != "/api/network/network-public" {
+		t.Errorf("unexpected network %v", network)
+	}
+
+	service.Name = "foreign"
+	service.Spec.LoadBalancerClass = strptr("example.com/public")
+	if provider.ownsLoadBalancerClass(*service.Spec.LoadBalancerClass) {
+		t.Errorf("expected class %s not to be owned", *service.Spec.LoadBalancerClass)
+	}
+	if _, err := provider.EnsureLoadBalancer(context.Background(), "cluster1", service, nil); err == nil {
+		t.Errorf("expected error for class without the class prefix")
+	}
+}
+
+func TestAviUnsupportedAnnotations(t *testing.T) {
+	controller := newFakeAviController()
+	provider := newTestAviLBProvider(t, controller)
+	ctx := context.Background()
+
+	service := newAviTestService("web", corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: 443, NodePort: 30443})
+	if _, err := provider.EnsureLoadBalancer(ctx, "cluster1", service, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, annotation := range aviUnsupportedAnnotations {
+		service.Annotations = map[string]string{annotation: "value"}
+		if _, err := provider.EnsureLoadBalancer(ctx, "cluster1", service, nil); err == nil {
+			t.Errorf("expected error for annotation %s", annotation)
+		}
+	}
+	// the load balancer is still deleted
+	if err := provider.EnsureLoadBalancerDeleted(ctx, "cluster1", service); err != nil {
+		t.Fatal(err)
+	}
+	if vsvips := controller.list(aviKindVsVip); len(vsvips) != 0 {
+		t.Errorf("expected no VsVip, got %v", vsvips)
+	}
+}
+
+func TestNewAviClientTLSSettings(t *testing.T) {
+	client, err := newAviClient(&config.AviConfig{
+		Controller:      "avi-controller",
+		TLSMinVersion:   "1.2",
+		TLSCipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := client.httpClient.Transport.(*http.Transport).TLSClientConfig
+	if tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("expected TLS 1.2 minimum version, got %x", tlsConfig.MinVersion)
+	}
+	if len(tlsConfig.CipherSuites) != 1 || tlsConfig.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("unexpected cipher suites %v", tlsConfig.CipherSuites)
+	}
+
+	if _, err := newAviClient(&config.AviConfig{Controller: "avi-controller", TLSMinVersion: "2.0"}); err == nil {
+		t.Error("expected error for invalid TLS version")
+	}
+}
+
+func TestAviCleanupServices(t *testing.T) {
+	controller := newFakeAviController()
+	provider := newTestAviLBProvider(t, controller)
+	ctx := context.Background()
+
+	for _, name := range []string{"kept", "orphan"} {
+		service := newAviTestService(name, corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080})
+		if _, err := provider.EnsureLoadBalancer(ctx, "cluster1", service, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// objects of other clusters are not touched
+	other := newAviTestService("orphan", corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080})
+	if _, err := provider.EnsureLoadBalancer(ctx, "cluster2", other, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[types.NamespacedName]corev1.Service{{Namespace: "default", Name: "kept"}: {}}
+	if err := provider.CleanupServices("cluster1", valid, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range []string{aviKindVirtualService, aviKindPool, aviKindVsVip} {
+		objects := controller.list(kind)
+		if len(objects) != 2 {
+			t.Fatalf("expected two %s objects, got %v", kind, objects)
+		}
+		for _, obj := range objects {
+			cluster, service := aviObjectMarker(obj, ScopeCluster), aviObjectMarker(obj, ScopeService)
+			if cluster == "cluster1" && service != "default/kept" {
+				t.Errorf("orphaned %s %s not deleted", kind, obj["name"])
+			}
+		}
+	}
+	if err := provider.CheckHealth(ctx); err != nil {
+		t.Errorf("unexpected health check error %v", err)
+	}
+}
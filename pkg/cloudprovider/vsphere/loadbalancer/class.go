@@ -21,6 +21,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
 
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 
@@ -37,10 +38,32 @@ type loadBalancerClass struct {
 	ipPool        Reference
 	tcpAppProfile Reference
 	udpAppProfile Reference
+	// ipv6Pool is the IP pool of the IPv6 virtual servers of dual-stack
+	// services, empty if the class has none
+	ipv6Pool Reference
+	// xForwardedFor is the NSX-T X-Forwarded-For handling of the HTTP
+	// application profiles of the services, empty if they use tcpAppProfile
+	xForwardedFor   string
+	serverKeepAlive bool
+	// clientSSLProfilePath is the client SSL profile of the virtual servers
+	// terminating TLS, empty for the default one of NSX-T
+	clientSSLProfilePath string
+	// zones are the placements of the virtual servers of the services whose
+	// endpoints all run in a zone, keyed by zone name
+	zones map[string]*loadBalancerZone
+	// zone is the zone of the virtual servers, empty if they are attached to
+	// the LbService of the cluster
+	zone string
 
 	tags []model.Tag
 }
 
+// loadBalancerZone is the placement of the virtual servers in a zone
+type loadBalancerZone struct {
+	tier1GatewayPath string
+	ipPool           Reference
+}
+
 func setupClasses(access NSXTAccess, cfg *config.LBConfig) (*loadBalancerClasses, error) {
 	if !config.LoadBalancerSizes.Has(cfg.LoadBalancer.Size) {
 		return nil, fmt.Errorf("invalid load balancer size %s", cfg.LoadBalancer.Size)
@@ -95,6 +118,23 @@ func (c *loadBalancerClasses) add(class *loadBalancerClass) {
 	c.classes[class.className] = class
 }
 
+// prefetchAppProfiles resolves the application profiles of the classes, so
+// that the first Services of a class do not wait for the profiles configured
+// by name. Failures are only logged, the profiles are resolved again on use.
+func prefetchAppProfiles(access NSXTAccess, classes *loadBalancerClasses) {
+	for _, name := range classes.GetClassNames() {
+		class := classes.GetClass(name)
+		for _, protocol := range []corev1.Protocol{corev1.ProtocolTCP, corev1.ProtocolUDP} {
+			if profile, _ := class.AppProfile(protocol); profile.Identifier != "" || profile.Name == "" {
+				continue
+			}
+			if _, err := access.GetAppProfilePath(class, protocol); err != nil {
+				klog.Warningf("resolving %s application profile of load balancer class %s failed: %s", protocol, name, err)
+			}
+		}
+	}
+}
+
 type ipPoolResolver struct {
 	access       NSXTAccess
 	knownIPPools map[string]string
@@ -133,6 +173,17 @@ func newLBClass(name string, classConfig *config.LoadBalancerClassConfig, defaul
 			Identifier: classConfig.UDPAppProfilePath,
 			Name:       classConfig.UDPAppProfileName,
 		},
+		ipv6Pool: Reference{
+			Identifier: classConfig.IPv6PoolID,
+			Name:       classConfig.IPv6PoolName,
+		},
+		serverKeepAlive:      classConfig.ServerKeepAlive,
+		clientSSLProfilePath: classConfig.ClientSSLProfilePath,
+	}
+	var err error
+	class.xForwardedFor, err = parseXForwardedFor(classConfig.XForwardedFor)
+	if err != nil {
+		return nil, err
 	}
 	if defaults != nil {
 		if class.ipPool.IsEmpty() {
@@ -144,27 +195,115 @@ func newLBClass(name string, classConfig *config.LoadBalancerClassConfig, defaul
 		if class.udpAppProfile.IsEmpty() {
 			class.udpAppProfile = defaults.udpAppProfile
 		}
+		if class.ipv6Pool.IsEmpty() {
+			class.ipv6Pool = defaults.ipv6Pool
+		}
+		if classConfig.XForwardedFor == "" {
+			class.xForwardedFor = defaults.xForwardedFor
+			class.serverKeepAlive = defaults.serverKeepAlive
+		}
+		if class.clientSSLProfilePath == "" {
+			class.clientSSLProfilePath = defaults.clientSSLProfilePath
+		}
+		if len(classConfig.Zones) == 0 {
+			class.zones = defaults.zones
+		}
+		class.zone = defaults.zone
 	}
 	if resolver != nil {
-		err := resolver.resolve(&class.ipPool)
+		err = resolver.resolve(&class.ipPool)
 		if err != nil {
 			return nil, err
 		}
-	} else if class.ipPool.Identifier == "" {
+		if !class.ipv6Pool.IsEmpty() {
+			err = resolver.resolve(&class.ipv6Pool)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if class.ipPool.Identifier == "" || (!class.ipv6Pool.IsEmpty() && class.ipv6Pool.Identifier == "") {
 		return nil, fmt.Errorf("ipPoolResolver needed if IP pool ID not provided")
 	}
+	if len(classConfig.Zones) > 0 {
+		class.zones, err = newLBZones(classConfig.Zones, class.ipPool, resolver)
+		if err != nil {
+			return nil, err
+		}
+	}
 	class.tags = []model.Tag{
 		newTag(ScopeIPPoolID, class.ipPool.Identifier),
 		newTag(ScopeLBClass, class.className),
 	}
+	if class.zone != "" {
+		class.tags = append(class.tags, zoneTag(class.zone))
+	}
 
 	return &class, nil
 }
 
+func newLBZones(zonesConfig map[string]*config.LoadBalancerZoneConfig, ipPool Reference, resolver *ipPoolResolver) (map[string]*loadBalancerZone, error) {
+	zones := make(map[string]*loadBalancerZone, len(zonesConfig))
+	for name, zoneConfig := range zonesConfig {
+		zone := &loadBalancerZone{
+			tier1GatewayPath: zoneConfig.Tier1GatewayPath,
+			ipPool: Reference{
+				Identifier: zoneConfig.IPPoolID,
+				Name:       zoneConfig.IPPoolName,
+			},
+		}
+		if zone.ipPool.IsEmpty() {
+			zone.ipPool = ipPool
+		}
+		if resolver != nil {
+			if err := resolver.resolve(&zone.ipPool); err != nil {
+				return nil, errors.Wrapf(err, "zone %s", name)
+			}
+		} else if zone.ipPool.Identifier == "" {
+			return nil, fmt.Errorf("ipPoolResolver needed if IP pool ID of zone %s not provided", name)
+		}
+		zones[name] = zone
+	}
+	return zones, nil
+}
+
 func (c *loadBalancerClass) Tags() []model.Tag {
 	return c.tags
 }
 
+// ipv6Class returns the class of the IPv6 virtual servers, tagged with the
+// IPv6 pool instead of the IPv4 one
+func (c *loadBalancerClass) ipv6Class() *loadBalancerClass {
+	class := *c
+	class.ipPool = c.ipv6Pool
+	class.tags = []model.Tag{
+		newTag(ScopeIPPoolID, c.ipv6Pool.Identifier),
+		newTag(ScopeLBClass, c.className),
+		newTag(ScopeIPFamily, string(corev1.IPv6Protocol)),
+	}
+	if c.zone != "" {
+		class.tags = append(class.tags, zoneTag(c.zone))
+	}
+	return &class
+}
+
+// zoneClass returns the class of the virtual servers in the zone, allocating
+// the addresses from the IP pool of the zone and tagged with the zone. The
+// IP pool of the class is kept if the zone is no longer configured, so that
+// the existing virtual servers of the zone can still be updated and deleted.
+func (c *loadBalancerClass) zoneClass(zone string) *loadBalancerClass {
+	class := *c
+	class.zone = zone
+	if placement, ok := c.zones[zone]; ok {
+		class.ipPool = placement.ipPool
+	}
+	class.tags = []model.Tag{
+		newTag(ScopeIPPoolID, class.ipPool.Identifier),
+		newTag(ScopeLBClass, c.className),
+		zoneTag(zone),
+	}
+	return &class
+}
+
 func (c *loadBalancerClass) AppProfile(protocol corev1.Protocol) (Reference, error) {
 	switch protocol {
 	case corev1.ProtocolTCP:
@@ -0,0 +1,254 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	cloudprovider "k8s.io/cloud-provider"
+	servicehelper "k8s.io/cloud-provider/service/helpers"
+	klog "k8s.io/klog/v2"
+)
+
+// classResyncPeriod is the period all the Services of the managed classes
+// are reconciled again, for instance to retry failed deletions
+const classResyncPeriod = 10 * time.Minute
+
+// classController reconciles the Services of type LoadBalancer with a
+// spec.loadBalancerClass managed by the load balancer provider. The service
+// controller of the cloud controller manager skips these Services, as they
+// are left to the controller implementing their class, so that the Services
+// of other load balancer controllers are ignored.
+// The artefacts of Services deleted while the controller was not running are
+// removed by the cleanup.
+type classController struct {
+	lb cloudprovider.LoadBalancer
+	// owns returns true if the spec.loadBalancerClass is managed
+	owns        func(loadBalancerClass string) bool
+	clusterName string
+	client      clientset.Interface
+
+	servicesLister corelisters.ServiceLister
+	nodesLister    corelisters.NodeLister
+	synced         []cache.InformerSynced
+
+	workqueue workqueue.RateLimitingInterface
+}
+
+func newClassController(lb cloudprovider.LoadBalancer, owns func(string) bool, clusterName string,
+	client clientset.Interface, factory informers.SharedInformerFactory) *classController {
+	services := factory.Core().V1().Services()
+	nodes := factory.Core().V1().Nodes()
+	c := &classController{
+		lb:          lb,
+		owns:        owns,
+		clusterName: clusterName,
+		client:      client,
+
+		servicesLister: services.Lister(),
+		nodesLister:    nodes.Lister(),
+
+		workqueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LoadBalancerClass"),
+	}
+
+	servicesHandler, _ := services.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueService,
+		UpdateFunc: func(old, cur interface{}) {
+			// the class is wiped when the type is no longer LoadBalancer
+			if c.ownsService(old) {
+				c.enqueueService(old)
+			} else {
+				c.enqueueService(cur)
+			}
+		},
+		DeleteFunc: c.enqueueService,
+	})
+	nodesHandler, _ := nodes.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { c.enqueueAllServices() },
+		UpdateFunc: func(old, cur interface{}) {
+			if isDrainingNode(old.(*corev1.Node)) != isDrainingNode(cur.(*corev1.Node)) {
+				c.enqueueAllServices()
+			}
+		},
+		DeleteFunc: func(interface{}) { c.enqueueAllServices() },
+	})
+	// the handlers have seen the initial lists once synced
+	c.synced = []cache.InformerSynced{servicesHandler.HasSynced, nodesHandler.HasSynced}
+	return c
+}
+
+func (c *classController) ownsService(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	service, ok := obj.(*corev1.Service)
+	return ok && service.Spec.LoadBalancerClass != nil && c.owns(*service.Spec.LoadBalancerClass)
+}
+
+func (c *classController) enqueueService(obj interface{}) {
+	if !c.ownsService(obj) {
+		return
+	}
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+func (c *classController) enqueueAllServices() {
+	services, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, service := range services {
+		c.enqueueService(service)
+	}
+}
+
+// run starts the worker reconciling the Services until stop is closed
+func (c *classController) run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	if !cache.WaitForNamedCacheSync("load balancer class", stop, c.synced...) {
+		return
+	}
+	go wait.Until(c.runWorker, time.Second, stop)
+
+	<-stop
+}
+
+func (c *classController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *classController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+	if err := c.syncService(key); err != nil {
+		c.workqueue.AddRateLimited(key)
+		utilruntime.HandleError(fmt.Errorf("error syncing service %s: %v, requeuing", key, err))
+		return true
+	}
+	c.workqueue.Forget(obj)
+	return true
+}
+
+// syncService ensures the load balancer of the Service exists if it wants
+// one, and is deleted otherwise
+func (c *classController) syncService(key string) error {
+	ctx := context.Background()
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	service, err := c.servicesLister.Services(namespace).Get(name)
+	switch {
+	case apierrors.IsNotFound(err):
+		klog.V(2).Infof("deleting load balancer of deleted service %s", key)
+		deleted := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		return c.lb.EnsureLoadBalancerDeleted(ctx, c.clusterName, deleted)
+	case err != nil:
+		return err
+	}
+
+	if !c.wantsLoadBalancer(service) {
+		klog.V(2).Infof("deleting load balancer of service %s", key)
+		if err := c.lb.EnsureLoadBalancerDeleted(ctx, c.clusterName, service); err != nil {
+			return err
+		}
+		return c.updateStatus(ctx, service, &corev1.LoadBalancerStatus{})
+	}
+
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var members []*corev1.Node
+	for _, node := range nodes {
+		if !isExcludedNode(node) {
+			members = append(members, node)
+		}
+	}
+	status, err := c.lb.EnsureLoadBalancer(ctx, c.clusterName, service, members)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		status = &corev1.LoadBalancerStatus{}
+	}
+	return c.updateStatus(ctx, service, status)
+}
+
+func (c *classController) wantsLoadBalancer(service *corev1.Service) bool {
+	return service.DeletionTimestamp == nil &&
+		service.Spec.Type == corev1.ServiceTypeLoadBalancer &&
+		service.Spec.LoadBalancerClass != nil && c.owns(*service.Spec.LoadBalancerClass)
+}
+
+func (c *classController) updateStatus(ctx context.Context, service *corev1.Service, status *corev1.LoadBalancerStatus) error {
+	if servicehelper.LoadBalancerStatusEqual(&service.Status.LoadBalancer, status) {
+		return nil
+	}
+	updated := service.DeepCopy()
+	updated.Status.LoadBalancer = *status
+	_, err := c.client.CoreV1().Services(service.Namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// isExcludedNode returns true if the node must not be a member of the pools,
+// as it has the exclusion label of Kubernetes or the ExcludeNodeAnnotation
+func isExcludedNode(node *corev1.Node) bool {
+	if _, excluded := node.Labels[corev1.LabelNodeExcludeBalancers]; excluded {
+		return true
+	}
+	excluded, _ := strconv.ParseBool(strings.TrimSpace(node.Annotations[ExcludeNodeAnnotation]))
+	return excluded
+}
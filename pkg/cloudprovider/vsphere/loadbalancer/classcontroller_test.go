@@ -0,0 +1,157 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+const testClassPrefix = "nsx-t.cpi.vsphere/"
+
+type classLoadBalancer struct {
+	cloudprovider.LoadBalancer
+	ensured []string
+	members []string
+	deleted []string
+}
+
+func (lb *classLoadBalancer) EnsureLoadBalancer(_ context.Context, _ string, service *corev1.Service, nodes []*corev1.Node) (*corev1.LoadBalancerStatus, error) {
+	lb.ensured = append(lb.ensured, service.Name)
+	for _, node := range nodes {
+		lb.members = append(lb.members, node.Name)
+	}
+	return newLoadBalancerStatus(strPtr("10.0.0.1")), nil
+}
+
+func (lb *classLoadBalancer) EnsureLoadBalancerDeleted(_ context.Context, _ string, service *corev1.Service) error {
+	lb.deleted = append(lb.deleted, service.Name)
+	return nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func classService(name string, loadBalancerClass *string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: corev1.ServiceSpec{
+			Type:              corev1.ServiceTypeLoadBalancer,
+			LoadBalancerClass: loadBalancerClass,
+		},
+	}
+}
+
+func TestClassFromService(t *testing.T) {
+	p := &lbProvider{
+		classes: &loadBalancerClasses{classes: map[string]*loadBalancerClass{
+			"default": {className: "default"},
+			"gold":    {className: "gold"},
+		}},
+		classPrefix: testClassPrefix,
+	}
+
+	testCases := []struct {
+		name              string
+		annotation        string
+		loadBalancerClass *string
+		expected          string
+	}{
+		{name: "default", expected: "default"},
+		{name: "annotation", annotation: "gold", expected: "gold"},
+		{name: "spec", loadBalancerClass: strPtr(testClassPrefix + "gold"), expected: "gold"},
+		{name: "spec overrides annotation", annotation: "gold", loadBalancerClass: strPtr(testClassPrefix), expected: "default"},
+		{name: "foreign spec", loadBalancerClass: strPtr("example.com/gold")},
+		{name: "unknown class", loadBalancerClass: strPtr(testClassPrefix + "silver")},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := classService("web", testCase.loadBalancerClass)
+			if testCase.annotation != "" {
+				service.Annotations = map[string]string{LoadBalancerClassAnnotation: testCase.annotation}
+			}
+			class, err := p.classFromService(service)
+			if testCase.expected == "" {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, testCase.expected, class.className)
+		})
+	}
+
+	// the class controller is not run without prefix
+	p.classPrefix = ""
+	assert.False(t, p.ownsLoadBalancerClass(testClassPrefix+"gold"))
+}
+
+func TestClassController(t *testing.T) {
+	owned := classService("owned", strPtr(testClassPrefix+"gold"))
+	foreign := classService("foreign", strPtr("example.com/gold"))
+	unclassed := classService("unclassed", nil)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	excluded := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node2",
+		Labels: map[string]string{corev1.LabelNodeExcludeBalancers: ""},
+	}}
+	client := fake.NewSimpleClientset(owned, foreign, unclassed, node, excluded)
+
+	p := &lbProvider{classPrefix: testClassPrefix}
+	lb := &classLoadBalancer{}
+	factory := informers.NewSharedInformerFactory(client, 0)
+	c := newClassController(lb, p.ownsLoadBalancerClass, "cluster1", client, factory)
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	if !cache.WaitForCacheSync(stop, c.synced...) {
+		t.Fatal("caches not synced")
+	}
+
+	// only the Service of the managed class is queued
+	assert.Equal(t, 1, c.workqueue.Len())
+	key, _ := c.workqueue.Get()
+	assert.Equal(t, "default/owned", key)
+	c.workqueue.Done(key)
+
+	if err := c.syncService("default/owned"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"owned"}, lb.ensured)
+	assert.Equal(t, []string{"node1"}, lb.members)
+	updated, err := client.CoreV1().Services("default").Get(context.Background(), "owned", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "10.0.0.1", updated.Status.LoadBalancer.Ingress[0].IP)
+
+	// deleted Services are deleted by name
+	if err := c.syncService("default/gone"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"gone"}, lb.deleted)
+}
@@ -31,8 +31,8 @@ import (
 
 const maxPeriod = 30 * time.Minute
 
-// cleanup is used to cleanup obsolete and potentially forgotten objects
-// created by the loadbalancer controller in NSX-T. This should not
+// runCleanup is used to cleanup obsolete and potentially forgotten objects
+// created by the loadbalancer controller in NSX-T or Avi. This should not
 // happen, but if users play with finalizers or some error condition
 // appears in the controller there might be orphaned objects in the
 // infrastructure. This is important for higher level automations like
@@ -45,7 +45,7 @@ const maxPeriod = 30 * time.Minute
 // to identify all elements originally created by this controller. By
 // comparing this set with the actually required objects it is possible
 // to identify those that are orphaned and safely delete them.
-func (p *lbProvider) cleanup(clusterName string, client clientcorev1.ServiceInterface, stop <-chan struct{}) {
+func runCleanup(provider LBProvider, clusterName string, client clientcorev1.ServiceInterface, stop <-chan struct{}) {
 	timer := time.NewTimer(1 * time.Second)
 	lastErrNext := 0 * time.Second
 	for {
@@ -54,7 +54,7 @@ func (p *lbProvider) cleanup(clusterName string, client clientcorev1.ServiceInte
 			return
 		case <-timer.C:
 			var next time.Duration
-			err := p.doCleanupStep(clusterName, client)
+			err := doCleanupStep(provider, clusterName, client)
 			if err == nil {
 				next = maxPeriod
 				lastErrNext = 0
@@ -75,7 +75,7 @@ func (p *lbProvider) cleanup(clusterName string, client clientcorev1.ServiceInte
 	}
 }
 
-func (p *lbProvider) doCleanupStep(clusterName string, client clientcorev1.ServiceInterface) error {
+func doCleanupStep(provider LBProvider, clusterName string, client clientcorev1.ServiceInterface) error {
 	klog.Infof("starting cleanup...")
 	list, err := client.List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
@@ -89,22 +89,20 @@ func (p *lbProvider) doCleanupStep(clusterName string, client clientcorev1.Servi
 		}
 	}
 
-	return p.CleanupServices(clusterName, services, false)
+	return provider.CleanupServices(clusterName, services, false)
 }
 
 func (p *lbProvider) CleanupServices(clusterName string, validServices map[types.NamespacedName]corev1.Service, ensureLBServiceDeleted bool) error {
-	ipPoolIds := sets.NewString()
-	for _, name := range p.classes.GetClassNames() {
-		class := p.classes.GetClass(name)
-		ipPoolIds.Insert(class.ipPool.Identifier)
-	}
+	ipPoolIds := p.classIPPoolIds()
 
 	lbs := map[types.NamespacedName]struct{}{}
+	usage := usageAccounting{}
 	servers, err := p.access.ListVirtualServers(clusterName)
 	if err != nil {
 		return err
 	}
 	for _, server := range servers {
+		usage.addVirtualServer(server)
 		tag := getTag(server.Tags, ScopeService)
 		if tag != "" {
 			lbs[parseNamespacedName(tag)] = struct{}{}
@@ -119,6 +117,7 @@ func (p *lbProvider) CleanupServices(clusterName string, validServices map[types
 		return err
 	}
 	for _, pool := range pools {
+		usage.addPool(pool)
 		tag := getTag(pool.Tags, ScopeService)
 		if tag != "" {
 			lbs[parseNamespacedName(tag)] = struct{}{}
@@ -136,19 +135,97 @@ func (p *lbProvider) CleanupServices(clusterName string, validServices map[types
 		}
 	}
 
+	udpMonitors, err := p.access.ListUDPMonitorProfiles(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range udpMonitors {
+		tag := getTag(monitor.Tags, ScopeService)
+		if tag != "" {
+			lbs[parseNamespacedName(tag)] = struct{}{}
+		}
+	}
+
+	httpMonitors, err := p.access.ListHTTPMonitorProfiles(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range httpMonitors {
+		tag := getTag(monitor.Tags, ScopeService)
+		if tag != "" {
+			lbs[parseNamespacedName(tag)] = struct{}{}
+		}
+	}
+
+	httpProfiles, err := p.access.ListHTTPAppProfiles(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, profile := range httpProfiles {
+		tag := getTag(profile.Tags, ScopeService)
+		if tag != "" {
+			lbs[parseNamespacedName(tag)] = struct{}{}
+		}
+	}
+
+	// the named IP address allocations are kept as long as a service names them
+	ipAllocNames := sets.NewString()
+	for _, svc := range validServices {
+		ipAllocNames.Insert(ipAllocationName(&svc))
+	}
+	ipAllocNames.Delete("")
+
 	for ipPoolID := range ipPoolIds {
 		ipAddressAllocs, err := p.access.ListExternalIPAddresses(ipPoolID, clusterName)
 		if err != nil {
 			return err
 		}
 		for _, ipAddressAlloc := range ipAddressAllocs {
+			usage.addVIP(ipAddressAlloc)
 			tag := getTag(ipAddressAlloc.Tags, ScopeService)
 			if tag != "" {
 				lbs[parseNamespacedName(tag)] = struct{}{}
 			}
+			released := getTag(ipAddressAlloc.Tags, ScopeReleased)
+			if name := getTag(ipAddressAlloc.Tags, ScopeIPAllocationName); name != "" {
+				if ipAllocNames.Has(name) {
+					continue
+				}
+				if released == "" {
+					// orphaned, as its service was deleted without
+					// releasing it or no longer names it
+					klog.Infof("holding orphaned IP address allocation %s", name)
+					err = p.access.HoldExternalIPAddress(ipPoolID, ipAddressAlloc, time.Now())
+					if err != nil {
+						return err
+					}
+					continue
+				}
+				// named allocations are kept for a recreated service
+				// naming them, unless their retention is configured
+				if p.namedAllocationRetention == 0 || !holdExpired(released, p.namedAllocationRetention) {
+					continue
+				}
+				klog.Infof("releasing named IP address allocation %s no service named for %s", name, p.namedAllocationRetention)
+				err = p.access.ReleaseExternalIPAddress(ipPoolID, *ipAddressAlloc.Id)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if released != "" && holdExpired(released, p.releaseQuarantine) {
+				klog.Infof("releasing held IP address allocation %s", *ipAddressAlloc.Id)
+				err = p.access.ReleaseExternalIPAddress(ipPoolID, *ipAddressAlloc.Id)
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
+	p.reportUsage(usage)
+	p.checkIPPoolUsage(ipPoolIds.List())
+
 	klog.Infof("cleanup: %d existing services, artefacts for %d services", len(validServices), len(lbs))
 	for lb := range lbs {
 		if svc, ok := validServices[lb]; !ok || svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
@@ -172,6 +249,48 @@ func (p *lbProvider) CleanupServices(clusterName string, validServices map[types
 		if err != nil && !isNotFoundError(err) {
 			return errors.Wrap(err, "removeLoadBalancerServiceIfUnused failed")
 		}
+		for _, zone := range p.zoneNames() {
+			err = p.removeZonalLoadBalancerServiceIfUnused(clusterName, zone)
+			if err != nil && !isNotFoundError(err) {
+				return errors.Wrapf(err, "removeZonalLoadBalancerServiceIfUnused failed for zone %s", zone)
+			}
+		}
 	}
 	return nil
 }
+
+// classIPPoolIds returns the IP pools of the load balancer classes and their
+// zones
+func (p *lbProvider) classIPPoolIds() sets.String {
+	ipPoolIds := sets.NewString()
+	for _, name := range p.classes.GetClassNames() {
+		class := p.classes.GetClass(name)
+		ipPoolIds.Insert(class.ipPool.Identifier, class.ipv6Pool.Identifier)
+		for _, zone := range class.zones {
+			ipPoolIds.Insert(zone.ipPool.Identifier)
+		}
+	}
+	return ipPoolIds
+}
+
+// zoneNames returns the sorted names of the zones of the load balancer classes
+func (p *lbProvider) zoneNames() []string {
+	zones := sets.NewString()
+	for _, name := range p.classes.GetClassNames() {
+		for zone := range p.classes.GetClass(name).zones {
+			zones.Insert(zone)
+		}
+	}
+	return zones.List()
+}
+
+// holdExpired checks whether an IP address allocation held since released
+// has been held for the given duration. Allocations with an invalid release
+// time are considered expired.
+func holdExpired(released string, d time.Duration) bool {
+	releasedAt, err := time.Parse(time.RFC3339, released)
+	if err != nil {
+		return true
+	}
+	return time.Since(releasedAt) >= d
+}
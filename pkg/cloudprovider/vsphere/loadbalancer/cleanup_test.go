@@ -0,0 +1,176 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// releaseAccess lists the given IP address allocations and records the
+// released and held ones. Methods not needed by the cleanup panic.
+type releaseAccess struct {
+	NSXTAccess
+	allocations []*model.IpAddressAllocation
+	released    []string
+	held        []string
+}
+
+func (a *releaseAccess) ListVirtualServers(string) ([]*model.LBVirtualServer, error) {
+	return nil, nil
+}
+
+func (a *releaseAccess) ListPools(string) ([]*model.LBPool, error) {
+	return nil, nil
+}
+
+func (a *releaseAccess) ListTCPMonitorProfiles(string) ([]*model.LBTcpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *releaseAccess) ListUDPMonitorProfiles(string) ([]*model.LBUdpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *releaseAccess) ListHTTPMonitorProfiles(string) ([]*model.LBHttpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *releaseAccess) ListHTTPAppProfiles(string) ([]*model.LBHttpProfile, error) {
+	return nil, nil
+}
+
+func (a *releaseAccess) ListExternalIPAddresses(string, string) ([]*model.IpAddressAllocation, error) {
+	return a.allocations, nil
+}
+
+func (a *releaseAccess) ReleaseExternalIPAddress(_ string, id string) error {
+	a.released = append(a.released, id)
+	return nil
+}
+
+func (a *releaseAccess) HoldExternalIPAddress(_ string, allocation *model.IpAddressAllocation, _ time.Time) error {
+	a.held = append(a.held, *allocation.Id)
+	return nil
+}
+
+func TestCleanupReleasesExpiredHolds(t *testing.T) {
+	now := time.Now()
+	heldAllocation := func(id string, released string) *model.IpAddressAllocation {
+		return &model.IpAddressAllocation{
+			Id:   strptr(id),
+			Tags: []model.Tag{clusterTag("cluster1"), newTag(ScopeReleased, released)},
+		}
+	}
+	access := &releaseAccess{
+		allocations: []*model.IpAddressAllocation{
+			heldAllocation("expired", now.Add(-2*time.Hour).UTC().Format(time.RFC3339)),
+			heldAllocation("quarantined", now.Add(-30*time.Minute).UTC().Format(time.RFC3339)),
+			heldAllocation("invalid", "yesterday"),
+			{
+				Id:   strptr("in-use"),
+				Tags: []model.Tag{clusterTag("cluster1"), serviceTag(types.NamespacedName{Namespace: "default", Name: "web"})},
+			},
+		},
+	}
+	lbService := newLbService(access, "")
+	lbService.releaseQuarantine = time.Hour
+	p := &lbProvider{
+		lbService: lbService,
+		classes: &loadBalancerClasses{classes: map[string]*loadBalancerClass{
+			"default": {className: "default", ipPool: Reference{Identifier: "pool"}},
+		}},
+	}
+
+	services := map[types.NamespacedName]corev1.Service{
+		{Namespace: "default", Name: "web"}: {Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+	}
+	if err := p.CleanupServices("cluster1", services, false); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"expired", "invalid"}; !reflect.DeepEqual(access.released, expected) {
+		t.Errorf("expected released allocations %v, but got %v", expected, access.released)
+	}
+}
+
+func TestCleanupHoldsOrphanedNamedAllocations(t *testing.T) {
+	now := time.Now()
+	namedAllocation := func(id, name string, tags ...model.Tag) *model.IpAddressAllocation {
+		return &model.IpAddressAllocation{
+			Id:   strptr(id),
+			Tags: append([]model.Tag{clusterTag("cluster1"), ipAllocationNameTag(name)}, tags...),
+		}
+	}
+	web := types.NamespacedName{Namespace: "default", Name: "web"}
+	api := types.NamespacedName{Namespace: "default", Name: "api"}
+	services := map[types.NamespacedName]corev1.Service{
+		web: {
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{IPAllocationNameAnnotation: "web-vip"}},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		},
+		api: {Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+	}
+
+	testCases := []struct {
+		name      string
+		retention time.Duration
+		released  []string
+	}{
+		{name: "never released by default"},
+		{name: "retention not expired", retention: 3 * time.Hour},
+		{name: "retention expired", retention: time.Hour, released: []string{"held"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			access := &releaseAccess{
+				allocations: []*model.IpAddressAllocation{
+					namedAllocation("named", "web-vip", serviceTag(web)),
+					namedAllocation("renamed", "api-vip", serviceTag(api)),
+					// allocated before the named allocations were bound to their service
+					namedAllocation("orphaned", "old-vip"),
+					namedAllocation("held", "held-vip", newTag(ScopeReleased, now.Add(-2*time.Hour).UTC().Format(time.RFC3339))),
+					namedAllocation("reused", "web-vip", newTag(ScopeReleased, now.Add(-2*time.Hour).UTC().Format(time.RFC3339))),
+				},
+			}
+			p := &lbProvider{
+				lbService: newLbService(access, ""),
+				classes: &loadBalancerClasses{classes: map[string]*loadBalancerClass{
+					"default": {className: "default", ipPool: Reference{Identifier: "pool"}},
+				}},
+			}
+			// the release quarantine must not release named allocations
+			p.releaseQuarantine = time.Minute
+			p.namedAllocationRetention = tc.retention
+
+			if err := p.CleanupServices("cluster1", services, false); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if expected := []string{"renamed", "orphaned"}; !reflect.DeepEqual(access.held, expected) {
+				t.Errorf("expected held allocations %v, but got %v", expected, access.held)
+			}
+			if !reflect.DeepEqual(access.released, tc.released) {
+				t.Errorf("expected released allocations %v, but got %v", tc.released, access.released)
+			}
+		})
+	}
+}
@@ -18,10 +18,21 @@ package config
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	klog "k8s.io/klog/v2"
 )
 
+func init() {
+	vcfg.RegisterConfigSchema(LBConfigINI{}, LBConfigYAML{})
+}
+
 /*
 	TODO:
 	When the INI based cloud-config is deprecated, this functions below should be preserved
@@ -40,6 +51,121 @@ func (cfg *LoadBalancerConfig) IsEmpty() bool {
 		cfg.Tier1GatewayPath == ""
 }
 
+// ProvisioningDeadlineDuration returns the parsed ProvisioningDeadline, 0 if
+// unset.
+func (cfg *LoadBalancerConfig) ProvisioningDeadlineDuration() (time.Duration, error) {
+	return parseDuration("provisioning deadline", cfg.ProvisioningDeadline)
+}
+
+// ReleaseQuarantineDuration returns the parsed ReleaseQuarantine, 0 if unset.
+func (cfg *LoadBalancerConfig) ReleaseQuarantineDuration() (time.Duration, error) {
+	return parseDuration("release quarantine", cfg.ReleaseQuarantine)
+}
+
+// NamedAllocationRetentionDuration returns the parsed
+// NamedAllocationRetention, 0 if unset.
+func (cfg *LoadBalancerConfig) NamedAllocationRetentionDuration() (time.Duration, error) {
+	return parseDuration("named allocation retention", cfg.NamedAllocationRetention)
+}
+
+// ParseNameTemplate parses the NameTemplate, nil if unset.
+func ParseNameTemplate(value string) (*template.Template, error) {
+	return parseTemplate("name", value)
+}
+
+// ParseDescriptionTemplate parses the DescriptionTemplate, nil if unset.
+func ParseDescriptionTemplate(value string) (*template.Template, error) {
+	return parseTemplate("description", value)
+}
+
+func parseTemplate(name, value string) (*template.Template, error) {
+	if value == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid load balancer %s template: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+// ParseUsageReportConfigMap splits the UsageReportConfigMap into namespace
+// and name, both empty if unset.
+func ParseUsageReportConfigMap(value string) (string, string, error) {
+	if value == "" {
+		return "", "", nil
+	}
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid load balancer usage report config map %q, must be <namespace>/<name>", value)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ParseIPPoolUsageThresholds parses the IPPoolUsageThresholds into sorted
+// percentages, nil if unset.
+func ParseIPPoolUsageThresholds(value string) ([]float64, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var thresholds []float64
+	for _, item := range strings.Split(value, ",") {
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil || threshold <= 0 || threshold > 100 {
+			return nil, fmt.Errorf("invalid load balancer IP pool usage threshold %q, must be a percentage between 0 and 100", item)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	sort.Float64s(thresholds)
+	return thresholds, nil
+}
+
+// nsxtOption is an option only implemented by the nsxt backend, named as in
+// the config format
+type nsxtOption struct {
+	name string
+	set  bool
+}
+
+// validateAviOptions returns an error naming the first of the options set,
+// as the avi backend would ignore them. prefix names the section of the
+// options in the error.
+func validateAviOptions(prefix string, options ...nsxtOption) error {
+	for _, option := range options {
+		if option.set {
+			return fmt.Errorf("%soption %s is not supported by the avi backend", prefix, option.name)
+		}
+	}
+	return nil
+}
+
+// ValidateClassPrefix checks that the ClassPrefix is a domain followed by a
+// slash, as required for the values of spec.loadBalancerClass.
+func ValidateClassPrefix(value string) error {
+	if value == "" {
+		return nil
+	}
+	domain, rest, found := strings.Cut(value, "/")
+	if !found || rest != "" || len(validation.IsDNS1123Subdomain(domain)) > 0 {
+		return fmt.Errorf("invalid load balancer class prefix %q, must be a domain followed by a slash, such as nsx-t.cpi.vsphere/", value)
+	}
+	return nil
+}
+
+func parseDuration(option, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid load balancer %s %q: %v", option, value, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid load balancer %s %q: must not be negative", option, value)
+	}
+	return duration, nil
+}
+
 /*
 	TODO:
 	When the INI based cloud-config is deprecated, the references to the
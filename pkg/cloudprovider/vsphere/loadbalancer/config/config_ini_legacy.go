@@ -25,6 +25,8 @@ import (
 	"gopkg.in/gcfg.v1"
 
 	klog "k8s.io/klog/v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 )
 
 /*
@@ -46,22 +48,62 @@ func (lbc *LBConfigINI) CreateConfig() *LBConfig {
 	cfg.LoadBalancer.TCPAppProfilePath = lbc.LoadBalancer.TCPAppProfilePath
 	cfg.LoadBalancer.UDPAppProfileName = lbc.LoadBalancer.UDPAppProfileName
 	cfg.LoadBalancer.UDPAppProfilePath = lbc.LoadBalancer.UDPAppProfilePath
+	cfg.LoadBalancer.IPv6PoolName = lbc.LoadBalancer.IPv6PoolName
+	cfg.LoadBalancer.IPv6PoolID = lbc.LoadBalancer.IPv6PoolID
+	cfg.LoadBalancer.XForwardedFor = lbc.LoadBalancer.XForwardedFor
+	cfg.LoadBalancer.ServerKeepAlive = lbc.LoadBalancer.ServerKeepAlive
+	cfg.LoadBalancer.ClientSSLProfilePath = lbc.LoadBalancer.ClientSSLProfilePath
 	//LoadBalancerClassConfig -> LoadBalancerConfig
+	cfg.LoadBalancer.Backend = lbc.LoadBalancer.Backend
 	cfg.LoadBalancer.Size = lbc.LoadBalancer.Size
 	cfg.LoadBalancer.LBServiceID = lbc.LoadBalancer.LBServiceID
 	cfg.LoadBalancer.Tier1GatewayPath = lbc.LoadBalancer.Tier1GatewayPath
+	cfg.LoadBalancer.EdgeClusterPath = lbc.LoadBalancer.EdgeClusterPath
+	cfg.LoadBalancer.FailoverMode = lbc.LoadBalancer.FailoverMode
 	cfg.LoadBalancer.SnatDisabled = lbc.LoadBalancer.SnatDisabled
+	cfg.LoadBalancer.ReachabilityCheck = lbc.LoadBalancer.ReachabilityCheck
+	cfg.LoadBalancer.ProvisioningDeadline = lbc.LoadBalancer.ProvisioningDeadline
+	cfg.LoadBalancer.ReleaseQuarantine = lbc.LoadBalancer.ReleaseQuarantine
+	cfg.LoadBalancer.NamedAllocationRetention = lbc.LoadBalancer.NamedAllocationRetention
+	cfg.LoadBalancer.DisplayNamePrefix = lbc.LoadBalancer.DisplayNamePrefix
+	cfg.LoadBalancer.NameTemplate = lbc.LoadBalancer.NameTemplate
+	cfg.LoadBalancer.DescriptionTemplate = lbc.LoadBalancer.DescriptionTemplate
+	cfg.LoadBalancer.UsageReportConfigMap = lbc.LoadBalancer.UsageReportConfigMap
+	cfg.LoadBalancer.IPPoolUsageThresholds = lbc.LoadBalancer.IPPoolUsageThresholds
+	cfg.LoadBalancer.ClassPrefix = lbc.LoadBalancer.ClassPrefix
+	cfg.LoadBalancer.PublishNodePortMappings = lbc.LoadBalancer.PublishNodePortMappings
+	cfg.LoadBalancer.PreviousClusterName = lbc.LoadBalancer.PreviousClusterName
+	cfg.LoadBalancer.DryRun = lbc.LoadBalancer.DryRun
 	cfg.LoadBalancer.AdditionalTags = lbc.LoadBalancer.AdditionalTags
 
+	//Avi
+	cfg.Avi.Controller = lbc.Avi.Controller
+	cfg.Avi.User = lbc.Avi.User
+	cfg.Avi.Password = lbc.Avi.Password
+	cfg.Avi.Tenant = lbc.Avi.Tenant
+	cfg.Avi.Version = lbc.Avi.Version
+	cfg.Avi.Cloud = lbc.Avi.Cloud
+	cfg.Avi.VRFContext = lbc.Avi.VRFContext
+	cfg.Avi.ServiceEngineGroup = lbc.Avi.ServiceEngineGroup
+	cfg.Avi.InsecureFlag = lbc.Avi.InsecureFlag
+	cfg.Avi.CAFile = lbc.Avi.CAFile
+	cfg.Avi.TLSMinVersion = lbc.Avi.TLSMinVersion
+	cfg.Avi.TLSCipherSuites = lbc.Avi.TLSCipherSuites
+
 	//LoadBalancerClass
 	for key, value := range lbc.LoadBalancerClass {
 		cfg.LoadBalancerClass[key] = &LoadBalancerClassConfig{
-			IPPoolName:        value.IPPoolName,
-			IPPoolID:          value.IPPoolID,
-			TCPAppProfileName: value.TCPAppProfileName,
-			TCPAppProfilePath: value.TCPAppProfilePath,
-			UDPAppProfileName: value.UDPAppProfileName,
-			UDPAppProfilePath: value.UDPAppProfilePath,
+			IPPoolName:           value.IPPoolName,
+			IPPoolID:             value.IPPoolID,
+			TCPAppProfileName:    value.TCPAppProfileName,
+			TCPAppProfilePath:    value.TCPAppProfilePath,
+			UDPAppProfileName:    value.UDPAppProfileName,
+			UDPAppProfilePath:    value.UDPAppProfilePath,
+			IPv6PoolName:         value.IPv6PoolName,
+			IPv6PoolID:           value.IPv6PoolID,
+			XForwardedFor:        value.XForwardedFor,
+			ServerKeepAlive:      value.ServerKeepAlive,
+			ClientSSLProfilePath: value.ClientSSLProfilePath,
 		}
 	}
 
@@ -73,25 +115,55 @@ func (lbc *LBConfigINI) isEnabled() bool {
 }
 
 func (lbc *LBConfigINI) validateConfig() error {
-	if lbc.LoadBalancer.LBServiceID == "" && lbc.LoadBalancer.Tier1GatewayPath == "" {
-		msg := "either load balancer service id or T1 gateway path required"
+	switch lbc.LoadBalancer.Backend {
+	case "", BackendNSXT:
+		if err := lbc.validateNSXTConfig(); err != nil {
+			return err
+		}
+	case BackendAvi:
+		if err := lbc.Avi.validateConfig(); err != nil {
+			return err
+		}
+		if err := lbc.validateAviOptions(); err != nil {
+			klog.Error(err)
+			return err
+		}
+	default:
+		msg := fmt.Sprintf("load balancer backend is invalid. Valid values are: %s", strings.Join(Backends.List(), ","))
 		klog.Errorf(msg)
 		return errors.New(msg)
 	}
-	if lbc.LoadBalancer.TCPAppProfileName == "" && lbc.LoadBalancer.TCPAppProfilePath == "" {
-		msg := "either load balancer TCP application profile name or path required"
-		klog.Errorf(msg)
-		return errors.New(msg)
+	if _, err := parseDuration("provisioning deadline", lbc.LoadBalancer.ProvisioningDeadline); err != nil {
+		klog.Error(err)
+		return err
 	}
-	if lbc.LoadBalancer.UDPAppProfileName == "" && lbc.LoadBalancer.UDPAppProfilePath == "" {
-		msg := "either load balancer UDP application profile name or path required"
-		klog.Errorf(msg)
-		return errors.New(msg)
+	if _, err := parseDuration("release quarantine", lbc.LoadBalancer.ReleaseQuarantine); err != nil {
+		klog.Error(err)
+		return err
 	}
-	if !LoadBalancerSizes.Has(lbc.LoadBalancer.Size) {
-		msg := fmt.Sprintf("load balancer size is invalid. Valid values are: %s", strings.Join(LoadBalancerSizes.List(), ","))
-		klog.Errorf(msg)
-		return errors.New(msg)
+	if _, err := parseDuration("named allocation retention", lbc.LoadBalancer.NamedAllocationRetention); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := ParseNameTemplate(lbc.LoadBalancer.NameTemplate); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := ParseDescriptionTemplate(lbc.LoadBalancer.DescriptionTemplate); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, _, err := ParseUsageReportConfigMap(lbc.LoadBalancer.UsageReportConfigMap); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := ParseIPPoolUsageThresholds(lbc.LoadBalancer.IPPoolUsageThresholds); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if err := ValidateClassPrefix(lbc.LoadBalancer.ClassPrefix); err != nil {
+		klog.Error(err)
+		return err
 	}
 	if lbc.LoadBalancer.IPPoolID == "" && lbc.LoadBalancer.IPPoolName == "" {
 		class, ok := lbc.LoadBalancerClass[DefaultLoadBalancerClass]
@@ -111,13 +183,146 @@ func (lbc *LBConfigINI) validateConfig() error {
 			return errors.New(msg)
 		}
 	}
+	if lbc.LoadBalancer.IPv6PoolName != "" && lbc.LoadBalancer.IPv6PoolID != "" {
+		msg := "either load balancer ipv6PoolName or ipv6PoolID can be set"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// validateNSXTConfig validates the options only used by the nsxt backend
+func (lbc *LBConfigINI) validateNSXTConfig() error {
+	if lbc.LoadBalancer.LBServiceID == "" && lbc.LoadBalancer.Tier1GatewayPath == "" {
+		msg := "either load balancer service id or T1 gateway path required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if lbc.LoadBalancer.TCPAppProfileName == "" && lbc.LoadBalancer.TCPAppProfilePath == "" {
+		msg := "either load balancer TCP application profile name or path required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if lbc.LoadBalancer.UDPAppProfileName == "" && lbc.LoadBalancer.UDPAppProfilePath == "" {
+		msg := "either load balancer UDP application profile name or path required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if (lbc.LoadBalancer.EdgeClusterPath != "" || lbc.LoadBalancer.FailoverMode != "") &&
+		(lbc.LoadBalancer.LBServiceID != "" || lbc.LoadBalancer.Tier1GatewayPath == "") {
+		msg := "edge cluster path and failover mode require the T1 gateway path without load balancer service id"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if lbc.LoadBalancer.FailoverMode != "" && !FailoverModes.Has(lbc.LoadBalancer.FailoverMode) {
+		msg := fmt.Sprintf("load balancer failover mode is invalid. Valid values are: %s", strings.Join(FailoverModes.List(), ","))
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if !LoadBalancerSizes.Has(lbc.LoadBalancer.Size) {
+		msg := fmt.Sprintf("load balancer size is invalid. Valid values are: %s", strings.Join(LoadBalancerSizes.List(), ","))
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// setDefaults sets the default tenant, API version, cloud and service engine
+// group if unset
+func (avi *AviConfigINI) setDefaults() {
+	if avi.Tenant == "" {
+		avi.Tenant = DefaultAviTenant
+	}
+	if avi.Version == "" {
+		avi.Version = DefaultAviVersion
+	}
+	if avi.Cloud == "" {
+		avi.Cloud = DefaultAviCloud
+	}
+	if avi.ServiceEngineGroup == "" {
+		avi.ServiceEngineGroup = DefaultAviServiceEngineGroup
+	}
+}
+
+// validateAviOptions rejects the options of the LoadBalancer section and the
+// load balancer classes only implemented by the nsxt backend.
+func (lbc *LBConfigINI) validateAviOptions() error {
+	lb := &lbc.LoadBalancer
+	if err := validateAviOptions("load balancer ",
+		nsxtOption{"size", lb.Size != ""},
+		nsxtOption{"lb-service-id", lb.LBServiceID != ""},
+		nsxtOption{"tier1-gateway-path", lb.Tier1GatewayPath != ""},
+		nsxtOption{"edge-cluster-path", lb.EdgeClusterPath != ""},
+		nsxtOption{"failover-mode", lb.FailoverMode != ""},
+		nsxtOption{"snat-disabled", lb.SnatDisabled},
+		nsxtOption{"reachability-check", lb.ReachabilityCheck},
+		nsxtOption{"provisioning-deadline", lb.ProvisioningDeadline != ""},
+		nsxtOption{"release-quarantine", lb.ReleaseQuarantine != ""},
+		nsxtOption{"named-allocation-retention", lb.NamedAllocationRetention != ""},
+		nsxtOption{"display-name-prefix", lb.DisplayNamePrefix != ""},
+		nsxtOption{"description-template", lb.DescriptionTemplate != ""},
+		nsxtOption{"usage-report-config-map", lb.UsageReportConfigMap != ""},
+		nsxtOption{"ip-pool-usage-thresholds", lb.IPPoolUsageThresholds != ""},
+		nsxtOption{"publish-node-port-mappings", lb.PublishNodePortMappings},
+		nsxtOption{"previous-cluster-name", lb.PreviousClusterName != ""},
+		nsxtOption{"dry-run", lb.DryRun},
+		nsxtOption{"tcp-app-profile-name", lb.TCPAppProfileName != ""},
+		nsxtOption{"tcp-app-profile-path", lb.TCPAppProfilePath != ""},
+		nsxtOption{"udp-app-profile-name", lb.UDPAppProfileName != ""},
+		nsxtOption{"udp-app-profile-path", lb.UDPAppProfilePath != ""},
+		nsxtOption{"ipv6-pool-name", lb.IPv6PoolName != ""},
+		nsxtOption{"ipv6-pool-id", lb.IPv6PoolID != ""},
+		nsxtOption{"x-forwarded-for", lb.XForwardedFor != ""},
+		nsxtOption{"server-keep-alive", lb.ServerKeepAlive},
+		nsxtOption{"client-ssl-profile-path", lb.ClientSSLProfilePath != ""},
+	); err != nil {
+		return err
+	}
+	for name, class := range lbc.LoadBalancerClass {
+		if err := validateAviOptions(fmt.Sprintf("load balancer class %s: ", name),
+			nsxtOption{"tcp-app-profile-name", class.TCPAppProfileName != ""},
+			nsxtOption{"tcp-app-profile-path", class.TCPAppProfilePath != ""},
+			nsxtOption{"udp-app-profile-name", class.UDPAppProfileName != ""},
+			nsxtOption{"udp-app-profile-path", class.UDPAppProfilePath != ""},
+			nsxtOption{"ipv6-pool-name", class.IPv6PoolName != ""},
+			nsxtOption{"ipv6-pool-id", class.IPv6PoolID != ""},
+			nsxtOption{"x-forwarded-for", class.XForwardedFor != ""},
+			nsxtOption{"server-keep-alive", class.ServerKeepAlive},
+			nsxtOption{"client-ssl-profile-path", class.ClientSSLProfilePath != ""},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateConfig validates the access to the Avi controller
+func (avi *AviConfigINI) validateConfig() error {
+	if avi.Controller == "" {
+		msg := "avi controller required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if avi.User == "" || avi.Password == "" {
+		msg := "avi user and password required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if _, err := vcfg.ParseTLSMinVersion(avi.TLSMinVersion); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := vcfg.ParseTLSCipherSuites(avi.TLSCipherSuites); err != nil {
+		klog.Error(err)
+		return err
+	}
 	return nil
 }
 
 func (lbc *LoadBalancerConfigINI) isEmpty() bool {
 	return lbc.Size == "" && lbc.LBServiceID == "" &&
 		lbc.IPPoolID == "" && lbc.IPPoolName == "" &&
-		lbc.Tier1GatewayPath == ""
+		lbc.Tier1GatewayPath == "" && lbc.Backend == ""
 }
 
 // CompleteAndValidate sets default values, overrides by env and validates the resulting config
@@ -136,6 +341,9 @@ func (lbc *LBConfigINI) CompleteAndValidate() error {
 	if lbc.LoadBalancerClass == nil {
 		lbc.LoadBalancerClass = map[string]*LoadBalancerClassConfigINI{}
 	}
+	if lbc.LoadBalancer.Backend == BackendAvi {
+		lbc.Avi.setDefaults()
+	}
 	for _, class := range lbc.LoadBalancerClass {
 		if class.IPPoolName == "" {
 			class.IPPoolName = lbc.LoadBalancer.IPPoolName
@@ -143,6 +351,10 @@ func (lbc *LBConfigINI) CompleteAndValidate() error {
 		if class.IPPoolID == "" {
 			class.IPPoolID = lbc.LoadBalancer.IPPoolID
 		}
+		if class.IPv6PoolName == "" && class.IPv6PoolID == "" {
+			class.IPv6PoolName = lbc.LoadBalancer.IPv6PoolName
+			class.IPv6PoolID = lbc.LoadBalancer.IPv6PoolID
+		}
 	}
 
 	return lbc.validateConfig()
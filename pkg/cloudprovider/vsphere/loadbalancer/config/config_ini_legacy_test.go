@@ -17,6 +17,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,12 +32,14 @@ func TestReadINIConfig(t *testing.T) {
 	contents := `
 [LoadBalancer]
 ip-pool-name = pool1
+ipv6-pool-name = pool6
 size = MEDIUM
 lb-service-id = 4711
 tier1-gateway-path = 1234
 tcp-app-profile-name = default-tcp-lb-app-profile
 udp-app-profile-name = default-udp-lb-app-profile
 snat-disabled = false
+dry-run = true
 tags = {\"tag1\": \"value1\", \"tag2\": \"value 2\"}
 
 [LoadBalancerClass "public"]
@@ -46,6 +49,9 @@ ip-pool-name = poolPublic
 ip-pool-name = poolPrivate
 tcp-app-profile-name = tcp2
 udp-app-profile-name = udp2
+x-forwarded-for = insert
+server-keep-alive = true
+client-ssl-profile-path = /infra/lb-client-ssl-profiles/strict
 `
 	config, err := ReadRawConfigINI([]byte(contents))
 	if err != nil {
@@ -59,18 +65,24 @@ udp-app-profile-name = udp2
 		}
 	}
 	assertEquals("LoadBalancer.ipPoolName", config.LoadBalancer.IPPoolName, "pool1")
+	assertEquals("LoadBalancer.ipv6PoolName", config.LoadBalancer.IPv6PoolName, "pool6")
 	assertEquals("LoadBalancer.lbServiceId", config.LoadBalancer.LBServiceID, "4711")
 	assertEquals("LoadBalancer.tier1GatewayPath", config.LoadBalancer.Tier1GatewayPath, "1234")
 	assertEquals("LoadBalancer.tcpAppProfileName", config.LoadBalancer.TCPAppProfileName, "default-tcp-lb-app-profile")
 	assertEquals("LoadBalancer.udpAppProfileName", config.LoadBalancer.UDPAppProfileName, "default-udp-lb-app-profile")
 	assertEquals("LoadBalancer.size", config.LoadBalancer.Size, "MEDIUM")
 	assert.Equal(t, false, config.LoadBalancer.SnatDisabled)
+	assert.Equal(t, true, config.LoadBalancer.DryRun)
 	if len(config.LoadBalancerClass) != 2 {
 		t.Errorf("expected two LoadBalancerClass subsections, but got %d", len(config.LoadBalancerClass))
 	}
 	assertEquals("LoadBalancerClass.public.ipPoolName", config.LoadBalancerClass["public"].IPPoolName, "poolPublic")
+	assertEquals("LoadBalancerClass.public.ipv6PoolName", config.LoadBalancerClass["public"].IPv6PoolName, "pool6")
 	assertEquals("LoadBalancerClass.private.tcpAppProfileName", config.LoadBalancerClass["private"].TCPAppProfileName, "tcp2")
 	assertEquals("LoadBalancerClass.private.udpAppProfileName", config.LoadBalancerClass["private"].UDPAppProfileName, "udp2")
+	assertEquals("LoadBalancerClass.private.xForwardedFor", config.LoadBalancerClass["private"].XForwardedFor, "insert")
+	assertEquals("LoadBalancerClass.private.clientSSLProfilePath", config.LoadBalancerClass["private"].ClientSSLProfilePath, "/infra/lb-client-ssl-profiles/strict")
+	assert.Equal(t, true, config.LoadBalancerClass["private"].ServerKeepAlive)
 	if len(config.LoadBalancer.AdditionalTags) != 2 || config.LoadBalancer.AdditionalTags["tag1"] != "value1" || config.LoadBalancer.AdditionalTags["tag2"] != "value 2" {
 		t.Errorf("unexpected additionalTags %v", config.LoadBalancer.AdditionalTags)
 	}
@@ -103,3 +115,34 @@ snat-disabled = false
 	assertEquals("LoadBalancer.udp-app-profile-path", config.LoadBalancer.UDPAppProfilePath, "infra/xxx/udp1234")
 	assert.Equal(t, false, config.LoadBalancer.SnatDisabled)
 }
+
+func TestReadINIConfigAvi(t *testing.T) {
+	contents := `
+[LoadBalancer]
+backend = avi
+ip-pool-name = vip-network
+
+[Avi]
+controller = avi-controller
+user = admin
+password = secret
+cloud = vcenter-cloud
+insecure-flag = true
+`
+	config, err := ReadConfigINI([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, BackendAvi, config.LoadBalancer.Backend)
+	assert.Equal(t, "avi-controller", config.Avi.Controller)
+	assert.Equal(t, "vcenter-cloud", config.Avi.Cloud)
+	assert.Equal(t, DefaultAviServiceEngineGroup, config.Avi.ServiceEngineGroup)
+	assert.Equal(t, true, config.Avi.InsecureFlag)
+
+	for _, option := range []string{"release-quarantine = 1h", "previous-cluster-name = old-cluster", "ipv6-pool-name = vip-network-v6"} {
+		invalid := strings.Replace(contents, "backend = avi", "backend = avi\n"+option, 1)
+		if _, err := ReadConfigINI([]byte(invalid)); err == nil {
+			t.Errorf("expected error for %q", option)
+		}
+	}
+}
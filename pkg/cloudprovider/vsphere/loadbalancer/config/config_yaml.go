@@ -23,6 +23,8 @@ import (
 
 	yaml "gopkg.in/yaml.v2"
 	klog "k8s.io/klog/v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 )
 
 /*
@@ -45,22 +47,73 @@ func (lbc *LBConfigYAML) CreateConfig() *LBConfig {
 	cfg.LoadBalancer.TCPAppProfilePath = lbc.LoadBalancer.TCPAppProfilePath
 	cfg.LoadBalancer.UDPAppProfileName = lbc.LoadBalancer.UDPAppProfileName
 	cfg.LoadBalancer.UDPAppProfilePath = lbc.LoadBalancer.UDPAppProfilePath
+	cfg.LoadBalancer.IPv6PoolName = lbc.LoadBalancer.IPv6PoolName
+	cfg.LoadBalancer.IPv6PoolID = lbc.LoadBalancer.IPv6PoolID
+	cfg.LoadBalancer.XForwardedFor = lbc.LoadBalancer.XForwardedFor
+	cfg.LoadBalancer.ServerKeepAlive = lbc.LoadBalancer.ServerKeepAlive
+	cfg.LoadBalancer.ClientSSLProfilePath = lbc.LoadBalancer.ClientSSLProfilePath
 	//LoadBalancerClassConfig -> LoadBalancerConfig
+	cfg.LoadBalancer.Backend = lbc.LoadBalancer.Backend
 	cfg.LoadBalancer.Size = lbc.LoadBalancer.Size
 	cfg.LoadBalancer.LBServiceID = lbc.LoadBalancer.LBServiceID
 	cfg.LoadBalancer.Tier1GatewayPath = lbc.LoadBalancer.Tier1GatewayPath
+	cfg.LoadBalancer.EdgeClusterPath = lbc.LoadBalancer.EdgeClusterPath
+	cfg.LoadBalancer.FailoverMode = lbc.LoadBalancer.FailoverMode
 	cfg.LoadBalancer.SnatDisabled = lbc.LoadBalancer.SnatDisabled
+	cfg.LoadBalancer.ReachabilityCheck = lbc.LoadBalancer.ReachabilityCheck
+	cfg.LoadBalancer.ProvisioningDeadline = lbc.LoadBalancer.ProvisioningDeadline
+	cfg.LoadBalancer.ReleaseQuarantine = lbc.LoadBalancer.ReleaseQuarantine
+	cfg.LoadBalancer.NamedAllocationRetention = lbc.LoadBalancer.NamedAllocationRetention
+	cfg.LoadBalancer.DisplayNamePrefix = lbc.LoadBalancer.DisplayNamePrefix
+	cfg.LoadBalancer.NameTemplate = lbc.LoadBalancer.NameTemplate
+	cfg.LoadBalancer.DescriptionTemplate = lbc.LoadBalancer.DescriptionTemplate
+	cfg.LoadBalancer.UsageReportConfigMap = lbc.LoadBalancer.UsageReportConfigMap
+	cfg.LoadBalancer.IPPoolUsageThresholds = lbc.LoadBalancer.IPPoolUsageThresholds
+	cfg.LoadBalancer.ClassPrefix = lbc.LoadBalancer.ClassPrefix
+	cfg.LoadBalancer.PublishNodePortMappings = lbc.LoadBalancer.PublishNodePortMappings
+	cfg.LoadBalancer.PreviousClusterName = lbc.LoadBalancer.PreviousClusterName
+	cfg.LoadBalancer.DryRun = lbc.LoadBalancer.DryRun
 	cfg.LoadBalancer.AdditionalTags = lbc.LoadBalancer.AdditionalTags
 
+	//Avi
+	cfg.Avi.Controller = lbc.Avi.Controller
+	cfg.Avi.User = lbc.Avi.User
+	cfg.Avi.Password = lbc.Avi.Password
+	cfg.Avi.Tenant = lbc.Avi.Tenant
+	cfg.Avi.Version = lbc.Avi.Version
+	cfg.Avi.Cloud = lbc.Avi.Cloud
+	cfg.Avi.VRFContext = lbc.Avi.VRFContext
+	cfg.Avi.ServiceEngineGroup = lbc.Avi.ServiceEngineGroup
+	cfg.Avi.InsecureFlag = lbc.Avi.InsecureFlag
+	cfg.Avi.CAFile = lbc.Avi.CAFile
+	cfg.Avi.TLSMinVersion = lbc.Avi.TLSMinVersion
+	cfg.Avi.TLSCipherSuites = strings.Join(lbc.Avi.TLSCipherSuites, ",")
+
 	//LoadBalancerClass
 	for key, value := range lbc.LoadBalancerClass {
 		cfg.LoadBalancerClass[key] = &LoadBalancerClassConfig{
-			IPPoolName:        value.IPPoolName,
-			IPPoolID:          value.IPPoolID,
-			TCPAppProfileName: value.TCPAppProfileName,
-			TCPAppProfilePath: value.TCPAppProfilePath,
-			UDPAppProfileName: value.UDPAppProfileName,
-			UDPAppProfilePath: value.UDPAppProfilePath,
+			IPPoolName:           value.IPPoolName,
+			IPPoolID:             value.IPPoolID,
+			TCPAppProfileName:    value.TCPAppProfileName,
+			TCPAppProfilePath:    value.TCPAppProfilePath,
+			UDPAppProfileName:    value.UDPAppProfileName,
+			UDPAppProfilePath:    value.UDPAppProfilePath,
+			IPv6PoolName:         value.IPv6PoolName,
+			IPv6PoolID:           value.IPv6PoolID,
+			XForwardedFor:        value.XForwardedFor,
+			ServerKeepAlive:      value.ServerKeepAlive,
+			ClientSSLProfilePath: value.ClientSSLProfilePath,
+		}
+		if len(value.Zones) > 0 {
+			zones := make(map[string]*LoadBalancerZoneConfig, len(value.Zones))
+			for zone, zoneConfig := range value.Zones {
+				zones[zone] = &LoadBalancerZoneConfig{
+					Tier1GatewayPath: zoneConfig.Tier1GatewayPath,
+					IPPoolName:       zoneConfig.IPPoolName,
+					IPPoolID:         zoneConfig.IPPoolID,
+				}
+			}
+			cfg.LoadBalancerClass[key].Zones = zones
 		}
 	}
 	return cfg
@@ -71,25 +124,55 @@ func (lbc *LBConfigYAML) isEnabled() bool {
 }
 
 func (lbc *LBConfigYAML) validateConfig() error {
-	if lbc.LoadBalancer.LBServiceID == "" && lbc.LoadBalancer.Tier1GatewayPath == "" {
-		msg := "either load balancer service id or T1 gateway path required"
+	switch lbc.LoadBalancer.Backend {
+	case "", BackendNSXT:
+		if err := lbc.validateNSXTConfig(); err != nil {
+			return err
+		}
+	case BackendAvi:
+		if err := lbc.Avi.validateConfig(); err != nil {
+			return err
+		}
+		if err := lbc.validateAviOptions(); err != nil {
+			klog.Error(err)
+			return err
+		}
+	default:
+		msg := fmt.Sprintf("load balancer backend is invalid. Valid values are: %s", strings.Join(Backends.List(), ","))
 		klog.Errorf(msg)
 		return errors.New(msg)
 	}
-	if lbc.LoadBalancer.TCPAppProfileName == "" && lbc.LoadBalancer.TCPAppProfilePath == "" {
-		msg := "either load balancer TCP application profile name or path required"
-		klog.Errorf(msg)
-		return errors.New(msg)
+	if _, err := parseDuration("provisioning deadline", lbc.LoadBalancer.ProvisioningDeadline); err != nil {
+		klog.Error(err)
+		return err
 	}
-	if lbc.LoadBalancer.UDPAppProfileName == "" && lbc.LoadBalancer.UDPAppProfilePath == "" {
-		msg := "either load balancer UDP application profile name or path required"
-		klog.Errorf(msg)
-		return errors.New(msg)
+	if _, err := parseDuration("release quarantine", lbc.LoadBalancer.ReleaseQuarantine); err != nil {
+		klog.Error(err)
+		return err
 	}
-	if !LoadBalancerSizes.Has(lbc.LoadBalancer.Size) {
-		msg := fmt.Sprintf("load balancer size is invalid. Valid values are: %s", strings.Join(LoadBalancerSizes.List(), ","))
-		klog.Errorf(msg)
-		return errors.New(msg)
+	if _, err := parseDuration("named allocation retention", lbc.LoadBalancer.NamedAllocationRetention); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := ParseNameTemplate(lbc.LoadBalancer.NameTemplate); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := ParseDescriptionTemplate(lbc.LoadBalancer.DescriptionTemplate); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, _, err := ParseUsageReportConfigMap(lbc.LoadBalancer.UsageReportConfigMap); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := ParseIPPoolUsageThresholds(lbc.LoadBalancer.IPPoolUsageThresholds); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if err := ValidateClassPrefix(lbc.LoadBalancer.ClassPrefix); err != nil {
+		klog.Error(err)
+		return err
 	}
 	if lbc.LoadBalancer.IPPoolID == "" && lbc.LoadBalancer.IPPoolName == "" {
 		class, ok := lbc.LoadBalancerClass[DefaultLoadBalancerClass]
@@ -109,13 +192,161 @@ func (lbc *LBConfigYAML) validateConfig() error {
 			return errors.New(msg)
 		}
 	}
+	if lbc.LoadBalancer.IPv6PoolName != "" && lbc.LoadBalancer.IPv6PoolID != "" {
+		msg := "either load balancer ipv6PoolName or ipv6PoolID can be set"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	for name, class := range lbc.LoadBalancerClass {
+		for zone, zoneConfig := range class.Zones {
+			if zoneConfig == nil || zoneConfig.Tier1GatewayPath == "" {
+				msg := fmt.Sprintf("load balancer class %s: zone %s requires the T1 gateway path", name, zone)
+				klog.Errorf(msg)
+				return errors.New(msg)
+			}
+			if zoneConfig.IPPoolName != "" && zoneConfig.IPPoolID != "" {
+				msg := fmt.Sprintf("load balancer class %s: either ipPoolName or ipPoolID can be set for zone %s", name, zone)
+				klog.Errorf(msg)
+				return errors.New(msg)
+			}
+		}
+	}
+	return nil
+}
+
+// validateNSXTConfig validates the options only used by the nsxt backend
+func (lbc *LBConfigYAML) validateNSXTConfig() error {
+	if lbc.LoadBalancer.LBServiceID == "" && lbc.LoadBalancer.Tier1GatewayPath == "" {
+		msg := "either load balancer service id or T1 gateway path required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if lbc.LoadBalancer.TCPAppProfileName == "" && lbc.LoadBalancer.TCPAppProfilePath == "" {
+		msg := "either load balancer TCP application profile name or path required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if lbc.LoadBalancer.UDPAppProfileName == "" && lbc.LoadBalancer.UDPAppProfilePath == "" {
+		msg := "either load balancer UDP application profile name or path required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if (lbc.LoadBalancer.EdgeClusterPath != "" || lbc.LoadBalancer.FailoverMode != "") &&
+		(lbc.LoadBalancer.LBServiceID != "" || lbc.LoadBalancer.Tier1GatewayPath == "") {
+		msg := "edge cluster path and failover mode require the T1 gateway path without load balancer service id"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if lbc.LoadBalancer.FailoverMode != "" && !FailoverModes.Has(lbc.LoadBalancer.FailoverMode) {
+		msg := fmt.Sprintf("load balancer failover mode is invalid. Valid values are: %s", strings.Join(FailoverModes.List(), ","))
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if !LoadBalancerSizes.Has(lbc.LoadBalancer.Size) {
+		msg := fmt.Sprintf("load balancer size is invalid. Valid values are: %s", strings.Join(LoadBalancerSizes.List(), ","))
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// setDefaults sets the default tenant, API version, cloud and service engine
+// group if unset
+func (avi *AviConfigYAML) setDefaults() {
+	if avi.Tenant == "" {
+		avi.Tenant = DefaultAviTenant
+	}
+	if avi.Version == "" {
+		avi.Version = DefaultAviVersion
+	}
+	if avi.Cloud == "" {
+		avi.Cloud = DefaultAviCloud
+	}
+	if avi.ServiceEngineGroup == "" {
+		avi.ServiceEngineGroup = DefaultAviServiceEngineGroup
+	}
+}
+
+// validateConfig validates the access to the Avi controller
+// validateAviOptions rejects the options of the loadBalancer section and the
+// load balancer classes only implemented by the nsxt backend.
+func (lbc *LBConfigYAML) validateAviOptions() error {
+	lb := &lbc.LoadBalancer
+	if err := validateAviOptions("load balancer ",
+		nsxtOption{"size", lb.Size != ""},
+		nsxtOption{"lbServiceId", lb.LBServiceID != ""},
+		nsxtOption{"tier1GatewayPath", lb.Tier1GatewayPath != ""},
+		nsxtOption{"edgeClusterPath", lb.EdgeClusterPath != ""},
+		nsxtOption{"failoverMode", lb.FailoverMode != ""},
+		nsxtOption{"snatDisabled", lb.SnatDisabled},
+		nsxtOption{"reachabilityCheck", lb.ReachabilityCheck},
+		nsxtOption{"provisioningDeadline", lb.ProvisioningDeadline != ""},
+		nsxtOption{"releaseQuarantine", lb.ReleaseQuarantine != ""},
+		nsxtOption{"namedAllocationRetention", lb.NamedAllocationRetention != ""},
+		nsxtOption{"displayNamePrefix", lb.DisplayNamePrefix != ""},
+		nsxtOption{"descriptionTemplate", lb.DescriptionTemplate != ""},
+		nsxtOption{"usageReportConfigMap", lb.UsageReportConfigMap != ""},
+		nsxtOption{"ipPoolUsageThresholds", lb.IPPoolUsageThresholds != ""},
+		nsxtOption{"publishNodePortMappings", lb.PublishNodePortMappings},
+		nsxtOption{"previousClusterName", lb.PreviousClusterName != ""},
+		nsxtOption{"dryRun", lb.DryRun},
+		nsxtOption{"tcpAppProfileName", lb.TCPAppProfileName != ""},
+		nsxtOption{"tcpAppProfilePath", lb.TCPAppProfilePath != ""},
+		nsxtOption{"udpAppProfileName", lb.UDPAppProfileName != ""},
+		nsxtOption{"udpAppProfilePath", lb.UDPAppProfilePath != ""},
+		nsxtOption{"ipv6PoolName", lb.IPv6PoolName != ""},
+		nsxtOption{"ipv6PoolId", lb.IPv6PoolID != ""},
+		nsxtOption{"xForwardedFor", lb.XForwardedFor != ""},
+		nsxtOption{"serverKeepAlive", lb.ServerKeepAlive},
+		nsxtOption{"clientSSLProfilePath", lb.ClientSSLProfilePath != ""},
+	); err != nil {
+		return err
+	}
+	for name, class := range lbc.LoadBalancerClass {
+		if err := validateAviOptions(fmt.Sprintf("load balancer class %s: ", name),
+			nsxtOption{"tcpAppProfileName", class.TCPAppProfileName != ""},
+			nsxtOption{"tcpAppProfilePath", class.TCPAppProfilePath != ""},
+			nsxtOption{"udpAppProfileName", class.UDPAppProfileName != ""},
+			nsxtOption{"udpAppProfilePath", class.UDPAppProfilePath != ""},
+			nsxtOption{"ipv6PoolName", class.IPv6PoolName != ""},
+			nsxtOption{"ipv6PoolId", class.IPv6PoolID != ""},
+			nsxtOption{"xForwardedFor", class.XForwardedFor != ""},
+			nsxtOption{"serverKeepAlive", class.ServerKeepAlive},
+			nsxtOption{"clientSSLProfilePath", class.ClientSSLProfilePath != ""},
+			nsxtOption{"zones", len(class.Zones) > 0},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (avi *AviConfigYAML) validateConfig() error {
+	if avi.Controller == "" {
+		msg := "avi controller required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if avi.User == "" || avi.Password == "" {
+		msg := "avi user and password required"
+		klog.Errorf(msg)
+		return errors.New(msg)
+	}
+	if _, err := vcfg.ParseTLSMinVersion(avi.TLSMinVersion); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err := vcfg.ParseTLSCipherSuites(strings.Join(avi.TLSCipherSuites, ",")); err != nil {
+		klog.Error(err)
+		return err
+	}
 	return nil
 }
 
 func (lbc *LoadBalancerConfigYAML) isEmpty() bool {
 	return lbc.Size == "" && lbc.LBServiceID == "" &&
 		lbc.IPPoolID == "" && lbc.IPPoolName == "" &&
-		lbc.Tier1GatewayPath == ""
+		lbc.Tier1GatewayPath == "" && lbc.Backend == ""
 }
 
 // CompleteAndValidate sets default values, overrides by env and validates the resulting config
@@ -127,6 +358,9 @@ func (lbc *LBConfigYAML) CompleteAndValidate() error {
 	if lbc.LoadBalancerClass == nil {
 		lbc.LoadBalancerClass = map[string]*LoadBalancerClassConfigYAML{}
 	}
+	if lbc.LoadBalancer.Backend == BackendAvi {
+		lbc.Avi.setDefaults()
+	}
 	for _, class := range lbc.LoadBalancerClass {
 		if class.IPPoolName == "" {
 			class.IPPoolName = lbc.LoadBalancer.IPPoolName
@@ -134,6 +368,10 @@ func (lbc *LBConfigYAML) CompleteAndValidate() error {
 		if class.IPPoolID == "" {
 			class.IPPoolID = lbc.LoadBalancer.IPPoolID
 		}
+		if class.IPv6PoolName == "" && class.IPv6PoolID == "" {
+			class.IPv6PoolName = lbc.LoadBalancer.IPv6PoolName
+			class.IPv6PoolID = lbc.LoadBalancer.IPv6PoolID
+		}
 	}
 
 	return lbc.validateConfig()
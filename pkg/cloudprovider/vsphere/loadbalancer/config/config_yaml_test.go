@@ -17,7 +17,9 @@
 package config
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +33,7 @@ func TestReadYAMLConfig(t *testing.T) {
 	contents := `
 loadBalancer:
   ipPoolName: pool1
+  ipv6PoolName: pool6
   size: MEDIUM
   lbServiceId: 4711
   tier1GatewayPath: 1234
@@ -44,10 +47,17 @@ loadBalancer:
 loadBalancerClass:
   public:
     ipPoolName: poolPublic
+    zones:
+      zone-a:
+        tier1GatewayPath: /infra/tier-1s/t1-a
+        ipPoolName: poolPublicA
   private:
     ipPoolName: poolPrivate
     tcpAppProfileName: tcp2
     udpAppProfileName: udp2
+    xForwardedFor: insert
+    serverKeepAlive: true
+    clientSSLProfilePath: /infra/lb-client-ssl-profiles/strict
 `
 	config, err := ReadRawConfigYAML([]byte(contents))
 	if err != nil {
@@ -61,6 +71,7 @@ loadBalancerClass:
 		}
 	}
 	assertEquals("loadBalancer.ipPoolName", config.LoadBalancer.IPPoolName, "pool1")
+	assertEquals("loadBalancer.ipv6PoolName", config.LoadBalancer.IPv6PoolName, "pool6")
 	assertEquals("loadBalancer.lbServiceId", config.LoadBalancer.LBServiceID, "4711")
 	assertEquals("loadBalancer.tier1GatewayPath", config.LoadBalancer.Tier1GatewayPath, "1234")
 	assertEquals("loadBalancer.tcpAppProfileName", config.LoadBalancer.TCPAppProfileName, "default-tcp-lb-app-profile")
@@ -71,8 +82,15 @@ loadBalancerClass:
 		t.Errorf("expected two LoadBalancerClass subsections, but got %d", len(config.LoadBalancerClass))
 	}
 	assertEquals("loadBalancerClass.public.ipPoolName", config.LoadBalancerClass["public"].IPPoolName, "poolPublic")
+	assertEquals("loadBalancerClass.public.ipv6PoolName", config.LoadBalancerClass["public"].IPv6PoolName, "pool6")
+	if zone := config.LoadBalancerClass["public"].Zones["zone-a"]; zone == nil || zone.Tier1GatewayPath != "/infra/tier-1s/t1-a" || zone.IPPoolName != "poolPublicA" {
+		t.Errorf("unexpected zones %v", config.LoadBalancerClass["public"].Zones)
+	}
 	assertEquals("loadBalancerClass.private.tcpAppProfileName", config.LoadBalancerClass["private"].TCPAppProfileName, "tcp2")
 	assertEquals("loadBalancerClass.private.udpAppProfileName", config.LoadBalancerClass["private"].UDPAppProfileName, "udp2")
+	assertEquals("loadBalancerClass.private.xForwardedFor", config.LoadBalancerClass["private"].XForwardedFor, "insert")
+	assertEquals("loadBalancerClass.private.clientSSLProfilePath", config.LoadBalancerClass["private"].ClientSSLProfilePath, "/infra/lb-client-ssl-profiles/strict")
+	assert.Equal(t, true, config.LoadBalancerClass["private"].ServerKeepAlive)
 	if len(config.LoadBalancer.AdditionalTags) != 2 || config.LoadBalancer.AdditionalTags["tag1"] != "value1" || config.LoadBalancer.AdditionalTags["tag2"] != "value 2" {
 		t.Errorf("unexpected additionalTags %v", config.LoadBalancer.AdditionalTags)
 	}
@@ -105,3 +123,364 @@ loadBalancer:
 	assertEquals("loadBalancer.udpAppProfilePath", config.LoadBalancer.UDPAppProfilePath, "infra/xxx/udp1234")
 	assert.Equal(t, false, config.LoadBalancer.SnatDisabled)
 }
+
+func TestReadYAMLConfigPlacement(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  edgeClusterPath: /infra/sites/default/enforcement-points/default/edge-clusters/ec1
+  failoverMode: PREEMPTIVE
+  reachabilityCheck: true
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, true, config.LoadBalancer.ReachabilityCheck)
+	assert.Equal(t, "/infra/sites/default/enforcement-points/default/edge-clusters/ec1", config.LoadBalancer.EdgeClusterPath)
+	assert.Equal(t, "PREEMPTIVE", config.LoadBalancer.FailoverMode)
+
+	invalid := map[string]string{
+		"failover mode": `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  failoverMode: ACTIVE
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`,
+		"unmanaged mode": `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  lbServiceId: 4711
+  edgeClusterPath: /infra/sites/default/enforcement-points/default/edge-clusters/ec1
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`,
+	}
+	for name, contents := range invalid {
+		if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestReadYAMLConfigProvisioningDeadline(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  provisioningDeadline: 10m
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline, err := config.LoadBalancer.ProvisioningDeadlineDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 10*time.Minute, deadline)
+
+	for _, value := range []string{"10", "-1m"} {
+		contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  provisioningDeadline: ` + value + `
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+		if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+			t.Errorf("%s: expected error", value)
+		}
+	}
+}
+
+func TestReadYAMLConfigReleaseQuarantine(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  releaseQuarantine: 1h
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	quarantine, err := config.LoadBalancer.ReleaseQuarantineDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, time.Hour, quarantine)
+
+	contents = strings.Replace(contents, "1h", "-1h", 1)
+	if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestReadYAMLConfigNamedAllocationRetention(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  namedAllocationRetention: 720h
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retention, err := config.LoadBalancer.NamedAllocationRetentionDuration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 720*time.Hour, retention)
+
+	contents = strings.Replace(contents, "720h", "forever", 1)
+	if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestReadYAMLConfigNaming(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  displayNamePrefix: "team-a:"
+  nameTemplate: "{{.Namespace}}-{{.Name}}"
+  descriptionTemplate: "{{.Kind}} of {{.Namespace}}/{{.Name}}"
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "team-a:", config.LoadBalancer.DisplayNamePrefix)
+	assert.Equal(t, "{{.Namespace}}-{{.Name}}", config.LoadBalancer.NameTemplate)
+	assert.Equal(t, "{{.Kind}} of {{.Namespace}}/{{.Name}}", config.LoadBalancer.DescriptionTemplate)
+
+	contents = `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  descriptionTemplate: "{{.Kind"
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+		t.Error("expected error")
+	}
+
+	contents = strings.Replace(contents, "descriptionTemplate", "nameTemplate", 1)
+	if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestReadYAMLConfigUsageReport(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  usageReportConfigMap: kube-system/lb-usage
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	namespace, name, err := ParseUsageReportConfigMap(config.LoadBalancer.UsageReportConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "kube-system", namespace)
+	assert.Equal(t, "lb-usage", name)
+
+	contents = strings.Replace(contents, "kube-system/lb-usage", "lb-usage", 1)
+	if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestReadYAMLConfigIPPoolUsageThresholds(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  ipPoolUsageThresholds: "95, 80"
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thresholds, err := ParseIPPoolUsageThresholds(config.LoadBalancer.IPPoolUsageThresholds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []float64{80, 95}, thresholds)
+
+	for _, invalid := range []string{"0", "101", "high"} {
+		invalidContents := strings.Replace(contents, "95, 80", invalid, 1)
+		if _, err := ReadConfigYAML([]byte(invalidContents)); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestReadYAMLConfigClassPrefix(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  classPrefix: nsx-t.cpi.vsphere/
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "nsx-t.cpi.vsphere/", config.LoadBalancer.ClassPrefix)
+
+	for _, invalid := range []string{"nsx-t.cpi.vsphere", "nsx-t.cpi.vsphere/gold", "NSX_T/"} {
+		invalidContents := strings.Replace(contents, "nsx-t.cpi.vsphere/", invalid, 1)
+		if _, err := ReadConfigYAML([]byte(invalidContents)); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestReadYAMLConfigZones(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+loadBalancerClass:
+  default:
+    zones:
+      zone-a:
+        tier1GatewayPath: /infra/tier-1s/t1-a
+        ipPoolId: pool-a
+      zone-b:
+        tier1GatewayPath: /infra/tier-1s/t1-b
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zones := config.LoadBalancerClass["default"].Zones
+	assert.Equal(t, &LoadBalancerZoneConfig{Tier1GatewayPath: "/infra/tier-1s/t1-a", IPPoolID: "pool-a"}, zones["zone-a"])
+	assert.Equal(t, &LoadBalancerZoneConfig{Tier1GatewayPath: "/infra/tier-1s/t1-b"}, zones["zone-b"])
+
+	invalidContents := strings.Replace(contents, "tier1GatewayPath: /infra/tier-1s/t1-b", "ipPoolName: pool-b", 1)
+	if _, err := ReadConfigYAML([]byte(invalidContents)); err == nil {
+		t.Errorf("expected error for zone without T1 gateway path")
+	}
+}
+
+func TestReadYAMLConfigPreviousClusterName(t *testing.T) {
+	contents := `
+loadBalancer:
+  ipPoolName: pool1
+  size: MEDIUM
+  tier1GatewayPath: /infra/tier-1s/t1
+  previousClusterName: old-cluster
+  dryRun: true
+  tcpAppProfileName: default-tcp-lb-app-profile
+  udpAppProfileName: default-udp-lb-app-profile
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "old-cluster", config.LoadBalancer.PreviousClusterName)
+	assert.Equal(t, true, config.LoadBalancer.DryRun)
+}
+
+func TestReadYAMLConfigAvi(t *testing.T) {
+	contents := `
+loadBalancer:
+  backend: avi
+  ipPoolName: vip-network
+loadBalancerClass:
+  public:
+    ipPoolName: public-network
+avi:
+  controller: avi-controller
+  user: admin
+  password: secret
+  vrfContext: vrf1
+  tlsMinVersion: "1.2"
+  tlsCipherSuites:
+    - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+    - TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, BackendAvi, config.LoadBalancer.Backend)
+	assert.Equal(t, AviConfig{
+		Controller:         "avi-controller",
+		User:               "admin",
+		Password:           "secret",
+		Tenant:             DefaultAviTenant,
+		Version:            DefaultAviVersion,
+		Cloud:              DefaultAviCloud,
+		VRFContext:         "vrf1",
+		ServiceEngineGroup: DefaultAviServiceEngineGroup,
+		TLSMinVersion:      "1.2",
+		TLSCipherSuites:    "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	}, config.Avi)
+	assert.Equal(t, "public-network", config.LoadBalancerClass["public"].IPPoolName)
+	assert.Equal(t, "<redacted>", config.Redacted().Avi.Password)
+	assert.Equal(t, "secret", config.Avi.Password)
+
+	for _, invalid := range []string{
+		strings.Replace(contents, "backend: avi", "backend: f5", 1),
+		strings.Replace(contents, "controller: avi-controller", "", 1),
+		strings.Replace(contents, "password: secret", "", 1),
+		strings.Replace(contents, "ipPoolName: vip-network", "", 1),
+		strings.Replace(contents, `tlsMinVersion: "1.2"`, `tlsMinVersion: "2.0"`, 1),
+		// the options only implemented by the nsxt backend
+		strings.Replace(contents, "backend: avi", "backend: avi\n  releaseQuarantine: 1h", 1),
+		strings.Replace(contents, "backend: avi", "backend: avi\n  previousClusterName: old-cluster", 1),
+		strings.Replace(contents, "backend: avi", "backend: avi\n  ipv6PoolName: vip-network-v6", 1),
+		strings.Replace(contents, "ipPoolName: public-network", "ipPoolName: public-network\n    xForwardedFor: insert", 1),
+		strings.Replace(contents, "ipPoolName: public-network", "ipPoolName: public-network\n    clientSSLProfilePath: /infra/lb-client-ssl-profiles/p", 1),
+	} {
+		if _, err := ReadConfigYAML([]byte(invalid)); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
@@ -25,8 +25,27 @@ import (
 const (
 	// DefaultLoadBalancerClass is the default load balancer class
 	DefaultLoadBalancerClass = "default"
+
+	// BackendNSXT is the backend provisioning the load balancers as NSX-T
+	// virtual servers, the default
+	BackendNSXT = "nsxt"
+	// BackendAvi is the backend provisioning the load balancers as virtual
+	// services of the Avi controller (NSX Advanced Load Balancer)
+	BackendAvi = "avi"
+
+	// DefaultAviVersion is the default API version of the Avi controller
+	DefaultAviVersion = "22.1.3"
+	// DefaultAviTenant is the default Avi tenant
+	DefaultAviTenant = "admin"
+	// DefaultAviCloud is the default Avi cloud
+	DefaultAviCloud = "Default-Cloud"
+	// DefaultAviServiceEngineGroup is the default Avi service engine group
+	DefaultAviServiceEngineGroup = "Default-Group"
 )
 
+// Backends contains the valid load balancer backends
+var Backends = sets.NewString(BackendNSXT, BackendAvi)
+
 // LoadBalancerSizes contains the valid size names
 var LoadBalancerSizes = sets.NewString(
 	model.LBService_SIZE_SMALL,
@@ -35,3 +54,9 @@ var LoadBalancerSizes = sets.NewString(
 	model.LBService_SIZE_XLARGE,
 	model.LBService_SIZE_DLB,
 )
+
+// FailoverModes contains the valid failover modes of the T1 gateway
+var FailoverModes = sets.NewString(
+	model.Tier1_FAILOVER_MODE_PREEMPTIVE,
+	model.Tier1_FAILOVER_MODE_NON_PREEMPTIVE,
+)
@@ -0,0 +1,31 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+// Redacted returns a copy of the config whose Avi password is replaced by
+// vcfg.RedactedValue, to be logged or dumped.
+func (cfg *LBConfig) Redacted() *LBConfig {
+	redacted := *cfg
+	if redacted.Avi.Password != "" {
+		redacted.Avi.Password = vcfg.RedactedValue
+	}
+	return &redacted
+}
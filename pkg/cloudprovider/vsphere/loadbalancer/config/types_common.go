@@ -20,16 +20,70 @@ package config
 type LBConfig struct {
 	LoadBalancer      LoadBalancerConfig
 	LoadBalancerClass map[string]*LoadBalancerClassConfig
+	// Avi is the Avi controller of the avi backend
+	Avi AviConfig
 }
 
 // LoadBalancerConfig contains the configuration for the load balancer itself
 type LoadBalancerConfig struct {
 	LoadBalancerClassConfig
+	// Backend provisions the load balancers, nsxt or avi. Empty for nsxt.
+	Backend          string
 	Size             string
 	LBServiceID      string
 	Tier1GatewayPath string
+	EdgeClusterPath  string
+	FailoverMode     string
 	SnatDisabled     bool
-	AdditionalTags   map[string]string
+	// ReachabilityCheck enables a TCP connect check of the VIP ports after
+	// the load balancer is provisioned
+	ReachabilityCheck bool
+	// ProvisioningDeadline is the duration after which a Service still
+	// without ingress is reported as stuck, such as 10m. Empty to disable.
+	ProvisioningDeadline string
+	// ReleaseQuarantine is the duration the IP address allocation of a
+	// deleted Service is held before it is returned to the IP pool, such as
+	// 1h. Empty to release it immediately.
+	ReleaseQuarantine string
+	// NamedAllocationRetention is the duration a named IP address
+	// allocation no Service names any longer is kept before it is returned
+	// to the IP pool, such as 720h. Empty to never release it.
+	NamedAllocationRetention string
+	// DisplayNamePrefix is prepended to the display names of the virtual
+	// servers, pools and TCP monitor profiles
+	DisplayNamePrefix string
+	// NameTemplate is the text/template of the names of the load balancers,
+	// the base of the display names of their virtual servers, pools and TCP
+	// monitor profiles. Empty for cluster:<cluster>:<namespace>/<name>.
+	NameTemplate string
+	// DescriptionTemplate is the text/template of the descriptions of the
+	// virtual servers, pools and TCP monitor profiles. Empty for the default
+	// descriptions.
+	DescriptionTemplate string
+	// UsageReportConfigMap is the namespace/name of the ConfigMap the
+	// number of virtual servers, pools and VIPs per namespace is reported
+	// to. Empty to only expose the usage as metrics.
+	UsageReportConfigMap string
+	// IPPoolUsageThresholds is the comma separated list of the utilization
+	// percentages of the IP pools, such as 80,95, above which a warning
+	// event is emitted. Empty to not watch the utilization.
+	IPPoolUsageThresholds string
+	AdditionalTags        map[string]string
+	// ClassPrefix is the prefix of the spec.loadBalancerClass of the Services
+	// reconciled by the controller, such as nsx-t.cpi.vsphere/. The rest of
+	// the class names the load balancer class, the default one if empty.
+	// Empty to only reconcile the Services without spec.loadBalancerClass.
+	ClassPrefix string
+	// PublishNodePortMappings publishes the mappings of the virtual IP
+	// addresses to the node ports in an annotation of the Services.
+	PublishNodePortMappings bool
+	// PreviousClusterName is the cluster name the NSX-T objects were created
+	// with before the cluster was renamed. They are tagged with the current
+	// cluster name on startup, before the Services are reconciled.
+	PreviousClusterName string
+	// DryRun logs and records the NSX-T create, update and delete operations
+	// instead of performing them, the load balancers are not provisioned.
+	DryRun bool
 }
 
 // LoadBalancerClassConfig contains the configuration for a load balancer class
@@ -40,4 +94,68 @@ type LoadBalancerClassConfig struct {
 	TCPAppProfilePath string
 	UDPAppProfileName string
 	UDPAppProfilePath string
+	// IPv6PoolName or IPv6PoolID is the IP pool of the IPv6 virtual IP of the
+	// dual-stack Services. Empty if the class only allocates IPv4 addresses.
+	IPv6PoolName string
+	IPv6PoolID   string
+	// XForwardedFor is the X-Forwarded-For handling, insert or replace, of
+	// the HTTP application profiles of the TCP virtual servers of the
+	// Services. Empty to use the TCP application profile.
+	XForwardedFor string
+	// ServerKeepAlive keeps a backend connection per client connection, which
+	// is closed with the client connection, in the HTTP application profiles.
+	ServerKeepAlive bool
+	// ClientSSLProfilePath is the policy path of the client SSL profile of
+	// the virtual servers terminating TLS for the Services requesting it.
+	// Empty for the default-balanced-client-ssl-profile of NSX-T.
+	ClientSSLProfilePath string
+	// Zones places the virtual servers of the Services whose endpoints all
+	// run in a zone on the T1 gateway of the zone, keyed by zone name
+	Zones map[string]*LoadBalancerZoneConfig
+}
+
+// LoadBalancerZoneConfig contains the placement of the virtual servers in a zone
+type LoadBalancerZoneConfig struct {
+	// Tier1GatewayPath is the policy path of the T1 gateway of the zone,
+	// whose load balancer service is managed by the controller
+	Tier1GatewayPath string
+	// IPPoolName or IPPoolID is the IP pool of the virtual IP addresses in
+	// the zone. Empty for the IP pool of the class.
+	IPPoolName string
+	IPPoolID   string
+}
+
+// AviConfig contains the access to the Avi controller (NSX Advanced Load
+// Balancer) and the placement of the virtual services of the avi backend
+type AviConfig struct {
+	// Controller is the host of the Avi controller, optionally with a port
+	Controller string
+	// User is the Avi user name
+	User string
+	// Password is the Avi password in clear text
+	Password string
+	// Tenant is the Avi tenant of the virtual services, pools and VIPs
+	Tenant string
+	// Version is the Avi API version the requests are sent with
+	Version string
+	// Cloud is the name of the Avi cloud of the virtual services
+	Cloud string
+	// VRFContext is the name of the VRF context of the VIPs. Empty for the
+	// VRF context of the cloud.
+	VRFContext string
+	// ServiceEngineGroup is the name of the service engine group hosting
+	// the virtual services
+	ServiceEngineGroup string
+	// InsecureFlag is to be set to true if the Avi controller uses a
+	// self-signed certificate
+	InsecureFlag bool
+	// CAFile is the certificate authority of the certificate of the Avi
+	// controller
+	CAFile string
+	// TLSMinVersion is the minimum TLS version of the connections to the
+	// Avi controller, 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string
+	// TLSCipherSuites are the comma separated TLS cipher suites of the
+	// connections to the Avi controller up to TLS 1.2, by their IANA names.
+	TLSCipherSuites string
 }
@@ -20,17 +20,38 @@ package config
 type LBConfigINI struct {
 	LoadBalancer      LoadBalancerConfigINI                  `gcfg:"loadbalancer"`
 	LoadBalancerClass map[string]*LoadBalancerClassConfigINI `gcfg:"loadbalancerclass"`
+	Avi               AviConfigINI                           `gcfg:"avi"`
 }
 
 // LoadBalancerConfigINI contains the configuration for the load balancer itself
 type LoadBalancerConfigINI struct {
 	LoadBalancerClassConfigINI
-	Size             string `gcfg:"size"`
-	LBServiceID      string `gcfg:"lb-service-id"`
-	Tier1GatewayPath string `gcfg:"tier1-gateway-path"`
-	SnatDisabled     bool   `gcfg:"snat-disabled"`
-	RawTags          string `gcfg:"tags"`
-	AdditionalTags   map[string]string
+	// the backend provisioning the load balancers, nsxt or avi
+	Backend                  string `gcfg:"backend"`
+	Size                     string `gcfg:"size"`
+	LBServiceID              string `gcfg:"lb-service-id"`
+	Tier1GatewayPath         string `gcfg:"tier1-gateway-path"`
+	EdgeClusterPath          string `gcfg:"edge-cluster-path"`
+	FailoverMode             string `gcfg:"failover-mode"`
+	SnatDisabled             bool   `gcfg:"snat-disabled"`
+	ReachabilityCheck        bool   `gcfg:"reachability-check"`
+	ProvisioningDeadline     string `gcfg:"provisioning-deadline"`
+	ReleaseQuarantine        string `gcfg:"release-quarantine"`
+	NamedAllocationRetention string `gcfg:"named-allocation-retention"`
+	DisplayNamePrefix        string `gcfg:"display-name-prefix"`
+	NameTemplate             string `gcfg:"name-template"`
+	DescriptionTemplate      string `gcfg:"description-template"`
+	UsageReportConfigMap     string `gcfg:"usage-report-config-map"`
+	IPPoolUsageThresholds    string `gcfg:"ip-pool-usage-thresholds"`
+	ClassPrefix              string `gcfg:"class-prefix"`
+	RawTags                  string `gcfg:"tags"`
+	AdditionalTags           map[string]string
+	// publish the node port mappings in an annotation of the Services
+	PublishNodePortMappings bool `gcfg:"publish-node-port-mappings"`
+	// the cluster name the NSX-T objects are migrated from
+	PreviousClusterName string `gcfg:"previous-cluster-name"`
+	// only log and record the NSX-T operations
+	DryRun bool `gcfg:"dry-run"`
 }
 
 // LoadBalancerClassConfigINI contains the configuration for a load balancer class
@@ -41,4 +62,29 @@ type LoadBalancerClassConfigINI struct {
 	TCPAppProfilePath string `gcfg:"tcp-app-profile-path"`
 	UDPAppProfileName string `gcfg:"udp-app-profile-name"`
 	UDPAppProfilePath string `gcfg:"udp-app-profile-path"`
+	IPv6PoolName      string `gcfg:"ipv6-pool-name"`
+	IPv6PoolID        string `gcfg:"ipv6-pool-id"`
+	XForwardedFor     string `gcfg:"x-forwarded-for"`
+	ServerKeepAlive   bool   `gcfg:"server-keep-alive"`
+	// the client SSL profile of the virtual servers terminating TLS
+	ClientSSLProfilePath string `gcfg:"client-ssl-profile-path"`
+}
+
+// AviConfigINI contains the access to the Avi controller of the avi backend
+type AviConfigINI struct {
+	Controller         string `gcfg:"controller"`
+	User               string `gcfg:"user"`
+	Password           string `gcfg:"password"`
+	Tenant             string `gcfg:"tenant"`
+	Version            string `gcfg:"version"`
+	Cloud              string `gcfg:"cloud"`
+	VRFContext         string `gcfg:"vrf-context"`
+	ServiceEngineGroup string `gcfg:"service-engine-group"`
+	InsecureFlag       bool   `gcfg:"insecure-flag"`
+	CAFile             string `gcfg:"ca-file"`
+	// the minimum TLS version of the connections to the Avi controller
+	TLSMinVersion string `gcfg:"tls-min-version"`
+	// the comma separated TLS cipher suites of the connections to the Avi
+	// controller up to TLS 1.2
+	TLSCipherSuites string `gcfg:"tls-cipher-suites"`
 }
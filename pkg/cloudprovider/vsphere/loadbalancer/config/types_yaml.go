@@ -30,15 +30,36 @@ package config
 type LBConfigYAML struct {
 	LoadBalancer      LoadBalancerConfigYAML                  `yaml:"loadBalancer"`
 	LoadBalancerClass map[string]*LoadBalancerClassConfigYAML `yaml:"loadBalancerClass"`
+	Avi               AviConfigYAML                           `yaml:"avi"`
 }
 
 // LoadBalancerConfigYAML contains the configuration for the load balancer itself
 type LoadBalancerConfigYAML struct {
-	Size             string            `yaml:"size"`
-	LBServiceID      string            `yaml:"lbServiceId"`
-	Tier1GatewayPath string            `yaml:"tier1GatewayPath"`
-	SnatDisabled     bool              `yaml:"snatDisabled"`
-	AdditionalTags   map[string]string `yaml:"tags"`
+	// the backend provisioning the load balancers, nsxt or avi
+	Backend                  string            `yaml:"backend"`
+	Size                     string            `yaml:"size"`
+	LBServiceID              string            `yaml:"lbServiceId"`
+	Tier1GatewayPath         string            `yaml:"tier1GatewayPath"`
+	EdgeClusterPath          string            `yaml:"edgeClusterPath"`
+	FailoverMode             string            `yaml:"failoverMode"`
+	SnatDisabled             bool              `yaml:"snatDisabled"`
+	ReachabilityCheck        bool              `yaml:"reachabilityCheck"`
+	ProvisioningDeadline     string            `yaml:"provisioningDeadline"`
+	ReleaseQuarantine        string            `yaml:"releaseQuarantine"`
+	NamedAllocationRetention string            `yaml:"namedAllocationRetention"`
+	DisplayNamePrefix        string            `yaml:"displayNamePrefix"`
+	NameTemplate             string            `yaml:"nameTemplate"`
+	DescriptionTemplate      string            `yaml:"descriptionTemplate"`
+	UsageReportConfigMap     string            `yaml:"usageReportConfigMap"`
+	IPPoolUsageThresholds    string            `yaml:"ipPoolUsageThresholds"`
+	ClassPrefix              string            `yaml:"classPrefix"`
+	AdditionalTags           map[string]string `yaml:"tags"`
+	// publish the node port mappings in an annotation of the Services
+	PublishNodePortMappings bool `yaml:"publishNodePortMappings"`
+	// the cluster name the NSX-T objects are migrated from
+	PreviousClusterName string `yaml:"previousClusterName"`
+	// only log and record the NSX-T operations
+	DryRun bool `yaml:"dryRun"`
 
 	// this struct use to inherit from LoadBalancerClassConfigYAML, but the YAML parser
 	// wasnt able to indirectly parse inherited fields
@@ -48,14 +69,52 @@ type LoadBalancerConfigYAML struct {
 	TCPAppProfilePath string `yaml:"tcpAppProfilePath"`
 	UDPAppProfileName string `yaml:"udpAppProfileName"`
 	UDPAppProfilePath string `yaml:"udpAppProfilePath"`
+	IPv6PoolName      string `yaml:"ipv6PoolName"`
+	IPv6PoolID        string `yaml:"ipv6PoolId"`
+	XForwardedFor     string `yaml:"xForwardedFor"`
+	ServerKeepAlive   bool   `yaml:"serverKeepAlive"`
+	// the client SSL profile of the virtual servers terminating TLS
+	ClientSSLProfilePath string `yaml:"clientSSLProfilePath"`
 }
 
 // LoadBalancerClassConfigYAML contains the configuration for a load balancer class
 type LoadBalancerClassConfigYAML struct {
-	IPPoolName        string `yaml:"ipPoolName"`
-	IPPoolID          string `yaml:"ipPoolId"`
-	TCPAppProfileName string `yaml:"tcpAppProfileName"`
-	TCPAppProfilePath string `yaml:"tcpAppProfilePath"`
-	UDPAppProfileName string `yaml:"udpAppProfileName"`
-	UDPAppProfilePath string `yaml:"udpAppProfilePath"`
+	IPPoolName        string                                 `yaml:"ipPoolName"`
+	IPPoolID          string                                 `yaml:"ipPoolId"`
+	TCPAppProfileName string                                 `yaml:"tcpAppProfileName"`
+	TCPAppProfilePath string                                 `yaml:"tcpAppProfilePath"`
+	UDPAppProfileName string                                 `yaml:"udpAppProfileName"`
+	UDPAppProfilePath string                                 `yaml:"udpAppProfilePath"`
+	IPv6PoolName      string                                 `yaml:"ipv6PoolName"`
+	IPv6PoolID        string                                 `yaml:"ipv6PoolId"`
+	XForwardedFor     string                                 `yaml:"xForwardedFor"`
+	ServerKeepAlive   bool                                   `yaml:"serverKeepAlive"`
+	Zones             map[string]*LoadBalancerZoneConfigYAML `yaml:"zones"`
+	// the client SSL profile of the virtual servers terminating TLS
+	ClientSSLProfilePath string `yaml:"clientSSLProfilePath"`
+}
+
+// LoadBalancerZoneConfigYAML contains the placement of the virtual servers in a zone
+type LoadBalancerZoneConfigYAML struct {
+	Tier1GatewayPath string `yaml:"tier1GatewayPath"`
+	IPPoolName       string `yaml:"ipPoolName"`
+	IPPoolID         string `yaml:"ipPoolId"`
+}
+
+// AviConfigYAML contains the access to the Avi controller of the avi backend
+type AviConfigYAML struct {
+	Controller         string `yaml:"controller"`
+	User               string `yaml:"user"`
+	Password           string `yaml:"password"`
+	Tenant             string `yaml:"tenant"`
+	Version            string `yaml:"version"`
+	Cloud              string `yaml:"cloud"`
+	VRFContext         string `yaml:"vrfContext"`
+	ServiceEngineGroup string `yaml:"serviceEngineGroup"`
+	InsecureFlag       bool   `yaml:"insecureFlag"`
+	CAFile             string `yaml:"caFile"`
+	// the minimum TLS version of the connections to the Avi controller
+	TLSMinVersion string `yaml:"tlsMinVersion"`
+	// the TLS cipher suites of the connections to the Avi controller up to TLS 1.2
+	TLSCipherSuites []string `yaml:"tlsCipherSuites"`
 }
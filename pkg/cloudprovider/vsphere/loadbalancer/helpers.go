@@ -17,12 +17,15 @@
 package loadbalancer
 
 import (
+	"fmt"
+	"net"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	vapi_errors "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
 )
 
 func namespacedNameFromService(service *corev1.Service) types.NamespacedName {
@@ -34,19 +37,103 @@ func parseNamespacedName(name string) types.NamespacedName {
 	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
 }
 
-func collectNodeInternalAddresses(nodes []*corev1.Node) map[string]string {
+// collectNodeInternalAddresses maps the internal IP addresses of the nodes of
+// the given family to the node names
+func collectNodeInternalAddresses(nodes []*corev1.Node, family corev1.IPFamily) map[string]string {
 	set := map[string]string{}
 	for _, node := range nodes {
-		for _, addr := range node.Status.Addresses {
-			if addr.Type == corev1.NodeInternalIP {
-				set[addr.Address] = node.Name
-				break
-			}
+		if address := nodeInternalAddress(node, family); address != "" {
+			set[address] = node.Name
 		}
 	}
 	return set
 }
 
+// nodeInternalAddress returns the first internal IP address of the node of the
+// given family, or its first internal IP address if it has none of the family
+func nodeInternalAddress(node *corev1.Node, family corev1.IPFamily) string {
+	first := ""
+	for _, addr := range node.Status.Addresses {
+		if addr.Type != corev1.NodeInternalIP {
+			continue
+		}
+		if addressFamily(addr.Address) == family {
+			return addr.Address
+		}
+		if first == "" {
+			first = addr.Address
+		}
+	}
+	return first
+}
+
+func addressFamily(address string) corev1.IPFamily {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return corev1.IPv6Protocol
+	}
+	return corev1.IPv4Protocol
+}
+
+// serviceIPFamily returns the primary IP family of the service, IPv4 if unset
+func serviceIPFamily(service *corev1.Service) corev1.IPFamily {
+	if len(service.Spec.IPFamilies) > 0 {
+		return service.Spec.IPFamilies[0]
+	}
+	return corev1.IPv4Protocol
+}
+
+// isDrainingNode returns true if the pool members of the node must not get new
+// connections, as it is cordoned or excluded from the load balancers
+func isDrainingNode(node *corev1.Node) bool {
+	return node.Spec.Unschedulable || isExcludedNode(node)
+}
+
+func filterNodes(nodes []*corev1.Node, keep func(*corev1.Node) bool) []*corev1.Node {
+	var filtered []*corev1.Node
+	for _, node := range nodes {
+		if keep(node) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// snatTranslationType returns the type of the SNAT translation of a pool,
+// empty if it has none
+func snatTranslationType(snatTranslation *data.StructValue) string {
+	if snatTranslation == nil {
+		return ""
+	}
+	snatType, err := snatTranslation.String("type")
+	if err != nil {
+		return ""
+	}
+	return snatType
+}
+
+// parseTier1Path returns the id of the T1 gateway of a policy path
+// /infra/tier-1s/<id>
+func parseTier1Path(path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 || parts[0] != "" || parts[1] != "infra" || parts[2] != "tier-1s" || parts[3] == "" {
+		return "", fmt.Errorf("invalid T1 gateway path %q", path)
+	}
+	return parts[3], nil
+}
+
+// parseEdgeClusterPath returns the site, enforcement point and edge cluster
+// ids of a policy path
+// /infra/sites/<site>/enforcement-points/<enforcement point>/edge-clusters/<id>
+func parseEdgeClusterPath(path string) (string, string, string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 8 || parts[0] != "" || parts[1] != "infra" || parts[2] != "sites" ||
+		parts[4] != "enforcement-points" || parts[6] != "edge-clusters" ||
+		parts[3] == "" || parts[5] == "" || parts[7] == "" {
+		return "", "", "", fmt.Errorf("invalid edge cluster path %q", path)
+	}
+	return parts[3], parts[5], parts[7], nil
+}
+
 func strptr(s string) *string {
 	return &s
 }
@@ -0,0 +1,108 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+// xForwardedForNone disables the X-Forwarded-For header of the class for a service
+const xForwardedForNone = "none"
+
+// httpProfileOptions are the settings of the HTTP application profile of the
+// TCP virtual servers of a service
+type httpProfileOptions struct {
+	// xForwardedFor is the NSX-T X-Forwarded-For handling, INSERT or REPLACE
+	xForwardedFor string
+	// serverKeepAlive ties the backend connection to the client connection
+	serverKeepAlive bool
+}
+
+// parseXForwardedFor returns the NSX-T X-Forwarded-For handling of the
+// configured value, empty if the header is not handled
+func parseXForwardedFor(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", xForwardedForNone:
+		return "", nil
+	case "insert":
+		return model.LBHttpProfile_X_FORWARDED_FOR_INSERT, nil
+	case "replace":
+		return model.LBHttpProfile_X_FORWARDED_FOR_REPLACE, nil
+	default:
+		return "", fmt.Errorf("invalid X-Forwarded-For %q, expected insert, replace or %s", value, xForwardedForNone)
+	}
+}
+
+// newHTTPProfileOptions returns the HTTP application profile settings of the
+// service, the annotations overriding the defaults of the class. It returns
+// nil if the service uses the TCP application profile of the class, unless
+// it terminates TLS, which requires an HTTP application profile.
+func newHTTPProfileOptions(service *corev1.Service, class *loadBalancerClass, terminatesTLS bool) (*httpProfileOptions, error) {
+	options := httpProfileOptions{
+		xForwardedFor:   class.xForwardedFor,
+		serverKeepAlive: class.serverKeepAlive,
+	}
+	if value, ok := service.GetAnnotations()[XForwardedForAnnotation]; ok {
+		xForwardedFor, err := parseXForwardedFor(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %s", XForwardedForAnnotation, err)
+		}
+		options.xForwardedFor = xForwardedFor
+	}
+	if value, ok := service.GetAnnotations()[ServerKeepAliveAnnotation]; ok {
+		serverKeepAlive, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %s", ServerKeepAliveAnnotation, err)
+		}
+		options.serverKeepAlive = serverKeepAlive
+	}
+	if options.xForwardedFor == "" && !terminatesTLS {
+		return nil, nil
+	}
+	return &options, nil
+}
+
+// xForwardedForPtr returns the X-Forwarded-For handling of the profile, nil
+// if the header is not handled
+func (o *httpProfileOptions) xForwardedForPtr() *string {
+	if o.xForwardedFor == "" {
+		return nil
+	}
+	return strptr(o.xForwardedFor)
+}
+
+// matchHTTPProfile returns true if the profile has the settings of the options
+func (o *httpProfileOptions) matchHTTPProfile(profile *model.LBHttpProfile) bool {
+	return safeEquals(profile.XForwardedFor, o.xForwardedForPtr()) &&
+		profile.ServerKeepAlive != nil && *profile.ServerKeepAlive == o.serverKeepAlive
+}
+
+// hasTCPMapping returns true if one of the mappings is a TCP one
+func hasTCPMapping(mappings []Mapping) bool {
+	for _, mapping := range mappings {
+		if mapping.Protocol == corev1.ProtocolTCP {
+			return true
+		}
+	}
+	return false
+}
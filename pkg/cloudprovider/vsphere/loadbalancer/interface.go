@@ -17,21 +17,34 @@
 package loadbalancer
 
 import (
+	"context"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clientset "k8s.io/client-go/kubernetes"
 	cloudprovider "k8s.io/cloud-provider"
 
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 )
 
 // LBProvider is the interface used call the load balancer functionality
 // It extends the cloud controller manager LoadBalancer interface by an
-// initialization function
+// initialization function. It is implemented by the backends provisioning
+// the load balancers, NSX-T and Avi.
 type LBProvider interface {
 	cloudprovider.LoadBalancer
 	Initialize(clusterName string, client clientset.Interface, stop <-chan struct{})
 	CleanupServices(clusterName string, services map[types.NamespacedName]corev1.Service, ensureLBServiceDeleted bool) error
+	// PendingReconciles returns the number of reconciles running or waiting
+	// for each Service, keyed by namespace/name
+	PendingReconciles() map[string]int
+	// ClassNames returns the sorted names of the load balancer classes
+	ClassNames() []string
+	// CheckHealth returns an error if the backend cannot be read, the
+	// LbService from NSX-T or the cloud from Avi
+	CheckHealth(ctx context.Context) error
 }
 
 // NSXTAccess provides methods for dealing with NSX-T objects
@@ -44,10 +57,15 @@ type NSXTAccess interface {
 	UpdateLoadBalancerService(lbService *model.LBService) error
 	// DeleteLoadBalancerService deletes a LbService by id
 	DeleteLoadBalancerService(id string) error
-
-	// CreateVirtualServer creates a virtual server
-	CreateVirtualServer(clusterName string, objectName types.NamespacedName, class LBClass, ipAddress string, mapping Mapping,
-		lbServicePath, applicationProfilePath string, poolPath *string) (*model.LBVirtualServer, error)
+	// CreateZonalLoadBalancerService creates the LbService of a zone on the T1 gateway of the zone
+	CreateZonalLoadBalancerService(clusterName, zone, tier1GatewayPath string) (*model.LBService, error)
+	// FindZonalLoadBalancerService finds the LbService of a zone by cluster name and zone
+	FindZonalLoadBalancerService(clusterName, zone string) (*model.LBService, error)
+
+	// CreateVirtualServer creates a virtual server named after the load
+	// balancer, terminating TLS if clientSSL is not nil
+	CreateVirtualServer(clusterName string, objectName types.NamespacedName, lbName string, class LBClass, ipAddress string, mapping Mapping,
+		lbServicePath, applicationProfilePath string, poolPath *string, clientSSL *model.LBClientSslProfileBinding) (*model.LBVirtualServer, error)
 	// FindVirtualServers finds a virtual server by cluster and object name
 	FindVirtualServers(clusterName string, objectName types.NamespacedName) ([]*model.LBVirtualServer, error)
 	// ListVirtualServers finds all virtual servers for a cluster
@@ -57,9 +75,10 @@ type NSXTAccess interface {
 	// DeleteVirtualServer deletes a virtual server by id
 	DeleteVirtualServer(id string) error
 
-	// CreatePool creates a LbPool
-	CreatePool(clusterName string, objectName types.NamespacedName, mapping Mapping, members []model.LBPoolMember,
-		activeMonitorPaths []string) (*model.LBPool, error)
+	// CreatePool creates a LbPool named after the load balancer, without
+	// SNAT if preserveClientIP is set
+	CreatePool(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping, members []model.LBPoolMember,
+		activeMonitorPaths []string, preserveClientIP bool) (*model.LBPool, error)
 	// GetPool gets a LbPool by id
 	GetPool(id string) (*model.LBPool, error)
 	// FindPool finds a LbPool for a mapping
@@ -70,11 +89,16 @@ type NSXTAccess interface {
 	ListPools(clusterName string) ([]*model.LBPool, error)
 	// UpdatePool updates a LbPool
 	UpdatePool(*model.LBPool) error
+	// SnatTranslation returns the SNAT translation of a LbPool, disabled if
+	// preserveClientIP is set or SNAT is disabled by the configuration
+	SnatTranslation(preserveClientIP bool) (*data.StructValue, error)
 	// DeletePool deletes a LbPool by id
 	DeletePool(id string) error
 
 	// FindIPPoolByName finds an IP pool by name
 	FindIPPoolByName(poolName string) (string, error)
+	// GetIPPoolUsage gets the usage of an IP pool, nil if NSX-T does not report it
+	GetIPPoolUsage(ipPoolID string) (*model.PolicyPoolUsage, error)
 
 	// GetAppProfilePath gets the application profile for given loadbalancer class and protocol
 	GetAppProfilePath(class LBClass, protocol corev1.Protocol) (string, error)
@@ -85,11 +109,20 @@ type NSXTAccess interface {
 	ListExternalIPAddresses(ipPoolID string, clusterName string) ([]*model.IpAddressAllocation, error)
 	// FindExternalIPAddressForObject finds an IP address belonging to an object
 	FindExternalIPAddressForObject(ipPoolID string, clusterName string, objectName types.NamespacedName) (allocation *model.IpAddressAllocation, ipAddress *string, err error)
+	// AllocateNamedExternalIPAddress allocates an IP address from the given IP pool under an allocation name, bound to the service
+	AllocateNamedExternalIPAddress(ipPoolID string, clusterName string, allocationName string, objectName types.NamespacedName) (allocation *model.IpAddressAllocation, ipAddress *string, err error)
+	// FindNamedExternalIPAddress finds an IP address allocated under an allocation name
+	FindNamedExternalIPAddress(ipPoolID string, clusterName string, allocationName string) (allocation *model.IpAddressAllocation, ipAddress *string, err error)
 	// ReleaseExternalIPAddress releases an allocated IP address
 	ReleaseExternalIPAddress(ipPoolID string, id string) error
-
-	// CreateTCPMonitorProfile creates a LBTcpMonitorProfile
-	CreateTCPMonitorProfile(clusterName string, objectName types.NamespacedName, mapping Mapping) (*model.LBTcpMonitorProfile, error)
+	// HoldExternalIPAddress detaches an allocated IP address from its service and tags it with the release time
+	HoldExternalIPAddress(ipPoolID string, allocation *model.IpAddressAllocation, releasedAt time.Time) error
+	// UpdateExternalIPAddress updates the display name and tags of an IP address allocation
+	UpdateExternalIPAddress(ipPoolID string, allocation *model.IpAddressAllocation) error
+
+	// CreateTCPMonitorProfile creates a LBTcpMonitorProfile named after the
+	// load balancer and the member port
+	CreateTCPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping) (*model.LBTcpMonitorProfile, error)
 	// FindTCPMonitors finds a LBTcpMonitorProfile by cluster and object name
 	FindTCPMonitorProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBTcpMonitorProfile, error)
 	// ListTCPMonitorProfile lists LBTcpMonitorProfile by cluster
@@ -98,6 +131,42 @@ type NSXTAccess interface {
 	UpdateTCPMonitorProfile(monitor *model.LBTcpMonitorProfile) error
 	// DeleteTCPMonitorProfile deletes a LBTcpMonitorProfile by id
 	DeleteTCPMonitorProfile(id string) error
+
+	// CreateUDPMonitorProfile creates a LBUdpMonitorProfile named after the
+	// load balancer and the member port
+	CreateUDPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping, healthCheck udpHealthCheck) (*model.LBUdpMonitorProfile, error)
+	// FindUDPMonitorProfiles finds a LBUdpMonitorProfile by cluster and object name
+	FindUDPMonitorProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBUdpMonitorProfile, error)
+	// ListUDPMonitorProfiles lists LBUdpMonitorProfile by cluster
+	ListUDPMonitorProfiles(clusterName string) ([]*model.LBUdpMonitorProfile, error)
+	// UpdateUDPMonitorProfile updates a LBUdpMonitorProfile
+	UpdateUDPMonitorProfile(monitor *model.LBUdpMonitorProfile) error
+	// DeleteUDPMonitorProfile deletes a LBUdpMonitorProfile by id
+	DeleteUDPMonitorProfile(id string) error
+
+	// CreateHTTPMonitorProfile creates a LBHttpMonitorProfile named after the
+	// load balancer, probing the health check node port of the service
+	CreateHTTPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, healthCheckNodePort int) (*model.LBHttpMonitorProfile, error)
+	// FindHTTPMonitorProfiles finds a LBHttpMonitorProfile by cluster and object name
+	FindHTTPMonitorProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBHttpMonitorProfile, error)
+	// ListHTTPMonitorProfiles lists LBHttpMonitorProfile by cluster
+	ListHTTPMonitorProfiles(clusterName string) ([]*model.LBHttpMonitorProfile, error)
+	// UpdateHTTPMonitorProfile updates a LBHttpMonitorProfile
+	UpdateHTTPMonitorProfile(monitor *model.LBHttpMonitorProfile) error
+	// DeleteHTTPMonitorProfile deletes a LBHttpMonitorProfile by id
+	DeleteHTTPMonitorProfile(id string) error
+
+	// CreateHTTPAppProfile creates a LBHttpProfile named after the load
+	// balancer, handling X-Forwarded-For as given by the options
+	CreateHTTPAppProfile(clusterName string, objectName types.NamespacedName, lbName string, options httpProfileOptions) (*model.LBHttpProfile, error)
+	// FindHTTPAppProfiles finds a LBHttpProfile by cluster and object name
+	FindHTTPAppProfiles(clusterName string, objectName types.NamespacedName) ([]*model.LBHttpProfile, error)
+	// ListHTTPAppProfiles lists LBHttpProfile by cluster
+	ListHTTPAppProfiles(clusterName string) ([]*model.LBHttpProfile, error)
+	// UpdateHTTPAppProfile updates a LBHttpProfile
+	UpdateHTTPAppProfile(profile *model.LBHttpProfile) error
+	// DeleteHTTPAppProfile deletes a LBHttpProfile by id
+	DeleteHTTPAppProfile(id string) error
 }
 
 // Reference references an object either by identifier or name
@@ -0,0 +1,164 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics/legacyregistry"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// IPPoolUsageHighReason is the reason of the event emitted when the
+// utilization of an IP pool crosses a threshold
+const IPPoolUsageHighReason = "IPPoolUsageHigh"
+
+var (
+	// ipPoolUtilizationMetric is the ratio of the allocated IPs of the IP
+	// pools of the load balancer classes
+	ipPoolUtilizationMetric = metrics.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "loadbalancer_ip_pool_utilization_ratio",
+			Help: "Ratio of the allocated IPs of an IP pool of the load balancer classes",
+		},
+		[]string{"ip_pool"},
+	)
+
+	// ipPoolThresholdCrossingsMetric counts the crossings of the utilization
+	// thresholds of the IP pools
+	ipPoolThresholdCrossingsMetric = metrics.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "loadbalancer_ip_pool_threshold_crossings",
+			Help: "Crossings of an utilization threshold of an IP pool of the load balancer classes",
+		},
+		[]string{"ip_pool", "threshold"},
+	)
+)
+
+func init() {
+	legacyregistry.RawMustRegister(ipPoolUtilizationMetric)
+	legacyregistry.RawMustRegister(ipPoolThresholdCrossingsMetric)
+}
+
+// ipPoolUsageWatcher compares the utilization of the IP pools with the
+// thresholds, so that the IP pools can be expanded before the provisioning
+// of Services fails. When the utilization crosses a threshold upwards, a
+// warning event is emitted on the IP pool.
+type ipPoolUsageWatcher struct {
+	// thresholds are the sorted utilization percentages
+	thresholds []float64
+	recorder   record.EventRecorder
+	// namespace of the events, which refer to the IP pools
+	namespace string
+	// levels is the highest threshold crossed by each IP pool, 0 for none
+	levels map[string]float64
+}
+
+func newIPPoolUsageWatcher(thresholds []float64, recorder record.EventRecorder) *ipPoolUsageWatcher {
+	return &ipPoolUsageWatcher{
+		thresholds: thresholds,
+		recorder:   recorder,
+		namespace:  metav1.NamespaceSystem,
+		levels:     map[string]float64{},
+	}
+}
+
+// utilization returns the percentage of the allocated IPs of an IP pool,
+// false if NSX-T does not report the counts
+func utilization(usage *model.PolicyPoolUsage) (float64, int64, int64, bool) {
+	if usage == nil || usage.TotalIps == nil || *usage.TotalIps <= 0 {
+		return 0, 0, 0, false
+	}
+	total := *usage.TotalIps
+	var allocated int64
+	switch {
+	case usage.AllocatedIpAllocations != nil:
+		allocated = *usage.AllocatedIpAllocations
+	case usage.AvailableIps != nil:
+		allocated = total - *usage.AvailableIps
+	default:
+		return 0, 0, 0, false
+	}
+	return 100 * float64(allocated) / float64(total), allocated, total, true
+}
+
+// check updates the utilization of the IP pool and emits an event if it
+// crossed a threshold upwards
+func (w *ipPoolUsageWatcher) check(ipPoolID string, usage *model.PolicyPoolUsage) {
+	percentage, allocated, total, ok := utilization(usage)
+	if !ok {
+		klog.V(4).Infof("NSX-T does not report the usage of IP pool %s", ipPoolID)
+		return
+	}
+	ipPoolUtilizationMetric.WithLabelValues(ipPoolID).Set(percentage / 100)
+
+	level := 0.0
+	for _, threshold := range w.thresholds {
+		if percentage >= threshold {
+			level = threshold
+		}
+	}
+	previous := w.levels[ipPoolID]
+	w.levels[ipPoolID] = level
+	if level <= previous {
+		if level < previous {
+			klog.Infof("IP pool %s is %.0f%% used, below the %s%% threshold again", ipPoolID, percentage, formatThreshold(previous))
+		}
+		return
+	}
+
+	ipPoolThresholdCrossingsMetric.WithLabelValues(ipPoolID, formatThreshold(level)).Inc()
+	message := fmt.Sprintf("IP pool %s is %.0f%% used (%d of %d IPs), above the %s%% threshold, expand it before the provisioning of load balancers fails",
+		ipPoolID, percentage, allocated, total, formatThreshold(level))
+	klog.Warning(message)
+	if w.recorder != nil {
+		ref := &corev1.ObjectReference{
+			Kind:      "IPPool",
+			Namespace: w.namespace,
+			Name:      ipPoolID,
+		}
+		w.recorder.Event(ref, corev1.EventTypeWarning, IPPoolUsageHighReason, message)
+	}
+}
+
+func formatThreshold(threshold float64) string {
+	return strconv.FormatFloat(threshold, 'f', -1, 64)
+}
+
+// checkIPPoolUsage checks the utilization of the IP pools if thresholds are
+// configured. Failures are only logged, as they must not stop the cleanup.
+func (p *lbProvider) checkIPPoolUsage(ipPoolIDs []string) {
+	if p.ipPoolUsage == nil {
+		return
+	}
+	for _, ipPoolID := range ipPoolIDs {
+		usage, err := p.access.GetIPPoolUsage(ipPoolID)
+		if err != nil {
+			klog.Warningf("reading the usage of IP pool %s failed: %v", ipPoolID, err)
+			continue
+		}
+		p.ipPoolUsage.check(ipPoolID, usage)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/client-go/tools/record"
+)
+
+// ipPoolUsageAccess returns the usage of the IP pools by ID
+type ipPoolUsageAccess struct {
+	NSXTAccess
+	usages map[string]*model.PolicyPoolUsage
+}
+
+func (a *ipPoolUsageAccess) GetIPPoolUsage(ipPoolID string) (*model.PolicyPoolUsage, error) {
+	usage, ok := a.usages[ipPoolID]
+	if !ok {
+		return nil, fmt.Errorf("IP pool %s not found", ipPoolID)
+	}
+	return usage, nil
+}
+
+func poolUsage(total, allocated int64) *model.PolicyPoolUsage {
+	return &model.PolicyPoolUsage{TotalIps: &total, AllocatedIpAllocations: &allocated}
+}
+
+func TestCheckIPPoolUsage(t *testing.T) {
+	access := &ipPoolUsageAccess{usages: map[string]*model.PolicyPoolUsage{
+		"usage-pool": poolUsage(100, 50),
+		"no-counts":  {},
+	}}
+	recorder := record.NewFakeRecorder(10)
+	p := &lbProvider{
+		lbService:   newLbService(access, ""),
+		ipPoolUsage: newIPPoolUsageWatcher([]float64{80, 95}, recorder),
+	}
+	events := func() []string {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+
+	p.checkIPPoolUsage([]string{"usage-pool", "no-counts", "missing"})
+	assert.Empty(t, events())
+	assert.Equal(t, 0.5, testutil.ToFloat64(ipPoolUtilizationMetric.WithLabelValues("usage-pool")))
+
+	access.usages["usage-pool"] = poolUsage(100, 96)
+	p.checkIPPoolUsage([]string{"usage-pool"})
+	got := events()
+	if assert.Len(t, got, 1) {
+		assert.True(t, strings.HasPrefix(got[0], "Warning "+IPPoolUsageHighReason), got[0])
+		assert.Contains(t, got[0], "95% threshold")
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(ipPoolThresholdCrossingsMetric.WithLabelValues("usage-pool", "95")))
+
+	// no repeated event while the utilization stays above the threshold
+	p.checkIPPoolUsage([]string{"usage-pool"})
+	assert.Empty(t, events())
+
+	// dropping below a threshold rearms it
+	access.usages["usage-pool"] = poolUsage(100, 85)
+	p.checkIPPoolUsage([]string{"usage-pool"})
+	assert.Empty(t, events())
+	access.usages["usage-pool"] = poolUsage(100, 95)
+	p.checkIPPoolUsage([]string{"usage-pool"})
+	assert.Len(t, events(), 1)
+}
+
+func TestIPPoolUtilization(t *testing.T) {
+	total := int64(200)
+	available := int64(50)
+	percentage, allocated, _, ok := utilization(&model.PolicyPoolUsage{TotalIps: &total, AvailableIps: &available})
+	assert.True(t, ok)
+	assert.Equal(t, int64(150), allocated)
+	assert.Equal(t, 75.0, percentage)
+
+	zero := int64(0)
+	_, _, _, ok = utilization(&model.PolicyPoolUsage{TotalIps: &zero, AvailableIps: &zero})
+	assert.False(t, ok)
+	_, _, _, ok = utilization(nil)
+	assert.False(t, ok)
+}
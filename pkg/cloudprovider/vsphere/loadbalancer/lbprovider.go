@@ -19,12 +19,19 @@ package loadbalancer
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
 )
@@ -32,6 +39,67 @@ import (
 const (
 	// LoadBalancerClassAnnotation is the optional class annotation at the service
 	LoadBalancerClassAnnotation = "loadbalancer.vmware.io/class"
+	// IPAllocationNameAnnotation is the optional annotation at the service naming
+	// the IP address allocation to use. Named allocations outlive the service,
+	// so the same IP address is reused when the service is recreated, until
+	// the cleanup releases them once no service names them.
+	IPAllocationNameAnnotation = "loadbalancer.vmware.io/ip-allocation-name"
+	// PoolMemberPortsAnnotation is the optional annotation at the service
+	// overriding the port of the pool members, which is the node port
+	// otherwise. Its value is a comma separated list of
+	// <service port name or number>:<member port>, for instance for backends
+	// exposing a hostPort on the node IPs.
+	PoolMemberPortsAnnotation = "loadbalancer.vmware.io/pool-member-ports"
+	// LoadBalancerNameAnnotation is the optional annotation at the service
+	// overriding the name of its load balancer, which is the base of the
+	// display names of its virtual servers, pools and monitor profiles.
+	LoadBalancerNameAnnotation = "loadbalancer.vmware.io/name"
+	// NodePortMappingsAnnotation is the annotation published at the service
+	// if publishNodePortMappings is set. Its value is a JSON list of the
+	// virtual servers with their vip, port, protocol, nodePort, memberPort
+	// and poolId, for node local dataplanes bypassing the load balancer. It is
+	// removed when the load balancer is deleted.
+	NodePortMappingsAnnotation = "loadbalancer.vmware.io/node-port-mappings"
+	// UDPHealthCheckSendAnnotation is the optional annotation at the service
+	// enabling the health check of its UDP ports, which sends its value to the
+	// member port and expects the value of UDPHealthCheckReceiveAnnotation in
+	// the response.
+	UDPHealthCheckSendAnnotation = "loadbalancer.vmware.io/udp-health-check-send"
+	// UDPHealthCheckReceiveAnnotation is the annotation at the service giving
+	// the data expected in the response to the UDP health check.
+	UDPHealthCheckReceiveAnnotation = "loadbalancer.vmware.io/udp-health-check-receive"
+	// XForwardedForAnnotation is the optional annotation at the service
+	// overriding the X-Forwarded-For handling of its load balancer class.
+	// With insert or replace, the TCP ports are served by an HTTP application
+	// profile adding the client IP address to the requests, none disables it.
+	XForwardedForAnnotation = "loadbalancer.vmware.io/x-forwarded-for"
+	// ServerKeepAliveAnnotation is the optional annotation at the service
+	// overriding the server keep-alive of its load balancer class. If true,
+	// the backend connection is closed with the client connection instead of
+	// being reused for other clients. Only used with X-Forwarded-For.
+	ServerKeepAliveAnnotation = "loadbalancer.vmware.io/server-keep-alive"
+	// ZoneAnnotation is the optional annotation at the service placing its
+	// virtual servers on the T1 gateway of a zone of its load balancer class,
+	// instead of the zone all its endpoints run in. It is only used when the
+	// virtual servers are created.
+	ZoneAnnotation = "loadbalancer.vmware.io/zone"
+	// TLSCertificateAnnotation is the optional annotation at the service
+	// terminating TLS on its TCP ports with the certificate of the NSX-T
+	// certificate store of this ID or policy path. The ports are served by an
+	// HTTP application profile and forward plain HTTP to the pool members.
+	TLSCertificateAnnotation = "loadbalancer.vmware.io/tls-certificate"
+	// TLSPortsAnnotation is the optional annotation at the service limiting
+	// the TLS termination to its comma separated list of ports, all TCP
+	// ports if unset.
+	TLSPortsAnnotation = "loadbalancer.vmware.io/tls-ports"
+	// ClientSSLProfileAnnotation is the optional annotation at the service
+	// overriding the client SSL profile of its load balancer class with this
+	// policy path, for the ports terminating TLS.
+	ClientSSLProfileAnnotation = "loadbalancer.vmware.io/client-ssl-profile"
+	// ExcludeNodeAnnotation is the optional annotation at a node excluding
+	// it from the pools of all load balancers if true, like the
+	// node.kubernetes.io/exclude-from-external-load-balancers label.
+	ExcludeNodeAnnotation = "loadbalancer.vmware.io/exclude"
 )
 
 var (
@@ -43,8 +111,37 @@ var (
 
 type lbProvider struct {
 	*lbService
-	classes *loadBalancerClasses
-	keyLock *keyLock
+	classes           *loadBalancerClasses
+	keyLock           *keyLock
+	reachabilityCheck bool
+	reachability      *reachabilityChecker
+	// provisioningDeadline is the duration after which a Service still
+	// without ingress is reported as stuck, 0 to not report it
+	provisioningDeadline time.Duration
+	provisioning         *provisioningTracker
+	// usageReportNamespace and usageReportName name the ConfigMap the usage
+	// per namespace is reported to, empty to only expose metrics
+	usageReportNamespace string
+	usageReportName      string
+	usageReporter        *usageReporter
+	// ipPoolUsageThresholds are the utilization percentages of the IP pools
+	// warned about, empty to not watch the IP pools
+	ipPoolUsageThresholds []float64
+	ipPoolUsage           *ipPoolUsageWatcher
+	// classPrefix is the prefix of the spec.loadBalancerClass of the
+	// Services reconciled by the class controller, empty to not run it
+	classPrefix string
+	// publishNodePortMappings enables the NodePortMappingsAnnotation
+	publishNodePortMappings bool
+	nodePortMappings        *nodePortMappingPublisher
+	// previousClusterName is the cluster name the NSX-T objects are migrated
+	// from, empty if the cluster was not renamed
+	previousClusterName string
+	// migrated is closed once the NSX-T objects are migrated, nil if there
+	// is no migration
+	migrated chan struct{}
+	// dryRun skips the NSX-T operations mutating objects, nil to perform them
+	dryRun *dryRunBroker
 }
 
 // ClusterName contains the cluster-name flag injected from main, needed for cleanup
@@ -52,7 +149,8 @@ var ClusterName string
 
 var _ LBProvider = &lbProvider{}
 
-// NewLBProvider creates a new LBProvider
+// NewLBProvider creates a new LBProvider of the backend of the config, the
+// NSX-T connector is not used by the avi backend
 func NewLBProvider(cfg *config.LBConfig, connector client.Connector) (LBProvider, error) {
 	if cfg == nil {
 		return nil, nil
@@ -60,11 +158,20 @@ func NewLBProvider(cfg *config.LBConfig, connector client.Connector) (LBProvider
 	if !cfg.IsEnabled() {
 		return nil, nil
 	}
+	if cfg.LoadBalancer.Backend == config.BackendAvi {
+		return newAviLBProvider(cfg)
+	}
 
 	broker, err := NewNsxtBroker(connector)
 	if err != nil {
 		return nil, err
 	}
+	var dryRun *dryRunBroker
+	if cfg.LoadBalancer.DryRun {
+		klog.Warningf("load balancer dry run enabled, NSX-T objects are not created, updated or deleted")
+		dryRun = newDryRunBroker(broker)
+		broker = dryRun
+	}
 	access, err := NewNSXTAccess(broker, cfg)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating access handler failed")
@@ -73,23 +180,154 @@ func NewLBProvider(cfg *config.LBConfig, connector client.Connector) (LBProvider
 	if err != nil {
 		return nil, errors.Wrap(err, "creating load balancer classes failed")
 	}
+	provisioningDeadline, err := cfg.LoadBalancer.ProvisioningDeadlineDuration()
+	if err != nil {
+		return nil, err
+	}
+	releaseQuarantine, err := cfg.LoadBalancer.ReleaseQuarantineDuration()
+	if err != nil {
+		return nil, err
+	}
+	namedAllocationRetention, err := cfg.LoadBalancer.NamedAllocationRetentionDuration()
+	if err != nil {
+		return nil, err
+	}
+	usageReportNamespace, usageReportName, err := config.ParseUsageReportConfigMap(cfg.LoadBalancer.UsageReportConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	ipPoolUsageThresholds, err := config.ParseIPPoolUsageThresholds(cfg.LoadBalancer.IPPoolUsageThresholds)
+	if err != nil {
+		return nil, err
+	}
+	nameTemplate, err := config.ParseNameTemplate(cfg.LoadBalancer.NameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	lbService := newLbService(access, cfg.LoadBalancer.LBServiceID)
+	lbService.releaseQuarantine = releaseQuarantine
+	lbService.namedAllocationRetention = namedAllocationRetention
+	lbService.nameTemplate = nameTemplate
+	prefetchAppProfiles(access, classes)
 	return &lbProvider{
-		lbService: newLbService(access, cfg.LoadBalancer.LBServiceID),
+		lbService: lbService,
 		classes:   classes,
 		keyLock:   newKeyLock(),
+
+		reachabilityCheck:    cfg.LoadBalancer.ReachabilityCheck,
+		provisioningDeadline: provisioningDeadline,
+		usageReportNamespace: usageReportNamespace,
+		usageReportName:      usageReportName,
+
+		ipPoolUsageThresholds: ipPoolUsageThresholds,
+		classPrefix:           cfg.LoadBalancer.ClassPrefix,
+
+		publishNodePortMappings: cfg.LoadBalancer.PublishNodePortMappings,
+		previousClusterName:     cfg.LoadBalancer.PreviousClusterName,
+		dryRun:                  dryRun,
 	}, nil
 }
 
 func (p *lbProvider) Initialize(clusterName string, client clientset.Interface, stop <-chan struct{}) {
 	if clusterName != "" {
-		go p.cleanup(clusterName, client.CoreV1().Services(""), stop)
+		if p.usageReportName != "" {
+			p.usageReporter = newUsageReporter(client.CoreV1().ConfigMaps(p.usageReportNamespace), p.usageReportName)
+		}
+		if p.previousClusterName != "" && p.previousClusterName != clusterName {
+			p.migrated = make(chan struct{})
+			go func() {
+				if p.migrate(clusterName, stop) {
+					runCleanup(p, clusterName, client.CoreV1().Services(""), stop)
+				}
+			}()
+		} else {
+			go runCleanup(p, clusterName, client.CoreV1().Services(""), stop)
+		}
+	} else {
+		if p.previousClusterName != "" {
+			klog.Warningf("migration from cluster name %s disabled, it requires the cluster name", p.previousClusterName)
+		}
+		if p.releaseQuarantine > 0 {
+			// held IP address allocations are only released by the cleanup
+			klog.Warningf("release quarantine disabled, it requires the cluster name")
+			p.releaseQuarantine = 0
+		}
+		if p.usageReportName != "" {
+			// the usage is accounted by the cleanup
+			klog.Warningf("usage report disabled, it requires the cluster name")
+		}
+		if len(p.ipPoolUsageThresholds) > 0 {
+			// the IP pools are watched by the cleanup
+			klog.Warningf("IP pool usage thresholds disabled, they require the cluster name")
+			p.ipPoolUsageThresholds = nil
+		}
+	}
+	if p.publishNodePortMappings {
+		p.nodePortMappings = newNodePortMappingPublisher(client.CoreV1())
+	}
+	factory := informers.NewSharedInformerFactory(client, classResyncPeriod)
+	p.nodesLister = factory.Core().V1().Nodes().Lister()
+	p.servicesLister = factory.Core().V1().Services().Lister()
+	endpointSlices := factory.Discovery().V1().EndpointSlices()
+	p.endpointSlicesLister = endpointSlices.Lister()
+	p.endpointSlicesSynced = endpointSlices.Informer().HasSynced
+	if p.classPrefix != "" {
+		controller := newClassController(p, p.ownsLoadBalancerClass, clusterName, client, factory)
+		go controller.run(stop)
+	}
+	localTraffic := newLocalTrafficController(p, p.ownsLoadBalancerClass, clusterName, factory)
+	go localTraffic.run(stop)
+	factory.Start(stop)
+	if !p.reachabilityCheck && p.provisioningDeadline == 0 && len(p.ipPoolUsageThresholds) == 0 && p.dryRun == nil {
+		return
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	go func() {
+		<-stop
+		eventBroadcaster.Shutdown()
+	}()
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: AppName})
+	if p.reachabilityCheck {
+		p.reachability = &reachabilityChecker{recorder: recorder}
+	}
+	if p.provisioningDeadline > 0 {
+		p.provisioning = newProvisioningTracker(p.provisioningDeadline, recorder)
+	}
+	if len(p.ipPoolUsageThresholds) > 0 {
+		p.ipPoolUsage = newIPPoolUsageWatcher(p.ipPoolUsageThresholds, recorder)
 	}
+	if p.dryRun != nil {
+		p.dryRun.setRecorder(recorder)
+	}
+}
+
+// PendingReconciles returns the number of reconciles running or waiting for
+// each Service, keyed by namespace/name
+func (p *lbProvider) PendingReconciles() map[string]int {
+	return p.keyLock.Pending()
+}
+
+// CheckHealth returns an error if the LbService cannot be read from NSX-T,
+// because NSX-T is not reachable or rejects the credentials
+func (p *lbProvider) CheckHealth(_ context.Context) error {
+	return p.lbService.checkHealth(ClusterName)
+}
+
+// ClassNames returns the sorted names of the load balancer classes
+func (p *lbProvider) ClassNames() []string {
+	names := p.classes.GetClassNames()
+	sort.Strings(names)
+	return names
 }
 
 // GetLoadBalancer returns the LoadBalancerStatus
 // Implementations must treat the *corev1.Service parameter as read-only and not modify it.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (p *lbProvider) GetLoadBalancer(_ context.Context, clusterName string, service *corev1.Service) (status *corev1.LoadBalancerStatus, exists bool, err error) {
+	if err := p.checkMigrated(); err != nil {
+		return nil, false, err
+	}
 	servers, err := p.access.FindVirtualServers(clusterName, namespacedNameFromService(service))
 	if err != nil {
 		return nil, false, err
@@ -97,56 +335,108 @@ func (p *lbProvider) GetLoadBalancer(_ context.Context, clusterName string, serv
 	if len(servers) == 0 {
 		return nil, false, nil
 	}
-	return newLoadBalancerStatus(servers[0].IpAddress), true, nil
+	var ipAddress, ipv6Address *string
+	for _, server := range servers {
+		if serverIPFamily(server) == corev1.IPv6Protocol {
+			ipv6Address = server.IpAddress
+		} else if ipAddress == nil {
+			ipAddress = server.IpAddress
+		}
+	}
+	return newServiceLoadBalancerStatus(service, ipAddress, ipv6Address), true, nil
 }
 
-func newLoadBalancerStatus(ipAddress *string) *corev1.LoadBalancerStatus {
+func newLoadBalancerStatus(ipAddresses ...*string) *corev1.LoadBalancerStatus {
 	status := &corev1.LoadBalancerStatus{
 		Ingress: []corev1.LoadBalancerIngress{},
 	}
-	if ipAddress != nil {
-		status.Ingress = append(status.Ingress, corev1.LoadBalancerIngress{IP: *ipAddress})
+	for _, ipAddress := range ipAddresses {
+		if ipAddress != nil {
+			status.Ingress = append(status.Ingress, corev1.LoadBalancerIngress{IP: *ipAddress})
+		}
 	}
 	return status
 }
 
+// newServiceLoadBalancerStatus reports the IPv4 and the IPv6 address in the
+// order of the IP families of the service
+func newServiceLoadBalancerStatus(service *corev1.Service, ipAddress, ipv6Address *string) *corev1.LoadBalancerStatus {
+	if serviceIPFamily(service) == corev1.IPv6Protocol {
+		return newLoadBalancerStatus(ipv6Address, ipAddress)
+	}
+	return newLoadBalancerStatus(ipAddress, ipv6Address)
+}
+
 // GetLoadBalancerName returns the name of the load balancer. Implementations must treat the
 // *corev1.Service parameter as read-only and not modify it.
+// The name is taken from the LoadBalancerNameAnnotation or rendered by the
+// name template, see loadBalancerName.
 func (p *lbProvider) GetLoadBalancerName(_ context.Context, clusterName string, service *corev1.Service) string {
-	return *displayNameObject(clusterName, namespacedNameFromService(service))
+	return truncateDisplayName(p.loadBalancerName(clusterName, service))
 }
 
 // EnsureLoadBalancer creates a new load balancer 'name', or updates the existing one. Returns the status of the balancer
 // Implementations must treat the *corev1.Service and *corev1.Node
 // parameters as read-only and not modify them.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
-func (p *lbProvider) EnsureLoadBalancer(_ context.Context, clusterName string, service *corev1.Service, nodes []*corev1.Node) (*corev1.LoadBalancerStatus, error) {
+func (p *lbProvider) EnsureLoadBalancer(ctx context.Context, clusterName string, service *corev1.Service, nodes []*corev1.Node) (*corev1.LoadBalancerStatus, error) {
+	if err := p.checkMigrated(); err != nil {
+		return nil, err
+	}
 	key := namespacedNameFromService(service).String()
 	p.keyLock.Lock(key)
 	defer p.keyLock.Unlock(key)
 
 	class, err := p.classFromService(service)
 	if err != nil {
+		p.provisioning.failed(service, stepClass, err)
 		return nil, err
 	}
 
 	state := newState(p.lbService, clusterName, service, nodes)
 	err = state.Process(class)
+	if err != nil {
+		// do not leave the elements created by this attempt behind
+		if errRollback := state.Rollback(); errRollback != nil {
+			klog.Errorf("%s: %v", key, errRollback)
+		}
+	}
 	status, err2 := state.Finish()
+	if p.dryRun != nil {
+		// the made up IP addresses of a dry run are not published
+		status = service.Status.LoadBalancer.DeepCopy()
+	}
 	if err != nil {
+		p.provisioning.failed(service, state.step, err)
 		return status, err
 	}
-	return status, err2
+	if err2 != nil {
+		p.provisioning.failed(service, state.step, err2)
+		return status, err2
+	}
+	p.provisioning.done(service)
+	p.reachability.check(service, status)
+	if err := p.nodePortMappings.publish(ctx, service, state.nodePortMappings()); err != nil {
+		return status, err
+	}
+	return status, nil
 }
 
+// classFromService returns the load balancer class named by the
+// spec.loadBalancerClass of the service if it is set, by the
+// LoadBalancerClassAnnotation otherwise.
 func (p *lbProvider) classFromService(service *corev1.Service) (*loadBalancerClass, error) {
-	annos := service.GetAnnotations()
-	if annos == nil {
-		annos = map[string]string{}
+	var name string
+	if service.Spec.LoadBalancerClass != nil {
+		var ok bool
+		name, ok = p.classNameFromLoadBalancerClass(*service.Spec.LoadBalancerClass)
+		if !ok {
+			return nil, fmt.Errorf("load balancer class %s is not managed by this controller", *service.Spec.LoadBalancerClass)
+		}
+	} else {
+		name = strings.TrimSpace(service.GetAnnotations()[LoadBalancerClassAnnotation])
 	}
-	name, ok := annos[LoadBalancerClassAnnotation]
-	name = strings.TrimSpace(name)
-	if !ok || name == "" {
+	if name == "" {
 		name = config.DefaultLoadBalancerClass
 	}
 
@@ -157,11 +447,37 @@ func (p *lbProvider) classFromService(service *corev1.Service) (*loadBalancerCla
 	return class, nil
 }
 
+// classNameFromLoadBalancerClass returns the name of the load balancer class
+// of a spec.loadBalancerClass, empty for the default class, and false if the
+// spec.loadBalancerClass does not have the class prefix.
+func (p *lbProvider) classNameFromLoadBalancerClass(loadBalancerClass string) (string, bool) {
+	return trimClassPrefix(p.classPrefix, loadBalancerClass)
+}
+
+// trimClassPrefix returns the spec.loadBalancerClass without the class
+// prefix, and false if the prefix is empty or not a prefix of it.
+func trimClassPrefix(classPrefix, loadBalancerClass string) (string, bool) {
+	if classPrefix == "" || !strings.HasPrefix(loadBalancerClass, classPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(loadBalancerClass, classPrefix), true
+}
+
+// ownsLoadBalancerClass returns true if the spec.loadBalancerClass is
+// reconciled by the class controller.
+func (p *lbProvider) ownsLoadBalancerClass(loadBalancerClass string) bool {
+	_, ok := p.classNameFromLoadBalancerClass(loadBalancerClass)
+	return ok
+}
+
 // UpdateLoadBalancer updates hosts under the specified load balancer.
 // Implementations must treat the *corev1.Service and *corev1.Node
 // parameters as read-only and not modify them.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (p *lbProvider) UpdateLoadBalancer(_ context.Context, clusterName string, service *corev1.Service, nodes []*corev1.Node) error {
+	if err := p.checkMigrated(); err != nil {
+		return err
+	}
 	key := namespacedNameFromService(service).String()
 	p.keyLock.Lock(key)
 	defer p.keyLock.Unlock(key)
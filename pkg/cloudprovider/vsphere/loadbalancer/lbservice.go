@@ -19,6 +19,12 @@ package loadbalancer
 import (
 	"fmt"
 	"sync"
+	"text/template"
+	"time"
+
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 type lbService struct {
@@ -26,32 +32,66 @@ type lbService struct {
 	lbServiceID string
 	managed     bool
 	lbLock      sync.Mutex
+	// releaseQuarantine is the duration the IP address allocation of a
+	// deleted service is held before it is released, 0 to release it
+	// immediately
+	releaseQuarantine time.Duration
+	// namedAllocationRetention is the duration a named IP address
+	// allocation no service names any longer is held before it is
+	// released, 0 to never release it
+	namedAllocationRetention time.Duration
+	// nameTemplate renders the names of the load balancers, nil for the
+	// default names
+	nameTemplate *template.Template
+	// nodesLister finds the nodes excluded from the load balancers, whose
+	// pool members are drained rather than removed, nil if not initialized
+	nodesLister corelisters.NodeLister
+	// servicesLister finds the services bound to named IP address
+	// allocations, nil if not initialized
+	servicesLister corelisters.ServiceLister
+	// endpointSlicesLister finds the nodes running the endpoints of the
+	// services with the Local external traffic policy, nil if not initialized
+	endpointSlicesLister discoverylisters.EndpointSliceLister
+	endpointSlicesSynced cache.InformerSynced
 }
 
 func newLbService(access NSXTAccess, lbServiceID string) *lbService {
 	return &lbService{access: access, lbServiceID: lbServiceID, managed: lbServiceID == ""}
 }
 
-func (s *lbService) getOrCreateLoadBalancerService(clusterName string) (string, error) {
+// getOrCreateLoadBalancerService returns the path of the LbService and
+// whether it was created by this call
+func (s *lbService) getOrCreateLoadBalancerService(clusterName string) (string, bool, error) {
 	s.lbLock.Lock()
 	defer s.lbLock.Unlock()
 
 	lbService, err := s.access.FindLoadBalancerService(clusterName, s.lbServiceID)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	if lbService != nil {
-		return *lbService.Path, nil
+		return *lbService.Path, false, nil
 	}
 	if s.managed {
 		lbService, err = s.access.CreateLoadBalancerService(clusterName)
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 		s.lbServiceID = *lbService.Id
-		return *lbService.Path, nil
+		return *lbService.Path, true, nil
 	}
-	return "", fmt.Errorf("no load balancer service found with id %s", s.lbServiceID)
+	return "", false, fmt.Errorf("no load balancer service found with id %s", s.lbServiceID)
+}
+
+// checkHealth reads the LbService, a missing managed LbService is created on
+// the next reconcile and is not an error
+func (s *lbService) checkHealth(clusterName string) error {
+	s.lbLock.Lock()
+	id := s.lbServiceID
+	s.lbLock.Unlock()
+
+	_, err := s.access.FindLoadBalancerService(clusterName, id)
+	return err
 }
 
 func (s *lbService) removeLoadBalancerServiceIfUnused(clusterName string) error {
@@ -81,3 +121,49 @@ func (s *lbService) removeLoadBalancerServiceIfUnused(clusterName string) error
 	}
 	return nil
 }
+
+// getOrCreateZonalLoadBalancerService returns the path of the LbService of
+// the zone, created on the T1 gateway of the zone if missing, and whether it
+// was created by this call. Zonal LbServices are always managed.
+func (s *lbService) getOrCreateZonalLoadBalancerService(clusterName, zone, tier1GatewayPath string) (string, bool, error) {
+	s.lbLock.Lock()
+	defer s.lbLock.Unlock()
+
+	lbService, err := s.access.FindZonalLoadBalancerService(clusterName, zone)
+	if err != nil {
+		return "", false, err
+	}
+	if lbService != nil {
+		return *lbService.Path, false, nil
+	}
+	lbService, err = s.access.CreateZonalLoadBalancerService(clusterName, zone, tier1GatewayPath)
+	if err != nil {
+		return "", false, err
+	}
+	return *lbService.Path, true, nil
+}
+
+// removeZonalLoadBalancerServiceIfUnused deletes the LbService of the zone if
+// none of the virtual servers of the cluster is attached to it
+func (s *lbService) removeZonalLoadBalancerServiceIfUnused(clusterName, zone string) error {
+	s.lbLock.Lock()
+	defer s.lbLock.Unlock()
+
+	lbService, err := s.access.FindZonalLoadBalancerService(clusterName, zone)
+	if err != nil {
+		return err
+	}
+	if lbService == nil {
+		return nil
+	}
+	virtualServers, err := s.access.ListVirtualServers(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, server := range virtualServers {
+		if safeEquals(server.LbServicePath, lbService.Path) {
+			return nil
+		}
+	}
+	return s.access.DeleteLoadBalancerService(*lbService.Id)
+}
@@ -0,0 +1,210 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	cloudprovider "k8s.io/cloud-provider"
+	klog "k8s.io/klog/v2"
+)
+
+// healthCheckPath is the path probed on the health check node port of a
+// service with the Local external traffic policy. kube-proxy answers it with
+// 200 on the nodes running ready endpoints of the service, 503 otherwise.
+const healthCheckPath = "/healthz"
+
+// isLocalTrafficPolicy returns true if the service preserves the client source
+// IP by only sending its external traffic to the endpoints of the node
+func isLocalTrafficPolicy(service *corev1.Service) bool {
+	return service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal
+}
+
+// localEndpointNodes returns the names of the nodes running ready endpoints of
+// a service with the Local external traffic policy, nil if all the nodes are
+// pool members as the policy is Cluster or the endpoints are not known yet
+func (s *state) localEndpointNodes() sets.String {
+	if !isLocalTrafficPolicy(s.service) || s.endpointSlicesLister == nil || !s.endpointSlicesSynced() {
+		return nil
+	}
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: s.service.Name})
+	slices, err := s.endpointSlicesLister.EndpointSlices(s.service.Namespace).List(selector)
+	if err != nil {
+		klog.Warningf("%s: listing endpoint slices failed, all nodes are pool members: %v", s.objectName, err)
+		return nil
+	}
+	nodes := sets.NewString()
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.NodeName != nil && (endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready) {
+				nodes.Insert(*endpoint.NodeName)
+			}
+		}
+	}
+	return nodes
+}
+
+// localTrafficController updates the pool members of the services with the
+// Local external traffic policy when their endpoints move to other nodes, as
+// the service controller only updates them when the nodes change
+type localTrafficController struct {
+	lb          cloudprovider.LoadBalancer
+	owns        func(string) bool
+	clusterName string
+
+	servicesLister corelisters.ServiceLister
+	nodesLister    corelisters.NodeLister
+	synced         []cache.InformerSynced
+
+	workqueue workqueue.RateLimitingInterface
+}
+
+func newLocalTrafficController(lb cloudprovider.LoadBalancer, owns func(string) bool, clusterName string,
+	factory informers.SharedInformerFactory) *localTrafficController {
+	services := factory.Core().V1().Services()
+	nodes := factory.Core().V1().Nodes()
+	endpointSlices := factory.Discovery().V1().EndpointSlices()
+	c := &localTrafficController{
+		lb:          lb,
+		owns:        owns,
+		clusterName: clusterName,
+
+		servicesLister: services.Lister(),
+		nodesLister:    nodes.Lister(),
+
+		workqueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LocalTrafficPolicy"),
+	}
+
+	endpointSlicesHandler, _ := endpointSlices.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueEndpointSlice,
+		UpdateFunc: func(_, cur interface{}) {
+			c.enqueueEndpointSlice(cur)
+		},
+		DeleteFunc: c.enqueueEndpointSlice,
+	})
+	c.synced = []cache.InformerSynced{services.Informer().HasSynced, nodes.Informer().HasSynced, endpointSlicesHandler.HasSynced}
+	return c
+}
+
+func (c *localTrafficController) enqueueEndpointSlice(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return
+	}
+	name := slice.Labels[discoveryv1.LabelServiceName]
+	if name == "" {
+		return
+	}
+	c.workqueue.Add(slice.Namespace + "/" + name)
+}
+
+// run starts the worker updating the pool members until stop is closed
+func (c *localTrafficController) run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	if !cache.WaitForNamedCacheSync("local traffic policy", stop, c.synced...) {
+		return
+	}
+	go wait.Until(c.runWorker, time.Second, stop)
+
+	<-stop
+}
+
+func (c *localTrafficController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *localTrafficController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+	if err := c.syncService(key); err != nil {
+		c.workqueue.AddRateLimited(key)
+		utilruntime.HandleError(fmt.Errorf("error updating pool members of service %s: %v, requeuing", key, err))
+		return true
+	}
+	c.workqueue.Forget(obj)
+	return true
+}
+
+// syncService updates the pool members of the load balancer of the Service if
+// it has the Local external traffic policy
+func (c *localTrafficController) syncService(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	service, err := c.servicesLister.Services(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !c.wantsPoolMembers(service) {
+		return nil
+	}
+
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var members []*corev1.Node
+	for _, node := range nodes {
+		if !isExcludedNode(node) {
+			members = append(members, node)
+		}
+	}
+	klog.V(4).Infof("updating pool members of service %s with Local external traffic policy", key)
+	return c.lb.UpdateLoadBalancer(context.Background(), c.clusterName, service, members)
+}
+
+// wantsPoolMembers returns true if the Service has a load balancer of this
+// controller with the Local external traffic policy
+func (c *localTrafficController) wantsPoolMembers(service *corev1.Service) bool {
+	return service.DeletionTimestamp == nil &&
+		service.Spec.Type == corev1.ServiceTypeLoadBalancer &&
+		isLocalTrafficPolicy(service) &&
+		(service.Spec.LoadBalancerClass == nil || c.owns(*service.Spec.LoadBalancerClass))
+}
@@ -24,10 +24,12 @@ import (
 type keyLock struct {
 	lock sync.Mutex
 	keys map[string]*sync.Mutex
+	// pending counts the holder and waiters of each key
+	pending map[string]int
 }
 
 func newKeyLock() *keyLock {
-	return &keyLock{keys: map[string]*sync.Mutex{}}
+	return &keyLock{keys: map[string]*sync.Mutex{}, pending: map[string]int{}}
 }
 
 // Lock locks the key
@@ -38,6 +40,7 @@ func (l *keyLock) Lock(key string) {
 		lock = &sync.Mutex{}
 		l.keys[key] = lock
 	}
+	l.pending[key]++
 	l.lock.Unlock()
 
 	lock.Lock()
@@ -52,5 +55,20 @@ func (l *keyLock) Unlock(key string) {
 	if lock == nil {
 		panic(fmt.Sprintf("unlock of unknown keyLock %s", key))
 	}
+	if l.pending[key]--; l.pending[key] == 0 {
+		delete(l.pending, key)
+	}
 	lock.Unlock()
 }
+
+// Pending returns the number of holders and waiters of each locked key
+func (l *keyLock) Pending() map[string]int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	pending := make(map[string]int, len(l.pending))
+	for key, n := range l.pending {
+		pending[key] = n
+	}
+	return pending
+}
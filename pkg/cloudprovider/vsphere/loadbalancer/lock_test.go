@@ -0,0 +1,49 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyLockPending(t *testing.T) {
+	l := newKeyLock()
+	l.Lock("ns/a")
+	l.Lock("ns/b")
+
+	waiting := make(chan struct{})
+	go func() {
+		l.Lock("ns/a")
+		l.Unlock("ns/a")
+		close(waiting)
+	}()
+	for i := 0; l.Pending()["ns/a"] != 2; i++ {
+		if i == 100 {
+			t.Fatalf("Expected 2 pending reconciles for ns/a, got %v", l.Pending())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	l.Unlock("ns/a")
+	<-waiting
+	l.Unlock("ns/b")
+
+	if pending := l.Pending(); len(pending) != 0 {
+		t.Errorf("Expected no pending reconciles, got %v", pending)
+	}
+}
@@ -19,6 +19,7 @@ package loadbalancer
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -31,6 +32,9 @@ type Mapping struct {
 	SourcePort int
 	// NodePort is the service node port
 	NodePort int
+	// MemberPort is the port of the pool members, the node port unless
+	// overridden by the pool member ports annotation
+	MemberPort int
 	// Protoocl is the protocol on the service port
 	Protocol corev1.Protocol
 }
@@ -40,12 +44,96 @@ func NewMapping(servicePort corev1.ServicePort) Mapping {
 	return Mapping{
 		SourcePort: int(servicePort.Port),
 		NodePort:   int(servicePort.NodePort),
+		MemberPort: int(servicePort.NodePort),
 		Protocol:   servicePort.Protocol,
 	}
 }
 
+// newMappings creates the mappings for the ports of the service, with the
+// pool member ports overridden by the PoolMemberPortsAnnotation. Its value is
+// a comma separated list of <service port name or number>:<member port>.
+func newMappings(service *corev1.Service) ([]Mapping, error) {
+	mappings := make([]Mapping, 0, len(service.Spec.Ports))
+	for _, servicePort := range service.Spec.Ports {
+		mappings = append(mappings, NewMapping(servicePort))
+	}
+
+	value := strings.TrimSpace(service.GetAnnotations()[PoolMemberPortsAnnotation])
+	if value == "" || len(mappings) == 0 {
+		return mappings, nil
+	}
+
+	overridden := make(map[int]bool)
+	for _, entry := range strings.Split(value, ",") {
+		ref, portValue, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid annotation %s: entry %q is not <service port>:<member port>", PoolMemberPortsAnnotation, entry)
+		}
+		ref = strings.TrimSpace(ref)
+		port, err := strconv.Atoi(strings.TrimSpace(portValue))
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid annotation %s: member port %q of %s is not a port number", PoolMemberPortsAnnotation, portValue, ref)
+		}
+
+		found := false
+		for i, servicePort := range service.Spec.Ports {
+			if servicePort.Name != ref && formatPort(int(servicePort.Port)) != ref {
+				continue
+			}
+			if overridden[i] {
+				return nil, fmt.Errorf("invalid annotation %s: service port %s is given twice", PoolMemberPortsAnnotation, ref)
+			}
+			overridden[i] = true
+			mappings[i].MemberPort = port
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid annotation %s: service has no port %s", PoolMemberPortsAnnotation, ref)
+		}
+	}
+	return mappings, nil
+}
+
+// splitSupportedMappings splits the mappings into the ones of the protocols
+// NSX-T load balancers have virtual servers for, TCP and UDP, and the others.
+func splitSupportedMappings(mappings []Mapping) (supported []Mapping, unsupported []Mapping) {
+	for _, mapping := range mappings {
+		switch mapping.Protocol {
+		case corev1.ProtocolTCP, corev1.ProtocolUDP:
+			supported = append(supported, mapping)
+		default:
+			unsupported = append(unsupported, mapping)
+		}
+	}
+	return supported, unsupported
+}
+
+// udpHealthCheck is the payload of the health check of the UDP ports
+type udpHealthCheck struct {
+	// send is the data sent to the member port
+	send string
+	// receive is the data expected in the response
+	receive string
+}
+
+// newUDPHealthCheck returns the health check of the UDP ports of the service
+// given by the UDPHealthCheckSendAnnotation and
+// UDPHealthCheckReceiveAnnotation, nil if it has none.
+func newUDPHealthCheck(service *corev1.Service) (*udpHealthCheck, error) {
+	send := service.GetAnnotations()[UDPHealthCheckSendAnnotation]
+	receive := service.GetAnnotations()[UDPHealthCheckReceiveAnnotation]
+	if send == "" && receive == "" {
+		return nil, nil
+	}
+	if send == "" || receive == "" {
+		return nil, fmt.Errorf("invalid annotations: %s and %s must be given together",
+			UDPHealthCheckSendAnnotation, UDPHealthCheckReceiveAnnotation)
+	}
+	return &udpHealthCheck{send: send, receive: receive}, nil
+}
+
 func (m Mapping) String() string {
-	return fmt.Sprintf("%s/%d->%d", m.Protocol, m.SourcePort, m.NodePort)
+	return fmt.Sprintf("%s/%d->%d", m.Protocol, m.SourcePort, m.MemberPort)
 }
 
 // MatchVirtualServer returns true if source port is matching
@@ -63,9 +151,14 @@ func (m Mapping) MatchTCPMonitor(monitor *model.LBTcpMonitorProfile) bool {
 	return checkTags(monitor.Tags, portTag(m))
 }
 
-// MatchNodePort returns true if the server pool member port is equal to the mapping's node port
-func (m Mapping) MatchNodePort(server *model.LBVirtualServer) bool {
-	return len(server.DefaultPoolMemberPorts) == 1 && server.DefaultPoolMemberPorts[0] == formatPort(m.NodePort)
+// MatchUDPMonitor returns true if the monitor has the correct port tag
+func (m Mapping) MatchUDPMonitor(monitor *model.LBUdpMonitorProfile) bool {
+	return checkTags(monitor.Tags, portTag(m))
+}
+
+// MatchMemberPort returns true if the server pool member port is equal to the mapping's member port
+func (m Mapping) MatchMemberPort(server *model.LBVirtualServer) bool {
+	return len(server.DefaultPoolMemberPorts) == 1 && server.DefaultPoolMemberPorts[0] == formatPort(m.MemberPort)
 }
 
 func formatPort(port int) string {
@@ -0,0 +1,149 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewMappings(t *testing.T) {
+	ports := []corev1.ServicePort{
+		{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080},
+		{Name: "https", Protocol: corev1.ProtocolTCP, Port: 443, NodePort: 30443},
+	}
+
+	testCases := []struct {
+		name        string
+		annotation  string
+		ports       []corev1.ServicePort
+		memberPorts []int
+		expectErr   bool
+	}{
+		{
+			name:        "no annotation",
+			ports:       ports,
+			memberPorts: []int{30080, 30443},
+		},
+		{
+			name:        "by port name",
+			annotation:  "http:8080",
+			ports:       ports,
+			memberPorts: []int{8080, 30443},
+		},
+		{
+			name:        "by port number",
+			annotation:  " http:8080 , 443 : 8443 ",
+			ports:       ports,
+			memberPorts: []int{8080, 8443},
+		},
+		{
+			name:       "unknown port",
+			annotation: "8443:8443",
+			ports:      ports,
+			expectErr:  true,
+		},
+		{
+			name:       "port given twice",
+			annotation: "http:8080,80:8081",
+			ports:      ports,
+			expectErr:  true,
+		},
+		{
+			name:       "invalid member port",
+			annotation: "http:70000",
+			ports:      ports,
+			expectErr:  true,
+		},
+		{
+			name:       "missing member port",
+			annotation: "http",
+			ports:      ports,
+			expectErr:  true,
+		},
+		{
+			name:        "ignored without ports",
+			annotation:  "http:8080",
+			memberPorts: []int{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{PoolMemberPortsAnnotation: testCase.annotation},
+				},
+				Spec: corev1.ServiceSpec{Ports: testCase.ports},
+			}
+			mappings, err := newMappings(service)
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, but got mappings %v", mappings)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			memberPorts := []int{}
+			for i, mapping := range mappings {
+				memberPorts = append(memberPorts, mapping.MemberPort)
+				if mapping.NodePort != int(testCase.ports[i].NodePort) {
+					t.Errorf("expected node port %d, but found %d", testCase.ports[i].NodePort, mapping.NodePort)
+				}
+			}
+			if !reflect.DeepEqual(memberPorts, testCase.memberPorts) {
+				t.Errorf("expected member ports %v, but found %v", testCase.memberPorts, memberPorts)
+			}
+		})
+	}
+}
+
+func TestMatchMemberPort(t *testing.T) {
+	mapping := Mapping{SourcePort: 80, NodePort: 30080, MemberPort: 8080, Protocol: corev1.ProtocolTCP}
+
+	if !mapping.MatchMemberPort(&model.LBVirtualServer{DefaultPoolMemberPorts: []string{"8080"}}) {
+		t.Errorf("expected virtual server with member port 8080 to match")
+	}
+	if mapping.MatchMemberPort(&model.LBVirtualServer{DefaultPoolMemberPorts: []string{"30080"}}) {
+		t.Errorf("expected virtual server with the node port not to match an overridden member port")
+	}
+}
+
+func TestNewUDPHealthCheck(t *testing.T) {
+	service := &corev1.Service{}
+	healthCheck, err := newUDPHealthCheck(service)
+	if err != nil || healthCheck != nil {
+		t.Errorf("expected no health check without annotations, but found %v, %v", healthCheck, err)
+	}
+
+	service.Annotations = map[string]string{UDPHealthCheckSendAnnotation: "ping"}
+	if _, err := newUDPHealthCheck(service); err == nil {
+		t.Errorf("expected the send annotation without the receive annotation to be rejected")
+	}
+
+	service.Annotations[UDPHealthCheckReceiveAnnotation] = "pong"
+	healthCheck, err = newUDPHealthCheck(service)
+	if err != nil || !reflect.DeepEqual(healthCheck, &udpHealthCheck{send: "ping", receive: "pong"}) {
+		t.Errorf("expected health check sending ping and receiving pong, but found %v, %v", healthCheck, err)
+	}
+}
@@ -0,0 +1,242 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+)
+
+// clusterNameMigration renames the cluster of the NSX-T objects created with
+// the previous cluster name
+type clusterNameMigration struct {
+	previous string
+	current  string
+}
+
+// tags replaces the cluster tag
+func (m clusterNameMigration) tags(tags []model.Tag) []model.Tag {
+	renamed := make([]model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Scope != nil && *tag.Scope == ScopeCluster {
+			tag = clusterTag(m.current)
+		}
+		renamed = append(renamed, tag)
+	}
+	return renamed
+}
+
+// displayName renames the cluster in a default display name, which is
+// cluster:<cluster> or cluster:<cluster>:<rest> after the display name
+// prefix. Other display names, such as those rendered by a name template,
+// are kept.
+func (m clusterNameMigration) displayName(name *string) *string {
+	if name == nil {
+		return nil
+	}
+	previous := *displayName(m.previous)
+	i := strings.Index(*name, previous)
+	if i < 0 {
+		return name
+	}
+	rest := (*name)[i+len(previous):]
+	if rest != "" && !strings.HasPrefix(rest, ":") {
+		return name
+	}
+	return strptr(truncateDisplayName((*name)[:i] + *displayName(m.current) + rest))
+}
+
+// memberDisplayName renames the cluster in the display name of a pool member,
+// which is <cluster>:<node>
+func (m clusterNameMigration) memberDisplayName(name *string) *string {
+	if name == nil || !strings.HasPrefix(*name, m.previous+":") {
+		return name
+	}
+	return strptr(m.current + strings.TrimPrefix(*name, m.previous))
+}
+
+// migrateClusterName tags the NSX-T objects of the previous cluster name with
+// the current one and renames the cluster in their default display names, so
+// that the Services keep their virtual servers and IP addresses. Objects
+// already migrated are no longer found by the previous cluster name, so a
+// failed migration is continued by the next call.
+func (p *lbProvider) migrateClusterName(previous, current string) error {
+	m := clusterNameMigration{previous: previous, current: current}
+	ipPoolIds := p.classIPPoolIds()
+	migrated := 0
+
+	servers, err := p.access.ListVirtualServers(previous)
+	if err != nil {
+		return err
+	}
+	for _, server := range servers {
+		ipPoolIds.Insert(getTag(server.Tags, ScopeIPPoolID))
+		server.Tags, server.DisplayName = m.tags(server.Tags), m.displayName(server.DisplayName)
+		if err := p.access.UpdateVirtualServer(server); err != nil {
+			return err
+		}
+		migrated++
+	}
+	ipPoolIds.Delete("")
+
+	pools, err := p.access.ListPools(previous)
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		pool.Tags, pool.DisplayName = m.tags(pool.Tags), m.displayName(pool.DisplayName)
+		for i := range pool.Members {
+			pool.Members[i].DisplayName = m.memberDisplayName(pool.Members[i].DisplayName)
+		}
+		if err := p.access.UpdatePool(pool); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	tcpMonitors, err := p.access.ListTCPMonitorProfiles(previous)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range tcpMonitors {
+		monitor.Tags, monitor.DisplayName = m.tags(monitor.Tags), m.displayName(monitor.DisplayName)
+		if err := p.access.UpdateTCPMonitorProfile(monitor); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	udpMonitors, err := p.access.ListUDPMonitorProfiles(previous)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range udpMonitors {
+		monitor.Tags, monitor.DisplayName = m.tags(monitor.Tags), m.displayName(monitor.DisplayName)
+		if err := p.access.UpdateUDPMonitorProfile(monitor); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	httpMonitors, err := p.access.ListHTTPMonitorProfiles(previous)
+	if err != nil {
+		return err
+	}
+	for _, monitor := range httpMonitors {
+		monitor.Tags, monitor.DisplayName = m.tags(monitor.Tags), m.displayName(monitor.DisplayName)
+		if err := p.access.UpdateHTTPMonitorProfile(monitor); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	httpProfiles, err := p.access.ListHTTPAppProfiles(previous)
+	if err != nil {
+		return err
+	}
+	for _, profile := range httpProfiles {
+		profile.Tags, profile.DisplayName = m.tags(profile.Tags), m.displayName(profile.DisplayName)
+		if err := p.access.UpdateHTTPAppProfile(profile); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	for _, ipPoolID := range ipPoolIds.List() {
+		allocations, err := p.access.ListExternalIPAddresses(ipPoolID, previous)
+		if err != nil {
+			return err
+		}
+		for _, allocation := range allocations {
+			allocation.Tags = m.tags(allocation.Tags)
+			if err := p.access.UpdateExternalIPAddress(ipPoolID, allocation); err != nil {
+				return err
+			}
+			migrated++
+		}
+	}
+
+	var lbServices []*model.LBService
+	if p.managed {
+		lbService, err := p.access.FindLoadBalancerService(previous, "")
+		if err != nil {
+			return err
+		}
+		lbServices = append(lbServices, lbService)
+	}
+	for _, zone := range p.zoneNames() {
+		lbService, err := p.access.FindZonalLoadBalancerService(previous, zone)
+		if err != nil {
+			return err
+		}
+		lbServices = append(lbServices, lbService)
+	}
+	for _, lbService := range lbServices {
+		// the LbService on the configured T1 gateway is found regardless of its tags
+		if lbService == nil || getTag(lbService.Tags, ScopeCluster) != previous {
+			continue
+		}
+		lbService.Tags, lbService.DisplayName = m.tags(lbService.Tags), m.displayName(lbService.DisplayName)
+		if err := p.access.UpdateLoadBalancerService(lbService); err != nil {
+			return err
+		}
+		migrated++
+	}
+
+	klog.Infof("migrated %d NSX-T objects from cluster name %s to %s", migrated, previous, current)
+	return nil
+}
+
+// migrate retries the migration of the NSX-T objects from the previous
+// cluster name until it succeeds, and then lets the Services be reconciled.
+// It returns false if stopped before.
+func (p *lbProvider) migrate(clusterName string, stop <-chan struct{}) bool {
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Steps: math.MaxInt32, Cap: maxPeriod}
+	for {
+		err := p.migrateClusterName(p.previousClusterName, clusterName)
+		if err == nil {
+			close(p.migrated)
+			return true
+		}
+		klog.Warningf("migration from cluster name %s failed: %v", p.previousClusterName, err)
+		select {
+		case <-stop:
+			return false
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// checkMigrated returns an error while the NSX-T objects are migrated from
+// the previous cluster name, as the Services would not find them
+func (p *lbProvider) checkMigrated() error {
+	if p.migrated == nil {
+		return nil
+	}
+	select {
+	case <-p.migrated:
+		return nil
+	default:
+		return fmt.Errorf("migration of the NSX-T objects from cluster name %s pending", p.previousClusterName)
+	}
+}
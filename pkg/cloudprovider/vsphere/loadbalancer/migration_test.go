@@ -0,0 +1,173 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// migrationAccess lists the NSX-T objects of the old cluster name and records
+// the updated ones. Methods not needed by the migration panic.
+type migrationAccess struct {
+	NSXTAccess
+	servers     []*model.LBVirtualServer
+	pools       []*model.LBPool
+	monitors    []*model.LBTcpMonitorProfile
+	allocations []*model.IpAddressAllocation
+	lbService   *model.LBService
+	updated     []string
+}
+
+func (a *migrationAccess) ListVirtualServers(clusterName string) ([]*model.LBVirtualServer, error) {
+	var servers []*model.LBVirtualServer
+	for _, server := range a.servers {
+		if checkTags(server.Tags, clusterTag(clusterName)) {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+func (a *migrationAccess) UpdateVirtualServer(server *model.LBVirtualServer) error {
+	a.updated = append(a.updated, *server.DisplayName)
+	return nil
+}
+
+func (a *migrationAccess) ListPools(string) ([]*model.LBPool, error) {
+	return a.pools, nil
+}
+
+func (a *migrationAccess) UpdatePool(pool *model.LBPool) error {
+	a.updated = append(a.updated, *pool.DisplayName)
+	return nil
+}
+
+func (a *migrationAccess) ListTCPMonitorProfiles(string) ([]*model.LBTcpMonitorProfile, error) {
+	return a.monitors, nil
+}
+
+func (a *migrationAccess) UpdateTCPMonitorProfile(monitor *model.LBTcpMonitorProfile) error {
+	a.updated = append(a.updated, *monitor.DisplayName)
+	return nil
+}
+
+func (a *migrationAccess) ListUDPMonitorProfiles(string) ([]*model.LBUdpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *migrationAccess) ListHTTPMonitorProfiles(string) ([]*model.LBHttpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *migrationAccess) ListHTTPAppProfiles(string) ([]*model.LBHttpProfile, error) {
+	return nil, nil
+}
+
+func (a *migrationAccess) ListExternalIPAddresses(ipPoolID string, _ string) ([]*model.IpAddressAllocation, error) {
+	if ipPoolID != "pool" {
+		return nil, nil
+	}
+	return a.allocations, nil
+}
+
+func (a *migrationAccess) UpdateExternalIPAddress(_ string, allocation *model.IpAddressAllocation) error {
+	a.updated = append(a.updated, *allocation.Id)
+	return nil
+}
+
+func (a *migrationAccess) FindLoadBalancerService(string, string) (*model.LBService, error) {
+	return a.lbService, nil
+}
+
+func (a *migrationAccess) UpdateLoadBalancerService(lbService *model.LBService) error {
+	a.updated = append(a.updated, *lbService.DisplayName)
+	return nil
+}
+
+func TestMigrateClusterName(t *testing.T) {
+	web := types.NamespacedName{Namespace: "default", Name: "web"}
+	tags := []model.Tag{clusterTag("old"), serviceTag(web)}
+	access := &migrationAccess{
+		servers: []*model.LBVirtualServer{
+			{DisplayName: strptr("k8s-cluster:old:default/web"), Tags: append(tags, newTag(ScopeIPPoolID, "pool"))},
+			{DisplayName: strptr("cluster:other:default/web"), Tags: []model.Tag{clusterTag("other")}},
+		},
+		pools: []*model.LBPool{{
+			DisplayName: strptr("k8s-cluster:old:default/web"),
+			Tags:        tags,
+			Members:     []model.LBPoolMember{{DisplayName: strptr("old:node1"), IpAddress: strptr("10.0.0.1")}},
+		}},
+		monitors: []*model.LBTcpMonitorProfile{
+			{DisplayName: strptr("cluster:older:default/web:30080"), Tags: tags},
+			{DisplayName: strptr("custom-web"), Tags: tags},
+		},
+		allocations: []*model.IpAddressAllocation{{Id: strptr("ip1"), Tags: tags}},
+		lbService:   &model.LBService{DisplayName: strptr("cluster:old"), Tags: []model.Tag{clusterTag("old")}},
+	}
+	p := &lbProvider{
+		lbService: newLbService(access, ""),
+		classes:   &loadBalancerClasses{classes: map[string]*loadBalancerClass{}},
+	}
+
+	if err := p.migrateClusterName("old", "new"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []string{
+		"k8s-cluster:new:default/web",
+		"k8s-cluster:new:default/web",
+		"cluster:older:default/web:30080",
+		"custom-web",
+		"ip1",
+		"cluster:new",
+	}
+	if !reflect.DeepEqual(access.updated, expected) {
+		t.Errorf("expected updated objects %v, but found %v", expected, access.updated)
+	}
+	for _, tags := range [][]model.Tag{access.servers[0].Tags, access.pools[0].Tags, access.monitors[0].Tags, access.allocations[0].Tags, access.lbService.Tags} {
+		if getTag(tags, ScopeCluster) != "new" {
+			t.Errorf("expected the cluster tag to be migrated, but found %v", tags)
+		}
+	}
+	if getTag(access.servers[0].Tags, ScopeService) != web.String() {
+		t.Errorf("expected the service tag to be kept")
+	}
+	if name := *access.pools[0].Members[0].DisplayName; name != "new:node1" {
+		t.Errorf("expected the pool member to be renamed, but found %s", name)
+	}
+	if *access.servers[1].DisplayName != "cluster:other:default/web" {
+		t.Errorf("expected the virtual server of another cluster to be kept")
+	}
+}
+
+func TestCheckMigrated(t *testing.T) {
+	p := &lbProvider{previousClusterName: "old"}
+	if err := p.checkMigrated(); err != nil {
+		t.Errorf("unexpected error without migration: %s", err)
+	}
+	p.migrated = make(chan struct{})
+	if err := p.checkMigrated(); err == nil {
+		t.Errorf("expected an error while migrating")
+	}
+	close(p.migrated)
+	if err := p.checkMigrated(); err != nil {
+		t.Errorf("unexpected error after migration: %s", err)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// maxDisplayNameLength is the maximum length of the display names of NSX-T
+// objects
+const maxDisplayNameLength = 255
+
+// displayNameHashLength is the length of the hash replacing the end of a
+// truncated display name
+const displayNameHashLength = 10
+
+// nameData is the data of the name template
+type nameData struct {
+	// Cluster is the name of the cluster
+	Cluster string
+	// Namespace is the namespace of the Service
+	Namespace string
+	// Name is the name of the Service
+	Name string
+}
+
+// loadBalancerName returns the name of the load balancer of a service, see
+// renderLoadBalancerName.
+func (s *lbService) loadBalancerName(clusterName string, service *corev1.Service) string {
+	return renderLoadBalancerName(s.nameTemplate, clusterName, service)
+}
+
+// renderLoadBalancerName returns the name of the load balancer of a service,
+// the value of the LoadBalancerNameAnnotation if set, the name rendered by the
+// name template otherwise, or cluster:<cluster>:<namespace>/<name> if there
+// is none or it fails.
+func renderLoadBalancerName(nameTemplate *template.Template, clusterName string, service *corev1.Service) string {
+	if name := strings.TrimSpace(service.GetAnnotations()[LoadBalancerNameAnnotation]); name != "" {
+		return name
+	}
+	objectName := namespacedNameFromService(service)
+	defaultName := *displayNameObject(clusterName, objectName)
+	if nameTemplate == nil {
+		return defaultName
+	}
+	var name strings.Builder
+	err := nameTemplate.Execute(&name, nameData{
+		Cluster:   clusterName,
+		Namespace: objectName.Namespace,
+		Name:      objectName.Name,
+	})
+	if err == nil && strings.TrimSpace(name.String()) == "" {
+		err = fmt.Errorf("empty name")
+	}
+	if err != nil {
+		klog.Warningf("rendering load balancer name of %s failed, using %s: %v", objectName, defaultName, err)
+		return defaultName
+	}
+	return name.String()
+}
+
+// truncateDisplayName shortens a display name exceeding the length limit of
+// NSX-T. Its end is replaced by a hash of the full name, so that truncated
+// names are deterministic and stay distinct.
+func truncateDisplayName(name string) string {
+	if len(name) <= maxDisplayNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:displayNameHashLength]
+	end := maxDisplayNameLength - len(suffix)
+	// do not split a multi-byte character
+	for end > 0 && !utf8.RuneStart(name[end]) {
+		end--
+	}
+	return name[:end] + suffix
+}
@@ -0,0 +1,103 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+)
+
+func TestGetLoadBalancerName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		nameTemplate string
+		annotation   string
+		expected     string
+	}{
+		{
+			name:     "default",
+			expected: "cluster:cluster1:default/web",
+		},
+		{
+			name:         "template",
+			nameTemplate: "{{.Cluster}}-{{.Namespace}}-{{.Name}}",
+			expected:     "cluster1-default-web",
+		},
+		{
+			name:         "annotation overrides template",
+			nameTemplate: "{{.Cluster}}-{{.Namespace}}-{{.Name}}",
+			annotation:   " shop-frontend ",
+			expected:     "shop-frontend",
+		},
+		{
+			name:         "failing template",
+			nameTemplate: "{{.Owner}}",
+			expected:     "cluster:cluster1:default/web",
+		},
+		{
+			name:         "empty template result",
+			nameTemplate: "{{if false}}x{{end}}",
+			expected:     "cluster:cluster1:default/web",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			nameTemplate, err := config.ParseNameTemplate(testCase.nameTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lbService := newLbService(nil, "")
+			lbService.nameTemplate = nameTemplate
+			p := &lbProvider{lbService: lbService}
+			service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+			if testCase.annotation != "" {
+				service.Annotations = map[string]string{LoadBalancerNameAnnotation: testCase.annotation}
+			}
+
+			assert.Equal(t, testCase.expected, p.GetLoadBalancerName(context.Background(), "cluster1", service))
+			assert.Equal(t, testCase.expected, newState(lbService, "cluster1", service, nil).lbName)
+		})
+	}
+}
+
+func TestTruncateDisplayName(t *testing.T) {
+	short := "cluster:cluster1:default/web"
+	assert.Equal(t, short, truncateDisplayName(short))
+
+	long := "cluster:cluster1:" + strings.Repeat("a", 300)
+	truncated := truncateDisplayName(long)
+	assert.Len(t, truncated, maxDisplayNameLength)
+	assert.Equal(t, truncated, truncateDisplayName(long))
+	assert.True(t, strings.HasPrefix(truncated, "cluster:cluster1:aaa"))
+
+	other := truncateDisplayName(long + "b")
+	assert.NotEqual(t, truncated, other)
+	assert.Equal(t, truncated[:200], other[:200])
+
+	multiByte := truncateDisplayName(strings.Repeat("ü", 200))
+	assert.True(t, utf8.ValidString(multiByte))
+	assert.LessOrEqual(t, len(multiByte), maxDisplayNameLength)
+}
@@ -0,0 +1,136 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// nodePortMapping describes a virtual server of a service, so that node
+// local dataplanes can forward the traffic of its virtual IP address to the
+// node port without going through the load balancer.
+type nodePortMapping struct {
+	// VIP is the virtual IP address of the virtual server
+	VIP string `json:"vip"`
+	// Port is the service port of the virtual server
+	Port int `json:"port"`
+	// Protocol is the protocol of the service port
+	Protocol corev1.Protocol `json:"protocol"`
+	// NodePort is the node port of the service port
+	NodePort int `json:"nodePort"`
+	// MemberPort is the port of the pool members, the node port unless
+	// overridden by the PoolMemberPortsAnnotation
+	MemberPort int `json:"memberPort"`
+	// PoolID is the id of the NSX-T pool of the virtual server
+	PoolID string `json:"poolId"`
+}
+
+// nodePortMappings returns the mappings of the virtual servers of the
+// service, sorted by port, protocol and VIP. It is empty for a deleted load
+// balancer.
+func (s *state) nodePortMappings() []nodePortMapping {
+	var result []nodePortMapping
+	for _, server := range s.servers {
+		if server.IpAddress == nil || (!s.dualStack && serverIPFamily(server) == corev1.IPv6Protocol) {
+			continue
+		}
+		for _, mapping := range s.mappings {
+			if !mapping.MatchVirtualServer(server) {
+				continue
+			}
+			m := nodePortMapping{
+				VIP:        *server.IpAddress,
+				Port:       mapping.SourcePort,
+				Protocol:   mapping.Protocol,
+				NodePort:   mapping.NodePort,
+				MemberPort: mapping.MemberPort,
+			}
+			for _, pool := range s.pools {
+				if pool.Id != nil && pool.Path != nil && safeEquals(pool.Path, server.PoolPath) {
+					m.PoolID = *pool.Id
+				}
+			}
+			result = append(result, m)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Port != result[j].Port {
+			return result[i].Port < result[j].Port
+		}
+		if result[i].Protocol != result[j].Protocol {
+			return result[i].Protocol < result[j].Protocol
+		}
+		return result[i].VIP < result[j].VIP
+	})
+	return result
+}
+
+// nodePortMappingPublisher publishes the node port mappings of the services
+// in the NodePortMappingsAnnotation
+type nodePortMappingPublisher struct {
+	client clientcorev1.ServicesGetter
+}
+
+func newNodePortMappingPublisher(client clientcorev1.ServicesGetter) *nodePortMappingPublisher {
+	return &nodePortMappingPublisher{client: client}
+}
+
+// publish sets the annotation of the service to the mappings, or removes it
+// if there are none. The service is only patched if the annotation changes.
+func (p *nodePortMappingPublisher) publish(ctx context.Context, service *corev1.Service, mappings []nodePortMapping) error {
+	if p == nil {
+		return nil
+	}
+	var value interface{}
+	if len(mappings) > 0 {
+		bytes, err := json.Marshal(mappings)
+		if err != nil {
+			return err
+		}
+		value = string(bytes)
+	}
+	current, ok := service.Annotations[NodePortMappingsAnnotation]
+	if (value == nil && !ok) || (ok && value == current) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{NodePortMappingsAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.client.Services(service.Namespace).Patch(ctx, service.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "publishing node port mappings of service %s/%s failed", service.Namespace, service.Name)
+	}
+	return nil
+}
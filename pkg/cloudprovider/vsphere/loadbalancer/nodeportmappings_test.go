@@ -0,0 +1,105 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodePortMappings(t *testing.T) {
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: corev1.ServiceSpec{
+			Ports:          []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+			IPFamilyPolicy: &requireDualStack,
+			IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+		},
+	}
+	access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10", "pool6": "fd00::10"}}
+	s := newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+	class := &loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}, ipv6Pool: Reference{Identifier: "pool6"}}
+	if err := s.Process(class); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []nodePortMapping{
+		{VIP: "10.0.0.10", Port: 80, Protocol: corev1.ProtocolTCP, NodePort: 30080, MemberPort: 30080, PoolID: "pool1"},
+		{VIP: "fd00::10", Port: 80, Protocol: corev1.ProtocolTCP, NodePort: 30080, MemberPort: 30080, PoolID: "pool1"},
+	}
+	assert.Equal(t, expected, s.nodePortMappings())
+
+	// the deleted load balancer has no mappings
+	s.mappings = nil
+	assert.Empty(t, s.nodePortMappings())
+}
+
+func TestPublishNodePortMappings(t *testing.T) {
+	ctx := context.Background()
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+	client := fake.NewSimpleClientset(service)
+	p := newNodePortMappingPublisher(client.CoreV1())
+	mappings := []nodePortMapping{{VIP: "10.0.0.10", Port: 80, Protocol: corev1.ProtocolTCP, NodePort: 30080, MemberPort: 30080, PoolID: "pool1"}}
+
+	get := func() *corev1.Service {
+		t.Helper()
+		current, err := client.CoreV1().Services("default").Get(ctx, "web", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return current
+	}
+
+	if err := p.publish(ctx, service, mappings); err != nil {
+		t.Fatal(err)
+	}
+	published := get()
+	assert.Equal(t, `[{"vip":"10.0.0.10","port":80,"protocol":"TCP","nodePort":30080,"memberPort":30080,"poolId":"pool1"}]`,
+		published.Annotations[NodePortMappingsAnnotation])
+
+	// unchanged mappings are not patched again
+	client.ClearActions()
+	if err := p.publish(ctx, published, mappings); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, client.Actions())
+
+	// the annotation is removed with the load balancer
+	if err := p.publish(ctx, published, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, ok := get().Annotations[NodePortMappingsAnnotation]
+	assert.False(t, ok)
+
+	// deleted services are ignored
+	missing := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "missing",
+		Annotations: map[string]string{NodePortMappingsAnnotation: "[]"},
+	}}
+	assert.NoError(t, p.publish(ctx, missing, nil))
+
+	// nothing is published if disabled
+	var disabled *nodePortMappingPublisher
+	assert.NoError(t, disabled.publish(ctx, service, mappings))
+}
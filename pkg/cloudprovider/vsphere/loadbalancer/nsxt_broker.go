@@ -31,7 +31,11 @@ import (
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/ip_pools"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/realized_state"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/sites/enforcement_points"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/tier_1s"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"k8s.io/cloud-provider-vsphere/pkg/nsxt"
 )
 
 // NsxtBroker is an internal interface to enable mocking the nsxt backend
@@ -53,15 +57,31 @@ type NsxtBroker interface {
 	ListIPPools() ([]model.IpAddressPool, error)
 	AllocateFromIPPool(ipPoolID string, allocation model.IpAddressAllocation) (model.IpAddressAllocation, string, error)
 	ListIPPoolAllocations(ipPoolID string) ([]model.IpAddressAllocation, error)
+	UpdateIPPoolAllocation(ipPoolID string, allocation model.IpAddressAllocation) error
 	ReleaseFromIPPool(ipPoolID, ipAllocationID string) error
 	GetRealizedExternalIPAddress(ipAllocationPath string, timeout time.Duration) (*string, error)
 	ListAppProfiles() ([]*data.StructValue, error)
+	CreateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error)
+	UpdateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error)
+	DeleteLoadBalancerAppProfile(id string) error
 
 	CreateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error)
 	ListLoadBalancerMonitorProfiles() ([]*data.StructValue, error)
 	ReadLoadBalancerTCPMonitorProfile(id string) (model.LBTcpMonitorProfile, error)
 	UpdateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error)
+	CreateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error)
+	ReadLoadBalancerUDPMonitorProfile(id string) (model.LBUdpMonitorProfile, error)
+	UpdateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error)
+	CreateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error)
+	ReadLoadBalancerHTTPMonitorProfile(id string) (model.LBHttpMonitorProfile, error)
+	UpdateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error)
 	DeleteLoadBalancerMonitorProfile(id string) error
+
+	ReadTier1(id string) (model.Tier1, error)
+	PatchTier1(id string, tier1 model.Tier1) error
+	ListTier1LocaleServices(tier1ID string) ([]model.LocaleServices, error)
+	PatchTier1LocaleServices(tier1ID string, id string, localeServices model.LocaleServices) error
+	ReadEdgeCluster(siteID, enforcementPointID, id string) (model.PolicyEdgeCluster, error)
 }
 
 type nsxtBroker struct {
@@ -73,6 +93,9 @@ type nsxtBroker struct {
 	lbAppProfilesClient     infra.LbAppProfilesClient
 	lbMonitorProfilesClient infra.LbMonitorProfilesClient
 	realizedEntitiesClient  realized_state.RealizedEntitiesClient
+	tier1sClient            infra.Tier1sClient
+	localeServicesClient    tier_1s.LocaleServicesClient
+	edgeClustersClient      enforcement_points.EdgeClustersClient
 }
 
 // NewNsxtBroker creates a new NsxtBroker using the configuration
@@ -82,7 +105,7 @@ func NewNsxtBroker(connector client.Connector) (NsxtBroker, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "Connection to NSX-T API failed. Please check your connection settings.")
 	}
-	return NewNsxtBrokerFromConnector(connector), nil
+	return newMetricsBroker(NewNsxtBrokerFromConnector(connector)), nil
 }
 
 // NewNsxtBrokerFromConnector creates a new NsxtBroker to the real API
@@ -96,6 +119,9 @@ func NewNsxtBrokerFromConnector(connector client.Connector) NsxtBroker {
 		lbAppProfilesClient:     infra.NewLbAppProfilesClient(connector),
 		lbMonitorProfilesClient: infra.NewLbMonitorProfilesClient(connector),
 		realizedEntitiesClient:  realized_state.NewRealizedEntitiesClient(connector),
+		tier1sClient:            infra.NewTier1sClient(connector),
+		localeServicesClient:    tier_1s.NewLocaleServicesClient(connector),
+		edgeClustersClient:      enforcement_points.NewEdgeClustersClient(connector),
 	}
 }
 
@@ -110,20 +136,10 @@ func (b *nsxtBroker) CreateLoadBalancerService(service model.LBService) (model.L
 }
 
 func (b *nsxtBroker) ListLoadBalancerServices() ([]model.LBService, error) {
-	result, err := b.lbServicesClient.List(nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return nil, nicerVAPIError(err)
-	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.lbServicesClient.List(result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
-	}
-	return list, nil
+	return listAll(func(cursor *string) ([]model.LBService, *string, error) {
+		result, err := b.lbServicesClient.List(cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
 }
 
 func (b *nsxtBroker) UpdateLoadBalancerService(service model.LBService) (model.LBService, error) {
@@ -143,20 +159,10 @@ func (b *nsxtBroker) CreateLoadBalancerVirtualServer(server model.LBVirtualServe
 }
 
 func (b *nsxtBroker) ListLoadBalancerVirtualServers() ([]model.LBVirtualServer, error) {
-	result, err := b.lbVirtServersClient.List(nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return nil, nicerVAPIError(err)
-	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.lbVirtServersClient.List(result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
-	}
-	return list, nil
+	return listAll(func(cursor *string) ([]model.LBVirtualServer, *string, error) {
+		result, err := b.lbVirtServersClient.List(cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
 }
 
 func (b *nsxtBroker) UpdateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
@@ -181,20 +187,10 @@ func (b *nsxtBroker) ReadLoadBalancerPool(id string) (model.LBPool, error) {
 }
 
 func (b *nsxtBroker) ListLoadBalancerPools() ([]model.LBPool, error) {
-	result, err := b.lbPoolsClient.List(nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return nil, nicerVAPIError(err)
-	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.lbPoolsClient.List(result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
-	}
-	return list, nil
+	return listAll(func(cursor *string) ([]model.LBPool, *string, error) {
+		result, err := b.lbPoolsClient.List(cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
 }
 
 func (b *nsxtBroker) UpdateLoadBalancerPool(pool model.LBPool) (model.LBPool, error) {
@@ -208,20 +204,40 @@ func (b *nsxtBroker) DeleteLoadBalancerPool(id string) error {
 }
 
 func (b *nsxtBroker) ListAppProfiles() ([]*data.StructValue, error) {
-	result, err := b.lbAppProfilesClient.List(nil, nil, nil, nil, nil, nil)
+	return listAll(func(cursor *string) ([]*data.StructValue, *string, error) {
+		result, err := b.lbAppProfilesClient.List(cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
+}
+
+func (b *nsxtBroker) CreateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	id := uuid.New().String()
+	result, err := b.createOrUpdateLoadBalancerHTTPAppProfile(id, profile)
+	return result, nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) createOrUpdateLoadBalancerHTTPAppProfile(id string, profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	profile.ResourceType = model.LBAppProfile_RESOURCE_TYPE_LBHTTPPROFILE
+	converter := newNsxtTypeConverter()
+	value, err := converter.convertLBHTTPProfileToStructValue(profile)
 	if err != nil {
-		return nil, nicerVAPIError(err)
+		return model.LBHttpProfile{}, errors.Wrapf(err, "converting LBHttpProfile failed")
 	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.lbAppProfilesClient.List(result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
+	result, err := b.lbAppProfilesClient.Update(id, value)
+	if err != nil {
+		return model.LBHttpProfile{}, nicerVAPIError(err)
 	}
-	return list, nil
+	return converter.convertStructValueToLBHTTPProfile(result)
+}
+
+func (b *nsxtBroker) UpdateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	result, err := b.createOrUpdateLoadBalancerHTTPAppProfile(*profile.Id, profile)
+	return result, nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) DeleteLoadBalancerAppProfile(id string) error {
+	err := b.lbAppProfilesClient.Delete(id, nil)
+	return nicerVAPIError(err)
 }
 
 func (b *nsxtBroker) CreateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error) {
@@ -245,20 +261,10 @@ func (b *nsxtBroker) createOrUpdateLoadBalancerTCPMonitorProfile(id string, moni
 }
 
 func (b *nsxtBroker) ListLoadBalancerMonitorProfiles() ([]*data.StructValue, error) {
-	result, err := b.lbMonitorProfilesClient.List(nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return nil, nicerVAPIError(err)
-	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.lbMonitorProfilesClient.List(result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
-	}
-	return list, nil
+	return listAll(func(cursor *string) ([]*data.StructValue, *string, error) {
+		result, err := b.lbMonitorProfilesClient.List(cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
 }
 
 func (b *nsxtBroker) ReadLoadBalancerTCPMonitorProfile(id string) (model.LBTcpMonitorProfile, error) {
@@ -274,26 +280,109 @@ func (b *nsxtBroker) UpdateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMoni
 	return result, nicerVAPIError(err)
 }
 
+func (b *nsxtBroker) CreateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	id := uuid.New().String()
+	result, err := b.createOrUpdateLoadBalancerUDPMonitorProfile(id, monitor)
+	return result, nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) createOrUpdateLoadBalancerUDPMonitorProfile(id string, monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	monitor.ResourceType = model.LBMonitorProfile_RESOURCE_TYPE_LBUDPMONITORPROFILE
+	converter := newNsxtTypeConverter()
+	value, err := converter.convertLBUDPMonitorProfileToStructValue(monitor)
+	if err != nil {
+		return model.LBUdpMonitorProfile{}, errors.Wrapf(err, "converting LBUdpMonitorProfile failed")
+	}
+	result, err := b.lbMonitorProfilesClient.Update(id, value)
+	if err != nil {
+		return model.LBUdpMonitorProfile{}, nicerVAPIError(err)
+	}
+	return converter.convertStructValueToLBUDPMonitorProfile(result)
+}
+
+func (b *nsxtBroker) ReadLoadBalancerUDPMonitorProfile(id string) (model.LBUdpMonitorProfile, error) {
+	itf, err := b.lbMonitorProfilesClient.Get(id)
+	if err != nil {
+		return model.LBUdpMonitorProfile{}, errors.Wrapf(nicerVAPIError(err), "getting LBUdpMonitorProfile %s failed", id)
+	}
+	return newNsxtTypeConverter().convertStructValueToLBUDPMonitorProfile(itf)
+}
+
+func (b *nsxtBroker) UpdateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	result, err := b.createOrUpdateLoadBalancerUDPMonitorProfile(*monitor.Id, monitor)
+	return result, nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) CreateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	id := uuid.New().String()
+	result, err := b.createOrUpdateLoadBalancerHTTPMonitorProfile(id, monitor)
+	return result, nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) createOrUpdateLoadBalancerHTTPMonitorProfile(id string, monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	monitor.ResourceType = model.LBMonitorProfile_RESOURCE_TYPE_LBHTTPMONITORPROFILE
+	converter := newNsxtTypeConverter()
+	value, err := converter.convertLBHTTPMonitorProfileToStructValue(monitor)
+	if err != nil {
+		return model.LBHttpMonitorProfile{}, errors.Wrapf(err, "converting LBHttpMonitorProfile failed")
+	}
+	result, err := b.lbMonitorProfilesClient.Update(id, value)
+	if err != nil {
+		return model.LBHttpMonitorProfile{}, nicerVAPIError(err)
+	}
+	return converter.convertStructValueToLBHTTPMonitorProfile(result)
+}
+
+func (b *nsxtBroker) ReadLoadBalancerHTTPMonitorProfile(id string) (model.LBHttpMonitorProfile, error) {
+	itf, err := b.lbMonitorProfilesClient.Get(id)
+	if err != nil {
+		return model.LBHttpMonitorProfile{}, errors.Wrapf(nicerVAPIError(err), "getting LBHttpMonitorProfile %s failed", id)
+	}
+	return newNsxtTypeConverter().convertStructValueToLBHTTPMonitorProfile(itf)
+}
+
+func (b *nsxtBroker) UpdateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	result, err := b.createOrUpdateLoadBalancerHTTPMonitorProfile(*monitor.Id, monitor)
+	return result, nicerVAPIError(err)
+}
+
 func (b *nsxtBroker) DeleteLoadBalancerMonitorProfile(id string) error {
 	err := b.lbMonitorProfilesClient.Delete(id, nil)
 	return nicerVAPIError(err)
 }
 
+func (b *nsxtBroker) ReadTier1(id string) (model.Tier1, error) {
+	result, err := b.tier1sClient.Get(id)
+	return result, nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) PatchTier1(id string, tier1 model.Tier1) error {
+	err := b.tier1sClient.Patch(id, tier1)
+	return nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) ListTier1LocaleServices(tier1ID string) ([]model.LocaleServices, error) {
+	return listAll(func(cursor *string) ([]model.LocaleServices, *string, error) {
+		result, err := b.localeServicesClient.List(tier1ID, cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
+}
+
+func (b *nsxtBroker) PatchTier1LocaleServices(tier1ID string, id string, localeServices model.LocaleServices) error {
+	err := b.localeServicesClient.Patch(tier1ID, id, localeServices)
+	return nicerVAPIError(err)
+}
+
+func (b *nsxtBroker) ReadEdgeCluster(siteID, enforcementPointID, id string) (model.PolicyEdgeCluster, error) {
+	result, err := b.edgeClustersClient.Get(siteID, enforcementPointID, id)
+	return result, nicerVAPIError(err)
+}
+
 func (b *nsxtBroker) ListIPPools() ([]model.IpAddressPool, error) {
-	result, err := b.ipPoolsClient.List(nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return nil, nicerVAPIError(err)
-	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.ipPoolsClient.List(result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
-	}
-	return list, nil
+	return listAll(func(cursor *string) ([]model.IpAddressPool, *string, error) {
+		result, err := b.ipPoolsClient.List(cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
 }
 
 func (b *nsxtBroker) AllocateFromIPPool(ipPoolID string, allocation model.IpAddressAllocation) (model.IpAddressAllocation, string, error) {
@@ -317,20 +406,15 @@ func (b *nsxtBroker) AllocateFromIPPool(ipPoolID string, allocation model.IpAddr
 }
 
 func (b *nsxtBroker) ListIPPoolAllocations(ipPoolID string) ([]model.IpAddressAllocation, error) {
-	result, err := b.ipAllocationsClient.List(ipPoolID, nil, nil, nil, nil, nil, nil)
-	if err != nil {
-		return nil, nicerVAPIError(err)
-	}
-	list := result.Results
-	count := int(*result.ResultCount)
-	for len(list) < count {
-		result, err = b.ipAllocationsClient.List(ipPoolID, result.Cursor, nil, nil, nil, nil, nil)
-		if err != nil {
-			return nil, nicerVAPIError(err)
-		}
-		list = append(list, result.Results...)
-	}
-	return list, nil
+	return listAll(func(cursor *string) ([]model.IpAddressAllocation, *string, error) {
+		result, err := b.ipAllocationsClient.List(ipPoolID, cursor, nil, nil, nil, nil, nil)
+		return result.Results, result.Cursor, err
+	})
+}
+
+func (b *nsxtBroker) UpdateIPPoolAllocation(ipPoolID string, allocation model.IpAddressAllocation) error {
+	err := b.ipAllocationsClient.Patch(ipPoolID, *allocation.Id, allocation)
+	return nicerVAPIError(err)
 }
 
 func (b *nsxtBroker) ReleaseFromIPPool(ipPoolID, ipAllocationID string) error {
@@ -362,7 +446,29 @@ func (b *nsxtBroker) GetRealizedExternalIPAddress(ipAllocationPath string, timeo
 			}
 		}
 	}
-	return nil, fmt.Errorf("Timeout of wait for realized state of IP allocation")
+	return nil, errRealizationTimeout
+}
+
+// listAll collects the results of a paginated list call with nsxt.ListAll.
+func listAll[T any](list func(cursor *string) ([]T, *string, error)) ([]T, error) {
+	all, err := nsxt.ListAll(list)
+	if err != nil {
+		return nil, nicerVAPIError(err)
+	}
+	return all, nil
+}
+
+// errRealizationTimeout is returned when NSX-T does not realize an IP address
+// allocation in time
+var errRealizationTimeout = errors.New("Timeout of wait for realized state of IP allocation")
+
+// unreachableError is returned when a request did not reach NSX-T
+type unreachableError struct {
+	error
+}
+
+func (e *unreachableError) Unwrap() error {
+	return e.error
 }
 
 func nicerVAPIError(err error) error {
@@ -379,7 +485,10 @@ func nicerVAPIError(err error) error {
 	case vapi_errors.InternalServerError:
 		return nicerVapiErrorData("InternalServerError", vapiError.Data, vapiError.Messages)
 	case vapi_errors.ServiceUnavailable:
-		return nicerVapiErrorData("ServiceUnavailable", vapiError.Data, vapiError.Messages)
+		// transport errors end up here
+		return &unreachableError{nicerVapiErrorData("ServiceUnavailable", vapiError.Data, vapiError.Messages)}
+	case vapi_errors.TimedOut:
+		return &unreachableError{nicerVapiErrorData("TimedOut", vapiError.Data, vapiError.Messages)}
 	}
 
 	return err
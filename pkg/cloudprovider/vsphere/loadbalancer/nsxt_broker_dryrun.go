@@ -0,0 +1,299 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics/legacyregistry"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// DryRunReason is the reason of the event emitted on a Service for each
+// NSX-T operation skipped in dry-run mode
+const DryRunReason = "LoadBalancerDryRun"
+
+// dryRunIDPrefix prefixes the ids of the objects pretended to be created in
+// dry-run mode
+const dryRunIDPrefix = "dry-run-"
+
+// dryRunIPAddress is the IP address pretended to be allocated in dry-run mode
+const dryRunIPAddress = "0.0.0.0"
+
+// dryRunOperationsMetric counts the NSX-T operations skipped in dry-run mode
+var dryRunOperationsMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "loadbalancer_dry_run_operations",
+		Help: "NSX-T create, update and delete operations skipped by the load balancer in dry-run mode",
+	},
+	[]string{"method", "resource"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(dryRunOperationsMetric)
+}
+
+// dryRunBroker passes the reads of a NsxtBroker through and only logs and
+// records the operations mutating NSX-T objects. The created objects get
+// made up ids, they are not found by later reads.
+type dryRunBroker struct {
+	NsxtBroker
+
+	lock sync.Mutex
+	// recorder emits an event on the Service of the object of a skipped
+	// operation, nil to not emit events
+	recorder record.EventRecorder
+	// services are the Services of the objects listed, keyed by object id,
+	// to find the Service of an object deleted by id
+	services map[string]string
+}
+
+var _ NsxtBroker = &dryRunBroker{}
+
+// newDryRunBroker returns a NsxtBroker not mutating NSX-T objects
+func newDryRunBroker(broker NsxtBroker) *dryRunBroker {
+	return &dryRunBroker{
+		NsxtBroker: broker,
+		services:   map[string]string{},
+	}
+}
+
+// setRecorder sets the recorder of the events of the skipped operations
+func (b *dryRunBroker) setRecorder(recorder record.EventRecorder) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.recorder = recorder
+}
+
+// skip logs and records an operation on the object with the given id or
+// display name, and emits an event on the Service it belongs to if known
+func (b *dryRunBroker) skip(method, resource string, id, displayName *string, tags []model.Tag) {
+	name := ""
+	if displayName != nil {
+		name = *displayName
+	} else if id != nil {
+		name = *id
+	}
+	dryRunOperationsMetric.WithLabelValues(method, resource).Inc()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	service := getTag(tags, ScopeService)
+	if service == "" && id != nil {
+		service = b.services[*id]
+	}
+	if service == "" {
+		klog.Infof("dry run: skipping %s of %s %s", method, resource, name)
+		return
+	}
+	klog.Infof("dry run: skipping %s of %s %s of service %s", method, resource, name, service)
+	if b.recorder != nil {
+		objectName := parseNamespacedName(service)
+		ref := &corev1.ObjectReference{
+			Kind:      "Service",
+			Namespace: objectName.Namespace,
+			Name:      objectName.Name,
+		}
+		b.recorder.Eventf(ref, corev1.EventTypeNormal, DryRunReason, "Dry run: skipped %s of %s %s", method, resource, name)
+	}
+}
+
+// remember records the Service of the listed object
+func (b *dryRunBroker) remember(id *string, tags []model.Tag) {
+	service := getTag(tags, ScopeService)
+	if id == nil || service == "" {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.services[*id] = service
+}
+
+// newDryRunID returns a made up id and path for an object created under
+// the parent path
+func newDryRunID(parentPath string) (*string, *string) {
+	id := dryRunIDPrefix + uuid.New().String()
+	return &id, strptr(parentPath + "/" + id)
+}
+
+func (b *dryRunBroker) CreateLoadBalancerService(service model.LBService) (model.LBService, error) {
+	service.Id, service.Path = newDryRunID("/infra/lb-services")
+	b.skip("create", resourceLBService, service.Id, service.DisplayName, service.Tags)
+	return service, nil
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerService(service model.LBService) (model.LBService, error) {
+	b.skip("update", resourceLBService, service.Id, service.DisplayName, service.Tags)
+	return service, nil
+}
+
+func (b *dryRunBroker) DeleteLoadBalancerService(id string) error {
+	b.skip("delete", resourceLBService, &id, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) CreateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
+	server.Id, server.Path = newDryRunID("/infra/lb-virtual-servers")
+	b.skip("create", resourceVirtualServer, server.Id, server.DisplayName, server.Tags)
+	return server, nil
+}
+
+func (b *dryRunBroker) ListLoadBalancerVirtualServers() ([]model.LBVirtualServer, error) {
+	result, err := b.NsxtBroker.ListLoadBalancerVirtualServers()
+	for _, item := range result {
+		b.remember(item.Id, item.Tags)
+	}
+	return result, err
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
+	b.skip("update", resourceVirtualServer, server.Id, server.DisplayName, server.Tags)
+	return server, nil
+}
+
+func (b *dryRunBroker) DeleteLoadBalancerVirtualServer(id string) error {
+	b.skip("delete", resourceVirtualServer, &id, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) CreateLoadBalancerPool(pool model.LBPool) (model.LBPool, error) {
+	pool.Id, pool.Path = newDryRunID("/infra/lb-pools")
+	b.skip("create", resourceLBPool, pool.Id, pool.DisplayName, pool.Tags)
+	return pool, nil
+}
+
+func (b *dryRunBroker) ListLoadBalancerPools() ([]model.LBPool, error) {
+	result, err := b.NsxtBroker.ListLoadBalancerPools()
+	for _, item := range result {
+		b.remember(item.Id, item.Tags)
+	}
+	return result, err
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerPool(pool model.LBPool) (model.LBPool, error) {
+	b.skip("update", resourceLBPool, pool.Id, pool.DisplayName, pool.Tags)
+	return pool, nil
+}
+
+func (b *dryRunBroker) DeleteLoadBalancerPool(id string) error {
+	b.skip("delete", resourceLBPool, &id, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) AllocateFromIPPool(ipPoolID string, allocation model.IpAddressAllocation) (model.IpAddressAllocation, string, error) {
+	allocation.Id, allocation.Path = newDryRunID("/infra/ip-pools/" + ipPoolID + "/ip-allocations")
+	b.skip("create", resourceIPAllocation, allocation.Id, allocation.DisplayName, allocation.Tags)
+	return allocation, dryRunIPAddress, nil
+}
+
+func (b *dryRunBroker) ListIPPoolAllocations(ipPoolID string) ([]model.IpAddressAllocation, error) {
+	result, err := b.NsxtBroker.ListIPPoolAllocations(ipPoolID)
+	for _, item := range result {
+		b.remember(item.Id, item.Tags)
+	}
+	return result, err
+}
+
+func (b *dryRunBroker) UpdateIPPoolAllocation(_ string, allocation model.IpAddressAllocation) error {
+	b.skip("update", resourceIPAllocation, allocation.Id, allocation.DisplayName, allocation.Tags)
+	return nil
+}
+
+func (b *dryRunBroker) ReleaseFromIPPool(_, ipAllocationID string) error {
+	b.skip("delete", resourceIPAllocation, &ipAllocationID, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) GetRealizedExternalIPAddress(ipAllocationPath string, timeout time.Duration) (*string, error) {
+	if strings.Contains(ipAllocationPath, "/"+dryRunIDPrefix) {
+		return strptr(dryRunIPAddress), nil
+	}
+	return b.NsxtBroker.GetRealizedExternalIPAddress(ipAllocationPath, timeout)
+}
+
+func (b *dryRunBroker) CreateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	profile.Id, profile.Path = newDryRunID("/infra/lb-app-profiles")
+	b.skip("create", resourceAppProfile, profile.Id, profile.DisplayName, profile.Tags)
+	return profile, nil
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	b.skip("update", resourceAppProfile, profile.Id, profile.DisplayName, profile.Tags)
+	return profile, nil
+}
+
+func (b *dryRunBroker) DeleteLoadBalancerAppProfile(id string) error {
+	b.skip("delete", resourceAppProfile, &id, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) CreateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error) {
+	monitor.Id, monitor.Path = newDryRunID("/infra/lb-monitor-profiles")
+	b.skip("create", resourceMonitorProfile, monitor.Id, monitor.DisplayName, monitor.Tags)
+	return monitor, nil
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error) {
+	b.skip("update", resourceMonitorProfile, monitor.Id, monitor.DisplayName, monitor.Tags)
+	return monitor, nil
+}
+
+func (b *dryRunBroker) CreateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	monitor.Id, monitor.Path = newDryRunID("/infra/lb-monitor-profiles")
+	b.skip("create", resourceMonitorProfile, monitor.Id, monitor.DisplayName, monitor.Tags)
+	return monitor, nil
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	b.skip("update", resourceMonitorProfile, monitor.Id, monitor.DisplayName, monitor.Tags)
+	return monitor, nil
+}
+
+func (b *dryRunBroker) CreateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	monitor.Id, monitor.Path = newDryRunID("/infra/lb-monitor-profiles")
+	b.skip("create", resourceMonitorProfile, monitor.Id, monitor.DisplayName, monitor.Tags)
+	return monitor, nil
+}
+
+func (b *dryRunBroker) UpdateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	b.skip("update", resourceMonitorProfile, monitor.Id, monitor.DisplayName, monitor.Tags)
+	return monitor, nil
+}
+
+func (b *dryRunBroker) DeleteLoadBalancerMonitorProfile(id string) error {
+	b.skip("delete", resourceMonitorProfile, &id, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) PatchTier1(id string, _ model.Tier1) error {
+	b.skip("update", resourceTier1, &id, nil, nil)
+	return nil
+}
+
+func (b *dryRunBroker) PatchTier1LocaleServices(_ string, id string, _ model.LocaleServices) error {
+	b.skip("update", resourceLocaleServices, &id, nil, nil)
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+// poolListBroker lists the given pools. Methods not needed by the dry run
+// panic.
+type poolListBroker struct {
+	NsxtBroker
+	pools []model.LBPool
+}
+
+func (b *poolListBroker) ListLoadBalancerPools() ([]model.LBPool, error) {
+	return b.pools, nil
+}
+
+func TestDryRunBroker(t *testing.T) {
+	web := types.NamespacedName{Namespace: "default", Name: "web"}
+	inner := &poolListBroker{pools: []model.LBPool{
+		{Id: strptr("pool-1"), Tags: []model.Tag{clusterTag("cluster1"), serviceTag(web)}},
+	}}
+	broker := newDryRunBroker(inner)
+	recorder := record.NewFakeRecorder(10)
+	broker.setRecorder(recorder)
+	created := testutil.ToFloat64(dryRunOperationsMetric.WithLabelValues("create", resourceVirtualServer))
+	deleted := testutil.ToFloat64(dryRunOperationsMetric.WithLabelValues("delete", resourceLBPool))
+
+	server, err := broker.CreateLoadBalancerVirtualServer(model.LBVirtualServer{
+		DisplayName: strptr("cluster:cluster1:default/web"),
+		Tags:        []model.Tag{clusterTag("cluster1"), serviceTag(web)},
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, *server.Id, dryRunIDPrefix)
+	assert.Equal(t, "/infra/lb-virtual-servers/"+*server.Id, *server.Path)
+	assert.Equal(t, "Normal LoadBalancerDryRun Dry run: skipped create of virtual_server cluster:cluster1:default/web", <-recorder.Events)
+
+	// the service of an object deleted by id is known from the listing
+	_, err = broker.ListLoadBalancerPools()
+	assert.NoError(t, err)
+	assert.NoError(t, broker.DeleteLoadBalancerPool("pool-1"))
+	assert.Equal(t, "Normal LoadBalancerDryRun Dry run: skipped delete of lb_pool pool-1", <-recorder.Events)
+	assert.NoError(t, broker.DeleteLoadBalancerPool("pool-2"))
+	assert.Equal(t, 0, len(recorder.Events))
+
+	allocation, ipAddress, err := broker.AllocateFromIPPool("pool", model.IpAddressAllocation{})
+	assert.NoError(t, err)
+	realized, err := broker.GetRealizedExternalIPAddress(*allocation.Path, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, ipAddress, *realized)
+
+	assert.Equal(t, created+1, testutil.ToFloat64(dryRunOperationsMetric.WithLabelValues("create", resourceVirtualServer)))
+	assert.Equal(t, deleted+2, testutil.ToFloat64(dryRunOperationsMetric.WithLabelValues("delete", resourceLBPool)))
+}
@@ -0,0 +1,396 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// Resource types of the NSX-T API calls, used as label of the broker metrics
+const (
+	resourceLBService       = "lb_service"
+	resourceVirtualServer   = "virtual_server"
+	resourceLBPool          = "lb_pool"
+	resourceIPPool          = "ip_pool"
+	resourceIPAllocation    = "ip_allocation"
+	resourceAppProfile      = "app_profile"
+	resourceMonitorProfile  = "monitor_profile"
+	resourceTier1           = "tier1"
+	resourceLocaleServices  = "locale_services"
+	resourceEdgeCluster     = "edge_cluster"
+	resourceRealizedAddress = "realized_address"
+)
+
+// Kinds of the errors of the NSX-T API calls, used as label of
+// nsxtAPIErrorMetric
+const (
+	apiErrorUnreachable        = "unreachable"
+	apiErrorRealizationTimeout = "realization_timeout"
+	apiErrorNotFound           = "not_found"
+	apiErrorOther              = "other"
+)
+
+var (
+	// nsxtAPIDurationMetric is the latency of the NSX-T API calls of the
+	// broker, including the failed ones
+	nsxtAPIDurationMetric = metrics.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "loadbalancer_nsxt_api_request_duration_seconds",
+			Help:    "Latency of the NSX-T API calls of the load balancer",
+			Buckets: prometheus.ExponentialBuckets(0.025, 2, 12),
+		},
+		[]string{"method", "resource"},
+	)
+
+	// nsxtAPIErrorMetric counts the failed NSX-T API calls of the broker
+	nsxtAPIErrorMetric = metrics.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "loadbalancer_nsxt_api_request_errors",
+			Help: "Failed NSX-T API calls of the load balancer",
+		},
+		[]string{"method", "resource", "error"},
+	)
+)
+
+func init() {
+	legacyregistry.RawMustRegister(nsxtAPIDurationMetric)
+	legacyregistry.RawMustRegister(nsxtAPIErrorMetric)
+}
+
+// apiErrorKind classifies the error of a NSX-T API call
+func apiErrorKind(err error) string {
+	var unreachable *unreachableError
+	switch {
+	case errors.As(err, &unreachable):
+		return apiErrorUnreachable
+	case errors.Is(err, errRealizationTimeout):
+		return apiErrorRealizationTimeout
+	case isNotFoundError(err):
+		return apiErrorNotFound
+	}
+	return apiErrorOther
+}
+
+// observeAPICall records the latency and the error of a NSX-T API call
+// started at start
+func observeAPICall(method, resource string, start time.Time, err error) {
+	nsxtAPIDurationMetric.WithLabelValues(method, resource).Observe(time.Since(start).Seconds())
+	if err != nil {
+		nsxtAPIErrorMetric.WithLabelValues(method, resource, apiErrorKind(err)).Inc()
+	}
+}
+
+// metricsBroker instruments the calls of a NsxtBroker
+type metricsBroker struct {
+	broker NsxtBroker
+}
+
+var _ NsxtBroker = &metricsBroker{}
+
+// newMetricsBroker returns a NsxtBroker recording the latency and the errors
+// of the calls of broker
+func newMetricsBroker(broker NsxtBroker) NsxtBroker {
+	return &metricsBroker{broker: broker}
+}
+
+func (b *metricsBroker) ReadLoadBalancerService(id string) (model.LBService, error) {
+	start := time.Now()
+	result, err := b.broker.ReadLoadBalancerService(id)
+	observeAPICall("read", resourceLBService, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) CreateLoadBalancerService(service model.LBService) (model.LBService, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerService(service)
+	observeAPICall("create", resourceLBService, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ListLoadBalancerServices() ([]model.LBService, error) {
+	start := time.Now()
+	result, err := b.broker.ListLoadBalancerServices()
+	observeAPICall("list", resourceLBService, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerService(service model.LBService) (model.LBService, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerService(service)
+	observeAPICall("update", resourceLBService, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) DeleteLoadBalancerService(id string) error {
+	start := time.Now()
+	err := b.broker.DeleteLoadBalancerService(id)
+	observeAPICall("delete", resourceLBService, start, err)
+	return err
+}
+
+func (b *metricsBroker) CreateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerVirtualServer(server)
+	observeAPICall("create", resourceVirtualServer, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ListLoadBalancerVirtualServers() ([]model.LBVirtualServer, error) {
+	start := time.Now()
+	result, err := b.broker.ListLoadBalancerVirtualServers()
+	observeAPICall("list", resourceVirtualServer, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerVirtualServer(server)
+	observeAPICall("update", resourceVirtualServer, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) DeleteLoadBalancerVirtualServer(id string) error {
+	start := time.Now()
+	err := b.broker.DeleteLoadBalancerVirtualServer(id)
+	observeAPICall("delete", resourceVirtualServer, start, err)
+	return err
+}
+
+func (b *metricsBroker) CreateLoadBalancerPool(pool model.LBPool) (model.LBPool, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerPool(pool)
+	observeAPICall("create", resourceLBPool, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ReadLoadBalancerPool(id string) (model.LBPool, error) {
+	start := time.Now()
+	result, err := b.broker.ReadLoadBalancerPool(id)
+	observeAPICall("read", resourceLBPool, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ListLoadBalancerPools() ([]model.LBPool, error) {
+	start := time.Now()
+	result, err := b.broker.ListLoadBalancerPools()
+	observeAPICall("list", resourceLBPool, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerPool(pool model.LBPool) (model.LBPool, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerPool(pool)
+	observeAPICall("update", resourceLBPool, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) DeleteLoadBalancerPool(id string) error {
+	start := time.Now()
+	err := b.broker.DeleteLoadBalancerPool(id)
+	observeAPICall("delete", resourceLBPool, start, err)
+	return err
+}
+
+func (b *metricsBroker) ListIPPools() ([]model.IpAddressPool, error) {
+	start := time.Now()
+	result, err := b.broker.ListIPPools()
+	observeAPICall("list", resourceIPPool, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) AllocateFromIPPool(ipPoolID string, allocation model.IpAddressAllocation) (model.IpAddressAllocation, string, error) {
+	start := time.Now()
+	result, path, err := b.broker.AllocateFromIPPool(ipPoolID, allocation)
+	observeAPICall("create", resourceIPAllocation, start, err)
+	return result, path, err
+}
+
+func (b *metricsBroker) ListIPPoolAllocations(ipPoolID string) ([]model.IpAddressAllocation, error) {
+	start := time.Now()
+	result, err := b.broker.ListIPPoolAllocations(ipPoolID)
+	observeAPICall("list", resourceIPAllocation, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateIPPoolAllocation(ipPoolID string, allocation model.IpAddressAllocation) error {
+	start := time.Now()
+	err := b.broker.UpdateIPPoolAllocation(ipPoolID, allocation)
+	observeAPICall("update", resourceIPAllocation, start, err)
+	return err
+}
+
+func (b *metricsBroker) ReleaseFromIPPool(ipPoolID, ipAllocationID string) error {
+	start := time.Now()
+	err := b.broker.ReleaseFromIPPool(ipPoolID, ipAllocationID)
+	observeAPICall("delete", resourceIPAllocation, start, err)
+	return err
+}
+
+func (b *metricsBroker) GetRealizedExternalIPAddress(ipAllocationPath string, timeout time.Duration) (*string, error) {
+	start := time.Now()
+	result, err := b.broker.GetRealizedExternalIPAddress(ipAllocationPath, timeout)
+	observeAPICall("read", resourceRealizedAddress, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ListAppProfiles() ([]*data.StructValue, error) {
+	start := time.Now()
+	result, err := b.broker.ListAppProfiles()
+	observeAPICall("list", resourceAppProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) CreateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerHTTPAppProfile(profile)
+	observeAPICall("create", resourceAppProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerHTTPAppProfile(profile model.LBHttpProfile) (model.LBHttpProfile, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerHTTPAppProfile(profile)
+	observeAPICall("update", resourceAppProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) DeleteLoadBalancerAppProfile(id string) error {
+	start := time.Now()
+	err := b.broker.DeleteLoadBalancerAppProfile(id)
+	observeAPICall("delete", resourceAppProfile, start, err)
+	return err
+}
+
+func (b *metricsBroker) CreateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerTCPMonitorProfile(monitor)
+	observeAPICall("create", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ListLoadBalancerMonitorProfiles() ([]*data.StructValue, error) {
+	start := time.Now()
+	result, err := b.broker.ListLoadBalancerMonitorProfiles()
+	observeAPICall("list", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ReadLoadBalancerTCPMonitorProfile(id string) (model.LBTcpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.ReadLoadBalancerTCPMonitorProfile(id)
+	observeAPICall("read", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerTCPMonitorProfile(monitor model.LBTcpMonitorProfile) (model.LBTcpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerTCPMonitorProfile(monitor)
+	observeAPICall("update", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) CreateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerUDPMonitorProfile(monitor)
+	observeAPICall("create", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ReadLoadBalancerUDPMonitorProfile(id string) (model.LBUdpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.ReadLoadBalancerUDPMonitorProfile(id)
+	observeAPICall("read", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerUDPMonitorProfile(monitor model.LBUdpMonitorProfile) (model.LBUdpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerUDPMonitorProfile(monitor)
+	observeAPICall("update", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) CreateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.CreateLoadBalancerHTTPMonitorProfile(monitor)
+	observeAPICall("create", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) ReadLoadBalancerHTTPMonitorProfile(id string) (model.LBHttpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.ReadLoadBalancerHTTPMonitorProfile(id)
+	observeAPICall("read", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) UpdateLoadBalancerHTTPMonitorProfile(monitor model.LBHttpMonitorProfile) (model.LBHttpMonitorProfile, error) {
+	start := time.Now()
+	result, err := b.broker.UpdateLoadBalancerHTTPMonitorProfile(monitor)
+	observeAPICall("update", resourceMonitorProfile, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) DeleteLoadBalancerMonitorProfile(id string) error {
+	start := time.Now()
+	err := b.broker.DeleteLoadBalancerMonitorProfile(id)
+	observeAPICall("delete", resourceMonitorProfile, start, err)
+	return err
+}
+
+func (b *metricsBroker) ReadTier1(id string) (model.Tier1, error) {
+	start := time.Now()
+	result, err := b.broker.ReadTier1(id)
+	observeAPICall("read", resourceTier1, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) PatchTier1(id string, tier1 model.Tier1) error {
+	start := time.Now()
+	err := b.broker.PatchTier1(id, tier1)
+	observeAPICall("patch", resourceTier1, start, err)
+	return err
+}
+
+func (b *metricsBroker) ListTier1LocaleServices(tier1ID string) ([]model.LocaleServices, error) {
+	start := time.Now()
+	result, err := b.broker.ListTier1LocaleServices(tier1ID)
+	observeAPICall("list", resourceLocaleServices, start, err)
+	return result, err
+}
+
+func (b *metricsBroker) PatchTier1LocaleServices(tier1ID string, id string, localeServices model.LocaleServices) error {
+	start := time.Now()
+	err := b.broker.PatchTier1LocaleServices(tier1ID, id, localeServices)
+	observeAPICall("patch", resourceLocaleServices, start, err)
+	return err
+}
+
+func (b *metricsBroker) ReadEdgeCluster(siteID, enforcementPointID, id string) (model.PolicyEdgeCluster, error) {
+	start := time.Now()
+	result, err := b.broker.ReadEdgeCluster(siteID, enforcementPointID, id)
+	observeAPICall("read", resourceEdgeCluster, start, err)
+	return result, err
+}
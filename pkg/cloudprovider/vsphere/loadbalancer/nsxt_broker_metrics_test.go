@@ -0,0 +1,88 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	vapi_errors "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+// failingBroker fails the calls with the given error. Methods not needed
+// for the metrics panic.
+type failingBroker struct {
+	NsxtBroker
+	err error
+}
+
+func (b *failingBroker) ReadLoadBalancerPool(string) (model.LBPool, error) {
+	return model.LBPool{}, b.err
+}
+
+func (b *failingBroker) ListIPPools() ([]model.IpAddressPool, error) {
+	return nil, b.err
+}
+
+func (b *failingBroker) GetRealizedExternalIPAddress(string, time.Duration) (*string, error) {
+	return nil, b.err
+}
+
+func TestMetricsBroker(t *testing.T) {
+	inner := &failingBroker{}
+	broker := newMetricsBroker(inner)
+	nsxtAPIErrorMetric.Reset()
+
+	if _, err := broker.ListIPPools(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Positive(t, testutil.CollectAndCount(nsxtAPIDurationMetric))
+	assert.Equal(t, 0.0, testutil.ToFloat64(nsxtAPIErrorMetric.WithLabelValues("list", resourceIPPool, apiErrorOther)))
+
+	testCases := []struct {
+		err  error
+		kind string
+		call func() error
+	}{
+		{
+			err:  &unreachableError{fmt.Errorf("ServiceUnavailable")},
+			kind: apiErrorUnreachable,
+			call: func() error { _, err := broker.ListIPPools(); return err },
+		},
+		{
+			err:  vapi_errors.NotFound{},
+			kind: apiErrorNotFound,
+			call: func() error { _, err := broker.ReadLoadBalancerPool("pool"); return err },
+		},
+		{
+			err:  errRealizationTimeout,
+			kind: apiErrorRealizationTimeout,
+			call: func() error { _, err := broker.GetRealizedExternalIPAddress("path", time.Second); return err },
+		},
+	}
+	for _, tc := range testCases {
+		inner.err = tc.err
+		assert.Equal(t, tc.err, tc.call())
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(nsxtAPIErrorMetric.WithLabelValues("list", resourceIPPool, apiErrorUnreachable)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(nsxtAPIErrorMetric.WithLabelValues("read", resourceLBPool, apiErrorNotFound)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(nsxtAPIErrorMetric.WithLabelValues("read", resourceRealizedAddress, apiErrorRealizationTimeout)))
+}
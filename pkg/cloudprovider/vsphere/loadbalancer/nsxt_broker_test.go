@@ -0,0 +1,156 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+// pagedLbServicesClient returns the pages of LBServices keyed by cursor, the
+// first page having the empty cursor. Methods not needed for listing panic.
+type pagedLbServicesClient struct {
+	infra.LbServicesClient
+	pages map[string]model.LBServiceListResult
+	calls int
+}
+
+func (c *pagedLbServicesClient) List(cursor *string, _ *bool, _ *string, _ *int64, _ *bool, _ *string) (model.LBServiceListResult, error) {
+	c.calls++
+	key := ""
+	if cursor != nil {
+		key = *cursor
+	}
+	page, ok := c.pages[key]
+	if !ok {
+		return model.LBServiceListResult{}, fmt.Errorf("unknown cursor %q", key)
+	}
+	return page, nil
+}
+
+func lbServicePage(count int64, cursor string, ids ...string) model.LBServiceListResult {
+	page := model.LBServiceListResult{ResultCount: &count}
+	if cursor != "" {
+		page.Cursor = strptr(cursor)
+	}
+	for _, id := range ids {
+		page.Results = append(page.Results, model.LBService{Id: strptr(id)})
+	}
+	return page
+}
+
+func TestListLoadBalancerServicesPagination(t *testing.T) {
+	testCases := []struct {
+		name          string
+		pages         map[string]model.LBServiceListResult
+		expectedIDs   []string
+		expectedCalls int
+		expectedErr   bool
+	}{
+		{
+			name:          "single page",
+			pages:         map[string]model.LBServiceListResult{"": lbServicePage(2, "", "a", "b")},
+			expectedIDs:   []string{"a", "b"},
+			expectedCalls: 1,
+		},
+		{
+			name: "all pages",
+			pages: map[string]model.LBServiceListResult{
+				"":   lbServicePage(3, "c1", "a"),
+				"c1": lbServicePage(3, "c2", "b"),
+				"c2": lbServicePage(3, "", "c"),
+			},
+			expectedIDs:   []string{"a", "b", "c"},
+			expectedCalls: 3,
+		},
+		{
+			name: "count shrinking while listing",
+			pages: map[string]model.LBServiceListResult{
+				"":   lbServicePage(3, "c1", "a"),
+				"c1": lbServicePage(1, "c2", "b"),
+				"c2": lbServicePage(1, "", "c"),
+			},
+			expectedIDs:   []string{"a", "b", "c"},
+			expectedCalls: 3,
+		},
+		{
+			name: "count growing while listing",
+			pages: map[string]model.LBServiceListResult{
+				"":   lbServicePage(2, "c1", "a"),
+				"c1": lbServicePage(5, "", "b"),
+			},
+			expectedIDs:   []string{"a", "b"},
+			expectedCalls: 2,
+		},
+		{
+			name: "empty last page",
+			pages: map[string]model.LBServiceListResult{
+				"":   lbServicePage(1, "c1", "a"),
+				"c1": lbServicePage(1, "c2"),
+			},
+			expectedIDs:   []string{"a"},
+			expectedCalls: 2,
+		},
+		{
+			name: "repeated cursor",
+			pages: map[string]model.LBServiceListResult{
+				"":   lbServicePage(3, "c1", "a"),
+				"c1": lbServicePage(3, "c1", "b"),
+			},
+			expectedErr: true,
+		},
+		{
+			name: "failing page",
+			pages: map[string]model.LBServiceListResult{
+				"": lbServicePage(2, "c1", "a"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := &pagedLbServicesClient{pages: testCase.pages}
+			broker := &nsxtBroker{lbServicesClient: client}
+
+			services, err := broker.ListLoadBalancerServices()
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var ids []string
+			for _, service := range services {
+				ids = append(ids, *service.Id)
+			}
+			if !reflect.DeepEqual(ids, testCase.expectedIDs) {
+				t.Errorf("expected %v, but got %v", testCase.expectedIDs, ids)
+			}
+			if client.calls != testCase.expectedCalls {
+				t.Errorf("expected %d calls, but got %d", testCase.expectedCalls, client.calls)
+			}
+		})
+	}
+}
@@ -80,3 +80,69 @@ func (c *nsxtTypeConverter) convertStructValueToLBTCPMonitorProfile(dataValue *d
 	}
 	return profile, nil
 }
+
+func (c *nsxtTypeConverter) convertLBUDPMonitorProfileToStructValue(monitor model.LBUdpMonitorProfile) (*data.StructValue, error) {
+	dataValue, errs := c.ConvertToVapi(monitor, model.LBUdpMonitorProfileBindingType())
+	if errs != nil {
+		return nil, errs[0]
+	}
+
+	return dataValue.(*data.StructValue), nil
+}
+
+func (c *nsxtTypeConverter) convertStructValueToLBUDPMonitorProfile(dataValue *data.StructValue) (model.LBUdpMonitorProfile, error) {
+	itf, errs := c.ConvertToGolang(dataValue, model.LBUdpMonitorProfileBindingType())
+	if errs != nil {
+		return model.LBUdpMonitorProfile{}, errs[0]
+	}
+
+	profile, ok := itf.(model.LBUdpMonitorProfile)
+	if !ok {
+		return model.LBUdpMonitorProfile{}, fmt.Errorf("converting struct value to LBUdpMonitorProfile failed")
+	}
+	return profile, nil
+}
+
+func (c *nsxtTypeConverter) convertLBHTTPMonitorProfileToStructValue(monitor model.LBHttpMonitorProfile) (*data.StructValue, error) {
+	dataValue, errs := c.ConvertToVapi(monitor, model.LBHttpMonitorProfileBindingType())
+	if errs != nil {
+		return nil, errs[0]
+	}
+
+	return dataValue.(*data.StructValue), nil
+}
+
+func (c *nsxtTypeConverter) convertStructValueToLBHTTPMonitorProfile(dataValue *data.StructValue) (model.LBHttpMonitorProfile, error) {
+	itf, errs := c.ConvertToGolang(dataValue, model.LBHttpMonitorProfileBindingType())
+	if errs != nil {
+		return model.LBHttpMonitorProfile{}, errs[0]
+	}
+
+	profile, ok := itf.(model.LBHttpMonitorProfile)
+	if !ok {
+		return model.LBHttpMonitorProfile{}, fmt.Errorf("converting struct value to LBHttpMonitorProfile failed")
+	}
+	return profile, nil
+}
+
+func (c *nsxtTypeConverter) convertLBHTTPProfileToStructValue(profile model.LBHttpProfile) (*data.StructValue, error) {
+	dataValue, errs := c.ConvertToVapi(profile, model.LBHttpProfileBindingType())
+	if errs != nil {
+		return nil, errs[0]
+	}
+
+	return dataValue.(*data.StructValue), nil
+}
+
+func (c *nsxtTypeConverter) convertStructValueToLBHTTPProfile(dataValue *data.StructValue) (model.LBHttpProfile, error) {
+	itf, errs := c.ConvertToGolang(dataValue, model.LBHttpProfileBindingType())
+	if errs != nil {
+		return model.LBHttpProfile{}, errs[0]
+	}
+
+	profile, ok := itf.(model.LBHttpProfile)
+	if !ok {
+		return model.LBHttpProfile{}, fmt.Errorf("converting struct value to LBHttpProfile failed")
+	}
+	return profile, nil
+}
@@ -0,0 +1,62 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"sync"
+)
+
+// appProfileCache caches the paths of the application profiles resolved by
+// name, as resolving a name lists all application profiles of NSX-T.
+// The zero value is an empty cache.
+type appProfileCache struct {
+	lock  sync.Mutex
+	paths map[appProfileKey]string
+}
+
+type appProfileKey struct {
+	name         string
+	resourceType string
+}
+
+func (c *appProfileCache) get(name, resourceType string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	path, ok := c.paths[appProfileKey{name: name, resourceType: resourceType}]
+	return path, ok
+}
+
+func (c *appProfileCache) put(name, resourceType, path string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.paths == nil {
+		c.paths = map[appProfileKey]string{}
+	}
+	c.paths[appProfileKey{name: name, resourceType: resourceType}] = path
+}
+
+// invalidate drops the names resolved to the path, they are resolved again
+// on their next use
+func (c *appProfileCache) invalidate(path string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, value := range c.paths {
+		if value == path {
+			delete(c.paths, key)
+		}
+	}
+}
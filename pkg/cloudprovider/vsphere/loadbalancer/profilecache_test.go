@@ -0,0 +1,95 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+)
+
+// appProfileBroker lists the application profiles and fails to create
+// virtual servers while serverErr is set. Methods not needed panic.
+type appProfileBroker struct {
+	NsxtBroker
+	profiles  map[string]string
+	lists     int
+	serverErr error
+}
+
+func (b *appProfileBroker) ListAppProfiles() ([]*data.StructValue, error) {
+	b.lists++
+	var list []*data.StructValue
+	for name, path := range b.profiles {
+		list = append(list, data.NewStructValue("", map[string]data.DataValue{
+			"resource_type": data.NewStringValue(model.LBAppProfile_RESOURCE_TYPE_LBFASTTCPPROFILE),
+			"display_name":  data.NewStringValue(name),
+			"path":          data.NewStringValue(path),
+		}))
+	}
+	return list, nil
+}
+
+func (b *appProfileBroker) CreateLoadBalancerVirtualServer(server model.LBVirtualServer) (model.LBVirtualServer, error) {
+	return server, b.serverErr
+}
+
+func TestAppProfilePathCache(t *testing.T) {
+	broker := &appProfileBroker{profiles: map[string]string{"tcp": "/profiles/tcp1"}}
+	a, err := NewNSXTAccess(broker, &config.LBConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	class := &loadBalancerClass{className: "default", tcpAppProfile: Reference{Name: "tcp"}}
+
+	resolve := func(expected string) {
+		t.Helper()
+		path, err := a.GetAppProfilePath(class, corev1.ProtocolTCP)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if path != expected {
+			t.Errorf("expected path %s, but found %s", expected, path)
+		}
+	}
+
+	// prefetching resolves the profile once
+	prefetchAppProfiles(a, &loadBalancerClasses{classes: map[string]*loadBalancerClass{"default": class}})
+	resolve("/profiles/tcp1")
+	resolve("/profiles/tcp1")
+	if broker.lists != 1 {
+		t.Errorf("expected a single listing of the profiles, but found %d", broker.lists)
+	}
+
+	// a failure with the cached path resolves the name again
+	broker.profiles["tcp"] = "/profiles/tcp2"
+	broker.serverErr = fmt.Errorf("NotFound")
+	objectName := types.NamespacedName{Namespace: "default", Name: "web"}
+	if _, err := a.CreateVirtualServer("cluster1", objectName, "web", class, "10.0.0.10", Mapping{}, "/lbs1", "/profiles/tcp1", nil, nil); err == nil {
+		t.Fatalf("expected CreateVirtualServer to fail")
+	}
+	resolve("/profiles/tcp2")
+	if broker.lists != 2 {
+		t.Errorf("expected the profiles to be listed again, but found %d listings", broker.lists)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics/legacyregistry"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// LoadBalancerStuckReason is the reason of the event emitted on a Service
+// still without ingress after the provisioning deadline
+const LoadBalancerStuckReason = "LoadBalancerStuck"
+
+// Provisioning steps of a load balancer, reported as the blocking step of a
+// stuck provisioning
+const (
+	stepClass         = "selecting the load balancer class"
+	stepMapping       = "mapping the Service ports"
+	stepLookup        = "looking up the NSX-T objects"
+	stepTCPMonitor    = "reconciling the TCP monitor profiles"
+	stepUDPMonitor    = "reconciling the UDP monitor profiles"
+	stepHTTPMonitor   = "reconciling the HTTP monitor profiles"
+	stepPool          = "reconciling the pools"
+	stepIPAllocation  = "allocating the IP address"
+	stepLBService     = "creating the load balancer service"
+	stepHTTPProfile   = "reconciling the HTTP application profile"
+	stepVirtualServer = "reconciling the virtual servers"
+	stepCleanup       = "deleting the orphaned NSX-T objects"
+)
+
+// Reasons of a stuck provisioning, used as label of stuckLoadBalancersMetric
+const (
+	stuckReasonIPPoolExhausted    = "ip-pool-exhausted"
+	stuckReasonNSXUnreachable     = "nsx-unreachable"
+	stuckReasonRealizationTimeout = "realization-timeout"
+	stuckReasonOther              = "other"
+)
+
+// stuckLoadBalancersMetric counts the Services still without ingress after
+// the provisioning deadline, to alert on stuck provisioning.
+var stuckLoadBalancersMetric = metrics.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "stuck_loadbalancers",
+		Help: "Load balancers not provisioned within the provisioning deadline",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(stuckLoadBalancersMetric)
+}
+
+// provisioningTracker tracks the Services without ingress whose load balancer
+// fails to be provisioned. Once a Service fails for longer than the deadline,
+// it is counted in stuckLoadBalancersMetric and a warning event naming the
+// blocking step is emitted on it.
+type provisioningTracker struct {
+	deadline time.Duration
+	recorder record.EventRecorder
+	now      func() time.Time
+
+	lock    sync.Mutex
+	pending map[string]*pendingProvisioning
+}
+
+type pendingProvisioning struct {
+	// since is the time of the first failed attempt
+	since time.Time
+	// stuckReason is the reason the Service is counted with, empty until
+	// the deadline is exceeded
+	stuckReason string
+}
+
+func newProvisioningTracker(deadline time.Duration, recorder record.EventRecorder) *provisioningTracker {
+	return &provisioningTracker{
+		deadline: deadline,
+		recorder: recorder,
+		now:      time.Now,
+		pending:  map[string]*pendingProvisioning{},
+	}
+}
+
+// failed records a failed attempt to provision the load balancer of service,
+// blocked at step.
+func (t *provisioningTracker) failed(service *corev1.Service, step string, err error) {
+	if t == nil {
+		return
+	}
+	if len(service.Spec.Ports) == 0 || len(service.Status.LoadBalancer.Ingress) != 0 {
+		// deleted or provisioned already
+		t.done(service)
+		return
+	}
+
+	key := namespacedNameFromService(service).String()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, ok := t.pending[key]
+	if !ok {
+		p = &pendingProvisioning{since: t.now()}
+		t.pending[key] = p
+	}
+	waiting := t.now().Sub(p.since)
+	reason := stuckReason(step, err)
+	if waiting < t.deadline || reason == p.stuckReason {
+		return
+	}
+	if p.stuckReason != "" {
+		stuckLoadBalancersMetric.WithLabelValues(p.stuckReason).Dec()
+	}
+	stuckLoadBalancersMetric.WithLabelValues(reason).Inc()
+	p.stuckReason = reason
+
+	klog.Warningf("%s: load balancer not provisioned after %s (%s), blocked at %s: %v", key, waiting.Round(time.Second), reason, step, err)
+	t.recorder.Eventf(service, corev1.EventTypeWarning, LoadBalancerStuckReason,
+		"Load balancer not provisioned after %s (%s), blocked at %s: %v", waiting.Round(time.Second), reason, step, err)
+}
+
+// done forgets the failed attempts of service once its load balancer is
+// provisioned or deleted.
+func (t *provisioningTracker) done(service *corev1.Service) {
+	if t == nil {
+		return
+	}
+	key := namespacedNameFromService(service).String()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if p, ok := t.pending[key]; ok {
+		if p.stuckReason != "" {
+			stuckLoadBalancersMetric.WithLabelValues(p.stuckReason).Dec()
+		}
+		delete(t.pending, key)
+	}
+}
+
+// stuckReason classifies the error of a failed provisioning step
+func stuckReason(step string, err error) string {
+	var unreachable *unreachableError
+	switch {
+	case errors.Is(err, errRealizationTimeout):
+		return stuckReasonRealizationTimeout
+	case errors.As(err, &unreachable):
+		return stuckReasonNSXUnreachable
+	case step == stepIPAllocation:
+		return stuckReasonIPPoolExhausted
+	}
+	return stuckReasonOther
+}
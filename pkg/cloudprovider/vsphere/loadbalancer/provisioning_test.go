@@ -0,0 +1,124 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestStuckReason(t *testing.T) {
+	testCases := []struct {
+		step     string
+		err      error
+		expected string
+	}{
+		{stepIPAllocation, errors.Wrap(errRealizationTimeout, "allocating external IP address failed"), stuckReasonRealizationTimeout},
+		{stepLookup, errors.Wrap(&unreachableError{fmt.Errorf("ServiceUnavailable")}, "lookup failed"), stuckReasonNSXUnreachable},
+		{stepIPAllocation, &unreachableError{fmt.Errorf("TimedOut")}, stuckReasonNSXUnreachable},
+		{stepIPAllocation, fmt.Errorf("no IP address allocated"), stuckReasonIPPoolExhausted},
+		{stepVirtualServer, fmt.Errorf("InvalidRequest"), stuckReasonOther},
+	}
+	for _, testCase := range testCases {
+		if reason := stuckReason(testCase.step, testCase.err); reason != testCase.expected {
+			t.Errorf("%s %v: expected reason %s, but got %s", testCase.step, testCase.err, testCase.expected, reason)
+		}
+	}
+}
+
+func TestProvisioningTracker(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	tracker := newProvisioningTracker(10*time.Minute, recorder)
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80}}},
+	}
+	expectEvent := func(expected string) {
+		t.Helper()
+		select {
+		case event := <-recorder.Events:
+			if expected == "" {
+				t.Errorf("unexpected event %q", event)
+			} else if !strings.HasPrefix(event, expected) {
+				t.Errorf("expected event %q, but got %q", expected, event)
+			}
+		default:
+			if expected != "" {
+				t.Errorf("expected event %q", expected)
+			}
+		}
+	}
+	expectStuck := func(reason string, expected float64) {
+		t.Helper()
+		if value := testutil.ToFloat64(stuckLoadBalancersMetric.WithLabelValues(reason)); value != expected {
+			t.Errorf("expected %v stuck load balancers for %s, but got %v", expected, reason, value)
+		}
+	}
+
+	poolErr := fmt.Errorf("pool exhausted")
+	tracker.failed(service, stepIPAllocation, poolErr)
+	now = now.Add(5 * time.Minute)
+	tracker.failed(service, stepIPAllocation, poolErr)
+	expectEvent("")
+	expectStuck(stuckReasonIPPoolExhausted, 0)
+
+	// deadline exceeded
+	now = now.Add(5 * time.Minute)
+	tracker.failed(service, stepIPAllocation, poolErr)
+	expectEvent("Warning LoadBalancerStuck Load balancer not provisioned after 10m0s (ip-pool-exhausted), blocked at allocating the IP address: pool exhausted")
+	expectStuck(stuckReasonIPPoolExhausted, 1)
+
+	// alerted once per reason
+	now = now.Add(5 * time.Minute)
+	tracker.failed(service, stepIPAllocation, poolErr)
+	expectEvent("")
+	tracker.failed(service, stepLookup, &unreachableError{fmt.Errorf("ServiceUnavailable")})
+	expectEvent("Warning LoadBalancerStuck Load balancer not provisioned after 15m0s (nsx-unreachable), blocked at looking up the NSX-T objects")
+	expectStuck(stuckReasonIPPoolExhausted, 0)
+	expectStuck(stuckReasonNSXUnreachable, 1)
+
+	// provisioned
+	tracker.done(service)
+	expectStuck(stuckReasonNSXUnreachable, 0)
+	if len(tracker.pending) != 0 {
+		t.Errorf("unexpected pending provisionings %v", tracker.pending)
+	}
+
+	// failed deletions are not tracked
+	deleted := service.DeepCopy()
+	deleted.Spec.Ports = nil
+	tracker.failed(deleted, stepCleanup, poolErr)
+	if len(tracker.pending) != 0 {
+		t.Errorf("unexpected pending provisionings %v", tracker.pending)
+	}
+
+	// a disabled tracker does nothing
+	var disabled *provisioningTracker
+	disabled.failed(service, stepIPAllocation, poolErr)
+	disabled.done(service)
+}
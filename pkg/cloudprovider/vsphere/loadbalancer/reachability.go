@@ -0,0 +1,108 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+)
+
+// VIPUnreachableReason is the reason of the event emitted on a Service whose
+// VIP does not accept TCP connections after the load balancer was provisioned
+const VIPUnreachableReason = "VIPUnreachable"
+
+var (
+	// reachabilityCheckAttempts is the number of connect attempts to a VIP
+	// port, as the virtual server may take a while to be realized on the edges
+	reachabilityCheckAttempts = 6
+	// reachabilityCheckInterval is the delay between two connect attempts
+	reachabilityCheckInterval = 5 * time.Second
+	// reachabilityCheckTimeout is the timeout of a connect attempt
+	reachabilityCheckTimeout = 5 * time.Second
+)
+
+// reachabilityChecker connects to the VIP ports of the provisioned load
+// balancers and emits an event on the Service for each unreachable one,
+// catching a missing route advertisement of the tier1 gateway or a firewall
+// dropping the traffic at provisioning time.
+type reachabilityChecker struct {
+	recorder record.EventRecorder
+}
+
+// check runs the connect checks of the TCP ports of the Service in the
+// background.
+func (c *reachabilityChecker) check(service *corev1.Service, status *corev1.LoadBalancerStatus) {
+	if c == nil || status == nil {
+		return
+	}
+	var addresses []string
+	for _, ingress := range status.Ingress {
+		if ingress.IP == "" {
+			continue
+		}
+		for _, port := range service.Spec.Ports {
+			if port.Protocol == corev1.ProtocolTCP {
+				addresses = append(addresses, net.JoinHostPort(ingress.IP, strconv.Itoa(int(port.Port))))
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return
+	}
+
+	service = service.DeepCopy()
+	go c.checkAddresses(service, addresses)
+}
+
+// checkAddresses connects to the addresses and emits one event listing the
+// unreachable ones.
+func (c *reachabilityChecker) checkAddresses(service *corev1.Service, addresses []string) {
+	var unreachable []string
+	for _, address := range addresses {
+		if err := connect(address); err != nil {
+			klog.Warningf("%s/%s: VIP %s is not reachable: %v", service.Namespace, service.Name, address, err)
+			unreachable = append(unreachable, address)
+		}
+	}
+	if len(unreachable) != 0 {
+		c.recorder.Eventf(service, corev1.EventTypeWarning, VIPUnreachableReason,
+			"Load balancer VIP is not reachable on %s, check the route advertisement of the tier1 gateway and the firewall rules",
+			strings.Join(unreachable, ", "))
+	}
+}
+
+// connect opens a TCP connection to address, retrying until the last attempt.
+func connect(address string) error {
+	var err error
+	for attempt := 0; attempt < reachabilityCheckAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(reachabilityCheckInterval)
+		}
+		var conn net.Conn
+		conn, err = net.DialTimeout("tcp", address, reachabilityCheckTimeout)
+		if err == nil {
+			return conn.Close()
+		}
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReachabilityCheck(t *testing.T) {
+	reachabilityCheckAttempts = 2
+	reachabilityCheckInterval = 10 * time.Millisecond
+	defer func() {
+		reachabilityCheckAttempts = 6
+		reachabilityCheckInterval = 5 * time.Second
+	}()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer listener.Close()
+	openPort := int32(listener.Addr().(*net.TCPAddr).Port)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	closedPort := int32(closed.Addr().(*net.TCPAddr).Port)
+	closed.Close()
+
+	status := &corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "127.0.0.1"}}}
+	newService := func(ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		service       *corev1.Service
+		expectedEvent string
+	}{
+		{
+			name:    "reachable",
+			service: newService(corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: openPort}),
+		},
+		{
+			name: "unreachable",
+			service: newService(
+				corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: openPort},
+				corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: closedPort},
+			),
+			expectedEvent: "Warning VIPUnreachable Load balancer VIP is not reachable on " + closed.Addr().String(),
+		},
+		{
+			name:    "udp ports are not checked",
+			service: newService(corev1.ServicePort{Protocol: corev1.ProtocolUDP, Port: closedPort}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			checker := &reachabilityChecker{recorder: recorder}
+
+			checker.check(testCase.service, status)
+
+			if testCase.expectedEvent == "" {
+				select {
+				case event := <-recorder.Events:
+					t.Errorf("unexpected event %q", event)
+				case <-time.After(100 * time.Millisecond):
+				}
+				return
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.HasPrefix(event, testCase.expectedEvent) {
+					t.Errorf("expected event %q, but got %q", testCase.expectedEvent, event)
+				}
+			case <-time.After(10 * time.Second):
+				t.Error("expected an event")
+			}
+		})
+	}
+
+	// a disabled check does nothing
+	var disabled *reachabilityChecker
+	disabled.check(newService(corev1.ServicePort{Protocol: corev1.ProtocolTCP, Port: closedPort}), status)
+}
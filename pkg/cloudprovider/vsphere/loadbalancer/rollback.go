@@ -0,0 +1,68 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// rollbackStep undoes the creation of an element in NSX-T
+type rollbackStep struct {
+	name string
+	undo func() error
+}
+
+// checkpoint records how to undo the creation of an element, so that it is
+// deleted again if processing the load balancer fails later on
+func (s *state) checkpoint(name string, undo func() error) {
+	s.rollbackSteps = append(s.rollbackSteps, rollbackStep{name: name, undo: undo})
+}
+
+// Rollback deletes the elements created since the state was created, in the
+// reverse order of their creation. Elements which existed before are kept.
+// Undoing is idempotent: elements already gone are ignored and undone steps
+// are dropped, so Rollback may be called again after a failure. Elements it
+// fails to delete are tagged like any other element of the service and are
+// reused or garbage collected by the next reconcile.
+// Named IP address allocations are kept, see releaseResources.
+func (s *state) Rollback() error {
+	var errs []error
+	var failed []rollbackStep
+	for i := len(s.rollbackSteps) - 1; i >= 0; i-- {
+		step := s.rollbackSteps[i]
+		s.CtxInfof("rolling back %s", step.name)
+		if err := step.undo(); err != nil {
+			errs = append(errs, fmt.Errorf("rolling back %s failed: %w", step.name, err))
+			failed = append([]rollbackStep{step}, failed...)
+		}
+	}
+	s.rollbackSteps = failed
+	return utilerrors.NewAggregate(errs)
+}
+
+// without returns list without item
+func without[T comparable](list []T, item T) []T {
+	result := list[:0]
+	for _, elem := range list {
+		if elem != item {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rollbackAccess finds no elements for the service and fails to create
+// virtual servers. Methods not needed by Process panic.
+type rollbackAccess struct {
+	NSXTAccess
+	lbServiceExists  bool
+	deleteFailures   int
+	calls            []string
+	virtualServerErr error
+}
+
+func (a *rollbackAccess) FindExternalIPAddressForObject(string, string, types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	return nil, nil, nil
+}
+
+func (a *rollbackAccess) FindVirtualServers(string, types.NamespacedName) ([]*model.LBVirtualServer, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) FindPools(string, types.NamespacedName) ([]*model.LBPool, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) FindTCPMonitorProfiles(string, types.NamespacedName) ([]*model.LBTcpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) FindUDPMonitorProfiles(string, types.NamespacedName) ([]*model.LBUdpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) FindHTTPMonitorProfiles(string, types.NamespacedName) ([]*model.LBHttpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) FindHTTPAppProfiles(string, types.NamespacedName) ([]*model.LBHttpProfile, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) CreateTCPMonitorProfile(string, types.NamespacedName, string, Mapping) (*model.LBTcpMonitorProfile, error) {
+	a.calls = append(a.calls, "create monitor")
+	return &model.LBTcpMonitorProfile{Id: strptr("monitor1"), Path: strptr("/monitor1")}, nil
+}
+
+func (a *rollbackAccess) DeleteTCPMonitorProfile(id string) error {
+	a.calls = append(a.calls, "delete "+id)
+	if a.deleteFailures > 0 {
+		a.deleteFailures--
+		return fmt.Errorf("nsx-t unavailable")
+	}
+	return nil
+}
+
+func (a *rollbackAccess) CreatePool(string, types.NamespacedName, string, Mapping, []model.LBPoolMember, []string, bool) (*model.LBPool, error) {
+	a.calls = append(a.calls, "create pool")
+	return &model.LBPool{Id: strptr("pool1"), Path: strptr("/pool1")}, nil
+}
+
+func (a *rollbackAccess) DeletePool(id string) error {
+	a.calls = append(a.calls, "delete "+id)
+	return nil
+}
+
+func (a *rollbackAccess) AllocateExternalIPAddress(string, string, types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	a.calls = append(a.calls, "allocate ip")
+	return &model.IpAddressAllocation{Id: strptr("ip1")}, strptr("10.0.0.10"), nil
+}
+
+func (a *rollbackAccess) ReleaseExternalIPAddress(_ string, id string) error {
+	a.calls = append(a.calls, "release "+id)
+	return nil
+}
+
+func (a *rollbackAccess) FindLoadBalancerService(string, string) (*model.LBService, error) {
+	if a.lbServiceExists {
+		return &model.LBService{Id: strptr("lbs1"), Path: strptr("/lbs1")}, nil
+	}
+	return nil, nil
+}
+
+func (a *rollbackAccess) CreateLoadBalancerService(string) (*model.LBService, error) {
+	a.calls = append(a.calls, "create lbs")
+	a.lbServiceExists = true
+	return &model.LBService{Id: strptr("lbs1"), Path: strptr("/lbs1")}, nil
+}
+
+func (a *rollbackAccess) ListVirtualServers(string) ([]*model.LBVirtualServer, error) {
+	return nil, nil
+}
+
+func (a *rollbackAccess) DeleteLoadBalancerService(id string) error {
+	a.calls = append(a.calls, "delete "+id)
+	a.lbServiceExists = false
+	return nil
+}
+
+func (a *rollbackAccess) GetAppProfilePath(LBClass, corev1.Protocol) (string, error) {
+	return "/profile", nil
+}
+
+func (a *rollbackAccess) CreateVirtualServer(string, types.NamespacedName, string, LBClass, string, Mapping, string, string, *string, *model.LBClientSslProfileBinding) (*model.LBVirtualServer, error) {
+	a.calls = append(a.calls, "create virtual server")
+	return nil, a.virtualServerErr
+}
+
+func newRollbackState(access *rollbackAccess, managed bool) *state {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+		},
+	}
+	lbService := newLbService(access, "")
+	lbService.managed = managed
+	return newState(lbService, "cluster1", service, nil)
+}
+
+func TestRollbackOnVirtualServerFailure(t *testing.T) {
+	access := &rollbackAccess{virtualServerErr: fmt.Errorf("virtual server quota exceeded")}
+	s := newRollbackState(access, true)
+
+	if err := s.Process(&loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}}); err == nil {
+		t.Fatalf("expected Process to fail")
+	}
+	if err := s.Rollback(); err != nil {
+		t.Fatalf("unexpected rollback error: %s", err)
+	}
+
+	expected := []string{
+		"create monitor", "create pool", "allocate ip", "create lbs", "create virtual server",
+		"delete lbs1", "release ip1", "delete pool1", "delete monitor1",
+	}
+	if !reflect.DeepEqual(access.calls, expected) {
+		t.Errorf("expected calls %v, but found %v", expected, access.calls)
+	}
+	if len(s.pools) != 0 || len(s.tcpMonitors) != 0 || s.ipAddress != nil {
+		t.Errorf("expected rolled back elements to be dropped from state")
+	}
+	status, err := s.Finish()
+	if err != nil || len(status.Ingress) != 0 {
+		t.Errorf("expected empty status, but found %v, %v", status, err)
+	}
+}
+
+func TestRollbackIsRetrySafe(t *testing.T) {
+	access := &rollbackAccess{lbServiceExists: true, deleteFailures: 1, virtualServerErr: fmt.Errorf("failed")}
+	s := newRollbackState(access, true)
+
+	if err := s.Process(&loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}}); err == nil {
+		t.Fatalf("expected Process to fail")
+	}
+	if err := s.Rollback(); err == nil {
+		t.Fatalf("expected rollback to fail")
+	}
+	if len(s.rollbackSteps) != 1 || s.rollbackSteps[0].name != "LbTcpMonitor monitor1" {
+		t.Fatalf("expected only the failed step to be kept, but found %v", s.rollbackSteps)
+	}
+
+	access.calls = nil
+	if err := s.Rollback(); err != nil {
+		t.Fatalf("unexpected rollback error: %s", err)
+	}
+	if expected := []string{"delete monitor1"}; !reflect.DeepEqual(access.calls, expected) {
+		t.Errorf("expected calls %v, but found %v", expected, access.calls)
+	}
+	if len(s.rollbackSteps) != 0 {
+		t.Errorf("expected no rollback steps left")
+	}
+}
+
+func TestWithout(t *testing.T) {
+	a, b, c := strptr("a"), strptr("b"), strptr("c")
+	if list := without([]*string{a, b, c}, b); !reflect.DeepEqual(list, []*string{a, c}) {
+		t.Errorf("unexpected list %v", list)
+	}
+}
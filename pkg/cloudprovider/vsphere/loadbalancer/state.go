@@ -19,9 +19,13 @@ package loadbalancer
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	klog "k8s.io/klog/v2"
@@ -29,6 +33,7 @@ import (
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer/config"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 type state struct {
@@ -37,12 +42,40 @@ type state struct {
 	objectName     types.NamespacedName
 	service        *corev1.Service
 	nodes          []*corev1.Node
+	mappings       []Mapping
 	servers        []*model.LBVirtualServer
 	pools          []*model.LBPool
 	tcpMonitors    []*model.LBTcpMonitorProfile
+	udpMonitors    []*model.LBUdpMonitorProfile
+	httpMonitors   []*model.LBHttpMonitorProfile
 	ipAddressAlloc *model.IpAddressAllocation
 	ipAddress      *string
+	ipAllocName    string
 	class          *loadBalancerClass
+	rollbackSteps  []rollbackStep
+	// step is the provisioning step being processed, reported when the
+	// provisioning is stuck
+	step string
+	// lbName is the name of the load balancer, the base of the display
+	// names of the NSX-T objects created
+	lbName string
+	// dualStack is set if the service gets IPv6 virtual servers in addition
+	// to the IPv4 ones, with the address allocated from the IPv6 pool
+	dualStack        bool
+	ipv6AddressAlloc *model.IpAddressAllocation
+	ipv6Address      *string
+	// udpHealthCheck is the health check of the UDP ports, nil if they are
+	// not health checked
+	udpHealthCheck *udpHealthCheck
+	// httpProfileOptions are the settings of the HTTP application profile of
+	// the TCP ports, nil if they use the TCP application profile of the class
+	httpProfileOptions *httpProfileOptions
+	httpProfiles       []*model.LBHttpProfile
+	// httpProfile is the HTTP application profile of the TCP virtual servers
+	httpProfile *model.LBHttpProfile
+	// tls is the TLS termination of the TCP virtual servers, nil if they do
+	// not terminate TLS
+	tls *tlsTermination
 }
 
 func newState(lbService *lbService, clusterName string, service *corev1.Service, nodes []*corev1.Node) *state {
@@ -52,21 +85,37 @@ func newState(lbService *lbService, clusterName string, service *corev1.Service,
 		service:     service,
 		nodes:       nodes,
 		objectName:  namespacedNameFromService(service),
+		lbName:      lbService.loadBalancerName(clusterName, service),
+		ipAllocName: ipAllocationName(service),
 	}
 }
 
 // CxtInfof logs with object name context
 func (s *state) CtxInfof(format string, args ...interface{}) {
-	klog.V(2).Infof("%s: %s", s.objectName, fmt.Sprintf(format, args...))
+	logging.V(logging.LoadBalancer, 2).Infof("%s: %s", s.objectName, fmt.Sprintf(format, args...))
 }
 
 // Process processes a load balancer and ensures that all needed objects are existing
 func (s *state) Process(class *loadBalancerClass) error {
 	var err error
-	s.ipAddressAlloc, s.ipAddress, err = s.access.FindExternalIPAddressForObject(class.ipPool.Identifier, s.clusterName, s.objectName)
+	s.step = stepMapping
+	mappings, err := newMappings(s.service)
 	if err != nil {
 		return err
 	}
+	var unsupported []Mapping
+	s.mappings, unsupported = splitSupportedMappings(mappings)
+	for _, mapping := range unsupported {
+		klog.Warningf("%s: ignoring port %s, NSX-T load balancers only support TCP and UDP", s.objectName, mapping)
+	}
+	if len(s.mappings) == 0 && len(unsupported) > 0 {
+		return fmt.Errorf("no port of the service has a protocol supported by NSX-T load balancers, only TCP and UDP are supported")
+	}
+	s.udpHealthCheck, err = newUDPHealthCheck(s.service)
+	if err != nil {
+		return err
+	}
+	s.step = stepLookup
 	s.servers, err = s.access.FindVirtualServers(s.clusterName, s.objectName)
 	if err != nil {
 		return err
@@ -79,61 +128,231 @@ func (s *state) Process(class *loadBalancerClass) error {
 	if err != nil {
 		return err
 	}
+	s.udpMonitors, err = s.access.FindUDPMonitorProfiles(s.clusterName, s.objectName)
+	if err != nil {
+		return err
+	}
+	s.httpMonitors, err = s.access.FindHTTPMonitorProfiles(s.clusterName, s.objectName)
+	if err != nil {
+		return err
+	}
+	s.httpProfiles, err = s.access.FindHTTPAppProfiles(s.clusterName, s.objectName)
+	if err != nil {
+		return err
+	}
 	if len(s.servers) > 0 {
 		className := getTag(s.servers[0].Tags, ScopeLBClass)
-		ipPoolID := getTag(s.servers[0].Tags, ScopeIPPoolID)
-		if class.className != className || class.ipPool.Identifier != ipPoolID {
+		if zone := getTag(s.servers[0].Tags, ScopeZone); zone != "" {
+			// the virtual servers stay in their zone
+			class = class.zoneClass(zone)
+		}
+		ipPoolID := class.ipPool.Identifier
+		ipv6PoolID := class.ipv6Pool.Identifier
+		for _, server := range s.servers {
+			if serverIPFamily(server) == corev1.IPv6Protocol {
+				ipv6PoolID = getTag(server.Tags, ScopeIPPoolID)
+			} else {
+				ipPoolID = getTag(server.Tags, ScopeIPPoolID)
+			}
+		}
+		if class.className != className || class.ipPool.Identifier != ipPoolID || class.ipv6Pool.Identifier != ipv6PoolID {
 			classConfig := &config.LoadBalancerClassConfig{
-				IPPoolID: ipPoolID,
+				IPPoolID:   ipPoolID,
+				IPv6PoolID: ipv6PoolID,
 			}
 			class, err = newLBClass(className, classConfig, class, nil)
 			if err != nil {
 				return err
 			}
 		}
+	} else if len(s.mappings) > 0 {
+		zone, err := s.serviceZone(class)
+		if err != nil {
+			return err
+		}
+		if zone != "" {
+			class = class.zoneClass(zone)
+		}
 	}
 	s.class = class
+	s.tls, err = newTLSTermination(s.service, class)
+	if err != nil {
+		return err
+	}
+	s.httpProfileOptions, err = newHTTPProfileOptions(s.service, class, s.tls != nil)
+	if err != nil {
+		return err
+	}
+	// the addresses are looked up in the pools of the class the virtual
+	// servers are placed in, which may be the ones of a zone
+	s.ipAddressAlloc, s.ipAddress, err = s.findIPAddress(class.ipPool.Identifier)
+	if err != nil {
+		return err
+	}
+	if class.ipv6Pool.Identifier != "" {
+		s.ipv6AddressAlloc, s.ipv6Address, err = s.findIPAddress(class.ipv6Pool.Identifier)
+		if err != nil {
+			return err
+		}
+	}
+	if len(s.mappings) > 0 {
+		s.dualStack, err = s.wantsDualStack()
+		if err != nil {
+			return err
+		}
+	}
+	if s.httpProfileOptions != nil && hasTCPMapping(s.mappings) {
+		s.step = stepHTTPProfile
+		s.httpProfile, err = s.getHTTPProfile()
+		if err != nil {
+			return err
+		}
+	}
 
-	for _, servicePort := range s.service.Spec.Ports {
-		mapping := NewMapping(servicePort)
-
-		monitor, err := s.getTCPMonitor(mapping)
+	for _, mapping := range s.mappings {
+		activeMonitorPaths, err := s.getMonitorPaths(mapping)
 		if err != nil {
 			return err
 		}
-		pool, err := s.getPool(mapping, monitor)
+		s.step = stepPool
+		pool, err := s.getPool(mapping, activeMonitorPaths)
 		if err != nil {
 			return err
 		}
-		_, err = s.getVirtualServer(mapping, pool.Path)
+		s.step = stepVirtualServer
+		_, err = s.getVirtualServer(mapping, corev1.IPv4Protocol, pool.Path)
 		if err != nil {
 			return err
 		}
+		if s.dualStack {
+			_, err = s.getVirtualServer(mapping, corev1.IPv6Protocol, pool.Path)
+			if err != nil {
+				return err
+			}
+		}
 	}
+	s.step = stepCleanup
 	validPoolPaths, err := s.deleteOrphanVirtualServers()
 	if err != nil {
 		return err
 	}
+	err = s.deleteOrphanHTTPProfiles()
+	if err != nil {
+		return err
+	}
 	s.CtxInfof("validPoolPaths: %v", validPoolPaths.List())
-	validTCPMonitorPaths, err := s.deleteOrphanPools(validPoolPaths)
+	validMonitorPaths, err := s.deleteOrphanPools(validPoolPaths)
 	if err != nil {
 		return err
 	}
-	s.CtxInfof("validTCPMonitorPaths: %v", validTCPMonitorPaths.List())
-	err = s.deleteOrphanTCPMonitors(validTCPMonitorPaths)
+	s.CtxInfof("validMonitorPaths: %v", validMonitorPaths.List())
+	err = s.deleteOrphanTCPMonitors(validMonitorPaths)
+	if err != nil {
+		return err
+	}
+	err = s.deleteOrphanUDPMonitors(validMonitorPaths)
+	if err != nil {
+		return err
+	}
+	err = s.deleteOrphanHTTPMonitors(validMonitorPaths)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// findIPAddress looks up the IP address allocation of the service in the IP pool
+func (s *state) findIPAddress(ipPoolID string) (*model.IpAddressAllocation, *string, error) {
+	if s.ipAllocName != "" {
+		return s.findNamedIPAddress(ipPoolID)
+	}
+	return s.access.FindExternalIPAddressForObject(ipPoolID, s.clusterName, s.objectName)
+}
+
+// findNamedIPAddress finds the named IP address allocation and binds it to
+// the service. It fails if the allocation is bound to another service which
+// still names it, and is not found for a deleted service it is not bound to.
+func (s *state) findNamedIPAddress(ipPoolID string) (*model.IpAddressAllocation, *string, error) {
+	ipAddressAlloc, ipAddress, err := s.access.FindNamedExternalIPAddress(ipPoolID, s.clusterName, s.ipAllocName)
+	if err != nil || ipAddressAlloc == nil {
+		return nil, nil, err
+	}
+	owner := getTag(ipAddressAlloc.Tags, ScopeService)
+	if owner == s.objectName.String() {
+		return ipAddressAlloc, ipAddress, nil
+	}
+	if owner != "" && s.namesIPAllocation(parseNamespacedName(owner)) {
+		if len(s.mappings) == 0 {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("IP address allocation %s is bound to service %s", s.ipAllocName, owner)
+	}
+	if len(s.mappings) == 0 {
+		return nil, nil, nil
+	}
+	// the allocation is held, or its service is gone or names another one
+	ipAddressAlloc.Tags = boundTags(ipAddressAlloc.Tags, s.objectName)
+	err = s.access.UpdateExternalIPAddress(ipPoolID, ipAddressAlloc)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.CtxInfof("bound IP address allocation %s previously bound to %q", s.ipAllocName, owner)
+	return ipAddressAlloc, ipAddress, nil
+}
+
+// namesIPAllocation checks whether the service exists and still names the IP
+// address allocation of the state. Without services lister, it is assumed to.
+func (s *state) namesIPAllocation(objectName types.NamespacedName) bool {
+	if s.servicesLister == nil {
+		return true
+	}
+	service, err := s.servicesLister.Services(objectName.Namespace).Get(objectName.Name)
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return service.Spec.Type == corev1.ServiceTypeLoadBalancer && ipAllocationName(service) == s.ipAllocName
+}
+
+// ipAllocationName returns the name of the IP address allocation of the
+// service, empty if it has none
+func ipAllocationName(service *corev1.Service) string {
+	return strings.TrimSpace(service.GetAnnotations()[IPAllocationNameAnnotation])
+}
+
+// wantsDualStack returns true if the service requires both IP families, or
+// prefers them in a dual-stack cluster and the class has an IPv6 pool
+func (s *state) wantsDualStack() (bool, error) {
+	policy := s.service.Spec.IPFamilyPolicy
+	if policy == nil || *policy == corev1.IPFamilyPolicySingleStack {
+		return false, nil
+	}
+	if s.class.ipv6Pool.Identifier == "" {
+		if *policy == corev1.IPFamilyPolicyRequireDualStack {
+			return false, fmt.Errorf("load balancer class %s has no IPv6 pool for dual-stack services", s.class.className)
+		}
+		return false, nil
+	}
+	return *policy == corev1.IPFamilyPolicyRequireDualStack || len(s.service.Spec.IPFamilies) > 1, nil
+}
+
+// serverIPFamily returns the IP family of the virtual server, servers without
+// IP family tag are IPv4 ones
+func serverIPFamily(server *model.LBVirtualServer) corev1.IPFamily {
+	if getTag(server.Tags, ScopeIPFamily) == string(corev1.IPv6Protocol) {
+		return corev1.IPv6Protocol
+	}
+	return corev1.IPv4Protocol
+}
+
 func (s *state) deleteOrphanVirtualServers() (sets.String, error) {
 	validPoolPaths := sets.String{}
 	for _, server := range s.servers {
 		found := false
-		for _, servicePort := range s.service.Spec.Ports {
-			mapping := NewMapping(servicePort)
-			if mapping.MatchVirtualServer(server) {
+		for _, mapping := range s.mappings {
+			if mapping.MatchVirtualServer(server) && (s.dualStack || serverIPFamily(server) == corev1.IPv4Protocol) {
 				if server.PoolPath != nil {
 					validPoolPaths.Insert(*server.PoolPath)
 				}
@@ -152,14 +371,13 @@ func (s *state) deleteOrphanVirtualServers() (sets.String, error) {
 }
 
 func (s *state) deleteOrphanPools(validPoolPaths sets.String) (sets.String, error) {
-	validTCPMonitorPaths := sets.String{}
+	validMonitorPaths := sets.String{}
 	for _, pool := range s.pools {
 		found := false
-		for _, servicePort := range s.service.Spec.Ports {
-			mapping := NewMapping(servicePort)
+		for _, mapping := range s.mappings {
 			if mapping.MatchPool(pool) && validPoolPaths.Has(*pool.Path) {
 				if len(pool.ActiveMonitorPaths) > 0 {
-					validTCPMonitorPaths.Insert(pool.ActiveMonitorPaths...)
+					validMonitorPaths.Insert(pool.ActiveMonitorPaths...)
 				}
 				found = true
 				break
@@ -172,15 +390,14 @@ func (s *state) deleteOrphanPools(validPoolPaths sets.String) (sets.String, erro
 			}
 		}
 	}
-	return validTCPMonitorPaths, nil
+	return validMonitorPaths, nil
 }
 
-func (s *state) deleteOrphanTCPMonitors(validTCPMonitorPaths sets.String) error {
+func (s *state) deleteOrphanTCPMonitors(validMonitorPaths sets.String) error {
 	for _, monitor := range s.tcpMonitors {
 		found := false
-		for _, servicePort := range s.service.Spec.Ports {
-			mapping := NewMapping(servicePort)
-			if mapping.MatchTCPMonitor(monitor) && monitor.Path != nil && validTCPMonitorPaths.Has(*monitor.Path) {
+		for _, mapping := range s.mappings {
+			if mapping.MatchTCPMonitor(monitor) && monitor.Path != nil && validMonitorPaths.Has(*monitor.Path) {
 				found = true
 				break
 			}
@@ -195,23 +412,105 @@ func (s *state) deleteOrphanTCPMonitors(validTCPMonitorPaths sets.String) error
 	return nil
 }
 
-func (s *state) allocateResources() (allocated bool, err error) {
-	if s.ipAddressAlloc == nil {
-		ipPoolID := s.class.ipPool.Identifier
-		s.ipAddressAlloc, s.ipAddress, err = s.access.AllocateExternalIPAddress(ipPoolID, s.clusterName, s.objectName)
+func (s *state) deleteOrphanUDPMonitors(validMonitorPaths sets.String) error {
+	for _, monitor := range s.udpMonitors {
+		found := false
+		for _, mapping := range s.mappings {
+			if mapping.MatchUDPMonitor(monitor) && monitor.Path != nil && validMonitorPaths.Has(*monitor.Path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := s.deleteUDPMonitor(monitor)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *state) deleteOrphanHTTPMonitors(validMonitorPaths sets.String) error {
+	for _, monitor := range s.httpMonitors {
+		if monitor.Path != nil && validMonitorPaths.Has(*monitor.Path) {
+			continue
+		}
+		err := s.deleteHTTPMonitor(monitor)
 		if err != nil {
-			return
+			return err
 		}
-		allocated = true
-		s.CtxInfof("allocated IP address %s from pool %s", *s.ipAddress, ipPoolID)
 	}
-	return
+	return nil
 }
 
-func (s *state) releaseResources() error {
+// deleteOrphanHTTPProfiles deletes the HTTP application profiles no longer
+// used by the virtual servers of the service
+func (s *state) deleteOrphanHTTPProfiles() error {
+	for _, profile := range s.httpProfiles {
+		if s.httpProfile != nil && *profile.Id == *s.httpProfile.Id {
+			continue
+		}
+		err := s.deleteHTTPProfile(profile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocateResources allocates the IP address of the IP family if the
+// service has none yet
+func (s *state) allocateResources(family corev1.IPFamily) error {
+	var err error
+	if family == corev1.IPv6Protocol {
+		if s.ipv6AddressAlloc == nil {
+			s.ipv6AddressAlloc, s.ipv6Address, err = s.allocateIPAddress(s.class.ipv6Pool.Identifier)
+			if err == nil {
+				s.checkpoint(fmt.Sprintf("IP address allocation %s", *s.ipv6AddressAlloc.Id), func() error { return s.releaseIPv6Resources(false) })
+			}
+		}
+		return err
+	}
+	if s.ipAddressAlloc == nil {
+		s.ipAddressAlloc, s.ipAddress, err = s.allocateIPAddress(s.class.ipPool.Identifier)
+		if err == nil {
+			s.checkpoint(fmt.Sprintf("IP address allocation %s", *s.ipAddressAlloc.Id), func() error { return s.releaseIPv4Resources(false) })
+		}
+	}
+	return err
+}
+
+func (s *state) allocateIPAddress(ipPoolID string) (*model.IpAddressAllocation, *string, error) {
+	var ipAddressAlloc *model.IpAddressAllocation
+	var ipAddress *string
+	var err error
+	if s.ipAllocName != "" {
+		ipAddressAlloc, ipAddress, err = s.access.AllocateNamedExternalIPAddress(ipPoolID, s.clusterName, s.ipAllocName, s.objectName)
+	} else {
+		ipAddressAlloc, ipAddress, err = s.access.AllocateExternalIPAddress(ipPoolID, s.clusterName, s.objectName)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	s.CtxInfof("allocated IP address %s from pool %s", *ipAddress, ipPoolID)
+	return ipAddressAlloc, ipAddress, nil
+}
+
+// releaseResources releases the IP address allocations. With hold, the
+// allocation of a deleted service is held for the release quarantine instead,
+// the cleanup releases it once the quarantine has expired.
+func (s *state) releaseResources(hold bool) error {
+	err := s.releaseIPv4Resources(hold)
+	if err != nil {
+		return err
+	}
+	return s.releaseIPv6Resources(hold)
+}
+
+func (s *state) releaseIPv4Resources(hold bool) error {
 	if s.ipAddressAlloc != nil {
-		ipPoolID := s.class.ipPool.Identifier
-		err := s.access.ReleaseExternalIPAddress(ipPoolID, *s.ipAddressAlloc.Id)
+		err := s.releaseIPAddress(s.ipAddressAlloc, corev1.IPv4Protocol, hold)
 		if err != nil {
 			return err
 		}
@@ -221,24 +520,85 @@ func (s *state) releaseResources() error {
 	return nil
 }
 
-func (s *state) loggedReleaseResources() {
-	ipAddress := s.ipAddress
-	err := s.releaseResources()
-	if err != nil {
-		s.CtxInfof("failed to release IP address %s to pool %s", *ipAddress, s.class.ipPool.Identifier)
+// releaseIPv6Resources releases the IPv6 address allocation only, as when a
+// service is no longer dual-stack
+func (s *state) releaseIPv6Resources(hold bool) error {
+	if s.ipv6AddressAlloc != nil {
+		err := s.releaseIPAddress(s.ipv6AddressAlloc, corev1.IPv6Protocol, hold)
+		if err != nil {
+			return err
+		}
+		s.ipv6AddressAlloc = nil
+		s.ipv6Address = nil
 	}
+	return nil
+}
+
+func (s *state) releaseIPAddress(ipAddressAlloc *model.IpAddressAllocation, family corev1.IPFamily, hold bool) error {
+	ipPoolID := s.class.ipPool.Identifier
+	if family == corev1.IPv6Protocol {
+		ipPoolID = s.class.ipv6Pool.Identifier
+	}
+	if s.ipAllocName != "" {
+		// named allocations are held to be reused by a recreated service,
+		// the cleanup releases them once no service names them
+		s.CtxInfof("keeping IP address allocation %s", s.ipAllocName)
+		return s.access.HoldExternalIPAddress(ipPoolID, ipAddressAlloc, time.Now())
+	}
+	if hold && s.releaseQuarantine > 0 {
+		s.CtxInfof("holding IP address allocation %s for %s", *ipAddressAlloc.Id, s.releaseQuarantine)
+		return s.access.HoldExternalIPAddress(ipPoolID, ipAddressAlloc, time.Now())
+	}
+	return s.access.ReleaseExternalIPAddress(ipPoolID, *ipAddressAlloc.Id)
 }
 
 // Finish performs cleanup after Process
 func (s *state) Finish() (*corev1.LoadBalancerStatus, error) {
 	if len(s.service.Spec.Ports) == 0 {
-		err := s.releaseResources()
+		err := s.releaseResources(true)
 		if err != nil {
 			return nil, err
 		}
 		return nil, nil
 	}
-	return newLoadBalancerStatus(s.ipAddress), nil
+	if !s.dualStack {
+		err := s.releaseIPv6Resources(true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newServiceLoadBalancerStatus(s.service, s.ipAddress, s.ipv6Address), nil
+}
+
+// getMonitorPaths returns the paths of the monitor profiles health checking
+// the pool of the mapping, creating or updating them as needed
+func (s *state) getMonitorPaths(mapping Mapping) ([]string, error) {
+	if isLocalTrafficPolicy(s.service) && s.service.Spec.HealthCheckNodePort != 0 {
+		// the nodes without endpoints of the service are down
+		s.step = stepHTTPMonitor
+		httpMonitor, err := s.getHTTPMonitor()
+		if err != nil {
+			return nil, err
+		}
+		return []string{*httpMonitor.Path}, nil
+	}
+	s.step = stepTCPMonitor
+	tcpMonitor, err := s.getTCPMonitor(mapping)
+	if err != nil {
+		return nil, err
+	}
+	if tcpMonitor != nil {
+		return []string{*tcpMonitor.Path}, nil
+	}
+	s.step = stepUDPMonitor
+	udpMonitor, err := s.getUDPMonitor(mapping)
+	if err != nil {
+		return nil, err
+	}
+	if udpMonitor != nil {
+		return []string{*udpMonitor.Path}, nil
+	}
+	return nil, nil
 }
 
 func (s *state) getTCPMonitor(mapping Mapping) (*model.LBTcpMonitorProfile, error) {
@@ -258,19 +618,23 @@ func (s *state) getTCPMonitor(mapping Mapping) (*model.LBTcpMonitorProfile, erro
 }
 
 func (s *state) createTCPMonitor(mapping Mapping) (*model.LBTcpMonitorProfile, error) {
-	monitor, err := s.access.CreateTCPMonitorProfile(s.clusterName, s.objectName, mapping)
+	monitor, err := s.access.CreateTCPMonitorProfile(s.clusterName, s.objectName, s.lbName, mapping)
 	if err == nil {
 		s.CtxInfof("created LbTcpMonitor %s for %s", *monitor.Id, mapping)
 		s.tcpMonitors = append(s.tcpMonitors, monitor)
+		s.checkpoint(fmt.Sprintf("LbTcpMonitor %s", *monitor.Id), func() error {
+			s.tcpMonitors = without(s.tcpMonitors, monitor)
+			return s.access.DeleteTCPMonitorProfile(*monitor.Id)
+		})
 	}
 	return monitor, err
 }
 
 func (s *state) updateTCPMonitor(monitor *model.LBTcpMonitorProfile, mapping Mapping) error {
-	if monitor.MonitorPort != nil && *monitor.MonitorPort == int64(mapping.NodePort) {
+	if monitor.MonitorPort != nil && *monitor.MonitorPort == int64(mapping.MemberPort) {
 		return nil
 	}
-	monitor.MonitorPort = int64ptr(int64(mapping.NodePort))
+	monitor.MonitorPort = int64ptr(int64(mapping.MemberPort))
 	s.CtxInfof("updating LbTcpMonitor %s for %s", *monitor.Id, mapping)
 	return s.access.UpdateTCPMonitorProfile(monitor)
 }
@@ -280,11 +644,155 @@ func (s *state) deleteTCPMonitor(monitor *model.LBTcpMonitorProfile) error {
 	return s.access.DeleteTCPMonitorProfile(*monitor.Id)
 }
 
-func (s *state) getPool(mapping Mapping, monitor *model.LBTcpMonitorProfile) (*model.LBPool, error) {
-	var activeMonitorPaths []string
-	if monitor != nil {
-		activeMonitorPaths = []string{*monitor.Path}
+func (s *state) getUDPMonitor(mapping Mapping) (*model.LBUdpMonitorProfile, error) {
+	if mapping.Protocol == corev1.ProtocolUDP && s.udpHealthCheck != nil {
+		for _, m := range s.udpMonitors {
+			if mapping.MatchUDPMonitor(m) {
+				err := s.updateUDPMonitor(m, mapping)
+				if err != nil {
+					return nil, err
+				}
+				return m, nil
+			}
+		}
+		return s.createUDPMonitor(mapping)
+	}
+	return nil, nil
+}
+
+func (s *state) createUDPMonitor(mapping Mapping) (*model.LBUdpMonitorProfile, error) {
+	monitor, err := s.access.CreateUDPMonitorProfile(s.clusterName, s.objectName, s.lbName, mapping, *s.udpHealthCheck)
+	if err == nil {
+		s.CtxInfof("created LbUdpMonitor %s for %s", *monitor.Id, mapping)
+		s.udpMonitors = append(s.udpMonitors, monitor)
+		s.checkpoint(fmt.Sprintf("LbUdpMonitor %s", *monitor.Id), func() error {
+			s.udpMonitors = without(s.udpMonitors, monitor)
+			return s.access.DeleteUDPMonitorProfile(*monitor.Id)
+		})
 	}
+	return monitor, err
+}
+
+func (s *state) updateUDPMonitor(monitor *model.LBUdpMonitorProfile, mapping Mapping) error {
+	if monitor.MonitorPort != nil && *monitor.MonitorPort == int64(mapping.MemberPort) &&
+		monitor.Send != nil && *monitor.Send == s.udpHealthCheck.send &&
+		monitor.Receive != nil && *monitor.Receive == s.udpHealthCheck.receive {
+		return nil
+	}
+	monitor.MonitorPort = int64ptr(int64(mapping.MemberPort))
+	monitor.Send = strptr(s.udpHealthCheck.send)
+	monitor.Receive = strptr(s.udpHealthCheck.receive)
+	s.CtxInfof("updating LbUdpMonitor %s for %s", *monitor.Id, mapping)
+	return s.access.UpdateUDPMonitorProfile(monitor)
+}
+
+func (s *state) deleteUDPMonitor(monitor *model.LBUdpMonitorProfile) error {
+	s.CtxInfof("deleting LbUdpMonitor %s for %s", *monitor.Id, getTag(monitor.Tags, ScopePort))
+	return s.access.DeleteUDPMonitorProfile(*monitor.Id)
+}
+
+// getHTTPMonitor returns the monitor profile probing the health check node
+// port, which is shared by the pools of the service
+func (s *state) getHTTPMonitor() (*model.LBHttpMonitorProfile, error) {
+	if len(s.httpMonitors) > 0 {
+		monitor := s.httpMonitors[0]
+		err := s.updateHTTPMonitor(monitor)
+		if err != nil {
+			return nil, err
+		}
+		return monitor, nil
+	}
+	return s.createHTTPMonitor()
+}
+
+func (s *state) createHTTPMonitor() (*model.LBHttpMonitorProfile, error) {
+	port := int(s.service.Spec.HealthCheckNodePort)
+	monitor, err := s.access.CreateHTTPMonitorProfile(s.clusterName, s.objectName, s.lbName, port)
+	if err == nil {
+		s.CtxInfof("created LbHttpMonitor %s for health check node port %d", *monitor.Id, port)
+		s.httpMonitors = append(s.httpMonitors, monitor)
+		s.checkpoint(fmt.Sprintf("LbHttpMonitor %s", *monitor.Id), func() error {
+			s.httpMonitors = without(s.httpMonitors, monitor)
+			return s.access.DeleteHTTPMonitorProfile(*monitor.Id)
+		})
+	}
+	return monitor, err
+}
+
+func (s *state) updateHTTPMonitor(monitor *model.LBHttpMonitorProfile) error {
+	port := int64(s.service.Spec.HealthCheckNodePort)
+	if monitor.MonitorPort != nil && *monitor.MonitorPort == port {
+		return nil
+	}
+	monitor.MonitorPort = int64ptr(port)
+	s.CtxInfof("updating LbHttpMonitor %s for health check node port %d", *monitor.Id, port)
+	return s.access.UpdateHTTPMonitorProfile(monitor)
+}
+
+func (s *state) deleteHTTPMonitor(monitor *model.LBHttpMonitorProfile) error {
+	s.CtxInfof("deleting LbHttpMonitor %s", *monitor.Id)
+	return s.access.DeleteHTTPMonitorProfile(*monitor.Id)
+}
+
+// getHTTPProfile returns the HTTP application profile inserting the
+// X-Forwarded-For header or terminating TLS, which is shared by the TCP
+// virtual servers
+func (s *state) getHTTPProfile() (*model.LBHttpProfile, error) {
+	if len(s.httpProfiles) > 0 {
+		profile := s.httpProfiles[0]
+		err := s.updateHTTPProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		return profile, nil
+	}
+	return s.createHTTPProfile()
+}
+
+func (s *state) createHTTPProfile() (*model.LBHttpProfile, error) {
+	profile, err := s.access.CreateHTTPAppProfile(s.clusterName, s.objectName, s.lbName, *s.httpProfileOptions)
+	if err == nil {
+		s.CtxInfof("created LBHttpProfile %s with X-Forwarded-For %s", *profile.Id, s.httpProfileOptions.xForwardedFor)
+		s.httpProfiles = append(s.httpProfiles, profile)
+		s.checkpoint(fmt.Sprintf("LBHttpProfile %s", *profile.Id), func() error {
+			s.httpProfiles = without(s.httpProfiles, profile)
+			return s.access.DeleteHTTPAppProfile(*profile.Id)
+		})
+	}
+	return profile, err
+}
+
+func (s *state) updateHTTPProfile(profile *model.LBHttpProfile) error {
+	if s.httpProfileOptions.matchHTTPProfile(profile) {
+		return nil
+	}
+	profile.XForwardedFor = s.httpProfileOptions.xForwardedForPtr()
+	profile.ServerKeepAlive = boolptr(s.httpProfileOptions.serverKeepAlive)
+	s.CtxInfof("updating LBHttpProfile %s with X-Forwarded-For %s", *profile.Id, s.httpProfileOptions.xForwardedFor)
+	return s.access.UpdateHTTPAppProfile(profile)
+}
+
+func (s *state) deleteHTTPProfile(profile *model.LBHttpProfile) error {
+	s.CtxInfof("deleting LBHttpProfile %s", *profile.Id)
+	return s.access.DeleteHTTPAppProfile(*profile.Id)
+}
+
+// appProfilePath returns the path of the application profile of the virtual
+// servers of the mapping. Without X-Forwarded-For, only the ports terminating
+// TLS use the HTTP application profile.
+func (s *state) appProfilePath(mapping Mapping) (string, error) {
+	if mapping.Protocol == corev1.ProtocolTCP && s.httpProfile != nil &&
+		(s.httpProfileOptions.xForwardedFor != "" || s.tls.terminates(mapping)) {
+		return *s.httpProfile.Path, nil
+	}
+	path, err := s.access.GetAppProfilePath(s.class, mapping.Protocol)
+	if err != nil {
+		return "", errors.Wrapf(err, "Lookup of application profile failed for %s", mapping.Protocol)
+	}
+	return path, nil
+}
+
+func (s *state) getPool(mapping Mapping, activeMonitorPaths []string) (*model.LBPool, error) {
 	for _, pool := range s.pools {
 		if mapping.MatchPool(pool) {
 			err := s.updatePool(pool, mapping, activeMonitorPaths)
@@ -296,21 +804,28 @@ func (s *state) getPool(mapping Mapping, monitor *model.LBTcpMonitorProfile) (*m
 
 func (s *state) createPool(mapping Mapping, activeMonitorIds []string) (*model.LBPool, error) {
 	members, _ := s.updatedPoolMembers(nil)
-	pool, err := s.access.CreatePool(s.clusterName, s.objectName, mapping, members, activeMonitorIds)
+	pool, err := s.access.CreatePool(s.clusterName, s.objectName, s.lbName, mapping, members, activeMonitorIds, isLocalTrafficPolicy(s.service))
 	if err == nil {
 		s.CtxInfof("created LbPool %s for %s", *pool.Id, mapping)
 		s.pools = append(s.pools, pool)
+		s.checkpoint(fmt.Sprintf("LbPool %s", *pool.Id), func() error {
+			s.pools = without(s.pools, pool)
+			return s.access.DeletePool(*pool.Id)
+		})
 	}
 	return pool, err
 }
 
 func (s *state) UpdatePoolMembers() error {
+	mappings, err := newMappings(s.service)
+	if err != nil {
+		return err
+	}
 	pools, err := s.access.FindPools(s.clusterName, s.objectName)
 	if err != nil {
 		return err
 	}
-	for _, servicePort := range s.service.Spec.Ports {
-		mapping := NewMapping(servicePort)
+	for _, mapping := range mappings {
 		for _, pool := range pools {
 			if mapping.MatchPool(pool) {
 				err = s.updatePool(pool, mapping, pool.ActiveMonitorPaths)
@@ -325,6 +840,14 @@ func (s *state) UpdatePoolMembers() error {
 
 func (s *state) updatePool(pool *model.LBPool, mapping Mapping, activeMonitorPaths []string) error {
 	newMembers, modified := s.updatedPoolMembers(pool.Members)
+	snatTranslation, err := s.access.SnatTranslation(isLocalTrafficPolicy(s.service))
+	if err != nil {
+		return err
+	}
+	if snatTranslationType(pool.SnatTranslation) != snatTranslationType(snatTranslation) {
+		pool.SnatTranslation = snatTranslation
+		modified = true
+	}
 	if modified || !reflect.DeepEqual(activeMonitorPaths, pool.ActiveMonitorPaths) {
 		pool.Members = newMembers
 		pool.ActiveMonitorPaths = activeMonitorPaths
@@ -337,51 +860,105 @@ func (s *state) updatePool(pool *model.LBPool, mapping Mapping, activeMonitorPat
 	return nil
 }
 
+// updatedPoolMembers returns the members of the nodes, and whether they differ
+// from oldMembers. The members have the internal IP addresses of the nodes of
+// the primary IP family of the service, if the nodes have one. The members of draining nodes are gracefully disabled
+// instead of being removed, and enabled again once the nodes are schedulable.
 func (s *state) updatedPoolMembers(oldMembers []model.LBPoolMember) ([]model.LBPoolMember, bool) {
 	modified := false
-	nodeIPAddresses := collectNodeInternalAddresses(s.nodes)
+	// the service controller only leaves out the nodes with the exclusion
+	// label, not the ones with the annotation
+	nodes := filterNodes(s.nodes, func(node *corev1.Node) bool { return !isExcludedNode(node) })
+	if endpointNodes := s.localEndpointNodes(); endpointNodes != nil {
+		nodes = filterNodes(nodes, func(node *corev1.Node) bool { return endpointNodes.Has(node.Name) })
+	}
+	family := serviceIPFamily(s.service)
+	nodeIPAddresses := collectNodeInternalAddresses(nodes, family)
+	drainingIPAddresses := collectNodeInternalAddresses(filterNodes(nodes, isDrainingNode), family)
+	excludedIPAddresses := s.excludedNodeAddresses()
 	newMembers := []model.LBPoolMember{}
+	oldIPAddresses := sets.NewString()
 	for _, member := range oldMembers {
 		if member.IpAddress == nil {
 			continue
 		}
-		if _, ok := nodeIPAddresses[*member.IpAddress]; ok {
-			newMembers = append(newMembers, member)
-		} else {
+		oldIPAddresses.Insert(*member.IpAddress)
+		_, draining := drainingIPAddresses[*member.IpAddress]
+		if _, ok := nodeIPAddresses[*member.IpAddress]; !ok {
+			if _, ok := excludedIPAddresses[*member.IpAddress]; !ok {
+				modified = true
+				continue
+			}
+			draining = true
+		}
+		if adminState := memberAdminState(member, draining); adminState != nil {
+			s.CtxInfof("setting admin state of pool member %s to %s", *member.IpAddress, *adminState)
+			member.AdminState = adminState
 			modified = true
 		}
+		newMembers = append(newMembers, member)
 	}
-	if len(nodeIPAddresses) > len(newMembers) {
-		for nodeIPAddress, nodeName := range nodeIPAddresses {
-			found := false
-			for _, member := range oldMembers {
-				if member.IpAddress != nil && *member.IpAddress == nodeIPAddress {
-					found = true
-					break
-				}
-			}
-			if !found {
-				member := model.LBPoolMember{
-					AdminState:  strptr("ENABLED"),
-					DisplayName: strptr(fmt.Sprintf("%s:%s", s.clusterName, nodeName)),
-					IpAddress:   strptr(nodeIPAddress),
-				}
-				newMembers = append(newMembers, member)
-				modified = true
-			}
+	for nodeIPAddress, nodeName := range nodeIPAddresses {
+		if oldIPAddresses.Has(nodeIPAddress) {
+			continue
+		}
+		adminState := model.LBPoolMember_ADMIN_STATE_ENABLED
+		if _, ok := drainingIPAddresses[nodeIPAddress]; ok {
+			adminState = model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED
+		}
+		member := model.LBPoolMember{
+			AdminState:  strptr(adminState),
+			DisplayName: strptr(fmt.Sprintf("%s:%s", s.clusterName, nodeName)),
+			IpAddress:   strptr(nodeIPAddress),
 		}
+		newMembers = append(newMembers, member)
+		modified = true
 	}
 	return newMembers, modified
 }
 
+// excludedNodeAddresses returns the internal IP addresses of the draining nodes
+// known to the nodes lister. The service controller does not pass the nodes
+// excluded from the load balancers, their members are kept while they exist.
+func (s *state) excludedNodeAddresses() map[string]string {
+	if s.nodesLister == nil {
+		return nil
+	}
+	nodes, err := s.nodesLister.List(labels.Everything())
+	if err != nil {
+		klog.Warningf("%s: listing nodes failed, removing the pool members of excluded nodes: %v", s.objectName, err)
+		return nil
+	}
+	if endpointNodes := s.localEndpointNodes(); endpointNodes != nil {
+		nodes = filterNodes(nodes, func(node *corev1.Node) bool { return endpointNodes.Has(node.Name) })
+	}
+	return collectNodeInternalAddresses(filterNodes(nodes, isDrainingNode), serviceIPFamily(s.service))
+}
+
+// memberAdminState returns the admin state the member must be updated to, nil
+// if it is kept. A member disabled by the administrator is left alone.
+func memberAdminState(member model.LBPoolMember, draining bool) *string {
+	current := model.LBPoolMember_ADMIN_STATE_ENABLED
+	if member.AdminState != nil {
+		current = *member.AdminState
+	}
+	switch {
+	case draining && current == model.LBPoolMember_ADMIN_STATE_ENABLED:
+		return strptr(model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED)
+	case !draining && current == model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED:
+		return strptr(model.LBPoolMember_ADMIN_STATE_ENABLED)
+	}
+	return nil
+}
+
 func (s *state) deletePool(pool *model.LBPool) error {
 	s.CtxInfof("deleting LbPool %s for %s", *pool.Id, getTag(pool.Tags, ScopePort))
 	return s.access.DeletePool(*pool.Id)
 }
 
-func (s *state) getVirtualServer(mapping Mapping, poolPath *string) (*model.LBVirtualServer, error) {
+func (s *state) getVirtualServer(mapping Mapping, family corev1.IPFamily, poolPath *string) (*model.LBVirtualServer, error) {
 	for _, server := range s.servers {
-		if mapping.MatchVirtualServer(server) {
+		if mapping.MatchVirtualServer(server) && serverIPFamily(server) == family {
 			err := s.updateVirtualServer(server, mapping, poolPath)
 			if err != nil {
 				return nil, err
@@ -390,46 +967,83 @@ func (s *state) getVirtualServer(mapping Mapping, poolPath *string) (*model.LBVi
 		}
 	}
 
-	return s.createVirtualServer(mapping, poolPath)
+	return s.createVirtualServer(mapping, family, poolPath)
 }
 
-func (s *state) createVirtualServer(mapping Mapping, poolPath *string) (*model.LBVirtualServer, error) {
-	allocated, err := s.allocateResources()
+func (s *state) createVirtualServer(mapping Mapping, family corev1.IPFamily, poolPath *string) (*model.LBVirtualServer, error) {
+	s.step = stepIPAllocation
+	err := s.allocateResources(family)
 	if err != nil {
 		return nil, err
 	}
+	class, ipAddress := s.class, s.ipAddress
+	if family == corev1.IPv6Protocol {
+		class, ipAddress = s.class.ipv6Class(), s.ipv6Address
+	}
 
-	lbServicePath, err := s.lbService.getOrCreateLoadBalancerService(s.clusterName)
+	s.step = stepLBService
+	lbServicePath, err := s.getOrCreateLoadBalancerService()
 	if err != nil {
 		return nil, errors.Wrapf(err, "get or create LBService failed")
 	}
 
-	applicationProfilePath, err := s.access.GetAppProfilePath(s.class, mapping.Protocol)
+	s.step = stepVirtualServer
+	applicationProfilePath, err := s.appProfilePath(mapping)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Lookup of application profile failed for %s", mapping.Protocol)
+		return nil, err
 	}
 
-	server, err := s.access.CreateVirtualServer(s.clusterName, s.objectName, s.class, *s.ipAddress, mapping,
-		lbServicePath, applicationProfilePath, poolPath)
+	server, err := s.access.CreateVirtualServer(s.clusterName, s.objectName, s.lbName, class, *ipAddress, mapping,
+		lbServicePath, applicationProfilePath, poolPath, s.tls.clientSSLBinding(mapping))
 	if err != nil {
-		if allocated {
-			s.loggedReleaseResources()
-		}
 		return nil, err
 	}
-	s.CtxInfof("created LBVirtualServer %s for %s", *server.Id, mapping)
+	s.CtxInfof("created %s LBVirtualServer %s for %s", family, *server.Id, mapping)
 	s.servers = append(s.servers, server)
+	s.checkpoint(fmt.Sprintf("LBVirtualServer %s", *server.Id), func() error {
+		s.servers = without(s.servers, server)
+		return s.access.DeleteVirtualServer(*server.Id)
+	})
 	return server, nil
 }
 
+// getOrCreateLoadBalancerService returns the path of the LbService of the
+// virtual servers, the one of their zone if they are zonal
+func (s *state) getOrCreateLoadBalancerService() (string, error) {
+	zone := s.class.zone
+	if zone == "" {
+		lbServicePath, created, err := s.lbService.getOrCreateLoadBalancerService(s.clusterName)
+		if err == nil && created {
+			s.checkpoint("LBService", func() error {
+				return s.lbService.removeLoadBalancerServiceIfUnused(s.clusterName)
+			})
+		}
+		return lbServicePath, err
+	}
+	placement, ok := s.class.zones[zone]
+	if !ok {
+		return "", fmt.Errorf("load balancer class %s has no zone %s", s.class.className, zone)
+	}
+	lbServicePath, created, err := s.lbService.getOrCreateZonalLoadBalancerService(s.clusterName, zone, placement.tier1GatewayPath)
+	if err == nil && created {
+		s.checkpoint(fmt.Sprintf("LBService of zone %s", zone), func() error {
+			return s.lbService.removeZonalLoadBalancerServiceIfUnused(s.clusterName, zone)
+		})
+	}
+	return lbServicePath, err
+}
+
 func (s *state) updateVirtualServer(server *model.LBVirtualServer, mapping Mapping, poolPath *string) error {
-	applicationProfilePath, err := s.access.GetAppProfilePath(s.class, mapping.Protocol)
+	applicationProfilePath, err := s.appProfilePath(mapping)
 	if err != nil {
-		return errors.Wrapf(err, "Lookup of application profile failed for %s", mapping.Protocol)
+		return err
 	}
-	if !mapping.MatchNodePort(server) || !safeEquals(server.PoolPath, poolPath) || !safeEquals(server.ApplicationProfilePath, &applicationProfilePath) {
+	clientSSL := s.tls.clientSSLBinding(mapping)
+	if !mapping.MatchMemberPort(server) || !safeEquals(server.PoolPath, poolPath) || !safeEquals(server.ApplicationProfilePath, &applicationProfilePath) ||
+		!matchClientSSLBinding(server, clientSSL) {
 		server.ApplicationProfilePath = strptr(applicationProfilePath)
-		server.DefaultPoolMemberPorts = []string{formatPort(mapping.NodePort)}
+		server.ClientSslProfileBinding = clientSSL
+		server.DefaultPoolMemberPorts = []string{formatPort(mapping.MemberPort)}
 		server.PoolPath = poolPath
 		s.CtxInfof("updating LbVirtualServer %s for %s", *server.Id, mapping)
 		err = s.access.UpdateVirtualServer(server)
@@ -450,5 +1064,8 @@ func (s *state) deleteVirtualServer(server *model.LBVirtualServer) error {
 	if err != nil {
 		return err
 	}
+	if zone := getTag(server.Tags, ScopeZone); zone != "" {
+		return s.lbService.removeZonalLoadBalancerServiceIfUnused(s.clusterName, zone)
+	}
 	return s.lbService.removeLoadBalancerServiceIfUnused(s.clusterName)
 }
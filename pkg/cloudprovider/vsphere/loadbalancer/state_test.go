@@ -0,0 +1,1140 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNamedIPAddressAllocationIsKept(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web",
+			Annotations: map[string]string{
+				IPAllocationNameAnnotation: " web-vip ",
+			},
+		},
+	}
+
+	// without release quarantine, a named allocation is held rather than
+	// released, until the cleanup finds that no service names it
+	access := &releaseAccess{}
+	s := newState(newLbService(access, ""), "cluster1", service, nil)
+	if s.ipAllocName != "web-vip" {
+		t.Fatalf("expected allocation name web-vip, but found %q", s.ipAllocName)
+	}
+
+	s.class = &loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}}
+	s.ipAddressAlloc = &model.IpAddressAllocation{Id: strptr("id1")}
+	s.ipAddress = strptr("10.0.0.10")
+	if err := s.releaseResources(true); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s.ipAddressAlloc != nil || s.ipAddress != nil {
+		t.Errorf("expected allocation to be dropped from state")
+	}
+	if len(access.released) != 0 || !reflect.DeepEqual(access.held, []string{"id1"}) {
+		t.Errorf("expected the named allocation to be held, but found released %v and held %v", access.released, access.held)
+	}
+}
+
+// namedAccess finds the named IP address allocation of allocation.
+type namedAccess struct {
+	NSXTAccess
+	allocation *model.IpAddressAllocation
+	updated    int
+}
+
+func (a *namedAccess) FindNamedExternalIPAddress(_ string, _ string, allocationName string) (*model.IpAddressAllocation, *string, error) {
+	if a.allocation == nil || getTag(a.allocation.Tags, ScopeIPAllocationName) != allocationName {
+		return nil, nil, nil
+	}
+	return a.allocation, a.allocation.AllocationIp, nil
+}
+
+func (a *namedAccess) UpdateExternalIPAddress(_ string, allocation *model.IpAddressAllocation) error {
+	a.allocation = allocation
+	a.updated++
+	return nil
+}
+
+func TestNamedIPAddressAllocationBinding(t *testing.T) {
+	namedService := func(name, allocationName string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        name,
+				Annotations: map[string]string{IPAllocationNameAnnotation: allocationName},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+			},
+		}
+	}
+	allocation := func(tags ...model.Tag) *model.IpAddressAllocation {
+		return &model.IpAddressAllocation{
+			Id:           strptr("id1"),
+			AllocationIp: strptr("10.0.0.10"),
+			Tags:         append([]model.Tag{clusterTag("cluster1"), ipAllocationNameTag("vip")}, tags...),
+		}
+	}
+	owner := types.NamespacedName{Namespace: "default", Name: "web"}
+
+	testCases := []struct {
+		name          string
+		services      []*corev1.Service
+		allocation    *model.IpAddressAllocation
+		deleted       bool
+		expectedFound bool
+		expectedBound bool
+		expectedError bool
+	}{
+		{
+			name:          "bound to the service",
+			allocation:    allocation(serviceTag(types.NamespacedName{Namespace: "default", Name: "api"})),
+			expectedFound: true,
+		},
+		{
+			name:          "bound to a live service",
+			services:      []*corev1.Service{namedService("web", "vip")},
+			allocation:    allocation(serviceTag(owner)),
+			expectedError: true,
+		},
+		{
+			name:       "bound to a live service on delete",
+			services:   []*corev1.Service{namedService("web", "vip")},
+			allocation: allocation(serviceTag(owner)),
+			deleted:    true,
+		},
+		{
+			name:          "bound to a deleted service",
+			allocation:    allocation(serviceTag(owner)),
+			expectedFound: true,
+			expectedBound: true,
+		},
+		{
+			name:          "bound to a service naming another allocation",
+			services:      []*corev1.Service{namedService("web", "other")},
+			allocation:    allocation(serviceTag(owner)),
+			expectedFound: true,
+			expectedBound: true,
+		},
+		{
+			name:          "held",
+			allocation:    allocation(newTag(ScopeReleased, "2026-10-15T08:00:00Z")),
+			expectedFound: true,
+			expectedBound: true,
+		},
+		{
+			name:       "held on delete",
+			allocation: allocation(newTag(ScopeReleased, "2026-10-15T08:00:00Z")),
+			deleted:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, service := range testCase.services {
+				if err := indexer.Add(service); err != nil {
+					t.Fatal(err)
+				}
+			}
+			access := &namedAccess{allocation: testCase.allocation}
+			lbService := newLbService(access, "")
+			lbService.servicesLister = corelisters.NewServiceLister(indexer)
+			service := namedService("api", "vip")
+			if testCase.deleted {
+				service.Spec.Ports = nil
+			}
+			s := newState(lbService, "cluster1", service, nil)
+			s.mappings, _ = newMappings(service)
+
+			found, ipAddress, err := s.findIPAddress("pool")
+			if testCase.expectedError {
+				if err == nil {
+					t.Fatalf("expected the allocation bound to %s to be refused", owner)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (found != nil) != testCase.expectedFound {
+				t.Fatalf("expected allocation found %t, but found %v", testCase.expectedFound, found)
+			}
+			if found != nil && *ipAddress != "10.0.0.10" {
+				t.Errorf("expected IP address 10.0.0.10, but found %s", *ipAddress)
+			}
+			if (access.updated > 0) != testCase.expectedBound {
+				t.Errorf("expected allocation bound %t, but it was updated %d times", testCase.expectedBound, access.updated)
+			}
+			if testCase.expectedBound {
+				if tag := getTag(access.allocation.Tags, ScopeService); tag != "default/api" {
+					t.Errorf("expected allocation bound to default/api, but found %q", tag)
+				}
+				if tag := getTag(access.allocation.Tags, ScopeReleased); tag != "" {
+					t.Errorf("expected the release time tag to be removed, but found %q", tag)
+				}
+			}
+		})
+	}
+}
+
+func TestReleasedIPAddressAllocationIsHeld(t *testing.T) {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+	class := &loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}}
+
+	testCases := []struct {
+		quarantine       time.Duration
+		expectedReleased []string
+		expectedHeld     []string
+	}{
+		{quarantine: 0, expectedReleased: []string{"id1"}},
+		{quarantine: time.Hour, expectedHeld: []string{"id1"}},
+	}
+	for _, testCase := range testCases {
+		quarantine := testCase.quarantine
+		access := &releaseAccess{}
+		lbService := newLbService(access, "")
+		lbService.releaseQuarantine = quarantine
+		s := newState(lbService, "cluster1", service, nil)
+		s.class = class
+		s.ipAddressAlloc = &model.IpAddressAllocation{Id: strptr("id1")}
+		s.ipAddress = strptr("10.0.0.10")
+
+		if _, err := s.Finish(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(access.released, testCase.expectedReleased) || !reflect.DeepEqual(access.held, testCase.expectedHeld) {
+			t.Errorf("quarantine %s: unexpected released %v and held %v allocations", quarantine, access.released, access.held)
+		}
+		if s.ipAddressAlloc != nil || s.ipAddress != nil {
+			t.Errorf("quarantine %s: expected allocation to be dropped from state", quarantine)
+		}
+	}
+}
+
+// dualStackAccess finds no elements for the service and allocates the
+// addresses per IP pool. Methods not needed by Process panic.
+type dualStackAccess struct {
+	NSXTAccess
+	addresses   map[string]string
+	servers     []*model.LBVirtualServer
+	udpMonitors []*model.LBUdpMonitorProfile
+	// httpMonitors and snatDisabledPools record the monitors probing the
+	// health check node port and the pools created without SNAT
+	httpMonitors      []*model.LBHttpMonitorProfile
+	snatDisabledPools int
+	// httpProfiles are the HTTP application profiles of the service, the
+	// deleted ones are recorded in deletedHTTPProfiles
+	httpProfiles        []*model.LBHttpProfile
+	deletedHTTPProfiles []string
+	// zonalLBServices are the LbServices created per zone on the T1 gateway
+	// of the zone
+	zonalLBServices map[string]string
+}
+
+func (a *dualStackAccess) FindExternalIPAddressForObject(string, string, types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	return nil, nil, nil
+}
+
+func (a *dualStackAccess) FindVirtualServers(string, types.NamespacedName) ([]*model.LBVirtualServer, error) {
+	return nil, nil
+}
+
+func (a *dualStackAccess) FindPools(string, types.NamespacedName) ([]*model.LBPool, error) {
+	return nil, nil
+}
+
+func (a *dualStackAccess) FindTCPMonitorProfiles(string, types.NamespacedName) ([]*model.LBTcpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *dualStackAccess) CreateTCPMonitorProfile(_ string, _ types.NamespacedName, _ string, mapping Mapping) (*model.LBTcpMonitorProfile, error) {
+	return &model.LBTcpMonitorProfile{Id: strptr("monitor1"), Path: strptr("/monitor1"), Tags: []model.Tag{portTag(mapping)}}, nil
+}
+
+func (a *dualStackAccess) FindUDPMonitorProfiles(string, types.NamespacedName) ([]*model.LBUdpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *dualStackAccess) CreateUDPMonitorProfile(_ string, _ types.NamespacedName, _ string, mapping Mapping, healthCheck udpHealthCheck) (*model.LBUdpMonitorProfile, error) {
+	monitor := &model.LBUdpMonitorProfile{
+		Id:      strptr("udp-monitor1"),
+		Path:    strptr("/udp-monitor1"),
+		Send:    strptr(healthCheck.send),
+		Receive: strptr(healthCheck.receive),
+		Tags:    []model.Tag{portTag(mapping)},
+	}
+	a.udpMonitors = append(a.udpMonitors, monitor)
+	return monitor, nil
+}
+
+func (a *dualStackAccess) FindHTTPMonitorProfiles(string, types.NamespacedName) ([]*model.LBHttpMonitorProfile, error) {
+	return nil, nil
+}
+
+func (a *dualStackAccess) CreateHTTPMonitorProfile(_ string, _ types.NamespacedName, _ string, healthCheckNodePort int) (*model.LBHttpMonitorProfile, error) {
+	monitor := &model.LBHttpMonitorProfile{
+		Id:          strptr("http-monitor1"),
+		Path:        strptr("/http-monitor1"),
+		MonitorPort: int64ptr(int64(healthCheckNodePort)),
+	}
+	a.httpMonitors = append(a.httpMonitors, monitor)
+	return monitor, nil
+}
+
+func (a *dualStackAccess) FindHTTPAppProfiles(string, types.NamespacedName) ([]*model.LBHttpProfile, error) {
+	return a.httpProfiles, nil
+}
+
+func (a *dualStackAccess) CreateHTTPAppProfile(_ string, _ types.NamespacedName, _ string, options httpProfileOptions) (*model.LBHttpProfile, error) {
+	profile := &model.LBHttpProfile{
+		Id:              strptr("http-profile1"),
+		Path:            strptr("/http-profile1"),
+		XForwardedFor:   options.xForwardedForPtr(),
+		ServerKeepAlive: boolptr(options.serverKeepAlive),
+	}
+	a.httpProfiles = append(a.httpProfiles, profile)
+	return profile, nil
+}
+
+func (a *dualStackAccess) UpdateHTTPAppProfile(*model.LBHttpProfile) error {
+	return nil
+}
+
+func (a *dualStackAccess) DeleteHTTPAppProfile(id string) error {
+	a.deletedHTTPProfiles = append(a.deletedHTTPProfiles, id)
+	return nil
+}
+
+func (a *dualStackAccess) CreatePool(_ string, _ types.NamespacedName, _ string, mapping Mapping, _ []model.LBPoolMember, activeMonitorPaths []string, preserveClientIP bool) (*model.LBPool, error) {
+	if preserveClientIP {
+		a.snatDisabledPools++
+	}
+	return &model.LBPool{Id: strptr("pool1"), Path: strptr("/pool1"), ActiveMonitorPaths: activeMonitorPaths, Tags: []model.Tag{portTag(mapping)}}, nil
+}
+
+func (a *dualStackAccess) AllocateExternalIPAddress(ipPoolID string, _ string, _ types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	return &model.IpAddressAllocation{Id: strptr(ipPoolID + "-ip")}, strptr(a.addresses[ipPoolID]), nil
+}
+
+func (a *dualStackAccess) FindLoadBalancerService(string, string) (*model.LBService, error) {
+	return &model.LBService{Id: strptr("lbs1"), Path: strptr("/lbs1")}, nil
+}
+
+func (a *dualStackAccess) FindZonalLoadBalancerService(_ string, zone string) (*model.LBService, error) {
+	if _, ok := a.zonalLBServices[zone]; !ok {
+		return nil, nil
+	}
+	return &model.LBService{Id: strptr("lbs-" + zone), Path: strptr("/lbs-" + zone)}, nil
+}
+
+func (a *dualStackAccess) CreateZonalLoadBalancerService(_ string, zone, tier1GatewayPath string) (*model.LBService, error) {
+	if a.zonalLBServices == nil {
+		a.zonalLBServices = map[string]string{}
+	}
+	a.zonalLBServices[zone] = tier1GatewayPath
+	return &model.LBService{Id: strptr("lbs-" + zone), Path: strptr("/lbs-" + zone)}, nil
+}
+
+func (a *dualStackAccess) GetAppProfilePath(LBClass, corev1.Protocol) (string, error) {
+	return "/profile", nil
+}
+
+func (a *dualStackAccess) CreateVirtualServer(_ string, _ types.NamespacedName, _ string, class LBClass, ipAddress string, mapping Mapping, lbServicePath string, applicationProfilePath string, poolPath *string, clientSSL *model.LBClientSslProfileBinding) (*model.LBVirtualServer, error) {
+	server := &model.LBVirtualServer{
+		LbServicePath:           strptr(lbServicePath),
+		Id:                      strptr(fmt.Sprintf("server%d", len(a.servers)+1)),
+		IpAddress:               strptr(ipAddress),
+		PoolPath:                poolPath,
+		Ports:                   []string{formatPort(mapping.SourcePort)},
+		Tags:                    append(class.Tags(), portTag(mapping)),
+		ApplicationProfilePath:  strptr(applicationProfilePath),
+		ClientSslProfileBinding: clientSSL,
+	}
+	a.servers = append(a.servers, server)
+	return server, nil
+}
+
+func TestDualStackVirtualServers(t *testing.T) {
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	dualStackClass := &loadBalancerClass{
+		className: "default",
+		ipPool:    Reference{Identifier: "pool"},
+		ipv6Pool:  Reference{Identifier: "pool6"},
+	}
+
+	testCases := []struct {
+		name        string
+		policy      *corev1.IPFamilyPolicy
+		families    []corev1.IPFamily
+		class       *loadBalancerClass
+		expectedIPs []string
+		expectedErr bool
+	}{
+		{
+			name:        "single stack",
+			class:       dualStackClass,
+			expectedIPs: []string{"10.0.0.10"},
+		},
+		{
+			name:        "require dual stack",
+			policy:      &requireDualStack,
+			families:    []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			class:       dualStackClass,
+			expectedIPs: []string{"fd00::10", "10.0.0.10"},
+		},
+		{
+			name:        "prefer dual stack in single stack cluster",
+			policy:      &preferDualStack,
+			families:    []corev1.IPFamily{corev1.IPv4Protocol},
+			class:       dualStackClass,
+			expectedIPs: []string{"10.0.0.10"},
+		},
+		{
+			name:        "require dual stack without IPv6 pool",
+			policy:      &requireDualStack,
+			families:    []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			class:       &loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}},
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				Spec: corev1.ServiceSpec{
+					Ports:          []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+					IPFamilyPolicy: testCase.policy,
+					IPFamilies:     testCase.families,
+				},
+			}
+			access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10", "pool6": "fd00::10"}}
+			s := newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+
+			err := s.Process(testCase.class)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatalf("expected Process to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(access.servers) != len(testCase.expectedIPs) {
+				t.Fatalf("expected %d virtual servers, but found %d", len(testCase.expectedIPs), len(access.servers))
+			}
+			for _, server := range access.servers {
+				if serverIPFamily(server) == corev1.IPv6Protocol && getTag(server.Tags, ScopeIPPoolID) != "pool6" {
+					t.Errorf("expected IPv6 virtual server to be tagged with the IPv6 pool, but found %v", server.Tags)
+				}
+			}
+
+			status, err := s.Finish()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var ips []string
+			for _, ingress := range status.Ingress {
+				ips = append(ips, ingress.IP)
+			}
+			if !reflect.DeepEqual(ips, testCase.expectedIPs) {
+				t.Errorf("expected ingress %v, but found %v", testCase.expectedIPs, ips)
+			}
+		})
+	}
+}
+
+func TestMixedProtocolVirtualServers(t *testing.T) {
+	class := &loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "dns",
+			Annotations: map[string]string{
+				UDPHealthCheckSendAnnotation:    "ping",
+				UDPHealthCheckReceiveAnnotation: "pong",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Protocol: corev1.ProtocolTCP, Port: 53, NodePort: 30053},
+				{Protocol: corev1.ProtocolUDP, Port: 53, NodePort: 30053},
+				{Protocol: corev1.ProtocolSCTP, Port: 9, NodePort: 30009},
+			},
+		},
+	}
+	access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10"}}
+	s := newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+
+	if err := s.Process(class); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var ports []string
+	for _, server := range access.servers {
+		ports = append(ports, getTag(server.Tags, ScopePort))
+	}
+	if expected := []string{"TCP/53", "UDP/53"}; !reflect.DeepEqual(ports, expected) {
+		t.Errorf("expected virtual servers for %v, but found %v", expected, ports)
+	}
+	if len(access.udpMonitors) != 1 || *access.udpMonitors[0].Send != "ping" || *access.udpMonitors[0].Receive != "pong" {
+		t.Fatalf("expected a UDP monitor sending ping and receiving pong, but found %v", access.udpMonitors)
+	}
+	var monitorPaths [][]string
+	for _, pool := range s.pools {
+		monitorPaths = append(monitorPaths, pool.ActiveMonitorPaths)
+	}
+	if expected := [][]string{{"/monitor1"}, {"/udp-monitor1"}}; !reflect.DeepEqual(monitorPaths, expected) {
+		t.Errorf("expected pools monitored by %v, but found %v", expected, monitorPaths)
+	}
+
+	service.Spec.Ports = []corev1.ServicePort{{Protocol: corev1.ProtocolSCTP, Port: 9, NodePort: 30009}}
+	s = newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+	if err := s.Process(class); err == nil {
+		t.Errorf("expected Process to fail for a service without TCP or UDP port")
+	}
+}
+
+func TestDrainingPoolMembers(t *testing.T) {
+	node := func(name, address string, unschedulable, excluded bool) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+			},
+		}
+		if excluded {
+			node.Labels[corev1.LabelNodeExcludeBalancers] = ""
+		}
+		return node
+	}
+	member := func(address, adminState string) model.LBPoolMember {
+		return model.LBPoolMember{AdminState: strptr(adminState), IpAddress: strptr(address)}
+	}
+	adminStates := func(members []model.LBPoolMember) map[string]string {
+		states := map[string]string{}
+		for _, member := range members {
+			states[*member.IpAddress] = *member.AdminState
+		}
+		return states
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	nodes := []*corev1.Node{
+		node("enabled", "10.0.0.1", false, false),
+		node("cordoned", "10.0.0.2", true, false),
+		node("uncordoned", "10.0.0.3", false, false),
+		node("disabled", "10.0.0.4", true, false),
+		node("added", "10.0.0.5", true, false),
+	}
+	excluded := node("excluded", "10.0.0.6", false, true)
+	for _, node := range append(nodes, excluded) {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lbService := &lbService{nodesLister: corelisters.NewNodeLister(indexer)}
+	s := newState(lbService, "cluster1", &corev1.Service{}, nodes)
+
+	members, modified := s.updatedPoolMembers([]model.LBPoolMember{
+		member("10.0.0.1", model.LBPoolMember_ADMIN_STATE_ENABLED),
+		member("10.0.0.2", model.LBPoolMember_ADMIN_STATE_ENABLED),
+		member("10.0.0.3", model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED),
+		member("10.0.0.4", model.LBPoolMember_ADMIN_STATE_DISABLED),
+		member("10.0.0.6", model.LBPoolMember_ADMIN_STATE_ENABLED),
+		member("10.0.0.7", model.LBPoolMember_ADMIN_STATE_ENABLED),
+	})
+	if !modified {
+		t.Error("expected the pool members to be modified")
+	}
+	expected := map[string]string{
+		"10.0.0.1": model.LBPoolMember_ADMIN_STATE_ENABLED,
+		"10.0.0.2": model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED,
+		"10.0.0.3": model.LBPoolMember_ADMIN_STATE_ENABLED,
+		"10.0.0.4": model.LBPoolMember_ADMIN_STATE_DISABLED,
+		"10.0.0.5": model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED,
+		"10.0.0.6": model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED,
+	}
+	if states := adminStates(members); !reflect.DeepEqual(states, expected) {
+		t.Errorf("expected pool members %v, but found %v", expected, states)
+	}
+
+	if _, modified := s.updatedPoolMembers(members); modified {
+		t.Error("expected the drained pool members to be kept")
+	}
+
+	if err := indexer.Delete(excluded); err != nil {
+		t.Fatal(err)
+	}
+	members, _ = s.updatedPoolMembers(members)
+	if _, ok := adminStates(members)["10.0.0.6"]; ok {
+		t.Error("expected the pool member of the deleted node to be removed")
+	}
+}
+
+func TestExcludeNodeAnnotation(t *testing.T) {
+	node := func(name, address, exclude string) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+			},
+		}
+		if exclude != "" {
+			node.Annotations[ExcludeNodeAnnotation] = exclude
+		}
+		return node
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	// the service controller passes the nodes with the annotation
+	nodes := []*corev1.Node{
+		node("worker", "10.0.0.1", ""),
+		node("included", "10.0.0.2", "false"),
+		node("storage", "10.0.0.3", "true"),
+		node("ingress", "10.0.0.4", "true"),
+	}
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lbService := &lbService{nodesLister: corelisters.NewNodeLister(indexer)}
+	s := newState(lbService, "cluster1", &corev1.Service{}, nodes)
+
+	members, _ := s.updatedPoolMembers([]model.LBPoolMember{
+		{AdminState: strptr(model.LBPoolMember_ADMIN_STATE_ENABLED), IpAddress: strptr("10.0.0.3")},
+	})
+	states := map[string]string{}
+	for _, member := range members {
+		states[*member.IpAddress] = *member.AdminState
+	}
+	expected := map[string]string{
+		"10.0.0.1": model.LBPoolMember_ADMIN_STATE_ENABLED,
+		"10.0.0.2": model.LBPoolMember_ADMIN_STATE_ENABLED,
+		"10.0.0.3": model.LBPoolMember_ADMIN_STATE_GRACEFUL_DISABLED,
+	}
+	if !reflect.DeepEqual(states, expected) {
+		t.Errorf("expected pool members %v, but found %v", expected, states)
+	}
+}
+
+func TestPoolMemberIPFamily(t *testing.T) {
+	node := func(name string, addresses ...string) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, address := range addresses {
+			node.Status.Addresses = append(node.Status.Addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: address})
+		}
+		return node
+	}
+	nodes := []*corev1.Node{
+		node("dual-stack", "10.0.0.1", "fd00::1"),
+		node("ipv6-first", "fd00::2", "10.0.0.2"),
+		node("ipv4-only", "10.0.0.3"),
+	}
+	memberAddresses := func(members []model.LBPoolMember) []string {
+		var addresses []string
+		for _, member := range members {
+			addresses = append(addresses, *member.IpAddress)
+		}
+		sort.Strings(addresses)
+		return addresses
+	}
+
+	testCases := []struct {
+		name     string
+		families []corev1.IPFamily
+		expected []string
+	}{
+		{name: "unset", expected: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}},
+		{name: "IPv4 primary", families: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}, expected: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}},
+		{name: "IPv6 primary", families: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}, expected: []string{"10.0.0.3", "fd00::1", "fd00::2"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &corev1.Service{Spec: corev1.ServiceSpec{IPFamilies: testCase.families}}
+			s := newState(&lbService{}, "cluster1", service, nodes)
+			members, _ := s.updatedPoolMembers(nil)
+			if addresses := memberAddresses(members); !reflect.DeepEqual(addresses, testCase.expected) {
+				t.Errorf("expected pool members %v, but found %v", testCase.expected, addresses)
+			}
+		})
+	}
+}
+
+func TestLocalTrafficPolicy(t *testing.T) {
+	class := &loadBalancerClass{className: "default", ipPool: Reference{Identifier: "pool"}}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: corev1.ServiceSpec{
+			Ports:                 []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+			HealthCheckNodePort:   32000,
+		},
+	}
+	node := func(name, address string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+			},
+		}
+	}
+	nodes := []*corev1.Node{node("node1", "10.0.0.1"), node("node2", "10.0.0.2"), node("node3", "10.0.0.3")}
+	notReady := false
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	err := indexer.Add(&discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web-abcde",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"192.168.1.1"}, NodeName: strptr("node1")},
+			{Addresses: []string{"192.168.2.1"}, NodeName: strptr("node2"), Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10"}}
+	lbService := newLbService(access, "lbs1")
+	lbService.endpointSlicesLister = discoverylisters.NewEndpointSliceLister(indexer)
+	lbService.endpointSlicesSynced = func() bool { return true }
+	s := newState(lbService, "cluster1", service, nodes)
+
+	if err := s.Process(class); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(access.httpMonitors) != 1 || *access.httpMonitors[0].MonitorPort != 32000 {
+		t.Fatalf("expected an HTTP monitor probing the health check node port, but found %v", access.httpMonitors)
+	}
+	if len(s.pools) != 1 || !reflect.DeepEqual(s.pools[0].ActiveMonitorPaths, []string{"/http-monitor1"}) {
+		t.Errorf("expected the pool to be monitored by the HTTP monitor, but found %v", s.pools)
+	}
+	if access.snatDisabledPools != 1 {
+		t.Errorf("expected the pool to be created without SNAT")
+	}
+
+	members, _ := s.updatedPoolMembers(nil)
+	if len(members) != 1 || *members[0].IpAddress != "10.0.0.1" {
+		t.Errorf("expected only the node with a ready endpoint to be a member, but found %v", members)
+	}
+
+	service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	if members, _ := s.updatedPoolMembers(nil); len(members) != 3 {
+		t.Errorf("expected all nodes to be members with the Cluster policy, but found %v", members)
+	}
+}
+
+func TestXForwardedFor(t *testing.T) {
+	class := &loadBalancerClass{
+		className:     "default",
+		ipPool:        Reference{Identifier: "pool"},
+		xForwardedFor: model.LBHttpProfile_X_FORWARDED_FOR_INSERT,
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "web",
+			Annotations: map[string]string{ServerKeepAliveAnnotation: "true"},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080},
+				{Protocol: corev1.ProtocolUDP, Port: 53, NodePort: 30053},
+			},
+		},
+	}
+	access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10"}}
+	s := newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+
+	if err := s.Process(class); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(access.httpProfiles) != 1 || *access.httpProfiles[0].XForwardedFor != model.LBHttpProfile_X_FORWARDED_FOR_INSERT || !*access.httpProfiles[0].ServerKeepAlive {
+		t.Fatalf("expected an HTTP profile inserting X-Forwarded-For with server keep-alive, but found %v", access.httpProfiles)
+	}
+	var profilePaths []string
+	for _, server := range access.servers {
+		profilePaths = append(profilePaths, *server.ApplicationProfilePath)
+	}
+	if expected := []string{"/http-profile1", "/profile"}; !reflect.DeepEqual(profilePaths, expected) {
+		t.Errorf("expected virtual servers with application profiles %v, but found %v", expected, profilePaths)
+	}
+
+	service.Annotations[XForwardedForAnnotation] = "none"
+	s = newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+	if err := s.Process(class); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"http-profile1"}; !reflect.DeepEqual(access.deletedHTTPProfiles, expected) {
+		t.Errorf("expected HTTP profiles %v to be deleted, but found %v", expected, access.deletedHTTPProfiles)
+	}
+
+	service.Annotations[XForwardedForAnnotation] = "append"
+	s = newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+	if err := s.Process(class); err == nil {
+		t.Errorf("expected Process to fail for an invalid %s annotation", XForwardedForAnnotation)
+	}
+}
+
+func TestTLSTermination(t *testing.T) {
+	class := &loadBalancerClass{
+		className:            "default",
+		ipPool:               Reference{Identifier: "pool"},
+		clientSSLProfilePath: "/infra/lb-client-ssl-profiles/strict",
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web",
+			Annotations: map[string]string{
+				TLSCertificateAnnotation: "web-cert",
+				TLSPortsAnnotation:       "443",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Protocol: corev1.ProtocolTCP, Port: 443, NodePort: 30443},
+				{Protocol: corev1.ProtocolTCP, Port: 22, NodePort: 30022},
+			},
+		},
+	}
+	access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10"}}
+	s := newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+
+	if err := s.Process(class); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(access.httpProfiles) != 1 || access.httpProfiles[0].XForwardedFor != nil {
+		t.Fatalf("expected an HTTP profile without X-Forwarded-For, but found %v", access.httpProfiles)
+	}
+	if len(access.servers) != 2 {
+		t.Fatalf("expected 2 virtual servers, but found %d", len(access.servers))
+	}
+	tls, plain := access.servers[0], access.servers[1]
+	if *tls.ApplicationProfilePath != "/http-profile1" || tls.ClientSslProfileBinding == nil ||
+		*tls.ClientSslProfileBinding.DefaultCertificatePath != "/infra/certificates/web-cert" ||
+		*tls.ClientSslProfileBinding.SslProfilePath != "/infra/lb-client-ssl-profiles/strict" {
+		t.Errorf("expected the virtual server of port 443 to terminate TLS, but found %v", tls)
+	}
+	if *plain.ApplicationProfilePath != "/profile" || plain.ClientSslProfileBinding != nil {
+		t.Errorf("expected the virtual server of port 22 to use the TCP application profile, but found %v", plain)
+	}
+
+	service.Annotations[TLSPortsAnnotation] = "8443"
+	s = newState(newLbService(access, "lbs1"), "cluster1", service, nil)
+	if err := s.Process(class); err == nil {
+		t.Errorf("expected Process to fail for a %s annotation naming no port of the service", TLSPortsAnnotation)
+	}
+}
+
+func TestZonalPlacement(t *testing.T) {
+	class := &loadBalancerClass{
+		className: "default",
+		ipPool:    Reference{Identifier: "pool"},
+		zones: map[string]*loadBalancerZone{
+			"zone-a": {tier1GatewayPath: "/infra/tier-1s/t1-a", ipPool: Reference{Identifier: "pool-a"}},
+			"zone-b": {tier1GatewayPath: "/infra/tier-1s/t1-b", ipPool: Reference{Identifier: "pool-b"}},
+		},
+	}
+	endpoint := func(zone string) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{Addresses: []string{"192.168.1.1"}, Zone: strptr(zone)}
+	}
+
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		endpoints     []discoveryv1.Endpoint
+		expectedPath  string
+		expectedIP    string
+		expectedError bool
+	}{
+		{
+			name:         "endpoints in one zone",
+			endpoints:    []discoveryv1.Endpoint{endpoint("zone-a"), endpoint("zone-a")},
+			expectedPath: "/lbs-zone-a",
+			expectedIP:   "10.1.0.10",
+		},
+		{
+			name:         "endpoints in several zones",
+			endpoints:    []discoveryv1.Endpoint{endpoint("zone-a"), endpoint("zone-b")},
+			expectedPath: "/lbs1",
+			expectedIP:   "10.0.0.10",
+		},
+		{
+			name:         "endpoints in unconfigured zone",
+			endpoints:    []discoveryv1.Endpoint{endpoint("zone-c")},
+			expectedPath: "/lbs1",
+			expectedIP:   "10.0.0.10",
+		},
+		{
+			name:         "zone annotation",
+			annotations:  map[string]string{ZoneAnnotation: "zone-b"},
+			endpoints:    []discoveryv1.Endpoint{endpoint("zone-a")},
+			expectedPath: "/lbs-zone-b",
+			expectedIP:   "10.2.0.10",
+		},
+		{
+			name:          "unknown zone annotation",
+			annotations:   map[string]string{ZoneAnnotation: "zone-c"},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", Annotations: testCase.annotations},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+				},
+			}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			err := indexer.Add(&discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "web-abcde",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+				},
+				Endpoints: testCase.endpoints,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			access := &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10", "pool-a": "10.1.0.10", "pool-b": "10.2.0.10"}}
+			lbService := newLbService(access, "lbs1")
+			lbService.endpointSlicesLister = discoverylisters.NewEndpointSliceLister(indexer)
+			lbService.endpointSlicesSynced = func() bool { return true }
+			s := newState(lbService, "cluster1", service, nil)
+
+			err = s.Process(class)
+			if testCase.expectedError {
+				if err == nil {
+					t.Fatalf("expected Process to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(access.servers) != 1 {
+				t.Fatalf("expected one virtual server, but found %d", len(access.servers))
+			}
+			server := access.servers[0]
+			if *server.LbServicePath != testCase.expectedPath || *server.IpAddress != testCase.expectedIP {
+				t.Errorf("expected virtual server %s on %s, but found %s on %s", testCase.expectedIP, testCase.expectedPath, *server.IpAddress, *server.LbServicePath)
+			}
+			if zone := getTag(server.Tags, ScopeZone); zone != s.class.zone {
+				t.Errorf("expected virtual server tagged with zone %q, but found %q", s.class.zone, zone)
+			}
+			if s.class.zone != "" && access.zonalLBServices[s.class.zone] != class.zones[s.class.zone].tier1GatewayPath {
+				t.Errorf("expected LbService of zone %s on its T1 gateway, but found %v", s.class.zone, access.zonalLBServices)
+			}
+		})
+	}
+}
+
+// zonalAccess keeps the objects created for the service, so that it can be
+// reconciled again and deleted, and finds and releases the address
+// allocations in their IP pool.
+type zonalAccess struct {
+	*dualStackAccess
+	allocations map[string]*model.IpAddressAllocation
+	allocated   int
+	released    []string
+	pools       []*model.LBPool
+	tcpMonitors []*model.LBTcpMonitorProfile
+}
+
+func (a *zonalAccess) FindExternalIPAddressForObject(ipPoolID string, _ string, _ types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	allocation, ok := a.allocations[ipPoolID]
+	if !ok {
+		return nil, nil, nil
+	}
+	return allocation, strptr(a.addresses[ipPoolID]), nil
+}
+
+func (a *zonalAccess) AllocateExternalIPAddress(ipPoolID string, clusterName string, objectName types.NamespacedName) (*model.IpAddressAllocation, *string, error) {
+	allocation, ipAddress, err := a.dualStackAccess.AllocateExternalIPAddress(ipPoolID, clusterName, objectName)
+	if a.allocations == nil {
+		a.allocations = map[string]*model.IpAddressAllocation{}
+	}
+	a.allocations[ipPoolID] = allocation
+	a.allocated++
+	return allocation, ipAddress, err
+}
+
+func (a *zonalAccess) ReleaseExternalIPAddress(ipPoolID string, id string) error {
+	if allocation, ok := a.allocations[ipPoolID]; !ok || *allocation.Id != id {
+		return fmt.Errorf("no allocation %s in IP pool %s", id, ipPoolID)
+	}
+	delete(a.allocations, ipPoolID)
+	a.released = append(a.released, id)
+	return nil
+}
+
+func (a *zonalAccess) FindVirtualServers(string, types.NamespacedName) ([]*model.LBVirtualServer, error) {
+	return append([]*model.LBVirtualServer(nil), a.servers...), nil
+}
+
+func (a *zonalAccess) ListVirtualServers(string) ([]*model.LBVirtualServer, error) {
+	return a.servers, nil
+}
+
+func (a *zonalAccess) UpdateVirtualServer(*model.LBVirtualServer) error {
+	return nil
+}
+
+func (a *zonalAccess) DeleteVirtualServer(id string) error {
+	a.servers = filterByID(a.servers, id, func(server *model.LBVirtualServer) string { return *server.Id })
+	return nil
+}
+
+func (a *zonalAccess) DeleteLoadBalancerService(id string) error {
+	delete(a.zonalLBServices, strings.TrimPrefix(id, "lbs-"))
+	return nil
+}
+
+func (a *zonalAccess) FindPools(string, types.NamespacedName) ([]*model.LBPool, error) {
+	return append([]*model.LBPool(nil), a.pools...), nil
+}
+
+func (a *zonalAccess) CreatePool(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping, members []model.LBPoolMember, activeMonitorPaths []string, preserveClientIP bool) (*model.LBPool, error) {
+	pool, err := a.dualStackAccess.CreatePool(clusterName, objectName, lbName, mapping, members, activeMonitorPaths, preserveClientIP)
+	a.pools = append(a.pools, pool)
+	return pool, err
+}
+
+func (a *zonalAccess) SnatTranslation(bool) (*data.StructValue, error) {
+	return nil, nil
+}
+
+func (a *zonalAccess) UpdatePool(*model.LBPool) error {
+	return nil
+}
+
+func (a *zonalAccess) DeletePool(id string) error {
+	a.pools = filterByID(a.pools, id, func(pool *model.LBPool) string { return *pool.Id })
+	return nil
+}
+
+func (a *zonalAccess) FindTCPMonitorProfiles(string, types.NamespacedName) ([]*model.LBTcpMonitorProfile, error) {
+	return append([]*model.LBTcpMonitorProfile(nil), a.tcpMonitors...), nil
+}
+
+func (a *zonalAccess) CreateTCPMonitorProfile(clusterName string, objectName types.NamespacedName, lbName string, mapping Mapping) (*model.LBTcpMonitorProfile, error) {
+	monitor, err := a.dualStackAccess.CreateTCPMonitorProfile(clusterName, objectName, lbName, mapping)
+	a.tcpMonitors = append(a.tcpMonitors, monitor)
+	return monitor, err
+}
+
+func (a *zonalAccess) UpdateTCPMonitorProfile(*model.LBTcpMonitorProfile) error {
+	return nil
+}
+
+func (a *zonalAccess) DeleteTCPMonitorProfile(id string) error {
+	a.tcpMonitors = filterByID(a.tcpMonitors, id, func(monitor *model.LBTcpMonitorProfile) string { return *monitor.Id })
+	return nil
+}
+
+func filterByID[T any](objects []T, id string, getID func(T) string) []T {
+	var kept []T
+	for _, object := range objects {
+		if getID(object) != id {
+			kept = append(kept, object)
+		}
+	}
+	return kept
+}
+
+func TestZonalAddressIsReusedAndReleased(t *testing.T) {
+	class := &loadBalancerClass{
+		className: "default",
+		ipPool:    Reference{Identifier: "pool"},
+		zones: map[string]*loadBalancerZone{
+			"zone-a": {tier1GatewayPath: "/infra/tier-1s/t1-a", ipPool: Reference{Identifier: "pool-a"}},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "web",
+			Annotations: map[string]string{ZoneAnnotation: "zone-a"},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+		},
+	}
+	access := &zonalAccess{dualStackAccess: &dualStackAccess{addresses: map[string]string{"pool": "10.0.0.10", "pool-a": "10.1.0.10"}}}
+	lbService := newLbService(access, "lbs1")
+	reconcile := func(service *corev1.Service) *corev1.LoadBalancerStatus {
+		s := newState(lbService, "cluster1", service, nil)
+		if err := s.Process(class); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		status, err := s.Finish()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return status
+	}
+
+	for i := 1; i <= 2; i++ {
+		status := reconcile(service)
+		if status == nil || len(status.Ingress) != 1 || status.Ingress[0].IP != "10.1.0.10" {
+			t.Fatalf("reconcile %d: expected the address of zone-a, but found %v", i, status)
+		}
+		if access.allocated != 1 || len(access.servers) != 1 {
+			t.Fatalf("reconcile %d: expected one allocation and one virtual server, but found %d and %d", i, access.allocated, len(access.servers))
+		}
+	}
+
+	deleted := service.DeepCopy()
+	deleted.Spec.Ports = nil
+	reconcile(deleted)
+	if expected := []string{"pool-a-ip"}; !reflect.DeepEqual(access.released, expected) {
+		t.Errorf("expected allocations %v to be released, but found %v", expected, access.released)
+	}
+	if len(access.allocations) != 0 || len(access.servers) != 0 || len(access.pools) != 0 || len(access.tcpMonitors) != 0 {
+		t.Errorf("expected all objects to be deleted, but found allocations %v, servers %v, pools %v and monitors %v",
+			access.allocations, access.servers, access.pools, access.tcpMonitors)
+	}
+	if _, ok := access.zonalLBServices["zone-a"]; ok {
+		t.Errorf("expected the unused LbService of zone-a to be deleted")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -72,10 +73,34 @@ func serviceTag(objectName types.NamespacedName) model.Tag {
 	return newTag(ScopeService, objectName.String())
 }
 
+func zoneTag(zone string) model.Tag {
+	return newTag(ScopeZone, zone)
+}
+
+func ipAllocationNameTag(allocationName string) model.Tag {
+	return newTag(ScopeIPAllocationName, allocationName)
+}
+
+func releasedTag(releasedAt time.Time) model.Tag {
+	return newTag(ScopeReleased, releasedAt.UTC().Format(time.RFC3339))
+}
+
 func portTag(mapping Mapping) model.Tag {
 	return newTag(ScopePort, fmt.Sprintf("%s/%d", mapping.Protocol, mapping.SourcePort))
 }
 
+// boundTags returns the tags of an IP address allocation bound to the
+// service, replacing its service and release time tags
+func boundTags(tags []model.Tag, objectName types.NamespacedName) []model.Tag {
+	result := Tags{}
+	for _, tag := range tags {
+		if *tag.Scope != ScopeService && *tag.Scope != ScopeReleased {
+			result[*tag.Scope] = tag
+		}
+	}
+	return result.Append(serviceTag(objectName)).Normalize()
+}
+
 func checkTags(tags []model.Tag, required ...model.Tag) bool {
 outer:
 	for _, req := range required {
@@ -0,0 +1,125 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+const (
+	// defaultClientSSLProfilePath is the client SSL profile of the virtual
+	// servers terminating TLS if their class has none
+	defaultClientSSLProfilePath = "/infra/lb-client-ssl-profiles/default-balanced-client-ssl-profile"
+	// certificatesPath is the policy path of the NSX-T certificate store
+	certificatesPath = "/infra/certificates/"
+)
+
+// tlsTermination is the TLS termination of the TCP virtual servers of a
+// service
+type tlsTermination struct {
+	certificatePath      string
+	clientSSLProfilePath string
+	// ports are the service ports terminating TLS, all TCP ports if empty
+	ports sets.Int
+}
+
+// newTLSTermination returns the TLS termination requested by the annotations
+// of the service, nil if it has no TLS certificate.
+func newTLSTermination(service *corev1.Service, class *loadBalancerClass) (*tlsTermination, error) {
+	annotations := service.GetAnnotations()
+	certificate := strings.TrimSpace(annotations[TLSCertificateAnnotation])
+	if certificate == "" {
+		return nil, nil
+	}
+	tls := &tlsTermination{
+		certificatePath:      certificate,
+		clientSSLProfilePath: class.clientSSLProfilePath,
+		ports:                sets.NewInt(),
+	}
+	if !strings.HasPrefix(certificate, "/") {
+		tls.certificatePath = certificatesPath + certificate
+	}
+	if value := strings.TrimSpace(annotations[ClientSSLProfileAnnotation]); value != "" {
+		tls.clientSSLProfilePath = value
+	}
+	if tls.clientSSLProfilePath == "" {
+		tls.clientSSLProfilePath = defaultClientSSLProfilePath
+	}
+	if value := strings.TrimSpace(annotations[TLSPortsAnnotation]); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			port, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || port <= 0 || port > 65535 {
+				return nil, fmt.Errorf("invalid annotation %s: invalid port %q", TLSPortsAnnotation, part)
+			}
+			tls.ports.Insert(port)
+		}
+	}
+	for port := range tls.ports {
+		if !hasTCPServicePort(service, port) {
+			return nil, fmt.Errorf("invalid annotation %s: service has no TCP port %d", TLSPortsAnnotation, port)
+		}
+	}
+	return tls, nil
+}
+
+// hasTCPServicePort returns true if the service has the TCP port
+func hasTCPServicePort(service *corev1.Service, port int) bool {
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) == port && servicePort.Protocol == corev1.ProtocolTCP {
+			return true
+		}
+	}
+	return false
+}
+
+// terminates returns true if the virtual servers of the mapping terminate TLS
+func (t *tlsTermination) terminates(mapping Mapping) bool {
+	if t == nil || mapping.Protocol != corev1.ProtocolTCP {
+		return false
+	}
+	return t.ports.Len() == 0 || t.ports.Has(mapping.SourcePort)
+}
+
+// clientSSLBinding returns the client SSL profile binding of the virtual
+// servers of the mapping, nil if they do not terminate TLS
+func (t *tlsTermination) clientSSLBinding(mapping Mapping) *model.LBClientSslProfileBinding {
+	if !t.terminates(mapping) {
+		return nil
+	}
+	return &model.LBClientSslProfileBinding{
+		DefaultCertificatePath: strptr(t.certificatePath),
+		SslProfilePath:         strptr(t.clientSSLProfilePath),
+	}
+}
+
+// matchClientSSLBinding returns true if the virtual server has the client SSL
+// profile binding
+func matchClientSSLBinding(server *model.LBVirtualServer, binding *model.LBClientSslProfileBinding) bool {
+	current := server.ClientSslProfileBinding
+	if current == nil || binding == nil {
+		return current == binding
+	}
+	return safeEquals(current.DefaultCertificatePath, binding.DefaultCertificatePath) &&
+		safeEquals(current.SslProfilePath, binding.SslProfilePath)
+}
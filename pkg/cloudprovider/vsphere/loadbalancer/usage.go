@@ -0,0 +1,168 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/component-base/metrics/legacyregistry"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// Resources accounted per namespace, used as label of namespaceUsageMetric
+const (
+	resourceVirtualServers = "virtual-servers"
+	resourcePools          = "pools"
+	resourceVIPs           = "vips"
+)
+
+// Keys of the usage report ConfigMap
+const (
+	usageReportKey        = "usage.json"
+	usageReportUpdatedKey = "updated"
+)
+
+// namespaceUsageMetric is the number of NSX-T load balancer resources of the
+// Services of each namespace, for chargeback of shared load balancer capacity
+var namespaceUsageMetric = metrics.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "loadbalancer_namespace_resources",
+		Help: "NSX-T load balancer resources of the Services of a namespace",
+	},
+	[]string{"namespace", "resource"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(namespaceUsageMetric)
+}
+
+// namespaceUsage is the number of load balancer resources of a namespace
+type namespaceUsage struct {
+	VirtualServers int `json:"virtualServers"`
+	Pools          int `json:"pools"`
+	VIPs           int `json:"vips"`
+}
+
+// usageAccounting counts the resources per namespace, derived from the
+// Service tags of the NSX-T objects
+type usageAccounting map[string]*namespaceUsage
+
+func (u usageAccounting) namespace(tags []model.Tag) *namespaceUsage {
+	tag := getTag(tags, ScopeService)
+	if tag == "" {
+		return nil
+	}
+	namespace := parseNamespacedName(tag).Namespace
+	usage, ok := u[namespace]
+	if !ok {
+		usage = &namespaceUsage{}
+		u[namespace] = usage
+	}
+	return usage
+}
+
+func (u usageAccounting) addVirtualServer(server *model.LBVirtualServer) {
+	if usage := u.namespace(server.Tags); usage != nil {
+		usage.VirtualServers++
+	}
+}
+
+func (u usageAccounting) addPool(pool *model.LBPool) {
+	if usage := u.namespace(pool.Tags); usage != nil {
+		usage.Pools++
+	}
+}
+
+func (u usageAccounting) addVIP(allocation *model.IpAddressAllocation) {
+	if usage := u.namespace(allocation.Tags); usage != nil {
+		usage.VIPs++
+	}
+}
+
+// updateMetrics replaces the usage exposed as metrics, so namespaces without
+// resources are not reported anymore
+func (u usageAccounting) updateMetrics() {
+	namespaceUsageMetric.Reset()
+	for namespace, usage := range u {
+		namespaceUsageMetric.WithLabelValues(namespace, resourceVirtualServers).Set(float64(usage.VirtualServers))
+		namespaceUsageMetric.WithLabelValues(namespace, resourcePools).Set(float64(usage.Pools))
+		namespaceUsageMetric.WithLabelValues(namespace, resourceVIPs).Set(float64(usage.VIPs))
+	}
+}
+
+// usageReporter writes the usage to a ConfigMap
+type usageReporter struct {
+	client clientcorev1.ConfigMapInterface
+	name   string
+	now    func() time.Time
+}
+
+func newUsageReporter(client clientcorev1.ConfigMapInterface, name string) *usageReporter {
+	return &usageReporter{client: client, name: name, now: time.Now}
+}
+
+// report creates or updates the ConfigMap with the usage as JSON object
+// keyed by namespace
+func (r *usageReporter) report(ctx context.Context, usage usageAccounting) error {
+	content, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{
+		usageReportKey:        string(content),
+		usageReportUpdatedKey: r.now().UTC().Format(time.RFC3339),
+	}
+
+	configMap, err := r.client.Get(ctx, r.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.name},
+			Data:       data,
+		}
+		_, err = r.client.Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	configMap = configMap.DeepCopy()
+	configMap.Data = data
+	_, err = r.client.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// reportUsage exposes the usage as metrics and writes it to the usage report
+// ConfigMap if configured
+func (p *lbProvider) reportUsage(usage usageAccounting) {
+	usage.updateMetrics()
+	if p.usageReporter == nil {
+		return
+	}
+	if err := p.usageReporter.report(context.TODO(), usage); err != nil {
+		klog.Warningf("reporting load balancer usage to config map %s failed: %v", p.usageReporter.name, err)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// usageAccess lists the given virtual servers and pools in addition to the
+// IP address allocations of releaseAccess
+type usageAccess struct {
+	releaseAccess
+	servers []*model.LBVirtualServer
+	pools   []*model.LBPool
+}
+
+func (a *usageAccess) ListVirtualServers(string) ([]*model.LBVirtualServer, error) {
+	return a.servers, nil
+}
+
+func (a *usageAccess) ListPools(string) ([]*model.LBPool, error) {
+	return a.pools, nil
+}
+
+func TestCleanupReportsUsage(t *testing.T) {
+	web := types.NamespacedName{Namespace: "team-a", Name: "web"}
+	api := types.NamespacedName{Namespace: "team-a", Name: "api"}
+	db := types.NamespacedName{Namespace: "team-b", Name: "db"}
+	tags := func(service types.NamespacedName) []model.Tag {
+		return []model.Tag{clusterTag("cluster1"), serviceTag(service)}
+	}
+	access := &usageAccess{
+		releaseAccess: releaseAccess{allocations: []*model.IpAddressAllocation{
+			{Id: strptr("web"), Tags: tags(web)},
+			{Id: strptr("db"), Tags: tags(db)},
+			{Id: strptr("held"), Tags: []model.Tag{clusterTag("cluster1"), newTag(ScopeReleased, time.Now().UTC().Format(time.RFC3339))}},
+		}},
+		servers: []*model.LBVirtualServer{
+			{Tags: tags(web)}, {Tags: tags(web)}, {Tags: tags(api)}, {Tags: tags(db)},
+		},
+		pools: []*model.LBPool{
+			{Tags: tags(web)}, {Tags: tags(api)}, {Tags: tags(db)},
+		},
+	}
+	lbService := newLbService(access, "")
+	lbService.releaseQuarantine = time.Hour
+	client := fake.NewSimpleClientset()
+	p := &lbProvider{
+		lbService: lbService,
+		classes: &loadBalancerClasses{classes: map[string]*loadBalancerClass{
+			"default": {className: "default", ipPool: Reference{Identifier: "pool"}},
+		}},
+		usageReporter: newUsageReporter(client.CoreV1().ConfigMaps("kube-system"), "lb-usage"),
+	}
+
+	services := map[types.NamespacedName]corev1.Service{}
+	for _, name := range []types.NamespacedName{web, api, db} {
+		services[name] = corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}}
+	}
+	// the report is created and then updated
+	for i := 0; i < 2; i++ {
+		if err := p.CleanupServices("cluster1", services, false); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	expected := map[string]namespaceUsage{
+		"team-a": {VirtualServers: 3, Pools: 2, VIPs: 1},
+		"team-b": {VirtualServers: 1, Pools: 1, VIPs: 1},
+	}
+	for namespace, usage := range expected {
+		for resource, value := range map[string]int{resourceVirtualServers: usage.VirtualServers, resourcePools: usage.Pools, resourceVIPs: usage.VIPs} {
+			if actual := testutil.ToFloat64(namespaceUsageMetric.WithLabelValues(namespace, resource)); actual != float64(value) {
+				t.Errorf("expected %d %s in namespace %s, but got %v", value, resource, namespace, actual)
+			}
+		}
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "lb-usage", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reported map[string]namespaceUsage
+	if err := json.Unmarshal([]byte(configMap.Data[usageReportKey]), &reported); err != nil {
+		t.Fatal(err)
+	}
+	if len(reported) != len(expected) || reported["team-a"] != expected["team-a"] || reported["team-b"] != expected["team-b"] {
+		t.Errorf("expected reported usage %v, but got %v", expected, reported)
+	}
+	if configMap.Data[usageReportUpdatedKey] == "" {
+		t.Error("expected update time")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strings"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	klog "k8s.io/klog/v2"
+)
+
+// serviceZone returns the zone of the class the virtual servers of the
+// service are placed in, empty for the LbService of the cluster. The zone is
+// given by the zone annotation, or else the zone all the ready endpoints of
+// the service run in.
+func (s *state) serviceZone(class *loadBalancerClass) (string, error) {
+	if len(class.zones) == 0 {
+		return "", nil
+	}
+	if zone := strings.TrimSpace(s.service.GetAnnotations()[ZoneAnnotation]); zone != "" {
+		if _, ok := class.zones[zone]; !ok {
+			return "", fmt.Errorf("invalid annotation %s: load balancer class %s has no zone %s", ZoneAnnotation, class.className, zone)
+		}
+		return zone, nil
+	}
+	zones := s.endpointZones()
+	if zones.Len() != 1 {
+		return "", nil
+	}
+	zone := zones.List()[0]
+	if _, ok := class.zones[zone]; !ok {
+		return "", nil
+	}
+	return zone, nil
+}
+
+// endpointZones returns the zones of the ready endpoints of the service,
+// empty if they are not known
+func (s *state) endpointZones() sets.String {
+	zones := sets.NewString()
+	if s.endpointSlicesLister == nil || !s.endpointSlicesSynced() {
+		return zones
+	}
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: s.service.Name})
+	slices, err := s.endpointSlicesLister.EndpointSlices(s.service.Namespace).List(selector)
+	if err != nil {
+		klog.Warningf("%s: listing endpoint slices failed, not placing the virtual servers in a zone: %v", s.objectName, err)
+		return zones
+	}
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			if endpoint.Zone == nil {
+				// an endpoint of unknown zone may run in any zone
+				return sets.NewString()
+			}
+			zones.Insert(*endpoint.Zone)
+		}
+	}
+	return zones
+}
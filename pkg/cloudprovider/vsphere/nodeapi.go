@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	cpiv1alpha1 "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/apis/cloudprovider/v1alpha1"
+	"k8s.io/cloud-provider-vsphere/pkg/util/secureapi"
+)
+
+const (
+	// nodesAPIPath lists the discovered nodes, nodesAPIPath/<name> reads one
+	nodesAPIPath = "/vsphere/v1/nodes"
+	// discoveryAPIPath reports the state of the discovery of the nodes
+	discoveryAPIPath = "/vsphere/v1/discovery"
+)
+
+// discoveryState is the state of the discovery of the registered nodes as
+// reported by the secure API.
+type discoveryState struct {
+	// RegisteredNodes are the names of the nodes registered with the node
+	// manager
+	RegisteredNodes []string `json:"registeredNodes"`
+	// UndiscoveredNodes are the registered nodes whose VM is not discovered
+	UndiscoveredNodes []string `json:"undiscoveredNodes"`
+	// VMNotFoundSince maps the UUID of the discovered nodes whose VM is not
+	// found anymore to the time it was first not found
+	VMNotFoundSince map[string]time.Time `json:"vmNotFoundSince,omitempty"`
+	// Errors are the last errors of the node discoveries, if reported
+	Errors []cpiv1alpha1.ReconcileError `json:"errors,omitempty"`
+}
+
+// nodeStates returns the discovered nodes sorted by name.
+func (nm *NodeManager) nodeStates() []nodeState {
+	nm.nodeInfoLock.RLock()
+	nodes := make([]nodeState, 0, len(nm.nodeNameMap))
+	for _, node := range nm.nodeNameMap {
+		nodes = append(nodes, newNodeState(node))
+	}
+	nm.nodeInfoLock.RUnlock()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeName < nodes[j].NodeName
+	})
+	return nodes
+}
+
+// discoveryState returns the state of the discovery of the registered nodes.
+func (nm *NodeManager) discoveryState() discoveryState {
+	state := discoveryState{}
+
+	nm.nodeRegInfoLock.RLock()
+	registered := make([]string, 0, len(nm.nodeRegUUIDMap))
+	for _, node := range nm.nodeRegUUIDMap {
+		registered = append(registered, node.Name)
+	}
+	nm.nodeRegInfoLock.RUnlock()
+	sort.Strings(registered)
+	state.RegisteredNodes = registered
+
+	nm.nodeInfoLock.RLock()
+	for _, name := range registered {
+		if _, ok := nm.nodeNameMap[name]; !ok {
+			state.UndiscoveredNodes = append(state.UndiscoveredNodes, name)
+		}
+	}
+	if len(nm.vmNotFoundSince) > 0 {
+		state.VMNotFoundSince = make(map[string]time.Time, len(nm.vmNotFoundSince))
+		for uuid, since := range nm.vmNotFoundSince {
+			state.VMNotFoundSince[uuid] = since
+		}
+	}
+	nm.nodeInfoLock.RUnlock()
+
+	for _, e := range nm.reconcileErrors.list() {
+		if e.Kind == "Node" {
+			state.Errors = append(state.Errors, e)
+		}
+	}
+	return state
+}
+
+// nodesHandler responds with the discovered nodes, or with the node named by
+// the last element of the path under nodesAPIPath.
+func (nm *NodeManager) nodesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, nodesAPIPath), "/")
+		if name == "" {
+			writeJSON(w, nm.nodeStates())
+			return
+		}
+		nm.nodeInfoLock.RLock()
+		node, ok := nm.nodeNameMap[name]
+		var state nodeState
+		if ok {
+			state = newNodeState(node)
+		}
+		nm.nodeInfoLock.RUnlock()
+		if !ok {
+			http.Error(w, "node "+name+" not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, state)
+	})
+}
+
+// discoveryHandler responds with the state of the discovery of the nodes.
+func (nm *NodeManager) discoveryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, nm.discoveryState())
+	})
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	b, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	_, _ = w.Write(b)
+}
+
+// registerSecureAPI adds the node mappings and the state of the node
+// discovery to the secure API, unless the profile does not discover nodes.
+// Each endpoint is a separate non-resource URL for RBAC.
+func (vs *VSphere) registerSecureAPI() {
+	if vs.nodeManager == nil || !vs.profile.runsNodes() {
+		return
+	}
+	secureapi.Register(nodesAPIPath, vs.nodeManager.nodesHandler())
+	secureapi.Register(nodesAPIPath+"/", vs.nodeManager.nodesHandler())
+	secureapi.Register(discoveryAPIPath, vs.nodeManager.discoveryHandler())
+}
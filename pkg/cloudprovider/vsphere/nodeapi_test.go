@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodesHandler(t *testing.T) {
+	nm := newNodeManager(nil, nil)
+	nm.nodeNameMap["node-b"] = &NodeInfo{NodeName: "node-b", UUID: "uuid-b", vcServer: "vc"}
+	nm.nodeNameMap["node-a"] = &NodeInfo{NodeName: "node-a", UUID: "uuid-a", vcServer: "vc"}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		nm.nodesHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	var nodes []nodeState
+	if err := json.Unmarshal(get(nodesAPIPath).Body.Bytes(), &nodes); err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || nodes[0].NodeName != "node-a" || nodes[1].UUID != "uuid-b" {
+		t.Errorf("Unexpected nodes %+v", nodes)
+	}
+
+	var node nodeState
+	if err := json.Unmarshal(get(nodesAPIPath+"/node-b").Body.Bytes(), &node); err != nil {
+		t.Fatal(err)
+	}
+	if node.UUID != "uuid-b" || node.VCenter != "vc" {
+		t.Errorf("Unexpected node %+v", node)
+	}
+
+	if code := get(nodesAPIPath + "/node-c").Code; code != http.StatusNotFound {
+		t.Errorf("Unknown node should not be found, got %d", code)
+	}
+}
+
+func TestDiscoveryState(t *testing.T) {
+	nm := newNodeManager(nil, nil)
+	nm.reconcileErrors = newReconcileErrors()
+	nm.nodeNameMap["node-a"] = &NodeInfo{NodeName: "node-a", UUID: "uuid-a"}
+	nm.addNode("uuid-a", &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+	nm.addNode("uuid-b", &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}})
+	nm.reconcileErrors.record("Node", "node-b", errors.New("VM not found"))
+	nm.reconcileErrors.record("Service", "default/lb", errors.New("quota exceeded"))
+
+	state := nm.discoveryState()
+	if len(state.RegisteredNodes) != 2 || state.RegisteredNodes[0] != "node-a" {
+		t.Errorf("Unexpected registered nodes %v", state.RegisteredNodes)
+	}
+	if len(state.UndiscoveredNodes) != 1 || state.UndiscoveredNodes[0] != "node-b" {
+		t.Errorf("Unexpected undiscovered nodes %v", state.UndiscoveredNodes)
+	}
+	if len(state.Errors) != 1 || state.Errors[0].Name != "node-b" {
+		t.Errorf("Only the errors of the nodes should be reported, got %+v", state.Errors)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/legacyregistry"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer"
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// Triggers of a node cleanup, used as label of nodeCleanupMetric
+const (
+	nodeCleanupUnregistration = "unregistration"
+	nodeCleanupJanitor        = "janitor"
+)
+
+var (
+	// nodeCleanupInterval is the interval at which the janitor cleans up the
+	// NSX objects of nodes which do not exist anymore, 0 to only clean up
+	// when a node is unregistered.
+	nodeCleanupInterval time.Duration
+
+	// nodeCleanupMetric counts the cleanups of the NSX objects of a node by
+	// feature
+	nodeCleanupMetric = metrics.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "node_cleanups",
+			Help: "Cleanups of the NSX objects created for a node by an optional feature",
+		},
+		[]string{"feature", "trigger", "result"},
+	)
+)
+
+func init() {
+	legacyregistry.RawMustRegister(nodeCleanupMetric)
+
+	flag.DurationVar(&nodeCleanupInterval, "node-cleanup-interval", 10*time.Minute, "Interval at which the NSX objects created by optional features for nodes which do not exist anymore, such as static routes, are cleaned up. 0 only cleans them up when a node is deleted.")
+}
+
+// NodeCleaner is implemented by the optional features creating NSX objects
+// for each node, so that the objects of deleted nodes do not leak.
+type NodeCleaner interface {
+	// CleanupNode deletes the objects created for the node.
+	CleanupNode(ctx context.Context, clusterName string, nodeName string) error
+	// ListNodes returns the names of the nodes having objects.
+	ListNodes(ctx context.Context, clusterName string) ([]string, error)
+}
+
+// nodeCleanupRegistry runs the NodeCleaners of the features when a node is
+// unregistered and periodically for the nodes which do not exist anymore.
+type nodeCleanupRegistry struct {
+	lock     sync.RWMutex
+	cleaners map[string]NodeCleaner
+}
+
+func newNodeCleanupRegistry() *nodeCleanupRegistry {
+	return &nodeCleanupRegistry{cleaners: map[string]NodeCleaner{}}
+}
+
+// Register adds the cleaner of a feature.
+func (r *nodeCleanupRegistry) Register(feature string, cleaner NodeCleaner) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cleaners[feature] = cleaner
+}
+
+// features returns the registered features in a stable order.
+func (r *nodeCleanupRegistry) features() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	features := make([]string, 0, len(r.cleaners))
+	for feature := range r.cleaners {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return features
+}
+
+func (r *nodeCleanupRegistry) cleaner(feature string) NodeCleaner {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cleaners[feature]
+}
+
+// cleanupNode runs the cleaners of all features for a node.
+func (r *nodeCleanupRegistry) cleanupNode(ctx context.Context, clusterName, nodeName, trigger string) error {
+	var errs []error
+	for _, feature := range r.features() {
+		if err := r.cleanupFeature(ctx, feature, clusterName, nodeName, trigger); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *nodeCleanupRegistry) cleanupFeature(ctx context.Context, feature, clusterName, nodeName, trigger string) error {
+	err := r.cleaner(feature).CleanupNode(ctx, clusterName, nodeName)
+	result := "success"
+	if err != nil {
+		result = "error"
+		err = fmt.Errorf("cleaning up %s objects of node %s failed: %w", feature, nodeName, err)
+	}
+	nodeCleanupMetric.WithLabelValues(feature, trigger, result).Inc()
+	return err
+}
+
+// runJanitor cleans up the objects of the nodes listed by the features
+// which are not found by the node lister. Nothing is cleaned up while the
+// lister has no nodes, as an empty cache must not delete all objects.
+func (r *nodeCleanupRegistry) runJanitor(ctx context.Context, clusterName string, nodeLister listerv1.NodeLister) error {
+	nodes, err := nodeLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("listing nodes failed: %w", err)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, feature := range r.features() {
+		nodeNames, err := r.cleaner(feature).ListNodes(ctx, clusterName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing nodes with %s objects failed: %w", feature, err))
+			continue
+		}
+		for _, nodeName := range nodeNames {
+			if _, err := nodeLister.Get(nodeName); !apierrors.IsNotFound(err) {
+				continue
+			}
+			klog.V(2).Infof("Cleaning up %s objects of deleted node %s", feature, nodeName)
+			if err := r.cleanupFeature(ctx, feature, clusterName, nodeName, nodeCleanupJanitor); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// startNodeCleanupJanitor runs the janitor at the node cleanup interval once
+// the node informer is synced.
+func (vs *VSphere) startNodeCleanupJanitor(stop <-chan struct{}) {
+	if nodeCleanupInterval <= 0 || vs.nodeCleanup == nil || len(vs.nodeCleanup.features()) == 0 {
+		return
+	}
+	if loadbalancer.ClusterName == "" {
+		klog.Warning("Missing cluster id, no periodical cleanup of the NSX objects of deleted nodes possible")
+		return
+	}
+	go func() {
+		if !cache.WaitForCacheSync(stop, vs.informMgr.IsNodeInformerSynced()) {
+			return
+		}
+		wait.Until(func() {
+			err := vs.nodeCleanup.runJanitor(context.Background(), loadbalancer.ClusterName, vs.informMgr.GetNodeLister())
+			if err != nil {
+				klog.Warningf("Cleaning up the NSX objects of deleted nodes failed: %v", err)
+			}
+		}, nodeCleanupInterval, stop)
+	}()
+}
+
+// cleanupDeletedNode cleans up the objects of a node which was unregistered,
+// unless a node with the same name was registered again meanwhile.
+func (vs *VSphere) cleanupDeletedNode(nodeName string) {
+	if loadbalancer.ClusterName == "" || vs.nodeCleanup == nil || len(vs.nodeCleanup.features()) == 0 {
+		return
+	}
+	if vs.informMgr != nil {
+		if _, err := vs.informMgr.GetNodeLister().Get(nodeName); !apierrors.IsNotFound(err) {
+			return
+		}
+	}
+	err := vs.nodeCleanup.cleanupNode(context.Background(), loadbalancer.ClusterName, nodeName, nodeCleanupUnregistration)
+	if err != nil {
+		klog.Warningf("Cleaning up the NSX objects of node %s failed, retrying in the next janitor run: %v", nodeName, err)
+	}
+}
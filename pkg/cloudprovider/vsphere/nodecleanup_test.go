@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// fakeNodeCleaner keeps the names of the nodes having objects
+type fakeNodeCleaner struct {
+	nodes   map[string]bool
+	failing string
+}
+
+func (c *fakeNodeCleaner) CleanupNode(_ context.Context, _ string, nodeName string) error {
+	if nodeName == c.failing {
+		return errors.New("cleanup failed")
+	}
+	delete(c.nodes, nodeName)
+	return nil
+}
+
+func (c *fakeNodeCleaner) ListNodes(_ context.Context, _ string) ([]string, error) {
+	var nodeNames []string
+	for nodeName := range c.nodes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	return nodeNames, nil
+}
+
+func (c *fakeNodeCleaner) nodeNames() []string {
+	nodeNames, _ := c.ListNodes(context.TODO(), "")
+	sort.Strings(nodeNames)
+	return nodeNames
+}
+
+func newFakeNodeLister(t *testing.T, nodeNames ...string) listerv1.NodeLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, nodeName := range nodeNames {
+		if err := indexer.Add(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return listerv1.NewNodeLister(indexer)
+}
+
+func TestNodeCleanupJanitor(t *testing.T) {
+	routes := &fakeNodeCleaner{nodes: map[string]bool{"node1": true, "node2": true, "node3": true}}
+	snat := &fakeNodeCleaner{nodes: map[string]bool{"node2": true, "node4": true}, failing: "node4"}
+	registry := newNodeCleanupRegistry()
+	registry.Register("route", routes)
+	registry.Register("snat", snat)
+
+	// an empty cache deletes nothing
+	if err := registry.runJanitor(context.TODO(), "cluster", newFakeNodeLister(t)); err != nil {
+		t.Fatal(err)
+	}
+	if len(routes.nodes) != 3 {
+		t.Errorf("expected no cleanup, but got %v", routes.nodeNames())
+	}
+
+	failed := testutil.ToFloat64(nodeCleanupMetric.WithLabelValues("snat", nodeCleanupJanitor, "error"))
+	err := registry.runJanitor(context.TODO(), "cluster", newFakeNodeLister(t, "node1", "node2"))
+	if err == nil {
+		t.Error("expected error of the failing cleanup")
+	}
+	if !reflect.DeepEqual(routes.nodeNames(), []string{"node1", "node2"}) {
+		t.Errorf("unexpected route nodes %v", routes.nodeNames())
+	}
+	if !reflect.DeepEqual(snat.nodeNames(), []string{"node2", "node4"}) {
+		t.Errorf("unexpected snat nodes %v", snat.nodeNames())
+	}
+	if value := testutil.ToFloat64(nodeCleanupMetric.WithLabelValues("snat", nodeCleanupJanitor, "error")); value != failed+1 {
+		t.Errorf("expected %v failed cleanups, but got %v", failed+1, value)
+	}
+}
+
+func TestNodeCleanupOnUnregistration(t *testing.T) {
+	routes := &fakeNodeCleaner{nodes: map[string]bool{"node1": true, "node2": true}}
+	snat := &fakeNodeCleaner{nodes: map[string]bool{"node1": true}}
+	registry := newNodeCleanupRegistry()
+	registry.Register("route", routes)
+	registry.Register("snat", snat)
+
+	if err := registry.cleanupNode(context.TODO(), "cluster", "node1", nodeCleanupUnregistration); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(routes.nodeNames(), []string{"node2"}) {
+		t.Errorf("unexpected route nodes %v", routes.nodeNames())
+	}
+	if len(snat.nodes) != 0 {
+		t.Errorf("unexpected snat nodes %v", snat.nodeNames())
+	}
+}
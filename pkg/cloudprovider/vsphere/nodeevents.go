@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"errors"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+// Reasons of the events recorded on a node whose discovery failed
+const (
+	// EventReasonMultipleVMsFound is the reason of the event recorded when
+	// several VMs match the node
+	EventReasonMultipleVMsFound = "MultipleVMsFound"
+	// EventReasonGuestNicInfoEmpty is the reason of the event recorded when
+	// VMware Tools did not report any network interface of the VM
+	EventReasonGuestNicInfoEmpty = "GuestNicInfoEmpty"
+	// EventReasonNoSuitableIPAddress is the reason of the event recorded when
+	// none of the addresses of the VM can be used as node address
+	EventReasonNoSuitableIPAddress = "NoSuitableIPAddress"
+	// EventReasonInvalidGuestInfo is the reason of the event recorded when
+	// the guestinfo metadata of the VM is too large or malformed
+	EventReasonInvalidGuestInfo = "InvalidGuestInfo"
+	// EventReasonVMUUIDMismatch is the reason of the event recorded when the
+	// UUID of the VM found by name does not match the node
+	EventReasonVMUUIDMismatch = "VMUUIDMismatch"
+	// EventReasonNodeDiscoveryFailed is the reason of the event recorded for
+	// any other discovery failure
+	EventReasonNodeDiscoveryFailed = "NodeDiscoveryFailed"
+)
+
+var (
+	errGuestNicInfoEmpty   = errors.New("VM GuestNicInfo is empty")
+	errNoSuitableIPAddress = errors.New("unable to find suitable IP address for node")
+	errVMUUIDMismatch      = errors.New("UUID of the VM does not match the node")
+)
+
+// newNodeEventRecorder returns a recorder of the events on nodes, which stops
+// recording when stop is closed.
+func newNodeEventRecorder(client clientset.Interface, stop <-chan struct{}) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	go func() {
+		<-stop
+		eventBroadcaster.Shutdown()
+	}()
+	return eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: ClientName})
+}
+
+// discoveryFailureReason returns the event reason of a discovery error.
+func discoveryFailureReason(err error) string {
+	switch {
+	case errors.Is(err, vclib.ErrMultipleVMsFound):
+		return EventReasonMultipleVMsFound
+	case errors.Is(err, errGuestNicInfoEmpty):
+		return EventReasonGuestNicInfoEmpty
+	case errors.Is(err, errNoSuitableIPAddress):
+		return EventReasonNoSuitableIPAddress
+	case errors.As(err, new(*GuestInfoError)):
+		return EventReasonInvalidGuestInfo
+	case errors.Is(err, errVMUUIDMismatch):
+		return EventReasonVMUUIDMismatch
+	default:
+		return EventReasonNodeDiscoveryFailed
+	}
+}
+
+// recordDiscoveryFailure records a warning event on the node, so that the
+// failure shows up in kubectl describe node. Nothing is recorded without
+// recorder or if the name of the node is not known.
+func (nm *NodeManager) recordDiscoveryFailure(nodeName string, err error) {
+	if nm.recorder == nil || nodeName == "" || err == nil {
+		return
+	}
+	// events of nodes refer to the node by name, like the kubelet does
+	ref := &v1.ObjectReference{
+		Kind: "Node",
+		Name: nodeName,
+		UID:  types.UID(nodeName),
+	}
+	nm.recorder.Eventf(ref, v1.EventTypeWarning, discoveryFailureReason(err), "Discovering the VM of the node failed: %v", err)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+func TestDiscoveryFailureReason(t *testing.T) {
+	testcases := []struct {
+		err    error
+		reason string
+	}{
+		{err: vclib.ErrMultipleVMsFound, reason: EventReasonMultipleVMsFound},
+		{err: errGuestNicInfoEmpty, reason: EventReasonGuestNicInfoEmpty},
+		{err: fmt.Errorf("%w after filtering out localhost IPs", errNoSuitableIPAddress), reason: EventReasonNoSuitableIPAddress},
+		{err: fmt.Errorf("%w: VM UUID a, node UUID b", errVMUUIDMismatch), reason: EventReasonVMUUIDMismatch},
+		{err: &GuestInfoError{NodeName: "node1", Err: errGuestInfoTooLarge}, reason: EventReasonInvalidGuestInfo},
+		{err: errors.New("VM Guest hostname is empty"), reason: EventReasonNodeDiscoveryFailed},
+	}
+
+	for _, testcase := range testcases {
+		if reason := discoveryFailureReason(testcase.err); reason != testcase.reason {
+			t.Errorf("expected reason %s for %v, got %s", testcase.reason, testcase.err, reason)
+		}
+	}
+}
+
+func TestRegisterNodeRecordsDiscoveryFailure(t *testing.T) {
+	cfg, fin := configFromEnvOrSim(true)
+	defer fin()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = nil
+
+	nm := newNodeManager(nil, connMgr)
+	recorder := record.NewFakeRecorder(10)
+	nm.recorder = recorder
+
+	if err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	nm.RegisterNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: vm.Name},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: vm.Config.Uuid},
+		},
+	})
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, v1.EventTypeWarning+" "+EventReasonGuestNicInfoEmpty) {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected an event for the failed discovery")
+	}
+}
+
+func TestRecordDiscoveryFailureUnknownNode(t *testing.T) {
+	nm := newNodeManager(nil, nil)
+	recorder := record.NewFakeRecorder(10)
+	nm.recorder = recorder
+
+	nm.recordDiscoveryFailure("", errGuestNicInfoEmpty)
+	nm.recordDiscoveryFailure("node", nil)
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected no events, got %d", len(recorder.Events))
+	}
+}
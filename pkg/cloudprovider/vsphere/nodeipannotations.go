@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// AnnotationInternalIP is the node annotation selecting the InternalIP
+	// of the node among the addresses of its VM, ahead of the subnet,
+	// network name and default selections. It holds one address, or a
+	// comma separated address per IP family for dual-stack nodes.
+	AnnotationInternalIP = "vsphere.cpi.kubernetes.io/internal-ip"
+	// AnnotationExternalIP is the node annotation selecting the ExternalIP
+	// of the node, in the same format as AnnotationInternalIP.
+	AnnotationExternalIP = "vsphere.cpi.kubernetes.io/external-ip"
+
+	// EventReasonInvalidNodeIPAnnotation is the reason of the event recorded
+	// when an address annotated on the node is not an address of its VM
+	EventReasonInvalidNodeIPAnnotation = "InvalidNodeIPAnnotation"
+)
+
+// nodeIPAnnotations returns the addresses annotated on the node, which are
+// empty if the node or the node lister are not known.
+func (nm *NodeManager) nodeIPAnnotations(nodeName string) (internal string, external string) {
+	if nm.nodeLister == nil || nodeName == "" {
+		return "", ""
+	}
+	node, err := nm.nodeLister.Get(nodeName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Warningf("Failed to get node %s to read its IP annotations: %v", nodeName, err)
+		}
+		return "", ""
+	}
+	return node.Annotations[AnnotationInternalIP], node.Annotations[AnnotationExternalIP]
+}
+
+// findAnnotatedIP returns the address of ipAddrNetworkNames of ipFamily
+// annotated in value, nil if value has no address of ipFamily. An error is
+// returned if the annotated address is not one of ipAddrNetworkNames.
+func findAnnotatedIP(ipAddrNetworkNames []*ipAddrNetworkName, value string, ipFamily string) (*ipAddrNetworkName, error) {
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		ip := parseAddr(s)
+		if !ip.IsValid() {
+			return nil, fmt.Errorf("%q is not an IP address", s)
+		}
+		if !matchesFamily(ip, ipFamily) {
+			continue
+		}
+		match := findFirst(ipAddrNetworkNames, func(candidate *ipAddrNetworkName) bool {
+			return candidate.ip() == ip
+		})
+		if match == nil {
+			return nil, fmt.Errorf("%s is not an address of the VM", s)
+		}
+		return match, nil
+	}
+	return nil, nil
+}
+
+// annotatedIP returns the address annotated on the node under annotation like
+// findAnnotatedIP. An invalid annotation is reported on the node and ignored,
+// so that the address is selected as if the node was not annotated.
+func (nm *NodeManager) annotatedIP(nodeName string, annotation string, value string,
+	ipAddrNetworkNames []*ipAddrNetworkName, ipFamily string) *ipAddrNetworkName {
+	if value == "" {
+		return nil
+	}
+	match, err := findAnnotatedIP(ipAddrNetworkNames, value, ipFamily)
+	if err != nil {
+		klog.Warningf("Ignoring annotation %s of node %s: %v", annotation, nodeName, err)
+		if nm.recorder != nil {
+			ref := &v1.ObjectReference{Kind: "Node", Name: nodeName, UID: types.UID(nodeName)}
+			nm.recorder.Eventf(ref, v1.EventTypeWarning, EventReasonInvalidNodeIPAnnotation,
+				"Ignoring annotation %s: %v", annotation, err)
+		}
+		return nil
+	}
+	if match != nil {
+		logging.V(logging.NodeManager, 2).Infof("Using IP %s annotated with %s on node %s", match.ipAddr, annotation, nodeName)
+	}
+	return match
+}
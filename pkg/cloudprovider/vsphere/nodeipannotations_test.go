@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestFindAnnotatedIP(t *testing.T) {
+	candidates := []*ipAddrNetworkName{
+		{ipAddr: "10.0.0.1", networkName: "internal"},
+		{ipAddr: "192.168.0.1", networkName: "external"},
+		{ipAddr: "fd00::1", networkName: "internal"},
+	}
+
+	testcases := []struct {
+		name     string
+		value    string
+		ipFamily string
+		expected string
+		err      bool
+	}{
+		{name: "IPv4", value: "192.168.0.1", ipFamily: "ipv4", expected: "192.168.0.1"},
+		{name: "dual-stack IPv4", value: "fd00::1, 10.0.0.1", ipFamily: "ipv4", expected: "10.0.0.1"},
+		{name: "dual-stack IPv6", value: "10.0.0.1,fd00:0::1", ipFamily: "ipv6", expected: "fd00::1"},
+		{name: "other family", value: "10.0.0.1", ipFamily: "ipv6"},
+		{name: "not an address of the VM", value: "10.0.0.2", ipFamily: "ipv4", err: true},
+		{name: "not an IP", value: "node-1", ipFamily: "ipv4", err: true},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			match, err := findAnnotatedIP(candidates, testcase.value, testcase.ipFamily)
+			if (err != nil) != testcase.err {
+				t.Fatalf("expected error %t, got %v", testcase.err, err)
+			}
+			actual := ""
+			if match != nil {
+				actual = match.ipAddr
+			}
+			if actual != testcase.expected {
+				t.Errorf("expected %q, got %q", testcase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiscoverNodeIPAnnotations(t *testing.T) {
+	cfg, fin := configFromEnvOrSim(true)
+	defer fin()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+	if err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{Network: "VM Network", IpAddress: []string{"10.0.0.10", "10.0.0.11"}},
+		{Network: "External", IpAddress: []string{"192.168.0.10"}},
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: vm.Name,
+		Annotations: map[string]string{
+			AnnotationInternalIP: "10.0.0.11",
+			AnnotationExternalIP: "172.16.0.1",
+		},
+	}}
+	if err := indexer.Add(node); err != nil {
+		t.Fatal(err)
+	}
+
+	nm := newNodeManager(nil, connMgr)
+	nm.nodeLister = listerv1.NewNodeLister(indexer)
+	recorder := record.NewFakeRecorder(10)
+	nm.recorder = recorder
+
+	if err := nm.DiscoverNode(vm.Name, cm.FindVMByName); err != nil {
+		t.Fatalf("Failed DiscoverNode: %s", err)
+	}
+
+	addrs := nm.nodeNameMap[strings.ToLower(vm.Name)].NodeAddresses
+	for _, addr := range addrs {
+		switch addr.Type {
+		case v1.NodeInternalIP:
+			if addr.Address != "10.0.0.11" {
+				t.Errorf("expected the annotated InternalIP 10.0.0.11, got %s", addr.Address)
+			}
+		case v1.NodeExternalIP:
+			// the annotated address is not an address of the VM
+			if addr.Address != "10.0.0.10" {
+				t.Errorf("expected the discovered ExternalIP 10.0.0.10, got %s", addr.Address)
+			}
+		}
+	}
+
+	// the VM found by name is recorded before the annotation is checked
+	if event := <-recorder.Events; !strings.HasPrefix(event, v1.EventTypeNormal+" "+EventReasonVMDiscovered) {
+		t.Errorf("unexpected event %q", event)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, v1.EventTypeWarning+" "+EventReasonInvalidNodeIPAnnotation) {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected an event for the invalid annotation")
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// invalidLabelChars matches the characters not allowed in the name of a
+// label key or in a label value.
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeLabel turns s into a valid label name or value, by replacing the
+// characters that are not allowed with dashes and trimming it to 63
+// characters starting and ending with an alphanumeric character. It
+// returns "" if nothing of s is left.
+func sanitizeLabel(s string) string {
+	s = invalidLabelChars.ReplaceAllString(strings.TrimSpace(s), "-")
+	s = strings.TrimLeft(s, "._-")
+	if len(s) > validation.LabelValueMaxLength {
+		s = s[:validation.LabelValueMaxLength]
+	}
+	return strings.TrimRight(s, "._-")
+}
+
+// vmLabels returns the node labels of a VM, named prefix followed by the
+// sanitized name of its custom attributes and of its tags. A tag named
+// "key=value" becomes the label key with that value, any other tag a label
+// named after the tag with the value "true". Tags take precedence over
+// custom attributes of the same name.
+func vmLabels(prefix string, fields []types.CustomFieldDef, values []types.BaseCustomFieldValue, tagNames []string) map[string]string {
+	labels := make(map[string]string)
+
+	fieldNames := make(map[int32]string, len(fields))
+	for _, field := range fields {
+		fieldNames[field.Key] = field.Name
+	}
+	for _, value := range values {
+		v, ok := value.(*types.CustomFieldStringValue)
+		if !ok {
+			continue
+		}
+		if name := sanitizeLabel(fieldNames[v.Key]); name != "" {
+			labels[prefix+name] = sanitizeLabel(v.Value)
+		}
+	}
+
+	sort.Strings(tagNames)
+	for _, tag := range tagNames {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			value = "true"
+		}
+		if name := sanitizeLabel(key); name != "" {
+			labels[prefix+name] = sanitizeLabel(value)
+		}
+	}
+	return labels
+}
+
+// readVMLabels returns the node labels of the custom attributes and of the
+// tags of the configured category of the VM of the node.
+func (nm *NodeManager) readVMLabels(ctx context.Context, nodeInfo *NodeInfo) (map[string]string, error) {
+	nodes := &nm.cfg.Nodes
+
+	var fields []types.CustomFieldDef
+	var values []types.BaseCustomFieldValue
+	if nodes.VMLabelCustomAttributes {
+		vmMoList, err := nodeInfo.vm.Datacenter.GetVMMoList(ctx, []*vclib.VirtualMachine{nodeInfo.vm}, []string{"availableField", "customValue"})
+		if err != nil {
+			return nil, err
+		}
+		fields, values = vmMoList[0].AvailableField, vmMoList[0].CustomValue
+	}
+
+	var tagNames []string
+	if nodes.VMLabelTagCategory != "" {
+		var err error
+		tagNames, err = nm.connectionManager.ListTagsInCategory(ctx, nodeInfo.tenantRef, nodeInfo.vm.Reference(), nodes.VMLabelTagCategory)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return vmLabels(nodes.VMLabelPrefixOrDefault(), fields, values, tagNames), nil
+}
+
+// syncVMLabels applies the custom attributes and tags of the VMs of the
+// registered nodes as node labels. The labels of a node whose VM cannot be
+// read are left unchanged.
+func (nm *NodeManager) syncVMLabels(ctx context.Context) {
+	nm.nodeRegInfoLock.RLock()
+	nodeNames := make(map[string]string, len(nm.nodeRegUUIDMap))
+	for uuid, node := range nm.nodeRegUUIDMap {
+		nodeNames[uuid] = node.Name
+	}
+	nm.nodeRegInfoLock.RUnlock()
+
+	for uuid, nodeName := range nodeNames {
+		nm.nodeInfoLock.RLock()
+		nodeInfo, ok := nm.nodeUUIDMap[uuid]
+		nm.nodeInfoLock.RUnlock()
+		if !ok || nodeInfo.vm == nil || nm.kubeClient == nil {
+			continue
+		}
+
+		labels, err := nm.readVMLabels(ctx, nodeInfo)
+		if err != nil {
+			klog.Warningf("Failed to read the labels of node %s from its VM, keeping the previous ones: %v", nodeName, err)
+			continue
+		}
+		if err := nm.updateVMLabels(ctx, nodeName, labels); err != nil {
+			klog.Errorf("Failed to update the VM labels of node %s: %v", nodeName, err)
+		}
+	}
+}
+
+// updateVMLabels sets the labels of the node with the VM label prefix to
+// labels, removing the ones the VM no longer has.
+func (nm *NodeManager) updateVMLabels(ctx context.Context, nodeName string, labels map[string]string) error {
+	node, err := nm.kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	prefix := nm.cfg.Nodes.VMLabelPrefixOrDefault()
+	changed := make(map[string]interface{})
+	for key, value := range labels {
+		if current, ok := node.Labels[key]; !ok || current != value {
+			changed[key] = value
+		}
+	}
+	for key := range node.Labels {
+		if _, ok := labels[key]; !ok && strings.HasPrefix(key, prefix) {
+			changed[key] = nil
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": changed,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.V(logging.NodeManager, 2).Infof("Updating the VM labels of node %s: %v", nodeName, changed)
+	_, err = nm.kubeClient.CoreV1().Nodes().Patch(ctx, nodeName, apitypes.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestSanitizeLabel(t *testing.T) {
+	for s, expected := range map[string]string{
+		"rack-12":                      "rack-12",
+		" Rack Position ":              "Rack-Position",
+		"-_.gpu/a100._":                "gpu-a100",
+		"***":                          "",
+		strings.Repeat("a", 70):        strings.Repeat("a", 63),
+		strings.Repeat("a", 62) + ".b": strings.Repeat("a", 62),
+	} {
+		if label := sanitizeLabel(s); label != expected {
+			t.Errorf("expected %q to be sanitized as %q, but got %q", s, expected, label)
+		}
+	}
+}
+
+func TestVMLabels(t *testing.T) {
+	fields := []vimtypes.CustomFieldDef{{Key: 1, Name: "rack"}, {Key: 2, Name: "Serial Number"}, {Key: 3, Name: "***"}}
+	values := []vimtypes.BaseCustomFieldValue{
+		&vimtypes.CustomFieldStringValue{CustomFieldValue: vimtypes.CustomFieldValue{Key: 1}, Value: "r12"},
+		&vimtypes.CustomFieldStringValue{CustomFieldValue: vimtypes.CustomFieldValue{Key: 2}, Value: "SN 1234"},
+		&vimtypes.CustomFieldStringValue{CustomFieldValue: vimtypes.CustomFieldValue{Key: 3}, Value: "ignored"},
+	}
+
+	labels := vmLabels("hw/", fields, values, []string{"gpu", "rack=r13"})
+	expected := map[string]string{
+		"hw/rack":          "r13",
+		"hw/Serial-Number": "SN-1234",
+		"hw/gpu":           "true",
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected labels %v, but got %v", expected, labels)
+	}
+}
+
+func TestSyncVMLabels(t *testing.T) {
+	cfg, ok := configFromEnvOrSim(true)
+	defer ok()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	cpiCfg := &ccfg.CPIConfig{}
+	cpiCfg.Nodes.VMLabelCustomAttributes = true
+	cpiCfg.Nodes.VMLabelTagCategory = "k8s-labels"
+	nm := newNodeManager(cpiCfg, connMgr)
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+
+	ctx := context.Background()
+	vsi := connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+	if err := nm.DiscoverNode(vm.Name, cm.FindVMByName); err != nil {
+		t.Fatalf("Failed DiscoverNode: %s", err)
+	}
+	var nodeInfo *NodeInfo
+	for _, n := range nm.nodeUUIDMap {
+		nodeInfo = n
+	}
+
+	// set a custom attribute and attach a tag of the label category
+	fieldsManager, err := object.GetCustomFieldsManager(vsi.Conn.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, err := fieldsManager.Add(ctx, "rack", "VirtualMachine", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fieldsManager.Set(ctx, vm.Reference(), field.Key, "r12"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := rest.NewClient(vsi.Conn.Client)
+	if err := c.Login(ctx, url.UserPassword(vsi.Conn.Username, vsi.Conn.Password)); err != nil {
+		t.Fatalf("Rest login failed. err=%v", err)
+	}
+	m := tags.NewManager(c)
+	categoryID, err := m.CreateCategory(ctx, &tags.Category{Name: "k8s-labels", Cardinality: "MULTIPLE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagID, err := m.CreateTag(ctx, &tags.Tag{CategoryID: categoryID, Name: "gpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AttachTag(ctx, tagID, vm.Reference()); err != nil {
+		t.Fatal(err)
+	}
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeInfo.NodeName,
+			Labels: map[string]string{
+				ccfg.DefaultVMLabelPrefix + "removed": "true",
+				"kubernetes.io/os":                    "linux",
+			},
+		},
+	}
+	nm.kubeClient = fake.NewSimpleClientset(node)
+	nm.addNode(nodeInfo.UUID, node)
+
+	nm.syncVMLabels(ctx)
+
+	updated, err := nm.kubeClient.CoreV1().Nodes().Get(ctx, nodeInfo.NodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		ccfg.DefaultVMLabelPrefix + "rack": "r12",
+		ccfg.DefaultVMLabelPrefix + "gpu":  "true",
+		"kubernetes.io/os":                 "linux",
+	}
+	if !reflect.DeepEqual(updated.Labels, expected) {
+		t.Errorf("expected labels %v, but got %v", expected, updated.Labels)
+	}
+}
@@ -25,8 +25,11 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/netip"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
@@ -34,6 +37,7 @@ import (
 	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
 	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 	v1helper "k8s.io/cloud-provider/node/helpers"
 	klog "k8s.io/klog/v2"
 
@@ -53,14 +57,28 @@ var (
 
 	// ErrVMNotFound is returned when the specified VM cannot be found.
 	ErrVMNotFound = errors.New("VM not found")
+
+	// ErrVMNotFoundWithinGracePeriod is returned when the VM of a previously
+	// discovered node is not found within the VM not found grace period.
+	ErrVMNotFoundWithinGracePeriod = errors.New("VM not found within the grace period")
 )
 
 type (
 	networkConfig struct {
+		// Ethernets are the interfaces of a version 2 (netplan) network config
 		Ethernets map[string]struct {
 			Name      string   `yaml:"set-name"`
 			Addresses []string `yaml:"addresses"`
 		} `yaml:"ethernets"`
+		// Config lists the interfaces of a version 1 network config
+		Config []struct {
+			Type    string `yaml:"type"`
+			Name    string `yaml:"name"`
+			Subnets []struct {
+				Type    string `yaml:"type"`
+				Address string `yaml:"address"`
+			} `yaml:"subnets"`
+		} `yaml:"config"`
 	}
 	cloudInitConfig struct {
 		Network networkConfig `yaml:"network"`
@@ -71,7 +89,7 @@ type (
 )
 
 func newNodeManager(cfg *ccfg.CPIConfig, cm *cm.ConnectionManager) *NodeManager {
-	return &NodeManager{
+	nm := &NodeManager{
 		nodeNameMap:       make(map[string]*NodeInfo),
 		nodeUUIDMap:       make(map[string]*NodeInfo),
 		nodeRegUUIDMap:    make(map[string]*v1.Node),
@@ -79,90 +97,132 @@ func newNodeManager(cfg *ccfg.CPIConfig, cm *cm.ConnectionManager) *NodeManager
 		connectionManager: cm,
 		cfg:               cfg,
 	}
+	if cfg != nil {
+		nm.networks, nm.networksErr = newNodeNetworks(&cfg.Nodes)
+	}
+	return nm
 }
 
 // RegisterNode is the handler for when a node is added to a K8s cluster.
 func (nm *NodeManager) RegisterNode(node *v1.Node) {
-	klog.V(4).Info("RegisterNode ENTER: ", node.Name)
+	logging.V(logging.NodeManager, 4).Info("RegisterNode ENTER: ", node.Name)
 
-	uuid := ConvertK8sUUIDtoNormal(node.Status.NodeInfo.SystemUUID)
-	if err := nm.DiscoverNode(uuid, cm.FindVMByUUID); err != nil {
+	uuid := nm.nodeUUID(node)
+	err := nm.discoverNode(uuid, cm.FindVMByUUID, node.Name)
+	nm.reconcileErrors.record("Node", node.Name, err)
+	if err != nil {
 		klog.Errorf("error discovering node %s: %v", node.Name, err)
+		nm.recordDiscoveryFailure(node.Name, err)
 		return
 	}
 
 	nm.addNode(uuid, node)
-	klog.V(4).Info("RegisterNode LEAVE: ", node.Name)
+	if err := nm.updateVMMetadataAnnotations(context.Background(), node, uuid); err != nil {
+		klog.Errorf("error updating the VM metadata annotations of node %s: %v", node.Name, err)
+	}
+	logging.V(logging.NodeManager, 4).Info("RegisterNode LEAVE: ", node.Name)
 }
 
 // UnregisterNode is the handler for when a node is removed from a K8s cluster.
 func (nm *NodeManager) UnregisterNode(node *v1.Node) {
-	klog.V(4).Info("UnregisterNode ENTER: ", node.Name)
-	uuid := ConvertK8sUUIDtoNormal(node.Status.NodeInfo.SystemUUID)
+	logging.V(logging.NodeManager, 4).Info("UnregisterNode ENTER: ", node.Name)
+	uuid := nm.nodeUUID(node)
 	nm.removeNode(uuid, node)
-	klog.V(4).Info("UnregisterNode LEAVE: ", node.Name)
+	logging.V(logging.NodeManager, 4).Info("UnregisterNode LEAVE: ", node.Name)
 }
 
 func (nm *NodeManager) addNodeInfo(node *NodeInfo) {
 	nm.nodeInfoLock.Lock()
-	klog.V(4).Info("addNodeInfo NodeName: ", node.NodeName, ", UUID: ", node.UUID)
-	nm.nodeNameMap[node.NodeName] = node
+	logging.V(logging.NodeManager, 4).Info("addNodeInfo NodeName: ", node.NodeName, ", UUID: ", node.UUID)
+	if node.NodeName != "" {
+		nm.nodeNameMap[node.NodeName] = node
+	}
 	nm.nodeUUIDMap[node.UUID] = node
+	delete(nm.vmNotFoundSince, node.UUID)
 	nm.AddNodeInfoToVCList(node.vcServer, node.dataCenter.Name(), node)
 	nm.nodeInfoLock.Unlock()
 }
 
 func (nm *NodeManager) addNode(uuid string, node *v1.Node) {
 	nm.nodeRegInfoLock.Lock()
-	klog.V(4).Info("addNode NodeName: ", node.GetName(), ", UID: ", uuid)
+	logging.V(logging.NodeManager, 4).Info("addNode NodeName: ", node.GetName(), ", UID: ", uuid)
 	nm.nodeRegUUIDMap[uuid] = node
 	nm.nodeRegInfoLock.Unlock()
 }
 
+// registeredNodeName returns the name of the registered node with the uuid,
+// or "" if there is none.
+func (nm *NodeManager) registeredNodeName(uuid string) string {
+	nm.nodeRegInfoLock.RLock()
+	defer nm.nodeRegInfoLock.RUnlock()
+	if node, ok := nm.nodeRegUUIDMap[uuid]; ok {
+		return node.Name
+	}
+	return ""
+}
+
 func (nm *NodeManager) removeNode(uuid string, node *v1.Node) {
 	nm.nodeRegInfoLock.Lock()
-	klog.V(4).Info("removeNode NodeName: ", node.GetName(), ", UID: ", uuid)
+	logging.V(logging.NodeManager, 4).Info("removeNode NodeName: ", node.GetName(), ", UID: ", uuid)
 	delete(nm.nodeRegUUIDMap, uuid)
 	nm.nodeRegInfoLock.Unlock()
 
 	nm.nodeInfoLock.Lock()
-	klog.V(4).Info("removeNode from UUID and Name cache. NodeName: ", node.GetName(), ", UID: ", uuid)
+	logging.V(logging.NodeManager, 4).Info("removeNode from UUID and Name cache. NodeName: ", node.GetName(), ", UID: ", uuid)
 	name := nm.getNodeNameByUUID(uuid)
 	if name != "" {
 		delete(nm.nodeNameMap, name)
 	} else {
-		klog.V(4).Info("node name: ", node.GetName(), " has a different uuid. Delete this node from cache, this could happen if VM is rebooted, and SystemUUID change.")
+		logging.V(logging.NodeManager, 4).Info("node name: ", node.GetName(), " has a different uuid. Delete this node from cache, this could happen if VM is rebooted, and SystemUUID change.")
 		delete(nm.nodeNameMap, node.GetName())
 	}
 	delete(nm.nodeUUIDMap, uuid)
+	delete(nm.vmNotFoundSince, uuid)
 	nm.nodeInfoLock.Unlock()
 }
 
-func (nm *NodeManager) shakeOutNodeIDLookup(ctx context.Context, nodeID string, searchBy cm.FindVM) (*cm.VMDiscoveryInfo, error) {
-	// Search by NodeName
-	if searchBy == cm.FindVMByName {
-		vmDI, err := nm.connectionManager.WhichVCandDCByNodeID(ctx, nodeID, cm.FindVM(searchBy))
-		if err == nil {
-			klog.Info("Discovered VM using FQDN or short-hand name")
-			return vmDI, nil
-		}
-
-		if err != vclib.ErrNoVMFound {
-			return nil, err
+// checkVMNotFoundGracePeriod records that the VM of the previously discovered
+// node with the uuid was not found, and returns ErrVMNotFoundWithinGracePeriod
+// until it has not been found for the VM not found grace period. The inventory
+// of vCenter lags behind right after a cross vCenter vMotion or a
+// re-registration, and the VM is found again once it caught up.
+func (nm *NodeManager) checkVMNotFoundGracePeriod(uuid string) error {
+	if nm.vmNotFoundGracePeriod <= 0 {
+		return nil
+	}
+	nm.nodeInfoLock.Lock()
+	defer nm.nodeInfoLock.Unlock()
+	since, ok := nm.vmNotFoundSince[uuid]
+	if !ok {
+		if nm.vmNotFoundSince == nil {
+			nm.vmNotFoundSince = make(map[string]time.Time)
 		}
+		since = time.Now()
+		nm.vmNotFoundSince[uuid] = since
+	}
+	if elapsed := time.Since(since); elapsed < nm.vmNotFoundGracePeriod {
+		return fmt.Errorf("%w: VM of node uid %q not found for %s of %s",
+			ErrVMNotFoundWithinGracePeriod, uuid, elapsed.Round(time.Second), nm.vmNotFoundGracePeriod)
+	}
+	return nil
+}
 
-		vmDI, err = nm.connectionManager.WhichVCandDCByNodeID(ctx, nodeID, cm.FindVMByIP)
-		if err == nil {
-			klog.Info("Discovered VM using IP address")
-			return vmDI, nil
-		}
+// resetVMNotFound forgets when the VM of the node with the uuid was first not
+// found, as it was found again.
+func (nm *NodeManager) resetVMNotFound(uuid string) {
+	nm.nodeInfoLock.Lock()
+	delete(nm.vmNotFoundSince, uuid)
+	nm.nodeInfoLock.Unlock()
+}
 
-		klog.Errorf("WhichVCandDCByNodeID failed using VM name. Err: %v", err)
-		return nil, err
+func (nm *NodeManager) shakeOutNodeIDLookup(ctx context.Context, nodeID string, searchBy cm.FindVM, pin cm.VCenterPin) (*cm.VMDiscoveryInfo, error) {
+	// Search by NodeName
+	if searchBy == cm.FindVMByName {
+		return nm.findVMByName(ctx, nodeID, pin)
 	}
 
 	// Search by UUID
-	vmDI, err := nm.connectionManager.WhichVCandDCByNodeID(ctx, nodeID, cm.FindVM(searchBy))
+	vmDI, err := nm.connectionManager.WhichVCandDCByNodeIDPinned(ctx, nodeID, cm.FindVM(searchBy), pin)
 	if err == nil {
 		klog.Info("Discovered VM using normal UUID format")
 		return vmDI, nil
@@ -176,7 +236,7 @@ func (nm *NodeManager) shakeOutNodeIDLookup(ctx context.Context, nodeID string,
 	// different from Photon 3, RHEL, CentOS, Ubuntu, and etc
 	klog.Errorf("WhichVCandDCByNodeID failed using normally formatted UUID. Err: %v", err)
 	reverseUUID := ConvertK8sUUIDtoNormal(nodeID)
-	vmDI, err = nm.connectionManager.WhichVCandDCByNodeID(ctx, reverseUUID, cm.FindVM(searchBy))
+	vmDI, err = nm.connectionManager.WhichVCandDCByNodeIDPinned(ctx, reverseUUID, cm.FindVM(searchBy), pin)
 	if err == nil {
 		klog.Info("Discovered VM using reverse UUID format")
 		return vmDI, nil
@@ -189,18 +249,44 @@ func (nm *NodeManager) shakeOutNodeIDLookup(ctx context.Context, nodeID string,
 type ipAddrNetworkName struct {
 	ipAddr      string
 	networkName string
+
+	// addr caches the parsed ipAddr, as it is matched against many subnets
+	addr   netip.Addr
+	parsed bool
+}
+
+// ip returns the parsed address, which is not valid if ipAddr is not an IP
+// address.
+func (c *ipAddrNetworkName) ip() netip.Addr {
+	if !c.parsed {
+		c.addr = parseAddr(c.ipAddr)
+		c.parsed = true
+	}
+	return c.addr
 }
 
-func (c *ipAddrNetworkName) ip() net.IP {
-	return net.ParseIP(c.ipAddr)
+func (c *ipAddrNetworkName) String() string {
+	return fmt.Sprintf("%s (network %q)", c.ipAddr, c.networkName)
 }
 
 // DiscoverNode finds a node's VM using the specified search value and search
 // type.
 func (nm *NodeManager) DiscoverNode(nodeID string, searchBy cm.FindVM) error {
+	nodeName := nodeID
+	if searchBy == cm.FindVMByUUID {
+		nodeName = nm.registeredNodeName(nodeID)
+	}
+	err := nm.discoverNode(nodeID, searchBy, nodeName)
+	nm.recordDiscoveryFailure(nodeName, err)
+	return err
+}
+
+// discoverNode finds a node's VM like DiscoverNode, nodeName is the name of
+// the Kubernetes node if it is known.
+func (nm *NodeManager) discoverNode(nodeID string, searchBy cm.FindVM, nodeName string) error {
 	ctx := context.Background()
 
-	vmDI, err := nm.shakeOutNodeIDLookup(ctx, nodeID, searchBy)
+	vmDI, err := nm.shakeOutNodeIDLookup(ctx, nodeID, searchBy, nm.vCenterPin(nodeName))
 	if err != nil {
 		klog.Errorf("shakeOutNodeIDLookup failed. Err=%v", err)
 		return err
@@ -210,32 +296,51 @@ func (nm *NodeManager) DiscoverNode(nodeID string, searchBy cm.FindVM) error {
 		return errors.New("discovered VM UUID is empty")
 	}
 
-	var oVM mo.VirtualMachine
-	err = vmDI.VM.Properties(ctx, vmDI.VM.Reference(), []string{"guest", "summary", "config"}, &oVM)
+	tenantRef := vmDI.VcServer
+	if vmDI.TenantRef != "" {
+		tenantRef = vmDI.TenantRef
+	}
+	vcInstance := nm.connectionManager.Instances()[tenantRef]
+
+	oVM := &mo.VirtualMachine{}
+	if vcInstance != nil {
+		oVM, err = vcInstance.VMProperties(ctx, vmDI.VM)
+	} else {
+		err = vmDI.VM.Properties(ctx, vmDI.VM.Reference(), cm.VMDiscoveryProperties, oVM)
+	}
 	if err != nil {
 		klog.Errorf("Error collecting properties for vm=%+v in vc=%s and datacenter=%s: %v",
 			vmDI.VM, vmDI.VcServer, vmDI.DataCenter.Name(), err)
 		return err
 	}
 
-	if oVM.Guest == nil {
-		return errors.New("VirtualMachine Guest property was nil")
+	// the guest is nil if none of its properties that are read is set
+	guest := oVM.Guest
+	if guest == nil {
+		guest = &types.GuestInfo{}
 	}
 
-	if oVM.Guest.HostName == "" {
-		return errors.New("VM Guest hostname is empty")
+	if guest.HostName == "" {
+		if searchBy != cm.FindVMByUUID || nm.cfg == nil || !nm.cfg.Nodes.AllowEmptyGuestHostname {
+			return errors.New("VM Guest hostname is empty")
+		}
+		logging.V(logging.NodeManager, 4).Infof("VM Guest hostname of node %s is empty, discovering it by UUID", nodeID)
 	}
 
-	if len(oVM.Guest.Net) == 0 {
-		klog.V(4).Infof("oVM.Guest.Net is empty, skipping node discovery. This could be cauesd by vmtool not reporting correct IP address")
-		return errors.New("VM GuestNicInfo is empty")
+	// NSX-T realizes the addresses of segment ports right after boot, while
+	// VMware Tools may take a while to report them.
+	var nsxtAddrs []*ipAddrNetworkName
+	if nm.nsxtBroker != nil && oVM.Config != nil {
+		nsxtAddrs, err = nm.discoverNSXTAddresses(oVM.Config.InstanceUuid, guest.Net)
+		if err != nil {
+			klog.Warningf("Failed to discover NSX-T addresses for vm=%+v, using VMware Tools only: %v", vmDI.VM, err)
+		}
 	}
 
-	tenantRef := vmDI.VcServer
-	if vmDI.TenantRef != "" {
-		tenantRef = vmDI.TenantRef
+	if len(guest.Net) == 0 && len(nsxtAddrs) == 0 {
+		logging.V(logging.NodeManager, 4).Infof("oVM.Guest.Net is empty, skipping node discovery. This could be cauesd by vmtool not reporting correct IP address")
+		return errGuestNicInfoEmpty
 	}
-	vcInstance := nm.connectionManager.VsphereInstanceMap[tenantRef]
 
 	ipFamilies := []string{vcfg.DefaultIPFamily}
 	if vcInstance != nil {
@@ -244,143 +349,173 @@ func (nm *NodeManager) DiscoverNode(nodeID string, searchBy cm.FindVM) error {
 		klog.Warningf("Unable to find vcInstance for %s. Defaulting to ipv4.", tenantRef)
 	}
 
-	var internalNetworkSubnets []*net.IPNet
-	var externalNetworkSubnets []*net.IPNet
-	var excludeInternalNetworkSubnets []*net.IPNet
-	var excludeExternalNetworkSubnets []*net.IPNet
-	var internalVMNetworkName string
-	var externalVMNetworkName string
-
-	if nm.cfg != nil {
-		internalNetworkSubnets, err = parseCIDRs(nm.cfg.Nodes.InternalNetworkSubnetCIDR)
-		if err != nil {
-			return err
-		}
-		externalNetworkSubnets, err = parseCIDRs(nm.cfg.Nodes.ExternalNetworkSubnetCIDR)
-		if err != nil {
-			return err
-		}
-		excludeInternalNetworkSubnets, err = parseCIDRs(nm.cfg.Nodes.ExcludeInternalNetworkSubnetCIDR)
-		if err != nil {
-			return err
-		}
-		excludeExternalNetworkSubnets, err = parseCIDRs(nm.cfg.Nodes.ExcludeExternalNetworkSubnetCIDR)
-		if err != nil {
-			return err
+	// the selectors are parsed once when the node manager is built
+	if nm.networksErr != nil {
+		return nm.networksErr
+	}
+	internalVMNetworkName := nm.networks.internalVMNetworkName
+	externalVMNetworkName := nm.networks.externalVMNetworkName
+
+	// the guest hostname may differ from the node name, or be empty
+	hostname := guest.HostName
+	guestHostname := true
+	name := vmDI.NodeName
+	if nodeName != "" && (name == "" || (nm.cfg != nil && nm.cfg.Nodes.UseNodeNameAsHostname)) {
+		name = nodeName
+		if nm.cfg != nil && nm.cfg.Nodes.UseNodeNameAsHostname {
+			hostname = nodeName
+			guestHostname = false
 		}
-		internalVMNetworkName = nm.cfg.Nodes.InternalVMNetworkName
-		externalVMNetworkName = nm.cfg.Nodes.ExternalVMNetworkName
+	}
+	if guestHostname && nm.cfg != nil && !nm.cfg.Nodes.GuestHostnamePublished() {
+		logging.V(logging.NodeManager, 4).Infof("Not publishing the guest hostname %q of node %s", hostname, name)
+		hostname = ""
+	} else if hostname != "" && name != "" && hostname != name {
+		klog.Warningf("Guest hostname %q of node %s differs from the node name and is published as its Hostname address, "+
+			"set publish-guest-hostname to false if it breaks the kubelet or webhook certificates", hostname, name)
 	}
 
 	addrs := []v1.NodeAddress{}
-	klog.V(2).Infof("Adding Hostname: %s", oVM.Guest.HostName)
-	v1helper.AddToNodeAddresses(&addrs,
-		v1.NodeAddress{
-			Type:    v1.NodeHostName,
-			Address: oVM.Guest.HostName,
-		},
-	)
-
-	nonVNICDevices := collectNonVNICDevices(oVM.Guest.Net)
+	if hostname != "" {
+		logging.V(logging.NodeManager, 2).Infof("Adding Hostname: %s", hostname)
+		v1helper.AddToNodeAddresses(&addrs,
+			v1.NodeAddress{
+				Type:    v1.NodeHostName,
+				Address: hostname,
+			},
+		)
+	}
+
+	nonVNICDevices := collectNonVNICDevices(guest.Net)
 	for _, v := range nonVNICDevices {
-		klog.V(6).Infof("internalVMNetworkName = %s", internalVMNetworkName)
-		klog.V(6).Infof("externalVMNetworkName = %s", externalVMNetworkName)
-		klog.V(6).Infof("v.Network = %s", v.Network)
+		logging.V(logging.NodeManager, 6).Infof("internalVMNetworkName = %s", internalVMNetworkName)
+		logging.V(logging.NodeManager, 6).Infof("externalVMNetworkName = %s", externalVMNetworkName)
+		logging.V(logging.NodeManager, 6).Infof("v.Network = %s", v.Network)
 
-		if (internalVMNetworkName != "" && !strings.EqualFold(internalVMNetworkName, v.Network)) &&
-			(externalVMNetworkName != "" && !strings.EqualFold(externalVMNetworkName, v.Network)) {
-			klog.V(4).Infof("Skipping device because vNIC Network=%s doesn't match internal=%s or external=%s network names",
+		if (internalVMNetworkName != nil && !internalVMNetworkName.matches(v.Network)) &&
+			(externalVMNetworkName != nil && !externalVMNetworkName.matches(v.Network)) {
+			logging.V(logging.NodeManager, 4).Infof("Skipping device because vNIC Network=%s doesn't match internal=%s or external=%s network names",
 				v.Network, internalVMNetworkName, externalVMNetworkName)
 		}
 	}
 
 	existingNetworkNames := toNetworkNames(nonVNICDevices)
-	if internalVMNetworkName != "" && externalVMNetworkName != "" {
-		if !ArrayContainsCaseInsensitive(existingNetworkNames, internalVMNetworkName) &&
-			!ArrayContainsCaseInsensitive(existingNetworkNames, externalVMNetworkName) {
-			return fmt.Errorf("unable to find suitable IP address for node")
+	if internalVMNetworkName != nil && externalVMNetworkName != nil {
+		if !internalVMNetworkName.matchesAny(existingNetworkNames) &&
+			!externalVMNetworkName.matchesAny(existingNetworkNames) {
+			return errNoSuitableIPAddress
 		}
 	}
 
 	ipAddrNetworkNames := toIPAddrNetworkNames(nonVNICDevices)
+	if nm.cfg != nil && nm.cfg.Nodes.NSXAddressSource == ccfg.NSXAddressSourcePrefer {
+		ipAddrNetworkNames = mergeIPAddrNetworkNames(nsxtAddrs, ipAddrNetworkNames)
+	} else {
+		ipAddrNetworkNames = mergeIPAddrNetworkNames(ipAddrNetworkNames, nsxtAddrs)
+	}
 	nonLocalhostIPs := excludeLocalhostIPs(ipAddrNetworkNames)
 
 	if len(nonLocalhostIPs) == 0 {
-		klog.V(4).Infof("nonLocalhostIPs is empty")
-		klog.V(4).Infof("oVM.Guest.Net=%v", oVM.Guest.Net)
-		return fmt.Errorf("unable to find suitable IP address for node after filtering out localhost IPs")
+		logging.V(logging.NodeManager, 4).Infof("nonLocalhostIPs is empty")
+		logging.V(logging.NodeManager, 4).Infof("oVM.Guest.Net=%v", guest.Net)
+		return fmt.Errorf("%w after filtering out localhost IPs", errNoSuitableIPAddress)
 	}
 
-	sortedNonLocalhostIPs, err := sortStaticallyConfiguredAddressesFirst(oVM.Config.ExtraConfig, nonLocalhostIPs)
+	maxGuestInfoSize := int64(ccfg.DefaultMaxGuestInfoSize)
+	if nm.cfg != nil {
+		maxGuestInfoSize = nm.cfg.Nodes.MaxGuestInfoSizeOrDefault()
+	}
+	sortedNonLocalhostIPs, err := sortStaticallyConfiguredAddressesFirst(oVM.Config.ExtraConfig, nonLocalhostIPs, maxGuestInfoSize)
 	if err != nil {
+		err = newGuestInfoError(name, vmDI, err)
 		klog.Errorf("Error sorting statically configured addresses for vm=%+v in vc=%s and datacenter=%s: %v",
 			vmDI.VM, vmDI.VcServer, vmDI.DataCenter.Name(), err)
 		return err
 	}
 
+	suppressExternalIP := nm.cfg != nil && nm.cfg.Nodes.SuppressExternalIP
+	publishAllMatchingIPs := nm.cfg != nil && nm.cfg.Nodes.PublishAllMatchingIPs
+	annotatedInternal, annotatedExternal := nm.nodeIPAnnotations(nodeName)
+	if suppressExternalIP {
+		annotatedExternal = ""
+	}
 	for _, ipFamily := range ipFamilies {
-		klog.V(6).Infof("ipFamily: %q nonLocalhostIPs: %v", ipFamily, sortedNonLocalhostIPs)
+		logging.V(logging.NodeManager, 6).Infof("ipFamily: %q nonLocalhostIPs: %v", ipFamily, sortedNonLocalhostIPs)
 		discoveredInternal, discoveredExternal := discoverIPs(
 			sortedNonLocalhostIPs,
 			ipFamily,
-			internalNetworkSubnets,
-			externalNetworkSubnets,
-			excludeInternalNetworkSubnets,
-			excludeExternalNetworkSubnets,
+			nm.networks.internalNetworkSubnets,
+			nm.networks.externalNetworkSubnets,
+			nm.networks.excludeInternalNetworkSubnets,
+			nm.networks.excludeExternalNetworkSubnets,
 			internalVMNetworkName,
 			externalVMNetworkName,
+			suppressExternalIP,
 		)
 
-		klog.V(6).Infof("ipFamily: %q discovered Internal: %q discoveredExternal: %q",
+		if !publishAllMatchingIPs {
+			discoveredInternal = firstIPAddrNetworkName(discoveredInternal)
+			discoveredExternal = firstIPAddrNetworkName(discoveredExternal)
+		}
+
+		logging.V(logging.NodeManager, 6).Infof("ipFamily: %q discovered Internal: %q discoveredExternal: %q",
 			ipFamily, discoveredInternal, discoveredExternal)
 
-		if discoveredInternal != nil {
+		// the addresses annotated on the node take precedence over the
+		// discovered ones, if they are addresses of the VM
+		if ip := nm.annotatedIP(nodeName, AnnotationInternalIP, annotatedInternal, sortedNonLocalhostIPs, ipFamily); ip != nil {
+			discoveredInternal = []*ipAddrNetworkName{ip}
+		}
+		if ip := nm.annotatedIP(nodeName, AnnotationExternalIP, annotatedExternal, sortedNonLocalhostIPs, ipFamily); ip != nil {
+			discoveredExternal = []*ipAddrNetworkName{ip}
+		}
+
+		for _, ip := range discoveredInternal {
 			v1helper.AddToNodeAddresses(&addrs,
-				v1.NodeAddress{Type: v1.NodeInternalIP, Address: discoveredInternal.ipAddr},
+				v1.NodeAddress{Type: v1.NodeInternalIP, Address: ip.ipAddr},
 			)
 		}
 
-		if discoveredExternal != nil {
+		for _, ip := range discoveredExternal {
 			v1helper.AddToNodeAddresses(&addrs,
-				v1.NodeAddress{Type: v1.NodeExternalIP, Address: discoveredExternal.ipAddr},
+				v1.NodeAddress{Type: v1.NodeExternalIP, Address: ip.ipAddr},
 			)
 		}
 
-		if len(oVM.Guest.Net) > 0 {
-			if discoveredInternal == nil && discoveredExternal == nil {
-				klog.V(4).Infof("oVM.Guest.Net=%v", oVM.Guest.Net)
-				return fmt.Errorf("unable to find suitable IP address for node %s with IP family %s", nodeID, ipFamilies)
+		if len(guest.Net) > 0 {
+			if len(discoveredInternal) == 0 && len(discoveredExternal) == 0 {
+				logging.V(logging.NodeManager, 4).Infof("oVM.Guest.Net=%v", guest.Net)
+				return fmt.Errorf("%w %s with IP family %s", errNoSuitableIPAddress, nodeID, ipFamilies)
 			}
 		}
 	}
 
-	klog.V(2).Infof("Found node %s as vm=%+v in vc=%s and datacenter=%s",
-		nodeID, vmDI.VM, vmDI.VcServer, vmDI.DataCenter.Name())
-	klog.V(2).Info("Hostname: ", oVM.Guest.HostName, " UUID: ", vmDI.UUID)
-
-	os := "unknown"
-	if g, ok := GuestOSLookup[oVM.Summary.Config.GuestId]; ok {
-		os = g
+	addrs, err = nm.reviewAddresses(ctx, name, vmDI, sortedNonLocalhostIPs, addrs)
+	if err != nil {
+		return err
 	}
 
+	logging.V(logging.NodeManager, 2).Infof("Found node %s as vm=%+v in vc=%s and datacenter=%s",
+		nodeID, vmDI.VM, vmDI.VcServer, vmDI.DataCenter.Name())
+	logging.V(logging.NodeManager, 2).Info("Hostname: ", hostname, " UUID: ", vmDI.UUID)
+
 	// store instance type in nodeinfo map
-	instanceType := fmt.Sprintf("vsphere-vm.cpu-%d.mem-%dgb.os-%s",
-		oVM.Summary.Config.NumCpu,
-		(oVM.Summary.Config.MemorySizeMB / 1024),
-		os,
-	)
+	instanceType := instanceTypeFromConfig(oVM.Summary.Config)
 
 	nodeInfo := &NodeInfo{
 		tenantRef: tenantRef, dataCenter: vmDI.DataCenter, vm: vmDI.VM, vcServer: vmDI.VcServer,
-		UUID: vmDI.UUID, NodeName: vmDI.NodeName, NodeType: instanceType, NodeAddresses: addrs,
+		UUID: vmDI.UUID, NodeName: name, NodeType: instanceType, NodeAddresses: addrs,
+		nodeTypeTime:  time.Now(),
+		vmAnnotations: vmMetadataAnnotations(oVM.Config, nm.sourceTemplateKey()),
 	}
 	nm.addNodeInfo(nodeInfo)
 
 	return nil
 }
 
-// discoverIPs returns a pair of *ipAddrNetworkNames. The first representing
-// the internal network IP and the second being the external network IP.
+// discoverIPs returns a pair of []*ipAddrNetworkNames. The first representing
+// the internal network IPs and the second being the external network IPs,
+// most preferred first.
 //
 // The returned ipAddrNetworkNames will match the given ipFamily.
 //
@@ -391,76 +526,87 @@ func (nm *NodeManager) DiscoverNode(nodeID string, searchBy cm.FindVM) error {
 //
 // The returned ipAddrNetworkNames will be selected first by attempting to
 // match the given internalNetworkSubnets and externalNetworkSubnets. Subnet
-// matching has the highest precedence.
+// matching has the highest precedence. Every address matching a subnet is
+// returned, ordered by the first subnet it matches.
 //
 // If subnet matches are not found, or if subnets are not provided, then an
 // attempt is made to select ipAddrNetworkNames that match the given network
-// names. Network name matching has the second highest precedence.
+// names. Network name matching has the second highest precedence. Every
+// address of a matching network is returned.
 //
 // If ipAddrNetworkNames are not found by subnet nor network name matching, then
 // the first ipAddrNetworkName of the desired family is returned as both the
 // internal and external matches.
 //
-// If either of these IPs cannot be discovered, nil will be returned instead.
+// If suppressExternal is set, no external network IP is returned and the
+// external subnets and network names are ignored, so that they neither select
+// an address nor prevent the default selection of the internal one.
+//
+// If either of these IPs cannot be discovered, an empty slice will be
+// returned instead.
 func discoverIPs(ipAddrNetworkNames []*ipAddrNetworkName, ipFamily string,
 	internalNetworkSubnets, externalNetworkSubnets,
-	excludeInternalNetworkSubnets, excludeExternalNetworkSubnets []*net.IPNet,
-	internalVMNetworkName, externalVMNetworkName string,
-) (internal *ipAddrNetworkName, external *ipAddrNetworkName) {
+	excludeInternalNetworkSubnets, excludeExternalNetworkSubnets []netip.Prefix,
+	internalVMNetworkName, externalVMNetworkName *networkNameMatcher,
+	suppressExternal bool,
+) (internal []*ipAddrNetworkName, external []*ipAddrNetworkName) {
 	ipFamilyMatches := collectMatchesForIPFamily(ipAddrNetworkNames, ipFamily)
 
-	var discoveredInternal *ipAddrNetworkName
-	var discoveredExternal *ipAddrNetworkName
+	var discoveredInternal []*ipAddrNetworkName
+	var discoveredExternal []*ipAddrNetworkName
 
 	filteredInternalMatches := filterSubnetExclusions(ipFamilyMatches, excludeInternalNetworkSubnets)
-	filteredExternalMatches := filterSubnetExclusions(ipFamilyMatches, excludeExternalNetworkSubnets)
+	var filteredExternalMatches []*ipAddrNetworkName
+	if !suppressExternal {
+		filteredExternalMatches = filterSubnetExclusions(ipFamilyMatches, excludeExternalNetworkSubnets)
+	}
 
 	if len(filteredInternalMatches) > 0 || len(filteredExternalMatches) > 0 {
-		discoveredInternal = findSubnetMatch(filteredInternalMatches, internalNetworkSubnets)
-		if discoveredInternal != nil {
-			klog.V(2).Infof("Adding Internal IP by AddressMatching: %s", discoveredInternal.ipAddr)
+		discoveredInternal = findSubnetMatches(filteredInternalMatches, internalNetworkSubnets)
+		for _, ip := range discoveredInternal {
+			logging.V(logging.NodeManager, 2).Infof("Adding Internal IP by AddressMatching: %s", ip.ipAddr)
 		}
-		discoveredExternal = findSubnetMatch(filteredExternalMatches, externalNetworkSubnets)
-		if discoveredExternal != nil {
-			klog.V(2).Infof("Adding External IP by AddressMatching: %s", discoveredExternal.ipAddr)
+		discoveredExternal = findSubnetMatches(filteredExternalMatches, externalNetworkSubnets)
+		for _, ip := range discoveredExternal {
+			logging.V(logging.NodeManager, 2).Infof("Adding External IP by AddressMatching: %s", ip.ipAddr)
 		}
 
-		if discoveredInternal == nil && internalVMNetworkName != "" {
-			discoveredInternal = findNetworkNameMatch(filteredInternalMatches, internalVMNetworkName)
-			if discoveredInternal != nil {
-				klog.V(2).Infof("Adding Internal IP by NetworkName: %s", discoveredInternal.ipAddr)
+		if len(discoveredInternal) == 0 && internalVMNetworkName != nil {
+			discoveredInternal = findNetworkNameMatches(filteredInternalMatches, internalVMNetworkName)
+			for _, ip := range discoveredInternal {
+				logging.V(logging.NodeManager, 2).Infof("Adding Internal IP by NetworkName: %s", ip.ipAddr)
 			}
 		}
 
-		if discoveredExternal == nil && externalVMNetworkName != "" {
-			discoveredExternal = findNetworkNameMatch(filteredExternalMatches, externalVMNetworkName)
-			if discoveredExternal != nil {
-				klog.V(2).Infof("Adding External IP by NetworkName: %s", discoveredExternal.ipAddr)
+		if len(discoveredExternal) == 0 && externalVMNetworkName != nil {
+			discoveredExternal = findNetworkNameMatches(filteredExternalMatches, externalVMNetworkName)
+			for _, ip := range discoveredExternal {
+				logging.V(logging.NodeManager, 2).Infof("Adding External IP by NetworkName: %s", ip.ipAddr)
 			}
 		}
 
 		// Neither internal or external addresses were found. This defaults to the legacy
 		// address selection behavior which is to only support a single address and
 		// return the first one found
-		if discoveredInternal == nil && discoveredExternal == nil {
-			klog.V(5).Info("Default address selection.")
+		if len(discoveredInternal) == 0 && len(discoveredExternal) == 0 {
+			logging.V(logging.NodeManager, 5).Info("Default address selection.")
 			if len(filteredInternalMatches) > 0 {
-				klog.V(2).Infof("Adding Internal IP: %s", filteredInternalMatches[0].ipAddr)
-				discoveredInternal = filteredInternalMatches[0]
+				logging.V(logging.NodeManager, 2).Infof("Adding Internal IP: %s", filteredInternalMatches[0].ipAddr)
+				discoveredInternal = filteredInternalMatches[:1]
 			}
 
 			if len(filteredExternalMatches) > 0 {
-				klog.V(2).Infof("Adding External IP: %s", filteredExternalMatches[0].ipAddr)
-				discoveredExternal = filteredExternalMatches[0]
+				logging.V(logging.NodeManager, 2).Infof("Adding External IP: %s", filteredExternalMatches[0].ipAddr)
+				discoveredExternal = filteredExternalMatches[:1]
 			}
 		} else {
 			// At least one of the Internal or External addresses has been found.
 			// Minimally the Internal needs to exist for the node to function correctly.
 			// If only one was discovered, will log the warning and continue which will
 			// ultimately be visible to the end user
-			if discoveredInternal != nil && discoveredExternal == nil {
+			if len(discoveredInternal) > 0 && len(discoveredExternal) == 0 && !suppressExternal {
 				klog.Warning("Internal address found, but external address not found. Returning what addresses were discovered.")
-			} else if discoveredInternal == nil && discoveredExternal != nil {
+			} else if len(discoveredInternal) == 0 && len(discoveredExternal) > 0 {
 				klog.Warning("External address found, but internal address not found. Returning what addresses were discovered.")
 			}
 		}
@@ -468,13 +614,21 @@ func discoverIPs(ipAddrNetworkNames []*ipAddrNetworkName, ipFamily string,
 	return discoveredInternal, discoveredExternal
 }
 
+// firstIPAddrNetworkName returns the first of ipAddrNetworkNames, if any.
+func firstIPAddrNetworkName(ipAddrNetworkNames []*ipAddrNetworkName) []*ipAddrNetworkName {
+	if len(ipAddrNetworkNames) > 1 {
+		return ipAddrNetworkNames[:1]
+	}
+	return ipAddrNetworkNames
+}
+
 // collectNonVNICDevices filters out NICs that are virtual NIC devices. The IPs of
 // these NICs should not be added to the node status.
 func collectNonVNICDevices(guestNicInfos []types.GuestNicInfo) []types.GuestNicInfo {
 	var toReturn []types.GuestNicInfo
 	for _, v := range guestNicInfos {
 		if v.DeviceConfigId == -1 {
-			klog.V(4).Info("Skipping device because not a vNIC")
+			logging.V(logging.NodeManager, 4).Info("Skipping device because not a vNIC")
 			continue
 		}
 		toReturn = append(toReturn, v)
@@ -482,30 +636,146 @@ func collectNonVNICDevices(guestNicInfos []types.GuestNicInfo) []types.GuestNicI
 	return toReturn
 }
 
+// nodeNetworks holds the subnets and the VM network names selecting the
+// internal and external addresses of the nodes.
+type nodeNetworks struct {
+	internalNetworkSubnets        []netip.Prefix
+	externalNetworkSubnets        []netip.Prefix
+	excludeInternalNetworkSubnets []netip.Prefix
+	excludeExternalNetworkSubnets []netip.Prefix
+	internalVMNetworkName         *networkNameMatcher
+	externalVMNetworkName         *networkNameMatcher
+}
+
+// newNodeNetworks parses the subnets and compiles the VM network name
+// patterns of the Nodes section.
+func newNodeNetworks(nodes *ccfg.Nodes) (networks nodeNetworks, err error) {
+	if networks.internalNetworkSubnets, err = parseCIDRs(nodes.InternalNetworkSubnetCIDR); err != nil {
+		return nodeNetworks{}, err
+	}
+	if networks.externalNetworkSubnets, err = parseCIDRs(nodes.ExternalNetworkSubnetCIDR); err != nil {
+		return nodeNetworks{}, err
+	}
+	if networks.excludeInternalNetworkSubnets, err = parseCIDRs(nodes.ExcludeInternalNetworkSubnetCIDR); err != nil {
+		return nodeNetworks{}, err
+	}
+	if networks.excludeExternalNetworkSubnets, err = parseCIDRs(nodes.ExcludeExternalNetworkSubnetCIDR); err != nil {
+		return nodeNetworks{}, err
+	}
+	if networks.internalVMNetworkName, err = newNetworkNameMatcher(nodes.InternalVMNetworkName, nodes.InternalVMNetworkNamePattern); err != nil {
+		return nodeNetworks{}, err
+	}
+	if networks.externalVMNetworkName, err = newNetworkNameMatcher(nodes.ExternalVMNetworkName, nodes.ExternalVMNetworkNamePattern); err != nil {
+		return nodeNetworks{}, err
+	}
+	return networks, nil
+}
+
 // parseCIDRs converts a comma delimited string of CIDRs to
-// a slice of IPNet pointers.
-func parseCIDRs(cidrsString string) ([]*net.IPNet, error) {
+// a slice of masked prefixes.
+func parseCIDRs(cidrsString string) ([]netip.Prefix, error) {
 	if cidrsString != "" {
 		cidrStringSlice := strings.Split(cidrsString, ",")
-		subnets := make([]*net.IPNet, len(cidrStringSlice))
+		subnets := make([]netip.Prefix, len(cidrStringSlice))
 		for i, cidrString := range cidrStringSlice {
-			_, ipNet, err := net.ParseCIDR(cidrString)
+			prefix, err := netip.ParsePrefix(cidrString)
 			if err != nil {
-				return nil, err
+				return nil, &net.ParseError{Type: "CIDR address", Text: cidrString}
 			}
-			subnets[i] = ipNet
+			subnets[i] = prefix.Masked()
 		}
 		return subnets, nil
 	}
 	return nil, nil
 }
 
+// parseAddr parses an IP address the way net.ParseIP does: IPv4-mapped IPv6
+// addresses are IPv4 addresses and addresses with a zone are invalid.
+func parseAddr(s string) netip.Addr {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || addr.Zone() != "" {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// networkNameMatcher matches the network names reported by VMware Tools
+// against a configured VM network name and an optional regular expression.
+type networkNameMatcher struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// newNetworkNameMatcher returns a matcher for the given network name and
+// pattern, or nil if neither is set.
+func newNetworkNameMatcher(name, pattern string) (*networkNameMatcher, error) {
+	if name == "" && pattern == "" {
+		return nil, nil
+	}
+	m := &networkNameMatcher{name: name}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VM network name pattern %q: %v", pattern, err)
+		}
+		m.pattern = re
+	}
+	return m, nil
+}
+
+// matches reports whether the network name equals the configured name, as
+// defined by networkNamesMatch, or matches the configured pattern once
+// normalized.
+func (m *networkNameMatcher) matches(networkName string) bool {
+	if m == nil {
+		return false
+	}
+	if m.name != "" && networkNamesMatch(m.name, networkName) {
+		return true
+	}
+	return m.pattern != nil && m.pattern.MatchString(normalizeName(networkName))
+}
+
+// matchesAny reports whether any of the network names matches.
+func (m *networkNameMatcher) matchesAny(networkNames []string) bool {
+	for _, networkName := range networkNames {
+		if m.matches(networkName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *networkNameMatcher) String() string {
+	switch {
+	case m == nil:
+		return ""
+	case m.pattern == nil:
+		return m.name
+	case m.name == "":
+		return "/" + m.pattern.String() + "/"
+	default:
+		return fmt.Sprintf("%s or /%s/", m.name, m.pattern)
+	}
+}
+
 // toIPAddrNetworkNames maps an array of GuestNicInfo to and array of *ipAddrNetworkName.
 func toIPAddrNetworkNames(guestNicInfos []types.GuestNicInfo) []*ipAddrNetworkName {
-	var candidates []*ipAddrNetworkName
+	var count int
+	for _, v := range guestNicInfos {
+		count += len(v.IpAddress)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	// allocate the candidates at once
+	values := make([]ipAddrNetworkName, 0, count)
+	candidates := make([]*ipAddrNetworkName, 0, count)
 	for _, v := range guestNicInfos {
 		for _, ip := range v.IpAddress {
-			candidates = append(candidates, &ipAddrNetworkName{ipAddr: ip, networkName: v.Network})
+			values = append(values, ipAddrNetworkName{ipAddr: ip, networkName: v.Network})
+			candidates = append(candidates, &values[len(values)-1])
 		}
 	}
 	return candidates
@@ -529,13 +799,13 @@ func collectMatchesForIPFamily(ipAddrNetworkNames []*ipAddrNetworkName, ipFamily
 }
 
 // matchesFamily detects whether a given IP matches the given IP family.
-func matchesFamily(ip net.IP, ipFamily string) bool {
+func matchesFamily(ip netip.Addr, ipFamily string) bool {
 	if ipFamily == vcfg.IPv6Family {
-		return ip.To4() == nil && ip.To16() != nil
+		return ip.Is6()
 	}
 
 	if ipFamily == vcfg.IPv4Family {
-		return ip.To4() != nil
+		return ip.Is4()
 	}
 
 	return false
@@ -545,6 +815,9 @@ func matchesFamily(ip net.IP, ipFamily string) bool {
 // items in the collection pass the given predicate function.
 func filter(ipAddrNetworkNames []*ipAddrNetworkName, predicate func(*ipAddrNetworkName) bool) []*ipAddrNetworkName {
 	var filtered []*ipAddrNetworkName
+	if len(ipAddrNetworkNames) != 0 {
+		filtered = make([]*ipAddrNetworkName, 0, len(ipAddrNetworkNames))
+	}
 	for _, item := range ipAddrNetworkNames {
 		if predicate(item) {
 			filtered = append(filtered, item)
@@ -555,7 +828,7 @@ func filter(ipAddrNetworkNames []*ipAddrNetworkName, predicate func(*ipAddrNetwo
 
 // findSubnetMatch finds the first *ipAddrNetworkName that has an IP in the
 // given network subnets.
-func findSubnetMatch(ipAddrNetworkNames []*ipAddrNetworkName, networkSubnets []*net.IPNet) *ipAddrNetworkName {
+func findSubnetMatch(ipAddrNetworkNames []*ipAddrNetworkName, networkSubnets []netip.Prefix) *ipAddrNetworkName {
 	for _, networkSubnet := range networkSubnets {
 		match := findFirst(ipAddrNetworkNames, func(candidate *ipAddrNetworkName) bool {
 			return networkSubnet.Contains(candidate.ip())
@@ -568,12 +841,39 @@ func findSubnetMatch(ipAddrNetworkNames []*ipAddrNetworkName, networkSubnets []*
 	return nil
 }
 
-// findNetworkNameMatch finds the first *ipAddrNetworkName that matches the
-// given network name, ignoring case.
-func findNetworkNameMatch(ipAddrNetworkNames []*ipAddrNetworkName, networkName string) *ipAddrNetworkName {
-	if networkName != "" {
+// findSubnetMatches finds every *ipAddrNetworkName that has an IP in the
+// given network subnets, ordered by the first subnet it is in.
+func findSubnetMatches(ipAddrNetworkNames []*ipAddrNetworkName, networkSubnets []netip.Prefix) []*ipAddrNetworkName {
+	var matches []*ipAddrNetworkName
+	matched := make(map[*ipAddrNetworkName]bool)
+	for _, networkSubnet := range networkSubnets {
+		for _, candidate := range ipAddrNetworkNames {
+			if !matched[candidate] && networkSubnet.Contains(candidate.ip()) {
+				matched[candidate] = true
+				matches = append(matches, candidate)
+			}
+		}
+	}
+	return matches
+}
+
+// findNetworkNameMatches finds every *ipAddrNetworkName whose network name is
+// matched by the given matcher.
+func findNetworkNameMatches(ipAddrNetworkNames []*ipAddrNetworkName, networkName *networkNameMatcher) []*ipAddrNetworkName {
+	if networkName == nil {
+		return nil
+	}
+	return filter(ipAddrNetworkNames, func(candidate *ipAddrNetworkName) bool {
+		return networkName.matches(candidate.networkName)
+	})
+}
+
+// findNetworkNameMatch finds the first *ipAddrNetworkName whose network name
+// is matched by the given matcher.
+func findNetworkNameMatch(ipAddrNetworkNames []*ipAddrNetworkName, networkName *networkNameMatcher) *ipAddrNetworkName {
+	if networkName != nil {
 		return findFirst(ipAddrNetworkNames, func(candidate *ipAddrNetworkName) bool {
-			return strings.EqualFold(networkName, candidate.networkName)
+			return networkName.matches(candidate.networkName)
 		})
 	}
 	return nil
@@ -594,19 +894,19 @@ func findFirst(ipAddrNetworkNames []*ipAddrNetworkName, predicate func(*ipAddrNe
 // node status.
 func excludeLocalhostIPs(ipAddrNetworkNames []*ipAddrNetworkName) []*ipAddrNetworkName {
 	return filter(ipAddrNetworkNames, func(i *ipAddrNetworkName) bool {
-		err := ErrOnLocalOnlyIPAddr(i.ipAddr)
+		err := errOnLocalOnlyAddr(i.ipAddr, i.ip())
 		if err != nil {
-			klog.V(4).Infof("IP is local only or there was an error. ip=%q err=%v", i.ipAddr, err)
+			logging.V(logging.NodeManager, 4).Infof("IP is local only or there was an error. ip=%q err=%v", i.ipAddr, err)
 		}
 		return err == nil
 	})
 }
 
-func filterSubnetExclusions(ipAddrNetworkNames []*ipAddrNetworkName, exlusionSubnets []*net.IPNet) []*ipAddrNetworkName {
+func filterSubnetExclusions(ipAddrNetworkNames []*ipAddrNetworkName, exlusionSubnets []netip.Prefix) []*ipAddrNetworkName {
 	return filter(ipAddrNetworkNames, func(i *ipAddrNetworkName) bool {
 		for _, exlusionSubnet := range exlusionSubnets {
 			if exlusionSubnet.Contains(i.ip()) {
-				klog.V(4).Infof("IP is excluded %q because it is contained in exlusion subnet %q", i.ipAddr, exlusionSubnet.String())
+				logging.V(logging.NodeManager, 4).Infof("IP is excluded %q because it is contained in exlusion subnet %q", i.ipAddr, exlusionSubnet.String())
 				return false
 			}
 		}
@@ -668,7 +968,7 @@ func (nm *NodeManager) FindNodeInfo(UUID string) (*NodeInfo, error) {
 		return nil, ErrVMNotFound
 	}
 
-	klog.V(4).Infof("FindNodeInfo( %s ) FOUND", UUIDlower)
+	logging.V(logging.NodeManager, 4).Infof("FindNodeInfo( %s ) FOUND", UUIDlower)
 	return nodeInfo, nil
 }
 
@@ -695,18 +995,76 @@ func guestInfoMetadata(extraConfig []types.BaseOptionValue) (string, string) {
 	return guestInfo, encoding
 }
 
+// errUnsupportedGuestInfoEncoding is returned by decodeGuestInfo for an
+// encoding that is not a cloud-init guestinfo encoding.
+var errUnsupportedGuestInfoEncoding = errors.New("unsupported guestinfo encoding")
+
+// decodeGuestInfo decodes data published with a cloud-init guestinfo
+// encoding. The data is raw when the encoding is empty. errGuestInfoTooLarge
+// is returned if the decoded data is larger than maxSize bytes, without
+// decompressing more than that.
+func decodeGuestInfo(data, encoding string, maxSize int64) ([]byte, error) {
+	var value []byte
+	switch encoding {
+	case "":
+		value = []byte(data)
+	case "base64", "b64":
+		var err error
+		if value, err = base64.StdEncoding.DecodeString(data); err != nil {
+			return nil, err
+		}
+	case "gzip+base64", "gz+b64":
+		gzData, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, err
+		}
+
+		gr, err := gzip.NewReader(bytes.NewReader(gzData))
+		if err != nil {
+			return nil, err
+		}
+
+		// reading one more byte than allowed tells too large data apart
+		value, err = io.ReadAll(io.LimitReader(gr, maxSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(value)) > maxSize {
+			return nil, fmt.Errorf("%w: decompressed data exceeds %d bytes", errGuestInfoTooLarge, maxSize)
+		}
+
+		if err := gr.Close(); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%w %q", errUnsupportedGuestInfoEncoding, encoding)
+	}
+	if int64(len(value)) > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes exceed %d bytes", errGuestInfoTooLarge, len(value), maxSize)
+	}
+	return value, nil
+}
+
 // sortStaticallyConfiguredAddressesFirst prefers addresses that are from the
 // guestInfo but only if they are on a NIC already. It preserves the order in which
 // the addresses appear in the guestInfo. For addresses not found in the guestInfo,
 // it preserves the order in which they appear in nonlocalhostIPs.
-func sortStaticallyConfiguredAddressesFirst(extraConfig []types.BaseOptionValue, nonLocalhostIPs []*ipAddrNetworkName) ([]*ipAddrNetworkName, error) {
+//
+// The metadata and its network key may both be raw, base64 or gzip+base64
+// encoded, and are rejected once decoded to more than maxSize bytes.
+func sortStaticallyConfiguredAddressesFirst(extraConfig []types.BaseOptionValue, nonLocalhostIPs []*ipAddrNetworkName, maxSize int64) ([]*ipAddrNetworkName, error) {
 	guestInfo, encoding := guestInfoMetadata(extraConfig)
 
-	if guestInfo == "" || encoding != "base64" {
+	if guestInfo == "" {
 		return nonLocalhostIPs, nil
 	}
 
-	value, err := base64.StdEncoding.DecodeString(guestInfo)
+	value, err := decodeGuestInfo(guestInfo, encoding, maxSize)
+	if errors.Is(err, errUnsupportedGuestInfoEncoding) {
+		logging.V(logging.NodeManager, 4).Infof("Ignoring guestinfo.metadata: %v", err)
+		return nonLocalhostIPs, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -720,41 +1078,13 @@ func sortStaticallyConfiguredAddressesFirst(extraConfig []types.BaseOptionValue,
 
 	var netConfig networkConfig
 	switch ne.NetworkEncoding {
-	case "base64", "b64":
-		var encNetconfig encodedCloudInitConfig
-		if err := yaml.Unmarshal(value, &encNetconfig); err != nil {
-			return nil, err
-		}
-
-		if value, err = base64.StdEncoding.DecodeString(encNetconfig.Network); err != nil {
-			return nil, err
-		}
-
-		if err := yaml.Unmarshal(value, &netConfig); err != nil {
-			return nil, err
-		}
-	case "gzip+base64", "gz+b64":
+	case "base64", "b64", "gzip+base64", "gz+b64":
 		var encNetconfig encodedCloudInitConfig
 		if err := yaml.Unmarshal(value, &encNetconfig); err != nil {
 			return nil, err
 		}
 
-		gzData, err := base64.StdEncoding.DecodeString(encNetconfig.Network)
-		if err != nil {
-			return nil, err
-		}
-
-		r := bytes.NewReader(gzData)
-		gr, err := gzip.NewReader(r)
-		if err != nil {
-			return nil, err
-		}
-
-		if value, err = io.ReadAll(gr); err != nil {
-			return nil, err
-		}
-
-		if err := gr.Close(); err != nil {
+		if value, err = decodeGuestInfo(encNetconfig.Network, ne.NetworkEncoding, maxSize); err != nil {
 			return nil, err
 		}
 
@@ -771,11 +1101,9 @@ func sortStaticallyConfiguredAddressesFirst(extraConfig []types.BaseOptionValue,
 
 	// Map of guestInfo IP -> index that describes the order they appear in the guestInfo
 	guestInfoAddresses := make(map[string]int)
-	for _, eth := range netConfig.Ethernets {
-		for _, address := range eth.Addresses {
-			ip := net.ParseIP(strings.Split(address, "/")[0])
-			guestInfoAddresses[ip.String()] = len(guestInfoAddresses)
-		}
+	for _, address := range netConfig.staticAddresses() {
+		ip := parseAddr(strings.Split(address, "/")[0])
+		guestInfoAddresses[ip.String()] = len(guestInfoAddresses)
 	}
 
 	// Sort nonlocalhostIPs by the following comparator for two IP addresses: a and b
@@ -791,3 +1119,25 @@ func sortStaticallyConfiguredAddressesFirst(extraConfig []types.BaseOptionValue,
 
 	return nonLocalhostIPs, nil
 }
+
+// staticAddresses returns the statically configured addresses of the network
+// config, with or without prefix length. A version 1 config lists them in the
+// static subnets of its physical interfaces, a version 2 one in its ethernets.
+func (n *networkConfig) staticAddresses() []string {
+	var addresses []string
+	for _, iface := range n.Config {
+		if iface.Type != "physical" {
+			continue
+		}
+		for _, subnet := range iface.Subnets {
+			// static and static6, the dhcp subnets have no address
+			if strings.HasPrefix(subnet.Type, "static") && subnet.Address != "" {
+				addresses = append(addresses, subnet.Address)
+			}
+		}
+	}
+	for _, eth := range n.Ethernets {
+		addresses = append(addresses, eth.Addresses...)
+	}
+	return addresses
+}
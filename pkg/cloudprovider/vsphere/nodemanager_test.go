@@ -22,7 +22,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"net"
+	"net/netip"
 	"strings"
 	"testing"
 
@@ -95,6 +95,117 @@ func TestRegUnregNode(t *testing.T) {
 	}
 }
 
+func TestRegisterNodeWithoutGuestHostname(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		useNodeNameAsHostname   bool
+		allowEmptyGuestHostname bool
+		hideGuestHostname       bool
+		guestHostname           string
+		expectedDiscovered      bool
+		expectedHostname        string
+	}{
+		{
+			name:               "guest hostname",
+			guestHostname:      "guest-hostname",
+			expectedDiscovered: true,
+			expectedHostname:   "guest-hostname",
+		},
+		{
+			name:                  "node name as hostname",
+			useNodeNameAsHostname: true,
+			guestHostname:         "guest-hostname",
+			expectedDiscovered:    true,
+			expectedHostname:      "k8s-node",
+		},
+		{
+			name:               "guest hostname not published",
+			hideGuestHostname:  true,
+			guestHostname:      "guest-hostname",
+			expectedDiscovered: true,
+		},
+		{
+			name:                  "node name as hostname with guest hostname not published",
+			useNodeNameAsHostname: true,
+			hideGuestHostname:     true,
+			guestHostname:         "guest-hostname",
+			expectedDiscovered:    true,
+			expectedHostname:      "k8s-node",
+		},
+		{
+			name:               "empty guest hostname",
+			expectedDiscovered: false,
+		},
+		{
+			name:                    "empty guest hostname allowed",
+			allowEmptyGuestHostname: true,
+			expectedDiscovered:      true,
+		},
+		{
+			name:                    "empty guest hostname allowed with node name as hostname",
+			useNodeNameAsHostname:   true,
+			allowEmptyGuestHostname: true,
+			expectedDiscovered:      true,
+			expectedHostname:        "k8s-node",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cfg, ok := configFromEnvOrSim(true)
+			defer ok()
+
+			connMgr := cm.NewConnectionManager(cfg, nil, nil)
+			defer connMgr.Logout()
+
+			cpiCfg := &ccfg.CPIConfig{}
+			cpiCfg.Nodes.UseNodeNameAsHostname = testCase.useNodeNameAsHostname
+			cpiCfg.Nodes.AllowEmptyGuestHostname = testCase.allowEmptyGuestHostname
+			if testCase.hideGuestHostname {
+				publish := false
+				cpiCfg.Nodes.PublishGuestHostname = &publish
+			}
+			nm := newNodeManager(cpiCfg, connMgr)
+
+			vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+			vm.Guest.HostName = testCase.guestHostname
+			vm.Guest.Net = []vimtypes.GuestNicInfo{
+				{
+					Network:   "foo-bar",
+					IpAddress: []string{"10.0.0.1"},
+				},
+			}
+
+			nm.RegisterNode(&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "k8s-node"},
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{SystemUUID: ConvertK8sUUIDtoNormal(vm.Config.Uuid)},
+				},
+			})
+
+			nodeInfo, discovered := nm.nodeUUIDMap[strings.ToLower(vm.Config.Uuid)]
+			if discovered != testCase.expectedDiscovered {
+				t.Fatalf("expected node discovered %t, but found %t", testCase.expectedDiscovered, discovered)
+			}
+			if !discovered {
+				return
+			}
+			hostname := ""
+			for _, addr := range nodeInfo.NodeAddresses {
+				if addr.Type == v1.NodeHostName {
+					hostname = addr.Address
+				}
+			}
+			if hostname != testCase.expectedHostname {
+				t.Errorf("expected hostname %q, but found %q", testCase.expectedHostname, hostname)
+			}
+			if nm.nodeNameMap[nodeInfo.NodeName] != nodeInfo || nodeInfo.NodeName == "" {
+				t.Errorf("node should be cached by its name, found %q", nodeInfo.NodeName)
+			}
+		})
+	}
+}
+
 func TestDiscoverNodeByName(t *testing.T) {
 	cfg, ok := configFromEnvOrSim(true)
 	defer ok()
@@ -584,6 +695,222 @@ func TestDiscoverNodeIPs(t *testing.T) {
 				{Type: "ExternalIP", Address: "fd00:dddd::11"},
 			},
 		},
+		{
+			testName: "SuppressExternalIP_dualstack_itSelectsOnlyInternalAddrs",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4", "ipv6"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalNetworkSubnetCIDR: "10.10.0.0/16,fd00:cccc::/64",
+						ExternalNetworkSubnetCIDR: "172.15.0.0/16,fd00:dddd::/64",
+						SuppressExternalIP:        true,
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "net_foo",
+						IpAddress: []string{
+							"127.0.0.6",
+							"169.0.1.2",
+						},
+					},
+					{
+						Network: "net_bar",
+						IpAddress: []string{
+							"10.10.1.22",
+							"fd00:dddd::11",
+						},
+					},
+					{
+						Network: "net_baz",
+						IpAddress: []string{
+							"172.15.108.11",
+							"fd00:cccc::22",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "10.10.1.22"},
+				{Type: "InternalIP", Address: "fd00:cccc::22"},
+			},
+		},
+		{
+			testName: "SuppressExternalIP_dualstack_WhenAnIPOfFamilyOnlyMatchesTheExternalSubnet_itFallsThroughToDefaultSelection",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4", "ipv6"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalNetworkSubnetCIDR: "10.10.0.0/16,fd00:ffff::/64",
+						ExternalNetworkSubnetCIDR: "172.15.0.0/16,fd00:cccc::/64",
+						SuppressExternalIP:        true,
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "net_foo",
+						IpAddress: []string{
+							"127.0.0.6",
+							"169.0.1.2",
+						},
+					},
+					{
+						Network: "net_bar",
+						IpAddress: []string{
+							"10.10.1.22",
+							"fd00:dddd::11",
+						},
+					},
+					{
+						Network: "net_baz",
+						IpAddress: []string{
+							"172.15.108.11",
+							"fd00:cccc::22",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "10.10.1.22"},
+				{Type: "InternalIP", Address: "fd00:dddd::11"},
+			},
+		},
+		{
+			testName: "SuppressExternalIP_ByDefaultSelection_itOmitsTheExternalIP",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv6", "ipv4"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						SuppressExternalIP: true,
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "net_foo",
+						IpAddress: []string{
+							"127.0.0.6",
+							"169.0.1.2",
+						},
+					},
+					{
+						Network: "net_bar",
+						IpAddress: []string{
+							"10.10.1.22",
+							"fd00:dddd::11",
+						},
+					},
+					{
+						Network: "net_baz",
+						IpAddress: []string{
+							"172.15.108.11",
+							"fd00:cccc::22",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "fd00:dddd::11"},
+				{Type: "InternalIP", Address: "169.0.1.2"},
+			},
+		},
+		{
+			testName: "PublishAllMatchingIPs_BySubnet_itSelectsEveryMatchingAddrInSubnetOrder",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4", "ipv6"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalNetworkSubnetCIDR: "10.20.0.0/16,10.10.0.0/16,fd00:dddd::/64",
+						ExternalNetworkSubnetCIDR: "172.15.0.0/16",
+						PublishAllMatchingIPs:     true,
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "net_foo",
+						IpAddress: []string{
+							"10.10.1.22",
+							"10.20.1.22",
+							"fd00:dddd::11",
+						},
+					},
+					{
+						Network: "net_bar",
+						IpAddress: []string{
+							"10.10.1.33",
+							"172.15.108.11",
+							"172.15.108.12",
+							"fd00:dddd::22",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "10.20.1.22"},
+				{Type: "InternalIP", Address: "10.10.1.22"},
+				{Type: "InternalIP", Address: "10.10.1.33"},
+				{Type: "ExternalIP", Address: "172.15.108.11"},
+				{Type: "ExternalIP", Address: "172.15.108.12"},
+				{Type: "InternalIP", Address: "fd00:dddd::11"},
+				{Type: "InternalIP", Address: "fd00:dddd::22"},
+			},
+		},
+		{
+			testName: "PublishAllMatchingIPs_ByNetworkName_itSelectsEveryAddrOfTheNetwork",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalVMNetworkName:            "internal_net",
+						ExcludeInternalNetworkSubnetCIDR: "10.10.2.0/24",
+						PublishAllMatchingIPs:            true,
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "internal_net",
+						IpAddress: []string{
+							"10.10.1.22",
+							"10.10.2.22",
+							"10.10.1.33",
+						},
+					},
+					{
+						Network: "external_net",
+						IpAddress: []string{
+							"172.15.108.11",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "10.10.1.22"},
+				{Type: "InternalIP", Address: "10.10.1.33"},
+			},
+		},
+		{
+			testName: "PublishAllMatchingIPs_ByDefaultSelection_itSelectsASingleAddr",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						PublishAllMatchingIPs: true,
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "net_foo",
+						IpAddress: []string{
+							"10.10.1.22",
+							"10.10.1.33",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "10.10.1.22"},
+				{Type: "ExternalIP", Address: "10.10.1.22"},
+			},
+		},
 		{
 			testName: "ByMultipleSubnets",
 			setup: testSetup{
@@ -910,6 +1237,90 @@ func TestDiscoverNodeIPs(t *testing.T) {
 				{Type: "ExternalIP", Address: "20.30.40.51"},
 			},
 		},
+		{
+			testName: "ByNetworkName_selectsIgnoringSwitchPrefixAndInvisibleCharacters",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalVMNetworkName: "internal_net",
+						ExternalVMNetworkName: "DSwitch/external_net",
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "DSwitch/internal_net",
+						IpAddress: []string{
+							"127.0.0.6",
+							"20.30.40.50",
+						},
+					},
+					{
+						Network: "external_net\u200b",
+						IpAddress: []string{
+							"127.0.0.6",
+							"20.30.40.51",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "20.30.40.50"},
+				{Type: "ExternalIP", Address: "20.30.40.51"},
+			},
+		},
+		{
+			testName: "ByNetworkNamePattern",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalVMNetworkNamePattern: "^k8s-internal-[0-9]+$",
+						ExternalVMNetworkName:        "external_net",
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "external_net",
+						IpAddress: []string{
+							"127.0.0.6",
+							"20.30.40.51",
+						},
+					},
+					{
+						Network: "k8s-internal-42",
+						IpAddress: []string{
+							"127.0.0.6",
+							"20.30.40.50",
+						},
+					},
+				},
+			},
+			expectedIPs: []v1.NodeAddress{
+				{Type: "InternalIP", Address: "20.30.40.50"},
+				{Type: "ExternalIP", Address: "20.30.40.51"},
+			},
+		},
+		{
+			testName: "ByNetworkNamePattern_invalidPattern",
+			setup: testSetup{
+				ipFamilyPriority: []string{"ipv4"},
+				cpiConfig: &ccfg.CPIConfig{
+					Nodes: ccfg.Nodes{
+						InternalVMNetworkNamePattern: "k8s-internal-[",
+					},
+				},
+				networks: []vimtypes.GuestNicInfo{
+					{
+						Network: "k8s-internal-42",
+						IpAddress: []string{
+							"20.30.40.50",
+						},
+					},
+				},
+			},
+			expectedErrorSubstring: "invalid VM network name pattern",
+		},
 		{
 			testName: "ByNetworkName_whenOnlyExternalNetworkIsSet_onlyExternalNetIsSet",
 			setup: testSetup{
@@ -1992,35 +2403,35 @@ func TestCollectMatchesForIPFamily(t *testing.T) {
 }
 
 func TestMatchesFamily(t *testing.T) {
-	if !matchesFamily(net.ParseIP("192.168.1.1"), "ipv4") {
+	if !matchesFamily(parseAddr("192.168.1.1"), "ipv4") {
 		t.Errorf("failed: expected 192.168.1.1 to match ipFamily ipv4, but it did not")
 	}
 
-	if matchesFamily(net.ParseIP("192.168.1.1"), "ipv6") {
+	if matchesFamily(parseAddr("192.168.1.1"), "ipv6") {
 		t.Errorf("failed: expected 192.168.1.1 not to match ipFamily ipv6, but it did")
 	}
 
-	if !matchesFamily(net.ParseIP("fd00:1::1"), "ipv6") {
+	if !matchesFamily(parseAddr("fd00:1::1"), "ipv6") {
 		t.Errorf("failed: expected fd00:1::1to match ipFamily ipv6, but it did not")
 	}
 
-	if matchesFamily(net.ParseIP("fd00:1::1"), "ipv4") {
+	if matchesFamily(parseAddr("fd00:1::1"), "ipv4") {
 		t.Errorf("failed: expected fd00:1::1 not to match ipFamily ipv4, but it did")
 	}
 
-	if matchesFamily(net.ParseIP("garbage"), "ipv6") {
+	if matchesFamily(parseAddr("garbage"), "ipv6") {
 		t.Errorf("failed: expected garbage not to match ipFamily ipv6, but it did")
 	}
 
-	if matchesFamily(net.ParseIP("garbage"), "ipv4") {
+	if matchesFamily(parseAddr("garbage"), "ipv4") {
 		t.Errorf("failed: expected garbage not to match ipFamily ipv4, but it did")
 	}
 
-	if matchesFamily(net.ParseIP("fd00:1::1"), "ipv7") {
+	if matchesFamily(parseAddr("fd00:1::1"), "ipv7") {
 		t.Errorf("failed: expected fd00:1::1 not to match ipFamily ipv7, but it did")
 	}
 
-	if matchesFamily(net.ParseIP("192.168.1.1"), "ipv7") {
+	if matchesFamily(parseAddr("192.168.1.1"), "ipv7") {
 		t.Errorf("failed: expected 192.168.1.1 not to match ipFamily ipv7, but it did")
 	}
 }
@@ -2051,16 +2462,16 @@ func TestFindSubnetMatch(t *testing.T) {
 		{ipAddr: "10.10.1.3"},
 	}
 
-	_, ipNetA, err := net.ParseCIDR("10.11.0.0/16")
+	ipNetA, err := netip.ParsePrefix("10.11.0.0/16")
 	if err != nil {
 		t.Errorf("failed to parse CIDR")
 	}
-	_, ipNetB, err := net.ParseCIDR("10.10.0.0/16")
+	ipNetB, err := netip.ParsePrefix("10.10.0.0/16")
 	if err != nil {
 		t.Errorf("failed to parse CIDR")
 	}
 
-	actual := findSubnetMatch(ipAddrNetworkNames, []*net.IPNet{ipNetA, ipNetB})
+	actual := findSubnetMatch(ipAddrNetworkNames, []netip.Prefix{ipNetA, ipNetB})
 
 	if actual.ipAddr != "10.10.1.2" {
 		t.Errorf("failed: expected ipAddr to equal 10.10.1.2, but was %s", actual.ipAddr)
@@ -2072,12 +2483,12 @@ func TestFindSubnetMatch(t *testing.T) {
 		{ipAddr: "fd00:100:64::2"},
 	}
 
-	_, ipNet, err := net.ParseCIDR("fd00:100:64::/64")
+	ipNet, err := netip.ParsePrefix("fd00:100:64::/64")
 	if err != nil {
 		t.Errorf("failed to parse CIDR")
 	}
 
-	actual = findSubnetMatch(ipAddrNetworkNames, []*net.IPNet{ipNet})
+	actual = findSubnetMatch(ipAddrNetworkNames, []netip.Prefix{ipNet})
 
 	if actual.ipAddr != "fd00:100:64::1" {
 		t.Errorf("failed: expected ipAddr to equal fd00:100:64::1, but was %s", actual.ipAddr)
@@ -2090,23 +2501,91 @@ func TestFindSubnetMatch(t *testing.T) {
 		{ipAddr: "fd00:100:64::2"},
 	}
 
-	_, ipNet1, err := net.ParseCIDR("fd00:100:64::/64")
+	ipNet1, err := netip.ParsePrefix("fd00:100:64::/64")
 	if err != nil {
 		t.Errorf("failed to parse CIDR")
 	}
 
-	_, ipNet2, err := net.ParseCIDR("fd00:101:64::/64")
+	ipNet2, err := netip.ParsePrefix("fd00:101:64::/64")
 	if err != nil {
 		t.Errorf("failed to parse CIDR")
 	}
 
-	actual = findSubnetMatch(ipAddrNetworkNames, []*net.IPNet{ipNet1, ipNet2})
+	actual = findSubnetMatch(ipAddrNetworkNames, []netip.Prefix{ipNet1, ipNet2})
 
 	if actual.ipAddr != "fd00:100:64::1" {
 		t.Errorf("failed: expected ipAddr to equal fd00:100:64::1, but was %s", actual.ipAddr)
 	}
 }
 
+func TestParseAddr(t *testing.T) {
+	testCases := []struct {
+		addr     string
+		expected string
+	}{
+		{addr: "10.10.1.2", expected: "10.10.1.2"},
+		{addr: "fd00:100:64::1", expected: "fd00:100:64::1"},
+		{addr: "::ffff:10.10.1.2", expected: "10.10.1.2"},
+		{addr: "fe80::1%eth0", expected: "invalid IP"},
+		{addr: "garbage", expected: "invalid IP"},
+		{addr: "", expected: "invalid IP"},
+	}
+	for _, testCase := range testCases {
+		if actual := parseAddr(testCase.addr).String(); actual != testCase.expected {
+			t.Errorf("failed: expected %q to parse as %s, but got %s", testCase.addr, testCase.expected, actual)
+		}
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	subnets, err := parseCIDRs("10.10.1.2/16,fd00:100:64::1/64")
+	if err != nil {
+		t.Fatalf("failed: unexpected error: %s", err)
+	}
+	if fmt.Sprint(subnets) != "[10.10.0.0/16 fd00:100:64::/64]" {
+		t.Errorf("failed: expected masked subnets, but got %v", subnets)
+	}
+
+	if _, err := parseCIDRs("10.10.0.0/16,garbage"); err == nil || err.Error() != "invalid CIDR address: garbage" {
+		t.Errorf("failed: expected invalid CIDR address error, but got %v", err)
+	}
+}
+
+// BenchmarkDiscoverIPs measures the address selection of a node with many
+// addresses against many configured subnets, as done for every node on a
+// full resync.
+func BenchmarkDiscoverIPs(b *testing.B) {
+	var cidrs []string
+	for i := 0; i < 64; i++ {
+		cidrs = append(cidrs, fmt.Sprintf("10.%d.0.0/16", i), fmt.Sprintf("fd00:%x::/64", i))
+	}
+	subnets, err := parseCIDRs(strings.Join(cidrs, ","))
+	if err != nil {
+		b.Fatal(err)
+	}
+	excluded, err := parseCIDRs("172.16.0.0/12,fc00::/8")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var guestNicInfos []vimtypes.GuestNicInfo
+	for nic := 0; nic < 8; nic++ {
+		guestNicInfos = append(guestNicInfos, vimtypes.GuestNicInfo{
+			Network:   fmt.Sprintf("net-%d", nic),
+			IpAddress: []string{fmt.Sprintf("192.168.%d.10", nic), fmt.Sprintf("fd01:%x::10", nic)},
+		})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ipAddrNetworkNames := excludeLocalhostIPs(toIPAddrNetworkNames(guestNicInfos))
+		for _, ipFamily := range []string{"ipv4", "ipv6"} {
+			discoverIPs(ipAddrNetworkNames, ipFamily, subnets, subnets, excluded, excluded, nil, nil, false)
+		}
+	}
+}
+
 func TestFindFirst(t *testing.T) {
 	ipAddrNetworkNames := []*ipAddrNetworkName{
 		{networkName: "foo", ipAddr: "::1"},
@@ -2130,13 +2609,51 @@ func TestFindNetworkNameMatch(t *testing.T) {
 		{networkName: "bar", ipAddr: "192.168.1.1"},
 	}
 
-	match := findNetworkNameMatch(ipAddrNetworkNames, "bar")
+	match := findNetworkNameMatch(ipAddrNetworkNames, &networkNameMatcher{name: "bar"})
 
 	if match.networkName != "bar" || match.ipAddr != "::1" {
 		t.Errorf("failed: expected a match of name \"bar\" with an ipAddr of \"::1\", but got: %s %s", match.networkName, match.ipAddr)
 	}
 }
 
+func TestNetworkNameMatcher(t *testing.T) {
+	testcases := []struct {
+		name        string
+		pattern     string
+		networkName string
+		expected    bool
+	}{
+		{name: "VM Network", networkName: "vm network", expected: true},
+		{name: "VM Network", networkName: "DSwitch/VM Network", expected: true},
+		{name: "DSwitch/VM Network", networkName: "VM Network", expected: true},
+		{name: "DSwitch/VM Network", networkName: "OtherSwitch/VM Network", expected: false},
+		{name: "External/Outbound Traffic", networkName: "DSwitch/External/Outbound Traffic", expected: true},
+		{name: "VM Network", networkName: "VM Network 2", expected: false},
+		{pattern: "^k8s-.*", networkName: "k8s-nodes", expected: true},
+		{pattern: "^k8s-.*", networkName: "\ufeffk8s-nodes", expected: true},
+		{pattern: "^k8s-.*", networkName: "DSwitch/k8s-nodes", expected: false},
+		{name: "VM Network", pattern: "^k8s-.*", networkName: "k8s-nodes", expected: true},
+		{name: "VM Network", pattern: "^k8s-.*", networkName: "VM Network", expected: true},
+	}
+
+	for _, testcase := range testcases {
+		m, err := newNetworkNameMatcher(testcase.name, testcase.pattern)
+		if err != nil {
+			t.Fatalf("failed: unexpected error for %s: %s", m, err)
+		}
+		if actual := m.matches(testcase.networkName); actual != testcase.expected {
+			t.Errorf("failed: expected %q to match %s to be %t", testcase.networkName, m, testcase.expected)
+		}
+	}
+
+	if m, err := newNetworkNameMatcher("", ""); m != nil || err != nil {
+		t.Errorf("failed: expected no matcher, got %s (%v)", m, err)
+	}
+	if _, err := newNetworkNameMatcher("", "("); err == nil {
+		t.Errorf("failed: expected an invalid pattern to fail")
+	}
+}
+
 func TestExcludeLocalhostIPs(t *testing.T) {
 	ipAddrNetworkNames := []*ipAddrNetworkName{
 		// doesn't parse
@@ -2191,6 +2708,71 @@ network:
       dhcp6: true`
 }
 
+func TestSortStaticallyConfiguredAddressesFirstEncodings(t *testing.T) {
+	encode := func(encoding, data string) string {
+		switch encoding {
+		case "base64":
+			return base64.StdEncoding.EncodeToString([]byte(data))
+		case "gzip+base64":
+			buf := bytes.NewBuffer(nil)
+			gw := gzip.NewWriter(buf)
+			if _, err := gw.Write([]byte(data)); err != nil {
+				t.Fatal(err)
+			}
+			if err := gw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			return base64.StdEncoding.EncodeToString(buf.Bytes())
+		}
+		return data
+	}
+
+	for _, encoding := range []string{"", "base64", "gzip+base64"} {
+		for _, networkEncoding := range []string{"", "base64", "gzip+base64"} {
+			t.Run(fmt.Sprintf("metadata %q network %q", encoding, networkEncoding), func(t *testing.T) {
+				extraConfig := []vimtypes.BaseOptionValue{
+					&vimtypes.OptionValue{
+						Key:   "guestinfo.metadata",
+						Value: encode(encoding, guestInfoEncodedNetconfigWithAddresses(networkEncoding, "192.168.1.20/24")),
+					},
+					&vimtypes.OptionValue{
+						Key:   "guestinfo.metadata.encoding",
+						Value: encoding,
+					},
+				}
+				ips := []*ipAddrNetworkName{
+					{ipAddr: "192.168.1.10", networkName: "VM Network"},
+					{ipAddr: "192.168.1.20", networkName: "VM Network"},
+				}
+
+				sorted, err := sortStaticallyConfiguredAddressesFirst(extraConfig, ips, ccfg.DefaultMaxGuestInfoSize)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if sorted[0].ipAddr != "192.168.1.20" {
+					t.Errorf("expected the static address first, but got %v", sorted)
+				}
+			})
+		}
+	}
+
+	// unsupported encodings are ignored
+	extraConfig := []vimtypes.BaseOptionValue{
+		&vimtypes.OptionValue{Key: "guestinfo.metadata", Value: "data"},
+		&vimtypes.OptionValue{Key: "guestinfo.metadata.encoding", Value: "bzip2"},
+	}
+	ips := []*ipAddrNetworkName{{ipAddr: "192.168.1.10", networkName: "VM Network"}}
+	if sorted, err := sortStaticallyConfiguredAddressesFirst(extraConfig, ips, ccfg.DefaultMaxGuestInfoSize); err != nil || len(sorted) != 1 {
+		t.Errorf("unexpected result %v, %v", sorted, err)
+	}
+
+	// invalid data is not
+	extraConfig[1] = &vimtypes.OptionValue{Key: "guestinfo.metadata.encoding", Value: "gzip+base64"}
+	if _, err := sortStaticallyConfiguredAddressesFirst(extraConfig, ips, ccfg.DefaultMaxGuestInfoSize); err == nil {
+		t.Error("expected error")
+	}
+}
+
 func guestInfoWithAddresses(addresses string) string {
 	return fmt.Sprintf(`instance-id: "tkg-mgmt-vc"
 local-hostname: "tkg-mgmt-vc"
@@ -2254,3 +2836,51 @@ network.encoding: %s
 network: %s`,
 		encoding, encodedNetconfig)
 }
+
+func TestSortStaticallyConfiguredAddressesFirstNetworkConfigV1(t *testing.T) {
+	metadata := `instance-id: "tkg-mgmt-vc"
+local-hostname: "tkg-mgmt-vc"
+network:
+  version: 1
+  config:
+    - type: physical
+      name: eth0
+      mac_address: "00:11:22"
+      subnets:
+        - type: dhcp
+        - type: static
+          address: 192.168.1.30/24
+          gateway: 192.168.1.1
+        - type: static6
+          address: fd00::30/64
+    - type: physical
+      name: eth1
+      subnets:
+        - type: static
+          address: 192.168.2.20
+          netmask: 255.255.255.0
+    - type: nameserver
+      address: [192.168.1.1]`
+	extraConfig := []vimtypes.BaseOptionValue{
+		&vimtypes.OptionValue{Key: "guestinfo.metadata", Value: metadata},
+	}
+	ips := []*ipAddrNetworkName{
+		{ipAddr: "192.168.1.10", networkName: "VM Network"},
+		{ipAddr: "192.168.2.20", networkName: "VM Network"},
+		{ipAddr: "fd00::30", networkName: "VM Network"},
+		{ipAddr: "192.168.1.30", networkName: "VM Network"},
+	}
+
+	sorted, err := sortStaticallyConfiguredAddressesFirst(extraConfig, ips, ccfg.DefaultMaxGuestInfoSize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, ip := range sorted {
+		got = append(got, ip.ipAddr)
+	}
+	expected := "192.168.1.30,fd00::30,192.168.2.20,192.168.1.10"
+	if strings.Join(got, ",") != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
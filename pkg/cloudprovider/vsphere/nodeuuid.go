@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// AnnotationVMUUID is the node annotation holding the vSphere BIOS UUID of
+	// the VM of the node, which is trusted instead of the SystemUUID of the
+	// node. It unblocks VMs imported from other hypervisors, e.g. with HCX,
+	// whose SMBIOS UUID does not match the one vSphere knows them by.
+	AnnotationVMUUID = "vsphere.cpi.kubernetes.io/vm-uuid"
+
+	// EventReasonInvalidVMUUIDAnnotation is the reason of the event recorded
+	// when the VM UUID annotated on the node is not a UUID
+	EventReasonInvalidVMUUIDAnnotation = "InvalidVMUUIDAnnotation"
+)
+
+// annotatedVMUUID returns the VM UUID annotated on the node, "" if it is not
+// annotated. An invalid annotation is reported on the node and ignored.
+func (nm *NodeManager) annotatedVMUUID(node *v1.Node) string {
+	value := strings.TrimSpace(node.Annotations[AnnotationVMUUID])
+	if value == "" {
+		return ""
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		klog.Warningf("Ignoring annotation %s of node %s: %v", AnnotationVMUUID, node.Name, err)
+		if nm.recorder != nil {
+			ref := &v1.ObjectReference{Kind: "Node", Name: node.Name, UID: types.UID(node.Name)}
+			nm.recorder.Eventf(ref, v1.EventTypeWarning, EventReasonInvalidVMUUIDAnnotation,
+				"Ignoring annotation %s: %v", AnnotationVMUUID, err)
+		}
+		return ""
+	}
+	logging.V(logging.NodeManager, 2).Infof("Using VM UUID %s annotated with %s on node %s", value, AnnotationVMUUID, node.Name)
+	return strings.ToLower(value)
+}
+
+// nodeUUID returns the UUID of the VM of the node, the annotated VM UUID if
+// any, else the SystemUUID of the node in the VMware format.
+func (nm *NodeManager) nodeUUID(node *v1.Node) string {
+	if vmUUID := nm.annotatedVMUUID(node); vmUUID != "" {
+		return vmUUID
+	}
+	return ConvertK8sUUIDtoNormal(node.Status.NodeInfo.SystemUUID)
+}
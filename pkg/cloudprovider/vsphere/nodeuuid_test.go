@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestNodeUUID(t *testing.T) {
+	testcases := []struct {
+		name       string
+		annotation string
+		expected   string
+		event      bool
+	}{
+		{name: "not annotated", expected: "422e4956-ad22-1139-6d72-59cc8f26bc90"},
+		{name: "annotated", annotation: " 4217C0F2-0E3A-5B8C-9D1E-2F3A4B5C6D7E ", expected: "4217c0f2-0e3a-5b8c-9d1e-2f3a4b5c6d7e"},
+		{name: "invalid annotation", annotation: "vm-42", expected: "422e4956-ad22-1139-6d72-59cc8f26bc90", event: true},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			nm := &NodeManager{recorder: recorder}
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{SystemUUID: "56492e42-22ad-3911-6d72-59cc8f26bc90"},
+				},
+			}
+			if testcase.annotation != "" {
+				node.Annotations = map[string]string{AnnotationVMUUID: testcase.annotation}
+			}
+
+			if actual := nm.nodeUUID(node); actual != testcase.expected {
+				t.Errorf("expected %q, got %q", testcase.expected, actual)
+			}
+			select {
+			case event := <-recorder.Events:
+				if !testcase.event || !strings.Contains(event, EventReasonInvalidVMUUIDAnnotation) {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				if testcase.event {
+					t.Error("expected an event")
+				}
+			}
+		})
+	}
+}
+
+func TestInstanceMetadataAnnotatedVMUUID(t *testing.T) {
+	initCfg, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+	cfg := &ccfg.CPIConfig{}
+	cfg.Config = *initCfg
+
+	vs, err := newVSphere(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct vSphere: %s", err)
+	}
+	vs.connectionManager = cm.NewConnectionManager(&cfg.Config, nil, nil)
+	defer vs.connectionManager.Logout()
+	vs.nodeManager.connectionManager = vs.connectionManager
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+
+	// the SystemUUID and the name of an imported node do not match its VM
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "imported-node",
+			Annotations: map[string]string{AnnotationVMUUID: vm.Config.Uuid},
+		},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: "00000000-0000-0000-0000-000000000001"},
+		},
+	}
+	vs.nodeAdded(node)
+	if vs.nodeManager.registeredNodeName(strings.ToLower(vm.Config.Uuid)) != node.Name {
+		t.Errorf("node should be registered with its annotated VM UUID")
+	}
+
+	metadata, err := newInstancesV2(vs.nodeManager, vcfg.Labels{}).InstanceMetadata(context.Background(), node)
+	if err != nil {
+		t.Fatalf("InstanceMetadata failed err=%v", err)
+	}
+	if !strings.EqualFold(metadata.ProviderID, ProviderPrefix+vm.Config.Uuid) {
+		t.Errorf("ProviderID mismatch %s != %s", metadata.ProviderID, ProviderPrefix+vm.Config.Uuid)
+	}
+}
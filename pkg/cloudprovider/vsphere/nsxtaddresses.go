@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/segments/ports"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/search"
+
+	"k8s.io/cloud-provider-vsphere/pkg/nsxt"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// nsxtVMInterface is a vNIC of a VM as known to NSX-T.
+type nsxtVMInterface struct {
+	macAddress   string
+	attachmentID string
+}
+
+// nsxtAddressBroker is an internal interface to look up the addresses NSX-T
+// realized on the segment ports of a VM.
+type nsxtAddressBroker interface {
+	// ListVMInterfaces returns the vNICs of the VM with the given external
+	// ID, which is the VM's instance UUID.
+	ListVMInterfaces(externalID string) ([]nsxtVMInterface, error)
+	// ListRealizedAddresses returns the IP addresses realized on the
+	// segment ports with the given attachment ID.
+	ListRealizedAddresses(attachmentID string) ([]string, error)
+}
+
+// nsxtAddressClient includes the NSX-T API clients of the nsxtAddressBroker
+type nsxtAddressClient struct {
+	queryClient     search.QueryClient
+	portStateClient ports.StateClient
+}
+
+// newNsxtAddressBroker creates a new nsxtAddressBroker to the NSX-T API
+func newNsxtAddressBroker(connector client.Connector) nsxtAddressBroker {
+	return &nsxtAddressClient{
+		queryClient:     search.NewQueryClient(connector),
+		portStateClient: ports.NewStateClient(connector),
+	}
+}
+
+func (c *nsxtAddressClient) ListVMInterfaces(externalID string) ([]nsxtVMInterface, error) {
+	query := fmt.Sprintf("resource_type:VirtualNetworkInterface AND owner_vm_id:%s", externalID)
+	results, err := c.search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var vifs []nsxtVMInterface
+	for _, item := range results {
+		vif := nsxtVMInterface{
+			macAddress:   stringField(item, "mac_address"),
+			attachmentID: stringField(item, "lport_attachment_id"),
+		}
+		if vif.attachmentID == "" {
+			continue
+		}
+		vifs = append(vifs, vif)
+	}
+	return vifs, nil
+}
+
+func (c *nsxtAddressClient) ListRealizedAddresses(attachmentID string) ([]string, error) {
+	query := fmt.Sprintf("resource_type:SegmentPort AND attachment.id:%s", attachmentID)
+	results, err := c.search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, item := range results {
+		path := stringField(item, "path")
+		segmentID, portID, ok := parseSegmentPortPath(path)
+		if !ok {
+			logging.V(logging.NodeManager, 4).Infof("Skipping NSX-T segment port %s which is not an infra segment port", path)
+			continue
+		}
+		state, err := c.portStateClient.Get(segmentID, portID, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range state.RealizedBindings {
+			if entry.Binding == nil || entry.Binding.IpAddress == nil {
+				continue
+			}
+			// bindings may be subnets, only single addresses are node addresses
+			if net.ParseIP(*entry.Binding.IpAddress) == nil {
+				continue
+			}
+			addrs = append(addrs, *entry.Binding.IpAddress)
+		}
+	}
+	return addrs, nil
+}
+
+// search returns the results of the query from all its pages.
+func (c *nsxtAddressClient) search(query string) ([]*data.StructValue, error) {
+	return nsxt.ListAll(func(cursor *string) ([]*data.StructValue, *string, error) {
+		result, err := c.queryClient.List(query, cursor, nil, nil, nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Results, result.Cursor, nil
+	})
+}
+
+// parseSegmentPortPath returns the segment and port IDs of a segment port
+// policy path like /infra/segments/<segment>/ports/<port>.
+func parseSegmentPortPath(path string) (string, string, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 5 || parts[0] != "infra" || parts[1] != "segments" || parts[3] != "ports" {
+		return "", "", false
+	}
+	return parts[2], parts[4], true
+}
+
+// stringField returns the value of a string field of a search result, or ""
+// if it is not set.
+func stringField(item *data.StructValue, name string) string {
+	field, err := item.Field(name)
+	if err != nil {
+		return ""
+	}
+	if s, ok := field.(*data.StringValue); ok {
+		return s.Value()
+	}
+	return ""
+}
+
+// discoverNSXTAddresses returns the addresses NSX-T realized on the segment
+// ports of the VM with the given instance UUID. The network name of an
+// address is the one VMware Tools reported for the vNIC of the same MAC
+// address, if any.
+func (nm *NodeManager) discoverNSXTAddresses(instanceUUID string, guestNicInfos []types.GuestNicInfo) ([]*ipAddrNetworkName, error) {
+	vifs, err := nm.nsxtBroker.ListVMInterfaces(instanceUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*ipAddrNetworkName
+	for _, vif := range vifs {
+		addrs, err := nm.nsxtBroker.ListRealizedAddresses(vif.attachmentID)
+		if err != nil {
+			return nil, err
+		}
+		networkName := ""
+		for _, nic := range guestNicInfos {
+			if strings.EqualFold(nic.MacAddress, vif.macAddress) {
+				networkName = nic.Network
+				break
+			}
+		}
+		for _, addr := range addrs {
+			candidates = append(candidates, &ipAddrNetworkName{ipAddr: addr, networkName: networkName})
+		}
+	}
+	return candidates, nil
+}
+
+// mergeIPAddrNetworkNames returns the addresses of first followed by the
+// ones of second that are not in first. An address without a network name
+// takes the one of its duplicate.
+func mergeIPAddrNetworkNames(first, second []*ipAddrNetworkName) []*ipAddrNetworkName {
+	var merged []*ipAddrNetworkName
+	seen := make(map[string]*ipAddrNetworkName)
+	for _, candidates := range [][]*ipAddrNetworkName{first, second} {
+		for _, candidate := range candidates {
+			key := candidate.ipAddr
+			if ip := candidate.ip(); ip.IsValid() {
+				key = ip.String()
+			}
+			if existing, ok := seen[key]; ok {
+				if existing.networkName == "" {
+					existing.networkName = candidate.networkName
+				}
+				continue
+			}
+			seen[key] = candidate
+			merged = append(merged, candidate)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/search"
+	v1 "k8s.io/api/core/v1"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+type fakeNsxtAddressBroker struct {
+	vifs  map[string][]nsxtVMInterface
+	addrs map[string][]string
+	err   error
+}
+
+func (b *fakeNsxtAddressBroker) ListVMInterfaces(externalID string) ([]nsxtVMInterface, error) {
+	return b.vifs[externalID], b.err
+}
+
+func (b *fakeNsxtAddressBroker) ListRealizedAddresses(attachmentID string) ([]string, error) {
+	return b.addrs[attachmentID], nil
+}
+
+// pagedQueryClient returns the pages of a search by cursor, "" for the first.
+type pagedQueryClient struct {
+	search.QueryClient
+	pages map[string]model.SearchResponse
+	calls int
+}
+
+func (c *pagedQueryClient) List(_ string, cursor *string, _ *string, _ *int64, _ *bool, _ *string) (model.SearchResponse, error) {
+	c.calls++
+	key := ""
+	if cursor != nil {
+		key = *cursor
+	}
+	page, ok := c.pages[key]
+	if !ok {
+		return model.SearchResponse{}, fmt.Errorf("unknown cursor %q", key)
+	}
+	return page, nil
+}
+
+func vifPage(count int64, cursor string, attachmentIDs ...string) model.SearchResponse {
+	page := model.SearchResponse{ResultCount: &count}
+	if cursor != "" {
+		page.Cursor = &cursor
+	}
+	for _, id := range attachmentIDs {
+		page.Results = append(page.Results, data.NewStructValue("", map[string]data.DataValue{
+			"lport_attachment_id": data.NewStringValue(id),
+		}))
+	}
+	return page
+}
+
+func TestListVMInterfacesPagination(t *testing.T) {
+	testcases := []struct {
+		name          string
+		pages         map[string]model.SearchResponse
+		expectedIDs   string
+		expectedCalls int
+		expectedErr   bool
+	}{
+		{
+			name:          "single page",
+			pages:         map[string]model.SearchResponse{"": vifPage(2, "", "att-1", "att-2")},
+			expectedIDs:   "att-1,att-2",
+			expectedCalls: 1,
+		},
+		{
+			name: "all pages",
+			pages: map[string]model.SearchResponse{
+				"":  vifPage(3, "1", "att-1"),
+				"1": vifPage(3, "2", "att-2"),
+				"2": vifPage(3, "", "att-3"),
+			},
+			expectedIDs:   "att-1,att-2,att-3",
+			expectedCalls: 3,
+		},
+		{
+			name: "result count drifting",
+			pages: map[string]model.SearchResponse{
+				"":  vifPage(2, "1", "att-1"),
+				"1": vifPage(2, "2", "att-2"),
+				"2": vifPage(3, "", "att-3"),
+			},
+			expectedIDs:   "att-1,att-2,att-3",
+			expectedCalls: 3,
+		},
+		{
+			name: "cursor set on an empty last page",
+			pages: map[string]model.SearchResponse{
+				"":  vifPage(1, "1", "att-1"),
+				"1": vifPage(1, "2"),
+			},
+			expectedIDs:   "att-1",
+			expectedCalls: 2,
+		},
+		{
+			name: "cursor returned twice",
+			pages: map[string]model.SearchResponse{
+				"":  vifPage(3, "1", "att-1"),
+				"1": vifPage(3, "1", "att-2"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			queryClient := &pagedQueryClient{pages: testcase.pages}
+			c := &nsxtAddressClient{queryClient: queryClient}
+
+			vifs, err := c.ListVMInterfaces("vm-uuid")
+			if testcase.expectedErr {
+				if err == nil {
+					t.Error("failed: expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed: unexpected error: %s", err)
+			}
+			var ids []string
+			for _, vif := range vifs {
+				ids = append(ids, vif.attachmentID)
+			}
+			if strings.Join(ids, ",") != testcase.expectedIDs {
+				t.Errorf("failed: expected %s, actual %s", testcase.expectedIDs, strings.Join(ids, ","))
+			}
+			if queryClient.calls != testcase.expectedCalls {
+				t.Errorf("failed: expected %d calls, actual %d", testcase.expectedCalls, queryClient.calls)
+			}
+		})
+	}
+}
+
+func TestParseSegmentPortPath(t *testing.T) {
+	segmentID, portID, ok := parseSegmentPortPath("/infra/segments/seg-1/ports/port-1")
+	if !ok || segmentID != "seg-1" || portID != "port-1" {
+		t.Errorf("failed: unexpected segment %q port %q ok %v", segmentID, portID, ok)
+	}
+	if _, _, ok := parseSegmentPortPath("/infra/tier-1s/t1/segments/seg-1/ports/port-1"); ok {
+		t.Error("failed: tier-1 segment ports should not be parsed")
+	}
+}
+
+func TestMergeIPAddrNetworkNames(t *testing.T) {
+	tools := []*ipAddrNetworkName{
+		{ipAddr: "10.0.0.2", networkName: "net"},
+		{ipAddr: "fe80::1", networkName: "net"},
+	}
+	nsxt := []*ipAddrNetworkName{
+		{ipAddr: "10.0.0.1"},
+		{ipAddr: "10.0.0.2"},
+		{ipAddr: "fe80:0::1"},
+	}
+
+	merged := mergeIPAddrNetworkNames(nsxt, tools)
+	var actual []string
+	for _, m := range merged {
+		actual = append(actual, m.ipAddr+"@"+m.networkName)
+	}
+	expected := "10.0.0.1@,10.0.0.2@net,fe80:0::1@net"
+	if strings.Join(actual, ",") != expected {
+		t.Errorf("failed: expected %s, actual %s", expected, strings.Join(actual, ","))
+	}
+}
+
+func TestDiscoverNSXTAddresses(t *testing.T) {
+	nm := newNodeManager(nil, nil)
+	nm.nsxtBroker = &fakeNsxtAddressBroker{
+		vifs: map[string][]nsxtVMInterface{
+			"vm-uuid": {
+				{macAddress: "00:50:56:AA:BB:01", attachmentID: "att-1"},
+				{macAddress: "00:50:56:aa:bb:02", attachmentID: "att-2"},
+			},
+		},
+		addrs: map[string][]string{
+			"att-1": {"10.0.0.1"},
+			"att-2": {"10.0.1.1", "10.0.1.2"},
+		},
+	}
+
+	addrs, err := nm.discoverNSXTAddresses("vm-uuid", []vimtypes.GuestNicInfo{
+		{Network: "internal_net", MacAddress: "00:50:56:aa:bb:01"},
+	})
+	if err != nil {
+		t.Fatalf("Failed discoverNSXTAddresses: %s", err)
+	}
+	var actual []string
+	for _, a := range addrs {
+		actual = append(actual, a.ipAddr+"@"+a.networkName)
+	}
+	expected := "10.0.0.1@internal_net,10.0.1.1@,10.0.1.2@"
+	if strings.Join(actual, ",") != expected {
+		t.Errorf("failed: expected %s, actual %s", expected, strings.Join(actual, ","))
+	}
+}
+
+func TestDiscoverNodeNSXTAddresses(t *testing.T) {
+	testcases := []struct {
+		testName       string
+		source         string
+		networks       []vimtypes.GuestNicInfo
+		brokerErr      error
+		expectedIP     string
+		expectedErrMsg string
+	}{
+		{
+			testName:   "BeforeToolsReport",
+			source:     ccfg.NSXAddressSourceMerge,
+			expectedIP: "10.0.0.1",
+		},
+		{
+			testName: "Merge",
+			source:   ccfg.NSXAddressSourceMerge,
+			networks: []vimtypes.GuestNicInfo{
+				{Network: "net", IpAddress: []string{"10.0.0.2"}},
+			},
+			expectedIP: "10.0.0.2",
+		},
+		{
+			testName: "Prefer",
+			source:   ccfg.NSXAddressSourcePrefer,
+			networks: []vimtypes.GuestNicInfo{
+				{Network: "net", IpAddress: []string{"10.0.0.2"}},
+			},
+			expectedIP: "10.0.0.1",
+		},
+		{
+			testName: "NSXTUnavailable",
+			source:   ccfg.NSXAddressSourcePrefer,
+			networks: []vimtypes.GuestNicInfo{
+				{Network: "net", IpAddress: []string{"10.0.0.2"}},
+			},
+			brokerErr:  errors.New("unavailable"),
+			expectedIP: "10.0.0.2",
+		},
+		{
+			testName:       "NoAddresses",
+			source:         ccfg.NSXAddressSourceMerge,
+			brokerErr:      errors.New("unavailable"),
+			expectedErrMsg: "VM GuestNicInfo is empty",
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.testName, func(t *testing.T) {
+			cfg, fin := configFromEnvOrSim(true)
+			defer fin()
+
+			connMgr := cm.NewConnectionManager(cfg, nil, nil)
+			defer connMgr.Logout()
+
+			vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+			vm.Guest.HostName = strings.ToLower(vm.Name)
+			vm.Guest.Net = testcase.networks
+
+			nm := newNodeManager(&ccfg.CPIConfig{Nodes: ccfg.Nodes{NSXAddressSource: testcase.source}}, connMgr)
+			nm.nsxtBroker = &fakeNsxtAddressBroker{
+				vifs: map[string][]nsxtVMInterface{
+					vm.Config.InstanceUuid: {{attachmentID: "att-1"}},
+				},
+				addrs: map[string][]string{"att-1": {"10.0.0.1"}},
+				err:   testcase.brokerErr,
+			}
+
+			if err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]); err != nil {
+				t.Errorf("Failed to Connect to vSphere: %s", err)
+			}
+
+			err := nm.DiscoverNode(vm.Name, cm.FindVMByName)
+			if testcase.expectedErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), testcase.expectedErrMsg) {
+					t.Errorf("failed: expected error containing %q, got %v", testcase.expectedErrMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed DiscoverNode: %s", err)
+			}
+
+			nodeInfo, ok := nm.nodeNameMap[strings.ToLower(vm.Name)]
+			if !ok {
+				t.Fatalf("failed: %v not found", vm.Name)
+			}
+			for _, addr := range nodeInfo.NodeAddresses {
+				if addr.Type == v1.NodeInternalIP && addr.Address != testcase.expectedIP {
+					t.Errorf("failed: InternalIP should be %v, not %v", testcase.expectedIP, addr.Address)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+const (
+	// LabelPlacementHost is the node label holding the name of the ESXi host
+	// running the node's VM, if Nodes.EnablePlacementLabels is set.
+	LabelPlacementHost = "vsphere.vmware.com/host"
+	// LabelPlacementCluster is the node label holding the name of the compute
+	// cluster of the host, unless the host is standalone.
+	LabelPlacementCluster = "vsphere.vmware.com/cluster"
+	// LabelPlacementResourcePool is the node label holding the name of the
+	// resource pool of the node's VM.
+	LabelPlacementResourcePool = "vsphere.vmware.com/resource-pool"
+)
+
+// placementLabels returns the node labels describing where the VM of the
+// node runs: its ESXi host, the compute cluster of the host and its resource
+// pool, as valid label values. Labels of the placement the VM does not have,
+// such as the cluster of a standalone host, are left out.
+func (nm *NodeManager) placementLabels(ctx context.Context, nodeInfo *NodeInfo) (map[string]string, error) {
+	labels := make(map[string]string)
+	if nodeInfo.vm == nil {
+		return labels, nil
+	}
+
+	var vm mo.VirtualMachine
+	if err := nodeInfo.vm.Properties(ctx, nodeInfo.vm.Reference(), []string{"runtime.host", "resourcePool"}, &vm); err != nil {
+		return nil, fmt.Errorf("failed to read the placement of VM %s: %w", nodeInfo.UUID, err)
+	}
+
+	pc := property.DefaultCollector(nodeInfo.vm.Client())
+	if vm.Runtime.Host != nil {
+		var host mo.HostSystem
+		if err := pc.RetrieveOne(ctx, *vm.Runtime.Host, []string{"name", "parent"}, &host); err != nil {
+			return nil, fmt.Errorf("failed to read the host of VM %s: %w", nodeInfo.UUID, err)
+		}
+		labels[LabelPlacementHost] = sanitizeLabel(host.Name)
+
+		if host.Parent != nil && host.Parent.Type == "ClusterComputeResource" {
+			var cluster mo.ClusterComputeResource
+			if err := pc.RetrieveOne(ctx, *host.Parent, []string{"name"}, &cluster); err != nil {
+				return nil, fmt.Errorf("failed to read the cluster of VM %s: %w", nodeInfo.UUID, err)
+			}
+			labels[LabelPlacementCluster] = sanitizeLabel(cluster.Name)
+		}
+	}
+	if vm.ResourcePool != nil {
+		var pool mo.ResourcePool
+		if err := pc.RetrieveOne(ctx, *vm.ResourcePool, []string{"name"}, &pool); err != nil {
+			return nil, fmt.Errorf("failed to read the resource pool of VM %s: %w", nodeInfo.UUID, err)
+		}
+		labels[LabelPlacementResourcePool] = sanitizeLabel(pool.Name)
+	}
+	return labels, nil
+}
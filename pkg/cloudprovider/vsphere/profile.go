@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"fmt"
+
+	"k8s.io/cloud-provider/names"
+)
+
+// Profile is a preset of the controllers run by the cloud controller manager,
+// for deployments splitting the node and the load balancer roles. The vSphere
+// cloud provider does not initialize the subsystems the controllers of the
+// profile do not use, so their credentials are not needed.
+type Profile string
+
+const (
+	// ProfileFull runs the controllers selected by --controllers.
+	ProfileFull Profile = "full"
+	// ProfileNodeOnly runs the node controllers, without NSX-T load
+	// balancers and routes.
+	ProfileNodeOnly Profile = "node-only"
+	// ProfileLBOnly runs the service controller, without discovering the VMs
+	// of the nodes in vCenter and without routes.
+	ProfileLBOnly Profile = "lb-only"
+)
+
+// ActiveProfile is the profile of the cloud controller manager, set by the
+// main program.
+var ActiveProfile = ProfileFull
+
+// ParseProfile returns the profile with the given name.
+func ParseProfile(name string) (Profile, error) {
+	switch profile := Profile(name); profile {
+	case ProfileFull, ProfileNodeOnly, ProfileLBOnly:
+		return profile, nil
+	}
+	return "", fmt.Errorf("unknown profile %q, must be %s, %s or %s", name, ProfileFull, ProfileNodeOnly, ProfileLBOnly)
+}
+
+// Controllers returns the controllers run with the profile, nil for the
+// controllers selected by --controllers.
+func (p Profile) Controllers() []string {
+	switch p {
+	case ProfileNodeOnly:
+		return []string{names.CloudNodeController, names.CloudNodeLifecycleController}
+	case ProfileLBOnly:
+		return []string{names.ServiceLBController}
+	}
+	return nil
+}
+
+// runsNodes returns true if the node controllers, which need the VMs of the
+// nodes, run with the profile.
+func (p Profile) runsNodes() bool {
+	return p != ProfileLBOnly
+}
+
+// runsLoadBalancers returns true if the service controller runs with the
+// profile.
+func (p Profile) runsLoadBalancers() bool {
+	return p != ProfileNodeOnly
+}
+
+// runsRoutes returns true if the route controller can run with the profile.
+func (p Profile) runsRoutes() bool {
+	return p == ProfileFull
+}
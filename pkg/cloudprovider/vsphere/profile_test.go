@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cloud-provider/names"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	ncfg "k8s.io/cloud-provider-vsphere/pkg/nsxt/config"
+)
+
+func TestParseProfile(t *testing.T) {
+	for _, name := range []string{"full", "node-only", "lb-only"} {
+		profile, err := ParseProfile(name)
+		if err != nil {
+			t.Errorf("profile %s: %v", name, err)
+		}
+		assert.Equal(t, Profile(name), profile)
+	}
+	if _, err := ParseProfile("routes-only"); err == nil {
+		t.Error("expected error")
+	}
+
+	assert.Nil(t, ProfileFull.Controllers())
+	assert.Equal(t, []string{names.CloudNodeController, names.CloudNodeLifecycleController}, ProfileNodeOnly.Controllers())
+	assert.Equal(t, []string{names.ServiceLBController}, ProfileLBOnly.Controllers())
+}
+
+func TestBuildVSphereNodeOnlyProfile(t *testing.T) {
+	cfg, err := ccfg.ReadCPIConfig([]byte(`[Global]
+server = 0.0.0.0
+user = user
+password = password
+datacenters = us-west
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nsxtcfg := &ncfg.Config{Host: "nsxt.local", User: "admin", Password: "secret"}
+
+	defer func(profile Profile) { ActiveProfile = profile }(ActiveProfile)
+
+	ActiveProfile = ProfileFull
+	vs, err := buildVSphereFromConfig(cfg, nsxtcfg, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotNil(t, vs.nsxtConnectorMgr.GetConnector())
+
+	// the NSX-T connector is not built without load balancers and routes
+	ActiveProfile = ProfileNodeOnly
+	vs, err = buildVSphereFromConfig(cfg, nsxtcfg, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ProfileNodeOnly, vs.profile)
+	assert.Nil(t, vs.nsxtConnectorMgr.GetConnector())
+	assert.Nil(t, vs.loadbalancer)
+	assert.Nil(t, vs.routes)
+}
@@ -19,9 +19,14 @@ package config
 import (
 	"fmt"
 
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	klog "k8s.io/klog/v2"
 )
 
+func init() {
+	vcfg.RegisterConfigSchema(RouteConfigINI{}, RouteConfigYAML{})
+}
+
 /*
 	TODO:
 	When the INI based cloud-config is deprecated, the references to the
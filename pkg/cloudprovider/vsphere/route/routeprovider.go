@@ -42,6 +42,10 @@ type RoutesProvider interface {
 	cloudprovider.Routes
 	AddNode(*v1.Node)
 	DeleteNode(*v1.Node)
+	// CleanupNode deletes the static routes of a node
+	CleanupNode(ctx context.Context, clusterName string, nodeName string) error
+	// ListNodes returns the names of the nodes having static routes
+	ListNodes(ctx context.Context, clusterName string) ([]string, error)
 }
 
 type routeProvider struct {
@@ -166,6 +170,44 @@ func (p *routeProvider) DeleteRoute(ctx context.Context, clusterName string, rou
 	return nil
 }
 
+// CleanupNode deletes the static routes of a node, which the route controller
+// may not have deleted if the node was removed while it was not running.
+func (p *routeProvider) CleanupNode(ctx context.Context, clusterName string, nodeName string) error {
+	routes, err := p.ListRoutes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		if string(route.TargetNode) != nodeName {
+			continue
+		}
+		if err := p.DeleteRoute(ctx, clusterName, route); err != nil {
+			return err
+		}
+		klog.V(2).Infof("Deleted static route %s of node %s", route.Name, nodeName)
+	}
+	return nil
+}
+
+// ListNodes returns the names of the nodes having static routes
+func (p *routeProvider) ListNodes(ctx context.Context, clusterName string) ([]string, error) {
+	routes, err := p.ListRoutes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var nodeNames []string
+	for _, route := range routes {
+		nodeName := string(route.TargetNode)
+		if nodeName == "" || seen[nodeName] {
+			continue
+		}
+		seen[nodeName] = true
+		nodeNames = append(nodeNames, nodeName)
+	}
+	return nodeNames, nil
+}
+
 // checkStaticRouteRealizedState checks static route realized state
 // The check happends every 1 second and the default timeout is 10 seconds
 // Do not delete the creating static route after the timeout
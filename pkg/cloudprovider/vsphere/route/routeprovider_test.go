@@ -36,7 +36,9 @@ import (
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 )
 
-func TestListRoutes(t *testing.T) {
+// staticRoutesResponse returns the static routes of node1 and node2 in
+// cluster kubernetes.
+func staticRoutesResponse() model.SearchResponse {
 	response := `
 {
   "results" : [ {
@@ -82,12 +84,16 @@ func TestListRoutes(t *testing.T) {
 	dataValue, _ := decoder.Decode(jsondata)
 	typeConverter := bindings.NewTypeConverter()
 	output, _ := typeConverter.ConvertToGolang(dataValue, bindings.NewReferenceType(model.SearchResponseBindingType))
-	queryParam := "resource_type:StaticRoutes AND tags.scope:vsphere.k8s.io/cluster-name AND tags.tag:kubernetes"
+	return output.(model.SearchResponse)
+}
+
+const staticRoutesQuery = "resource_type:StaticRoutes AND tags.scope:vsphere.k8s.io/cluster-name AND tags.tag:kubernetes"
 
+func TestListRoutes(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockBroker := NewMockNsxtBroker(ctrl)
-	mockBroker.EXPECT().QueryEntities(queryParam).Return(output, nil)
+	mockBroker.EXPECT().QueryEntities(staticRoutesQuery).Return(staticRoutesResponse(), nil)
 	p := &routeProvider{
 		routerPath: "/infra/tier-1s/test-t1",
 		broker:     mockBroker,
@@ -318,6 +324,25 @@ func TestDeleteRoute(t *testing.T) {
 	assert.Equal(t, nil, err, "Should not return error")
 }
 
+func TestCleanupNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockBroker := NewMockNsxtBroker(ctrl)
+	p := &routeProvider{
+		routerPath: "/infra/tier-1s/test-t1",
+		broker:     mockBroker,
+	}
+	mockBroker.EXPECT().QueryEntities(staticRoutesQuery).Return(staticRoutesResponse(), nil).Times(2)
+	mockBroker.EXPECT().DeleteStaticRoute(p.routerPath, "a4775ec4-8b68-42ea-86fc-d17390e4c373_100.96.1.0_24").Return(nil)
+
+	nodeNames, err := p.ListNodes(context.TODO(), "kubernetes")
+	assert.Equal(t, nil, err, "Should not return error")
+	assert.Equal(t, []string{"node1", "node2"}, nodeNames)
+
+	err = p.CleanupNode(context.TODO(), "kubernetes", "node2")
+	assert.Equal(t, nil, err, "Should not return error")
+}
+
 func buildFakeNode(nodeName string) *v1.Node {
 	addresses := make([]v1.NodeAddress, 2)
 	addresses = append(addresses, v1.NodeAddress{Type: v1.NodeHostName, Address: nodeName})
@@ -0,0 +1,232 @@
+//go:build soak
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	"k8s.io/cloud-provider-vsphere/pkg/util/leakcheck"
+)
+
+// soakSessionExpiry is the number of churn iterations after which every
+// vCenter session but the monitoring one is terminated, the way vCenter
+// expires idle sessions.
+const soakSessionExpiry = 10
+
+type fakeClientBuilder struct {
+	client clientset.Interface
+}
+
+func (b *fakeClientBuilder) Config(string) (*restclient.Config, error) {
+	return &restclient.Config{}, nil
+}
+
+func (b *fakeClientBuilder) ConfigOrDie(string) *restclient.Config {
+	return &restclient.Config{}
+}
+
+func (b *fakeClientBuilder) Client(string) (clientset.Interface, error) {
+	return b.client, nil
+}
+
+func (b *fakeClientBuilder) ClientOrDie(string) clientset.Interface {
+	return b.client
+}
+
+// TestSoak runs the provider against vcsim while nodes join and leave the
+// cluster and vCenter sessions expire, and fails if goroutines, heap or
+// vCenter sessions grow monotonically. Run it with "make soak".
+func TestSoak(t *testing.T) {
+	duration, err := leakcheck.Duration(2 * time.Minute)
+	if err != nil {
+		t.Fatalf("Invalid %s: %s", leakcheck.DurationEnv, err)
+	}
+
+	initCfg, cleanup := configFromSim(false)
+	defer cleanup()
+	cfg := &ccfg.CPIConfig{}
+	cfg.Config = *initCfg
+
+	var vms []*simulator.VirtualMachine
+	for _, obj := range simulator.Map.All("VirtualMachine") {
+		vm := obj.(*simulator.VirtualMachine)
+		vm.Guest.HostName = strings.ToLower(vm.Name)
+		vm.Guest.Net = []vimtypes.GuestNicInfo{
+			{
+				Network:   "foo-bar",
+				IpAddress: []string{"10.0.0.1"},
+			},
+		}
+		vms = append(vms, vm)
+	}
+
+	vs, err := newVSphere(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to construct vSphere: %s", err)
+	}
+	client := fake.NewSimpleClientset()
+	vs.Initialize(&fakeClientBuilder{client}, make(chan struct{}))
+	defer SessionLogout()
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	monitor, err := soakMonitor(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Failed to log in monitoring session: %s", err)
+	}
+
+	d := leakcheck.New()
+	d.Add("goroutines", leakcheck.Goroutines())
+	d.Add("heap_inuse_bytes", leakcheck.HeapInuse())
+	d.Add("vcenter_sessions", leakcheck.Sessions(monitor))
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx, duration/60)
+		close(done)
+	}()
+
+	for i := 0; ctx.Err() == nil; i++ {
+		vm := vms[i%len(vms)]
+		if err := soakNodeChurn(ctx, vs, client, vm); err != nil && ctx.Err() == nil {
+			t.Fatalf("Node churn failed for %s: %s", vm.Name, err)
+		}
+		if i%soakSessionExpiry == soakSessionExpiry-1 {
+			if err := expireSessions(ctx, monitor); err != nil && ctx.Err() == nil {
+				t.Fatalf("Failed to expire sessions: %s", err)
+			}
+		}
+	}
+	<-done
+
+	t.Log("\n" + d.Report())
+	if err := d.Check(); err != nil {
+		t.Error(err)
+	}
+}
+
+// soakNodeChurn adds a node backed by the VM, queries it through the
+// Instances interface and removes it again.
+func soakNodeChurn(ctx context.Context, vs *VSphere, client clientset.Interface, vm *simulator.VirtualMachine) error {
+	uuid := vm.Config.Uuid
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: strings.ToLower(vm.Name),
+		},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{
+				SystemUUID: ConvertK8sUUIDtoNormal(uuid),
+			},
+		},
+	}
+
+	if _, err := client.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	if err := waitForNode(ctx, vs, uuid, true); err != nil {
+		return err
+	}
+
+	providerID := ProviderPrefix + uuid
+	if _, err := vs.instances.NodeAddressesByProviderID(ctx, providerID); err != nil {
+		return err
+	}
+	if _, err := vs.instances.InstanceExistsByProviderID(ctx, providerID); err != nil {
+		return err
+	}
+
+	if err := client.CoreV1().Nodes().Delete(ctx, node.Name, metav1.DeleteOptions{}); err != nil {
+		return err
+	}
+	return waitForNode(ctx, vs, uuid, false)
+}
+
+func waitForNode(ctx context.Context, vs *VSphere, uuid string, registered bool) error {
+	return wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 30*time.Second, true, func(context.Context) (bool, error) {
+		vs.nodeManager.nodeRegInfoLock.RLock()
+		_, ok := vs.nodeManager.nodeRegUUIDMap[uuid]
+		vs.nodeManager.nodeRegInfoLock.RUnlock()
+		return ok == registered, nil
+	})
+}
+
+// soakMonitor logs in a session used to observe vCenter independently of
+// the provider.
+func soakMonitor(ctx context.Context, cfg *ccfg.CPIConfig) (*vim25.Client, error) {
+	u, err := soap.ParseURL(net.JoinHostPort(cfg.Global.VCenterIP, cfg.Global.VCenterPort))
+	if err != nil {
+		return nil, err
+	}
+	c, err := vim25.NewClient(ctx, soap.NewClient(u, cfg.Global.InsecureFlag))
+	if err != nil {
+		return nil, err
+	}
+	if err = session.NewManager(c).Login(ctx, url.UserPassword(cfg.Global.User, cfg.Global.Password)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// expireSessions terminates every vCenter session but the monitor's own.
+func expireSessions(ctx context.Context, monitor *vim25.Client) error {
+	m := session.NewManager(monitor)
+	current, err := m.UserSession(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sm mo.SessionManager
+	pc := property.DefaultCollector(monitor)
+	if err = pc.RetrieveOne(ctx, *monitor.ServiceContent.SessionManager, []string{"sessionList"}, &sm); err != nil {
+		return err
+	}
+
+	var keys []string
+	for _, s := range sm.SessionList {
+		if s.Key != current.Key {
+			keys = append(keys, s.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return m.TerminateSession(ctx, keys)
+}
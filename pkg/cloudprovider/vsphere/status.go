@@ -0,0 +1,237 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	cloudprovider "k8s.io/cloud-provider"
+	klog "k8s.io/klog/v2"
+
+	cpiv1alpha1 "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/apis/cloudprovider/v1alpha1"
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer"
+)
+
+const (
+	// StatusName is the name of the VSphereCloudProviderStatus object
+	// maintained by the cloud provider.
+	StatusName = "vsphere-cloud-provider"
+
+	// maxReconcileErrors is the number of reconcile errors reported in the
+	// status, the most recent ones.
+	maxReconcileErrors = 20
+)
+
+var statusGVR = cpiv1alpha1.SchemeGroupVersion.WithResource("vspherecloudproviderstatuses")
+
+// reconcileErrors keeps the last error of the reconcile of each object.
+// Its methods may be called on a nil reconcileErrors, which records nothing.
+type reconcileErrors struct {
+	lock   sync.Mutex
+	errors map[string]cpiv1alpha1.ReconcileError
+}
+
+func newReconcileErrors() *reconcileErrors {
+	return &reconcileErrors{errors: make(map[string]cpiv1alpha1.ReconcileError)}
+}
+
+// record sets the error of the reconcile of an object, or removes it if err
+// is nil as the object was reconciled.
+func (r *reconcileErrors) record(kind string, name string, err error) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := kind + "/" + name
+	if err == nil {
+		delete(r.errors, key)
+		return
+	}
+	r.errors[key] = cpiv1alpha1.ReconcileError{
+		Kind:    kind,
+		Name:    name,
+		Message: err.Error(),
+		Time:    metav1.Now(),
+	}
+}
+
+// list returns the most recent errors first, at most maxReconcileErrors.
+func (r *reconcileErrors) list() []cpiv1alpha1.ReconcileError {
+	if r == nil {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	list := make([]cpiv1alpha1.ReconcileError, 0, len(r.errors))
+	for _, e := range r.errors {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Time.Equal(&list[j].Time) {
+			return list[j].Time.Before(&list[i].Time)
+		}
+		return list[i].Kind+"/"+list[i].Name < list[j].Kind+"/"+list[j].Name
+	})
+	if len(list) > maxReconcileErrors {
+		list = list[:maxReconcileErrors]
+	}
+	return list
+}
+
+// reconcileErrorsLoadBalancer records the errors of the load balancer
+// reconciles of the Services.
+type reconcileErrorsLoadBalancer struct {
+	loadbalancer.LBProvider
+	errors *reconcileErrors
+}
+
+func (lb *reconcileErrorsLoadBalancer) EnsureLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (*v1.LoadBalancerStatus, error) {
+	status, err := lb.LBProvider.EnsureLoadBalancer(ctx, clusterName, service, nodes)
+	lb.errors.record("Service", service.Namespace+"/"+service.Name, err)
+	return status, err
+}
+
+func (lb *reconcileErrorsLoadBalancer) UpdateLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) error {
+	err := lb.LBProvider.UpdateLoadBalancer(ctx, clusterName, service, nodes)
+	lb.errors.record("Service", service.Namespace+"/"+service.Name, err)
+	return err
+}
+
+func (lb *reconcileErrorsLoadBalancer) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *v1.Service) error {
+	err := lb.LBProvider.EnsureLoadBalancerDeleted(ctx, clusterName, service)
+	lb.errors.record("Service", service.Namespace+"/"+service.Name, err)
+	return err
+}
+
+// status summarizes the configured vCenters, their connections, the nodes
+// managed in each vCenter and datacenter, the load balancer classes and the
+// last reconcile errors.
+func (vs *VSphere) status(errors *reconcileErrors) cpiv1alpha1.VSphereCloudProviderStatusStatus {
+	status := cpiv1alpha1.VSphereCloudProviderStatusStatus{
+		LastReconcileErrors: errors.list(),
+		LastUpdateTime:      metav1.Now(),
+	}
+
+	// managed nodes by tenantRef and datacenter
+	nodes := make(map[string]map[string]int)
+	nm := vs.nodeManager
+	nm.nodeRegInfoLock.RLock()
+	nm.nodeInfoLock.RLock()
+	for uuid := range nm.nodeRegUUIDMap {
+		node, ok := nm.nodeUUIDMap[uuid]
+		if !ok || node.dataCenter == nil {
+			continue
+		}
+		if nodes[node.tenantRef] == nil {
+			nodes[node.tenantRef] = make(map[string]int)
+		}
+		nodes[node.tenantRef][node.dataCenter.Name()]++
+	}
+	nm.nodeInfoLock.RUnlock()
+	nm.nodeRegInfoLock.RUnlock()
+
+	if vs.connectionManager != nil {
+		for _, connection := range vs.connectionManager.ConnectionStates() {
+			vc := cpiv1alpha1.VCenterStatus{
+				Name:      connection.VCenter,
+				Server:    connection.Hostname,
+				Port:      connection.Port,
+				Connected: connection.Connected,
+			}
+			for datacenter, count := range nodes[connection.VCenter] {
+				vc.Datacenters = append(vc.Datacenters, cpiv1alpha1.DatacenterStatus{Name: datacenter, Nodes: count})
+				vc.Nodes += count
+			}
+			sort.Slice(vc.Datacenters, func(i, j int) bool {
+				return vc.Datacenters[i].Name < vc.Datacenters[j].Name
+			})
+			status.VCenters = append(status.VCenters, vc)
+		}
+	}
+
+	if vs.isLoadBalancerSupportEnabled() {
+		status.LoadBalancerClasses = vs.loadbalancer.ClassNames()
+	}
+	return status
+}
+
+// reportStatus writes the status to the VSphereCloudProviderStatus object,
+// which is created if it does not exist.
+func (vs *VSphere) reportStatus(ctx context.Context, client dynamic.Interface, errors *reconcileErrors) error {
+	resource := client.Resource(statusGVR)
+	obj, err := resource.Get(ctx, StatusName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion(cpiv1alpha1.SchemeGroupVersion.String())
+		obj.SetKind("VSphereCloudProviderStatus")
+		obj.SetName(StatusName)
+		obj, err = resource.Create(ctx, obj, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&cpiv1alpha1.VSphereCloudProviderStatus{
+		Status: vs.status(errors),
+	})
+	if err != nil {
+		return err
+	}
+	obj.Object["status"] = status["status"]
+	_, err = resource.UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// startStatusReporter records the reconcile errors of the nodes and the
+// load balancers and reports the status every statusInterval until stop is
+// closed.
+func (vs *VSphere) startStatusReporter(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
+	config, err := clientBuilder.Config(ClientName)
+	if err != nil {
+		klog.Errorf("Failed to create the client reporting the status: %v", err)
+		return
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		klog.Errorf("Failed to create the client reporting the status: %v", err)
+		return
+	}
+
+	errors := newReconcileErrors()
+	vs.nodeManager.reconcileErrors = errors
+	if vs.isLoadBalancerSupportEnabled() {
+		vs.loadbalancer = &reconcileErrorsLoadBalancer{LBProvider: vs.loadbalancer, errors: errors}
+	}
+
+	go wait.Until(func() {
+		if err := vs.reportStatus(context.Background(), client, errors); err != nil {
+			klog.Warningf("Failed to report the status to %s %s: %v", statusGVR.GroupResource(), StatusName, err)
+		}
+	}, statusInterval, stop)
+}
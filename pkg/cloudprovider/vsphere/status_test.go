@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	cpiv1alpha1 "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/apis/cloudprovider/v1alpha1"
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+func newStatusTestDatacenter(name string) *vclib.Datacenter {
+	dc := object.NewDatacenter(nil, types.ManagedObjectReference{Type: "Datacenter", Value: "datacenter-" + name})
+	dc.InventoryPath = "/" + name
+	return &vclib.Datacenter{Datacenter: dc}
+}
+
+func TestReconcileErrors(t *testing.T) {
+	r := newReconcileErrors()
+	r.record("Node", "node-a", errors.New("not found"))
+	r.record("Service", "default/web", errors.New("no IP address left"))
+	r.record("Node", "node-b", errors.New("not found"))
+	r.record("Node", "node-b", nil)
+
+	list := r.list()
+	if len(list) != 2 {
+		t.Fatalf("expected 2 errors, but found %+v", list)
+	}
+	for _, e := range list {
+		if e.Kind == "Node" && e.Name == "node-b" {
+			t.Errorf("error of reconciled node-b should be removed")
+		}
+	}
+
+	for i := 0; i < 2*maxReconcileErrors; i++ {
+		r.record("Node", fmt.Sprintf("node-%d", i), errors.New("not found"))
+	}
+	if len(r.list()) != maxReconcileErrors {
+		t.Errorf("expected %d errors, but found %d", maxReconcileErrors, len(r.list()))
+	}
+
+	// a nil reconcileErrors records nothing
+	var disabled *reconcileErrors
+	disabled.record("Node", "node-a", errors.New("not found"))
+	if disabled.list() != nil {
+		t.Errorf("expected no errors")
+	}
+}
+
+func TestReportStatus(t *testing.T) {
+	cfg := &vcfg.Config{}
+	cfg.VirtualCenter = map[string]*vcfg.VirtualCenterConfig{
+		"vc1": {VCenterIP: "vc1", TenantRef: "vc1", VCenterPort: "443"},
+	}
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	nm := newNodeManager(&ccfg.CPIConfig{}, connMgr)
+	vs := &VSphere{connectionManager: connMgr, nodeManager: nm}
+
+	for i, dc := range []string{"dc1", "dc1", "dc2"} {
+		uuid := fmt.Sprintf("uuid-%d", i)
+		nm.nodeUUIDMap[uuid] = &NodeInfo{
+			NodeName:   fmt.Sprintf("node-%d", i),
+			UUID:       uuid,
+			tenantRef:  "vc1",
+			vcServer:   "vc1",
+			dataCenter: newStatusTestDatacenter(dc),
+		}
+		nm.addNode(uuid, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)}})
+	}
+	// discovered but not registered nodes are not managed
+	nm.nodeUUIDMap["uuid-x"] = &NodeInfo{NodeName: "node-x", UUID: "uuid-x", tenantRef: "vc1", dataCenter: newStatusTestDatacenter("dc2")}
+
+	errs := newReconcileErrors()
+	errs.record("Node", "node-y", errors.New("not found"))
+
+	scheme := runtime.NewScheme()
+	_ = cpiv1alpha1.AddToScheme(scheme)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		statusGVR: "VSphereCloudProviderStatusList",
+	})
+
+	// the object is created, then updated
+	for i := 0; i < 2; i++ {
+		if err := vs.reportStatus(context.Background(), client, errs); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	obj, err := client.Resource(statusGVR).Get(context.Background(), StatusName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	status := &cpiv1alpha1.VSphereCloudProviderStatus{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, status); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(status.Status.VCenters) != 1 {
+		t.Fatalf("expected 1 vCenter, but found %+v", status.Status.VCenters)
+	}
+	vc := status.Status.VCenters[0]
+	if vc.Name != "vc1" || vc.Connected || vc.Nodes != 3 {
+		t.Errorf("unexpected vCenter status %+v", vc)
+	}
+	expected := []cpiv1alpha1.DatacenterStatus{{Name: "dc1", Nodes: 2}, {Name: "dc2", Nodes: 1}}
+	if fmt.Sprint(vc.Datacenters) != fmt.Sprint(expected) {
+		t.Errorf("expected datacenters %+v, but found %+v", expected, vc.Datacenters)
+	}
+	if len(status.Status.LastReconcileErrors) != 1 || status.Status.LastReconcileErrors[0].Name != "node-y" {
+		t.Errorf("unexpected reconcile errors %+v", status.Status.LastReconcileErrors)
+	}
+	if status.Status.LoadBalancerClasses != nil {
+		t.Errorf("expected no load balancer classes, but found %v", status.Status.LoadBalancerClasses)
+	}
+	if time.Since(status.Status.LastUpdateTime.Time) > time.Minute {
+		t.Errorf("unexpected last update time %s", status.Status.LastUpdateTime)
+	}
+}
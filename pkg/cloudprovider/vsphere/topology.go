@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	cloudprovider "k8s.io/cloud-provider"
+	klog "k8s.io/klog/v2"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+// topology resolves the zone and the region of the nodes from the vSphere
+// tags of the zone and region categories. It is shared by the Zones and the
+// InstancesV2 interfaces.
+type topology struct {
+	nodeManager *NodeManager
+	zone        string
+	region      string
+	// disabled skips the zone lookup even if zone and region are set
+	disabled bool
+}
+
+// enabled returns true if the zone and region of the nodes are looked up in
+// vCenter, as both categories are set or a vCenter is mapped to a region or has
+// its own zone category. Otherwise the nodes have an empty zone, without
+// querying vCenter.
+func (t *topology) enabled() bool {
+	if t.disabled {
+		return false
+	}
+	if len(t.region) != 0 && len(t.zone) != 0 {
+		return true
+	}
+	return t.nodeManager != nil && t.nodeManager.connectionManager != nil &&
+		t.nodeManager.connectionManager.HasVCenterTopology()
+}
+
+// lookup returns the zone and the region of the node's VM. The tags are
+// searched on the host of the VM, then on its resource pool, then on its
+// folders. Each search walks up the inventory, e.g. from the host to its
+// cluster and its datacenter, so that the zone can be tagged on the
+// cluster and the region on the datacenter.
+func (t *topology) lookup(ctx context.Context, node *NodeInfo) (cloudprovider.Zone, error) {
+	zone := cloudprovider.Zone{}
+
+	if !t.enabled() {
+		return zone, nil
+	}
+	klog.V(4).Infof("Getting zone/region for VM %s", node.NodeName)
+
+	vmHost, err := node.vm.HostSystem(ctx)
+	if err != nil {
+		klog.Errorf("Failed to get host system for VM: %q. err: %+v", node.vm.InventoryPath, err)
+		return zone, err
+	}
+	vmRP, err := node.vm.ResourcePool(ctx)
+	if err != nil {
+		klog.Warningf("Failed to get resource pool for VM: %q. err: %+v", node.vm.InventoryPath, err)
+		vmRP = nil
+	}
+
+	var oHost mo.HostSystem
+	err = vmHost.Properties(ctx, vmHost.Reference(), []string{"summary"}, &oHost)
+	if err != nil {
+		klog.Errorf("Failed to get host system properties. err: %+v", err)
+		return zone, err
+	}
+	klog.V(4).Infof("Host owning VM is %s", oHost.Summary.Config.Name)
+
+	// Look down the compute resources
+	zoneResult, err := t.nodeManager.connectionManager.LookupZoneByMoref(
+		ctx, node.tenantRef, vmHost.Reference(), t.zone, t.region)
+	if err == nil {
+		zone.FailureDomain = zoneResult[cm.ZoneLabel]
+		zone.Region = zoneResult[cm.RegionLabel]
+		return zone, nil
+	}
+
+	// Look down the resource pools
+	if vmRP != nil {
+		zoneResult, err := t.nodeManager.connectionManager.LookupZoneByMoref(
+			ctx, node.tenantRef, vmRP.Reference(), t.zone, t.region)
+		if err == nil {
+			zone.FailureDomain = zoneResult[cm.ZoneLabel]
+			zone.Region = zoneResult[cm.RegionLabel]
+			return zone, nil
+		}
+	}
+
+	// Look down the folders path
+	zoneResult, err = t.nodeManager.connectionManager.LookupZoneByMoref(
+		ctx, node.tenantRef, node.vm.Reference(), t.zone, t.region)
+	if err != nil {
+		klog.Errorf("Failed to get host system properties. err: %+v", err)
+		return zone, err
+	}
+
+	zone.FailureDomain = zoneResult[cm.ZoneLabel]
+	zone.Region = zoneResult[cm.RegionLabel]
+
+	return zone, nil
+}
@@ -18,8 +18,12 @@ package vsphere
 
 import (
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 
 	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
@@ -36,6 +40,11 @@ import (
 type VSphere struct {
 	// input (aka configs) and output (aka interfaces)
 	cfg *ccfg.CPIConfig
+	// effectiveConfig is cfg and the configs of the pluggable interfaces,
+	// redacted for debug dumps
+	effectiveConfig *EffectiveConfig
+	// profile selects the subsystems initialized for the controllers run
+	profile Profile
 
 	/*
 		Interfaces start
@@ -46,8 +55,9 @@ type VSphere struct {
 	routes       route.RoutesProvider
 
 	// cloud provider interfaces
-	instances cloudprovider.Instances
-	zones     cloudprovider.Zones
+	instances   cloudprovider.Instances
+	instancesV2 cloudprovider.InstancesV2
+	zones       cloudprovider.Zones
 	/*
 		Interfaces end
 	*/
@@ -58,6 +68,10 @@ type VSphere struct {
 	informMgr           *k8s.InformerManager
 	nsxtConnectorMgr    *nsxt.ConnectorManager
 	nsxtSecretNamespace string
+	// cleaners of the NSX objects created per node by optional features
+	nodeCleanup *nodeCleanupRegistry
+	// optional sections of the cloud config that could not be read
+	ignoredConfigSections []string
 }
 
 // NodeInfo is information about a Kubernetes node.
@@ -70,6 +84,11 @@ type NodeInfo struct {
 	NodeName      string
 	NodeType      string
 	NodeAddresses []v1.NodeAddress
+
+	// time NodeType was read from the VM
+	nodeTypeTime time.Time
+	// node annotations describing the VM, see vmMetadataAnnotations
+	vmAnnotations map[string]string
 }
 
 // DatacenterInfo is information about a vCenter datascenter.
@@ -99,6 +118,43 @@ type NodeManager struct {
 
 	// Reference to CPI-specific configuration
 	cfg *ccfg.CPIConfig
+	// Subnets and VM network names selecting the node addresses, parsed
+	// from cfg when the node manager is built
+	networks nodeNetworks
+	// Error parsing networks, returned by DiscoverNode. It is nil for a
+	// config that passed validation.
+	networksErr error
+
+	// NSX-T address lookups, nil unless Nodes.NSXAddressSource is set
+	nsxtBroker nsxtAddressBroker
+	// Webhook adjusting the discovered addresses, nil unless
+	// Nodes.AddressWebhookURL is set
+	addressWebhook *addressWebhook
+
+	// Age after which NodeType is read again from the VM, 0 to never
+	instanceTypeTTL time.Duration
+	// Period at which the addresses of the registered nodes are discovered
+	// again, 0 to never
+	addressResyncPeriod time.Duration
+	// Period at which the custom attributes and tags of the VMs of the
+	// registered nodes are applied as node labels, 0 to never
+	vmLabelResyncPeriod time.Duration
+	// How long previously discovered nodes are still reported as existing
+	// while their VM is not found, 0 to report them as deleted right away
+	vmNotFoundGracePeriod time.Duration
+	// Maps the UUID of previously discovered nodes to the time their VM was
+	// first not found, guarded by nodeInfoLock
+	vmNotFoundSince map[string]time.Time
+	// Client used to update the instance type labels of nodes
+	kubeClient clientset.Interface
+	// Lister of the nodes whose IP annotations override the discovered
+	// addresses, nil until the cloud provider is initialized
+	nodeLister listerv1.NodeLister
+	// Errors of the node registrations, nil unless the status is reported
+	reconcileErrors *reconcileErrors
+	// Recorder of the events on nodes whose discovery failed, nil until
+	// the cloud provider is initialized
+	recorder record.EventRecorder
 
 	// Mutexes
 	nodeInfoLock    sync.RWMutex
@@ -110,9 +166,7 @@ type instances struct {
 }
 
 type zones struct {
-	nodeManager *NodeManager
-	zone        string
-	region      string
+	topology
 }
 
 // GuestOSLookup is a table for quick lookup between guestOsIdentifier and a shorthand name
@@ -18,10 +18,12 @@ package vsphere
 
 import (
 	"fmt"
-	"net"
+	"net/netip"
 	"strings"
+	"unicode"
 
 	"github.com/pkg/errors"
+	"golang.org/x/text/unicode/norm"
 	klog "k8s.io/klog/v2"
 )
 
@@ -65,9 +67,13 @@ func ConvertK8sUUIDtoNormal(k8sUUID string) string {
 // ErrOnLocalOnlyIPAddr returns an error if the provided IP address is
 // accessible only on the VM's guest OS.
 func ErrOnLocalOnlyIPAddr(addr string) error {
+	return errOnLocalOnlyAddr(addr, parseAddr(addr))
+}
+
+// errOnLocalOnlyAddr is ErrOnLocalOnlyIPAddr for the already parsed addr a.
+func errOnLocalOnlyAddr(addr string, a netip.Addr) error {
 	var reason string
-	a := net.ParseIP(addr)
-	if a == nil {
+	if !a.IsValid() {
 		reason = "invalid"
 	} else if a.IsUnspecified() {
 		reason = "unspecified"
@@ -85,12 +91,46 @@ func ErrOnLocalOnlyIPAddr(addr string) error {
 }
 
 // ArrayContainsCaseInsensitive detects whether a given array of string contains
-// the given string, ignoring case.
+// the given string, ignoring case and the invisible differences removed by
+// normalizeName.
 func ArrayContainsCaseInsensitive(arr []string, str string) bool {
+	str = normalizeName(str)
 	for _, a := range arr {
-		if strings.EqualFold(a, str) {
+		if strings.EqualFold(normalizeName(a), str) {
 			return true
 		}
 	}
 	return false
 }
+
+// normalizeName returns the name in Unicode NFC form, with invisible format
+// characters (zero-width spaces, byte order marks, ...) removed, Unicode
+// spaces replaced by ASCII ones and surrounding whitespace trimmed.
+func normalizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.Is(unicode.Cf, r):
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, norm.NFC.String(name))
+	return strings.TrimSpace(name)
+}
+
+// networkNamesMatch reports whether a configured network name designates a
+// network name reported by VMware Tools. Both are normalized and compared
+// ignoring case. vSphere reports some distributed portgroups prefixed with
+// the name of their switch ("DSwitch/portgroup"), so either name also
+// matches the other prefixed by a switch name.
+func networkNamesMatch(configured, reported string) bool {
+	configured = strings.ToLower(normalizeName(configured))
+	reported = strings.ToLower(normalizeName(reported))
+	if configured == "" || reported == "" {
+		return false
+	}
+	return configured == reported ||
+		strings.HasSuffix(reported, "/"+configured) ||
+		strings.HasSuffix(configured, "/"+reported)
+}
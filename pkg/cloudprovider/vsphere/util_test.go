@@ -145,3 +145,50 @@ func TestArrayContainsCaseInsensitive(t *testing.T) {
 		t.Errorf("Found ThirdMakesACrowd")
 	}
 }
+
+func TestArrayContainsCaseInsensitiveNormalizes(t *testing.T) {
+	// "Réseau" spelled with a combining acute accent (NFD)
+	arr := []string{"Re\u0301seau", "VM\u00a0Network", "k8s\u200b-nodes "}
+
+	if !ArrayContainsCaseInsensitive(arr, "r\u00e9seau") {
+		t.Errorf("Failed to find r\u00e9seau in NFC form")
+	}
+
+	if !ArrayContainsCaseInsensitive(arr, "vm network") {
+		t.Errorf("Failed to find vm network with an ASCII space")
+	}
+
+	if !ArrayContainsCaseInsensitive(arr, "K8S-nodes") {
+		t.Errorf("Failed to find K8S-nodes without the zero-width space")
+	}
+
+	if ArrayContainsCaseInsensitive(arr, "k8s nodes") {
+		t.Errorf("Found k8s nodes")
+	}
+}
+
+func TestNetworkNamesMatch(t *testing.T) {
+	testcases := []struct {
+		configured string
+		reported   string
+		expected   bool
+	}{
+		{"VM Network", "VM Network", true},
+		{"vm network", "VM Network", true},
+		{"VM Network", "DSwitch/VM Network", true},
+		{"DSwitch/VM Network", "VM Network", true},
+		{"DSwitch/VM Network", "dswitch/vm network", true},
+		{"DSwitch/VM Network", "DSwitch2/VM Network", false},
+		{"Network", "VM Network", false},
+		{"Network", "DSwitch/VM-Network", false},
+		{"VM Network", "VM Network\u200b", true},
+		{"", "VM Network", false},
+		{"VM Network", "", false},
+	}
+
+	for _, testcase := range testcases {
+		if actual := networkNamesMatch(testcase.configured, testcase.reported); actual != testcase.expected {
+			t.Errorf("networkNamesMatch(%q, %q) = %t, expected %t", testcase.configured, testcase.reported, actual, testcase.expected)
+		}
+	}
+}
@@ -19,6 +19,8 @@ package vsphere
 import (
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/log"
 	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // klogBridge is a connector for the vapi logger to klog
@@ -49,10 +51,12 @@ func (d klogBridge) Infof(a string, args ...interface{}) {
 	klog.Infof(a, args...)
 }
 
+// Debug logs of the SDK follow the verbosity of the loadbalancer module, as
+// most NSX-T calls are made by the load balancer.
 func (d klogBridge) Debug(args ...interface{}) {
-	klog.V(4).Info(args...)
+	logging.V(logging.LoadBalancer, 4).Info(args...)
 }
 
 func (d klogBridge) Debugf(a string, args ...interface{}) {
-	klog.V(4).Infof(a, args...)
+	logging.V(logging.LoadBalancer, 4).Infof(a, args...)
 }
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	klog "k8s.io/klog/v2"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// AnnotationVCenter is the node annotation pinning the node to the vCenter,
+// written as vcenter or vcenter/datacenter, its VM is searched in first. The
+// vCenter is the name of its VirtualCenter section or its server address.
+const AnnotationVCenter = "vsphere.cpi.kubernetes.io/vcenter"
+
+// vCenterPin returns the vCenter the VM of the node named nodeName is searched
+// in first: the one annotated on the node, else the one its node pool is
+// mapped to. The node is not pinned if it or the node lister are not known.
+func (nm *NodeManager) vCenterPin(nodeName string) cm.VCenterPin {
+	if nm.nodeLister == nil || nodeName == "" {
+		return cm.VCenterPin{}
+	}
+	node, err := nm.nodeLister.Get(nodeName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Warningf("Failed to get node %s to read the vCenter it is pinned to: %v", nodeName, err)
+		}
+		return cm.VCenterPin{}
+	}
+
+	if value := node.Annotations[AnnotationVCenter]; value != "" {
+		pin := cm.ParseVCenterPin(value)
+		logging.V(logging.NodeManager, 4).Infof("Node %s is pinned to %s by annotation %s", nodeName, pin, AnnotationVCenter)
+		return pin
+	}
+	if nm.cfg == nil || nm.cfg.Nodes.NodePoolLabel == "" {
+		return cm.VCenterPin{}
+	}
+	pool, ok := node.Labels[nm.cfg.Nodes.NodePoolLabel]
+	if !ok {
+		return cm.VCenterPin{}
+	}
+	if value, ok := nm.cfg.Nodes.NodePoolVCenters[pool]; ok {
+		pin := cm.ParseVCenterPin(value)
+		logging.V(logging.NodeManager, 4).Infof("Node %s of node pool %s is pinned to %s", nodeName, pool, pin)
+		return pin
+	}
+	return cm.VCenterPin{}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestVCenterPin(t *testing.T) {
+	const poolLabel = "example.com/pool"
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{
+			Name:        "annotated",
+			Annotations: map[string]string{AnnotationVCenter: "vc1.example.com/dc1"},
+			Labels:      map[string]string{poolLabel: "pool-a"},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pooled", Labels: map[string]string{poolLabel: "pool-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unmapped", Labels: map[string]string{poolLabel: "pool-b"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+	} {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nm := newNodeManager(&ccfg.CPIConfig{Nodes: ccfg.Nodes{
+		NodePoolLabel:    poolLabel,
+		NodePoolVCenters: map[string]string{"pool-a": "vc2.example.com"},
+	}}, nil)
+	nm.nodeLister = listerv1.NewNodeLister(indexer)
+
+	testcases := []struct {
+		nodeName string
+		pin      cm.VCenterPin
+	}{
+		{nodeName: "annotated", pin: cm.VCenterPin{VCenter: "vc1.example.com", Datacenter: "dc1"}},
+		{nodeName: "pooled", pin: cm.VCenterPin{VCenter: "vc2.example.com"}},
+		{nodeName: "unmapped"},
+		{nodeName: "unlabeled"},
+		{nodeName: "unknown"},
+		{nodeName: ""},
+	}
+	for _, testcase := range testcases {
+		if pin := nm.vCenterPin(testcase.nodeName); pin != testcase.pin {
+			t.Errorf("expected node %q to be pinned to %+v, got %+v", testcase.nodeName, testcase.pin, pin)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// EventReasonVMDiscovered is the reason of the event recorded when the VM of
+// a node looked up by name is discovered for the first time, stating which
+// lookup method found which VM.
+const EventReasonVMDiscovered = "VMDiscovered"
+
+// vmLookupSearchBy maps the VM lookup methods of the Nodes section to the
+// searches of the connection manager.
+var vmLookupSearchBy = map[string]cm.FindVM{
+	ccfg.VMLookupByName: cm.FindVMByName,
+	ccfg.VMLookupByIP:   cm.FindVMByIP,
+}
+
+// findVMByName searches the VM of the node named nodeName with the lookup
+// methods in the configured order, and returns the first VM found. If UUID
+// matches are required, a VM whose UUID does not match the node is skipped,
+// and the mismatch returned if no other method finds a VM.
+func (nm *NodeManager) findVMByName(ctx context.Context, nodeName string, pin cm.VCenterPin) (*cm.VMDiscoveryInfo, error) {
+	methods := ccfg.DefaultVMLookupOrder
+	if nm.cfg != nil {
+		var err error
+		if methods, err = nm.cfg.Nodes.VMLookupMethods(); err != nil {
+			return nil, err
+		}
+	}
+
+	err := vclib.ErrNoVMFound
+	var mismatch error
+	for _, method := range methods {
+		var vmDI *cm.VMDiscoveryInfo
+		vmDI, err = nm.connectionManager.WhichVCandDCByNodeIDPinned(ctx, nodeName, vmLookupSearchBy[method], pin)
+		if err == vclib.ErrNoVMFound {
+			logging.V(logging.NodeManager, 4).Infof("No VM of node %s found by %s", nodeName, method)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if mismatch = nm.confirmVMUUID(nodeName, vmDI); mismatch != nil {
+			klog.Warningf("Skipping VM %s found by %s for node %s: %v", vmDI.VM.Reference().Value, method, nodeName, mismatch)
+			continue
+		}
+		nm.auditVMDiscovery(nodeName, method, vmDI)
+		return vmDI, nil
+	}
+
+	if mismatch != nil {
+		return nil, mismatch
+	}
+	klog.Errorf("WhichVCandDCByNodeID failed using VM name. Err: %v", err)
+	return nil, err
+}
+
+// confirmVMUUID returns errVMUUIDMismatch if UUID matches are required and
+// the UUID of the VM found differs from the one of the node, or the node's
+// UUID is not known yet.
+func (nm *NodeManager) confirmVMUUID(nodeName string, vmDI *cm.VMDiscoveryInfo) error {
+	if nm.cfg == nil || !nm.cfg.Nodes.RequireUUIDMatch {
+		return nil
+	}
+	expected := nm.expectedVMUUID(nodeName)
+	if expected == "" {
+		return fmt.Errorf("%w: UUID of node %s is not known", errVMUUIDMismatch, nodeName)
+	}
+	if !matchesVMUUID(vmDI.UUID, expected) {
+		return fmt.Errorf("%w: VM UUID %s, node UUID %s", errVMUUIDMismatch, vmDI.UUID, expected)
+	}
+	return nil
+}
+
+// expectedVMUUID returns the UUID of the VM of the node named nodeName, its
+// VM UUID annotation or SystemUUID, "" if the node is not known.
+func (nm *NodeManager) expectedVMUUID(nodeName string) string {
+	if nm.nodeLister != nil {
+		node, err := nm.nodeLister.Get(nodeName)
+		if err == nil {
+			if vmUUID := nm.annotatedVMUUID(node); vmUUID != "" {
+				return vmUUID
+			}
+			return node.Status.NodeInfo.SystemUUID
+		}
+		if !apierrors.IsNotFound(err) {
+			klog.Warningf("Failed to get node %s to confirm the UUID of its VM: %v", nodeName, err)
+		}
+	}
+
+	nm.nodeRegInfoLock.RLock()
+	defer nm.nodeRegInfoLock.RUnlock()
+	for uuid, node := range nm.nodeRegUUIDMap {
+		if node.Name == nodeName {
+			return uuid
+		}
+	}
+	return ""
+}
+
+// matchesVMUUID returns true if vmUUID equals expected, either as is or in
+// the byte order of the SystemUUID reported by older guests.
+func matchesVMUUID(vmUUID string, expected string) bool {
+	if strings.EqualFold(vmUUID, expected) {
+		return true
+	}
+	return len(expected) >= MinUUIDLen && strings.EqualFold(vmUUID, ConvertK8sUUIDtoNormal(expected))
+}
+
+// auditVMDiscovery logs which lookup method found which VM for the node named
+// nodeName, and records it on the node the first time the VM is discovered.
+func (nm *NodeManager) auditVMDiscovery(nodeName string, method string, vmDI *cm.VMDiscoveryInfo) {
+	datacenter := ""
+	if vmDI.DataCenter != nil {
+		datacenter = vmDI.DataCenter.Name()
+	}
+	klog.Infof("Discovered VM %s (UUID %s, guest hostname %q) of node %s by %s in vCenter %s, datacenter %s",
+		vmDI.VM.Reference().Value, vmDI.UUID, vmDI.NodeName, nodeName, method, vmDI.VcServer, datacenter)
+
+	nm.nodeInfoLock.RLock()
+	_, known := nm.nodeUUIDMap[vmDI.UUID]
+	nm.nodeInfoLock.RUnlock()
+	if known || nm.recorder == nil {
+		return
+	}
+	ref := &v1.ObjectReference{
+		Kind: "Node",
+		Name: nodeName,
+		UID:  types.UID(nodeName),
+	}
+	nm.recorder.Eventf(ref, v1.EventTypeNormal, EventReasonVMDiscovered,
+		"Discovered VM %s (UUID %s) by %s in vCenter %s, datacenter %s",
+		vmDI.VM.Reference().Value, vmDI.UUID, method, vmDI.VcServer, datacenter)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+func TestDiscoverNodeVMLookupOrder(t *testing.T) {
+	cfg, fin := configFromEnvOrSim(true)
+	defer fin()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{{Network: "foo-bar", IpAddress: []string{"10.0.0.1"}}}
+
+	if err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	testcases := []struct {
+		order string
+		err   error
+	}{
+		{order: "", err: nil},
+		{order: "ip,name", err: nil},
+		{order: "ip", err: vclib.ErrNoVMFound},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.order, func(t *testing.T) {
+			nm := newNodeManager(&ccfg.CPIConfig{Nodes: ccfg.Nodes{VMLookupOrder: testcase.order}}, connMgr)
+			recorder := record.NewFakeRecorder(10)
+			nm.recorder = recorder
+
+			if err := nm.DiscoverNode(vm.Name, cm.FindVMByName); err != testcase.err {
+				t.Fatalf("expected error %v, got %v", testcase.err, err)
+			}
+			if testcase.err != nil {
+				return
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.HasPrefix(event, v1.EventTypeNormal+" "+EventReasonVMDiscovered) || !strings.Contains(event, "by name") {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				t.Error("expected an event for the discovered VM")
+			}
+
+			// a VM discovered before is not recorded again
+			if err := nm.DiscoverNode(vm.Name, cm.FindVMByName); err != nil {
+				t.Fatalf("Failed DiscoverNode: %s", err)
+			}
+			select {
+			case event := <-recorder.Events:
+				t.Errorf("unexpected event %q", event)
+			default:
+			}
+		})
+	}
+}
+
+func TestDiscoverNodeRequireUUIDMatch(t *testing.T) {
+	cfg, fin := configFromEnvOrSim(true)
+	defer fin()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{{Network: "foo-bar", IpAddress: []string{"10.0.0.1"}}}
+
+	if err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP]); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	testcases := []struct {
+		name       string
+		systemUUID string
+		mismatch   bool
+	}{
+		{name: "unknown node", mismatch: true},
+		{name: "other VM", systemUUID: "00000000-0000-0000-0000-000000000000", mismatch: true},
+		{name: "same VM", systemUUID: vm.Config.Uuid},
+		{name: "same VM reversed", systemUUID: ConvertK8sUUIDtoNormal(vm.Config.Uuid)},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if testcase.systemUUID != "" {
+				node := &v1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: vm.Name},
+					Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{SystemUUID: testcase.systemUUID}},
+				}
+				if err := indexer.Add(node); err != nil {
+					t.Fatal(err)
+				}
+			}
+			nm := newNodeManager(&ccfg.CPIConfig{Nodes: ccfg.Nodes{RequireUUIDMatch: true}}, connMgr)
+			nm.nodeLister = listerv1.NewNodeLister(indexer)
+			recorder := record.NewFakeRecorder(10)
+			nm.recorder = recorder
+
+			err := nm.DiscoverNode(vm.Name, cm.FindVMByName)
+			if !testcase.mismatch {
+				if err != nil {
+					t.Fatalf("Failed DiscoverNode: %s", err)
+				}
+				if len(nm.nodeUUIDMap) != 1 {
+					t.Errorf("the discovered node should be cached")
+				}
+				return
+			}
+			if !errors.Is(err, errVMUUIDMismatch) {
+				t.Fatalf("expected a UUID mismatch, got %v", err)
+			}
+			if len(nm.nodeNameMap) != 0 || len(nm.nodeUUIDMap) != 0 {
+				t.Errorf("a VM whose UUID does not match should not be cached")
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.HasPrefix(event, v1.EventTypeWarning+" "+EventReasonVMUUIDMismatch) {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				t.Error("expected an event for the UUID mismatch")
+			}
+		})
+	}
+}
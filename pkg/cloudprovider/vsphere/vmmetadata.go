@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// AnnotationVMCreateDate is the node annotation holding the creation
+	// date of the node's VM, in RFC 3339 format.
+	AnnotationVMCreateDate = "vsphere.vmware.com/vm-create-date"
+	// AnnotationSourceTemplate is the node annotation holding the name of
+	// the template the node's VM was built from, if the VM records it.
+	AnnotationSourceTemplate = "vsphere.vmware.com/source-template"
+
+	// DefaultSourceTemplateKey is the key of the VM's extraConfig holding
+	// the source template when Nodes.SourceTemplateKey is not set.
+	DefaultSourceTemplateKey = "guestinfo.source-template"
+)
+
+// vmMetadataAnnotations returns the node annotations describing the VM:
+// its creation date and, if its extraConfig records it under
+// sourceTemplateKey, the template it was built from.
+func vmMetadataAnnotations(config *types.VirtualMachineConfigInfo, sourceTemplateKey string) map[string]string {
+	annotations := make(map[string]string)
+	if config == nil {
+		return annotations
+	}
+
+	if config.CreateDate != nil && !config.CreateDate.IsZero() {
+		annotations[AnnotationVMCreateDate] = config.CreateDate.UTC().Format(time.RFC3339)
+	}
+	for _, option := range config.ExtraConfig {
+		value := option.GetOptionValue()
+		if value.Key != sourceTemplateKey {
+			continue
+		}
+		if template, ok := value.Value.(string); ok && strings.TrimSpace(template) != "" {
+			annotations[AnnotationSourceTemplate] = strings.TrimSpace(template)
+		}
+	}
+	return annotations
+}
+
+func (nm *NodeManager) sourceTemplateKey() string {
+	if nm.cfg != nil && nm.cfg.Nodes.SourceTemplateKey != "" {
+		return nm.cfg.Nodes.SourceTemplateKey
+	}
+	return DefaultSourceTemplateKey
+}
+
+// updateVMMetadataAnnotations adds the VM metadata annotations of the
+// discovered VM to the node, if they are missing or differ. Annotations
+// the VM no longer has, such as a source template removed from its
+// extraConfig, are left on the node.
+func (nm *NodeManager) updateVMMetadataAnnotations(ctx context.Context, node *v1.Node, uuid string) error {
+	if nm.kubeClient == nil {
+		return nil
+	}
+
+	nm.nodeInfoLock.RLock()
+	nodeInfo, ok := nm.nodeUUIDMap[uuid]
+	nm.nodeInfoLock.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	changed := make(map[string]string)
+	for key, value := range nodeInfo.vmAnnotations {
+		if node.Annotations[key] != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": changed,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.V(logging.NodeManager, 2).Infof("Updating the VM metadata annotations of node %s: %v", node.Name, changed)
+	_, err = nm.kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, apitypes.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
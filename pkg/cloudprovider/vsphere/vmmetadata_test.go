@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ccfg "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+)
+
+func TestVMMetadataAnnotations(t *testing.T) {
+	createDate := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	config := &vimtypes.VirtualMachineConfigInfo{
+		CreateDate: &createDate,
+		ExtraConfig: []vimtypes.BaseOptionValue{
+			&vimtypes.OptionValue{Key: "guestinfo.metadata", Value: "e30="},
+			&vimtypes.OptionValue{Key: "image.template", Value: " ubuntu-2204-v1.30 "},
+		},
+	}
+
+	annotations := vmMetadataAnnotations(config, "image.template")
+	if annotations[AnnotationVMCreateDate] != "2024-03-01T11:30:00Z" {
+		t.Errorf("Unexpected create date %q", annotations[AnnotationVMCreateDate])
+	}
+	if annotations[AnnotationSourceTemplate] != "ubuntu-2204-v1.30" {
+		t.Errorf("Unexpected source template %q", annotations[AnnotationSourceTemplate])
+	}
+
+	annotations = vmMetadataAnnotations(config, DefaultSourceTemplateKey)
+	if _, ok := annotations[AnnotationSourceTemplate]; ok {
+		t.Error("Source template should not be set without the extraConfig key")
+	}
+	if len(vmMetadataAnnotations(nil, DefaultSourceTemplateKey)) != 0 {
+		t.Error("No annotations expected without VM config")
+	}
+}
+
+func TestRegisterNodeVMMetadataAnnotations(t *testing.T) {
+	cfg, ok := configFromEnvOrSim(true)
+	defer ok()
+
+	connMgr := cm.NewConnectionManager(cfg, nil, nil)
+	defer connMgr.Logout()
+
+	cpiCfg := &ccfg.CPIConfig{}
+	cpiCfg.Nodes.SourceTemplateKey = "image.template"
+	nm := newNodeManager(cpiCfg, connMgr)
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	vm.Guest.Net = []vimtypes.GuestNicInfo{
+		{
+			Network:   "foo-bar",
+			IpAddress: []string{"10.0.0.1"},
+		},
+	}
+	createDate := time.Date(2024, 3, 1, 11, 30, 0, 0, time.UTC)
+	vm.Config.CreateDate = &createDate
+	vm.Config.ExtraConfig = append(vm.Config.ExtraConfig,
+		&vimtypes.OptionValue{Key: "image.template", Value: "ubuntu-2204-v1.30"})
+
+	err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[cfg.Global.VCenterIP])
+	if err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        vm.Guest.HostName,
+			Annotations: map[string]string{"team": "platform"},
+		},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{SystemUUID: ConvertK8sUUIDtoNormal(vm.Config.Uuid)},
+		},
+	}
+	nm.kubeClient = fake.NewSimpleClientset(node)
+	nm.RegisterNode(node)
+
+	updated, err := nm.kubeClient.CoreV1().Nodes().Get(context.Background(), node.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"team":                   "platform",
+		AnnotationVMCreateDate:   "2024-03-01T11:30:00Z",
+		AnnotationSourceTemplate: "ubuntu-2204-v1.30",
+	}
+	for key, value := range expected {
+		if updated.Annotations[key] != value {
+			t.Errorf("Expected annotation %s=%s, got %q", key, value, updated.Annotations[key])
+		}
+	}
+}
@@ -29,11 +29,17 @@ import (
 	cm "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
 )
 
-func newZones(nodeManager *NodeManager, zone string, region string) cloudprovider.Zones {
+func newZones(nodeManager *NodeManager, zone string, region string, disabled bool) cloudprovider.Zones {
+	if disabled {
+		klog.Info("Zone and region labeling is disabled")
+	}
 	return &zones{
-		nodeManager: nodeManager,
-		zone:        zone,
-		region:      region,
+		topology{
+			nodeManager: nodeManager,
+			zone:        zone,
+			region:      region,
+			disabled:    disabled,
+		},
 	}
 }
 
@@ -45,7 +51,7 @@ func (z *zones) GetZone(ctx context.Context) (cloudprovider.Zone, error) {
 
 	zone := cloudprovider.Zone{}
 
-	if len(z.region) == 0 || len(z.zone) == 0 {
+	if !z.enabled() {
 		return zone, nil
 	}
 
@@ -96,7 +102,7 @@ func (z *zones) GetZoneByNodeName(ctx context.Context, nodeName k8stypes.NodeNam
 
 	zone := cloudprovider.Zone{}
 
-	if len(z.region) == 0 || len(z.zone) == 0 {
+	if !z.enabled() {
 		return zone, nil
 	}
 
@@ -105,59 +111,8 @@ func (z *zones) GetZoneByNodeName(ctx context.Context, nodeName k8stypes.NodeNam
 		klog.V(2).Info("zones.GetZoneByNodeName() NOT FOUND with ", string(nodeName))
 		return zone, ErrVMNotFound
 	}
-	klog.V(4).Infof("Getting zone/region for VM %s", node.NodeName)
-
-	vmHost, err := node.vm.HostSystem(ctx)
-	if err != nil {
-		klog.Errorf("Failed to get host system for VM: %q. err: %+v", node.vm.InventoryPath, err)
-		return zone, err
-	}
-	vmRP, err := node.vm.ResourcePool(ctx)
-	if err != nil {
-		klog.Warningf("Failed to get resource pool for VM: %q. err: %+v", node.vm.InventoryPath, err)
-		vmRP = nil
-	}
-
-	var oHost mo.HostSystem
-	err = vmHost.Properties(ctx, vmHost.Reference(), []string{"summary"}, &oHost)
-	if err != nil {
-		klog.Errorf("Failed to get host system properties. err: %+v", err)
-		return zone, err
-	}
-	klog.V(4).Infof("Host owning VM is %s", oHost.Summary.Config.Name)
 
-	// Look down the compute resources
-	zoneResult, err := z.nodeManager.connectionManager.LookupZoneByMoref(
-		ctx, node.tenantRef, vmHost.Reference(), z.zone, z.region)
-	if err == nil {
-		zone.FailureDomain = zoneResult[cm.ZoneLabel]
-		zone.Region = zoneResult[cm.RegionLabel]
-		return zone, nil
-	}
-
-	// Look down the resource pools
-	if vmRP != nil {
-		zoneResult, err := z.nodeManager.connectionManager.LookupZoneByMoref(
-			ctx, node.tenantRef, vmRP.Reference(), z.zone, z.region)
-		if err == nil {
-			zone.FailureDomain = zoneResult[cm.ZoneLabel]
-			zone.Region = zoneResult[cm.RegionLabel]
-			return zone, nil
-		}
-	}
-
-	// Look down the folders path
-	zoneResult, err = z.nodeManager.connectionManager.LookupZoneByMoref(
-		ctx, node.tenantRef, node.vm.Reference(), z.zone, z.region)
-	if err != nil {
-		klog.Errorf("Failed to get host system properties. err: %+v", err)
-		return zone, err
-	}
-
-	zone.FailureDomain = zoneResult[cm.ZoneLabel]
-	zone.Region = zoneResult[cm.RegionLabel]
-
-	return zone, nil
+	return z.lookup(ctx, node)
 }
 
 // GetZoneByProviderID implements Zones.GetZone for Out-Tree providers
@@ -166,7 +121,7 @@ func (z *zones) GetZoneByProviderID(ctx context.Context, providerID string) (clo
 
 	zone := cloudprovider.Zone{}
 
-	if len(z.region) == 0 || len(z.zone) == 0 {
+	if !z.enabled() {
 		return zone, nil
 	}
 
@@ -176,57 +131,6 @@ func (z *zones) GetZoneByProviderID(ctx context.Context, providerID string) (clo
 		klog.V(2).Info("zones.GetZoneByProviderID() NOT FOUND with ", uid)
 		return zone, ErrVMNotFound
 	}
-	klog.V(4).Infof("Getting zone/region for VM %s", node.NodeName)
 
-	vmHost, err := node.vm.HostSystem(ctx)
-	if err != nil {
-		klog.Errorf("Failed to get host system for VM: %q. err: %+v", node.vm.InventoryPath, err)
-		return zone, err
-	}
-	vmRP, err := node.vm.ResourcePool(ctx)
-	if err != nil {
-		klog.Warningf("Failed to get resource pool for VM: %q. err: %+v", node.vm.InventoryPath, err)
-		vmRP = nil
-	}
-
-	var oHost mo.HostSystem
-	err = vmHost.Properties(ctx, vmHost.Reference(), []string{"summary"}, &oHost)
-	if err != nil {
-		klog.Errorf("Failed to get host system properties. err: %+v", err)
-		return zone, err
-	}
-	klog.V(4).Infof("Host owning VM is %s", oHost.Summary.Config.Name)
-
-	// Look down the compute resources
-	zoneResult, err := z.nodeManager.connectionManager.LookupZoneByMoref(
-		ctx, node.tenantRef, vmHost.Reference(), z.zone, z.region)
-	if err == nil {
-		zone.FailureDomain = zoneResult[cm.ZoneLabel]
-		zone.Region = zoneResult[cm.RegionLabel]
-		return zone, nil
-	}
-
-	// Look down the resource pools
-	if vmRP != nil {
-		zoneResult, err := z.nodeManager.connectionManager.LookupZoneByMoref(
-			ctx, node.tenantRef, vmRP.Reference(), z.zone, z.region)
-		if err == nil {
-			zone.FailureDomain = zoneResult[cm.ZoneLabel]
-			zone.Region = zoneResult[cm.RegionLabel]
-			return zone, nil
-		}
-	}
-
-	// Look down the folders path
-	zoneResult, err = z.nodeManager.connectionManager.LookupZoneByMoref(
-		ctx, node.tenantRef, node.vm.Reference(), z.zone, z.region)
-	if err != nil {
-		klog.Errorf("Failed to get host system properties. err: %+v", err)
-		return zone, err
-	}
-
-	zone.FailureDomain = zoneResult[cm.ZoneLabel]
-	zone.Region = zoneResult[cm.RegionLabel]
-
-	return zone, nil
+	return z.lookup(ctx, node)
 }
@@ -20,6 +20,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cloudprovider "k8s.io/cloud-provider"
 
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/simulator"
@@ -49,7 +50,7 @@ func TestZones(t *testing.T) {
 	defer connMgr.Logout()
 
 	nm := newNodeManager(nil, connMgr)
-	zones := newZones(nm, cfg.Labels.Zone, cfg.Labels.Region)
+	zones := newZones(nm, cfg.Labels.Zone, cfg.Labels.Region, cfg.Labels.Disabled)
 
 	// Create vSphere client
 	err := connMgr.Connect(ctx, connMgr.VsphereInstanceMap[cfg.Global.VCenterIP])
@@ -230,3 +231,24 @@ func TestZones(t *testing.T) {
 		}
 	}
 }
+
+func TestZonesDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	// No VM is registered, so any lookup in vCenter would fail
+	nm := newNodeManager(nil, nil)
+	zones := newZones(nm, "k8s-zone", "k8s-region", true)
+
+	zone, err := zones.GetZone(ctx)
+	if err != nil || zone != (cloudprovider.Zone{}) {
+		t.Errorf("GetZone() should return an empty zone, actual=%+v, err=%v", zone, err)
+	}
+	zone, err = zones.GetZoneByNodeName(ctx, "node1")
+	if err != nil || zone != (cloudprovider.Zone{}) {
+		t.Errorf("GetZoneByNodeName() should return an empty zone, actual=%+v, err=%v", zone, err)
+	}
+	zone, err = zones.GetZoneByProviderID(ctx, ProviderPrefix+"423740e7-7fae-4a8a-bbc9-bf2cf5b6bd88")
+	if err != nil || zone != (cloudprovider.Zone{}) {
+		t.Errorf("GetZoneByProviderID() should return an empty zone, actual=%+v, err=%v", zone, err)
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"time"
 
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/yaml"
@@ -31,6 +33,8 @@ import (
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
 	cpcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	k8s "k8s.io/cloud-provider-vsphere/pkg/common/kubernetes"
+	"k8s.io/cloud-provider-vsphere/pkg/util/health"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 const (
@@ -62,8 +66,22 @@ var (
 
 	// podIPPoolType specifies if Pod IP addresses are public or private.
 	podIPPoolType string
+
+	// routeDriftCheckInterval is the interval of the drift detection of the Route CRs, 0 to disable it
+	routeDriftCheckInterval time.Duration
+
+	// ownerRefRefreshInterval is the interval of the refresh of the owner reference of the TanzuKubernetesCluster, 0 to disable it
+	ownerRefRefreshInterval time.Duration
 )
 
+// loggingConfig is the logging section of the cloud config, read the same
+// way by the vSphere cloud provider.
+type loggingConfig struct {
+	Logging struct {
+		ModuleVerbosity map[string]int `json:"moduleVerbosity"`
+	} `json:"logging"`
+}
+
 func init() {
 	cloudprovider.RegisterCloudProvider(RegisteredProviderName, func(config io.Reader) (cloudprovider.Interface, error) {
 		if config == nil {
@@ -83,11 +101,23 @@ func init() {
 			return nil, err
 		}
 
+		var loggingCfg loggingConfig
+		if err := yaml.Unmarshal(data, &loggingCfg); err != nil {
+			return nil, err
+		}
+		if err := logging.SetModuleVerbosity(loggingCfg.Logging.ModuleVerbosity); err != nil {
+			return nil, err
+		}
+
 		return newVSphereParavirtual(&cfg)
 	})
 
-	flag.BoolVar(&vmservice.IsLegacy, "is-legacy-paravirtual", false, "If true, machine label selector will start with capw.vmware.com. By default, it's false, machine label selector will start with capv.vmware.com.")
+	flag.BoolVar(&vmservice.IsLegacy, LegacyParavirtualFlag, false, "If true, machine label selector will start with capw.vmware.com. By default, it's false, machine label selector will start with capv.vmware.com. "+
+		"Deprecated, it will be removed in "+LegacyParavirtualRemovalRelease+", see --"+ConvertLegacyParavirtualFlag+".")
+	flag.StringVar(&vmservice.AllowedClusterRoles, "allowed-cluster-roles", vmservice.AllowedClusterRoles, "Comma separated list of the cluster roles a Service can target with the "+vmservice.AnnotationVMServiceClusterRoleKey+" annotation, for instance the roles of specialized node pools.")
 	flag.BoolVar(&vpcModeEnabled, "enable-vpc-mode", false, "If true, routable pod controller will start with VPC mode. It is useful only when route controller is enabled in vsphereparavirtual mode")
+	flag.DurationVar(&routeDriftCheckInterval, "route-drift-check-interval", 0, "Interval of the comparison of the RouteSet or StaticRoute CRs with the pod CIDRs of the nodes, repairing missing, diverging and stale CRs. It is useful only when route controller is enabled in vsphereparavirtual mode. By default, it's 0 and the check is disabled.")
+	flag.DurationVar(&ownerRefRefreshInterval, "owner-reference-refresh-interval", 5*time.Minute, "Interval of the refresh of the TanzuKubernetesCluster owner reference set on the CRs created in the supervisor cluster. The cached owner reference is kept when the refresh fails. 0 disables the refresh.")
 	flag.StringVar(&podIPPoolType, "pod-ip-pool-type", "", "Specify if Pod IP address is Public or Private routable in VPC network. Valid values are Public and Private")
 }
 
@@ -102,17 +132,20 @@ func newVSphereParavirtual(cfg *cpcfg.Config) (*VSphereParavirtual, error) {
 
 // Initialize initializes the vSphere paravirtual cloud provider.
 func (cp *VSphereParavirtual) Initialize(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
-	klog.V(0).Info("Initing vSphere Paravirtual Cloud Provider")
+	logging.V(logging.Paravirtual, 0).Info("Initing vSphere Paravirtual Cloud Provider")
+	warnLegacyParavirtual(ClusterName, os.Args[1:])
 
 	err := checkPodIPPoolType(vpcModeEnabled, podIPPoolType)
 	if err != nil {
 		klog.Fatalf("Invalid IP pool type: %v", err)
 	}
 
-	ownerRef, err := readOwnerRef(VsphereParavirtualCloudProviderConfigPath)
+	ownerRefs, err := newOwnerRefCache(VsphereParavirtualCloudProviderConfigPath, ownerRefReadTimeout)
 	if err != nil {
 		klog.Fatalf("Failed to read ownerRef:%s", err)
 	}
+	go ownerRefs.Run(ownerRefRefreshInterval, stop)
+	ownerRef := ownerRefs.Get()
 
 	client, err := clientBuilder.Client(clientName)
 	if err != nil {
@@ -121,29 +154,41 @@ func (cp *VSphereParavirtual) Initialize(clientBuilder cloudprovider.ControllerC
 
 	cp.client = client
 	cp.informMgr = k8s.NewInformer(client)
-	cp.ownerReference = ownerRef
+	cp.ownerRefs = ownerRefs
 
 	kcfg, err := getRestConfig(SupervisorClusterConfigPath)
 	if err != nil {
 		klog.Fatalf("Failed to create rest config to communicate with supervisor: %v", err)
 	}
 
+	if check, err := supervisorHealthCheck(kcfg); err != nil {
+		klog.Errorf("Failed to create the health check of the supervisor: %v", err)
+	} else {
+		health.Register("supervisor-apiserver", check)
+	}
+
 	clusterNS, err := getNameSpace(SupervisorClusterConfigPath)
 	if err != nil {
 		klog.Fatalf("Failed to get cluster namespace: %v", err)
 	}
 
-	routes, err := NewRoutes(clusterNS, kcfg, *cp.ownerReference, vpcModeEnabled, cp.informMgr.GetNodeLister())
+	routes, err := NewRoutes(clusterNS, kcfg, cp.ownerRefs.Get, vpcModeEnabled, cp.informMgr.GetNodeLister())
 	if err != nil {
 		klog.Errorf("Failed to init Route: %v", err)
 	}
 	cp.routes = routes
+	if routes != nil {
+		routes.Initialize(ClusterName, client, cp.informMgr.IsNodeInformerSynced(), stop)
+	}
 
-	lb, err := NewLoadBalancer(clusterNS, kcfg, cp.ownerReference)
+	lb, err := NewLoadBalancer(clusterNS, kcfg, cp.ownerRefs.Get)
 	if err != nil {
 		klog.Errorf("Failed to init LoadBalancer: %v", err)
 	}
 	cp.loadBalancer = lb
+	if lb != nil {
+		lb.Initialize(ClusterName, client, stop)
+	}
 
 	instances, err := NewInstances(clusterNS, kcfg)
 	if err != nil {
@@ -152,7 +197,7 @@ func (cp *VSphereParavirtual) Initialize(clientBuilder cloudprovider.ControllerC
 	cp.instances = instances
 
 	if RouteEnabled {
-		klog.V(0).Info("Starting routable pod controllers")
+		logging.V(logging.Paravirtual, 0).Info("Starting routable pod controllers")
 
 		if err := routablepod.StartControllers(kcfg, client, cp.informMgr, ClusterName, clusterNS, ownerRef, vpcModeEnabled, podIPPoolType); err != nil {
 			klog.Errorf("Failed to start Routable pod controllers: %v", err)
@@ -166,20 +211,20 @@ func (cp *VSphereParavirtual) Initialize(clientBuilder cloudprovider.ControllerC
 	cp.zones = zones
 
 	cp.informMgr.Listen()
-	klog.V(0).Info("Initing vSphere Paravirtual Cloud Provider Succeeded")
+	logging.V(logging.Paravirtual, 0).Info("Initing vSphere Paravirtual Cloud Provider Succeeded")
 }
 
 // LoadBalancer returns a balancer interface. Also returns true if the
 // interface is supported, false otherwise.
 func (cp *VSphereParavirtual) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
-	klog.V(1).Info("Enabling load balancer support in vsphere paravirtual cloud provider")
+	logging.V(logging.Paravirtual, 1).Info("Enabling load balancer support in vsphere paravirtual cloud provider")
 	return cp.loadBalancer, true
 }
 
 // Instances returns an instances interface. Also returns true if the
 // interface is supported, false otherwise.
 func (cp *VSphereParavirtual) Instances() (cloudprovider.Instances, bool) {
-	klog.V(1).Info("Enabling Instances interface on vsphere paravirtual cloud provider")
+	logging.V(logging.Paravirtual, 1).Info("Enabling Instances interface on vsphere paravirtual cloud provider")
 	return cp.instances, true
 }
 
@@ -193,21 +238,21 @@ func (cp *VSphereParavirtual) InstancesV2() (cloudprovider.InstancesV2, bool) {
 // Zones returns a zones interface. Also returns true if the interface
 // is supported, false otherwise.
 func (cp *VSphereParavirtual) Zones() (cloudprovider.Zones, bool) {
-	klog.V(1).Info("Enabling Zones interface on vsphere paravirtual cloud provider")
+	logging.V(logging.Paravirtual, 1).Info("Enabling Zones interface on vsphere paravirtual cloud provider")
 	return cp.zones, true
 }
 
 // Clusters returns a clusters interface.  Also returns true if the interface
 // is supported, false otherwise.
 func (cp *VSphereParavirtual) Clusters() (cloudprovider.Clusters, bool) {
-	klog.V(1).Info("The vsphere paravirtual cloud provider does not support clusters")
+	logging.V(logging.Paravirtual, 1).Info("The vsphere paravirtual cloud provider does not support clusters")
 	return nil, false
 }
 
 // Routes returns a routes interface along with whether the interface
 // is supported.
 func (cp *VSphereParavirtual) Routes() (cloudprovider.Routes, bool) {
-	klog.V(1).Info("Enabling Routes interface on vsphere paravirtual cloud provider")
+	logging.V(logging.Paravirtual, 1).Info("Enabling Routes interface on vsphere paravirtual cloud provider")
 	return cp.routes, true
 }
 
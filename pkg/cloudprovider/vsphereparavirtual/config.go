@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/nsxipmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 const (
@@ -87,7 +88,7 @@ func readSupervisorConfig() (*SupervisorEndpoint, error) {
 
 	}
 
-	klog.V(6).Infof("Configured with remote apiserver %s:%s", remoteVip, remotePort)
+	logging.V(logging.Paravirtual, 6).Infof("Configured with remote apiserver %s:%s", remoteVip, remotePort)
 	return &SupervisorEndpoint{
 		Endpoint: remoteVip,
 		Port:     remotePort,
@@ -22,7 +22,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/klog/v2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/controllers/routablepod/ipaddressallocation"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/controllers/routablepod/ippool"
@@ -31,6 +30,7 @@ import (
 	ippmv1alpha1 "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/ippoolmanager/v1alpha1"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/nsxipmanager"
 	k8s "k8s.io/cloud-provider-vsphere/pkg/common/kubernetes"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // StartControllers starts ippool_controller and node_controller
@@ -45,7 +45,7 @@ func StartControllers(scCfg *rest.Config, client kubernetes.Interface,
 		return fmt.Errorf("cluster namespace can't be empty")
 	}
 
-	klog.V(2).Info("Routable pod controllers start with VPC mode enabled: ", vpcModeEnabled)
+	logging.V(logging.Paravirtual, 2).Info("Routable pod controllers start with VPC mode enabled: ", vpcModeEnabled)
 
 	ctx := informerManager.GetContext()
 	var nsxIPManager nsxipmanager.NSXIPManager
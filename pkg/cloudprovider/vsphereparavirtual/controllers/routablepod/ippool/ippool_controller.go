@@ -32,6 +32,7 @@ import (
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/controllers/routablepod/utils"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/ippoolmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 const (
@@ -101,13 +102,13 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
-	klog.V(4).Info("Waiting cache to be synced.")
+	logging.V(logging.Paravirtual, 4).Info("Waiting cache to be synced.")
 
 	if !cache.WaitForNamedCacheSync("ippool", stopCh, c.ippoolManager.GetIPPoolListerSynced()) {
 		return
 	}
 
-	klog.V(4).Info("Starting ippool workers.")
+	logging.V(logging.Paravirtual, 4).Info("Starting ippool workers.")
 	go wait.Until(c.runWorker, time.Second, stopCh)
 
 	<-stopCh
@@ -169,7 +170,7 @@ func (c *Controller) processNextWorkItem() bool {
 func (c *Controller) syncIPPool(key string) error {
 	startTime := time.Now()
 	defer func() {
-		klog.V(4).Infof("Finished syncing service %q (%v)", key, time.Since(startTime))
+		logging.V(logging.Paravirtual, 4).Infof("Finished syncing service %q (%v)", key, time.Since(startTime))
 	}()
 
 	ippool, err := c.ippoolManager.GetIPPoolFromIndexer(key)
@@ -33,6 +33,7 @@ import (
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/nsxipmanager"
 	k8s "k8s.io/cloud-provider-vsphere/pkg/common/kubernetes"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 const (
@@ -122,12 +123,12 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
-	klog.V(4).Info("Waiting cache to be synced.")
+	logging.V(logging.Paravirtual, 4).Info("Waiting cache to be synced.")
 	if !cache.WaitForNamedCacheSync("node", stopCh, c.nodeListerSynced) {
 		return
 	}
 
-	klog.V(4).Info("Starting node workers.")
+	logging.V(logging.Paravirtual, 4).Info("Starting node workers.")
 	go wait.Until(c.runWorker, time.Second, stopCh)
 
 	<-stopCh
@@ -189,7 +190,7 @@ func (c *Controller) processNextWorkItem() bool {
 func (c *Controller) syncNode(key string) error {
 	startTime := time.Now()
 	defer func() {
-		klog.V(4).Infof("Finished syncing service %q (%v)", key, time.Since(startTime))
+		logging.V(logging.Paravirtual, 4).Infof("Finished syncing service %q (%v)", key, time.Since(startTime))
 	}()
 
 	_, name, err := cache.SplitMetaNamespaceKey(key)
@@ -201,13 +202,13 @@ func (c *Controller) syncNode(key string) error {
 	switch {
 	case apierrors.IsNotFound(err):
 		// node absence in store means watcher caught the deletion, ensure Pod CIDR of this Node is released
-		klog.V(4).Infof("Node %s is not found, releasing its Pod CIDR", name)
+		logging.V(logging.Paravirtual, 4).Infof("Node %s is not found, releasing its Pod CIDR", name)
 		err = c.nsxIPManager.ReleasePodCIDR(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
 	case err != nil:
 		utilruntime.HandleError(fmt.Errorf("unable to retrieve node %v from store: %v", name, err))
 	default:
 		// node exists in store, ensure Pod CIDR of this Node is claimed
-		klog.V(4).Infof("Node %s is found, ensuring Pod CIDR claimed", name)
+		logging.V(logging.Paravirtual, 4).Infof("Node %s is found, ensuring Pod CIDR claimed", name)
 		err = c.nsxIPManager.ClaimPodCIDR(node)
 	}
 
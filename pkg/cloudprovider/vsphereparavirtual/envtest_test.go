@@ -0,0 +1,387 @@
+//go:build envtest
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	cloudprovider "k8s.io/cloud-provider"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	t1networkingapis "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/apis/nsxnetworking/v1alpha1"
+	vmopclient "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmoperator/client"
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+)
+
+// The envtest suite runs the paravirtual provider against a kube-apiserver
+// standing in for the supervisor, with the vm-operator and NSX CRDs of
+// testdata/crds installed. The tests play the part of the supervisor
+// controllers: they create the VirtualMachines and report the status of the
+// VirtualMachineServices and RouteSets. Run it with "make envtest".
+
+// supervisorConfig is the rest config of the simulated supervisor, nil when
+// KUBEBUILDER_ASSETS does not point to the envtest binaries.
+var supervisorConfig *rest.Config
+
+var routeSetGVR = t1networkingapis.SchemeGroupVersion.WithResource("routesets")
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("KUBEBUILDER_ASSETS is not set, skipping the envtest suite")
+		os.Exit(m.Run())
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("testdata", "crds")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Printf("Failed to start the simulated supervisor: %v\n", err)
+		os.Exit(1)
+	}
+	supervisorConfig = cfg
+
+	code := m.Run()
+	if err := env.Stop(); err != nil {
+		fmt.Printf("Failed to stop the simulated supervisor: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// newSupervisorNamespace creates a namespace of the simulated supervisor
+// for the guest cluster of the test.
+func newSupervisorNamespace(t *testing.T) (string, dynamic.Interface) {
+	t.Helper()
+	if supervisorConfig == nil {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	client, err := kubernetes.NewForConfig(supervisorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := client.CoreV1().Namespaces().Create(context.Background(), &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "guest-cluster-"},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = client.CoreV1().Namespaces().Delete(context.Background(), ns.Name, metav1.DeleteOptions{})
+	})
+
+	dc, err := dynamic.NewForConfig(supervisorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns.Name, dc
+}
+
+// updateStatus sets the status of a supervisor object, the way the
+// supervisor controllers report it.
+func updateStatus(ctx context.Context, dc dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, status interface{}) error {
+	obj, err := dc.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	unstructuredStatus, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedMap(obj.Object, unstructuredStatus, "status"); err != nil {
+		return err
+	}
+	_, err = dc.Resource(gvr).Namespace(namespace).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+func TestEnvtestInstances(t *testing.T) {
+	ns, dc := newSupervisorNamespace(t)
+	ctx := context.Background()
+
+	uuid := "4237d8fe-6c26-4e27-b5fe-0b1e6f9a3d5c"
+	vm := &vmopv1.VirtualMachine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: vmopv1.SchemeGroupVersion.String(),
+			Kind:       "VirtualMachine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-1",
+			Namespace: ns,
+			Labels:    map[string]string{BiosUUIDLabelKey: uuid},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dc.Resource(vmopclient.VirtualMachineGVR).Namespace(ns).Create(ctx, &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instances, err := NewInstances(ns, supervisorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	providerID := providerPrefix + uuid
+
+	// the VM is found by its label before the supervisor reports its status
+	instanceID, err := instances.InstanceID(ctx, "node-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uuid, instanceID)
+	exists, err := instances.InstanceExistsByProviderID(ctx, providerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, exists)
+	addresses, err := instances.NodeAddresses(ctx, "node-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, addresses)
+
+	if err := updateStatus(ctx, dc, vmopclient.VirtualMachineGVR, ns, "node-1", vmopv1.VirtualMachineStatus{
+		BiosUUID:   uuid,
+		PowerState: vmopv1.VirtualMachinePowerStateOn,
+		Network:    &vmopv1.VirtualMachineNetworkStatus{PrimaryIP4: "192.168.10.11"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err = instances.NodeAddressesByProviderID(ctx, providerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, addresses, v1.NodeAddress{Type: v1.NodeInternalIP, Address: "192.168.10.11"})
+	shutdown, err := instances.InstanceShutdownByProviderID(ctx, providerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, shutdown)
+
+	if err := updateStatus(ctx, dc, vmopclient.VirtualMachineGVR, ns, "node-1", vmopv1.VirtualMachineStatus{
+		BiosUUID:   uuid,
+		PowerState: vmopv1.VirtualMachinePowerStateOff,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	shutdown, err = instances.InstanceShutdownByProviderID(ctx, providerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, shutdown)
+
+	if err := dc.Resource(vmopclient.VirtualMachineGVR).Namespace(ns).Delete(ctx, "node-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	exists, err = instances.InstanceExistsByProviderID(ctx, providerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, exists)
+	_, err = instances.InstanceID(ctx, "node-1")
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+}
+
+func TestEnvtestLoadBalancer(t *testing.T) {
+	ns, dc := newSupervisorNamespace(t)
+	ctx := context.Background()
+
+	lb, err := NewLoadBalancer(ns, supervisorConfig, func() *metav1.OwnerReference { return &testOwnerReference })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testK8sServiceName,
+			Namespace: testK8sServiceNameSpace,
+		},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeLoadBalancer,
+			Ports: []v1.ServicePort{
+				{
+					Name:     "http",
+					Protocol: v1.ProtocolTCP,
+					Port:     80,
+					NodePort: 30080,
+				},
+			},
+		},
+	}
+	name := lb.GetLoadBalancerName(ctx, testClustername, service)
+
+	// the VirtualMachineService is created, without IP until the supervisor assigns one
+	_, err = lb.EnsureLoadBalancer(ctx, testClustername, service, nil)
+	assert.Equal(t, vmservice.ErrVMServiceIPNotFound, err)
+	_, err = dc.Resource(vmopclient.VirtualMachineServiceGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateStatus(ctx, dc, vmopclient.VirtualMachineServiceGVR, ns, name, vmopv1.VirtualMachineServiceStatus{
+		LoadBalancer: vmopv1.LoadBalancerStatus{
+			Ingress: []vmopv1.LoadBalancerIngress{{IP: "10.10.0.5"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := lb.EnsureLoadBalancer(ctx, testClustername, service, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []v1.LoadBalancerIngress{{IP: "10.10.0.5"}}, status.Ingress)
+	status, exists, err := lb.GetLoadBalancer(ctx, testClustername, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, exists)
+	assert.Equal(t, []v1.LoadBalancerIngress{{IP: "10.10.0.5"}}, status.Ingress)
+
+	service.Spec.Ports[0].Port = 8080
+	if err := lb.UpdateLoadBalancer(ctx, testClustername, service, nil); err != nil {
+		t.Fatal(err)
+	}
+	vmClient, err := vmservice.GetVmopClient(supervisorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmService, err := vmClient.V1alpha2().VirtualMachineServices(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, vmService.Spec.Ports, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, int32(8080), vmService.Spec.Ports[0].Port)
+	assert.Equal(t, int32(30080), vmService.Spec.Ports[0].TargetPort)
+	// the status reported by the supervisor is not overwritten by the update
+	assert.Equal(t, "10.10.0.5", vmService.Status.LoadBalancer.Ingress[0].IP)
+
+	if err := lb.EnsureLoadBalancerDeleted(ctx, testClustername, service); err != nil {
+		t.Fatal(err)
+	}
+	_, err = dc.Resource(vmopclient.VirtualMachineServiceGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	_, exists, err = lb.GetLoadBalancer(ctx, testClustername, service)
+	assert.Error(t, err)
+	assert.False(t, exists)
+	if err := lb.EnsureLoadBalancerDeleted(ctx, testClustername, service); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvtestRoutes(t *testing.T) {
+	ns, dc := newSupervisorNamespace(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "192.168.10.11"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := NewRoutes(ns, supervisorConfig, func() *metav1.OwnerReference { return &testOwnerReference }, false, listerv1.NewNodeLister(indexer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// realize the RouteSet once it is created, as NCP does
+	realized := make(chan error, 1)
+	go func() {
+		realized <- wait.PollUntilContextCancel(ctx, 100*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+			err := updateStatus(ctx, dc, routeSetGVR, ns, "node-1", t1networkingapis.RouteSetStatus{
+				Conditions: []t1networkingapis.RouteSetCondition{
+					{Type: t1networkingapis.RouteSetConditionTypeReady, Status: v1.ConditionTrue},
+				},
+			})
+			return err == nil, nil
+		})
+	}()
+
+	route := &cloudprovider.Route{
+		TargetNode:      types.NodeName("node-1"),
+		DestinationCIDR: "100.96.1.0/24",
+	}
+	if err := routes.CreateRoute(ctx, testClustername, "e1c9f1f2-7f6b-4c36-8b36-7d2f4b8a5d10", route); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-realized; err != nil {
+		t.Fatal(err)
+	}
+
+	listed, err := routes.ListRoutes(ctx, testClustername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, listed, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, route.TargetNode, listed[0].TargetNode)
+	assert.Equal(t, route.DestinationCIDR, listed[0].DestinationCIDR)
+
+	// routes of other clusters sharing the namespace are not listed
+	listed, err = routes.ListRoutes(ctx, "other-cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, listed)
+
+	if err := routes.DeleteRoute(ctx, testClustername, route); err != nil {
+		t.Fatal(err)
+	}
+	_, err = dc.Resource(routeSetGVR).Namespace(ns).Get(ctx, "node-1", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	listed, err = routes.ListRoutes(ctx, testClustername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, listed)
+}
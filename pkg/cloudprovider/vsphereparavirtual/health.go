@@ -0,0 +1,40 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/health"
+)
+
+// supervisorHealthCheck returns a check of the readiness of the supervisor
+// apiserver, which serves the VirtualMachine, VirtualMachineService and
+// route CRs.
+func supervisorHealthCheck(kcfg *rest.Config) (health.Check, error) {
+	client, err := kubernetes.NewForConfig(kcfg)
+	if err != nil {
+		return nil, err
+	}
+	restClient := client.Discovery().RESTClient()
+	return func(ctx context.Context) error {
+		return restClient.Get().AbsPath("/readyz").Do(ctx).Error()
+	}, nil
+}
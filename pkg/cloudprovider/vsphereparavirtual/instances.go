@@ -23,6 +23,8 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -34,10 +36,12 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	vmop "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmoperator"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 type instances struct {
 	vmClient  vmop.Interface
+	podClient corev1client.PodsGetter
 	namespace string
 }
 
@@ -45,6 +49,14 @@ const (
 	// providerPrefix is the Kubernetes cloud provider prefix for this
 	// cloud provider.
 	providerPrefix = ProviderName + "://"
+
+	// BiosUUIDLabelKey is the label the supervisor sets on VirtualMachines with
+	// their BIOS UUID, before Status.BiosUUID is reported.
+	BiosUUIDLabelKey = "vmoperator.vmware.com/bios-uuid"
+
+	// ProviderIDAnnotationKey is the annotation the supervisor sets on
+	// VirtualMachines with their providerID, before Status.BiosUUID is reported.
+	ProviderIDAnnotationKey = "vmoperator.vmware.com/provider-id"
 )
 
 // DiscoverNodeBackoff is set to be the same with https://github.com/kubernetes/kubernetes/blob/master/pkg/controller/cloud/node_controller.go#L83
@@ -74,6 +86,16 @@ func (i instances) discoverNodeByName(ctx context.Context, name types.NodeName)
 	return discoverNodeByName(ctx, name, i.namespace, i.vmClient)
 }
 
+// discoverPodVMByProviderID takes a ProviderID and returns the vSphere Pod backing the node if one exists, or nil otherwise
+func (i instances) discoverPodVMByProviderID(ctx context.Context, providerID string) (*v1.Pod, error) {
+	return discoverPodVMByProviderID(ctx, providerID, i.namespace, i.podClient)
+}
+
+// discoverPodVMByName takes a node name and returns the vSphere Pod backing the node if one exists, or nil otherwise
+func (i instances) discoverPodVMByName(ctx context.Context, name types.NodeName) (*v1.Pod, error) {
+	return discoverPodVMByName(ctx, name, i.namespace, i.podClient)
+}
+
 // NewInstances returns an implementation of cloudprovider.Instances
 func NewInstances(clusterNS string, kcfg *rest.Config) (cloudprovider.Instances, error) {
 	vmClient, err := vmservice.GetVmopClient(kcfg)
@@ -82,8 +104,14 @@ func NewInstances(clusterNS string, kcfg *rest.Config) (cloudprovider.Instances,
 		return nil, err
 	}
 
+	podClient, err := kubernetes.NewForConfig(kcfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &instances{
 		vmClient:  vmClient,
+		podClient: podClient.CoreV1(),
 		namespace: clusterNS,
 	}, nil
 }
@@ -95,7 +123,7 @@ func createNodeAddresses(vm *vmopv1.VirtualMachine) []v1.NodeAddress {
 	// dynamically determine the IP format based on the cluster's IP family.
 	// https://github.com/kubernetes/cloud-provider-vsphere/issues/1129
 	if vm.Status.Network == nil || (vm.Status.Network.PrimaryIP4 == "" && vm.Status.Network.PrimaryIP6 == "") {
-		klog.V(4).Info("instance found, but no address yet")
+		logging.V(logging.Paravirtual, 4).Info("instance found, but no address yet")
 		return []v1.NodeAddress{}
 	}
 
@@ -119,7 +147,7 @@ func createNodeAddresses(vm *vmopv1.VirtualMachine) []v1.NodeAddress {
 // NodeAddresses returns the addresses of the specified instance if one exists, otherwise nil
 // If the instance exists but does not yet have an IP address, the function returns a zero length slice
 func (i *instances) NodeAddresses(ctx context.Context, name types.NodeName) ([]v1.NodeAddress, error) {
-	klog.V(4).Info("instances.NodeAddresses() called with ", name)
+	logging.V(logging.Paravirtual, 4).Info("instances.NodeAddresses() called with ", name)
 
 	vm, err := i.discoverNodeByName(ctx, name)
 	if err != nil {
@@ -127,7 +155,15 @@ func (i *instances) NodeAddresses(ctx context.Context, name types.NodeName) ([]v
 		return nil, err
 	}
 	if vm == nil {
-		klog.V(4).Info("instances.NodeAddresses() InstanceNotFound ", name)
+		pod, err := i.discoverPodVMByName(ctx, name)
+		if err != nil {
+			klog.Errorf("Error trying to find vSphere Pod: %v", err)
+			return nil, err
+		}
+		if pod != nil {
+			return createPodVMNodeAddresses(pod), nil
+		}
+		logging.V(logging.Paravirtual, 4).Info("instances.NodeAddresses() InstanceNotFound ", name)
 		return nil, cloudprovider.InstanceNotFound
 	}
 	return createNodeAddresses(vm), err
@@ -136,7 +172,7 @@ func (i *instances) NodeAddresses(ctx context.Context, name types.NodeName) ([]v
 // NodeAddressesByProviderID returns the addresses of the specified instance if one exists, otherwise nil
 // If the instance exists but does not yet have an IP address, the function returns a zero length slice
 func (i *instances) NodeAddressesByProviderID(ctx context.Context, providerID string) ([]v1.NodeAddress, error) {
-	klog.V(4).Info("instances.NodeAddressesByProviderID() called with ", providerID)
+	logging.V(logging.Paravirtual, 4).Info("instances.NodeAddressesByProviderID() called with ", providerID)
 
 	vm, err := i.discoverNodeByProviderID(ctx, providerID)
 	if err != nil {
@@ -144,7 +180,15 @@ func (i *instances) NodeAddressesByProviderID(ctx context.Context, providerID st
 		return nil, err
 	}
 	if vm == nil {
-		klog.V(4).Info("instances.NodeAddressesByProviderID() InstanceNotFound ", providerID)
+		pod, err := i.discoverPodVMByProviderID(ctx, providerID)
+		if err != nil {
+			klog.Errorf("Error trying to find vSphere Pod: %v", err)
+			return nil, err
+		}
+		if pod != nil {
+			return createPodVMNodeAddresses(pod), nil
+		}
+		logging.V(logging.Paravirtual, 4).Info("instances.NodeAddressesByProviderID() InstanceNotFound ", providerID)
 		return nil, cloudprovider.InstanceNotFound
 	}
 	return createNodeAddresses(vm), nil
@@ -158,51 +202,87 @@ func (i *instances) InstanceID(ctx context.Context, nodeName types.NodeName) (st
 		return "", err
 	}
 	if vm == nil {
-		klog.V(4).Info("instances.InstanceID() InstanceNotFound ", nodeName)
+		pod, err := i.discoverPodVMByName(ctx, nodeName)
+		if err != nil {
+			klog.Errorf("Error trying to find vSphere Pod: %v", err)
+			return "", err
+		}
+		if pod != nil {
+			return getPodVMUUID(pod), nil
+		}
+		logging.V(logging.Paravirtual, 4).Info("instances.InstanceID() InstanceNotFound ", nodeName)
 		return "", cloudprovider.InstanceNotFound
 	}
 
-	if vm.Status.BiosUUID == "" {
+	uuid := getBiosUUID(vm)
+	if uuid == "" {
 		return "", errBiosUUIDEmpty
 	}
 
-	klog.V(4).Infof("instances.InstanceID() called to get vm: %v uuid: %v", nodeName, vm.Status.BiosUUID)
-	return vm.Status.BiosUUID, nil
+	logging.V(logging.Paravirtual, 4).Infof("instances.InstanceID() called to get vm: %v uuid: %v", nodeName, uuid)
+	return uuid, nil
 }
 
 // InstanceType returns the type of the specified instance.
 func (i *instances) InstanceType(ctx context.Context, name types.NodeName) (string, error) {
-	klog.V(4).Info("instances.InstanceTypeByProviderID() called with ", name)
+	logging.V(logging.Paravirtual, 4).Info("instances.InstanceType() called with ", name)
+
+	pod, err := i.discoverPodVMByName(ctx, name)
+	if err != nil {
+		klog.Errorf("Error trying to find vSphere Pod: %v", err)
+		return "", err
+	}
+	if pod != nil {
+		return PodVMInstanceType, nil
+	}
 	return "", nil
 }
 
 // InstanceTypeByProviderID returns the type of the specified instance.
 func (i *instances) InstanceTypeByProviderID(ctx context.Context, providerID string) (string, error) {
-	klog.V(4).Info("instances.InstanceTypeByProviderID() called with ", providerID)
+	logging.V(logging.Paravirtual, 4).Info("instances.InstanceTypeByProviderID() called with ", providerID)
+
+	pod, err := i.discoverPodVMByProviderID(ctx, providerID)
+	if err != nil {
+		klog.Errorf("Error trying to find vSphere Pod: %v", err)
+		return "", err
+	}
+	if pod != nil {
+		return PodVMInstanceType, nil
+	}
 	return "", nil
 }
 
 // CurrentNodeName returns the name of the node we are currently running on
 func (i *instances) CurrentNodeName(ctx context.Context, hostname string) (types.NodeName, error) {
-	klog.V(4).Info("instances.CurrentNodeName() called with ", hostname)
+	logging.V(logging.Paravirtual, 4).Info("instances.CurrentNodeName() called with ", hostname)
 	return types.NodeName(hostname), nil
 }
 
 // InstanceExistsByProviderID returns true if the instance for the given provider exists
 func (i *instances) InstanceExistsByProviderID(ctx context.Context, providerID string) (bool, error) {
-	klog.V(4).Info("instances.InstanceExistsByProviderID() called with ", providerID)
+	logging.V(logging.Paravirtual, 4).Info("instances.InstanceExistsByProviderID() called with ", providerID)
 
 	vm, err := i.discoverNodeByProviderID(ctx, providerID)
 	if err != nil {
 		klog.Errorf("Error trying to find VM: %v", err)
 		return false, err
 	}
-	return vm != nil, nil
+	if vm != nil {
+		return true, nil
+	}
+
+	pod, err := i.discoverPodVMByProviderID(ctx, providerID)
+	if err != nil {
+		klog.Errorf("Error trying to find vSphere Pod: %v", err)
+		return false, err
+	}
+	return pod != nil, nil
 }
 
 // InstanceShutdownByProviderID returns true if the instance exists and is shut down
 func (i *instances) InstanceShutdownByProviderID(ctx context.Context, providerID string) (bool, error) {
-	klog.V(4).Info("instances.InstanceShutdownByProviderID() called with ", providerID)
+	logging.V(logging.Paravirtual, 4).Info("instances.InstanceShutdownByProviderID() called with ", providerID)
 
 	vm, err := i.discoverNodeByProviderID(ctx, providerID)
 	if err != nil {
@@ -210,17 +290,42 @@ func (i *instances) InstanceShutdownByProviderID(ctx context.Context, providerID
 		return false, err
 	}
 	if vm == nil {
-		klog.V(4).Info("instances.InstanceShutdownByProviderID() InstanceNotFound ", providerID)
+		pod, err := i.discoverPodVMByProviderID(ctx, providerID)
+		if err != nil {
+			klog.Errorf("Error trying to find vSphere Pod: %v", err)
+			return false, err
+		}
+		if pod != nil {
+			return isPodVMShutdown(pod), nil
+		}
+		logging.V(logging.Paravirtual, 4).Info("instances.InstanceShutdownByProviderID() InstanceNotFound ", providerID)
 		return false, cloudprovider.InstanceNotFound
 	}
 	return vm.Status.PowerState == vmopv1.VirtualMachinePowerStateOff, nil
 }
 
 func (i *instances) AddSSHKeyToAllInstances(ctx context.Context, user string, keyData []byte) error {
-	klog.V(4).Info("instances.AddSSHKeyToAllInstances() called")
+	logging.V(logging.Paravirtual, 4).Info("instances.AddSSHKeyToAllInstances() called")
 	return cloudprovider.NotImplemented
 }
 
+// getBiosUUID returns the BIOS UUID of the VirtualMachine. Status.BiosUUID is
+// only reported once the VM is created in vCenter, so the label and
+// annotation set by the supervisor are used until it is, so that new nodes
+// are initialized without waiting for it.
+func getBiosUUID(vm *vmopv1.VirtualMachine) string {
+	if vm.Status.BiosUUID != "" {
+		return vm.Status.BiosUUID
+	}
+	if uuid := vm.Labels[BiosUUIDLabelKey]; uuid != "" {
+		return strings.ToLower(strings.TrimSpace(uuid))
+	}
+	if providerID := vm.Annotations[ProviderIDAnnotationKey]; providerID != "" {
+		return GetUUIDFromProviderID(providerID)
+	}
+	return ""
+}
+
 // GetUUIDFromProviderID returns a UUID from the supplied cloud provider ID.
 func GetUUIDFromProviderID(providerID string) string {
 	withoutPrefix := strings.TrimPrefix(providerID, providerPrefix)
@@ -53,6 +53,18 @@ func createTestVM(name, namespace, biosUUID string) *vmopv1.VirtualMachine {
 	}
 }
 
+func createTestVMWithBiosUUIDLabel(name, namespace, biosUUID string) *vmopv1.VirtualMachine {
+	vm := createTestVM(name, namespace, "")
+	vm.Labels = map[string]string{BiosUUIDLabelKey: biosUUID}
+	return vm
+}
+
+func createTestVMWithProviderIDAnnotation(name, namespace, providerID string) *vmopv1.VirtualMachine {
+	vm := createTestVM(name, namespace, "")
+	vm.Annotations = map[string]string{ProviderIDAnnotationKey: providerID}
+	return vm
+}
+
 func createTestVMWithVMIPAndHost(name, namespace, biosUUID string) *vmopv1.VirtualMachine {
 	// TODO: Currently, dual-stack (IPv4 and IPv6) is not supported.
 	// Cluster will be assumed as IPv4 Primary by default.
@@ -135,6 +147,18 @@ func TestInstanceID(t *testing.T) {
 			expectedInstanceID: "",
 			expectedErr:        cloudprovider.InstanceNotFound,
 		},
+		{
+			name:               "bios uuid from the supervisor label before it is reported",
+			testVM:             createTestVMWithBiosUUIDLabel(string(testVMName), testClusterNameSpace, testVMUUID),
+			expectedInstanceID: testVMUUID,
+			expectedErr:        nil,
+		},
+		{
+			name:               "bios uuid from the supervisor providerID annotation before it is reported",
+			testVM:             createTestVMWithProviderIDAnnotation(string(testVMName), testClusterNameSpace, testProviderID),
+			expectedInstanceID: testVMUUID,
+			expectedErr:        nil,
+		},
 		{
 			name:               "cannot find virtualmachine with empty bios uuid",
 			testVM:             createTestVM(string(testVMName), testClusterNameSpace, ""),
@@ -195,6 +219,18 @@ func TestInstanceExistsByProviderID(t *testing.T) {
 			expectedResult: true,
 			expectedErr:    nil,
 		},
+		{
+			name:           "InstanceExistsByProviderID should return true for a VM labeled with its bios uuid",
+			testVM:         createTestVMWithBiosUUIDLabel(string(testVMName), testClusterNameSpace, testVMUUID),
+			expectedResult: true,
+			expectedErr:    nil,
+		},
+		{
+			name:           "InstanceExistsByProviderID should return true for a VM annotated with its providerID",
+			testVM:         createTestVMWithProviderIDAnnotation(string(testVMName), testClusterNameSpace, testProviderID),
+			expectedResult: true,
+			expectedErr:    nil,
+		},
 		{
 			name:           "InstanceExistsByProviderID should return false",
 			testVM:         createTestVM(string(testVMName), testClusterNameSpace, "bogus"),
@@ -214,6 +250,21 @@ func TestInstanceExistsByProviderID(t *testing.T) {
 	}
 }
 
+func TestInstanceExistsByProviderIDUsesBiosUUIDLabel(t *testing.T) {
+	instance, fc, err := initTest(createTestVMWithBiosUUIDLabel(string(testVMName), testClusterNameSpace, testVMUUID))
+	assert.NoError(t, err)
+	fc.ClearActions()
+
+	exists, err := instance.InstanceExistsByProviderID(context.Background(), testProviderID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// the labeled VM is found without listing every VirtualMachine
+	actions := fc.Actions()
+	assert.Len(t, actions, 1)
+	assert.Equal(t, BiosUUIDLabelKey+"="+testVMUUID, actions[0].(clientgotesting.ListAction).GetListRestrictions().Labels.String())
+}
+
 func TestInstanceShutdownByProviderID(t *testing.T) {
 	testCases := []struct {
 		name             string
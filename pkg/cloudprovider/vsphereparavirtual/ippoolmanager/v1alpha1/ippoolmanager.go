@@ -9,12 +9,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/klog/v2"
 
 	t1networkingapis "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/apis/nsxnetworking/v1alpha1"
 	t1networkingclients "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/client/clientset/versioned"
 	t1networkinginformers "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/client/informers/externalversions"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/ippoolmanager/helper"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // IPPoolManager defines an ippool manager working with v1alpha1 ippool CR
@@ -136,7 +136,7 @@ func (p *IPPoolManager) AddSubnetToIPPool(node *corev1.Node, ippool helper.NSXIP
 	// skip if the request already added
 	for _, sub := range ipp.Spec.Subnets {
 		if sub.Name == node.Name {
-			klog.V(4).Infof("node %s already requested the subnet", node.Name)
+			logging.V(logging.Paravirtual, 4).Infof("node %s already requested the subnet", node.Name)
 			return nil
 		}
 	}
@@ -144,7 +144,7 @@ func (p *IPPoolManager) AddSubnetToIPPool(node *corev1.Node, ippool helper.NSXIP
 	newIpp := ipp.DeepCopy()
 	// add node cidr allocation req to the ippool spec only when node doesn't contain pod cidr
 	if node.Spec.PodCIDR == "" || len(node.Spec.PodCIDRs) == 0 {
-		klog.V(4).Infof("add subnet to ippool for node %s", node.Name)
+		logging.V(logging.Paravirtual, 4).Infof("add subnet to ippool for node %s", node.Name)
 		newIpp.Spec.Subnets = append(newIpp.Spec.Subnets, t1networkingapis.SubnetRequest{
 			Name:         node.Name,
 			IPFamily:     helper.IPFamilyDefault,
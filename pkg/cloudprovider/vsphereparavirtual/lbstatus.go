@@ -0,0 +1,272 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// LoadBalancerConditionType is the condition of the Services of type
+	// LoadBalancer telling whether their VirtualMachineService has an IP
+	LoadBalancerConditionType = "loadbalancer.vmware.io/VirtualMachineServiceReady"
+
+	// reasonIPAssigned is the reason of the Events and conditions of the
+	// Services whose VirtualMachineService has been assigned an IP
+	reasonIPAssigned = "IPAssigned"
+	// reasonIPPending is the reason of the Events and conditions of the
+	// Services whose VirtualMachineService has no IP yet
+	reasonIPPending = "IPPending"
+	// reasonVMServiceFailed is the reason of the Events and conditions of the
+	// Services whose VirtualMachineService failed to be created or updated
+	reasonVMServiceFailed = "VirtualMachineServiceFailed"
+)
+
+var (
+	// LoadBalancerMinRetryDelay and LoadBalancerMaxRetryDelay bound the
+	// backoff of the polling of the VirtualMachineServices without IP
+	LoadBalancerMinRetryDelay = 5 * time.Second
+	LoadBalancerMaxRetryDelay = 5 * time.Minute
+)
+
+// Initialize implements LoadBalancerProvider.Initialize
+func (l *loadBalancer) Initialize(clusterName string, client clientset.Interface, stop <-chan struct{}) {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	l.initialize(clusterName, client, eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: clientName}))
+
+	go func() {
+		defer utilruntime.HandleCrash()
+		defer eventBroadcaster.Shutdown()
+		defer l.workqueue.ShutDown()
+
+		go wait.Until(l.runWorker, time.Second, stop)
+		<-stop
+	}()
+}
+
+func (l *loadBalancer) initialize(clusterName string, client clientset.Interface, recorder record.EventRecorder) {
+	l.clusterName = clusterName
+	l.client = client
+	l.recorder = recorder
+	l.workqueue = workqueue.NewNamedRateLimitingQueue(
+		workqueue.NewItemExponentialFailureRateLimiter(LoadBalancerMinRetryDelay, LoadBalancerMaxRetryDelay),
+		"VirtualMachineServiceIP")
+}
+
+// reportFailure records the failure of the VirtualMachineService of the
+// Service as an Event and condition of the Service. A VirtualMachineService
+// without IP is polled with backoff until one is assigned.
+func (l *loadBalancer) reportFailure(ctx context.Context, service *v1.Service, vmService string, err error) {
+	if l.recorder == nil {
+		return
+	}
+	reason, message := reasonVMServiceFailed, err.Error()
+	if errors.Is(err, vmservice.ErrVMServiceIPNotFound) {
+		reason = reasonIPPending
+		l.workqueue.AddRateLimited(cache.MetaObjectToName(service).String())
+	}
+	if warning := l.supervisorWarning(ctx, vmService); warning != "" {
+		message = fmt.Sprintf("%s: %s", message, warning)
+	}
+
+	l.recorder.Event(service, v1.EventTypeWarning, reason, message)
+	if err := l.patchStatus(ctx, service, metav1.ConditionFalse, reason, message, nil); err != nil {
+		klog.Errorf("failed to update the %s condition of %s: %v", LoadBalancerConditionType, namespacedName(service), err)
+	}
+}
+
+// reportReady records the assignment of the IP of the VirtualMachineService
+// of the Service, if not done yet. With a non nil status, the ingress of the
+// Service is updated as well.
+func (l *loadBalancer) reportReady(ctx context.Context, service *v1.Service, status *v1.LoadBalancerStatus) error {
+	if l.recorder == nil {
+		return nil
+	}
+	l.workqueue.Forget(cache.MetaObjectToName(service).String())
+
+	condition := meta.FindStatusCondition(service.Status.Conditions, LoadBalancerConditionType)
+	if condition != nil && condition.Status == metav1.ConditionTrue && status == nil {
+		return nil
+	}
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		l.recorder.Event(service, v1.EventTypeNormal, reasonIPAssigned, "VirtualMachineService has been assigned an IP")
+	}
+	return l.patchStatus(ctx, service, metav1.ConditionTrue, reasonIPAssigned, "", status)
+}
+
+// patchStatus sets the LoadBalancerConditionType condition of the Service,
+// and its load balancer status if not nil. Nothing is patched if neither of
+// them changes.
+func (l *loadBalancer) patchStatus(ctx context.Context, service *v1.Service, status metav1.ConditionStatus, reason, message string, lbStatus *v1.LoadBalancerStatus) error {
+	conditions := append([]metav1.Condition(nil), service.Status.Conditions...)
+	changed := meta.SetStatusCondition(&conditions, metav1.Condition{
+		Type:               LoadBalancerConditionType,
+		Status:             status,
+		ObservedGeneration: service.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if !changed && lbStatus == nil {
+		return nil
+	}
+
+	patchStatus := map[string]interface{}{
+		"conditions": []metav1.Condition{*meta.FindStatusCondition(conditions, LoadBalancerConditionType)},
+	}
+	if lbStatus != nil {
+		patchStatus["loadBalancer"] = lbStatus
+	}
+	patch, err := json.Marshal(map[string]interface{}{"status": patchStatus})
+	if err != nil {
+		return err
+	}
+	_, err = l.client.CoreV1().Services(service.Namespace).Patch(ctx, service.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// supervisorWarning returns the message of the latest Warning Event of the
+// VirtualMachineService or of its supervisor Service, which tells for
+// instance that a quota is exceeded or the IP pool is exhausted
+func (l *loadBalancer) supervisorWarning(ctx context.Context, vmService string) string {
+	if l.supervisorEvents == nil || vmService == "" {
+		return ""
+	}
+	events, err := l.supervisorEvents.List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.name": vmService, "type": v1.EventTypeWarning}.AsSelector().String(),
+	})
+	if err != nil {
+		logging.V(logging.Paravirtual, 2).Infof("failed to list the events of VirtualMachineService %s: %v", vmService, err)
+		return ""
+	}
+
+	var latest *v1.Event
+	for i := range events.Items {
+		event := &events.Items[i]
+		if event.InvolvedObject.Name != vmService || event.Type != v1.EventTypeWarning ||
+			(event.InvolvedObject.Kind != "VirtualMachineService" && event.InvolvedObject.Kind != "Service") {
+			continue
+		}
+		if latest == nil || eventTime(event).After(eventTime(latest)) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+func eventTime(event *v1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
+func (l *loadBalancer) runWorker() {
+	for l.processNextWorkItem() {
+	}
+}
+
+func (l *loadBalancer) processNextWorkItem() bool {
+	obj, shutdown := l.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer l.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		l.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+	if err := l.syncVMServiceIP(context.Background(), key); err != nil {
+		l.workqueue.AddRateLimited(key)
+		logging.V(logging.Paravirtual, 2).Infof("VirtualMachineService of service %s has no IP yet: %v, requeuing", key, err)
+		return true
+	}
+	l.workqueue.Forget(obj)
+	return true
+}
+
+// syncVMServiceIP checks whether the VirtualMachineService of the Service has
+// been assigned an IP, in which case the IP is reported on the Service without
+// waiting for the next resync of the service controller. An error is returned
+// to poll the VirtualMachineService again.
+func (l *loadBalancer) syncVMServiceIP(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	service, err := l.client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer || service.DeletionTimestamp != nil {
+		return nil
+	}
+
+	vmService, err := l.vmService.Get(ctx, service, l.clusterName)
+	if err != nil {
+		return err
+	}
+	if vmService == nil {
+		// the service controller creates it again
+		return nil
+	}
+	status := toStatus(vmService)
+	if len(status.Ingress) == 0 {
+		message := vmservice.ErrVMServiceIPNotFound.Error()
+		if warning := l.supervisorWarning(ctx, vmService.Name); warning != "" {
+			message = fmt.Sprintf("%s: %s", message, warning)
+			l.recorder.Event(service, v1.EventTypeWarning, reasonIPPending, message)
+		}
+		if err := l.patchStatus(ctx, service, metav1.ConditionFalse, reasonIPPending, message, nil); err != nil {
+			klog.Errorf("failed to update the %s condition of %s: %v", LoadBalancerConditionType, key, err)
+		}
+		return vmservice.ErrVMServiceIPNotFound
+	}
+	return l.reportReady(ctx, service, status)
+}
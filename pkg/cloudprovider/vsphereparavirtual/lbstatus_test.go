@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+)
+
+func newTestReportingLoadBalancer(t *testing.T, supervisorEvents ...runtime.Object) (*loadBalancer, *dynamicfake.FakeDynamicClient, *fake.Clientset, *record.FakeRecorder, *v1.Service) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testK8sServiceName,
+			Namespace: testK8sServiceNameSpace,
+		},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeLoadBalancer,
+			Ports: []v1.ServicePort{{Name: "http", Protocol: v1.ProtocolTCP, Port: 80, NodePort: 30800}},
+		},
+	}
+	lbi, fc := newTestLoadBalancer()
+	lb := lbi.(*loadBalancer)
+	lb.supervisorEvents = fake.NewSimpleClientset(supervisorEvents...).CoreV1().Events(testClusterNameSpace)
+	client := fake.NewSimpleClientset(service)
+	recorder := record.NewFakeRecorder(10)
+	lb.initialize(testClustername, client, recorder)
+	t.Cleanup(lb.workqueue.ShutDown)
+	return lb, fc, client, recorder, service
+}
+
+func TestEnsureLoadBalancerReportsPendingIP(t *testing.T) {
+	vmServiceName := vmservice.NewVMService(nil, testClusterNameSpace, &testOwnerReference).GetVMServiceName(&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: testK8sServiceName, Namespace: testK8sServiceNameSpace}}, testClustername)
+	warning := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "pool-exhausted", Namespace: testClusterNameSpace},
+		InvolvedObject: v1.ObjectReference{Kind: "Service", Name: vmServiceName},
+		Type:           v1.EventTypeWarning,
+		Message:        "IP pool exhausted",
+		LastTimestamp:  metav1.Now(),
+	}
+	lb, _, client, recorder, service := newTestReportingLoadBalancer(t, warning)
+
+	_, err := lb.EnsureLoadBalancer(context.Background(), testClustername, service, nil)
+	assert.ErrorIs(t, err, vmservice.ErrVMServiceIPNotFound)
+
+	event := <-recorder.Events
+	assert.True(t, strings.HasPrefix(event, "Warning "+reasonIPPending), event)
+	assert.Contains(t, event, "IP pool exhausted")
+	assert.Equal(t, 1, lb.workqueue.NumRequeues(testK8sServiceNameSpace+"/"+testK8sServiceName))
+
+	updated, err := client.CoreV1().Services(service.Namespace).Get(context.Background(), service.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	condition := meta.FindStatusCondition(updated.Status.Conditions, LoadBalancerConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, reasonIPPending, condition.Reason)
+		assert.Contains(t, condition.Message, "IP pool exhausted")
+	}
+}
+
+func TestSyncVMServiceIP(t *testing.T) {
+	lb, fc, client, recorder, service := newTestReportingLoadBalancer(t)
+	_, err := lb.EnsureLoadBalancer(context.Background(), testClustername, service, nil)
+	assert.ErrorIs(t, err, vmservice.ErrVMServiceIPNotFound)
+	<-recorder.Events
+
+	key := testK8sServiceNameSpace + "/" + testK8sServiceName
+	assert.ErrorIs(t, lb.syncVMServiceIP(context.Background(), key), vmservice.ErrVMServiceIPNotFound)
+
+	// the supervisor assigns an IP to the VirtualMachineService
+	gvr := vmopv1.SchemeGroupVersion.WithResource("virtualmachineservices")
+	name := lb.vmService.GetVMServiceName(service, testClustername)
+	obj, err := fc.Resource(gvr).Namespace(testClusterNameSpace).Get(context.Background(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, unstructured.SetNestedSlice(obj.Object, []interface{}{map[string]interface{}{"ip": "10.10.10.10"}}, "status", "loadBalancer", "ingress"))
+	_, err = fc.Resource(gvr).Namespace(testClusterNameSpace).Update(context.Background(), obj, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, lb.syncVMServiceIP(context.Background(), key))
+	select {
+	case event := <-recorder.Events:
+		assert.True(t, strings.HasPrefix(event, "Normal "+reasonIPAssigned), event)
+	case <-time.After(time.Second):
+		t.Fatal("expected an event for the assigned IP")
+	}
+
+	updated, err := client.CoreV1().Services(service.Namespace).Get(context.Background(), service.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.LoadBalancerIngress{{IP: "10.10.10.10"}}, updated.Status.LoadBalancer.Ingress)
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, LoadBalancerConditionType))
+	assert.Equal(t, 0, lb.workqueue.NumRequeues(key))
+}
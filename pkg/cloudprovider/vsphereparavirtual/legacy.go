@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"io"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// LegacyParavirtualFlag is the flag enabling the deprecated legacy
+	// paravirtual mode, selecting the VMs by their capw.vmware.com labels
+	LegacyParavirtualFlag = "is-legacy-paravirtual"
+	// ConvertLegacyParavirtualFlag is the flag printing the configuration
+	// equivalent to the legacy paravirtual mode
+	ConvertLegacyParavirtualFlag = "convert-legacy-paravirtual"
+
+	// LegacyParavirtualDeprecatedRelease is the release deprecating the
+	// legacy paravirtual mode
+	LegacyParavirtualDeprecatedRelease = "v1.33"
+	// LegacyParavirtualRemovalRelease is the release removing the legacy
+	// paravirtual mode and LegacyParavirtualFlag
+	LegacyParavirtualRemovalRelease = "v1.35"
+)
+
+// legacyModeMetric reports whether the legacy paravirtual mode is enabled, so
+// that fleets can track the clusters to migrate before its removal
+var legacyModeMetric = metrics.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "paravirtual_legacy_mode",
+		Help: "1 if the deprecated legacy paravirtual mode is enabled with --" + LegacyParavirtualFlag + ", 0 otherwise",
+	},
+	[]string{"removal_release"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(legacyModeMetric)
+}
+
+// LegacyParavirtualConversion is the configuration equivalent to a legacy
+// paravirtual one.
+type LegacyParavirtualConversion struct {
+	// Args are the command line arguments without LegacyParavirtualFlag.
+	Args []string `json:"args"`
+	// Selectors are the VM labels selected by the VirtualMachineServices, by
+	// cluster role. The VMs must carry them before the legacy mode is
+	// disabled.
+	Selectors map[string]map[string]string `json:"selectors"`
+}
+
+// ConvertLegacyParavirtual returns the configuration equivalent to the legacy
+// paravirtual mode of the cluster named clusterName run with the command line
+// arguments args.
+func ConvertLegacyParavirtual(clusterName string, args []string) LegacyParavirtualConversion {
+	conversion := LegacyParavirtualConversion{
+		Args:      []string{},
+		Selectors: make(map[string]map[string]string),
+	}
+	for _, arg := range args {
+		if name := argFlagName(arg); name != LegacyParavirtualFlag && name != ConvertLegacyParavirtualFlag {
+			conversion.Args = append(conversion.Args, arg)
+		}
+	}
+	for _, role := range strings.Split(vmservice.AllowedClusterRoles, ",") {
+		role = strings.TrimSpace(role)
+		if role == "" {
+			continue
+		}
+		conversion.Selectors[role] = vmservice.ConvertLegacySelector(map[string]string{
+			vmservice.LegacyClusterSelectorKey: clusterName,
+			vmservice.LegacyNodeSelectorKey:    role,
+		})
+	}
+	return conversion
+}
+
+// WriteLegacyParavirtualConversion writes the configuration equivalent to the
+// legacy paravirtual mode as YAML.
+func WriteLegacyParavirtualConversion(w io.Writer, clusterName string, args []string) error {
+	data, err := yaml.Marshal(ConvertLegacyParavirtual(clusterName, args))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// argFlagName returns the normalized name of the flag of a command line
+// argument, "" if it is not a flag.
+func argFlagName(arg string) string {
+	if !strings.HasPrefix(arg, "-") {
+		return ""
+	}
+	name := strings.TrimLeft(arg, "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ReplaceAll(name, "_", "-")
+}
+
+// warnLegacyParavirtual sets legacyModeMetric, and logs a deprecation warning
+// with the equivalent configuration if the legacy paravirtual mode is enabled.
+func warnLegacyParavirtual(clusterName string, args []string) {
+	if !vmservice.IsLegacy {
+		legacyModeMetric.WithLabelValues(LegacyParavirtualRemovalRelease).Set(0)
+		return
+	}
+	legacyModeMetric.WithLabelValues(LegacyParavirtualRemovalRelease).Set(1)
+
+	conversion := ConvertLegacyParavirtual(clusterName, args)
+	logging.Logger(logging.Paravirtual).Info("WARNING: the legacy paravirtual mode is deprecated, label the VMs with the selectors and drop the flag",
+		"flag", "--"+LegacyParavirtualFlag,
+		"deprecatedIn", LegacyParavirtualDeprecatedRelease,
+		"removedIn", LegacyParavirtualRemovalRelease,
+		"selectors", conversion.Selectors,
+		"args", conversion.Args)
+}
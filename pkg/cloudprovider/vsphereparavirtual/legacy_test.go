@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+)
+
+func TestConvertLegacyParavirtual(t *testing.T) {
+	args := []string{
+		"--cloud-provider=vsphere-paravirtual",
+		"--is-legacy-paravirtual",
+		"--is_legacy_paravirtual=true",
+		"--convert-legacy-paravirtual",
+		"--cluster-name=test-cluster",
+		"-v=2",
+	}
+	conversion := ConvertLegacyParavirtual("test-cluster", args)
+
+	expectedArgs := []string{"--cloud-provider=vsphere-paravirtual", "--cluster-name=test-cluster", "-v=2"}
+	if !reflect.DeepEqual(conversion.Args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, conversion.Args)
+	}
+	expectedSelectors := map[string]map[string]string{
+		vmservice.NodeRole: {
+			vmservice.ClusterSelectorKey: "test-cluster",
+			vmservice.NodeSelectorKey:    vmservice.NodeRole,
+		},
+		vmservice.ControlPlaneRole: {
+			vmservice.ClusterSelectorKey: "test-cluster",
+			vmservice.NodeSelectorKey:    vmservice.ControlPlaneRole,
+		},
+	}
+	if !reflect.DeepEqual(conversion.Selectors, expectedSelectors) {
+		t.Errorf("expected selectors %v, got %v", expectedSelectors, conversion.Selectors)
+	}
+
+	var out bytes.Buffer
+	if err := WriteLegacyParavirtualConversion(&out, "test-cluster", args); err != nil {
+		t.Fatalf("WriteLegacyParavirtualConversion failed: %v", err)
+	}
+	if !strings.Contains(out.String(), vmservice.ClusterSelectorKey+": test-cluster") || strings.Contains(out.String(), LegacyParavirtualFlag) {
+		t.Errorf("unexpected conversion:\n%s", out.String())
+	}
+}
+
+func TestWarnLegacyParavirtual(t *testing.T) {
+	defer func() { vmservice.IsLegacy = false }()
+
+	gauge := legacyModeMetric.WithLabelValues(LegacyParavirtualRemovalRelease)
+	warnLegacyParavirtual("test-cluster", nil)
+	if value := testutil.ToFloat64(gauge); value != 0 {
+		t.Errorf("expected the legacy mode metric to be 0, got %v", value)
+	}
+
+	vmservice.IsLegacy = true
+	warnLegacyParavirtual("test-cluster", []string{"--" + LegacyParavirtualFlag})
+	if value := testutil.ToFloat64(gauge); value != 1 {
+		t.Errorf("expected the legacy mode metric to be 1, got %v", value)
+	}
+}
@@ -23,7 +23,11 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
 
@@ -31,25 +35,51 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
+// LoadBalancerProvider is the interface definition for LoadBalancer functionality
+type LoadBalancerProvider interface {
+	cloudprovider.LoadBalancer
+	// Initialize starts reporting the failures of the VirtualMachineServices
+	// as Events and conditions of their Services, and polling the ones
+	// without IP until one is assigned
+	Initialize(clusterName string, client clientset.Interface, stop <-chan struct{})
+}
+
 // loadBalancer implements cloudprovider.LoadBalancer interface
 type loadBalancer struct {
 	vmService vmservice.VMService
+	// supervisorEvents lists the events of the VirtualMachineServices
+	supervisorEvents typedcorev1.EventInterface
+
+	// the fields below are set by Initialize
+	clusterName string
+	client      clientset.Interface
+	recorder    record.EventRecorder
+	workqueue   workqueue.RateLimitingInterface
 }
 
-// NewLoadBalancer returns an implementation of cloudprovider.LoadBalancer
-func NewLoadBalancer(clusterNS string, kcfg *rest.Config, ownerRef *metav1.OwnerReference) (cloudprovider.LoadBalancer, error) {
-	klog.V(1).Info("Create load balancer for vsphere paravirtual cloud provider")
+var _ LoadBalancerProvider = &loadBalancer{}
+
+// NewLoadBalancer returns an implementation of LoadBalancerProvider
+func NewLoadBalancer(clusterNS string, kcfg *rest.Config, ownerRef func() *metav1.OwnerReference) (LoadBalancerProvider, error) {
+	logging.V(logging.Paravirtual, 1).Info("Create load balancer for vsphere paravirtual cloud provider")
 
 	client, err := vmservice.GetVmopClient(kcfg)
 	if err != nil {
 		klog.Errorf("failed to create load balancer: %v", err)
 		return nil, err
 	}
-	vmService := vmservice.NewVMService(client, clusterNS, ownerRef)
+	supervisorClient, err := clientset.NewForConfig(kcfg)
+	if err != nil {
+		klog.Errorf("failed to create load balancer: %v", err)
+		return nil, err
+	}
+	vmService := vmservice.NewVMServiceWithOwnerReference(client, clusterNS, ownerRef)
 	return &loadBalancer{
-		vmService: vmService,
+		vmService:        vmService,
+		supervisorEvents: supervisorClient.CoreV1().Events(clusterNS),
 	}, nil
 }
 
@@ -59,7 +89,7 @@ func NewLoadBalancer(clusterNS string, kcfg *rest.Config, ownerRef *metav1.Owner
 // Implementations must treat the *v1.Service parameter as read-only and not modify it.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (l *loadBalancer) GetLoadBalancer(ctx context.Context, clusterName string, service *v1.Service) (status *v1.LoadBalancerStatus, exists bool, err error) {
-	klog.V(1).Infof("Get load balancer for %s", namespacedName(service))
+	logging.V(logging.Paravirtual, 1).Infof("Get load balancer for %s", namespacedName(service))
 
 	vmService, err := l.vmService.Get(ctx, service, clusterName)
 
@@ -79,7 +109,7 @@ func (l *loadBalancer) GetLoadBalancer(ctx context.Context, clusterName string,
 // GetLoadBalancerName returns the name of the load balancer. Implementations must treat the
 // *v1.Service parameter as read-only and not modify it.
 func (l *loadBalancer) GetLoadBalancerName(ctx context.Context, clusterName string, service *v1.Service) string {
-	klog.V(1).Infof("Get load balancer name for service  %s", namespacedName(service))
+	logging.V(logging.Paravirtual, 1).Infof("Get load balancer name for service  %s", namespacedName(service))
 	//TODO: confirm what name should be used here: vmService name? the real lb name on nsx-t ?
 
 	return l.vmService.GetVMServiceName(service, clusterName)
@@ -90,16 +120,20 @@ func (l *loadBalancer) GetLoadBalancerName(ctx context.Context, clusterName stri
 // parameters as read-only and not modify them.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (l *loadBalancer) EnsureLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) (*v1.LoadBalancerStatus, error) {
-	klog.V(1).Infof("Ensure Load Balancer for %s", namespacedName(service))
+	logging.V(logging.Paravirtual, 1).Infof("Ensure Load Balancer for %s", namespacedName(service))
 
 	vmService, err := l.vmService.CreateOrUpdate(ctx, service, clusterName)
 
 	if err != nil {
 		klog.Errorf("failed to ensure virtual machine service for %s: %v", namespacedName(service), err)
+		l.reportFailure(ctx, service, l.vmService.GetVMServiceName(service, clusterName), err)
 		return nil, err
 	}
 
-	klog.V(1).Infof("Ensured load balancer for %s with virtual machine service %s", namespacedName(service), vmService.Name)
+	logging.V(logging.Paravirtual, 1).Infof("Ensured load balancer for %s with virtual machine service %s", namespacedName(service), vmService.Name)
+	if err := l.reportReady(ctx, service, nil); err != nil {
+		klog.Errorf("failed to update the %s condition of %s: %v", LoadBalancerConditionType, namespacedName(service), err)
+	}
 
 	return toStatus(vmService), nil
 }
@@ -109,7 +143,7 @@ func (l *loadBalancer) EnsureLoadBalancer(ctx context.Context, clusterName strin
 // parameters as read-only and not modify them.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (l *loadBalancer) UpdateLoadBalancer(ctx context.Context, clusterName string, service *v1.Service, nodes []*v1.Node) error {
-	klog.V(1).Infof("Update load balancer for %s", namespacedName(service))
+	logging.V(logging.Paravirtual, 1).Infof("Update load balancer for %s", namespacedName(service))
 
 	vmService, err := l.vmService.Get(ctx, service, clusterName)
 
@@ -127,10 +161,11 @@ func (l *loadBalancer) UpdateLoadBalancer(ctx context.Context, clusterName strin
 
 	if err != nil {
 		klog.Errorf("failed to update virtual machine service for %s: %v", namespacedName(service), err)
+		l.reportFailure(ctx, service, l.vmService.GetVMServiceName(service, clusterName), err)
 		return err
 	}
 
-	klog.V(1).Infof("updated virtual machine service: %s", vmService.Name)
+	logging.V(logging.Paravirtual, 1).Infof("updated virtual machine service: %s", vmService.Name)
 	return nil
 }
 
@@ -143,7 +178,7 @@ func (l *loadBalancer) UpdateLoadBalancer(ctx context.Context, clusterName strin
 // Implementations must treat the *v1.Service parameter as read-only and not modify it.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (l *loadBalancer) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string, service *v1.Service) error {
-	klog.V(1).Infof("Ensure load balancer is deleted %s", namespacedName(service))
+	logging.V(logging.Paravirtual, 1).Infof("Ensure load balancer is deleted %s", namespacedName(service))
 
 	err := l.vmService.Delete(ctx, service, clusterName)
 
@@ -152,10 +187,10 @@ func (l *loadBalancer) EnsureLoadBalancerDeleted(ctx context.Context, clusterNam
 			klog.Errorf("failed to delete load balancer for %s", namespacedName(service))
 			return err
 		}
-		klog.V(1).Infof("load balancer for %s is not found", namespacedName(service))
+		logging.V(logging.Paravirtual, 1).Infof("load balancer for %s is not found", namespacedName(service))
 	}
 
-	klog.V(1).Infof("load balancer for %s is deleted", namespacedName(service))
+	logging.V(logging.Paravirtual, 1).Infof("load balancer for %s is deleted", namespacedName(service))
 
 	return nil
 }
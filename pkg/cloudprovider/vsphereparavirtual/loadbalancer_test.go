@@ -79,7 +79,7 @@ func TestNewLoadBalancer(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			_, err := NewLoadBalancer(testClusterNameSpace, testCase.config, &testOwnerReference)
+			_, err := NewLoadBalancer(testClusterNameSpace, testCase.config, func() *metav1.OwnerReference { return &testOwnerReference })
 			assert.Equal(t, testCase.err, err)
 		})
 	}
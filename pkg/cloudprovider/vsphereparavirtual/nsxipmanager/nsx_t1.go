@@ -9,6 +9,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/ippoolmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 var _ NSXIPManager = &NSXT1IPManager{}
@@ -29,7 +30,7 @@ func (m *NSXT1IPManager) ClaimPodCIDR(node *corev1.Node) error {
 			return fmt.Errorf("fail to get ippool in namespace %s for cluster %s", m.svNamespace, m.clusterName)
 		}
 		// if ippool does not exist, create one
-		klog.V(4).Info("creating ippool")
+		logging.V(logging.Paravirtual, 4).Info("creating ippool")
 		if ippool, err = m.ippoolManager.CreateIPPool(m.svNamespace, m.clusterName, m.ownerRef); err != nil {
 			klog.Error("error creating ippool")
 			return err
@@ -41,7 +42,7 @@ func (m *NSXT1IPManager) ClaimPodCIDR(node *corev1.Node) error {
 		return fmt.Errorf("fail to add subnet in IPPool for node %s, err: %v", node.Name, err)
 	}
 
-	klog.V(4).Infof("added the subnet in IPPool for node %s", node.Name)
+	logging.V(logging.Paravirtual, 4).Infof("added the subnet in IPPool for node %s", node.Name)
 	return nil
 }
 
@@ -50,7 +51,7 @@ func (m *NSXT1IPManager) ReleasePodCIDR(node *corev1.Node) error {
 	ippool, err := m.ippoolManager.GetIPPool(m.svNamespace, m.clusterName)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.V(4).Info("ippool is gone, no need to remove the node request")
+			logging.V(logging.Paravirtual, 4).Info("ippool is gone, no need to remove the node request")
 			return nil
 		}
 		return fmt.Errorf("fail to get ippool in namespace %s for cluster %s", m.svNamespace, m.clusterName)
@@ -61,7 +62,7 @@ func (m *NSXT1IPManager) ReleasePodCIDR(node *corev1.Node) error {
 		return fmt.Errorf("fail to delete subnet in IPPool for node %s, err: %v", node.Name, err)
 	}
 
-	klog.V(4).Infof("deleted the subnet in IPPool for node %s", node.Name)
+	logging.V(logging.Paravirtual, 4).Infof("deleted the subnet in IPPool for node %s", node.Name)
 	return nil
 }
 
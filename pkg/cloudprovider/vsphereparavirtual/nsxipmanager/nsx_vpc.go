@@ -9,7 +9,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 const allocationSize = 256
@@ -26,7 +27,7 @@ type NSXVPCIPManager struct {
 }
 
 func (m *NSXVPCIPManager) createIPAddressAllocation(name string) error {
-	klog.V(4).Infof("Creating IPAddressAllocation %s/%s", m.svNamespace, name)
+	logging.V(logging.Paravirtual, 4).Infof("Creating IPAddressAllocation %s/%s", m.svNamespace, name)
 	ipAddressAllocation := &vpcapisv1.IPAddressAllocation{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -48,7 +49,7 @@ func (m *NSXVPCIPManager) createIPAddressAllocation(name string) error {
 func (m *NSXVPCIPManager) ClaimPodCIDR(node *corev1.Node) error {
 	// Keep the same behavior as NSX T1: add Pod CIDR allocation req only when node doesn't contain pod cidr
 	if node.Spec.PodCIDR != "" && len(node.Spec.PodCIDRs) > 0 {
-		klog.V(4).Infof("Pod CIDR %s is already set to node %s", node.Spec.PodCIDR, node.Name)
+		logging.V(logging.Paravirtual, 4).Infof("Pod CIDR %s is already set to node %s", node.Spec.PodCIDR, node.Name)
 		return nil
 	}
 
@@ -59,7 +60,7 @@ func (m *NSXVPCIPManager) ClaimPodCIDR(node *corev1.Node) error {
 		return err
 	}
 
-	klog.V(4).Infof("Node %s already requested IPAddressAllocations", node.Name)
+	logging.V(logging.Paravirtual, 4).Infof("Node %s already requested IPAddressAllocations", node.Name)
 	return nil
 }
 
@@ -67,13 +68,13 @@ func (m *NSXVPCIPManager) ClaimPodCIDR(node *corev1.Node) error {
 func (m *NSXVPCIPManager) ReleasePodCIDR(node *corev1.Node) error {
 	if _, err := m.informerFactory.Crd().V1alpha1().IPAddressAllocations().Lister().IPAddressAllocations(m.svNamespace).Get(node.Name); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.V(4).Infof("IPAddressAllocations %s not found, no need to delete it", node.Name)
+			logging.V(logging.Paravirtual, 4).Infof("IPAddressAllocations %s not found, no need to delete it", node.Name)
 			return nil
 		}
 		return err
 	}
 
-	klog.V(4).Infof("Deleting IPAddressAllocations %s/%s", m.svNamespace, node.Name)
+	logging.V(logging.Paravirtual, 4).Infof("Deleting IPAddressAllocations %s/%s", m.svNamespace, node.Name)
 	return m.client.CrdV1alpha1().IPAddressAllocations(m.svNamespace).Delete(context.Background(), node.Name, metav1.DeleteOptions{})
 }
 
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// ownerRefReadTimeout is the time given to the owner reference file to
+	// become readable at startup
+	ownerRefReadTimeout = 2 * time.Minute
+	// ownerRefReadInterval is the interval between the reads of the owner
+	// reference file at startup
+	ownerRefReadInterval = 5 * time.Second
+)
+
+// ownerRefCache caches the owner reference of the TanzuKubernetesCluster,
+// which owns the CRs created in the supervisor cluster. The owner reference
+// is refreshed periodically from the owner reference file, and the cached
+// owner reference is kept when the file is briefly unavailable, for instance
+// while the supervisor is upgraded.
+type ownerRefCache struct {
+	path string
+
+	mu       sync.RWMutex
+	ownerRef metav1.OwnerReference
+}
+
+// newOwnerRefCache reads the owner reference file, retrying until the
+// timeout expires.
+func newOwnerRefCache(path string, timeout time.Duration) (*ownerRefCache, error) {
+	c := &ownerRefCache{path: path}
+	var lastErr error
+	err := wait.PollUntilContextTimeout(context.Background(), ownerRefReadInterval, timeout, true, func(context.Context) (bool, error) {
+		ownerRef, err := readOwnerRef(path)
+		if err != nil {
+			klog.Warningf("Reading the owner reference failed, retrying: %v", err)
+			lastErr = err
+			return false, nil
+		}
+		c.ownerRef = *ownerRef
+		return true, nil
+	})
+	if err != nil && lastErr != nil {
+		return nil, lastErr
+	}
+	return c, err
+}
+
+// Get returns the cached owner reference.
+func (c *ownerRefCache) Get() *metav1.OwnerReference {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ownerRef := c.ownerRef
+	return &ownerRef
+}
+
+// Run refreshes the owner reference until the stop channel is closed.
+func (c *ownerRefCache) Run(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+	wait.Until(c.refresh, interval, stop)
+}
+
+// refresh reads the owner reference file, keeping the cached owner reference
+// if it fails.
+func (c *ownerRefCache) refresh() {
+	ownerRef, err := readOwnerRef(c.path)
+	if err != nil {
+		cached := c.Get()
+		klog.Warningf("Refreshing the owner reference failed, keeping %s %s: %v", cached.Kind, cached.Name, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if reflect.DeepEqual(c.ownerRef, *ownerRef) {
+		return
+	}
+	logging.V(logging.Paravirtual, 2).Infof("Owner reference changed from %s %s (%s) to %s %s (%s)",
+		c.ownerRef.Kind, c.ownerRef.Name, c.ownerRef.UID, ownerRef.Kind, ownerRef.Name, ownerRef.UID)
+	c.ownerRef = *ownerRef
+}
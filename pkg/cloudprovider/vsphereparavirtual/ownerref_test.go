@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestOwnerRefCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ownerref.json")
+	writeOwnerRef := func(uid string) {
+		data, err := json.Marshal(metav1.OwnerReference{APIVersion: "v1alpha1", Kind: "TanzuKubernetesCluster", Name: "my-cluster", UID: types.UID("uid-" + uid)})
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, data, 0600))
+	}
+
+	_, err := newOwnerRefCache(path, 0)
+	assert.Error(t, err)
+
+	writeOwnerRef("1")
+	cache, err := newOwnerRefCache(path, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "uid-1", string(cache.Get().UID))
+
+	// a refresh failure keeps the cached owner reference
+	assert.NoError(t, os.Remove(path))
+	cache.refresh()
+	assert.Equal(t, "uid-1", string(cache.Get().UID))
+
+	writeOwnerRef("2")
+	cache.refresh()
+	assert.Equal(t, "uid-2", string(cache.Get().UID))
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+const (
+	// PodVMUUIDAnnotationKey is the annotation the supervisor sets on vSphere
+	// Pods with the BIOS UUID of their pod VM. Guest cluster nodes backed by a
+	// vSphere Pod have no VirtualMachine, their metadata is read from the Pod.
+	PodVMUUIDAnnotationKey = "vmware-system-vm-uuid"
+
+	// PodVMInstanceType is the instance type of the nodes backed by a vSphere
+	// Pod.
+	PodVMInstanceType = "vsphere-pod"
+
+	// zoneLabelKey is the label of the zone of VirtualMachines and vSphere
+	// Pods.
+	zoneLabelKey = "topology.kubernetes.io/zone"
+)
+
+// getPodVMUUID returns the BIOS UUID of the pod VM of a vSphere Pod, or "" if
+// the Pod is not a vSphere Pod.
+func getPodVMUUID(pod *v1.Pod) string {
+	return strings.ToLower(strings.TrimSpace(pod.Annotations[PodVMUUIDAnnotationKey]))
+}
+
+// discoverPodVMByName takes a node name and returns the vSphere Pod backing
+// the node if one exists, or nil otherwise. A nil podClient disables the
+// lookup.
+func discoverPodVMByName(ctx context.Context, name types.NodeName, namespace string, podClient corev1client.PodsGetter) (*v1.Pod, error) {
+	if podClient == nil {
+		return nil, nil
+	}
+	pod, err := podClient.Pods(namespace).Get(ctx, string(name), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if getPodVMUUID(pod) == "" {
+		return nil, nil
+	}
+	logging.V(logging.Paravirtual, 4).Infof("node %s is a vSphere Pod", name)
+	return pod, nil
+}
+
+// discoverPodVMByProviderID takes a ProviderID and returns the vSphere Pod
+// backing the node if one exists, or nil otherwise. A nil podClient disables
+// the lookup.
+func discoverPodVMByProviderID(ctx context.Context, providerID string, namespace string, podClient corev1client.PodsGetter) (*v1.Pod, error) {
+	if podClient == nil {
+		return nil, nil
+	}
+	uuid := GetUUIDFromProviderID(providerID)
+	pods, err := podClient.Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := pods.Items[i]
+		if podVMUUID := getPodVMUUID(&pod); podVMUUID != "" && podVMUUID == uuid {
+			logging.V(logging.Paravirtual, 4).Infof("node %s is the vSphere Pod %s", providerID, pod.Name)
+			return &pod, nil
+		}
+	}
+	return nil, nil
+}
+
+// createPodVMNodeAddresses returns the addresses of a node backed by a
+// vSphere Pod, the IPs of the Pod.
+func createPodVMNodeAddresses(pod *v1.Pod) []v1.NodeAddress {
+	addrs := []v1.NodeAddress{}
+	for _, podIP := range pod.Status.PodIPs {
+		addrs = append(addrs, v1.NodeAddress{Type: v1.NodeInternalIP, Address: podIP.IP})
+	}
+	if len(addrs) == 0 && pod.Status.PodIP != "" {
+		addrs = append(addrs, v1.NodeAddress{Type: v1.NodeInternalIP, Address: pod.Status.PodIP})
+	}
+	if len(addrs) == 0 {
+		logging.V(logging.Paravirtual, 4).Info("vSphere Pod found, but no address yet")
+		return addrs
+	}
+	return append(addrs, v1.NodeAddress{Type: v1.NodeHostName, Address: ""})
+}
+
+// isPodVMShutdown returns true if the vSphere Pod terminated.
+func isPodVMShutdown(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
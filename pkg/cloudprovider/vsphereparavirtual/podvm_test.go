@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+const testPodVMName = "test-pod-vm"
+
+func createTestPodVM(name, namespace, uuid string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{PodVMUUIDAnnotationKey: uuid},
+			Labels:      map[string]string{zoneLabelKey: "zone-a"},
+		},
+		Status: v1.PodStatus{
+			Phase:  v1.PodRunning,
+			PodIP:  "10.0.0.5",
+			PodIPs: []v1.PodIP{{IP: "10.0.0.5"}},
+		},
+	}
+}
+
+// initPodVMTest returns instances and zones of a guest cluster with a VM node
+// and a node backed by a vSphere Pod
+func initPodVMTest(t *testing.T, pods ...*v1.Pod) (*instances, *zones) {
+	instance, _, err := initTest(createTestVM(string(testVMName), testClusterNameSpace, testVMUUID))
+	assert.NoError(t, err)
+	podClient := fake.NewSimpleClientset()
+	for _, pod := range pods {
+		_, err := podClient.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	instance.podClient = podClient.CoreV1()
+	return instance, &zones{vmClient: instance.vmClient, podClient: podClient.CoreV1(), namespace: testClusterNameSpace}
+}
+
+func TestPodVMInstances(t *testing.T) {
+	ctx := context.Background()
+	instance, zone := initPodVMTest(t, createTestPodVM(testPodVMName, testClusterNameSpace, vmuuid))
+	name := types.NodeName(testPodVMName)
+
+	addrs, err := instance.NodeAddresses(ctx, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.NodeAddress{
+		{Type: v1.NodeInternalIP, Address: "10.0.0.5"},
+		{Type: v1.NodeHostName, Address: ""},
+	}, addrs)
+
+	addrs, err = instance.NodeAddressesByProviderID(ctx, providerid)
+	assert.NoError(t, err)
+	assert.Len(t, addrs, 2)
+
+	instanceID, err := instance.InstanceID(ctx, name)
+	assert.NoError(t, err)
+	assert.Equal(t, vmuuid, instanceID)
+
+	instanceType, err := instance.InstanceType(ctx, name)
+	assert.NoError(t, err)
+	assert.Equal(t, PodVMInstanceType, instanceType)
+
+	instanceType, err = instance.InstanceTypeByProviderID(ctx, providerid)
+	assert.NoError(t, err)
+	assert.Equal(t, PodVMInstanceType, instanceType)
+
+	exists, err := instance.InstanceExistsByProviderID(ctx, providerid)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	shutdown, err := instance.InstanceShutdownByProviderID(ctx, providerid)
+	assert.NoError(t, err)
+	assert.False(t, shutdown)
+
+	z, err := zone.GetZoneByNodeName(ctx, name)
+	assert.NoError(t, err)
+	assert.Equal(t, "zone-a", z.FailureDomain)
+
+	z, err = zone.GetZoneByProviderID(ctx, providerid)
+	assert.NoError(t, err)
+	assert.Equal(t, "zone-a", z.FailureDomain)
+
+	// VM nodes are still read from their VirtualMachine
+	instanceType, err = instance.InstanceType(ctx, testVMName)
+	assert.NoError(t, err)
+	assert.Equal(t, "", instanceType)
+	instanceID, err = instance.InstanceID(ctx, testVMName)
+	assert.NoError(t, err)
+	assert.Equal(t, testVMUUID, instanceID)
+}
+
+func TestPodVMInstancesShutdown(t *testing.T) {
+	pod := createTestPodVM(testPodVMName, testClusterNameSpace, vmuuid)
+	pod.Status.Phase = v1.PodFailed
+	instance, _ := initPodVMTest(t, pod)
+
+	shutdown, err := instance.InstanceShutdownByProviderID(context.Background(), providerid)
+	assert.NoError(t, err)
+	assert.True(t, shutdown)
+}
+
+func TestPodVMInstancesNotFound(t *testing.T) {
+	ctx := context.Background()
+	// a Pod which is not a vSphere Pod is not a node
+	pod := createTestPodVM(testPodVMName, testClusterNameSpace, "")
+	instance, _ := initPodVMTest(t, pod)
+
+	_, err := instance.NodeAddresses(ctx, types.NodeName(testPodVMName))
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+	_, err = instance.InstanceID(ctx, types.NodeName(testPodVMName))
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+	exists, err := instance.InstanceExistsByProviderID(ctx, providerid)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	_, err = instance.InstanceShutdownByProviderID(ctx, providerid)
+	assert.Equal(t, cloudprovider.InstanceNotFound, err)
+}
+
+func TestCreatePodVMNodeAddressesWithoutIP(t *testing.T) {
+	pod := createTestPodVM(testPodVMName, testClusterNameSpace, vmuuid)
+	pod.Status.PodIP = ""
+	pod.Status.PodIPs = nil
+	assert.Equal(t, []v1.NodeAddress{}, createPodVMNodeAddresses(pod))
+}
@@ -20,58 +20,97 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
 
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/routemanager"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/routemanager/helper"
 	"k8s.io/cloud-provider-vsphere/pkg/util"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // RoutesProvider is the interface definition for Routes functionality
 type RoutesProvider interface {
 	cloudprovider.Routes
+	// Initialize starts recording the events of the Route CR operations and,
+	// if enabled, the periodic drift detection of the Route CRs
+	Initialize(clusterName string, client clientset.Interface, nodeListerSynced cache.InformerSynced, stop <-chan struct{})
 }
 
 type routesProvider struct {
 	routeManager routemanager.RouteManager
-	ownerRefs    []metav1.OwnerReference
+	ownerRef     func() *metav1.OwnerReference
 	nodeLister   listerv1.NodeLister
+	recorder     record.EventRecorder
+	// driftCheckInterval is the interval of the drift detection, 0 to
+	// disable it
+	driftCheckInterval time.Duration
 }
 
 var _ RoutesProvider = &routesProvider{}
 
 // NewRoutes returns an implementation of RoutesProvider
-func NewRoutes(clusterNS string, kcfg *rest.Config, ownerRef metav1.OwnerReference, vpcModeEnabled bool, nodeLister listerv1.NodeLister) (RoutesProvider, error) {
+func NewRoutes(clusterNS string, kcfg *rest.Config, ownerRef func() *metav1.OwnerReference, vpcModeEnabled bool, nodeLister listerv1.NodeLister) (RoutesProvider, error) {
 	routeManager, err := routemanager.GetRouteManager(vpcModeEnabled, kcfg, clusterNS)
 	if err != nil {
 		return nil, err
 	}
 
-	ownerRefs := []metav1.OwnerReference{
-		ownerRef,
-	}
-
 	return &routesProvider{
-		routeManager: routeManager,
-		nodeLister:   nodeLister,
-		ownerRefs:    ownerRefs,
+		routeManager:       routeManager,
+		nodeLister:         nodeLister,
+		ownerRef:           ownerRef,
+		driftCheckInterval: routeDriftCheckInterval,
 	}, nil
 }
 
+// Initialize implements RoutesProvider.Initialize
+func (r *routesProvider) Initialize(clusterName string, client clientset.Interface, nodeListerSynced cache.InformerSynced, stop <-chan struct{}) {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	go func() {
+		<-stop
+		eventBroadcaster.Shutdown()
+	}()
+	r.recorder = eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: clientName})
+
+	if !RouteEnabled || r.driftCheckInterval <= 0 || clusterName == "" {
+		return
+	}
+	go func() {
+		if !cache.WaitForCacheSync(stop, nodeListerSynced) {
+			return
+		}
+		wait.Until(func() {
+			if err := r.repairDrift(context.TODO(), clusterName); err != nil {
+				klog.Errorf("repairing Route CR drift failed: %v", err)
+			}
+		}, r.driftCheckInterval, stop)
+	}()
+}
+
 // ListRoutes implements Routes.ListRoutes
 // Get RouteSet or StaticRoute CR from SC namespace and then filters routes that belong to the specified clusterName
 // Only return cloudprovider.Route if RouteSet CR status 'Ready' is true
 func (r *routesProvider) ListRoutes(ctx context.Context, clusterName string) ([]*cloudprovider.Route, error) {
-	klog.V(6).Infof("Attempting to list Routes for cluster %s", clusterName)
+	logging.V(logging.Paravirtual, 6).Infof("Attempting to list Routes for cluster %s", clusterName)
 
 	// use labelSelector to filter RouteSet CRs that belong to this cluster
 	labelSelector := metav1.LabelSelector{
@@ -90,48 +129,97 @@ func (r *routesProvider) ListRoutes(ctx context.Context, clusterName string) ([]
 }
 
 // CreateRoute implements Routes.CreateRoute
-// Create a RouteSet or StaticRoute CR for a Node
+// Create a RouteSet or StaticRoute CR for a pod CIDR of a Node
 func (r *routesProvider) CreateRoute(ctx context.Context, clusterName string, nameHint string, route *cloudprovider.Route) error {
 	nodeName := string(route.TargetNode)
-	klog.V(6).Infof("Creating Route for node %s with hint %s in cluster %s", nodeName, nameHint, clusterName)
+	logging.V(logging.Paravirtual, 6).Infof("Creating Route for node %s with hint %s in cluster %s", nodeName, nameHint, clusterName)
 
-	nodeIP, err := r.getNodeIPAddress(nodeName, util.IsIPv4(route.DestinationCIDR))
+	name, err := r.createRouteCR(ctx, clusterName, nameHint, route)
+	if apierrors.IsAlreadyExists(err) {
+		// the name may be taken by the Route CR of another node or pod CIDR
+		names, listErr := r.routeCRNames(ctx, clusterName, route)
+		if listErr == nil && len(names) > 0 {
+			klog.Errorf("Route CR %s is already existing: %v", name, err)
+			return nil
+		}
+		if listErr == nil {
+			err = fmt.Errorf("route CR %s already exists for another route: %w", name, err)
+		}
+	}
+	recordRouteOperation(routeOperationCreate, err)
 	if err != nil {
-		klog.Errorf("getting node %s IP address failed: %v", nodeName, err)
+		klog.Errorf("creating Route CR for node %s failed: %s", nodeName, err)
+		r.recordEvent(nodeName, types.UID(nameHint), v1.EventTypeWarning, RouteCreateFailedReason, "Creating Route CR for %s failed: %v", route.DestinationCIDR, err)
 		return err
 	}
+	logging.V(logging.Paravirtual, 6).Infof("Successfully created Route CR %s for node %s", name, nodeName)
+	r.recordEvent(nodeName, types.UID(nameHint), v1.EventTypeNormal, RouteCreatedReason, "Created Route CR for %s", route.DestinationCIDR)
+	return r.checkStaticRouteRealizedState(name)
+}
 
-	labels := map[string]string{
-		helper.LabelKeyClusterName: clusterName,
+// createRouteCR creates the Route CR of a pod CIDR of a node and returns its
+// name
+func (r *routesProvider) createRouteCR(ctx context.Context, clusterName string, nameHint string, route *cloudprovider.Route) (string, error) {
+	nodeName := string(route.TargetNode)
+	node, err := r.nodeLister.Get(nodeName)
+	if err != nil {
+		return nodeName, fmt.Errorf("getting node %s failed: %w", nodeName, err)
+	}
+	name := routeCRName(node, route.DestinationCIDR)
+	nodeIP, err := r.getNodeIPAddress(nodeName, util.IsIPv4(route.DestinationCIDR))
+	if err != nil {
+		return name, fmt.Errorf("getting node %s IP address failed: %w", nodeName, err)
 	}
+
+	labels := helper.RouteLabels(clusterName, nodeName, route.DestinationCIDR)
 	nodeRef := metav1.OwnerReference{
 		APIVersion: "v1",
 		Kind:       "Node",
 		Name:       nodeName,
 		UID:        types.UID(nameHint),
 	}
-	owners := make([]metav1.OwnerReference, len(r.ownerRefs))
-	copy(owners, r.ownerRefs)
+	var owners []metav1.OwnerReference
+	if r.ownerRef != nil {
+		if ownerRef := r.ownerRef(); ownerRef != nil {
+			owners = append(owners, *ownerRef)
+		}
+	}
 	owners = append(owners, nodeRef)
 	routeInfo := &helper.RouteInfo{
 		Labels:    labels,
 		Owner:     owners,
-		Name:      nodeName,
+		Name:      name,
 		Cidr:      route.DestinationCIDR,
 		NodeIP:    nodeIP,
 		RouteName: helper.GetRouteName(nodeName, route.DestinationCIDR, clusterName),
 	}
 	_, err = r.routeManager.CreateRouteCR(ctx, routeInfo)
-	if err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			klog.Errorf("Route CR %s is already existing: %v", nodeName, err)
-			return nil
-		}
-		klog.Errorf("creating Route CR for node %s failed: %s", nodeName, err)
-		return err
+	return name, err
+}
+
+// routeCRName returns the name of the Route CR of a pod CIDR of a node. The
+// Route CR of the IP family of the first pod CIDR is named after the node, the
+// one of the other IP family of a dual-stack node has a hash of the node name
+// and pod CIDR appended, as any suffix could be the name of another node.
+func routeCRName(node *v1.Node, cidr string) string {
+	cidrs := podCIDRs(node)
+	if len(cidrs) == 0 || util.IsIPv4(cidrs[0]) == util.IsIPv4(cidr) {
+		return node.Name
 	}
-	klog.V(6).Infof("Successfully created Route CR for node %s", nodeName)
-	return r.checkStaticRouteRealizedState(nodeName)
+	hash := helper.Hash(node.Name + "/" + cidr)
+	name := node.Name
+	if maxLength := validation.DNS1123SubdomainMaxLength - len(hash) - 1; len(name) > maxLength {
+		name = strings.TrimRight(name[:maxLength], ".-")
+	}
+	return name + "-" + hash
+}
+
+// podCIDRs returns the pod CIDRs of a node
+func podCIDRs(node *v1.Node) []string {
+	if len(node.Spec.PodCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		return []string{node.Spec.PodCIDR}
+	}
+	return node.Spec.PodCIDRs
 }
 
 // checkStaticRouteRealizedState checks static route realized state. The ready status is updated to Route CR by ncp/nsx-operator afterwards
@@ -153,18 +241,80 @@ func (r *routesProvider) checkStaticRouteRealizedState(routeSetName string) erro
 }
 
 // DeleteRoute implements Routes.DeleteRouteCR
-// Delete node's corresponding RouteSet or StaticRoute CR
+// Delete the RouteSet or StaticRoute CR of a pod CIDR of a Node, leaving the
+// Route CR of the other IP family of a dual-stack Node in place
 func (r *routesProvider) DeleteRoute(ctx context.Context, clusterName string, route *cloudprovider.Route) error {
-	routeSetName := string(route.TargetNode)
-	klog.V(6).Infof("Deleting Route CR %s in cluster %s", routeSetName, clusterName)
-	if err := r.routeManager.DeleteRouteCR(routeSetName); err != nil {
-		klog.ErrorS(helper.ErrDeleteRouteCR, fmt.Sprintf("%v", err))
+	nodeName := string(route.TargetNode)
+	names, err := r.routeCRNames(ctx, clusterName, route)
+	if err != nil {
+		recordRouteOperation(routeOperationDelete, err)
+		klog.ErrorS(helper.ErrListRouteCR, fmt.Sprintf("%v", err))
+		r.recordEvent(nodeName, "", v1.EventTypeWarning, RouteDeleteFailedReason, "Deleting Route CR failed: %v", err)
+		return nil
+	}
+	for _, name := range names {
+		logging.V(logging.Paravirtual, 6).Infof("Deleting Route CR %s in cluster %s", name, clusterName)
+		err := r.routeManager.DeleteRouteCR(name)
+		if apierrors.IsNotFound(err) {
+			err = nil
+		}
+		recordRouteOperation(routeOperationDelete, err)
+		if err != nil {
+			klog.ErrorS(helper.ErrDeleteRouteCR, fmt.Sprintf("%v", err))
+			r.recordEvent(nodeName, "", v1.EventTypeWarning, RouteDeleteFailedReason, "Deleting Route CR failed: %v", err)
+			continue
+		}
+		r.recordEvent(nodeName, "", v1.EventTypeNormal, RouteDeletedReason, "Deleted Route CR for %s", route.DestinationCIDR)
+		logging.V(logging.Paravirtual, 6).Infof("Successfully deleted Route CR %s for node %s", name, nodeName)
 	}
-	// routeset name equals node name
-	klog.V(6).Infof("Successfully deleted Route CR for node %s", routeSetName)
 	return nil
 }
 
+// routeCRNames returns the names of the Route CRs of the cluster routing the
+// destination CIDR of a route to its target node. The Route CRs are looked up
+// by their node and pod CIDR labels, the ones created without these labels by
+// their Node owner reference and routes.
+func (r *routesProvider) routeCRNames(ctx context.Context, clusterName string, route *cloudprovider.Route) ([]string, error) {
+	labelSelector := metav1.LabelSelector{
+		MatchLabels: helper.RouteLabels(clusterName, string(route.TargetNode), route.DestinationCIDR),
+	}
+	names, err := r.listRouteCRNames(ctx, labelSelector, func(*helper.RouteInfo) bool { return true })
+	if err != nil || len(names) > 0 {
+		return names, err
+	}
+
+	labelSelector = metav1.LabelSelector{
+		MatchLabels: map[string]string{helper.LabelKeyClusterName: clusterName},
+	}
+	return r.listRouteCRNames(ctx, labelSelector, func(info *helper.RouteInfo) bool {
+		_, labeled := info.Labels[helper.LabelKeyNodeName]
+		return !labeled && info.Cidr == route.DestinationCIDR && helper.NodeName(info.Name, info.Owner) == string(route.TargetNode)
+	})
+}
+
+// listRouteCRNames returns the names of the Route CRs matching a label
+// selector and a filter
+func (r *routesProvider) listRouteCRNames(ctx context.Context, labelSelector metav1.LabelSelector, filter func(*helper.RouteInfo) bool) ([]string, error) {
+	list, err := r.routeManager.ListRouteCR(ctx, labelSelector)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	routes, err := r.routeManager.CreateRouteInfos(list)
+	if err != nil {
+		return nil, err
+	}
+	names := sets.New[string]()
+	for _, info := range routes {
+		if filter(info) {
+			names.Insert(info.Name)
+		}
+	}
+	return sets.List(names), nil
+}
+
 // getNodeIPAddress gets node IP address
 // IP family of node address and podCIDR should be the same
 // The order is to choose node internal IP first, then external IP
@@ -198,7 +348,7 @@ func (r *routesProvider) getNodeIPAddress(nodeName string, isIPv4 bool) (string,
 	}
 	for _, ip := range allIPs {
 		if (ip.To4() != nil) == isIPv4 {
-			klog.V(4).Infof("successfully fetching node %s IP address", node.Name)
+			logging.V(logging.Paravirtual, 4).Infof("successfully fetching node %s IP address", node.Name)
 			return ip.String(), nil
 		}
 	}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/routemanager/helper"
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+	"k8s.io/cloud-provider-vsphere/pkg/util"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// Reasons of the events emitted on a Node for its Route CR
+const (
+	// RouteCreatedReason is the reason of the event emitted when a Route CR is created
+	RouteCreatedReason = "RouteCreated"
+	// RouteCreateFailedReason is the reason of the event emitted when a Route CR fails to be created
+	RouteCreateFailedReason = "RouteCreateFailed"
+	// RouteDeletedReason is the reason of the event emitted when a Route CR is deleted
+	RouteDeletedReason = "RouteDeleted"
+	// RouteDeleteFailedReason is the reason of the event emitted when a Route CR fails to be deleted
+	RouteDeleteFailedReason = "RouteDeleteFailed"
+	// RouteDriftRepairedReason is the reason of the event emitted when a Route CR diverging from the Node is repaired
+	RouteDriftRepairedReason = "RouteDriftRepaired"
+	// RoutePartiallyProgrammedReason is the reason of the event emitted when
+	// only some pod CIDRs of a dual-stack Node have a Route CR
+	RoutePartiallyProgrammedReason = "RoutePartiallyProgrammed"
+)
+
+// Route operations, used as label of routeOperationsMetric
+const (
+	routeOperationCreate = "create"
+	routeOperationDelete = "delete"
+)
+
+// Kinds of drift between the Nodes and the Route CRs, used as label of
+// routeDriftMetric
+const (
+	// routeDriftMissing is a Node with pod CIDR but without Route CR
+	routeDriftMissing = "missing"
+	// routeDriftMismatched is a Route CR not routing a pod CIDR of its Node
+	// to the Node IP
+	routeDriftMismatched = "mismatched"
+	// routeDriftStale is a Route CR of a Node which does not exist anymore, or
+	// of a pod CIDR the Node does not have anymore
+	routeDriftStale = "stale"
+)
+
+// routeOperationsMetric counts the Route CR operations by result
+var routeOperationsMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "paravirtual_route_operations",
+		Help: "Route CR operations of the vSphere paravirtual cloud provider",
+	},
+	[]string{"operation", "result"},
+)
+
+// routeDriftMetric counts the Route CRs found diverging from the Nodes and
+// repaired by the drift detection
+var routeDriftMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "paravirtual_route_drift_repairs",
+		Help: "Route CRs repaired after diverging from the Node pod CIDRs",
+	},
+	[]string{"kind"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(routeOperationsMetric)
+	legacyregistry.RawMustRegister(routeDriftMetric)
+}
+
+func recordRouteOperation(operation string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	routeOperationsMetric.WithLabelValues(operation, result).Inc()
+}
+
+// recordEvent records an event on the Node of a Route CR
+func (r *routesProvider) recordEvent(nodeName string, nodeUID types.UID, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	ref := &v1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Node",
+		Name:       nodeName,
+		UID:        nodeUID,
+	}
+	r.recorder.Eventf(ref, eventType, reason, messageFmt, args...)
+}
+
+// repairDrift compares the Route CRs of the cluster with the pod CIDRs of the
+// Nodes. Missing Route CRs are created, Route CRs not routing their pod CIDR
+// to the Node IP of the same IP family are recreated and Route CRs of deleted
+// Nodes or of pod CIDRs the Nodes do not have anymore are deleted. The pod
+// CIDRs of a dual-stack Node are repaired independently and a Node whose pod
+// CIDRs could only partially be routed gets a warning event.
+func (r *routesProvider) repairDrift(ctx context.Context, clusterName string) error {
+	labelSelector := metav1.LabelSelector{
+		MatchLabels: map[string]string{helper.LabelKeyClusterName: clusterName},
+	}
+	list, err := r.routeManager.ListRouteCR(ctx, labelSelector)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("listing Route CRs failed: %w", err)
+	}
+	routes, err := r.routeManager.CreateRouteInfos(list)
+	if err != nil {
+		return err
+	}
+	routesByNode := map[string]map[string][]*helper.RouteInfo{}
+	for _, route := range routes {
+		nodeName := helper.NodeName(route.Name, route.Owner)
+		if routesByNode[nodeName] == nil {
+			routesByNode[nodeName] = map[string][]*helper.RouteInfo{}
+		}
+		routesByNode[nodeName][route.Name] = append(routesByNode[nodeName][route.Name], route)
+	}
+
+	nodes, err := r.nodeLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("listing nodes failed: %w", err)
+	}
+	if len(nodes) == 0 {
+		// an empty cache must not delete all Route CRs
+		return nil
+	}
+
+	var errs []error
+	for _, node := range nodes {
+		nodeRoutes := routesByNode[node.Name]
+		delete(routesByNode, node.Name)
+		cidrs := podCIDRs(node)
+		if len(cidrs) == 0 {
+			continue
+		}
+
+		var routed, unrouted []string
+		for _, cidr := range cidrs {
+			name := routeCRName(node, cidr)
+			crRoutes, ok := nodeRoutes[name]
+			delete(nodeRoutes, name)
+			nodeIP, err := r.getNodeIPAddress(node.Name, util.IsIPv4(cidr))
+			if err != nil {
+				klog.Warningf("checking Route CR %s of node %s failed: %v", name, node.Name, err)
+				unrouted = append(unrouted, cidr)
+				continue
+			}
+
+			kind := routeDriftMissing
+			if ok {
+				if routesMatch(crRoutes, cidr, nodeIP) {
+					routed = append(routed, cidr)
+					continue
+				}
+				kind = routeDriftMismatched
+				if err := r.routeManager.DeleteRouteCR(name); err != nil && !apierrors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("deleting Route CR %s failed: %w", name, err))
+					unrouted = append(unrouted, cidr)
+					continue
+				}
+			}
+			route := &cloudprovider.Route{TargetNode: types.NodeName(node.Name), DestinationCIDR: cidr}
+			if _, err := r.createRouteCR(ctx, clusterName, string(node.UID), route); err != nil {
+				errs = append(errs, fmt.Errorf("creating Route CR %s failed: %w", name, err))
+				unrouted = append(unrouted, cidr)
+				continue
+			}
+			r.driftRepaired(node.Name, node.UID, kind)
+			routed = append(routed, cidr)
+		}
+		if len(routed) > 0 && len(unrouted) > 0 {
+			klog.Warningf("pod CIDRs %v of node %s are routed, but not %v", routed, node.Name, unrouted)
+			r.recordEvent(node.Name, node.UID, v1.EventTypeWarning, RoutePartiallyProgrammedReason, "Route CRs exist for pod CIDRs %v, but not for %v", routed, unrouted)
+		}
+
+		// Route CRs of pod CIDRs the node does not have anymore
+		errs = append(errs, r.deleteStaleRouteCRs(node.Name, node.UID, nodeRoutes)...)
+	}
+
+	for nodeName, nodeRoutes := range routesByNode {
+		errs = append(errs, r.deleteStaleRouteCRs(nodeName, "", nodeRoutes)...)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// deleteStaleRouteCRs deletes the given Route CRs of a node
+func (r *routesProvider) deleteStaleRouteCRs(nodeName string, nodeUID types.UID, routes map[string][]*helper.RouteInfo) []error {
+	var errs []error
+	for name := range routes {
+		if err := r.routeManager.DeleteRouteCR(name); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("deleting Route CR %s failed: %w", name, err))
+			continue
+		}
+		r.driftRepaired(nodeName, nodeUID, routeDriftStale)
+	}
+	return errs
+}
+
+// routesMatch checks whether one of the routes routes the pod CIDR to the node
+// IP
+func routesMatch(routes []*helper.RouteInfo, cidr, nodeIP string) bool {
+	for _, route := range routes {
+		if route.Cidr == cidr && route.NodeIP == nodeIP {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *routesProvider) driftRepaired(nodeName string, nodeUID types.UID, kind string) {
+	logging.V(logging.Paravirtual, 2).Infof("Repaired %s Route CR of node %s", kind, nodeName)
+	routeDriftMetric.WithLabelValues(kind).Inc()
+	r.recordEvent(nodeName, nodeUID, v1.EventTypeWarning, RouteDriftRepairedReason, "Repaired %s Route CR of node %s", kind, nodeName)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	cloudprovider "k8s.io/cloud-provider"
+)
+
+func TestRepairDrift(t *testing.T) {
+	r, _, fc, i := initRouteTest()
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+
+	addNode := func(name, podCIDR string) {
+		node := buildFakeNode(name)
+		node.UID = types.UID(name + "-uid")
+		node.Spec.PodCIDR = podCIDR
+		_ = i.Informer().GetIndexer().Add(node)
+	}
+	addNode("fakeNode1", "100.96.0.0/24")
+	addNode("fakeNode2", "100.96.1.0/24")
+	addNode("fakeNode3", "100.96.2.0/24")
+	addNode("fakeNode4", "")
+
+	// in sync
+	_, err := createFakeRouteSetCR(fc, testClustername, testNameHint, "fakeNode1", "100.96.0.0/24", testNodeIP)
+	assert.NoError(t, err)
+	// edited target
+	_, err = createFakeRouteSetCR(fc, testClustername, testNameHint, "fakeNode2", "100.96.1.0/24", "172.50.0.99")
+	assert.NoError(t, err)
+	// deleted node
+	_, err = createFakeRouteSetCR(fc, testClustername, testNameHint, "fakeNode5", "100.96.5.0/24", testNodeIP)
+	assert.NoError(t, err)
+	// another cluster
+	_, err = createFakeRouteSetCR(fc, "another-cluster-name", testNameHint, "fakeNode6", "100.96.6.0/24", testNodeIP)
+	assert.NoError(t, err)
+
+	missing := testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftMissing))
+	mismatched := testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftMismatched))
+	stale := testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftStale))
+
+	err = r.repairDrift(context.TODO(), testClustername)
+	assert.NoError(t, err)
+
+	routeSets, err := fc.NsxV1alpha1().RouteSets(testClusterNameSpace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, routeSet := range routeSets.Items {
+		names = append(names, routeSet.Name)
+		if routeSet.Name == "fakeNode2" || routeSet.Name == "fakeNode3" {
+			assert.Equal(t, testNodeIP, routeSet.Spec.Routes[0].Target)
+			assert.Equal(t, types.UID(routeSet.Name+"-uid"), routeSet.OwnerReferences[0].UID)
+		}
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"fakeNode1", "fakeNode2", "fakeNode3", "fakeNode6"}, names)
+
+	assert.Equal(t, missing+1, testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftMissing)))
+	assert.Equal(t, mismatched+1, testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftMismatched)))
+	assert.Equal(t, stale+1, testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftStale)))
+	assert.Equal(t, 3, len(recorder.Events))
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		assert.True(t, strings.HasPrefix(event, "Warning "+RouteDriftRepairedReason), event)
+	}
+
+	// repaired
+	err = r.repairDrift(context.TODO(), testClustername)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(recorder.Events))
+}
+
+func TestRouteOperationEvents(t *testing.T) {
+	r, _, _, _ := initRouteTest()
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+
+	deleted := testutil.ToFloat64(routeOperationsMetric.WithLabelValues(routeOperationDelete, "success"))
+	route := buildFakeRouteInfo(testClustername, testNameHint, testCIDR, testNodeName, testNodeIP)
+	_, err := r.routeManager.CreateRouteCR(context.TODO(), route)
+	assert.NoError(t, err)
+
+	err = r.DeleteRoute(context.TODO(), testClustername, &cloudprovider.Route{TargetNode: testNodeName, DestinationCIDR: testCIDR})
+	assert.NoError(t, err)
+	assert.Equal(t, deleted+1, testutil.ToFloat64(routeOperationsMetric.WithLabelValues(routeOperationDelete, "success")))
+	assert.Equal(t, "Normal RouteDeleted Deleted Route CR for "+testCIDR, <-recorder.Events)
+}
+
+func TestRepairDriftDualStack(t *testing.T) {
+	r, _, fc, i := initRouteTest()
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+
+	addNode := func(name string, podCIDRs ...string) *v1.Node {
+		node := buildFakeNode(name)
+		node.UID = types.UID(name + "-uid")
+		node.Spec.PodCIDRs = podCIDRs
+		_ = i.Informer().GetIndexer().Add(node)
+		return node
+	}
+	dualStack := addNode("fakeNode1", "100.96.0.0/24", "fd00:100:96::/64")
+	addNode("fakeNode2", "100.96.1.0/24")
+	ipv4Only := addNode("fakeNode3", "100.96.2.0/24", "fd00:100:96:2::/64")
+	ipv4Only.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: testNodeIP}}
+
+	// missing IPv6 Route CR
+	_, err := createFakeRouteSetCR(fc, testClustername, testNameHint, "fakeNode1", "100.96.0.0/24", testNodeIP)
+	assert.NoError(t, err)
+	// IPv6 pod CIDR removed
+	_, err = createFakeRouteSetCR(fc, testClustername, testNameHint, "fakeNode2", "100.96.1.0/24", testNodeIP)
+	assert.NoError(t, err)
+	route := buildFakeRouteInfo(testClustername, "fakeNode2-uid", "fd00:100:96:1::/64", "fakeNode2", "fe80::20c:29ff:fe0b:b407")
+	route.Name = "fakeNode2-ipv6"
+	_, err = r.routeManager.CreateRouteCR(context.TODO(), route)
+	assert.NoError(t, err)
+
+	missing := testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftMissing))
+	stale := testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftStale))
+
+	err = r.repairDrift(context.TODO(), testClustername)
+	assert.NoError(t, err)
+
+	routeSets, err := fc.NsxV1alpha1().RouteSets(testClusterNameSpace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, routeSet := range routeSets.Items {
+		names = append(names, routeSet.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"fakeNode1", routeCRName(dualStack, "fd00:100:96::/64"), "fakeNode2", "fakeNode3"}, names)
+
+	assert.Equal(t, missing+2, testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftMissing)))
+	assert.Equal(t, stale+1, testutil.ToFloat64(routeDriftMetric.WithLabelValues(routeDriftStale)))
+	var partial []string
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		if strings.HasPrefix(event, "Warning "+RoutePartiallyProgrammedReason) {
+			partial = append(partial, event)
+		}
+	}
+	assert.Equal(t, []string{"Warning RoutePartiallyProgrammed Route CRs exist for pod CIDRs [100.96.2.0/24], but not for [fd00:100:96:2::/64]"}, partial)
+}
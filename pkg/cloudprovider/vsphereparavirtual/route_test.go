@@ -27,6 +27,7 @@ import (
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	cloudprovider "k8s.io/cloud-provider"
@@ -58,7 +59,6 @@ func initRouteTest() (*routesProvider, *util.FakeRouteSetClientWrapper, *faket1n
 	routesProvider := &routesProvider{
 		routeManager: routeManager,
 		nodeLister:   informer.Core().V1().Nodes().Lister(),
-		ownerRefs:    []metav1.OwnerReference{},
 	}
 	return routesProvider, fcw, fc, informer.Core().V1().Nodes()
 }
@@ -295,3 +295,80 @@ func TestCheckStaticRouteRealizedState(t *testing.T) {
 	err = r.routeManager.DeleteRouteCR(fakeNode2.Name)
 	assert.NoError(t, err)
 }
+
+func TestDualStackRoutes(t *testing.T) {
+	r, _, fc, i := initRouteTest()
+	node := buildFakeNode(testNodeName)
+	node.Spec.PodCIDRs = []string{testCIDR, "fd00:100:96::/64"}
+	_ = i.Informer().GetIndexer().Add(node)
+
+	for _, cidr := range node.Spec.PodCIDRs {
+		route := &cloudprovider.Route{TargetNode: types.NodeName(testNodeName), DestinationCIDR: cidr}
+		_, err := r.createRouteCR(context.TODO(), testClustername, testNameHint, route)
+		assert.NoError(t, err)
+	}
+	ipv6RouteSet, err := fc.NsxV1alpha1().RouteSets(testClusterNameSpace).Get(context.TODO(), routeCRName(node, "fd00:100:96::/64"), metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "fd00:100:96::/64", ipv6RouteSet.Spec.Routes[0].Destination)
+	assert.Equal(t, "fe80::20c:29ff:fe0b:b407", ipv6RouteSet.Spec.Routes[0].Target)
+
+	// the IPv4 Route CR is kept when the IPv6 one is deleted
+	err = r.DeleteRoute(context.TODO(), testClustername, &cloudprovider.Route{TargetNode: types.NodeName(testNodeName), DestinationCIDR: "fd00:100:96::/64"})
+	assert.NoError(t, err)
+	routeSets, err := fc.NsxV1alpha1().RouteSets(testClusterNameSpace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(routeSets.Items))
+	assert.Equal(t, testNodeName, routeSets.Items[0].Name)
+	assert.Equal(t, testCIDR, routeSets.Items[0].Spec.Routes[0].Destination)
+}
+
+func TestRouteCRNameCollisions(t *testing.T) {
+	r, _, fc, i := initRouteTest()
+	node := buildFakeNode(testNodeName)
+	node.Spec.PodCIDRs = []string{testCIDR, "fd00:100:96::/64"}
+	_ = i.Informer().GetIndexer().Add(node)
+	// a node named like the Route CR of the IPv6 pod CIDR of another node
+	suffixed := buildFakeNode(testNodeName + "-ipv6")
+	suffixed.Spec.PodCIDRs = []string{"100.96.1.0/24"}
+	_ = i.Informer().GetIndexer().Add(suffixed)
+
+	routes := []*cloudprovider.Route{
+		{TargetNode: types.NodeName(testNodeName), DestinationCIDR: testCIDR},
+		{TargetNode: types.NodeName(testNodeName), DestinationCIDR: "fd00:100:96::/64"},
+		{TargetNode: types.NodeName(suffixed.Name), DestinationCIDR: "100.96.1.0/24"},
+	}
+	for _, route := range routes {
+		_, err := r.createRouteCR(context.TODO(), testClustername, testNameHint, route)
+		assert.NoError(t, err)
+	}
+	routeSets, err := fc.NsxV1alpha1().RouteSets(testClusterNameSpace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(routeSets.Items))
+
+	// the Route CRs are looked up by their node and pod CIDR labels
+	for _, route := range routes {
+		names, err := r.routeCRNames(context.TODO(), testClustername, route)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(names))
+	}
+	err = r.DeleteRoute(context.TODO(), testClustername, routes[1])
+	assert.NoError(t, err)
+	_, err = fc.NsxV1alpha1().RouteSets(testClusterNameSpace).Get(context.TODO(), suffixed.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestCreateRouteNameTakenByAnotherNode(t *testing.T) {
+	r, _, _, i := initRouteTest()
+	node := buildFakeNode(testNodeName)
+	_ = i.Informer().GetIndexer().Add(node)
+	other := buildFakeRouteInfo(testClustername, testNameHint, "100.96.1.0/24", "fakeNode2", testNodeIP)
+	other.Name = testNodeName
+	other.Labels = helper.RouteLabels(testClustername, "fakeNode2", "100.96.1.0/24")
+	_, err := r.routeManager.CreateRouteCR(context.TODO(), other)
+	assert.NoError(t, err)
+
+	route := &cloudprovider.Route{TargetNode: types.NodeName(testNodeName), DestinationCIDR: testCIDR}
+	err = r.CreateRoute(context.TODO(), testClustername, testNameHint, route)
+	assert.Error(t, err)
+	assert.True(t, apierrors.IsAlreadyExists(err))
+}
@@ -1,6 +1,8 @@
 package helper
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 	"time"
 
@@ -19,6 +21,10 @@ var (
 const (
 	// LabelKeyClusterName is the label key to specify GC name for RouteSet/StaticRoute CR
 	LabelKeyClusterName = "clusterName"
+	// LabelKeyNodeName is the label key to specify the node of a RouteSet/StaticRoute CR
+	LabelKeyNodeName = "nodeName"
+	// LabelKeyPodCIDR is the label key to specify the pod CIDR of a RouteSet/StaticRoute CR
+	LabelKeyPodCIDR = "podCIDR"
 	// RealizedStateTimeout is the timeout duration for realized state check
 	RealizedStateTimeout = 10 * time.Second
 	// RealizedStateSleepTime is the interval between realized state check
@@ -36,7 +42,7 @@ type RouteInfo struct {
 	Namespace string
 	Labels    map[string]string
 	Owner     []metav1.OwnerReference
-	Name      string // route cr name / node name
+	Name      string // route cr name, the node name followed by a hash for secondary pod CIDRs
 	Cidr      string // destination network
 	NodeIP    string // next hop / target ip
 	RouteName string
@@ -47,3 +53,41 @@ type RouteInfo struct {
 func GetRouteName(nodeName string, cidr string, clusterName string) string {
 	return strings.Replace(nodeName+"-"+cidr+"-"+clusterName, "/", "-", -1)
 }
+
+// NodeName returns the name of the node of a Route CR, that is the name of
+// its Node owner reference. Route CRs without Node owner reference are named
+// after their node.
+func NodeName(name string, owners []metav1.OwnerReference) string {
+	for _, owner := range owners {
+		if owner.APIVersion == "v1" && owner.Kind == "Node" {
+			return owner.Name
+		}
+	}
+	return name
+}
+
+// RouteLabels returns the labels of the Route CR routing a pod CIDR of a node
+func RouteLabels(clusterName string, nodeName string, cidr string) map[string]string {
+	return map[string]string{
+		LabelKeyClusterName: clusterName,
+		LabelKeyNodeName:    LabelValue(nodeName),
+		LabelKeyPodCIDR:     LabelValue(strings.NewReplacer(":", "-", "/", "_").Replace(cidr)),
+	}
+}
+
+// LabelValue returns a label value for a value which may be longer than the
+// 63 characters allowed, by truncating it and appending a hash of the value
+func LabelValue(value string) string {
+	const maxLength = 63
+	if len(value) <= maxLength {
+		return value
+	}
+	hash := Hash(value)
+	return value[:maxLength-len(hash)-1] + "-" + hash
+}
+
+// Hash returns a short hexadecimal hash of a value
+func Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:5])
+}
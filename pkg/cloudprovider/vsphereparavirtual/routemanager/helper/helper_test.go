@@ -1,9 +1,11 @@
 package helper
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -17,3 +19,27 @@ func TestGetRouteName(t *testing.T) {
 	expectedName := testNodeName + "-100.96.0.0-24-" + testClustername
 	assert.Equal(t, name, expectedName)
 }
+
+func TestNodeName(t *testing.T) {
+	owners := []metav1.OwnerReference{
+		{APIVersion: "vmoperator.vmware.com/v1alpha1", Kind: "VirtualMachineService", Name: "cluster"},
+		{APIVersion: "v1", Kind: "Node", Name: testNodeName},
+	}
+	assert.Equal(t, testNodeName, NodeName(testNodeName+"-ipv6", owners))
+	assert.Equal(t, testNodeName, NodeName(testNodeName, nil))
+}
+
+func TestRouteLabels(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		LabelKeyClusterName: testClustername,
+		LabelKeyNodeName:    testNodeName,
+		LabelKeyPodCIDR:     "fd00-100-96--_64",
+	}, RouteLabels(testClustername, testNodeName, "fd00:100:96::/64"))
+	assert.Equal(t, "100.96.0.0_24", RouteLabels(testClustername, testNodeName, testCIDR)[LabelKeyPodCIDR])
+
+	// long node names are truncated and hashed so they are still unique
+	name := strings.Repeat("a", 70)
+	value := LabelValue(name)
+	assert.Equal(t, 63, len(value))
+	assert.NotEqual(t, value, LabelValue(name+"b"))
+}
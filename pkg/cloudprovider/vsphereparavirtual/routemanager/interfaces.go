@@ -19,6 +19,8 @@ type RouteManager interface {
 	WaitRouteCR(crName string) error
 
 	CreateCPRoutes(routes helper.RouteCRList) ([]*cloudprovider.Route, error)
+	// CreateRouteInfos returns the routes of all Route CRs, ready or not
+	CreateRouteInfos(routes helper.RouteCRList) ([]*helper.RouteInfo, error)
 }
 
 // GetRouteManager gets an RouteManager
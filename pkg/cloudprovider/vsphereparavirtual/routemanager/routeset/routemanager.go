@@ -62,8 +62,7 @@ func (rs *RouteManager) CreateCPRoutes(routeSets helper.RouteCRList) ([]*cloudpr
 		// only return cloudprovider.RouteInfo if RouteManager CR status 'Ready' is true
 		condition := GetRouteCRCondition(&(routeSet.Status), t1networkingapis.RouteSetConditionTypeReady)
 		if condition != nil && condition.Status == v1.ConditionTrue {
-			// one RouteManager per node, so we can use nodeName as the name of RouteManager CR
-			nodeName := routeSet.Name
+			nodeName := helper.NodeName(routeSet.Name, routeSet.OwnerReferences)
 			for _, route := range routeSet.Spec.Routes {
 				cpRoute := &cloudprovider.Route{
 					Name:            route.Name,
@@ -77,6 +76,30 @@ func (rs *RouteManager) CreateCPRoutes(routeSets helper.RouteCRList) ([]*cloudpr
 	return routes, nil
 }
 
+// CreateRouteInfos returns the routes of all RouteSet CRs, ready or not
+func (rs *RouteManager) CreateRouteInfos(routeSets helper.RouteCRList) ([]*helper.RouteInfo, error) {
+	routeList, ok := routeSets.(*t1networkingapis.RouteSetList)
+	if !ok {
+		return nil, fmt.Errorf("unknow route set list struct")
+	}
+
+	var routes []*helper.RouteInfo
+	for _, routeSet := range routeList.Items {
+		for _, route := range routeSet.Spec.Routes {
+			routes = append(routes, &helper.RouteInfo{
+				Namespace: routeSet.Namespace,
+				Labels:    routeSet.Labels,
+				Owner:     routeSet.OwnerReferences,
+				Name:      routeSet.Name,
+				Cidr:      route.Destination,
+				NodeIP:    route.Target,
+				RouteName: route.Name,
+			})
+		}
+	}
+	return routes, nil
+}
+
 // GetRouteCRCondition extracts the provided condition from the given RouteSetStatus and returns that.
 // Returns nil if the condition is not present.
 func GetRouteCRCondition(status *t1networkingapis.RouteSetStatus, conditionType t1networkingapis.RouteSetConditionType) *t1networkingapis.RouteSetCondition {
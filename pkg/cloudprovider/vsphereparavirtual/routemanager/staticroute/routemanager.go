@@ -62,8 +62,7 @@ func (sr *RouteManager) CreateCPRoutes(staticroutes helper.RouteCRList) ([]*clou
 		// only return cloudprovider.RouteInfo if RouteSet CR status 'Ready' is true
 		condition := GetRouteCRCondition(&(staticroute.Status), vpcapisv1.Ready)
 		if condition != nil && condition.Status == v1.ConditionTrue {
-			// one RouteSet per node, so we can use nodeName as the name of RouteSet CR
-			nodeName := staticroute.Name
+			nodeName := helper.NodeName(staticroute.Name, staticroute.OwnerReferences)
 			cpRoute := &cloudprovider.Route{
 				Name:            staticroute.Name,
 				TargetNode:      types.NodeName(nodeName),
@@ -76,6 +75,31 @@ func (sr *RouteManager) CreateCPRoutes(staticroutes helper.RouteCRList) ([]*clou
 	return routes, nil
 }
 
+// CreateRouteInfos returns the routes of all StaticRoute CRs, ready or not
+func (sr *RouteManager) CreateRouteInfos(staticroutes helper.RouteCRList) ([]*helper.RouteInfo, error) {
+	routeList, ok := staticroutes.(*vpcapisv1.StaticRouteList)
+	if !ok {
+		return nil, fmt.Errorf("unknow static route list struct")
+	}
+
+	var routes []*helper.RouteInfo
+	for _, staticroute := range routeList.Items {
+		route := &helper.RouteInfo{
+			Namespace: staticroute.Namespace,
+			Labels:    staticroute.Labels,
+			Owner:     staticroute.OwnerReferences,
+			Name:      staticroute.Name,
+			Cidr:      staticroute.Spec.Network,
+			RouteName: staticroute.Name,
+		}
+		if len(staticroute.Spec.NextHops) > 0 {
+			route.NodeIP = staticroute.Spec.NextHops[0].IPAddress
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
 // GetRouteCRCondition extracts the provided condition from the given StaticRouteStatus and returns that.
 // Returns nil if the condition is not present.
 func GetRouteCRCondition(status *vpcapisv1.StaticRouteStatus, conditionType vpcapisv1.ConditionType) *vpcapisv1.StaticRouteCondition {
@@ -0,0 +1,100 @@
+//go:build soak
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphereparavirtual
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+	"k8s.io/cloud-provider-vsphere/pkg/util/leakcheck"
+)
+
+// soakServices is the number of distinct Services cycled through.
+const soakServices = 20
+
+// TestSoak creates, updates and deletes load balancer Services for the soak
+// duration and fails if goroutines or heap grow monotonically. Run it with
+// "make soak".
+func TestSoak(t *testing.T) {
+	duration, err := leakcheck.Duration(2 * time.Minute)
+	if err != nil {
+		t.Fatalf("Invalid %s: %s", leakcheck.DurationEnv, err)
+	}
+
+	lb, fc := newTestLoadBalancer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	d := leakcheck.New()
+	d.Add("goroutines", leakcheck.Goroutines())
+	d.Add("heap_inuse_bytes", leakcheck.HeapInuse())
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx, duration/60)
+		close(done)
+	}()
+
+	for i := 0; ctx.Err() == nil; i++ {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", testK8sServiceName, i%soakServices),
+				Namespace: testK8sServiceNameSpace,
+			},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{
+					{
+						Name:     "http",
+						Protocol: v1.ProtocolTCP,
+						Port:     80,
+						NodePort: 30080,
+					},
+				},
+			},
+		}
+
+		if _, err := lb.EnsureLoadBalancer(ctx, testClustername, service, nil); err != nil && err != vmservice.ErrVMServiceIPNotFound {
+			t.Fatalf("EnsureLoadBalancer failed for %s: %s", service.Name, err)
+		}
+		service.Spec.Ports[0].Port = 8080
+		if err := lb.UpdateLoadBalancer(ctx, testClustername, service, nil); err != nil {
+			t.Fatalf("UpdateLoadBalancer failed for %s: %s", service.Name, err)
+		}
+		if err := lb.EnsureLoadBalancerDeleted(ctx, testClustername, service); err != nil {
+			t.Fatalf("EnsureLoadBalancerDeleted failed for %s: %s", service.Name, err)
+		}
+
+		// the fake client records every action, which would show up as heap growth
+		fc.ClearActions()
+	}
+	<-done
+
+	t.Log("\n" + d.Report())
+	if err := d.Check(); err != nil {
+		t.Error(err)
+	}
+}
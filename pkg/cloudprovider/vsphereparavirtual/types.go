@@ -17,7 +17,6 @@ limitations under the License.
 package vsphereparavirtual
 
 import (
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	cloudprovider "k8s.io/cloud-provider"
 	cpcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
@@ -26,12 +25,12 @@ import (
 
 // VSphereParavirtual is an implementation of cloud provider Interface for vsphere paravirtual.
 type VSphereParavirtual struct {
-	cfg            *cpcfg.Config
-	ownerReference *metav1.OwnerReference
-	client         clientset.Interface
-	informMgr      *k8s.InformerManager
-	loadBalancer   cloudprovider.LoadBalancer
-	instances      cloudprovider.Instances
-	routes         RoutesProvider
-	zones          cloudprovider.Zones
+	cfg          *cpcfg.Config
+	ownerRefs    *ownerRefCache
+	client       clientset.Interface
+	informMgr    *k8s.InformerManager
+	loadBalancer cloudprovider.LoadBalancer
+	instances    cloudprovider.Instances
+	routes       RoutesProvider
+	zones        cloudprovider.Zones
 }
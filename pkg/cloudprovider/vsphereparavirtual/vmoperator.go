@@ -6,7 +6,9 @@ import (
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cloud-provider-vsphere/pkg/util"
 
 	vmop "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmoperator"
@@ -24,13 +26,28 @@ func discoverNodeByProviderID(ctx context.Context, providerID string, namespace
 		checkError,
 		func() error {
 			uuid := GetUUIDFromProviderID(providerID)
+
+			// fast path: VirtualMachines labeled by the supervisor with their BIOS UUID
+			if errs := validation.IsValidLabelValue(uuid); len(errs) == 0 {
+				vms, err := vmClient.V1alpha2().VirtualMachines(namespace).List(ctx, metav1.ListOptions{
+					LabelSelector: labels.SelectorFromSet(labels.Set{BiosUUIDLabelKey: uuid}).String(),
+				})
+				if err != nil {
+					return err
+				}
+				if len(vms.Items) > 0 {
+					discoveredNode = &vms.Items[0]
+					return nil
+				}
+			}
+
 			vms, err := vmClient.V1alpha2().VirtualMachines(namespace).List(ctx, metav1.ListOptions{})
 			if err != nil {
 				return err
 			}
 			for i := range vms.Items {
 				vm := vms.Items[i]
-				if uuid == vm.Status.BiosUUID {
+				if uuid == getBiosUUID(&vm) {
 					discoveredNode = &vm
 					break
 				}
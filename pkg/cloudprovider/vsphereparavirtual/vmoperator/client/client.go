@@ -3,6 +3,7 @@ package client
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 
@@ -37,9 +38,11 @@ func (c *Clientset) V1alpha2() vmoperator.V1alpha2Interface {
 	return c.vmopv1
 }
 
-// VmoperatorV1alpha2Client contains the dynamic client for vm operator group
+// VmoperatorV1alpha2Client contains the dynamic client for vm operator group.
+// The objects are converted to the version served by the supervisor.
 type VmoperatorV1alpha2Client struct {
 	dynamicClient *dynamic.DynamicClient
+	versions      *versionNegotiator
 }
 
 // VirtualMachines retrieves the virtualmachine client
@@ -60,6 +63,10 @@ func (c *VmoperatorV1alpha2Client) Client() dynamic.Interface {
 	return c.dynamicClient
 }
 
+func (c *VmoperatorV1alpha2Client) negotiator() *versionNegotiator {
+	return c.versions
+}
+
 // NewForConfig creates a new client for the given config.
 func NewForConfig(c *rest.Config) (*Clientset, error) {
 	scheme := runtime.NewScheme()
@@ -69,10 +76,15 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(c)
+	if err != nil {
+		return nil, err
+	}
 
 	clientSet := &Clientset{
 		vmopv1: &VmoperatorV1alpha2Client{
 			dynamicClient: dynamicClient,
+			versions:      newVersionNegotiator(discoveryClient),
 		},
 	}
 	return clientSet, nil
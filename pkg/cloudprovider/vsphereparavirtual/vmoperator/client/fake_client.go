@@ -1,6 +1,7 @@
 package client
 
 import (
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 
@@ -28,9 +29,18 @@ func NewFakeClientSet(fakeClient *dynamicfake.FakeDynamicClient) *FakeClientSet
 	return fcw
 }
 
+// NewFakeClientSetWithDiscovery creates a FakeClientWrapper converting the
+// objects to the versions served according to the discovery client
+func NewFakeClientSetWithDiscovery(fakeClient *dynamicfake.FakeDynamicClient, discovery discovery.DiscoveryInterface) *FakeClientSet {
+	fcw := NewFakeClientSet(fakeClient)
+	fcw.FakeClient.versions = newVersionNegotiator(discovery)
+	return fcw
+}
+
 // FakeClient contains the fake dynamic client for vm operator group
 type FakeClient struct {
 	DynamicClient *dynamicfake.FakeDynamicClient
+	versions      *versionNegotiator
 }
 
 // VirtualMachines retrieves the virtualmachine client
@@ -50,3 +60,10 @@ func (c *FakeClient) Client() dynamic.Interface {
 	}
 	return c.DynamicClient
 }
+
+func (c *FakeClient) negotiator() *versionNegotiator {
+	if c == nil {
+		return nil
+	}
+	return c.versions
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmoperator"
+)
+
+// SupportedVersions are the versions of the vm-operator API the clients
+// convert the v1alpha2 objects to, the most preferred first. The
+// VirtualMachineServices and the fields of the VirtualMachines used by the
+// cloud provider have the same schema in these versions, so converting an
+// object only changes its apiVersion.
+var SupportedVersions = []string{"v1alpha4", "v1alpha3", "v1alpha2"}
+
+// versionNegotiator resolves with discovery the most preferred of
+// SupportedVersions served by the supervisor for each vm-operator resource.
+// A nil versionNegotiator always resolves v1alpha2.
+type versionNegotiator struct {
+	discovery discovery.DiscoveryInterface
+
+	lock     sync.Mutex
+	versions map[string]string
+}
+
+func newVersionNegotiator(discovery discovery.DiscoveryInterface) *versionNegotiator {
+	return &versionNegotiator{
+		discovery: discovery,
+		versions:  map[string]string{},
+	}
+}
+
+// versionedClient is implemented by the clients negotiating the versions of
+// the vm-operator resources
+type versionedClient interface {
+	negotiator() *versionNegotiator
+}
+
+func negotiatorOf(c vmoperator.V1alpha2Interface) *versionNegotiator {
+	if vc, ok := c.(versionedClient); ok {
+		return vc.negotiator()
+	}
+	return nil
+}
+
+// resource returns the v1alpha2 resource in the version served by the
+// supervisor. If the negotiation fails, v1alpha2 is returned and the
+// negotiation is tried again on the next request.
+func (n *versionNegotiator) resource(gvr schema.GroupVersionResource) schema.GroupVersionResource {
+	if n == nil {
+		return gvr
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if version, ok := n.versions[gvr.Resource]; ok {
+		gvr.Version = version
+		return gvr
+	}
+	version, err := n.negotiate(gvr)
+	if err != nil {
+		klog.Errorf("failed to negotiate the version of %s, using %s: %v", gvr.GroupResource(), gvr.Version, err)
+		return gvr
+	}
+	klog.V(2).Infof("using version %s of %s", version, gvr.GroupResource())
+	n.versions[gvr.Resource] = version
+	gvr.Version = version
+	return gvr
+}
+
+func (n *versionNegotiator) negotiate(gvr schema.GroupVersionResource) (string, error) {
+	for _, version := range SupportedVersions {
+		resources, err := n.discovery.ServerResourcesForGroupVersion(schema.GroupVersion{Group: gvr.Group, Version: version}.String())
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == gvr.Resource {
+				return version, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("none of the versions %v is served", SupportedVersions)
+}
+
+// observe forgets the version of the resource if err tells it is not served
+// anymore, for instance after an upgrade of the supervisor, so that the next
+// request negotiates it again
+func (n *versionNegotiator) observe(gvr schema.GroupVersionResource, err error) {
+	if n == nil || !apierrors.IsNotFound(err) {
+		return
+	}
+	// a missing object is reported with its name, a missing resource is not
+	if status, ok := err.(apierrors.APIStatus); ok {
+		if details := status.Status().Details; details != nil && details.Name != "" {
+			return
+		}
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.versions, gvr.Resource)
+}
+
+// toVersion converts the v1alpha2 object to the version of gvr
+func toVersion(obj *unstructured.Unstructured, gvr schema.GroupVersionResource) *unstructured.Unstructured {
+	if obj.GetAPIVersion() != "" {
+		obj.SetAPIVersion(gvr.GroupVersion().String())
+	}
+	return obj
+}
+
+// fromVersion converts the object, or the items of the list, to v1alpha2
+func fromVersion(obj map[string]interface{}, gvr schema.GroupVersionResource) map[string]interface{} {
+	apiVersion := schema.GroupVersion{Group: gvr.Group, Version: "v1alpha2"}.String()
+	if _, ok := obj["apiVersion"]; ok {
+		obj["apiVersion"] = apiVersion
+	}
+	if items, ok := obj["items"].([]interface{}); ok {
+		for _, item := range items {
+			if item, ok := item.(map[string]interface{}); ok {
+				fromVersion(item, gvr)
+			}
+		}
+	}
+	return obj
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+func servedResources(versions ...string) []*metav1.APIResourceList {
+	var lists []*metav1.APIResourceList
+	for _, version := range versions {
+		lists = append(lists, &metav1.APIResourceList{
+			GroupVersion: "vmoperator.vmware.com/" + version,
+			APIResources: []metav1.APIResource{{Name: "virtualmachineservices"}, {Name: "virtualmachines"}},
+		})
+	}
+	return lists
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		served   []string
+		expected string
+	}{
+		{name: "most preferred version", served: []string{"v1alpha2", "v1alpha3", "v1alpha4"}, expected: "v1alpha4"},
+		{name: "v1alpha3", served: []string{"v1alpha2", "v1alpha3"}, expected: "v1alpha3"},
+		{name: "v1alpha2", served: []string{"v1alpha1", "v1alpha2"}, expected: "v1alpha2"},
+		{name: "no supported version falls back to v1alpha2", served: []string{"v1alpha1", "v1"}, expected: "v1alpha2"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			discovery := &discoveryfake.FakeDiscovery{Fake: &clientgotesting.Fake{Resources: servedResources(testCase.served...)}}
+			n := newVersionNegotiator(discovery)
+			assert.Equal(t, testCase.expected, n.resource(VirtualMachineServiceGVR).Version)
+		})
+	}
+}
+
+func TestVMServiceVersionConversion(t *testing.T) {
+	v1alpha3GVR := schema.GroupVersionResource{Group: "vmoperator.vmware.com", Version: "v1alpha3", Resource: "virtualmachineservices"}
+	scheme := runtime.NewScheme()
+	_ = vmopv1.AddToScheme(scheme)
+	fc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		v1alpha3GVR: "VirtualMachineServiceList",
+	})
+	discovery := &discoveryfake.FakeDiscovery{Fake: &clientgotesting.Fake{Resources: servedResources("v1alpha2", "v1alpha3")}}
+	vms := newVirtualMachineServices(NewFakeClientSetWithDiscovery(fc, discovery).V1alpha2(), "test-ns")
+
+	_, err := vms.Create(context.Background(), &vmopv1.VirtualMachineService{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "vmoperator.vmware.com/v1alpha2", Kind: "VirtualMachineService"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-vmservice", Namespace: "test-ns"},
+		Spec:       vmopv1.VirtualMachineServiceSpec{Type: vmopv1.VirtualMachineServiceTypeLoadBalancer},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	stored, err := fc.Resource(v1alpha3GVR).Namespace("test-ns").Get(context.Background(), "test-vmservice", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "vmoperator.vmware.com/v1alpha3", stored.GetAPIVersion())
+
+	vmService, err := vms.Get(context.Background(), "test-vmservice", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "vmoperator.vmware.com/v1alpha2", vmService.APIVersion)
+	assert.Equal(t, vmopv1.VirtualMachineServiceTypeLoadBalancer, vmService.Spec.Type)
+
+	list, err := vms.List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Items, 1) {
+		assert.Equal(t, "vmoperator.vmware.com/v1alpha2", list.Items[0].APIVersion)
+	}
+
+	// a missing object keeps the negotiated version
+	_, err = vms.Get(context.Background(), "missing", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, "v1alpha3", vms.versions.versions["virtualmachineservices"])
+
+	// the supervisor stops serving v1alpha3
+	discovery.Resources = servedResources("v1alpha2")
+	fc.PrependReactor("list", "virtualmachineservices", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Version == "v1alpha3" {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{}, "")
+		}
+		return false, nil, nil
+	})
+	_, err = vms.List(context.Background(), metav1.ListOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, "v1alpha2", vms.versions.resource(VirtualMachineServiceGVR).Version)
+}
@@ -16,14 +16,16 @@ import (
 
 // virtualMachines implements VirtualMachineInterface
 type virtualMachines struct {
-	client dynamic.Interface
-	ns     string
+	client   dynamic.Interface
+	versions *versionNegotiator
+	ns       string
 }
 
 func newVirtualMachines(c vmoperator.V1alpha2Interface, namespace string) *virtualMachines {
 	return &virtualMachines{
-		client: c.Client(),
-		ns:     namespace,
+		client:   c.Client(),
+		versions: negotiatorOf(c),
+		ns:       namespace,
 	}
 }
 
@@ -33,13 +35,15 @@ func (v *virtualMachines) Create(ctx context.Context, virtualMachine *vmopv1.Vir
 		return nil, err
 	}
 
-	obj, err := v.client.Resource(VirtualMachineGVR).Namespace(v.ns).Create(ctx, &unstructured.Unstructured{Object: unstructuredObj}, opts)
+	gvr := v.versions.resource(VirtualMachineGVR)
+	obj, err := v.client.Resource(gvr).Namespace(v.ns).Create(ctx, toVersion(&unstructured.Unstructured{Object: unstructuredObj}, gvr), opts)
 	if err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
 	}
 
 	createdVirtualMachine := &vmopv1.VirtualMachine{}
-	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), createdVirtualMachine); err != nil {
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), createdVirtualMachine); err != nil {
 		return nil, err
 	}
 	return createdVirtualMachine, nil
@@ -51,27 +55,34 @@ func (v *virtualMachines) Update(ctx context.Context, virtualMachine *vmopv1.Vir
 		return nil, err
 	}
 
-	obj, err := v.client.Resource(VirtualMachineGVR).Namespace(v.ns).Update(ctx, &unstructured.Unstructured{Object: unstructuredObj}, opts)
+	gvr := v.versions.resource(VirtualMachineGVR)
+	obj, err := v.client.Resource(gvr).Namespace(v.ns).Update(ctx, toVersion(&unstructured.Unstructured{Object: unstructuredObj}, gvr), opts)
 	if err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
 	}
 
 	updatedVirtualMachine := &vmopv1.VirtualMachine{}
-	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), updatedVirtualMachine); err != nil {
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), updatedVirtualMachine); err != nil {
 		return nil, err
 	}
 	return updatedVirtualMachine, nil
 }
 
 func (v *virtualMachines) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
-	return v.client.Resource(VirtualMachineGVR).Namespace(v.ns).Delete(ctx, name, opts)
+	gvr := v.versions.resource(VirtualMachineGVR)
+	err := v.client.Resource(gvr).Namespace(v.ns).Delete(ctx, name, opts)
+	v.versions.observe(gvr, err)
+	return err
 }
 
 func (v *virtualMachines) Get(ctx context.Context, name string, opts v1.GetOptions) (*vmopv1.VirtualMachine, error) {
 	virtualMachine := &vmopv1.VirtualMachine{}
-	if obj, err := v.client.Resource(VirtualMachineGVR).Namespace(v.ns).Get(ctx, name, opts); err != nil {
+	gvr := v.versions.resource(VirtualMachineGVR)
+	if obj, err := v.client.Resource(gvr).Namespace(v.ns).Get(ctx, name, opts); err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
-	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), virtualMachine); err != nil {
+	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), virtualMachine); err != nil {
 		return nil, err
 	}
 	return virtualMachine, nil
@@ -79,9 +90,11 @@ func (v *virtualMachines) Get(ctx context.Context, name string, opts v1.GetOptio
 
 func (v *virtualMachines) List(ctx context.Context, opts v1.ListOptions) (*vmopv1.VirtualMachineList, error) {
 	virtualMachineList := &vmopv1.VirtualMachineList{}
-	if obj, err := v.client.Resource(VirtualMachineGVR).Namespace(v.ns).List(ctx, opts); err != nil {
+	gvr := v.versions.resource(VirtualMachineGVR)
+	if obj, err := v.client.Resource(gvr).Namespace(v.ns).List(ctx, opts); err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
-	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), virtualMachineList); err != nil {
+	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), virtualMachineList); err != nil {
 		return nil, err
 	}
 	return virtualMachineList, nil
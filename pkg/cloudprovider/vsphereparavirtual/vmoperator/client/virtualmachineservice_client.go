@@ -14,15 +14,17 @@ import (
 
 // virtualMachineServices implements VirtualMachineServiceInterface
 type virtualMachineServices struct {
-	client dynamic.Interface
-	ns     string
+	client   dynamic.Interface
+	versions *versionNegotiator
+	ns       string
 }
 
 // newVirtualMachineServices returns a VirtualMachineServices
 func newVirtualMachineServices(c vmoperator.V1alpha2Interface, namespace string) *virtualMachineServices {
 	return &virtualMachineServices{
-		client: c.Client(),
-		ns:     namespace,
+		client:   c.Client(),
+		versions: negotiatorOf(c),
+		ns:       namespace,
 	}
 }
 
@@ -32,13 +34,15 @@ func (v *virtualMachineServices) Create(ctx context.Context, virtualMachineServi
 		return nil, err
 	}
 
-	obj, err := v.client.Resource(VirtualMachineServiceGVR).Namespace(v.ns).Create(ctx, &unstructured.Unstructured{Object: unstructuredObj}, opts)
+	gvr := v.versions.resource(VirtualMachineServiceGVR)
+	obj, err := v.client.Resource(gvr).Namespace(v.ns).Create(ctx, toVersion(&unstructured.Unstructured{Object: unstructuredObj}, gvr), opts)
 	if err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
 	}
 
 	createdVirtualMachineService := &vmopv1.VirtualMachineService{}
-	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), createdVirtualMachineService); err != nil {
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), createdVirtualMachineService); err != nil {
 		return nil, err
 	}
 	return createdVirtualMachineService, nil
@@ -50,27 +54,34 @@ func (v *virtualMachineServices) Update(ctx context.Context, virtualMachineServi
 		return nil, err
 	}
 
-	obj, err := v.client.Resource(VirtualMachineServiceGVR).Namespace(v.ns).Update(ctx, &unstructured.Unstructured{Object: unstructuredObj}, opts)
+	gvr := v.versions.resource(VirtualMachineServiceGVR)
+	obj, err := v.client.Resource(gvr).Namespace(v.ns).Update(ctx, toVersion(&unstructured.Unstructured{Object: unstructuredObj}, gvr), opts)
 	if err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
 	}
 
 	updatedVirtualMachineService := &vmopv1.VirtualMachineService{}
-	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), updatedVirtualMachineService); err != nil {
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), updatedVirtualMachineService); err != nil {
 		return nil, err
 	}
 	return updatedVirtualMachineService, nil
 }
 
 func (v *virtualMachineServices) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
-	return v.client.Resource(VirtualMachineServiceGVR).Namespace(v.ns).Delete(ctx, name, opts)
+	gvr := v.versions.resource(VirtualMachineServiceGVR)
+	err := v.client.Resource(gvr).Namespace(v.ns).Delete(ctx, name, opts)
+	v.versions.observe(gvr, err)
+	return err
 }
 
 func (v *virtualMachineServices) Get(ctx context.Context, name string, opts v1.GetOptions) (*vmopv1.VirtualMachineService, error) {
 	virtualMachineService := &vmopv1.VirtualMachineService{}
-	if obj, err := v.client.Resource(VirtualMachineServiceGVR).Namespace(v.ns).Get(ctx, name, opts); err != nil {
+	gvr := v.versions.resource(VirtualMachineServiceGVR)
+	if obj, err := v.client.Resource(gvr).Namespace(v.ns).Get(ctx, name, opts); err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
-	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), virtualMachineService); err != nil {
+	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), virtualMachineService); err != nil {
 		return nil, err
 	}
 	return virtualMachineService, nil
@@ -78,9 +89,11 @@ func (v *virtualMachineServices) Get(ctx context.Context, name string, opts v1.G
 
 func (v *virtualMachineServices) List(ctx context.Context, opts v1.ListOptions) (*vmopv1.VirtualMachineServiceList, error) {
 	virtualMachineServiceList := &vmopv1.VirtualMachineServiceList{}
-	if obj, err := v.client.Resource(VirtualMachineServiceGVR).Namespace(v.ns).List(ctx, opts); err != nil {
+	gvr := v.versions.resource(VirtualMachineServiceGVR)
+	if obj, err := v.client.Resource(gvr).Namespace(v.ns).List(ctx, opts); err != nil {
+		v.versions.observe(gvr, err)
 		return nil, err
-	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), virtualMachineServiceList); err != nil {
+	} else if err = runtime.DefaultUnstructuredConverter.FromUnstructured(fromVersion(obj.UnstructuredContent(), gvr), virtualMachineServiceList); err != nil {
 		return nil, err
 	}
 	return virtualMachineServiceList, nil
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmservice
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+)
+
+// ensureManaged makes sure the existing VirtualMachineService belongs to the
+// Service before it is updated. A VirtualMachineService without the labels of
+// any Service was pre-created by an admin, it is adopted when the Service opts
+// in with AnnotationAdoptVMServiceKey and its spec matches the Service.
+// The owner reference of the cluster is added next to the ones already set.
+func (s *vmService) ensureManaged(ctx context.Context, service *v1.Service, clusterName string, vmService *vmopv1.VirtualMachineService) (*vmopv1.VirtualMachineService, error) {
+	logger := log.WithValues("name", service.Name, "namespace", service.Namespace)
+
+	newVMService := vmService.DeepCopy()
+	if !isManagedBy(vmService, service, clusterName) {
+		if err := validateAdoption(service, clusterName, vmService); err != nil {
+			return nil, err
+		}
+
+		logger.V(2).Info(fmt.Sprintf("Adopting VirtualMachineService %s", vmService.Name))
+		if newVMService.Labels == nil {
+			newVMService.Labels = make(map[string]string)
+		}
+		for key, value := range getVMServiceLabels(service, clusterName) {
+			newVMService.Labels[key] = value
+		}
+	}
+	if ownerRef := s.ownerReference(); ownerRef != nil && !hasOwnerReference(newVMService, ownerRef) {
+		newVMService.OwnerReferences = append(newVMService.OwnerReferences, *ownerRef)
+	}
+
+	if reflect.DeepEqual(vmService.ObjectMeta, newVMService.ObjectMeta) {
+		return vmService, nil
+	}
+
+	newVMService, err := s.vmClient.V1alpha2().VirtualMachineServices(s.namespace).Update(ctx, newVMService, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(ErrAdoptVMService, "%s: %v", vmService.Name, err)
+	}
+	return newVMService, nil
+}
+
+// validateAdoption checks that the VirtualMachineService can be adopted by
+// the Service: the Service opts in, no other Service manages it, and it load
+// balances the vms of the cluster with the role targeted by the Service on the
+// loadBalancerIP of the Service. The ports are not compared, they follow the
+// node ports of the Service once adopted.
+func validateAdoption(service *v1.Service, clusterName string, vmService *vmopv1.VirtualMachineService) error {
+	if _, ok := service.Annotations[AnnotationAdoptVMServiceKey]; !ok {
+		return errors.Wrapf(ErrVMServiceNotManaged, "%s exists, set the %s annotation to adopt it", vmService.Name, AnnotationAdoptVMServiceKey)
+	}
+	for _, key := range []string{LabelClusterNameKey, LabelServiceNameKey, LabelServiceNameSpaceKey} {
+		if value, ok := vmService.Labels[key]; ok {
+			return errors.Wrapf(ErrVMServiceNotManaged, "%s has label %s=%s of another Service", vmService.Name, key, value)
+		}
+	}
+
+	if vmService.Spec.Type != vmopv1.VirtualMachineServiceTypeLoadBalancer {
+		return errors.Wrapf(ErrAdoptVMService, "%s has type %s instead of %s", vmService.Name, vmService.Spec.Type, vmopv1.VirtualMachineServiceTypeLoadBalancer)
+	}
+	selector, err := getVMServiceSelector(service, clusterName)
+	if err != nil {
+		return errors.Wrapf(ErrAdoptVMService, "%s: %v", vmService.Name, err)
+	}
+	if !reflect.DeepEqual(vmService.Spec.Selector, selector) {
+		return errors.Wrapf(ErrAdoptVMService, "%s has selector %v instead of %v", vmService.Name, vmService.Spec.Selector, selector)
+	}
+	if vmService.Spec.LoadBalancerIP != service.Spec.LoadBalancerIP {
+		return errors.Wrapf(ErrAdoptVMService, "%s has loadBalancerIP %q instead of %q", vmService.Name, vmService.Spec.LoadBalancerIP, service.Spec.LoadBalancerIP)
+	}
+	return nil
+}
+
+// isManagedBy returns whether the VirtualMachineService has the labels of the Service
+func isManagedBy(vmService *vmopv1.VirtualMachineService, service *v1.Service, clusterName string) bool {
+	for key, value := range getVMServiceLabels(service, clusterName) {
+		if vmService.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// hasOwnerReference returns whether the object has an owner reference to the same owner
+func hasOwnerReference(vmService *vmopv1.VirtualMachineService, ownerRef *metav1.OwnerReference) bool {
+	for _, ref := range vmService.OwnerReferences {
+		if ref.UID == ownerRef.UID {
+			return true
+		}
+	}
+	return false
+}
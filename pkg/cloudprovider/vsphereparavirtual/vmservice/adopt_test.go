@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testPreCreatedVMServiceName = "pinned-lb"
+
+func createPreCreatedVMService(t *testing.T, vms VMService, mutate func(*vmopv1.VirtualMachineService)) {
+	preCreated := &vmopv1.VirtualMachineService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testPreCreatedVMServiceName,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "admin", UID: "admin-uid"},
+			},
+		},
+		Spec: vmopv1.VirtualMachineServiceSpec{
+			Type:           vmopv1.VirtualMachineServiceTypeLoadBalancer,
+			Selector:       getClusterRoleSelector(testClustername, NodeRole),
+			LoadBalancerIP: fakeLBIP,
+		},
+	}
+	if mutate != nil {
+		mutate(preCreated)
+	}
+	_, err := vms.(*vmService).vmClient.V1alpha2().VirtualMachineServices(testClusterNameSpace).Create(context.Background(), preCreated, metav1.CreateOptions{})
+	assert.NoError(t, err)
+}
+
+func TestCreateOrUpdateVMService_Adopt(t *testing.T) {
+	testK8sService, vms, _ := initTest()
+	testK8sService.Annotations = map[string]string{AnnotationAdoptVMServiceKey: testPreCreatedVMServiceName}
+	testK8sService.Spec.LoadBalancerIP = fakeLBIP
+	createPreCreatedVMService(t, vms, nil)
+
+	assert.Equal(t, testPreCreatedVMServiceName, vms.GetVMServiceName(testK8sService, testClustername))
+
+	vmService, err := vms.CreateOrUpdate(context.Background(), testK8sService, testClustername)
+	assert.Equal(t, ErrVMServiceIPNotFound, err)
+	assert.Equal(t, testPreCreatedVMServiceName, vmService.Name)
+	assert.True(t, isManagedBy(vmService, testK8sService, testClustername))
+	assert.Len(t, vmService.OwnerReferences, 2)
+	assert.True(t, hasOwnerReference(vmService, &testOwnerReference))
+	ports, _ := findPorts(testK8sService)
+	assert.Equal(t, ports, vmService.Spec.Ports)
+
+	// adopted VirtualMachineServices are deleted with their Service
+	assert.NoError(t, vms.Delete(context.Background(), testK8sService, testClustername))
+	vmService, err = vms.Get(context.Background(), testK8sService, testClustername)
+	assert.NoError(t, err)
+	assert.Nil(t, vmService)
+}
+
+func TestCreateOrUpdateVMService_AdoptKeepsAnnotations(t *testing.T) {
+	testK8sService, vms, _ := initTest()
+	testK8sService.Annotations = map[string]string{AnnotationAdoptVMServiceKey: testPreCreatedVMServiceName}
+	testK8sService.Spec.LoadBalancerIP = fakeLBIP
+	testK8sService.Spec.ExternalTrafficPolicy = v1.ServiceExternalTrafficPolicyTypeLocal
+	testK8sService.Spec.HealthCheckNodePort = 31234
+	createPreCreatedVMService(t, vms, func(vmService *vmopv1.VirtualMachineService) {
+		vmService.Annotations = map[string]string{
+			"admin.example.com/owner":        "platform",
+			AnnotationServiceTopologyModeKey: "Auto",
+		}
+	})
+
+	vmService, err := vms.CreateOrUpdate(context.Background(), testK8sService, testClustername)
+	assert.Equal(t, ErrVMServiceIPNotFound, err)
+	// the annotations of the admin are kept, the managed ones follow the Service
+	assert.Equal(t, map[string]string{
+		"admin.example.com/owner":                 "platform",
+		AnnotationServiceExternalTrafficPolicyKey: string(v1.ServiceExternalTrafficPolicyTypeLocal),
+		AnnotationServiceHealthCheckNodePortKey:   "31234",
+	}, vmService.Annotations)
+}
+
+func TestCreateOrUpdateVMService_AdoptRejected(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotate    bool
+		mutate      func(*vmopv1.VirtualMachineService)
+		expectedErr error
+	}{
+		{
+			name:        "when the Service does not opt in",
+			annotate:    false,
+			expectedErr: ErrVMServiceNotManaged,
+		},
+		{
+			name:     "when another Service manages it",
+			annotate: true,
+			mutate: func(vmService *vmopv1.VirtualMachineService) {
+				vmService.Labels = map[string]string{LabelServiceNameKey: "other"}
+			},
+			expectedErr: ErrVMServiceNotManaged,
+		},
+		{
+			name:     "when the selector differs",
+			annotate: true,
+			mutate: func(vmService *vmopv1.VirtualMachineService) {
+				vmService.Spec.Selector = getClusterRoleSelector("other-cluster", NodeRole)
+			},
+			expectedErr: ErrAdoptVMService,
+		},
+		{
+			name:     "when the loadBalancerIP differs",
+			annotate: true,
+			mutate: func(vmService *vmopv1.VirtualMachineService) {
+				vmService.Spec.LoadBalancerIP = "2.2.2.2"
+			},
+			expectedErr: ErrAdoptVMService,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testK8sService, vms, _ := initTest()
+			testK8sService.Spec.LoadBalancerIP = fakeLBIP
+			if testCase.annotate {
+				testK8sService.Annotations = map[string]string{AnnotationAdoptVMServiceKey: testPreCreatedVMServiceName}
+			} else {
+				// collide with the generated name
+				vmsName := vms.GetVMServiceName(testK8sService, testClustername)
+				testCase.mutate = func(vmService *vmopv1.VirtualMachineService) {
+					vmService.Name = vmsName
+				}
+			}
+			createPreCreatedVMService(t, vms, testCase.mutate)
+
+			_, err := vms.CreateOrUpdate(context.Background(), testK8sService, testClustername)
+			assert.True(t, errors.Is(err, testCase.expectedErr), "unexpected error %v", err)
+
+			// a VirtualMachineService that was not adopted is left on deletion
+			if testCase.annotate {
+				assert.NoError(t, vms.Delete(context.Background(), testK8sService, testClustername))
+				vmService, err := vms.Get(context.Background(), testK8sService, testClustername)
+				assert.NoError(t, err)
+				assert.NotNil(t, vmService)
+			}
+		})
+	}
+}
+
+func TestCreateOrUpdateVMService_AddsOwnerReference(t *testing.T) {
+	testK8sService, vms, _ := initTest()
+	createdVMService, err := vms.Create(context.Background(), testK8sService, testClustername)
+	assert.NoError(t, err)
+
+	createdVMService.OwnerReferences = []metav1.OwnerReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "admin", UID: "admin-uid"},
+	}
+	_, err = vms.(*vmService).vmClient.V1alpha2().VirtualMachineServices(testClusterNameSpace).Update(context.Background(), createdVMService, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	vmService, err := vms.CreateOrUpdate(context.Background(), testK8sService, testClustername)
+	assert.Equal(t, ErrVMServiceIPNotFound, err)
+	assert.Len(t, vmService.OwnerReferences, 2)
+	assert.True(t, hasOwnerReference(vmService, &testOwnerReference))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmservice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+
+	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
+)
+
+// MaxPortNameLen is the maximum length of the port names of a
+// VirtualMachineService, the length of an IANA service name, so that the names
+// are valid in every object of the supervisor cluster they are copied to.
+const MaxPortNameLen = 15
+
+// ErrDuplicatePort is returned when a Service has several ports with the same
+// port number and protocol, which the supervisor cannot load balance.
+var ErrDuplicatePort = errors.New("duplicate port")
+
+// findPorts returns the ports of the VirtualMachineService of service. The
+// port names are normalized to lowercase alphanumerics and hyphens of at most
+// MaxPortNameLen characters, unnamed ports of a multi-port Service are named
+// after their protocol and port, and names made equal by the normalization
+// are told apart by a numeric suffix. An explicit error is returned instead
+// of letting the supervisor reject the VirtualMachineService.
+func findPorts(service *v1.Service) ([]vmopv1.VirtualMachineServicePort, error) {
+	var ports []vmopv1.VirtualMachineServicePort
+	seen := make(map[string]string)
+	names := make(map[string]bool)
+	for _, port := range service.Spec.Ports {
+		if port.NodePort == 0 {
+			return nil, errors.Wrapf(ErrNodePortNotFound, fmt.Sprintf("port %s", port.Name))
+		}
+		protocol := strings.ToUpper(string(port.Protocol))
+		if protocol == "" {
+			protocol = string(v1.ProtocolTCP)
+		}
+		key := protocol + "/" + strconv.Itoa(int(port.Port))
+		if other, ok := seen[key]; ok {
+			return nil, errors.Wrapf(ErrDuplicatePort, "ports %q and %q are both %s", other, port.Name, key)
+		}
+		seen[key] = port.Name
+
+		name := port.Name
+		if name != "" || len(service.Spec.Ports) > 1 {
+			name = uniquePortName(normalizePortName(port), names)
+			if name != port.Name {
+				log.V(2).Info("Normalized the port name of the VirtualMachineService",
+					"name", service.Name, "namespace", service.Namespace, "port", port.Name, "normalized", name)
+			}
+		}
+		ports = append(ports, vmopv1.VirtualMachineServicePort{
+			Name:       name,
+			Port:       port.Port,
+			TargetPort: port.NodePort,
+			Protocol:   string(port.Protocol),
+		})
+	}
+	return ports, nil
+}
+
+// normalizePortName returns the name of port in lowercase alphanumerics and
+// single hyphens, truncated to MaxPortNameLen. A port without a name, or whose
+// name has no letter, is named after its protocol and port number.
+func normalizePortName(port v1.ServicePort) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(port.Name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	name := truncatePortName(strings.TrimLeft(b.String(), "-"), MaxPortNameLen)
+	if strings.IndexFunc(name, func(r rune) bool { return r >= 'a' && r <= 'z' }) < 0 {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		name = strings.ToLower(string(protocol)) + "-" + strconv.Itoa(int(port.Port))
+	}
+	return name
+}
+
+// uniquePortName returns name, suffixed with the lowest number making it
+// unique among names if it is already taken, and adds it to names.
+func uniquePortName(name string, names map[string]bool) string {
+	unique := name
+	for i := 2; names[unique]; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		unique = truncatePortName(name, MaxPortNameLen-len(suffix)) + suffix
+	}
+	names[unique] = true
+	return unique
+}
+
+// truncatePortName truncates name to n characters, without a trailing hyphen.
+func truncatePortName(name string, n int) string {
+	if len(name) > n {
+		name = name[:n]
+	}
+	return strings.TrimRight(name, "-")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmservice
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestFindPorts_Names(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ports    []v1.ServicePort
+		expected []string
+	}{
+		{
+			name:     "valid names are kept",
+			ports:    []v1.ServicePort{{Name: "http", Port: 80}, {Name: "https", Port: 443}},
+			expected: []string{"http", "https"},
+		},
+		{
+			name:     "unnamed port of a single port service",
+			ports:    []v1.ServicePort{{Port: 80}},
+			expected: []string{""},
+		},
+		{
+			name:     "unnamed ports of a multi-port service",
+			ports:    []v1.ServicePort{{Port: 80}, {Port: 53, Protocol: v1.ProtocolUDP}},
+			expected: []string{"tcp-80", "udp-53"},
+		},
+		{
+			name:     "invalid characters",
+			ports:    []v1.ServicePort{{Name: "Web_UI..Port-", Port: 80}},
+			expected: []string{"web-ui-port"},
+		},
+		{
+			name:     "long name",
+			ports:    []v1.ServicePort{{Name: "prometheus-metrics-endpoint", Port: 9090}},
+			expected: []string{"prometheus-metr"},
+		},
+		{
+			name:     "name without letters",
+			ports:    []v1.ServicePort{{Name: "8080", Port: 8080}},
+			expected: []string{"tcp-8080"},
+		},
+		{
+			name: "names made equal by the normalization",
+			ports: []v1.ServicePort{
+				{Name: "prometheus-metrics-a", Port: 9090},
+				{Name: "prometheus-metrics-b", Port: 9091},
+				{Name: "prometheus_metr", Port: 9092},
+			},
+			expected: []string{"prometheus-metr", "prometheus-me-2", "prometheus-me-3"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := &v1.Service{Spec: v1.ServiceSpec{Ports: testCase.ports}}
+			for i := range service.Spec.Ports {
+				service.Spec.Ports[i].NodePort = 30000 + int32(i)
+			}
+			ports, err := findPorts(service)
+			assert.NoError(t, err)
+			var names []string
+			for _, port := range ports {
+				assert.LessOrEqual(t, len(port.Name), MaxPortNameLen)
+				names = append(names, port.Name)
+			}
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestFindPorts_DuplicatePort(t *testing.T) {
+	service := &v1.Service{Spec: v1.ServiceSpec{Ports: []v1.ServicePort{
+		{Name: "http", Port: 80, NodePort: 30080},
+		{Name: "http-alt", Port: 80, Protocol: v1.ProtocolTCP, NodePort: 30081},
+	}}}
+	_, err := findPorts(service)
+	assert.True(t, errors.Is(err, ErrDuplicatePort), "unexpected error %v", err)
+
+	// the same port number of another protocol is not a duplicate
+	service.Spec.Ports[1].Protocol = v1.ProtocolUDP
+	_, err = findPorts(service)
+	assert.NoError(t, err)
+}
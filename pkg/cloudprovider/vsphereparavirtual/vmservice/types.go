@@ -21,13 +21,13 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2/klogr"
 
 	vmopv1 "github.com/vmware-tanzu/vm-operator/api/v1alpha2"
 	vmop "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmoperator"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
-var log = klogr.New().WithName("vmservice")
+var log = logging.Logger(logging.Paravirtual).WithName("vmservice")
 
 // VMService is an interface for VirtualMachineService operations
 type VMService interface {
@@ -43,5 +43,5 @@ type VMService interface {
 type vmService struct {
 	vmClient       vmop.Interface
 	namespace      string
-	ownerReference *metav1.OwnerReference
+	ownerReference func() *metav1.OwnerReference
 }
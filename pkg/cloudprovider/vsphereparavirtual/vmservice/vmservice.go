@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
@@ -48,6 +49,8 @@ const (
 
 	// NodeRole is set by capw, we are targeting worker vms
 	NodeRole = "node"
+	// ControlPlaneRole is set by capw on the control plane vms
+	ControlPlaneRole = "controlplane"
 
 	// LabelClusterNameKey label should be added on virtual machine service with its corresponding k8s service
 	LabelClusterNameKey = "run.tanzu.vmware.com/cluster.name"
@@ -64,6 +67,24 @@ const (
 	// AnnotationServiceHealthCheckNodePortKey label is used to piggyback vSphere Paravirtual Service's
 	// configuration to the supervisor cluster.
 	AnnotationServiceHealthCheckNodePortKey = "virtualmachineservice.vmoperator.vmware.com/service.healthCheckNodePort"
+	// AnnotationServiceTopologyModeKey is used to piggyback vSphere Paravirtual Service's topology
+	// aware routing mode to the supervisor cluster, so that a multi-zone supervisor can prefer
+	// zone-local backends when placing the load balancer.
+	AnnotationServiceTopologyModeKey = "virtualmachineservice.vmoperator.vmware.com/service.topologyMode"
+	// AnnotationServiceTrafficDistributionKey is used to piggyback vSphere Paravirtual Service's
+	// spec.trafficDistribution to the supervisor cluster.
+	AnnotationServiceTrafficDistributionKey = "virtualmachineservice.vmoperator.vmware.com/service.trafficDistribution"
+
+	// AnnotationAdoptVMServiceKey is set on a Service to adopt the pre-created
+	// VirtualMachineService named by its value, for instance to pin the IP of
+	// the load balancer, instead of creating one.
+	AnnotationAdoptVMServiceKey = "loadbalancer.vmware.io/adopt-virtualmachineservice"
+
+	// AnnotationVMServiceClusterRoleKey is set on a Service to load balance
+	// the vms of the cluster with the role of its value, for instance the vms
+	// of an ingress or gpu node pool, instead of the worker vms. The role
+	// must be one of AllowedClusterRoles.
+	AnnotationVMServiceClusterRoleKey = "loadbalancer.vmware.io/cluster-role"
 
 	// MaxCheckSumLen is the maximum length of vmservice suffix: vsphere paravirtual name length cannot exceed 41 bytes in total, so we need to make sure vmservice suffix is 21 bytes (63 - 41 -1 = 21)
 	// https://gitlab.eng.vmware.com/core-build/guest-cluster-controller/blob/master/webhooks/validation/tanzukubernetescluster_validator.go#L56
@@ -72,18 +93,25 @@ const (
 
 // A list of possible error messages
 var (
-	ErrCreateVMService     = errors.New("failed to create VirtualMachineService")
-	ErrUpdateVMService     = errors.New("failed to update VirtualMachineService")
-	ErrGetVMService        = errors.New("failed to get VirtualMachineService")
-	ErrDeleteVMService     = errors.New("failed to delete VirtualMachineService")
-	ErrVMServiceIPNotFound = errors.New("VirtualMachineService IP not found")
-	ErrNodePortNotFound    = errors.New("NodePort not found")
+	ErrCreateVMService       = errors.New("failed to create VirtualMachineService")
+	ErrUpdateVMService       = errors.New("failed to update VirtualMachineService")
+	ErrGetVMService          = errors.New("failed to get VirtualMachineService")
+	ErrDeleteVMService       = errors.New("failed to delete VirtualMachineService")
+	ErrVMServiceIPNotFound   = errors.New("VirtualMachineService IP not found")
+	ErrNodePortNotFound      = errors.New("NodePort not found")
+	ErrVMServiceNotManaged   = errors.New("VirtualMachineService is not managed by the Service")
+	ErrAdoptVMService        = errors.New("failed to adopt VirtualMachineService")
+	ErrClusterRoleNotAllowed = errors.New("cluster role not allowed")
 )
 
 var (
 	// IsLegacy indicates whether legacy paravirtual mode is enabled
 	// Default to false
 	IsLegacy bool
+
+	// AllowedClusterRoles is the comma separated list of the cluster roles
+	// a Service can target with AnnotationVMServiceClusterRoleKey
+	AllowedClusterRoles = NodeRole + "," + ControlPlaneRole
 )
 
 // GetVmopClient gets a vm-operator-api client
@@ -94,6 +122,13 @@ func GetVmopClient(config *rest.Config) (vmop.Interface, error) {
 
 // NewVMService creates a vmService object
 func NewVMService(vmClient vmop.Interface, ns string, ownerRef *metav1.OwnerReference) VMService {
+	return NewVMServiceWithOwnerReference(vmClient, ns, func() *metav1.OwnerReference { return ownerRef })
+}
+
+// NewVMServiceWithOwnerReference creates a vmService object setting the owner
+// reference returned by the given function on the VirtualMachineServices, so
+// that a refreshed owner reference is used
+func NewVMServiceWithOwnerReference(vmClient vmop.Interface, ns string, ownerRef func() *metav1.OwnerReference) VMService {
 	return &vmService{
 		vmClient:       vmClient,
 		namespace:      ns,
@@ -111,8 +146,13 @@ func (s *vmService) hashString(str string) string {
 	return hex.EncodeToString(hash.Sum(nil))
 }
 
-// GetVMServiceName returns VirtualMachineService name for a lb type of service
+// GetVMServiceName returns VirtualMachineService name for a lb type of service,
+// or the name of the VirtualMachineService it adopts
 func (s *vmService) GetVMServiceName(service *v1.Service, clusterName string) string {
+	if name := service.Annotations[AnnotationAdoptVMServiceKey]; name != "" {
+		return name
+	}
+
 	suffix := s.hashString(service.Name + "." + service.Namespace)
 	logger := log.WithValues("name", service.Name, "namespace", service.Namespace)
 	logger.V(6).Info(fmt.Sprintf("Hash string for VirtualMachinService Name is %s", suffix))
@@ -186,6 +226,13 @@ func (s *vmService) CreateOrUpdate(ctx context.Context, service *v1.Service, clu
 			return nil, err
 		}
 	} else {
+		// Make sure the existing VirtualMachineService is ours before taking it over
+		vmService, err = s.ensureManaged(ctx, service, clusterName, vmService)
+		if err != nil {
+			logger.Error(ErrUpdateVMService, fmt.Sprintf("%v", err))
+			return nil, err
+		}
+
 		// Update the existing VirtualMachineService
 		vmService, err = s.Update(ctx, service, clusterName, vmService)
 		if err != nil {
@@ -226,7 +273,13 @@ func (s *vmService) Update(ctx context.Context, service *v1.Service, clusterName
 		service.Spec.LoadBalancerSourceRanges = []string{}
 	}
 
-	annotations := getVMServiceAnnotations(vmService, service)
+	selector, err := getVMServiceSelector(service, clusterName)
+	if err != nil {
+		logger.Error(ErrUpdateVMService, fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
+	annotations, annotationsChanged := mergeManagedAnnotations(vmService.Annotations, getVMServiceAnnotations(vmService, service))
 
 	// VMService only has a few fields to be kept in sync so we will simply
 	// iterate over them
@@ -244,7 +297,11 @@ func (s *vmService) Update(ctx context.Context, service *v1.Service, clusterName
 		needsUpdate = true
 		newVMService.Spec.LoadBalancerSourceRanges = service.Spec.LoadBalancerSourceRanges
 	}
-	if !reflect.DeepEqual(vmService.Annotations, annotations) {
+	if !reflect.DeepEqual(vmService.Spec.Selector, selector) {
+		needsUpdate = true
+		newVMService.Spec.Selector = selector
+	}
+	if annotationsChanged {
 		needsUpdate = true
 		newVMService.Annotations = annotations
 	}
@@ -268,6 +325,19 @@ func (s *vmService) Delete(ctx context.Context, service *v1.Service, clusterName
 	logger := log.WithValues("name", service.Name, "namespace", service.Namespace)
 	logger.V(2).Info("Attempting to delete VirtualMachineService")
 
+	// Leave a pre-created VirtualMachineService that could not be adopted
+	if _, ok := service.Annotations[AnnotationAdoptVMServiceKey]; ok {
+		vmService, err := s.Get(ctx, service, clusterName)
+		if err != nil {
+			logger.Error(ErrDeleteVMService, fmt.Sprintf("%v", err))
+			return err
+		}
+		if vmService != nil && !isManagedBy(vmService, service, clusterName) {
+			logger.V(2).Info("Skipping deletion of VirtualMachineService not managed by the Service")
+			return nil
+		}
+	}
+
 	err := s.vmClient.V1alpha2().VirtualMachineServices(s.namespace).Delete(ctx, s.GetVMServiceName(service, clusterName), metav1.DeleteOptions{})
 	if err != nil {
 		logger.Error(ErrDeleteVMService, fmt.Sprintf("%v", err))
@@ -278,34 +348,19 @@ func (s *vmService) Delete(ctx context.Context, service *v1.Service, clusterName
 	return nil
 }
 
-func findPorts(service *v1.Service) ([]vmopv1.VirtualMachineServicePort, error) {
-	var ports []vmopv1.VirtualMachineServicePort
-	for _, port := range service.Spec.Ports {
-		if port.NodePort == 0 {
-			return nil, errors.Wrapf(ErrNodePortNotFound, fmt.Sprintf("port %s", port.Name))
-		}
-		ports = append(ports, vmopv1.VirtualMachineServicePort{
-			Name:       port.Name,
-			Port:       port.Port,
-			TargetPort: port.NodePort,
-			Protocol:   string(port.Protocol),
-		})
-	}
-	return ports, nil
-}
-
 func (s *vmService) lbServiceToVMService(service *v1.Service, clusterName string) (*vmopv1.VirtualMachineService, error) {
 	ports, err := findPorts(service)
 	if err != nil {
 		return nil, err
 	}
+	selector, err := getVMServiceSelector(service, clusterName)
+	if err != nil {
+		return nil, err
+	}
 	vmServiceSpec := vmopv1.VirtualMachineServiceSpec{
-		Type:  vmopv1.VirtualMachineServiceTypeLoadBalancer,
-		Ports: ports,
-		Selector: map[string]string{
-			ClusterSelectorKey: clusterName,
-			NodeSelectorKey:    NodeRole,
-		},
+		Type:     vmopv1.VirtualMachineServiceTypeLoadBalancer,
+		Ports:    ports,
+		Selector: selector,
 		// When service has spec.loadBalancerIP specified, pass it to the
 		// corresponding VirtualMachineService
 		LoadBalancerIP: service.Spec.LoadBalancerIP,
@@ -314,18 +369,7 @@ func (s *vmService) lbServiceToVMService(service *v1.Service, clusterName string
 		LoadBalancerSourceRanges: service.Spec.LoadBalancerSourceRanges,
 	}
 
-	if IsLegacy {
-		vmServiceSpec.Selector = map[string]string{
-			LegacyClusterSelectorKey: clusterName,
-			LegacyNodeSelectorKey:    NodeRole,
-		}
-	}
-
-	label := map[string]string{
-		LabelClusterNameKey:      clusterName,
-		LabelServiceNameKey:      service.Name,
-		LabelServiceNameSpaceKey: service.Namespace,
-	}
+	label := getVMServiceLabels(service, clusterName)
 
 	vmService := &vmopv1.VirtualMachineService{
 		TypeMeta: metav1.TypeMeta{
@@ -336,7 +380,7 @@ func (s *vmService) lbServiceToVMService(service *v1.Service, clusterName string
 			Labels: label,
 			Name:   s.GetVMServiceName(service, clusterName),
 			OwnerReferences: []metav1.OwnerReference{
-				*s.ownerReference,
+				*s.ownerReference(),
 			},
 		},
 		Spec: vmServiceSpec,
@@ -349,6 +393,72 @@ func (s *vmService) lbServiceToVMService(service *v1.Service, clusterName string
 	return vmService, nil
 }
 
+// getVMServiceSelector returns the selector of the vms of the cluster with
+// the role targeted by the Service, the worker nodes by default
+func getVMServiceSelector(service *v1.Service, clusterName string) (map[string]string, error) {
+	role, err := getClusterRole(service)
+	if err != nil {
+		return nil, err
+	}
+	return getClusterRoleSelector(clusterName, role), nil
+}
+
+// getClusterRoleSelector returns the selector of the vms of the cluster with the role
+func getClusterRoleSelector(clusterName string, role string) map[string]string {
+	if IsLegacy {
+		return map[string]string{
+			LegacyClusterSelectorKey: clusterName,
+			LegacyNodeSelectorKey:    role,
+		}
+	}
+	return map[string]string{
+		ClusterSelectorKey: clusterName,
+		NodeSelectorKey:    role,
+	}
+}
+
+// ConvertLegacySelector returns the selector equivalent to a selector of the
+// legacy paravirtual mode, with the capw.vmware.com keys replaced by their
+// capv.vmware.com counterparts. The other keys are kept.
+func ConvertLegacySelector(selector map[string]string) map[string]string {
+	converted := make(map[string]string, len(selector))
+	for key, value := range selector {
+		switch key {
+		case LegacyClusterSelectorKey:
+			key = ClusterSelectorKey
+		case LegacyNodeSelectorKey:
+			key = NodeSelectorKey
+		}
+		converted[key] = value
+	}
+	return converted
+}
+
+// getClusterRole returns the cluster role set on the Service with
+// AnnotationVMServiceClusterRoleKey, or NodeRole if it is not set. The role
+// must be one of AllowedClusterRoles.
+func getClusterRole(service *v1.Service) (string, error) {
+	role, ok := service.Annotations[AnnotationVMServiceClusterRoleKey]
+	if !ok {
+		return NodeRole, nil
+	}
+	for _, allowed := range strings.Split(AllowedClusterRoles, ",") {
+		if role != "" && role == strings.TrimSpace(allowed) {
+			return role, nil
+		}
+	}
+	return "", errors.Wrapf(ErrClusterRoleNotAllowed, "%s=%q, allowed roles are %s", AnnotationVMServiceClusterRoleKey, role, AllowedClusterRoles)
+}
+
+// getVMServiceLabels returns the labels tying a VirtualMachineService to its Service
+func getVMServiceLabels(service *v1.Service, clusterName string) map[string]string {
+	return map[string]string{
+		LabelClusterNameKey:      clusterName,
+		LabelServiceNameKey:      service.Name,
+		LabelServiceNameSpaceKey: service.Namespace,
+	}
+}
+
 func getVMServiceAnnotations(vmService *vmopv1.VirtualMachineService, service *v1.Service) map[string]string {
 	var annotations map[string]string
 	// When ExternalTrafficPolicy is set to Local in the Service, add its
@@ -362,9 +472,76 @@ func getVMServiceAnnotations(vmService *vmopv1.VirtualMachineService, service *v
 		annotations[AnnotationServiceExternalTrafficPolicyKey] = string(service.Spec.ExternalTrafficPolicy)
 		annotations[AnnotationServiceHealthCheckNodePortKey] = strconv.Itoa(int(service.Spec.HealthCheckNodePort))
 	}
+
+	// Pass the zone placement hints of the Service on, so that the
+	// supervisor LB can pick zone-local backends where possible
+	for key, value := range getTopologyHints(service) {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[key] = value
+	}
 	return annotations
 }
 
+// managedAnnotations are the annotations of the VirtualMachineServices kept in
+// sync with their Service. The other annotations, such as those of an adopted
+// VirtualMachineService, are left alone.
+var managedAnnotations = []string{
+	AnnotationServiceExternalTrafficPolicyKey,
+	AnnotationServiceHealthCheckNodePortKey,
+	AnnotationServiceTopologyModeKey,
+	AnnotationServiceTrafficDistributionKey,
+}
+
+// mergeManagedAnnotations returns the current annotations with the managed
+// annotations replaced by the desired ones, and whether they changed.
+func mergeManagedAnnotations(current, desired map[string]string) (map[string]string, bool) {
+	merged := make(map[string]string, len(current)+len(desired))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for _, key := range managedAnnotations {
+		delete(merged, key)
+	}
+	for key, value := range desired {
+		merged[key] = value
+	}
+	changed := len(merged) != len(current)
+	for key, value := range merged {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			changed = true
+		}
+	}
+	if !changed {
+		return current, false
+	}
+	if len(merged) == 0 {
+		return nil, true
+	}
+	return merged, true
+}
+
+// getTopologyHints returns the VirtualMachineService annotations derived from
+// the topology aware routing settings of the Service. The deprecated
+// topology-aware-hints annotation is only honored when topology-mode is not set.
+func getTopologyHints(service *v1.Service) map[string]string {
+	hints := make(map[string]string)
+
+	topologyMode, ok := service.Annotations[v1.AnnotationTopologyMode]
+	if !ok {
+		topologyMode = service.Annotations[v1.DeprecatedAnnotationTopologyAwareHints]
+	}
+	if topologyMode != "" {
+		hints[AnnotationServiceTopologyModeKey] = topologyMode
+	}
+
+	if service.Spec.TrafficDistribution != nil && *service.Spec.TrafficDistribution != "" {
+		hints[AnnotationServiceTrafficDistributionKey] = *service.Spec.TrafficDistribution
+	}
+	return hints
+}
+
 func getVMServiceIP(vmService *vmopv1.VirtualMachineService) string {
 	if len(vmService.Status.LoadBalancer.Ingress) > 0 {
 		return vmService.Status.LoadBalancer.Ingress[0].IP
@@ -195,6 +195,93 @@ func TestCreateVMServiceWithLegacySelector(t *testing.T) {
 	IsLegacy = false
 }
 
+func TestConvertLegacySelector(t *testing.T) {
+	IsLegacy = true
+	legacy := getClusterRoleSelector(testClustername, ControlPlaneRole)
+	IsLegacy = false
+	legacy["example.com/pool"] = "pool-a"
+
+	expected := getClusterRoleSelector(testClustername, ControlPlaneRole)
+	expected["example.com/pool"] = "pool-a"
+	assert.Equal(t, expected, ConvertLegacySelector(legacy))
+	assert.Equal(t, expected, ConvertLegacySelector(expected))
+}
+
+func TestCreateVMService_ClusterRole(t *testing.T) {
+	testCases := []struct {
+		name             string
+		role             string
+		allowedRoles     string
+		expectedSelector map[string]string
+		expectedErr      error
+	}{
+		{
+			name:         "when the role is controlplane",
+			role:         ControlPlaneRole,
+			allowedRoles: AllowedClusterRoles,
+			expectedSelector: map[string]string{
+				ClusterSelectorKey: testClustername,
+				NodeSelectorKey:    ControlPlaneRole,
+			},
+		},
+		{
+			name:         "when the role of a node pool is allowed",
+			role:         "ingress",
+			allowedRoles: "node, ingress",
+			expectedSelector: map[string]string{
+				ClusterSelectorKey: testClustername,
+				NodeSelectorKey:    "ingress",
+			},
+		},
+		{
+			name:         "when the role is not allowed",
+			role:         "gpu",
+			allowedRoles: AllowedClusterRoles,
+			expectedErr:  ErrClusterRoleNotAllowed,
+		},
+		{
+			name:         "when the role is empty",
+			role:         "",
+			allowedRoles: AllowedClusterRoles,
+			expectedErr:  ErrClusterRoleNotAllowed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			defaultRoles := AllowedClusterRoles
+			AllowedClusterRoles = testCase.allowedRoles
+			defer func() { AllowedClusterRoles = defaultRoles }()
+
+			testK8sService, vms, _ := initTest()
+			testK8sService.Annotations = map[string]string{AnnotationVMServiceClusterRoleKey: testCase.role}
+
+			vmServiceObj, err := vms.Create(context.Background(), testK8sService, testClustername)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedSelector, vmServiceObj.Spec.Selector)
+		})
+	}
+}
+
+func TestUpdateVMService_ClusterRoleChanges(t *testing.T) {
+	testK8sService, vms, _ := initTest()
+	createdVMService, err := vms.Create(context.Background(), testK8sService, testClustername)
+	assert.NoError(t, err)
+
+	testK8sService.Annotations = map[string]string{AnnotationVMServiceClusterRoleKey: ControlPlaneRole}
+	updatedVMService, err := vms.Update(context.Background(), testK8sService, testClustername, createdVMService)
+	assert.NoError(t, err)
+	assert.Equal(t, ControlPlaneRole, updatedVMService.Spec.Selector[NodeSelectorKey])
+
+	testK8sService.Annotations = map[string]string{AnnotationVMServiceClusterRoleKey: "gpu"}
+	_, err = vms.Update(context.Background(), testK8sService, testClustername, updatedVMService)
+	assert.ErrorIs(t, err, ErrClusterRoleNotAllowed)
+}
+
 func TestCreateVMService_ZeroNodeport(t *testing.T) {
 	_, vms, _ := initTest()
 	k8sService := &v1.Service{
@@ -358,6 +445,80 @@ func TestCreateVMService_ExternalTrafficPolicyTypeCluster(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateVMService_TopologyHints(t *testing.T) {
+	preferClose := v1.ServiceTrafficDistributionPreferClose
+	testCases := []struct {
+		name                string
+		annotations         map[string]string
+		trafficDistribution *string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:                "no topology hints",
+			expectedAnnotations: nil,
+		},
+		{
+			name:        "topology mode",
+			annotations: map[string]string{v1.AnnotationTopologyMode: "Auto"},
+			expectedAnnotations: map[string]string{
+				AnnotationServiceTopologyModeKey: "Auto",
+			},
+		},
+		{
+			name:        "deprecated topology aware hints",
+			annotations: map[string]string{v1.DeprecatedAnnotationTopologyAwareHints: "auto"},
+			expectedAnnotations: map[string]string{
+				AnnotationServiceTopologyModeKey: "auto",
+			},
+		},
+		{
+			name: "topology mode takes precedence over deprecated annotation",
+			annotations: map[string]string{
+				v1.AnnotationTopologyMode:                 "Disabled",
+				v1.DeprecatedAnnotationTopologyAwareHints: "auto",
+			},
+			expectedAnnotations: map[string]string{
+				AnnotationServiceTopologyModeKey: "Disabled",
+			},
+		},
+		{
+			name:                "traffic distribution",
+			trafficDistribution: &preferClose,
+			expectedAnnotations: map[string]string{
+				AnnotationServiceTrafficDistributionKey: v1.ServiceTrafficDistributionPreferClose,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testK8sService, vms, _ := initTest()
+			testK8sService.Annotations = testCase.annotations
+			testK8sService.Spec.TrafficDistribution = testCase.trafficDistribution
+
+			vmServiceObj, err := vms.Create(context.Background(), testK8sService, testClustername)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedAnnotations, vmServiceObj.Annotations)
+
+			err = vms.Delete(context.Background(), testK8sService, testClustername)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdateVMService_TopologyModeAdded(t *testing.T) {
+	testK8sService, vms, _ := initTest()
+	createdVMService, _ := vms.Create(context.Background(), testK8sService, testClustername)
+
+	testK8sService.Annotations = map[string]string{v1.AnnotationTopologyMode: "Auto"}
+	vmServiceObj, err := vms.Update(context.Background(), testK8sService, testClustername, createdVMService)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{AnnotationServiceTopologyModeKey: "Auto"}, vmServiceObj.Annotations)
+
+	err = vms.Delete(context.Background(), testK8sService, testClustername)
+	assert.NoError(t, err)
+}
+
 func TestCreateOrUpdateVMService(t *testing.T) {
 	testK8sService, vms, _ := initTest()
 	testCases := []struct {
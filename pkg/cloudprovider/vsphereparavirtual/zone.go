@@ -7,14 +7,18 @@ import (
 	vmop "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmoperator"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual/vmservice"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 	"k8s.io/klog/v2"
 )
 
 type zones struct {
 	vmClient  vmop.Interface
+	podClient corev1client.PodsGetter
 	namespace string
 }
 
@@ -33,12 +37,20 @@ func (z zones) GetZoneByProviderID(ctx context.Context, providerID string) (clou
 	}
 
 	if vm == nil {
-		klog.V(4).Info("instances.GetZoneByProviderID() InstanceNotFound ", providerID)
+		pod, err := discoverPodVMByProviderID(ctx, providerID, z.namespace, z.podClient)
+		if err != nil {
+			klog.Errorf("Error trying to find vSphere Pod: %v", err)
+			return zone, err
+		}
+		if pod != nil {
+			return cloudprovider.Zone{FailureDomain: pod.Labels[zoneLabelKey]}, nil
+		}
+		logging.V(logging.Paravirtual, 4).Info("instances.GetZoneByProviderID() InstanceNotFound ", providerID)
 		return zone, cloudprovider.InstanceNotFound
 	}
 
-	if val, ok := vm.Labels["topology.kubernetes.io/zone"]; ok {
-		klog.V(4).Info("retrieved zone", val)
+	if val, ok := vm.Labels[zoneLabelKey]; ok {
+		logging.V(logging.Paravirtual, 4).Info("retrieved zone", val)
 		zone = cloudprovider.Zone{
 			FailureDomain: val,
 		}
@@ -57,12 +69,20 @@ func (z zones) GetZoneByNodeName(ctx context.Context, nodeName types.NodeName) (
 	}
 
 	if vm == nil {
-		klog.V(4).Info("zones.GetZoneByNodeName() InstanceNotFound ", nodeName)
+		pod, err := discoverPodVMByName(ctx, nodeName, z.namespace, z.podClient)
+		if err != nil {
+			klog.Errorf("Error trying to find vSphere Pod: %v", err)
+			return zone, err
+		}
+		if pod != nil {
+			return cloudprovider.Zone{FailureDomain: pod.Labels[zoneLabelKey]}, nil
+		}
+		logging.V(logging.Paravirtual, 4).Info("zones.GetZoneByNodeName() InstanceNotFound ", nodeName)
 		return zone, cloudprovider.InstanceNotFound
 	}
 
-	if val, ok := vm.Labels["topology.kubernetes.io/zone"]; ok {
-		klog.V(4).Info("retrieved zone", val)
+	if val, ok := vm.Labels[zoneLabelKey]; ok {
+		logging.V(logging.Paravirtual, 4).Info("retrieved zone", val)
 		zone = cloudprovider.Zone{
 			FailureDomain: val,
 		}
@@ -91,8 +111,14 @@ func NewZones(namespace string, kcfg *rest.Config) (cloudprovider.Zones, error)
 		return nil, err
 	}
 
+	podClient, err := kubernetes.NewForConfig(kcfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &zones{
 		vmClient:  vmClient,
+		podClient: podClient.CoreV1(),
 		namespace: namespace,
 	}, nil
 }
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Checksum returns the SHA-256 of the effective content of a cloud config,
+// as a hex string. Comments, blank lines and indentation do not change the
+// checksum, nor does the order of YAML keys.
+func Checksum(byConfig []byte) string {
+	sum := sha256.Sum256(normalizeConfig(byConfig))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeConfig(byConfig []byte) []byte {
+	if isConfigYaml(byConfig) == nil {
+		var doc interface{}
+		if err := yaml.Unmarshal(byConfig, &doc); err == nil {
+			// yaml.v2 marshals map keys sorted
+			if normalized, err := yaml.Marshal(doc); err == nil {
+				return normalized
+			}
+		}
+	}
+
+	var normalized bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(byConfig))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		normalized.Write(line)
+		normalized.WriteByte('\n')
+	}
+	return normalized.Bytes()
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	testcases := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{
+			name: "YAMLFormatting",
+			a: `
+global:
+  user: user
+  password: password
+`,
+			b: `# credentials
+global:
+    password: password   # secret
+    user: user
+`,
+			equal: true,
+		},
+		{
+			name: "YAMLValue",
+			a: `
+global:
+  user: user
+`,
+			b: `
+global:
+  user: other
+`,
+		},
+		{
+			name: "INIFormatting",
+			a: `
+[Global]
+user = user
+password = password
+`,
+			b: `; credentials
+[Global]
+  user = user
+
+  password = password
+`,
+			equal: true,
+		},
+		{
+			name: "INIValue",
+			a: `
+[Global]
+user = user
+`,
+			b: `
+[Global]
+user = other
+`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			a, b := Checksum([]byte(testcase.a)), Checksum([]byte(testcase.b))
+			if (a == b) != testcase.equal {
+				t.Errorf("Expected equal checksums to be %v, got %s and %s", testcase.equal, a, b)
+			}
+		})
+	}
+}
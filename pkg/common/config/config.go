@@ -22,7 +22,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	klog "k8s.io/klog/v2"
 )
 
@@ -85,6 +87,9 @@ func (cfg *Config) FromEnv() error {
 	if v := os.Getenv("VSPHERE_DATACENTER"); v != "" {
 		cfg.Global.Datacenters = v
 	}
+	if v := os.Getenv("VSPHERE_DATACENTER_MOIDS"); v != "" {
+		cfg.Global.DatacenterMoids = v
+	}
 	if v := os.Getenv("VSPHERE_SECRET_NAME"); v != "" {
 		cfg.Global.SecretName = v
 	}
@@ -126,12 +131,91 @@ func (cfg *Config) FromEnv() error {
 	if v := os.Getenv("VSPHERE_THUMBPRINT"); v != "" {
 		cfg.Global.Thumbprint = v
 	}
+	if v := os.Getenv("VSPHERE_IDENTITY_SOURCE"); v != "" {
+		cfg.Global.IdentitySource = v
+	}
+	if v := os.Getenv("VSPHERE_USER_AGENT"); v != "" {
+		cfg.Global.UserAgent = v
+	}
+	if v := os.Getenv("VSPHERE_UNLISTED_DATACENTER_POLICY"); v != "" {
+		cfg.Global.UnlistedDatacenterPolicy = v
+	}
+	if v := os.Getenv("VSPHERE_TLS_MIN_VERSION"); v != "" {
+		cfg.Global.TLSMinVersion = v
+	}
+	if v := os.Getenv("VSPHERE_TLS_CIPHER_SUITES"); v != "" {
+		cfg.Global.TLSCipherSuites = v
+	}
+	if v := os.Getenv("VSPHERE_IP_SEARCH_NETWORKS"); v != "" {
+		cfg.Global.IPSearchNetworks = v
+	}
+	if v := os.Getenv("VSPHERE_IP_SEARCH_DATASTORES"); v != "" {
+		cfg.Global.IPSearchDatastores = v
+	}
+	if v := os.Getenv("VSPHERE_MAX_CONCURRENT_QUERIES"); v != "" {
+		tmp, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_MAX_CONCURRENT_QUERIES: %s", err)
+		} else {
+			cfg.Global.MaxConcurrentQueries = uint(tmp)
+		}
+	}
+	if v := os.Getenv("VSPHERE_API_QPS"); v != "" {
+		tmp, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_API_QPS: %s", err)
+		} else {
+			cfg.Global.APIQPS = uint(tmp)
+		}
+	}
+	if v := os.Getenv("VSPHERE_API_BURST"); v != "" {
+		tmp, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_API_BURST: %s", err)
+		} else {
+			cfg.Global.APIBurst = uint(tmp)
+		}
+	}
+	if v := os.Getenv("VSPHERE_ENDPOINTS"); v != "" {
+		cfg.Global.Endpoints = v
+	}
+	if v := os.Getenv("VSPHERE_ENDPOINT_SRV"); v != "" {
+		cfg.Global.EndpointSRV = v
+	}
+	if v := os.Getenv("VSPHERE_STRICT_CONFIG"); v != "" {
+		cfg.Global.StrictConfig = v
+	}
+	if v := os.Getenv("VSPHERE_TENANT_CONFIGMAP_SELECTOR"); v != "" {
+		cfg.Global.TenantConfigMapSelector = v
+	}
+	if v := os.Getenv("VSPHERE_TENANT_CONFIGMAP_NAMESPACE"); v != "" {
+		cfg.Global.TenantConfigMapNamespace = v
+	}
+	if v := os.Getenv("VSPHERE_SESSION_KEEP_ALIVE_PERIOD"); v != "" {
+		cfg.Global.SessionKeepAlivePeriod = v
+	}
+	if v := os.Getenv("VSPHERE_CACHE_VM_PROPERTIES"); v != "" {
+		cacheVMProperties, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_CACHE_VM_PROPERTIES: %s", err)
+		} else {
+			cfg.Global.CacheVMProperties = cacheVMProperties
+		}
+	}
 	if v := os.Getenv("VSPHERE_LABEL_REGION"); v != "" {
 		cfg.Labels.Region = v
 	}
 	if v := os.Getenv("VSPHERE_LABEL_ZONE"); v != "" {
 		cfg.Labels.Zone = v
 	}
+	if v := os.Getenv("VSPHERE_LABEL_DISABLED"); v != "" {
+		disabled, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Errorf("Failed to parse VSPHERE_LABEL_DISABLED: %s", err)
+		} else {
+			cfg.Labels.Disabled = disabled
+		}
+	}
 
 	//Build VirtualCenter from ENVs
 	for _, e := range os.Environ() {
@@ -176,6 +260,10 @@ func (cfg *Config) FromEnv() error {
 			if errDatacenters != nil {
 				datacenters = cfg.Global.Datacenters
 			}
+			_, datacenterMoids, errDatacenterMoids := getEnvKeyValue("VCENTER_"+id+"_DATACENTER_MOIDS", false)
+			if errDatacenterMoids != nil {
+				datacenterMoids = cfg.Global.DatacenterMoids
+			}
 			roundtrip := DefaultRoundTripperCount
 			_, roundtripTmp, errRoundtrip := getEnvKeyValue("VCENTER_"+id+"_ROUNDTRIP", false)
 			if errRoundtrip != nil {
@@ -192,6 +280,60 @@ func (cfg *Config) FromEnv() error {
 			if errThumbprint != nil {
 				thumbprint = cfg.Global.Thumbprint
 			}
+			_, identitySource, errIdentitySource := getEnvKeyValue("VCENTER_"+id+"_IDENTITY_SOURCE", false)
+			if errIdentitySource != nil {
+				identitySource = cfg.Global.IdentitySource
+			}
+			_, userAgent, errUserAgent := getEnvKeyValue("VCENTER_"+id+"_USER_AGENT", false)
+			if errUserAgent != nil {
+				userAgent = cfg.Global.UserAgent
+			}
+			_, unlistedDatacenterPolicy, errUnlistedDatacenterPolicy := getEnvKeyValue("VCENTER_"+id+"_UNLISTED_DATACENTER_POLICY", false)
+			if errUnlistedDatacenterPolicy != nil {
+				unlistedDatacenterPolicy = cfg.Global.UnlistedDatacenterPolicy
+			}
+			_, tlsMinVersion, errTLSMinVersion := getEnvKeyValue("VCENTER_"+id+"_TLS_MIN_VERSION", false)
+			if errTLSMinVersion != nil {
+				tlsMinVersion = cfg.Global.TLSMinVersion
+			}
+			_, tlsCipherSuites, errTLSCipherSuites := getEnvKeyValue("VCENTER_"+id+"_TLS_CIPHER_SUITES", false)
+			if errTLSCipherSuites != nil {
+				tlsCipherSuites = cfg.Global.TLSCipherSuites
+			}
+			_, ipSearchNetworks, errIPSearchNetworks := getEnvKeyValue("VCENTER_"+id+"_IP_SEARCH_NETWORKS", false)
+			if errIPSearchNetworks != nil {
+				ipSearchNetworks = cfg.Global.IPSearchNetworks
+			}
+			_, ipSearchDatastores, errIPSearchDatastores := getEnvKeyValue("VCENTER_"+id+"_IP_SEARCH_DATASTORES", false)
+			if errIPSearchDatastores != nil {
+				ipSearchDatastores = cfg.Global.IPSearchDatastores
+			}
+			maxConcurrentQueries := cfg.Global.MaxConcurrentQueries
+			_, maxConcurrentQueriesTmp, errMaxConcurrentQueries := getEnvKeyValue("VCENTER_"+id+"_MAX_CONCURRENT_QUERIES", false)
+			if errMaxConcurrentQueries == nil {
+				if tmp, errTmp := strconv.ParseUint(maxConcurrentQueriesTmp, 10, 32); errTmp == nil {
+					maxConcurrentQueries = uint(tmp)
+				}
+			}
+			apiQPS := cfg.Global.APIQPS
+			_, apiQPSTmp, errAPIQPS := getEnvKeyValue("VCENTER_"+id+"_API_QPS", false)
+			if errAPIQPS == nil {
+				if tmp, errTmp := strconv.ParseUint(apiQPSTmp, 10, 32); errTmp == nil {
+					apiQPS = uint(tmp)
+				}
+			}
+			apiBurst := cfg.Global.APIBurst
+			_, apiBurstTmp, errAPIBurst := getEnvKeyValue("VCENTER_"+id+"_API_BURST", false)
+			if errAPIBurst == nil {
+				if tmp, errTmp := strconv.ParseUint(apiBurstTmp, 10, 32); errTmp == nil {
+					apiBurst = uint(tmp)
+				}
+			}
+			// the endpoints of a vCenter are not inherited from the Global section
+			_, endpoints, _ := getEnvKeyValue("VCENTER_"+id+"_ENDPOINTS", false)
+			_, endpointSRV, _ := getEnvKeyValue("VCENTER_"+id+"_ENDPOINT_SRV", false)
+			_, region, _ := getEnvKeyValue("VCENTER_"+id+"_REGION", false)
+			_, zoneCategoryOverride, _ := getEnvKeyValue("VCENTER_"+id+"_ZONE_CATEGORY_OVERRIDE", false)
 
 			_, secretName, secretNameErr := getEnvKeyValue("VCENTER_"+id+"_SECRET_NAME", false)
 			_, secretNamespace, secretNamespaceErr := getEnvKeyValue("VCENTER_"+id+"_SECRET_NAMESPACE", false)
@@ -234,9 +376,24 @@ func (cfg *Config) FromEnv() error {
 			vcc.VCenterPort = port
 			vcc.InsecureFlag = insecureFlag
 			vcc.Datacenters = datacenters
+			vcc.DatacenterMoids = datacenterMoids
 			vcc.RoundTripperCount = roundtrip
 			vcc.CAFile = caFile
 			vcc.Thumbprint = thumbprint
+			vcc.IdentitySource = identitySource
+			vcc.UserAgent = userAgent
+			vcc.UnlistedDatacenterPolicy = unlistedDatacenterPolicy
+			vcc.TLSMinVersion = tlsMinVersion
+			vcc.TLSCipherSuites = tlsCipherSuites
+			vcc.IPSearchNetworks = ipSearchNetworks
+			vcc.IPSearchDatastores = ipSearchDatastores
+			vcc.MaxConcurrentQueries = maxConcurrentQueries
+			vcc.APIQPS = apiQPS
+			vcc.APIBurst = apiBurst
+			vcc.Endpoints = endpoints
+			vcc.EndpointSRV = endpointSRV
+			vcc.Region = region
+			vcc.ZoneCategoryOverride = zoneCategoryOverride
 			vcc.SecretRef = secretRef
 			vcc.SecretName = secretName
 			vcc.SecretNamespace = secretNamespace
@@ -299,6 +456,118 @@ func ReadConfig(byConfig []byte) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.ValidateUnlistedDatacenterPolicies(); err != nil {
+		klog.Errorf("ValidateUnlistedDatacenterPolicies failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateTLSSettings(); err != nil {
+		klog.Errorf("ValidateTLSSettings failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateEndpoints(); err != nil {
+		klog.Errorf("ValidateEndpoints failed: %s", err)
+		return nil, err
+	}
+
+	if err := cfg.ValidateTenantConfigMapSelector(); err != nil {
+		klog.Errorf("ValidateTenantConfigMapSelector failed: %s", err)
+		return nil, err
+	}
+
+	if _, err := cfg.SessionKeepAlivePeriodDuration(); err != nil {
+		klog.Errorf("SessionKeepAlivePeriodDuration failed: %s", err)
+		return nil, err
+	}
+
+	if err := CheckUnknownKeys(byConfig, cfg.Global.StrictConfig); err != nil {
+		klog.Errorf("CheckUnknownKeys failed: %s", err)
+		return nil, err
+	}
+
 	klog.Info("Config initialized")
 	return cfg, nil
 }
+
+// ReadTenantConfig parses the cloud config of a tenant, whose vCenters are
+// added to the ones of the cloud config. Unlike ReadConfig, the environment
+// variables are not applied, as they configure the cloud config itself.
+func ReadTenantConfig(byConfig []byte) (*Config, error) {
+	var cfg *Config
+	var err error
+	if isConfigYaml(byConfig) == nil {
+		cfg, err = ReadConfigYAML(byConfig)
+	} else {
+		cfg, err = ReadConfigINI(byConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.ValidateUnlistedDatacenterPolicies(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ValidateTLSSettings(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ValidateEndpoints(); err != nil {
+		return nil, err
+	}
+
+	// the global secret of a tenant is not the one of the cloud config
+	for _, vcConfig := range cfg.VirtualCenter {
+		if vcConfig.SecretRef != DefaultCredentialManager {
+			continue
+		}
+		if vcConfig.SecretName == "" && vcConfig.SecretNamespace == "" {
+			vcConfig.SecretName = cfg.Global.SecretName
+			vcConfig.SecretNamespace = cfg.Global.SecretNamespace
+		}
+		vcConfig.SecretRef = vcConfig.SecretNamespace + "/" + vcConfig.SecretName
+	}
+	return cfg, nil
+}
+
+// ValidateTenantConfigMapSelector checks the label selector of the tenant
+// ConfigMaps.
+func (cfg *Config) ValidateTenantConfigMapSelector() error {
+	if _, err := labels.Parse(cfg.Global.TenantConfigMapSelector); err != nil {
+		return getError(fmt.Sprintf("Invalid tenant ConfigMap selector %q: %v", cfg.Global.TenantConfigMapSelector, err))
+	}
+	return nil
+}
+
+// SessionKeepAlivePeriodDuration returns the parsed SessionKeepAlivePeriod,
+// DefaultSessionKeepAlivePeriod if unset.
+func (cfg *Config) SessionKeepAlivePeriodDuration() (time.Duration, error) {
+	value := cfg.Global.SessionKeepAlivePeriod
+	if value == "" {
+		return DefaultSessionKeepAlivePeriod, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, getError(fmt.Sprintf("Invalid session keep-alive period %q: %v", value, err))
+	}
+	if d < 0 {
+		return 0, getError(fmt.Sprintf("Invalid session keep-alive period %q: must not be negative", value))
+	}
+	return d, nil
+}
+
+// ValidateUnlistedDatacenterPolicies checks the unlisted datacenter policies
+// of the global section and of the vCenters.
+func (cfg *Config) ValidateUnlistedDatacenterPolicies() error {
+	policies := []string{cfg.Global.UnlistedDatacenterPolicy}
+	for _, vcConfig := range cfg.VirtualCenter {
+		policies = append(policies, vcConfig.UnlistedDatacenterPolicy)
+	}
+	for _, policy := range policies {
+		switch policy {
+		case "", UnlistedDatacenterReject, UnlistedDatacenterWarnAndAccept, UnlistedDatacenterRescope:
+		default:
+			return ErrInvalidUnlistedDatacenterPolicy
+		}
+	}
+	return nil
+}
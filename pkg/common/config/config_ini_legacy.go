@@ -42,34 +42,68 @@ func (cci *CommonConfigINI) CreateConfig() *Config {
 	cfg.Global.VCenterPort = cci.Global.VCenterPort
 	cfg.Global.InsecureFlag = cci.Global.InsecureFlag
 	cfg.Global.Datacenters = cci.Global.Datacenters
+	cfg.Global.DatacenterMoids = cci.Global.DatacenterMoids
 	cfg.Global.RoundTripperCount = cci.Global.RoundTripperCount
 	cfg.Global.CAFile = cci.Global.CAFile
 	cfg.Global.Thumbprint = cci.Global.Thumbprint
+	cfg.Global.IdentitySource = cci.Global.IdentitySource
+	cfg.Global.UserAgent = cci.Global.UserAgent
+	cfg.Global.UnlistedDatacenterPolicy = cci.Global.UnlistedDatacenterPolicy
+	cfg.Global.TLSMinVersion = cci.Global.TLSMinVersion
+	cfg.Global.TLSCipherSuites = cci.Global.TLSCipherSuites
+	cfg.Global.IPSearchNetworks = cci.Global.IPSearchNetworks
+	cfg.Global.IPSearchDatastores = cci.Global.IPSearchDatastores
+	cfg.Global.MaxConcurrentQueries = cci.Global.MaxConcurrentQueries
+	cfg.Global.APIQPS = cci.Global.APIQPS
+	cfg.Global.APIBurst = cci.Global.APIBurst
+	cfg.Global.Endpoints = cci.Global.Endpoints
+	cfg.Global.EndpointSRV = cci.Global.EndpointSRV
 	cfg.Global.SecretName = cci.Global.SecretName
 	cfg.Global.SecretNamespace = cci.Global.SecretNamespace
 	cfg.Global.SecretsDirectory = cci.Global.SecretsDirectory
+	cfg.Global.StrictConfig = cci.Global.StrictConfig
+	cfg.Global.TenantConfigMapSelector = cci.Global.TenantConfigMapSelector
+	cfg.Global.TenantConfigMapNamespace = cci.Global.TenantConfigMapNamespace
+	cfg.Global.SessionKeepAlivePeriod = cci.Global.SessionKeepAlivePeriod
+	cfg.Global.CacheVMProperties = cci.Global.CacheVMProperties
 
 	for keyVcConfig, valVcConfig := range cci.VirtualCenter {
 		cfg.VirtualCenter[keyVcConfig] = &VirtualCenterConfig{
-			User:              valVcConfig.User,
-			Password:          valVcConfig.Password,
-			TenantRef:         valVcConfig.TenantRef,
-			VCenterIP:         valVcConfig.VCenterIP,
-			VCenterPort:       valVcConfig.VCenterPort,
-			InsecureFlag:      valVcConfig.InsecureFlag,
-			Datacenters:       valVcConfig.Datacenters,
-			RoundTripperCount: valVcConfig.RoundTripperCount,
-			CAFile:            valVcConfig.CAFile,
-			Thumbprint:        valVcConfig.Thumbprint,
-			SecretRef:         valVcConfig.SecretRef,
-			SecretName:        valVcConfig.SecretName,
-			SecretNamespace:   valVcConfig.SecretNamespace,
-			IPFamilyPriority:  valVcConfig.IPFamilyPriority,
+			User:                     valVcConfig.User,
+			Password:                 valVcConfig.Password,
+			TenantRef:                valVcConfig.TenantRef,
+			VCenterIP:                valVcConfig.VCenterIP,
+			VCenterPort:              valVcConfig.VCenterPort,
+			InsecureFlag:             valVcConfig.InsecureFlag,
+			Datacenters:              valVcConfig.Datacenters,
+			DatacenterMoids:          valVcConfig.DatacenterMoids,
+			RoundTripperCount:        valVcConfig.RoundTripperCount,
+			CAFile:                   valVcConfig.CAFile,
+			Thumbprint:               valVcConfig.Thumbprint,
+			IdentitySource:           valVcConfig.IdentitySource,
+			UserAgent:                valVcConfig.UserAgent,
+			UnlistedDatacenterPolicy: valVcConfig.UnlistedDatacenterPolicy,
+			TLSMinVersion:            valVcConfig.TLSMinVersion,
+			TLSCipherSuites:          valVcConfig.TLSCipherSuites,
+			IPSearchNetworks:         valVcConfig.IPSearchNetworks,
+			IPSearchDatastores:       valVcConfig.IPSearchDatastores,
+			MaxConcurrentQueries:     valVcConfig.MaxConcurrentQueries,
+			APIQPS:                   valVcConfig.APIQPS,
+			APIBurst:                 valVcConfig.APIBurst,
+			Endpoints:                valVcConfig.Endpoints,
+			EndpointSRV:              valVcConfig.EndpointSRV,
+			Region:                   valVcConfig.Region,
+			ZoneCategoryOverride:     valVcConfig.ZoneCategoryOverride,
+			SecretRef:                valVcConfig.SecretRef,
+			SecretName:               valVcConfig.SecretName,
+			SecretNamespace:          valVcConfig.SecretNamespace,
+			IPFamilyPriority:         valVcConfig.IPFamilyPriority,
 		}
 	}
 
 	cfg.Labels.Region = cci.Labels.Region
 	cfg.Labels.Zone = cci.Labels.Zone
+	cfg.Labels.Disabled = cci.Labels.Disabled
 
 	return cfg
 }
@@ -130,20 +164,33 @@ func (cci *CommonConfigINI) validateConfig() error {
 	// VirtualCenter does not already exist in the map
 	if cci.Global.VCenterIP != "" && cci.VirtualCenter[cci.Global.VCenterIP] == nil {
 		cci.VirtualCenter[cci.Global.VCenterIP] = &VirtualCenterConfigINI{
-			User:              cci.Global.User,
-			Password:          cci.Global.Password,
-			TenantRef:         cci.Global.VCenterIP,
-			VCenterIP:         cci.Global.VCenterIP,
-			VCenterPort:       cci.Global.VCenterPort,
-			InsecureFlag:      cci.Global.InsecureFlag,
-			Datacenters:       cci.Global.Datacenters,
-			RoundTripperCount: cci.Global.RoundTripperCount,
-			CAFile:            cci.Global.CAFile,
-			Thumbprint:        cci.Global.Thumbprint,
-			SecretRef:         DefaultCredentialManager,
-			SecretName:        cci.Global.SecretName,
-			SecretNamespace:   cci.Global.SecretNamespace,
-			IPFamily:          cci.Global.IPFamily,
+			User:                     cci.Global.User,
+			Password:                 cci.Global.Password,
+			TenantRef:                cci.Global.VCenterIP,
+			VCenterIP:                cci.Global.VCenterIP,
+			VCenterPort:              cci.Global.VCenterPort,
+			InsecureFlag:             cci.Global.InsecureFlag,
+			Datacenters:              cci.Global.Datacenters,
+			DatacenterMoids:          cci.Global.DatacenterMoids,
+			RoundTripperCount:        cci.Global.RoundTripperCount,
+			CAFile:                   cci.Global.CAFile,
+			Thumbprint:               cci.Global.Thumbprint,
+			IdentitySource:           cci.Global.IdentitySource,
+			UserAgent:                cci.Global.UserAgent,
+			UnlistedDatacenterPolicy: cci.Global.UnlistedDatacenterPolicy,
+			TLSMinVersion:            cci.Global.TLSMinVersion,
+			TLSCipherSuites:          cci.Global.TLSCipherSuites,
+			IPSearchNetworks:         cci.Global.IPSearchNetworks,
+			IPSearchDatastores:       cci.Global.IPSearchDatastores,
+			MaxConcurrentQueries:     cci.Global.MaxConcurrentQueries,
+			APIQPS:                   cci.Global.APIQPS,
+			APIBurst:                 cci.Global.APIBurst,
+			Endpoints:                cci.Global.Endpoints,
+			EndpointSRV:              cci.Global.EndpointSRV,
+			SecretRef:                DefaultCredentialManager,
+			SecretName:               cci.Global.SecretName,
+			SecretNamespace:          cci.Global.SecretNamespace,
+			IPFamily:                 cci.Global.IPFamily,
 		}
 	}
 
@@ -196,10 +243,10 @@ func (cci *CommonConfigINI) validateConfig() error {
 			vcConfig.VCenterPort = cci.Global.VCenterPort
 		}
 
-		if vcConfig.Datacenters == "" {
-			if cci.Global.Datacenters != "" {
-				vcConfig.Datacenters = cci.Global.Datacenters
-			}
+		// datacenters are inherited by name and by moid together
+		if vcConfig.Datacenters == "" && vcConfig.DatacenterMoids == "" {
+			vcConfig.Datacenters = cci.Global.Datacenters
+			vcConfig.DatacenterMoids = cci.Global.DatacenterMoids
 		}
 		if vcConfig.RoundTripperCount == 0 {
 			vcConfig.RoundTripperCount = cci.Global.RoundTripperCount
@@ -210,6 +257,36 @@ func (cci *CommonConfigINI) validateConfig() error {
 		if vcConfig.Thumbprint == "" {
 			vcConfig.Thumbprint = cci.Global.Thumbprint
 		}
+		if vcConfig.IdentitySource == "" {
+			vcConfig.IdentitySource = cci.Global.IdentitySource
+		}
+		if vcConfig.UserAgent == "" {
+			vcConfig.UserAgent = cci.Global.UserAgent
+		}
+		if vcConfig.UnlistedDatacenterPolicy == "" {
+			vcConfig.UnlistedDatacenterPolicy = cci.Global.UnlistedDatacenterPolicy
+		}
+		if vcConfig.TLSMinVersion == "" {
+			vcConfig.TLSMinVersion = cci.Global.TLSMinVersion
+		}
+		if vcConfig.TLSCipherSuites == "" {
+			vcConfig.TLSCipherSuites = cci.Global.TLSCipherSuites
+		}
+		if vcConfig.IPSearchNetworks == "" {
+			vcConfig.IPSearchNetworks = cci.Global.IPSearchNetworks
+		}
+		if vcConfig.IPSearchDatastores == "" {
+			vcConfig.IPSearchDatastores = cci.Global.IPSearchDatastores
+		}
+		if vcConfig.MaxConcurrentQueries == 0 {
+			vcConfig.MaxConcurrentQueries = cci.Global.MaxConcurrentQueries
+		}
+		if vcConfig.APIQPS == 0 {
+			vcConfig.APIQPS = cci.Global.APIQPS
+		}
+		if vcConfig.APIBurst == 0 {
+			vcConfig.APIBurst = cci.Global.APIBurst
+		}
 
 		if vcConfig.IPFamily == "" {
 			vcConfig.IPFamily = cci.Global.IPFamily
@@ -239,3 +239,221 @@ func TestTenantRefsINI(t *testing.T) {
 		t.Errorf("vcConfig3 SecretRef should be kube-system/eu-secret but actual=%s", vcConfig3.SecretRef)
 	}
 }
+
+func TestIdentitySourceINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+port = 443
+user = user
+password = password
+identity-source = vsphere.local
+
+[VirtualCenter "10.0.0.1"]
+datacenters = "vic0dc"
+identity-source = "corp.example.com"
+
+[VirtualCenter "10.0.0.2"]
+datacenters = "vic1dc"
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["10.0.0.1"].IdentitySource != "corp.example.com" {
+		t.Errorf("10.0.0.1 IdentitySource should be corp.example.com but actual=%s", cfg.VirtualCenter["10.0.0.1"].IdentitySource)
+	}
+	if cfg.VirtualCenter["10.0.0.2"].IdentitySource != "vsphere.local" {
+		t.Errorf("10.0.0.2 IdentitySource should be inherited from global but actual=%s", cfg.VirtualCenter["10.0.0.2"].IdentitySource)
+	}
+}
+
+func TestUserAgentINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+port = 443
+user = user
+password = password
+user-agent = cpi-prod
+
+[VirtualCenter "10.0.0.1"]
+datacenters = "vic0dc"
+user-agent = "cpi-prod-vc1"
+
+[VirtualCenter "10.0.0.2"]
+datacenters = "vic1dc"
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["10.0.0.1"].UserAgent != "cpi-prod-vc1" {
+		t.Errorf("10.0.0.1 UserAgent should be cpi-prod-vc1 but actual=%s", cfg.VirtualCenter["10.0.0.1"].UserAgent)
+	}
+	if cfg.VirtualCenter["10.0.0.2"].UserAgent != "cpi-prod" {
+		t.Errorf("10.0.0.2 UserAgent should be inherited from global but actual=%s", cfg.VirtualCenter["10.0.0.2"].UserAgent)
+	}
+}
+
+func TestDatacenterMoidsINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+port = 443
+user = user
+password = password
+datacenters = "vic0dc"
+datacenter-moids = "datacenter-3"
+
+[VirtualCenter "10.0.0.1"]
+datacenter-moids = "datacenter-21,datacenter-22"
+
+[VirtualCenter "10.0.0.2"]
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	vc1 := cfg.VirtualCenter["10.0.0.1"]
+	if vc1.DatacenterMoids != "datacenter-21,datacenter-22" || vc1.Datacenters != "" {
+		t.Errorf("10.0.0.1 should not inherit datacenters from global but actual=%s and %s", vc1.Datacenters, vc1.DatacenterMoids)
+	}
+	vc2 := cfg.VirtualCenter["10.0.0.2"]
+	if vc2.DatacenterMoids != "datacenter-3" || vc2.Datacenters != "vic0dc" {
+		t.Errorf("10.0.0.2 should inherit datacenters from global but actual=%s and %s", vc2.Datacenters, vc2.DatacenterMoids)
+	}
+}
+
+func TestIPSearchHintsINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+port = 443
+user = user
+password = password
+datacenters = "vic0dc"
+ip-search-networks = "k8s-nodes"
+
+[VirtualCenter "10.0.0.1"]
+ip-search-networks = "dvportgroup-42"
+ip-search-datastores = "datastore-12"
+
+[VirtualCenter "10.0.0.2"]
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	vc1 := cfg.VirtualCenter["10.0.0.1"]
+	if vc1.IPSearchNetworks != "dvportgroup-42" || vc1.IPSearchDatastores != "datastore-12" {
+		t.Errorf("10.0.0.1 IP search hints are wrong, actual=%s and %s", vc1.IPSearchNetworks, vc1.IPSearchDatastores)
+	}
+	vc2 := cfg.VirtualCenter["10.0.0.2"]
+	if vc2.IPSearchNetworks != "k8s-nodes" || vc2.IPSearchDatastores != "" {
+		t.Errorf("10.0.0.2 should inherit IP search hints from global but actual=%s and %s", vc2.IPSearchNetworks, vc2.IPSearchDatastores)
+	}
+}
+
+func TestUnlistedDatacenterPolicyINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+port = 443
+user = user
+password = password
+unlisted-datacenter-policy = warn-and-accept
+
+[VirtualCenter "10.0.0.1"]
+datacenters = "vic0dc"
+unlisted-datacenter-policy = "rescope"
+
+[VirtualCenter "10.0.0.2"]
+datacenters = "vic1dc"
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["10.0.0.1"].UnlistedDatacenterPolicy != UnlistedDatacenterRescope {
+		t.Errorf("10.0.0.1 UnlistedDatacenterPolicy should be rescope but actual=%s", cfg.VirtualCenter["10.0.0.1"].UnlistedDatacenterPolicy)
+	}
+	if cfg.VirtualCenter["10.0.0.2"].UnlistedDatacenterPolicy != UnlistedDatacenterWarnAndAccept {
+		t.Errorf("10.0.0.2 UnlistedDatacenterPolicy should be inherited from global but actual=%s", cfg.VirtualCenter["10.0.0.2"].UnlistedDatacenterPolicy)
+	}
+}
+
+func TestTLSSettingsINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+port = 443
+user = user
+password = password
+tls-min-version = 1.2
+tls-cipher-suites = "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"
+
+[VirtualCenter "10.0.0.1"]
+datacenters = "vic0dc"
+tls-min-version = "VersionTLS13"
+
+[VirtualCenter "10.0.0.2"]
+datacenters = "vic1dc"
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if err := cfg.ValidateTLSSettings(); err != nil {
+		t.Fatalf("Should succeed when valid TLS settings are provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["10.0.0.1"].TLSMinVersion != "VersionTLS13" {
+		t.Errorf("10.0.0.1 TLSMinVersion should be VersionTLS13 but actual=%s", cfg.VirtualCenter["10.0.0.1"].TLSMinVersion)
+	}
+	if cfg.VirtualCenter["10.0.0.2"].TLSMinVersion != "1.2" {
+		t.Errorf("10.0.0.2 TLSMinVersion should be inherited from global but actual=%s", cfg.VirtualCenter["10.0.0.2"].TLSMinVersion)
+	}
+	suites, err := ParseTLSCipherSuites(cfg.VirtualCenter["10.0.0.2"].TLSCipherSuites)
+	if err != nil || len(suites) != 2 {
+		t.Errorf("10.0.0.2 TLSCipherSuites should be inherited from global but actual=%v, err=%v", suites, err)
+	}
+}
+
+func TestLabelsDisabledINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+server = 0.0.0.0
+user = user
+password = password
+datacenters = us-west
+
+[Labels]
+zone = k8s-zone
+region = k8s-region
+disabled = true
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if !cfg.Labels.Disabled || cfg.Labels.Region != "k8s-region" {
+		t.Errorf("Labels should be disabled but actual=%+v", cfg.Labels)
+	}
+}
+
+func TestVCenterTopologyINI(t *testing.T) {
+	cfg, err := ReadConfigINI([]byte(`
+[Global]
+user = user
+password = password
+datacenters = us-west
+
+[VirtualCenter "10.0.0.1"]
+region = site-a
+zone-category-override = k8s-rack
+
+[Labels]
+zone = k8s-zone
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if vcConfig := cfg.VirtualCenter["10.0.0.1"]; vcConfig.Region != "site-a" || vcConfig.ZoneCategoryOverride != "k8s-rack" {
+		t.Errorf("10.0.0.1 should be mapped to region site-a and zone category k8s-rack but actual=%+v", vcConfig)
+	}
+}
@@ -90,3 +90,34 @@ func TestReadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestReadTenantConfig(t *testing.T) {
+	// the environment configures the cloud config, not the tenants
+	t.Setenv("VSPHERE_USER", "cloud-config-user")
+
+	cfg, err := ReadTenantConfig([]byte(`
+global:
+  port: 443
+vcenter:
+  tenant-a:
+    server: 10.1.0.1
+    user: tenant-a-user
+    password: tenant-a-password
+    datacenters:
+      - dc-a
+`))
+	if err != nil {
+		t.Fatalf("ReadTenantConfig was not expected to return error: %v", err)
+	}
+	if len(cfg.VirtualCenter) != 1 {
+		t.Fatalf("expected 1 vCenter, got %d", len(cfg.VirtualCenter))
+	}
+	vcConfig := cfg.VirtualCenter["tenant-a"]
+	if vcConfig == nil || vcConfig.VCenterIP != "10.1.0.1" || vcConfig.User != "tenant-a-user" {
+		t.Errorf("unexpected vCenter config %+v", vcConfig)
+	}
+
+	if _, err := ReadTenantConfig([]byte(missingServerConfigYAML)); err == nil {
+		t.Error("ReadTenantConfig was expected to return error without vCenter")
+	}
+}
@@ -43,34 +43,68 @@ func (ccy *CommonConfigYAML) CreateConfig() *Config {
 	cfg.Global.VCenterPort = fmt.Sprint(ccy.Global.VCenterPort)
 	cfg.Global.InsecureFlag = ccy.Global.InsecureFlag
 	cfg.Global.Datacenters = strings.Join(ccy.Global.Datacenters, ",")
+	cfg.Global.DatacenterMoids = strings.Join(ccy.Global.DatacenterMoids, ",")
 	cfg.Global.RoundTripperCount = ccy.Global.RoundTripperCount
 	cfg.Global.CAFile = ccy.Global.CAFile
 	cfg.Global.Thumbprint = ccy.Global.Thumbprint
+	cfg.Global.IdentitySource = ccy.Global.IdentitySource
+	cfg.Global.UserAgent = ccy.Global.UserAgent
+	cfg.Global.UnlistedDatacenterPolicy = ccy.Global.UnlistedDatacenterPolicy
+	cfg.Global.TLSMinVersion = ccy.Global.TLSMinVersion
+	cfg.Global.TLSCipherSuites = strings.Join(ccy.Global.TLSCipherSuites, ",")
+	cfg.Global.IPSearchNetworks = strings.Join(ccy.Global.IPSearchNetworks, ",")
+	cfg.Global.IPSearchDatastores = strings.Join(ccy.Global.IPSearchDatastores, ",")
+	cfg.Global.MaxConcurrentQueries = ccy.Global.MaxConcurrentQueries
+	cfg.Global.APIQPS = ccy.Global.APIQPS
+	cfg.Global.APIBurst = ccy.Global.APIBurst
+	cfg.Global.Endpoints = strings.Join(ccy.Global.Endpoints, ",")
+	cfg.Global.EndpointSRV = ccy.Global.EndpointSRV
 	cfg.Global.SecretName = ccy.Global.SecretName
 	cfg.Global.SecretNamespace = ccy.Global.SecretNamespace
 	cfg.Global.SecretsDirectory = ccy.Global.SecretsDirectory
+	cfg.Global.StrictConfig = ccy.Global.StrictConfig
+	cfg.Global.TenantConfigMapSelector = ccy.Global.TenantConfigMapSelector
+	cfg.Global.TenantConfigMapNamespace = ccy.Global.TenantConfigMapNamespace
+	cfg.Global.SessionKeepAlivePeriod = ccy.Global.SessionKeepAlivePeriod
+	cfg.Global.CacheVMProperties = ccy.Global.CacheVMProperties
 
 	for keyVcConfig, valVcConfig := range ccy.Vcenter {
 		cfg.VirtualCenter[keyVcConfig] = &VirtualCenterConfig{
-			User:              valVcConfig.User,
-			Password:          valVcConfig.Password,
-			TenantRef:         valVcConfig.TenantRef,
-			VCenterIP:         valVcConfig.VCenterIP,
-			VCenterPort:       fmt.Sprint(valVcConfig.VCenterPort),
-			InsecureFlag:      valVcConfig.InsecureFlag,
-			Datacenters:       strings.Join(valVcConfig.Datacenters, ","),
-			RoundTripperCount: valVcConfig.RoundTripperCount,
-			CAFile:            valVcConfig.CAFile,
-			Thumbprint:        valVcConfig.Thumbprint,
-			SecretRef:         valVcConfig.SecretRef,
-			SecretName:        valVcConfig.SecretName,
-			SecretNamespace:   valVcConfig.SecretNamespace,
-			IPFamilyPriority:  valVcConfig.IPFamilyPriority,
+			User:                     valVcConfig.User,
+			Password:                 valVcConfig.Password,
+			TenantRef:                valVcConfig.TenantRef,
+			VCenterIP:                valVcConfig.VCenterIP,
+			VCenterPort:              fmt.Sprint(valVcConfig.VCenterPort),
+			InsecureFlag:             valVcConfig.InsecureFlag,
+			Datacenters:              strings.Join(valVcConfig.Datacenters, ","),
+			DatacenterMoids:          strings.Join(valVcConfig.DatacenterMoids, ","),
+			RoundTripperCount:        valVcConfig.RoundTripperCount,
+			CAFile:                   valVcConfig.CAFile,
+			Thumbprint:               valVcConfig.Thumbprint,
+			IdentitySource:           valVcConfig.IdentitySource,
+			UserAgent:                valVcConfig.UserAgent,
+			UnlistedDatacenterPolicy: valVcConfig.UnlistedDatacenterPolicy,
+			TLSMinVersion:            valVcConfig.TLSMinVersion,
+			TLSCipherSuites:          strings.Join(valVcConfig.TLSCipherSuites, ","),
+			IPSearchNetworks:         strings.Join(valVcConfig.IPSearchNetworks, ","),
+			IPSearchDatastores:       strings.Join(valVcConfig.IPSearchDatastores, ","),
+			MaxConcurrentQueries:     valVcConfig.MaxConcurrentQueries,
+			APIQPS:                   valVcConfig.APIQPS,
+			APIBurst:                 valVcConfig.APIBurst,
+			Endpoints:                strings.Join(valVcConfig.Endpoints, ","),
+			EndpointSRV:              valVcConfig.EndpointSRV,
+			Region:                   valVcConfig.Region,
+			ZoneCategoryOverride:     valVcConfig.ZoneCategoryOverride,
+			SecretRef:                valVcConfig.SecretRef,
+			SecretName:               valVcConfig.SecretName,
+			SecretNamespace:          valVcConfig.SecretNamespace,
+			IPFamilyPriority:         valVcConfig.IPFamilyPriority,
 		}
 	}
 
 	cfg.Labels.Region = ccy.Labels.Region
 	cfg.Labels.Zone = ccy.Labels.Zone
+	cfg.Labels.Disabled = ccy.Labels.Disabled
 
 	return cfg
 }
@@ -106,20 +140,33 @@ func (ccy *CommonConfigYAML) validateConfig() error {
 	// VirtualCenter does not already exist in the map
 	if ccy.Global.VCenterIP != "" && ccy.Vcenter[ccy.Global.VCenterIP] == nil {
 		ccy.Vcenter[ccy.Global.VCenterIP] = &VirtualCenterConfigYAML{
-			User:              ccy.Global.User,
-			Password:          ccy.Global.Password,
-			TenantRef:         ccy.Global.VCenterIP,
-			VCenterIP:         ccy.Global.VCenterIP,
-			VCenterPort:       ccy.Global.VCenterPort,
-			InsecureFlag:      ccy.Global.InsecureFlag,
-			Datacenters:       ccy.Global.Datacenters,
-			RoundTripperCount: ccy.Global.RoundTripperCount,
-			CAFile:            ccy.Global.CAFile,
-			Thumbprint:        ccy.Global.Thumbprint,
-			SecretRef:         DefaultCredentialManager,
-			SecretName:        ccy.Global.SecretName,
-			SecretNamespace:   ccy.Global.SecretNamespace,
-			IPFamilyPriority:  ccy.Global.IPFamilyPriority,
+			User:                     ccy.Global.User,
+			Password:                 ccy.Global.Password,
+			TenantRef:                ccy.Global.VCenterIP,
+			VCenterIP:                ccy.Global.VCenterIP,
+			VCenterPort:              ccy.Global.VCenterPort,
+			InsecureFlag:             ccy.Global.InsecureFlag,
+			Datacenters:              ccy.Global.Datacenters,
+			DatacenterMoids:          ccy.Global.DatacenterMoids,
+			RoundTripperCount:        ccy.Global.RoundTripperCount,
+			CAFile:                   ccy.Global.CAFile,
+			Thumbprint:               ccy.Global.Thumbprint,
+			IdentitySource:           ccy.Global.IdentitySource,
+			UserAgent:                ccy.Global.UserAgent,
+			UnlistedDatacenterPolicy: ccy.Global.UnlistedDatacenterPolicy,
+			TLSMinVersion:            ccy.Global.TLSMinVersion,
+			TLSCipherSuites:          ccy.Global.TLSCipherSuites,
+			IPSearchNetworks:         ccy.Global.IPSearchNetworks,
+			IPSearchDatastores:       ccy.Global.IPSearchDatastores,
+			MaxConcurrentQueries:     ccy.Global.MaxConcurrentQueries,
+			APIQPS:                   ccy.Global.APIQPS,
+			APIBurst:                 ccy.Global.APIBurst,
+			Endpoints:                ccy.Global.Endpoints,
+			EndpointSRV:              ccy.Global.EndpointSRV,
+			SecretRef:                DefaultCredentialManager,
+			SecretName:               ccy.Global.SecretName,
+			SecretNamespace:          ccy.Global.SecretNamespace,
+			IPFamilyPriority:         ccy.Global.IPFamilyPriority,
 		}
 	}
 
@@ -165,10 +212,10 @@ func (ccy *CommonConfigYAML) validateConfig() error {
 			vcConfig.VCenterPort = ccy.Global.VCenterPort
 		}
 
-		if len(vcConfig.Datacenters) == 0 {
-			if len(ccy.Global.Datacenters) != 0 {
-				vcConfig.Datacenters = ccy.Global.Datacenters
-			}
+		// datacenters are inherited by name and by moid together
+		if len(vcConfig.Datacenters) == 0 && len(vcConfig.DatacenterMoids) == 0 {
+			vcConfig.Datacenters = ccy.Global.Datacenters
+			vcConfig.DatacenterMoids = ccy.Global.DatacenterMoids
 		}
 		if vcConfig.RoundTripperCount == 0 {
 			vcConfig.RoundTripperCount = ccy.Global.RoundTripperCount
@@ -179,6 +226,36 @@ func (ccy *CommonConfigYAML) validateConfig() error {
 		if vcConfig.Thumbprint == "" {
 			vcConfig.Thumbprint = ccy.Global.Thumbprint
 		}
+		if vcConfig.IdentitySource == "" {
+			vcConfig.IdentitySource = ccy.Global.IdentitySource
+		}
+		if vcConfig.UserAgent == "" {
+			vcConfig.UserAgent = ccy.Global.UserAgent
+		}
+		if vcConfig.UnlistedDatacenterPolicy == "" {
+			vcConfig.UnlistedDatacenterPolicy = ccy.Global.UnlistedDatacenterPolicy
+		}
+		if vcConfig.TLSMinVersion == "" {
+			vcConfig.TLSMinVersion = ccy.Global.TLSMinVersion
+		}
+		if len(vcConfig.TLSCipherSuites) == 0 {
+			vcConfig.TLSCipherSuites = ccy.Global.TLSCipherSuites
+		}
+		if len(vcConfig.IPSearchNetworks) == 0 {
+			vcConfig.IPSearchNetworks = ccy.Global.IPSearchNetworks
+		}
+		if len(vcConfig.IPSearchDatastores) == 0 {
+			vcConfig.IPSearchDatastores = ccy.Global.IPSearchDatastores
+		}
+		if vcConfig.MaxConcurrentQueries == 0 {
+			vcConfig.MaxConcurrentQueries = ccy.Global.MaxConcurrentQueries
+		}
+		if vcConfig.APIQPS == 0 {
+			vcConfig.APIQPS = ccy.Global.APIQPS
+		}
+		if vcConfig.APIBurst == 0 {
+			vcConfig.APIBurst = ccy.Global.APIBurst
+		}
 
 		if len(vcConfig.IPFamilyPriority) == 0 {
 			vcConfig.IPFamilyPriority = ccy.Global.IPFamilyPriority
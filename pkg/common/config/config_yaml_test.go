@@ -19,6 +19,7 @@ package config
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 /*
@@ -181,3 +182,458 @@ func TestIsConfigYAML(t *testing.T) {
 		t.Error("Generic text file should be invalid")
 	}
 }
+
+func TestIdentitySourceYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  identitySource: vsphere.local
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    identitySource: corp.example.com
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["tenant1"].IdentitySource != "corp.example.com" {
+		t.Errorf("tenant1 IdentitySource should be corp.example.com but actual=%s", cfg.VirtualCenter["tenant1"].IdentitySource)
+	}
+	if cfg.VirtualCenter["tenant2"].IdentitySource != "vsphere.local" {
+		t.Errorf("tenant2 IdentitySource should be inherited from global but actual=%s", cfg.VirtualCenter["tenant2"].IdentitySource)
+	}
+}
+
+func TestUserAgentYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  userAgent: cpi-prod
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    userAgent: cpi-prod-tenant1
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["tenant1"].UserAgent != "cpi-prod-tenant1" {
+		t.Errorf("tenant1 UserAgent should be cpi-prod-tenant1 but actual=%s", cfg.VirtualCenter["tenant1"].UserAgent)
+	}
+	if cfg.VirtualCenter["tenant2"].UserAgent != "cpi-prod" {
+		t.Errorf("tenant2 UserAgent should be inherited from global but actual=%s", cfg.VirtualCenter["tenant2"].UserAgent)
+	}
+}
+
+func TestDatacenterMoidsYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  datacenters:
+    - vic0dc
+  datacenterMoids:
+    - datacenter-3
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenterMoids:
+      - datacenter-21
+      - datacenter-22
+  tenant2:
+    server: 10.0.0.2
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.Global.DatacenterMoids != "datacenter-3" {
+		t.Errorf("Global DatacenterMoids should be datacenter-3 but actual=%s", cfg.Global.DatacenterMoids)
+	}
+	tenant1 := cfg.VirtualCenter["tenant1"]
+	if tenant1.DatacenterMoids != "datacenter-21,datacenter-22" || tenant1.Datacenters != "" {
+		t.Errorf("tenant1 should not inherit datacenters from global but actual=%s and %s", tenant1.Datacenters, tenant1.DatacenterMoids)
+	}
+	tenant2 := cfg.VirtualCenter["tenant2"]
+	if tenant2.DatacenterMoids != "datacenter-3" || tenant2.Datacenters != "vic0dc" {
+		t.Errorf("tenant2 should inherit datacenters from global but actual=%s and %s", tenant2.Datacenters, tenant2.DatacenterMoids)
+	}
+}
+
+func TestIPSearchHintsYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  ipSearchNetworks:
+    - k8s-nodes
+  ipSearchDatastores:
+    - datastore-12
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    ipSearchNetworks:
+      - dvportgroup-42
+      - k8s-nodes-2
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	tenant1 := cfg.VirtualCenter["tenant1"]
+	if tenant1.IPSearchNetworks != "dvportgroup-42,k8s-nodes-2" || tenant1.IPSearchDatastores != "datastore-12" {
+		t.Errorf("tenant1 IP search hints are wrong, actual=%s and %s", tenant1.IPSearchNetworks, tenant1.IPSearchDatastores)
+	}
+	tenant2 := cfg.VirtualCenter["tenant2"]
+	if tenant2.IPSearchNetworks != "k8s-nodes" || tenant2.IPSearchDatastores != "datastore-12" {
+		t.Errorf("tenant2 should inherit IP search hints from global but actual=%s and %s", tenant2.IPSearchNetworks, tenant2.IPSearchDatastores)
+	}
+}
+
+func TestUnlistedDatacenterPolicyYAML(t *testing.T) {
+	cfg, err := ReadConfig([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  unlistedDatacenterPolicy: warn-and-accept
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    unlistedDatacenterPolicy: rescope
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["tenant1"].UnlistedDatacenterPolicy != UnlistedDatacenterRescope {
+		t.Errorf("tenant1 UnlistedDatacenterPolicy should be rescope but actual=%s", cfg.VirtualCenter["tenant1"].UnlistedDatacenterPolicy)
+	}
+	if cfg.VirtualCenter["tenant2"].UnlistedDatacenterPolicy != UnlistedDatacenterWarnAndAccept {
+		t.Errorf("tenant2 UnlistedDatacenterPolicy should be inherited from global but actual=%s", cfg.VirtualCenter["tenant2"].UnlistedDatacenterPolicy)
+	}
+
+	_, err = ReadConfig([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    unlistedDatacenterPolicy: accept
+`))
+	if err != ErrInvalidUnlistedDatacenterPolicy {
+		t.Errorf("Should fail on an invalid unlisted datacenter policy, got %v", err)
+	}
+}
+
+func TestTLSSettingsYAML(t *testing.T) {
+	cfg, err := ReadConfig([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  tlsMinVersion: "1.2"
+  tlsCipherSuites:
+    - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+    - TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    tlsMinVersion: "1.3"
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	suites := "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"
+	if cfg.VirtualCenter["tenant1"].TLSMinVersion != "1.3" {
+		t.Errorf("tenant1 TLSMinVersion should be 1.3 but actual=%s", cfg.VirtualCenter["tenant1"].TLSMinVersion)
+	}
+	if cfg.VirtualCenter["tenant1"].TLSCipherSuites != suites {
+		t.Errorf("tenant1 TLSCipherSuites should be inherited from global but actual=%s", cfg.VirtualCenter["tenant1"].TLSCipherSuites)
+	}
+	if cfg.VirtualCenter["tenant2"].TLSMinVersion != "1.2" {
+		t.Errorf("tenant2 TLSMinVersion should be inherited from global but actual=%s", cfg.VirtualCenter["tenant2"].TLSMinVersion)
+	}
+
+	for _, invalid := range []string{"tlsMinVersion: \"1.4\"", "tlsCipherSuites: [TLS_RSA_WITH_RC4_128_SHA]"} {
+		_, err = ReadConfig([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    ` + invalid + `
+`))
+		if err == nil {
+			t.Errorf("Should fail on invalid TLS settings %s", invalid)
+		}
+	}
+}
+
+func TestLabelsDisabledYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  server: 0.0.0.0
+  user: user
+  password: password
+  datacenters:
+    - us-west
+
+labels:
+  zone: k8s-zone
+  region: k8s-region
+  disabled: true
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if !cfg.Labels.Disabled || cfg.Labels.Zone != "k8s-zone" {
+		t.Errorf("Labels should be disabled but actual=%+v", cfg.Labels)
+	}
+}
+
+func TestMaxConcurrentQueriesYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  maxConcurrentQueries: 4
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    maxConcurrentQueries: 2
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["tenant1"].MaxConcurrentQueries != 2 {
+		t.Errorf("tenant1 MaxConcurrentQueries should be 2 but actual=%d", cfg.VirtualCenter["tenant1"].MaxConcurrentQueries)
+	}
+	if cfg.VirtualCenter["tenant2"].MaxConcurrentQueries != 4 {
+		t.Errorf("tenant2 MaxConcurrentQueries should be inherited from global but actual=%d", cfg.VirtualCenter["tenant2"].MaxConcurrentQueries)
+	}
+}
+
+func TestAPIRateLimitYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+  apiQPS: 20
+  apiBurst: 40
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    apiQPS: 5
+  tenant2:
+    server: 10.0.0.2
+    datacenters:
+      - vic1dc
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if vcc := cfg.VirtualCenter["tenant1"]; vcc.APIQPS != 5 || vcc.APIBurst != 40 {
+		t.Errorf("tenant1 should have its own QPS and the global burst but actual=%d/%d", vcc.APIQPS, vcc.APIBurst)
+	}
+	if vcc := cfg.VirtualCenter["tenant2"]; vcc.APIQPS != 20 || vcc.APIBurst != 40 {
+		t.Errorf("tenant2 QPS and burst should be inherited from global but actual=%d/%d", vcc.APIQPS, vcc.APIBurst)
+	}
+}
+
+func TestEndpointsYAML(t *testing.T) {
+	cfg, err := ReadConfig([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+
+vcenter:
+  tenant1:
+    server: vcenter.site-a.example.com
+    datacenters:
+      - vic0dc
+    endpoints:
+      - vcenter.site-b.example.com
+      - 10.0.0.2:8443
+  tenant2:
+    server: 10.0.0.3
+    datacenters:
+      - vic1dc
+    endpointSRV: _vcenter._tcp.example.com
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if cfg.VirtualCenter["tenant1"].Endpoints != "vcenter.site-b.example.com,10.0.0.2:8443" {
+		t.Errorf("tenant1 Endpoints unexpected actual=%s", cfg.VirtualCenter["tenant1"].Endpoints)
+	}
+	if cfg.VirtualCenter["tenant2"].EndpointSRV != "_vcenter._tcp.example.com" || cfg.VirtualCenter["tenant2"].Endpoints != "" {
+		t.Errorf("tenant2 should only have an endpoint SRV record but actual=%+v", cfg.VirtualCenter["tenant2"])
+	}
+
+	endpoints, err := ParseEndpoints("vcenter.example.com, 10.0.0.2:8443,fd00::1,[fd00::2]:9443", "443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"vcenter.example.com:443", "10.0.0.2:8443", "[fd00::1]:443", "[fd00::2]:9443"}
+	if strings.Join(endpoints, ",") != strings.Join(expected, ",") {
+		t.Errorf("ParseEndpoints should return %v but actual=%v", expected, endpoints)
+	}
+
+	for _, invalid := range []string{"endpoints: [\"10.0.0.2:port\"]", "endpoints: [\"fd00:::1:2:3:x\"]",
+		"endpoints: [10.0.0.2]\n    endpointSRV: _vcenter._tcp.example.com"} {
+		_, err = ReadConfig([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    ` + invalid + `
+`))
+		if err == nil {
+			t.Errorf("Should fail on invalid endpoints %s", invalid)
+		}
+	}
+}
+
+func TestSessionKeepAlivePeriodYAML(t *testing.T) {
+	cfg, err := ReadConfig([]byte(`
+global:
+  user: user
+  password: password
+  sessionKeepAlivePeriod: 1m
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+	if period, _ := cfg.SessionKeepAlivePeriodDuration(); period != time.Minute {
+		t.Errorf("SessionKeepAlivePeriod should be 1m but actual=%s", period)
+	}
+
+	cfg.Global.SessionKeepAlivePeriod = ""
+	if period, _ := cfg.SessionKeepAlivePeriodDuration(); period != DefaultSessionKeepAlivePeriod {
+		t.Errorf("SessionKeepAlivePeriod should default to %s but actual=%s", DefaultSessionKeepAlivePeriod, period)
+	}
+
+	if _, err := ReadConfig([]byte(`
+global:
+  user: user
+  password: password
+  sessionKeepAlivePeriod: -1m
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+`)); err == nil {
+		t.Error("Should fail when the session keep-alive period is negative")
+	}
+}
+
+func TestVCenterTopologyYAML(t *testing.T) {
+	cfg, err := ReadConfigYAML([]byte(`
+global:
+  port: 443
+  user: user
+  password: password
+
+vcenter:
+  site-a:
+    server: 10.0.0.1
+    datacenters:
+      - dc-a
+    region: site-a
+    zoneCategoryOverride: k8s-rack
+  site-b:
+    server: 10.0.0.2
+    datacenters:
+      - dc-b
+
+labels:
+  zone: k8s-zone
+`))
+	if err != nil {
+		t.Fatalf("Should succeed when a valid config is provided: %s", err)
+	}
+
+	if vcConfig := cfg.VirtualCenter["site-a"]; vcConfig.Region != "site-a" || vcConfig.ZoneCategoryOverride != "k8s-rack" {
+		t.Errorf("site-a should be mapped to region site-a and zone category k8s-rack but actual=%+v", vcConfig)
+	}
+	if vcConfig := cfg.VirtualCenter["site-b"]; vcConfig.Region != "" || vcConfig.ZoneCategoryOverride != "" {
+		t.Errorf("site-b should not be mapped to a region but actual=%+v", vcConfig)
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package config
 
+import "time"
+
 const (
 	// DefaultRoundTripperCount is the number of allowed round trips
 	// before an error is returned.
@@ -43,6 +45,27 @@ const (
 
 	// DefaultCredentialManager used for the Global CredMgr/Lister
 	DefaultCredentialManager string = "Global"
+
+	// RedactedValue replaces the secrets of a redacted config
+	RedactedValue string = "<redacted>"
+
+	// DefaultTenantConfigMapNamespace is the default namespace of the
+	// ConfigMaps holding the cloud configs of tenants
+	DefaultTenantConfigMapNamespace string = "kube-system"
+
+	// DefaultSessionKeepAlivePeriod is the default period at which the
+	// vCenter sessions are checked
+	DefaultSessionKeepAlivePeriod = 5 * time.Minute
+
+	// UnlistedDatacenterReject fails the discovery of VMs found in a
+	// datacenter that is not listed, which is the default
+	UnlistedDatacenterReject = "reject"
+	// UnlistedDatacenterWarnAndAccept searches the datacenters that are not
+	// listed when a VM is not found in the listed ones and logs a warning
+	UnlistedDatacenterWarnAndAccept = "warn-and-accept"
+	// UnlistedDatacenterRescope also adds the datacenter a VM is found in to
+	// the datacenters of the vCenter until restart
+	UnlistedDatacenterRescope = "rescope"
 )
 
 var (
@@ -62,6 +85,10 @@ var (
 
 	// ErrInvalidIPFamilyType is returned when an invalid IPFamily type is encountered
 	ErrInvalidIPFamilyType = getError("Invalid IP Family type")
+
+	// ErrInvalidUnlistedDatacenterPolicy is returned when an invalid unlisted
+	// datacenter policy is encountered
+	ErrInvalidUnlistedDatacenterPolicy = getError("Invalid unlisted datacenter policy, must be reject, warn-and-accept or rescope")
 )
 
 // Err error to be used for any config related errors
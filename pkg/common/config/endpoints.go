@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseEndpoints parses a comma separated list of endpoints, as host or
+// host:port, into host:port endpoints, using defaultPort for the endpoints
+// without a port.
+func ParseEndpoints(endpoints, defaultPort string) ([]string, error) {
+	var parsed []string
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			// a host without a port, possibly an IPv6 address
+			host, port = strings.Trim(endpoint, "[]"), defaultPort
+			if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+				return nil, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+			}
+		}
+		if host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: invalid port %q", endpoint, port)
+		}
+		parsed = append(parsed, net.JoinHostPort(host, port))
+	}
+	return parsed, nil
+}
+
+// ValidateEndpoints checks the endpoints of the global section and of the
+// vCenters.
+func (cfg *Config) ValidateEndpoints() error {
+	if err := validateEndpoints(cfg.Global.Endpoints, cfg.Global.EndpointSRV); err != nil {
+		return err
+	}
+	for tenantRef, vcConfig := range cfg.VirtualCenter {
+		if err := validateEndpoints(vcConfig.Endpoints, vcConfig.EndpointSRV); err != nil {
+			return fmt.Errorf("vCenter %s: %w", tenantRef, err)
+		}
+	}
+	return nil
+}
+
+func validateEndpoints(endpoints, endpointSRV string) error {
+	if endpoints != "" && endpointSRV != "" {
+		return fmt.Errorf("endpoints and endpoint SRV record cannot be combined")
+	}
+	_, err := ParseEndpoints(endpoints, DefaultVCenterPortStr)
+	return err
+}
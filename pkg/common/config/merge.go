@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+	klog "k8s.io/klog/v2"
+)
+
+// ResolveConfigFiles returns the cloud config files of the --cloud-config
+// value, a comma separated list of files and directories. A directory stands
+// for the regular files it contains, sorted by name. Hidden files, such as
+// the ..data link of a mounted ConfigMap or Secret, are skipped.
+func ResolveConfigFiles(value string) ([]string, error) {
+	var files []string
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			// entries of mounted ConfigMaps and Secrets are links
+			info, err := os.Stat(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if info.Mode().IsRegular() {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no cloud config file found in %q", value)
+	}
+	return files, nil
+}
+
+// ReadConfigFiles reads the cloud config files of the --cloud-config value,
+// see ResolveConfigFiles. A single file is returned as is. Several files must
+// be YAML and are merged in order: sections and maps, such as the vCenters,
+// are merged key by key, other values of a later file take precedence and a
+// key which is a section in one file but a value in another is a conflict.
+func ReadConfigFiles(value string) ([]byte, error) {
+	files, err := ResolveConfigFiles(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 1 {
+		return os.ReadFile(files[0])
+	}
+
+	merged := map[interface{}]interface{}{}
+	origins := map[string]string{}
+	for _, file := range files {
+		byConfig, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := isConfigYaml(byConfig); err != nil {
+			return nil, fmt.Errorf("merging cloud config %s failed, only YAML cloud configs can be merged: %w", file, err)
+		}
+		doc := map[interface{}]interface{}{}
+		if err := yaml.Unmarshal(byConfig, &doc); err != nil {
+			return nil, fmt.Errorf("merging cloud config %s failed: %w", file, err)
+		}
+		if err := mergeConfig(merged, doc, "", file, origins); err != nil {
+			return nil, fmt.Errorf("merging cloud config %s failed: %w", file, err)
+		}
+	}
+	klog.Infof("merged cloud config files %s", strings.Join(files, ", "))
+	return yaml.Marshal(merged)
+}
+
+// mergeConfig merges src of file into dst. origins records the file each
+// key path was set by, to report overridden values.
+func mergeConfig(dst, src map[interface{}]interface{}, path, file string, origins map[string]string) error {
+	keys := make([]string, 0, len(src))
+	byName := map[string]interface{}{}
+	for key := range src {
+		name := fmt.Sprint(key)
+		keys = append(keys, name)
+		byName[name] = key
+	}
+	sort.Strings(keys)
+
+	for _, name := range keys {
+		key := byName[name]
+		keyPath := name
+		if path != "" {
+			keyPath = path + "." + name
+		}
+		value := src[key]
+		existing, ok := dst[key]
+		if !ok || existing == nil {
+			dst[key] = value
+			recordOrigins(value, keyPath, file, origins)
+			continue
+		}
+		existingMap, existingIsMap := existing.(map[interface{}]interface{})
+		valueMap, valueIsMap := value.(map[interface{}]interface{})
+		switch {
+		case existingIsMap && valueIsMap:
+			if err := mergeConfig(existingMap, valueMap, keyPath, file, origins); err != nil {
+				return err
+			}
+		case existingIsMap || valueIsMap:
+			return fmt.Errorf("%s conflicts with %s, a section cannot be merged with a value", keyPath, origins[keyPath])
+		default:
+			if !reflect.DeepEqual(existing, value) {
+				klog.Infof("cloud config %s of %s overrides the value of %s", keyPath, file, origins[keyPath])
+			}
+			dst[key] = value
+			origins[keyPath] = file
+		}
+	}
+	return nil
+}
+
+// recordOrigins records file as origin of value and, for a section, of all
+// its keys
+func recordOrigins(value interface{}, path, file string, origins map[string]string) {
+	origins[path] = file
+	if section, ok := value.(map[interface{}]interface{}); ok {
+		for key, value := range section {
+			recordOrigins(value, path+"."+fmt.Sprint(key), file, origins)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	vcenters := filepath.Join(dir, "vcenters")
+	if err := os.Mkdir(vcenters, 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFiles(t, dir, map[string]string{"global.yaml": "", "lb.yaml": ""})
+	writeConfigFiles(t, vcenters, map[string]string{"b.yaml": "", "a.yaml": "", "..data": ""})
+
+	files, err := ResolveConfigFiles(filepath.Join(dir, "global.yaml") + "," + vcenters + ", " + filepath.Join(dir, "lb.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		filepath.Join(dir, "global.yaml"),
+		filepath.Join(vcenters, "a.yaml"),
+		filepath.Join(vcenters, "b.yaml"),
+		filepath.Join(dir, "lb.yaml"),
+	}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected files %v, got %v", expected, files)
+	}
+
+	if _, err := ResolveConfigFiles(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := ResolveConfigFiles(t.TempDir()); err == nil {
+		t.Error("expected an error for an empty directory")
+	}
+}
+
+func TestReadConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"1-global.yaml": `
+global:
+  user: user
+  password: password
+  port: 443
+  insecureFlag: true
+`,
+		"2-vc1.yaml": `
+vcenter:
+  vc1:
+    server: vc1.example.com
+    datacenters:
+      - dc1
+`,
+		"3-vc2.yaml": `
+vcenter:
+  vc2:
+    server: vc2.example.com
+    datacenters:
+      - dc2
+`,
+		"4-overlay.yaml": `
+global:
+  port: 8443
+vcenter:
+  vc1:
+    datacenters:
+      - dc3
+`,
+	})
+
+	byConfig, err := ReadConfigFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ReadConfig(byConfig)
+	if err != nil {
+		t.Fatalf("merged config is invalid: %v", err)
+	}
+	if cfg.Global.User != "user" || cfg.Global.VCenterPort != "8443" {
+		t.Errorf("unexpected global section %+v", cfg.Global)
+	}
+	if len(cfg.VirtualCenter) != 2 {
+		t.Fatalf("expected 2 vCenters, got %d", len(cfg.VirtualCenter))
+	}
+	if vc := cfg.VirtualCenter["vc1"]; vc == nil || vc.Datacenters != "dc3" {
+		t.Errorf("expected vc1 overlaid with datacenter dc3, got %+v", vc)
+	}
+	if vc := cfg.VirtualCenter["vc2"]; vc == nil || vc.Datacenters != "dc2" || vc.User != "user" {
+		t.Errorf("unexpected vc2 %+v", vc)
+	}
+}
+
+func TestReadConfigFilesSingleINI(t *testing.T) {
+	dir := t.TempDir()
+	content := "[Global]\nuser = user\n"
+	writeConfigFiles(t, dir, map[string]string{"vsphere.conf": content})
+
+	byConfig, err := ReadConfigFiles(filepath.Join(dir, "vsphere.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(byConfig) != content {
+		t.Errorf("expected a single file to be returned as is, got %q", byConfig)
+	}
+}
+
+func TestReadConfigFilesErrors(t *testing.T) {
+	testcases := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{
+			name: "INI",
+			files: map[string]string{
+				"a.yaml": "global:\n  user: user\n",
+				"b.conf": "[Global]\nuser = user\n",
+			},
+			err: "only YAML cloud configs can be merged",
+		},
+		{
+			name: "Type",
+			files: map[string]string{
+				"a.yaml": "global:\n  user: user\n",
+				"b.yaml": "global: user\n",
+			},
+			err: "only YAML cloud configs can be merged",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tc.files)
+			_, err := ReadConfigFiles(dir)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMergeConfigConflict(t *testing.T) {
+	merged := map[interface{}]interface{}{}
+	origins := map[string]string{}
+	section := map[interface{}]interface{}{"labels": map[interface{}]interface{}{"zone": "k8s-zone"}}
+	if err := mergeConfig(merged, section, "", "a.yaml", origins); err != nil {
+		t.Fatal(err)
+	}
+	value := map[interface{}]interface{}{"labels": "k8s-zone"}
+	err := mergeConfig(merged, value, "", "b.yaml", origins)
+	if err == nil || !strings.Contains(err.Error(), "labels conflicts with a.yaml") {
+		t.Errorf("expected a conflict with a.yaml, got %v", err)
+	}
+}
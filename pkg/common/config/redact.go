@@ -0,0 +1,39 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// redact returns RedactedValue in place of a secret that is set.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+// Redacted returns a copy of the config whose passwords are replaced by
+// RedactedValue, to be logged or dumped.
+func (cfg *Config) Redacted() *Config {
+	redacted := *cfg
+	redacted.Global.Password = redact(cfg.Global.Password)
+	redacted.VirtualCenter = make(map[string]*VirtualCenterConfig, len(cfg.VirtualCenter))
+	for tenantRef, vcConfig := range cfg.VirtualCenter {
+		vc := *vcConfig
+		vc.Password = redact(vcConfig.Password)
+		redacted.VirtualCenter[tenantRef] = &vc
+	}
+	return &redacted
+}
@@ -0,0 +1,367 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v2"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// StrictConfigWarn logs the unknown sections and keys of the cloud config.
+	StrictConfigWarn = "warn"
+	// StrictConfigError fails reading a cloud config with unknown sections or keys.
+	StrictConfigError = "error"
+)
+
+/*
+	The cloud config is a single file read by several packages (the common
+	config, the CPI nodes, NSX-T, load balancer and route configs), each one
+	ignoring the sections of the others. A key is therefore only unknown if
+	none of the structs registered with RegisterConfigSchema declares it.
+*/
+
+var (
+	schemaLock  sync.Mutex
+	iniSchemas  []reflect.Type
+	yamlSchemas []reflect.Type
+)
+
+func init() {
+	RegisterConfigSchema(CommonConfigINI{}, CommonConfigYAML{})
+}
+
+// RegisterConfigSchema registers the INI and YAML structs a package reads
+// from the cloud config, so that their sections and keys are not reported
+// as unknown.
+func RegisterConfigSchema(iniConfig, yamlConfig interface{}) {
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+
+	iniSchemas = append(iniSchemas, reflect.TypeOf(iniConfig))
+	yamlSchemas = append(yamlSchemas, reflect.TypeOf(yamlConfig))
+}
+
+// CheckUnknownKeys reports the sections and keys of the cloud config that
+// are not declared by any registered struct, with the closest known key as
+// a suggestion. They are logged as warnings unless mode is
+// StrictConfigError, in which case an error is returned.
+func CheckUnknownKeys(byConfig []byte, mode string) error {
+	switch mode {
+	case "", StrictConfigWarn, StrictConfigError:
+	default:
+		return getError(fmt.Sprintf("Invalid strict config mode %q, must be %q or %q", mode, StrictConfigWarn, StrictConfigError))
+	}
+
+	var unknown []string
+	if isConfigYaml(byConfig) == nil {
+		var err error
+		if unknown, err = unknownKeysYAML(byConfig); err != nil {
+			return err
+		}
+	} else {
+		unknown = unknownKeysINI(byConfig)
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+	if mode == StrictConfigError {
+		return getError("Unknown cloud config keys: " + strings.Join(unknown, "; "))
+	}
+	for _, u := range unknown {
+		klog.Warningf("Ignoring %s", u)
+	}
+	return nil
+}
+
+// keyNode holds the keys accepted under a config key. A nil *keyNode
+// accepts any value.
+type keyNode struct {
+	// keys maps the normalized names of the declared keys to their nodes.
+	keys map[string]*keyNode
+	// names maps the normalized names of the declared keys to their names
+	// as written in the config, for suggestions.
+	names map[string]string
+	// elem is the node of every value of a map keyed by user defined names,
+	// such as the VirtualCenter sections.
+	elem *keyNode
+}
+
+func newKeyNode() *keyNode {
+	return &keyNode{
+		keys:  make(map[string]*keyNode),
+		names: make(map[string]string),
+	}
+}
+
+// merge adds the keys of other to n. Merging a nil node, which accepts
+// anything, is handled by the callers.
+func (n *keyNode) merge(other *keyNode) {
+	for k, child := range other.keys {
+		existing, ok := n.keys[k]
+		switch {
+		case !ok:
+			n.keys[k] = child
+			n.names[k] = other.names[k]
+		case existing != nil && child != nil:
+			existing.merge(child)
+		default:
+			n.keys[k] = nil
+		}
+	}
+	if other.elem != nil {
+		if n.elem == nil {
+			n.elem = newKeyNode()
+		}
+		n.elem.merge(other.elem)
+	}
+}
+
+// suggest returns the declared key closest to name, or "" if none is close.
+func (n *keyNode) suggest(name string) string {
+	best, bestDistance := "", len(name)/3+1
+	var candidates []string
+	for _, k := range n.names {
+		candidates = append(candidates, k)
+	}
+	sort.Strings(candidates)
+	for _, k := range candidates {
+		if d := levenshtein(strings.ToLower(name), strings.ToLower(k)); d <= bestDistance && (best == "" || d < bestDistance) {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+// schemaKeys returns the keys declared by a struct type. name returns the
+// normalized and written name of a field, or false if it is not read.
+func schemaKeys(t reflect.Type, name func(reflect.StructField) (string, string, bool)) *keyNode {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	n := newKeyNode()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		normalized, written, ok := name(f)
+		if !ok {
+			continue
+		}
+		if normalized == "" && f.Anonymous {
+			n.merge(schemaKeys(f.Type, name))
+			continue
+		}
+		n.keys[normalized] = valueKeys(f.Type, name)
+		n.names[normalized] = written
+	}
+	return n
+}
+
+// valueKeys returns the node of a field value: the keys of a struct, the
+// keys of every value of a map of structs, or nil for any other value.
+func valueKeys(t reflect.Type, name func(reflect.StructField) (string, string, bool)) *keyNode {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return schemaKeys(t, name)
+	case reflect.Map:
+		if elem := valueKeys(t.Elem(), name); elem != nil {
+			n := newKeyNode()
+			n.elem = elem
+			return n
+		}
+	}
+	return nil
+}
+
+func schemaNode(types []reflect.Type, name func(reflect.StructField) (string, string, bool)) *keyNode {
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+
+	n := newKeyNode()
+	for _, t := range types {
+		n.merge(schemaKeys(t, name))
+	}
+	return n
+}
+
+// yamlName follows gopkg.in/yaml.v2: the tag name or the lowercased field
+// name, embedded structs being only inlined with the inline flag. Embedded
+// structs are inlined here either way since their keys are declared.
+func yamlName(f reflect.StructField) (string, string, bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", "", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if f.Anonymous && (name == "" || strings.Contains(tag, ",inline")) {
+		return "", "", true
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name, name, true
+}
+
+// iniName follows gopkg.in/gcfg.v1: the tag name or the field name, case
+// insensitively, dashes in the config matching underscores in field names.
+func iniName(f reflect.StructField) (string, string, bool) {
+	if f.Anonymous {
+		return "", "", true
+	}
+	name := strings.Split(f.Tag.Get("gcfg"), ",")[0]
+	if name == "" {
+		name = f.Name
+	}
+	return strings.ToLower(name), name, true
+}
+
+func unknownKeysYAML(byConfig []byte) ([]string, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(byConfig, &doc); err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	var walk func(path string, n *keyNode, value interface{})
+	walk = func(path string, n *keyNode, value interface{}) {
+		if n == nil {
+			return
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				walk(path, n, item)
+			}
+		case yaml.MapSlice:
+			for _, item := range v {
+				key := fmt.Sprint(item.Key)
+				keyPath := key
+				if path != "" {
+					keyPath = path + "." + key
+				}
+				if n.elem != nil {
+					walk(keyPath, n.elem, item.Value)
+					continue
+				}
+				child, ok := n.keys[key]
+				if !ok {
+					unknown = append(unknown, unknownKey(fmt.Sprintf("key %q", keyPath), n.suggest(key)))
+					continue
+				}
+				walk(keyPath, child, item.Value)
+			}
+		}
+	}
+	walk("", schemaNode(yamlSchemas, yamlName), doc)
+	return unknown, nil
+}
+
+func unknownKeysINI(byConfig []byte) []string {
+	root := schemaNode(iniSchemas, iniName)
+
+	var unknown []string
+	var section *keyNode
+	var sectionName string
+	known := false
+	continued := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(byConfig))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		wasContinued := continued
+		continued = strings.HasSuffix(line, `\`)
+		if wasContinued || line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			header := strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+			sectionName = strings.TrimSpace(strings.SplitN(header, " ", 2)[0])
+			hasSubsection := strings.Contains(header, `"`)
+			node, ok := root.keys[strings.ToLower(sectionName)]
+			known = ok
+			section = node
+			if section != nil && hasSubsection && section.elem != nil {
+				section = section.elem
+			}
+			if !ok {
+				unknown = append(unknown, unknownKey(fmt.Sprintf("section %q at line %d", sectionName, lineNumber), root.suggest(sectionName)))
+			}
+			continue
+		}
+
+		if !known || section == nil {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == '=' || r == ' ' || r == '\t' })
+		if len(fields) == 0 {
+			continue
+		}
+		name := fields[0]
+		if _, ok := section.keys[strings.ToLower(name)]; ok {
+			continue
+		}
+		if _, ok := section.keys[strings.ToLower(strings.ReplaceAll(name, "-", "_"))]; ok {
+			continue
+		}
+		unknown = append(unknown, unknownKey(fmt.Sprintf("key %q in section %q at line %d", name, sectionName, lineNumber), section.suggest(name)))
+	}
+	return unknown
+}
+
+func unknownKey(what, suggestion string) string {
+	if suggestion == "" {
+		return "unknown " + what
+	}
+	return fmt.Sprintf("unknown %s, did you mean %q?", what, suggestion)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
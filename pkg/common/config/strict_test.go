@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+type extraINI struct {
+	Extra struct {
+		Name string `gcfg:"extra-name"`
+	}
+}
+
+type extraYAML struct {
+	Extra struct {
+		Name string `yaml:"extraName"`
+	} `yaml:"extra"`
+}
+
+func init() {
+	RegisterConfigSchema(extraINI{}, extraYAML{})
+}
+
+func TestUnknownKeysINI(t *testing.T) {
+	unknown := unknownKeysINI([]byte(`
+; comment
+[Global]
+user = user
+password = password
+insecure-flag
+srever = 0.0.0.0
+
+[VirtualCenter "10.0.0.1"]
+datacenters = "vic0dc"
+datacenter = "vic0dc"
+
+[Extra]
+extra-name = "value"
+
+[Labelz]
+zone = "k8s-zone"
+`))
+
+	expected := []string{
+		`unknown key "srever" in section "Global" at line 7, did you mean "server"?`,
+		`unknown key "datacenter" in section "VirtualCenter" at line 11, did you mean "datacenters"?`,
+		`unknown section "Labelz" at line 16, did you mean "Labels"?`,
+	}
+	if strings.Join(unknown, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected unknown keys:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(unknown, "\n"))
+	}
+}
+
+func TestUnknownKeysYAML(t *testing.T) {
+	unknown, err := unknownKeysYAML([]byte(`
+global:
+  user: user
+  password: password
+  insecureflag: true
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    datacenters:
+      - vic0dc
+    tumbprint: 00:11
+
+extra:
+  extraName: value
+
+completelyUnrelated: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`unknown key "global.insecureflag", did you mean "insecureFlag"?`,
+		`unknown key "vcenter.tenant1.tumbprint", did you mean "thumbprint"?`,
+		`unknown key "completelyUnrelated"`,
+	}
+	if strings.Join(unknown, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected unknown keys:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(unknown, "\n"))
+	}
+}
+
+func TestReadConfigStrict(t *testing.T) {
+	config := `
+global:
+  server: 0.0.0.0
+  port: 443
+  user: user
+  password: password
+  datacenters:
+    - us-west
+  insecureFlag: true
+  strictConfig: %s
+
+vcenter:
+  tenant1:
+    server: 10.0.0.1
+    tumbprint: 00:11
+`
+
+	if _, err := ReadConfig([]byte(strings.ReplaceAll(config, "%s", StrictConfigWarn))); err != nil {
+		t.Errorf("Should only warn about unknown keys: %s", err)
+	}
+
+	_, err := ReadConfig([]byte(strings.ReplaceAll(config, "%s", StrictConfigError)))
+	if err == nil || !strings.Contains(err.Error(), `did you mean "thumbprint"?`) {
+		t.Errorf("Should fail on unknown keys, got: %v", err)
+	}
+
+	if _, err := ReadConfig([]byte(strings.ReplaceAll(config, "%s", "bogus"))); err == nil {
+		t.Error("Should fail on an invalid strict config mode")
+	}
+
+	t.Setenv("VSPHERE_STRICT_CONFIG", StrictConfigError)
+	if _, err := ReadConfig([]byte(strings.ReplaceAll(config, "%s", StrictConfigWarn))); err == nil {
+		t.Error("Should fail on unknown keys when strict config is set in the environment")
+	}
+}
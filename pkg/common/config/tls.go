@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// tlsVersions are the supported values of a TLS minimum version
+var tlsVersions = map[string]uint16{
+	"1.0":          tls.VersionTLS10,
+	"1.1":          tls.VersionTLS11,
+	"1.2":          tls.VersionTLS12,
+	"1.3":          tls.VersionTLS13,
+	"VersionTLS10": tls.VersionTLS10,
+	"VersionTLS11": tls.VersionTLS11,
+	"VersionTLS12": tls.VersionTLS12,
+	"VersionTLS13": tls.VersionTLS13,
+}
+
+// ParseTLSMinVersion parses a TLS minimum version such as 1.2 or
+// VersionTLS12, 0 for the Go default if the value is empty.
+func ParseTLSMinVersion(value string) (uint16, error) {
+	if value == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[strings.TrimSpace(value)]
+	if !ok {
+		return 0, fmt.Errorf("invalid TLS minimum version %q, must be 1.0, 1.1, 1.2 or 1.3", value)
+	}
+	return version, nil
+}
+
+// ParseTLSCipherSuites parses a comma separated list of cipher suites named
+// by their IANA names, such as TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, nil
+// for the Go defaults if the value is empty. Cipher suites with known security
+// issues are rejected. The cipher suites only apply up to TLS 1.2.
+func ParseTLSCipherSuites(value string) ([]uint16, error) {
+	suites := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid or insecure TLS cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ValidateTLSSettings checks the TLS minimum versions and cipher suites of
+// the global section and of the vCenters.
+func (cfg *Config) ValidateTLSSettings() error {
+	if err := validateTLSSettings(cfg.Global.TLSMinVersion, cfg.Global.TLSCipherSuites); err != nil {
+		return err
+	}
+	for tenantRef, vcConfig := range cfg.VirtualCenter {
+		if err := validateTLSSettings(vcConfig.TLSMinVersion, vcConfig.TLSCipherSuites); err != nil {
+			return fmt.Errorf("vCenter %s: %w", tenantRef, err)
+		}
+	}
+	return nil
+}
+
+func validateTLSSettings(minVersion, cipherSuites string) error {
+	if _, err := ParseTLSMinVersion(minVersion); err != nil {
+		return err
+	}
+	_, err := ParseTLSCipherSuites(cipherSuites)
+	return err
+}
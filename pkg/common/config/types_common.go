@@ -37,6 +37,9 @@ type Global struct {
 	InsecureFlag bool
 	// Datacenter in which VMs are located.
 	Datacenters string
+	// Managed object IDs, such as datacenter-3, of datacenters in which VMs
+	// are located, which keep resolving when the datacenters are renamed.
+	DatacenterMoids string
 	// Soap round tripper count (retries = RoundTripper - 1)
 	RoundTripperCount uint
 	// Specifies the path to a CA certificate in PEM format. Optional; if not
@@ -44,6 +47,40 @@ type Global struct {
 	CAFile string
 	// Thumbprint of the VCenter's certificate thumbprint
 	Thumbprint string
+	// SSO identity source (domain) of the vCenter user.
+	IdentitySource string
+	// User agent sent to vCenter, identifying the sessions of this cluster.
+	UserAgent string
+	// How a VM found in a datacenter that is not listed is handled, either
+	// "reject" (default), "warn-and-accept" or "rescope".
+	UnlistedDatacenterPolicy string
+	// Minimum TLS version of the connections to vCenter, 1.0, 1.1, 1.2 or
+	// 1.3. Defaults to the Go default.
+	TLSMinVersion string
+	// TLS cipher suites of the connections to vCenter up to TLS 1.2, by
+	// their IANA names. Defaults to the Go defaults.
+	TLSCipherSuites string
+	// Networks, by name or managed object ID such as dvportgroup-42, and
+	// datastores whose VMs are searched first when a node is discovered by
+	// IP address, before the search index of the whole datacenter.
+	IPSearchNetworks   string
+	IPSearchDatastores string
+	// Maximum number of concurrent searches and property collections issued
+	// to vCenter when discovering nodes, 0 for no limit.
+	MaxConcurrentQueries uint
+	// Maximum rate of the API requests to vCenter, in requests per second,
+	// 0 for no limit.
+	APIQPS uint
+	// Number of API requests to vCenter that can be issued at once above
+	// APIQPS. Defaults to APIQPS.
+	APIBurst uint
+	// Endpoints of the vCenter, as host or host:port, tried in order after
+	// the server when it is unreachable, such as the vCenter of another site.
+	Endpoints string
+	// DNS SRV record, such as _vcenter._tcp.example.com, resolving the
+	// endpoints of the vCenter tried, in the order of their priority, when
+	// the server is unreachable. Cannot be combined with Endpoints.
+	EndpointSRV string
 	// Name of the secret were vCenter credentials are present.
 	SecretName string
 	// Secret Namespace where secret will be present that has vCenter credentials.
@@ -53,6 +90,21 @@ type Global struct {
 	// 2) we are not in a k8s env, namely DC/OS, since CSI is CO agnostic
 	// Default: /etc/cloud/credentials
 	SecretsDirectory string
+	// How unknown sections and keys of the cloud config are reported, either
+	// "warn" (default) or "error".
+	StrictConfig string
+	// Label selector of the ConfigMaps holding the cloud configs of tenants,
+	// whose vCenters are added to the ones of the cloud config at runtime.
+	// Tenant ConfigMaps are not watched if empty.
+	TenantConfigMapSelector string
+	// Namespace of the tenant ConfigMaps. Defaults to kube-system.
+	TenantConfigMapNamespace string
+	// Period at which the vCenter sessions are checked and logged in again
+	// when they are no longer active. Defaults to 5m, 0 disables the checks.
+	SessionKeepAlivePeriod string
+	// Whether the properties of the VMs are watched and cached, so that
+	// discovering a node does not query vCenter.
+	CacheVMProperties bool
 }
 
 // VirtualCenterConfig struct
@@ -75,6 +127,9 @@ type VirtualCenterConfig struct {
 	InsecureFlag bool
 	// Datacenter in which VMs are located.
 	Datacenters string
+	// Managed object IDs, such as datacenter-3, of datacenters in which VMs
+	// are located, which keep resolving when the datacenters are renamed.
+	DatacenterMoids string
 	// Soap round tripper count (retries = RoundTripper - 1)
 	RoundTripperCount uint
 	// Specifies the path to a CA certificate in PEM format. Optional; if not
@@ -82,6 +137,49 @@ type VirtualCenterConfig struct {
 	CAFile string
 	// Thumbprint of the VCenter's certificate thumbprint
 	Thumbprint string
+	// SSO identity source (domain) of the vCenter user. When set, a user name
+	// without a domain is qualified with it and the session is created from a
+	// SAML token issued for that identity source.
+	IdentitySource string
+	// User agent sent to vCenter, identifying the sessions of this cluster.
+	// Defaults to one naming the cloud provider, its version and the cluster.
+	UserAgent string
+	// How a VM found in a datacenter that is not listed is handled, either
+	// "reject" (default), "warn-and-accept" or "rescope".
+	UnlistedDatacenterPolicy string
+	// Minimum TLS version of the connections to vCenter, 1.0, 1.1, 1.2 or
+	// 1.3. Defaults to the Go default.
+	TLSMinVersion string
+	// TLS cipher suites of the connections to vCenter up to TLS 1.2, by
+	// their IANA names. Defaults to the Go defaults.
+	TLSCipherSuites string
+	// Networks, by name or managed object ID such as dvportgroup-42, and
+	// datastores whose VMs are searched first when a node is discovered by
+	// IP address, before the search index of the whole datacenter.
+	IPSearchNetworks   string
+	IPSearchDatastores string
+	// Maximum number of concurrent searches and property collections issued
+	// to vCenter when discovering nodes, 0 for no limit.
+	MaxConcurrentQueries uint
+	// Maximum rate of the API requests to vCenter, in requests per second,
+	// 0 for no limit.
+	APIQPS uint
+	// Number of API requests to vCenter that can be issued at once above
+	// APIQPS. Defaults to APIQPS.
+	APIBurst uint
+	// Endpoints of the vCenter, as host or host:port, tried in order after
+	// the server when it is unreachable, such as the vCenter of another site.
+	Endpoints string
+	// DNS SRV record, such as _vcenter._tcp.example.com, resolving the
+	// endpoints of the vCenter tried, in the order of their priority, when
+	// the server is unreachable. Cannot be combined with Endpoints.
+	EndpointSRV string
+	// Region of the nodes of the vCenter, such as the site it manages, used
+	// instead of the tags of the region category.
+	Region string
+	// Tag category of the zones of the nodes of the vCenter, used instead of
+	// the zone category of the Labels section.
+	ZoneCategoryOverride string
 	// SecretRef (intentionally not exposed via the config) is a key to identify which
 	// InformerManager holds the secret
 	SecretRef string
@@ -102,6 +200,9 @@ type Labels struct {
 	Zone string
 	// Region describes a region
 	Region string
+	// Disabled skips the zone and region discovery, so that nodes get no
+	// topology labels even if Zone and Region are set
+	Disabled bool
 }
 
 // Config is used to read and store information from the cloud configuration file
@@ -36,6 +36,8 @@ type GlobalINI struct {
 	InsecureFlag bool `gcfg:"insecure-flag"`
 	// Datacenter in which VMs are located.
 	Datacenters string `gcfg:"datacenters"`
+	// Managed object IDs of datacenters in which VMs are located.
+	DatacenterMoids string `gcfg:"datacenter-moids"`
 	// Soap round tripper count (retries = RoundTripper - 1)
 	RoundTripperCount uint `gcfg:"soap-roundtrip-count"`
 	// Specifies the path to a CA certificate in PEM format. Optional; if not
@@ -43,6 +45,40 @@ type GlobalINI struct {
 	CAFile string `gcfg:"ca-file"`
 	// Thumbprint of the VCenter's certificate thumbprint
 	Thumbprint string `gcfg:"thumbprint"`
+	// SSO identity source (domain) of the vCenter user.
+	IdentitySource string `gcfg:"identity-source"`
+	// User agent sent to vCenter, identifying the sessions of this cluster.
+	UserAgent string `gcfg:"user-agent"`
+	// How a VM found in a datacenter that is not listed is handled, either
+	// "reject" (default), "warn-and-accept" or "rescope".
+	UnlistedDatacenterPolicy string `gcfg:"unlisted-datacenter-policy"`
+	// Minimum TLS version of the connections to vCenter, 1.0, 1.1, 1.2 or
+	// 1.3. Defaults to the Go default.
+	TLSMinVersion string `gcfg:"tls-min-version"`
+	// TLS cipher suites of the connections to vCenter up to TLS 1.2, by
+	// their IANA names. Defaults to the Go defaults.
+	TLSCipherSuites string `gcfg:"tls-cipher-suites"`
+	// Networks, by name or managed object ID such as dvportgroup-42, and
+	// datastores whose VMs are searched first when a node is discovered by
+	// IP address, before the search index of the whole datacenter.
+	IPSearchNetworks   string `gcfg:"ip-search-networks"`
+	IPSearchDatastores string `gcfg:"ip-search-datastores"`
+	// Maximum number of concurrent searches and property collections issued
+	// to vCenter when discovering nodes, 0 for no limit.
+	MaxConcurrentQueries uint `gcfg:"max-concurrent-queries"`
+	// Maximum rate of the API requests to vCenter, in requests per second,
+	// 0 for no limit.
+	APIQPS uint `gcfg:"api-qps"`
+	// Number of API requests to vCenter that can be issued at once above
+	// APIQPS. Defaults to APIQPS.
+	APIBurst uint `gcfg:"api-burst"`
+	// Endpoints of the vCenter, as host or host:port, tried in order after
+	// the server when it is unreachable, such as the vCenter of another site.
+	Endpoints string `gcfg:"endpoints"`
+	// DNS SRV record, such as _vcenter._tcp.example.com, resolving the
+	// endpoints of the vCenter tried, in the order of their priority, when
+	// the server is unreachable. Cannot be combined with Endpoints.
+	EndpointSRV string `gcfg:"endpoint-srv"`
 	// Name of the secret were vCenter credentials are present.
 	SecretName string `gcfg:"secret-name"`
 	// Secret Namespace where secret will be present that has vCenter credentials.
@@ -63,6 +99,19 @@ type GlobalINI struct {
 	// ipv4 - IPv4 addresses only (Default)
 	// ipv6 - IPv6 addresses only
 	IPFamily string `gcfg:"ip-family"`
+	// How unknown sections and keys of the cloud config are reported, either
+	// "warn" (default) or "error".
+	StrictConfig string `gcfg:"strict-config"`
+	// Label selector of the ConfigMaps holding the cloud configs of tenants
+	TenantConfigMapSelector string `gcfg:"tenant-configmap-selector"`
+	// Namespace of the tenant ConfigMaps
+	// Default: kube-system
+	TenantConfigMapNamespace string `gcfg:"tenant-configmap-namespace"`
+	// Period at which the vCenter sessions are checked, 0 disables the checks
+	// Default: 5m
+	SessionKeepAlivePeriod string `gcfg:"session-keep-alive-period"`
+	// Whether the properties of the VMs are watched and cached
+	CacheVMProperties bool `gcfg:"cache-vm-properties"`
 }
 
 // VirtualCenterConfigINI contains information used to access a remote vCenter
@@ -86,6 +135,8 @@ type VirtualCenterConfigINI struct {
 	InsecureFlag bool `gcfg:"insecure-flag"`
 	// Datacenter in which VMs are located.
 	Datacenters string `gcfg:"datacenters"`
+	// Managed object IDs of datacenters in which VMs are located.
+	DatacenterMoids string `gcfg:"datacenter-moids"`
 	// Soap round tripper count (retries = RoundTripper - 1)
 	RoundTripperCount uint `gcfg:"soap-roundtrip-count"`
 	// Specifies the path to a CA certificate in PEM format. Optional; if not
@@ -93,6 +144,46 @@ type VirtualCenterConfigINI struct {
 	CAFile string `gcfg:"ca-file"`
 	// Thumbprint of the VCenter's certificate thumbprint
 	Thumbprint string `gcfg:"thumbprint"`
+	// SSO identity source (domain) of the vCenter user.
+	IdentitySource string `gcfg:"identity-source"`
+	// User agent sent to vCenter, identifying the sessions of this cluster.
+	UserAgent string `gcfg:"user-agent"`
+	// How a VM found in a datacenter that is not listed is handled, either
+	// "reject" (default), "warn-and-accept" or "rescope".
+	UnlistedDatacenterPolicy string `gcfg:"unlisted-datacenter-policy"`
+	// Minimum TLS version of the connections to vCenter, 1.0, 1.1, 1.2 or
+	// 1.3. Defaults to the Go default.
+	TLSMinVersion string `gcfg:"tls-min-version"`
+	// TLS cipher suites of the connections to vCenter up to TLS 1.2, by
+	// their IANA names. Defaults to the Go defaults.
+	TLSCipherSuites string `gcfg:"tls-cipher-suites"`
+	// Networks, by name or managed object ID such as dvportgroup-42, and
+	// datastores whose VMs are searched first when a node is discovered by
+	// IP address, before the search index of the whole datacenter.
+	IPSearchNetworks   string `gcfg:"ip-search-networks"`
+	IPSearchDatastores string `gcfg:"ip-search-datastores"`
+	// Maximum number of concurrent searches and property collections issued
+	// to vCenter when discovering nodes, 0 for no limit.
+	MaxConcurrentQueries uint `gcfg:"max-concurrent-queries"`
+	// Maximum rate of the API requests to vCenter, in requests per second,
+	// 0 for no limit.
+	APIQPS uint `gcfg:"api-qps"`
+	// Number of API requests to vCenter that can be issued at once above
+	// APIQPS. Defaults to APIQPS.
+	APIBurst uint `gcfg:"api-burst"`
+	// Endpoints of the vCenter, as host or host:port, tried in order after
+	// the server when it is unreachable, such as the vCenter of another site.
+	Endpoints string `gcfg:"endpoints"`
+	// DNS SRV record, such as _vcenter._tcp.example.com, resolving the
+	// endpoints of the vCenter tried, in the order of their priority, when
+	// the server is unreachable. Cannot be combined with Endpoints.
+	EndpointSRV string `gcfg:"endpoint-srv"`
+	// Region of the nodes of the vCenter, used instead of the tags of the
+	// region category.
+	Region string `gcfg:"region"`
+	// Tag category of the zones of the nodes of the vCenter, used instead of
+	// the zone category of the Labels section.
+	ZoneCategoryOverride string `gcfg:"zone-category-override"`
 	// SecretRef (intentionally not exposed via the config) is a key to identify which
 	// InformerManager holds the secret
 	SecretRef string
@@ -111,8 +202,9 @@ type VirtualCenterConfigINI struct {
 
 // LabelsINI tags categories and tags which correspond to "built-in node labels: zones and region"
 type LabelsINI struct {
-	Zone   string `gcfg:"zone"`
-	Region string `gcfg:"region"`
+	Zone     string `gcfg:"zone"`
+	Region   string `gcfg:"region"`
+	Disabled bool   `gcfg:"disabled"`
 }
 
 // CommonConfigINI is used to read and store information from the cloud configuration file
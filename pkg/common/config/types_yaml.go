@@ -42,6 +42,8 @@ type GlobalYAML struct {
 	InsecureFlag bool `yaml:"insecureFlag"`
 	// Datacenter in which VMs are located.
 	Datacenters []string `yaml:"datacenters"`
+	// Managed object IDs of datacenters in which VMs are located.
+	DatacenterMoids []string `yaml:"datacenterMoids"`
 	// Soap round tripper count (retries = RoundTripper - 1)
 	RoundTripperCount uint `yaml:"soapRoundtripCount"`
 	// Specifies the path to a CA certificate in PEM format. Optional; if not
@@ -49,6 +51,40 @@ type GlobalYAML struct {
 	CAFile string `yaml:"caFile"`
 	// Thumbprint of the VCenter's certificate thumbprint
 	Thumbprint string `yaml:"thumbprint"`
+	// SSO identity source (domain) of the vCenter user.
+	IdentitySource string `yaml:"identitySource"`
+	// User agent sent to vCenter, identifying the sessions of this cluster.
+	UserAgent string `yaml:"userAgent"`
+	// How a VM found in a datacenter that is not listed is handled, either
+	// "reject" (default), "warn-and-accept" or "rescope".
+	UnlistedDatacenterPolicy string `yaml:"unlistedDatacenterPolicy"`
+	// Minimum TLS version of the connections to vCenter, 1.0, 1.1, 1.2 or
+	// 1.3. Defaults to the Go default.
+	TLSMinVersion string `yaml:"tlsMinVersion"`
+	// TLS cipher suites of the connections to vCenter up to TLS 1.2, by
+	// their IANA names. Defaults to the Go defaults.
+	TLSCipherSuites []string `yaml:"tlsCipherSuites"`
+	// Networks, by name or managed object ID such as dvportgroup-42, and
+	// datastores whose VMs are searched first when a node is discovered by
+	// IP address, before the search index of the whole datacenter.
+	IPSearchNetworks   []string `yaml:"ipSearchNetworks"`
+	IPSearchDatastores []string `yaml:"ipSearchDatastores"`
+	// Maximum number of concurrent searches and property collections issued
+	// to vCenter when discovering nodes, 0 for no limit.
+	MaxConcurrentQueries uint `yaml:"maxConcurrentQueries"`
+	// Maximum rate of the API requests to vCenter, in requests per second,
+	// 0 for no limit.
+	APIQPS uint `yaml:"apiQPS"`
+	// Number of API requests to vCenter that can be issued at once above
+	// APIQPS. Defaults to APIQPS.
+	APIBurst uint `yaml:"apiBurst"`
+	// Endpoints of the vCenter, as host or host:port, tried in order after
+	// the server when it is unreachable, such as the vCenter of another site.
+	Endpoints []string `yaml:"endpoints"`
+	// DNS SRV record, such as _vcenter._tcp.example.com, resolving the
+	// endpoints of the vCenter tried, in the order of their priority, when
+	// the server is unreachable. Cannot be combined with Endpoints.
+	EndpointSRV string `yaml:"endpointSRV"`
 	// Name of the secret were vCenter credentials are present.
 	SecretName string `yaml:"secretName"`
 	// Secret Namespace where secret will be present that has vCenter credentials.
@@ -69,6 +105,19 @@ type GlobalYAML struct {
 	// ipv4 - IPv4 addresses only (Default)
 	// ipv6 - IPv6 addresses only
 	IPFamilyPriority []string `yaml:"ipFamily"`
+	// How unknown sections and keys of the cloud config are reported, either
+	// "warn" (default) or "error".
+	StrictConfig string `yaml:"strictConfig"`
+	// Label selector of the ConfigMaps holding the cloud configs of tenants
+	TenantConfigMapSelector string `yaml:"tenantConfigMapSelector"`
+	// Namespace of the tenant ConfigMaps
+	// Default: kube-system
+	TenantConfigMapNamespace string `yaml:"tenantConfigMapNamespace"`
+	// Period at which the vCenter sessions are checked, 0 disables the checks
+	// Default: 5m
+	SessionKeepAlivePeriod string `yaml:"sessionKeepAlivePeriod"`
+	// Whether the properties of the VMs are watched and cached
+	CacheVMProperties bool `yaml:"cacheVMProperties"`
 }
 
 // VirtualCenterConfigYAML contains information used to access a remote vCenter
@@ -92,6 +141,8 @@ type VirtualCenterConfigYAML struct {
 	InsecureFlag bool `yaml:"insecureFlag"`
 	// Datacenter in which VMs are located.
 	Datacenters []string `yaml:"datacenters"`
+	// Managed object IDs of datacenters in which VMs are located.
+	DatacenterMoids []string `yaml:"datacenterMoids"`
 	// Soap round tripper count (retries = RoundTripper - 1)
 	RoundTripperCount uint `yaml:"soapRoundtripCount"`
 	// Specifies the path to a CA certificate in PEM format. Optional; if not
@@ -99,6 +150,46 @@ type VirtualCenterConfigYAML struct {
 	CAFile string `yaml:"caFile"`
 	// Thumbprint of the VCenter's certificate thumbprint
 	Thumbprint string `yaml:"thumbprint"`
+	// SSO identity source (domain) of the vCenter user.
+	IdentitySource string `yaml:"identitySource"`
+	// User agent sent to vCenter, identifying the sessions of this cluster.
+	UserAgent string `yaml:"userAgent"`
+	// How a VM found in a datacenter that is not listed is handled, either
+	// "reject" (default), "warn-and-accept" or "rescope".
+	UnlistedDatacenterPolicy string `yaml:"unlistedDatacenterPolicy"`
+	// Minimum TLS version of the connections to vCenter, 1.0, 1.1, 1.2 or
+	// 1.3. Defaults to the Go default.
+	TLSMinVersion string `yaml:"tlsMinVersion"`
+	// TLS cipher suites of the connections to vCenter up to TLS 1.2, by
+	// their IANA names. Defaults to the Go defaults.
+	TLSCipherSuites []string `yaml:"tlsCipherSuites"`
+	// Networks, by name or managed object ID such as dvportgroup-42, and
+	// datastores whose VMs are searched first when a node is discovered by
+	// IP address, before the search index of the whole datacenter.
+	IPSearchNetworks   []string `yaml:"ipSearchNetworks"`
+	IPSearchDatastores []string `yaml:"ipSearchDatastores"`
+	// Maximum number of concurrent searches and property collections issued
+	// to vCenter when discovering nodes, 0 for no limit.
+	MaxConcurrentQueries uint `yaml:"maxConcurrentQueries"`
+	// Maximum rate of the API requests to vCenter, in requests per second,
+	// 0 for no limit.
+	APIQPS uint `yaml:"apiQPS"`
+	// Number of API requests to vCenter that can be issued at once above
+	// APIQPS. Defaults to APIQPS.
+	APIBurst uint `yaml:"apiBurst"`
+	// Endpoints of the vCenter, as host or host:port, tried in order after
+	// the server when it is unreachable, such as the vCenter of another site.
+	Endpoints []string `yaml:"endpoints"`
+	// DNS SRV record, such as _vcenter._tcp.example.com, resolving the
+	// endpoints of the vCenter tried, in the order of their priority, when
+	// the server is unreachable. Cannot be combined with Endpoints.
+	EndpointSRV string `yaml:"endpointSRV"`
+	// Region of the nodes of the vCenter, used instead of the tags of the
+	// region category.
+	Region string `yaml:"region"`
+	// Tag category of the zones of the nodes of the vCenter, used instead of
+	// the zone category of the labels section.
+	ZoneCategoryOverride string `yaml:"zoneCategoryOverride"`
 	// SecretRef (intentionally not exposed via the config) is a key to identify which
 	// InformerManager holds the secret
 	SecretRef string
@@ -115,8 +206,9 @@ type VirtualCenterConfigYAML struct {
 
 // LabelsYAML tags categories and tags which correspond to "built-in node labels: zones and region"
 type LabelsYAML struct {
-	Zone   string `yaml:"zone"`
-	Region string `yaml:"region"`
+	Zone     string `yaml:"zone"`
+	Region   string `yaml:"region"`
+	Disabled bool   `yaml:"disabled"`
 }
 
 // CommonConfigYAML is used to read and store information from the cloud configuration file
@@ -22,12 +22,14 @@ import (
 
 	clientset "k8s.io/client-go/kubernetes"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
 	klog "k8s.io/klog/v2"
 
 	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	cm "k8s.io/cloud-provider-vsphere/pkg/common/credentialmanager"
 	k8s "k8s.io/cloud-provider-vsphere/pkg/common/kubernetes"
 	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // NewConnectionManager returns a new ConnectionManager object
@@ -43,70 +45,141 @@ func NewConnectionManager(cfg *vcfg.Config, informMgr *k8s.InformerManager, clie
 	}
 
 	if cfg.Global.SecretsDirectory != "" {
-		klog.V(2).Info("Initializing for generic CO with secrets")
+		logging.V(logging.ConnectionManager, 2).Info("Initializing for generic CO with secrets")
 		credMgr, _ := connMgr.createManagersPerTenant("", "", cfg.Global.SecretsDirectory, nil)
 		connMgr.credentialManagers[vcfg.DefaultCredentialManager] = credMgr
 
 		return connMgr
 	}
 	if informMgr != nil {
-		klog.V(2).Info("Initializing with K8s SecretLister")
+		logging.V(logging.ConnectionManager, 2).Info("Initializing with K8s SecretLister")
 		credMgr := cm.NewCredentialManager(cfg.Global.SecretName, cfg.Global.SecretNamespace, "", informMgr.GetSecretLister(cfg.Global.SecretNamespace))
 		connMgr.credentialManagers[vcfg.DefaultCredentialManager] = credMgr
 		connMgr.informerManagers[vcfg.DefaultCredentialManager] = informMgr
+		connMgr.watchCredentials(vcfg.DefaultCredentialManager, credMgr, informMgr.GetSecretInformer(cfg.Global.SecretNamespace))
 
 		return connMgr
 	}
 
-	klog.V(2).Info("Initializing generic CO")
+	logging.V(logging.ConnectionManager, 2).Info("Initializing generic CO")
 	credMgr := cm.NewCredentialManager("", "", "", nil)
 	connMgr.credentialManagers[vcfg.DefaultCredentialManager] = credMgr
 
 	return connMgr
 }
 
+// Instances returns the vCenters by their tenant ref. The vCenters of the
+// tenant configs replace the map instead of modifying it, so the returned map
+// may be ranged over without a lock but must not be modified.
+func (connMgr *ConnectionManager) Instances() map[string]*VSphereInstance {
+	connMgr.instancesLock.RLock()
+	defer connMgr.instancesLock.RUnlock()
+
+	return connMgr.VsphereInstanceMap
+}
+
+// setInstances replaces the vCenters by their tenant ref.
+func (connMgr *ConnectionManager) setInstances(instances map[string]*VSphereInstance) {
+	connMgr.instancesLock.Lock()
+	defer connMgr.instancesLock.Unlock()
+
+	connMgr.VsphereInstanceMap = instances
+}
+
 // generateInstanceMap creates a map of vCenter connection objects that can be
 // use to create a connection to a vCenter using vclib package
 func generateInstanceMap(cfg *vcfg.Config) map[string]*VSphereInstance {
 	vsphereInstanceMap := make(map[string]*VSphereInstance)
 
 	for _, vcConfig := range cfg.VirtualCenter {
-		vSphereConn := vclib.VSphereConnection{
-			Username:          vcConfig.User,
-			Password:          vcConfig.Password,
-			Hostname:          vcConfig.VCenterIP,
-			Insecure:          vcConfig.InsecureFlag,
-			RoundTripperCount: vcConfig.RoundTripperCount,
-			Port:              vcConfig.VCenterPort,
-			CACert:            vcConfig.CAFile,
-			Thumbprint:        vcConfig.Thumbprint,
-		}
-		vsphereIns := VSphereInstance{
-			Conn: &vSphereConn,
-			Cfg:  vcConfig,
-		}
-		vsphereInstanceMap[vcConfig.TenantRef] = &vsphereIns
+		vsphereInstanceMap[vcConfig.TenantRef] = newVSphereInstance(vcConfig)
 	}
 
 	return vsphereInstanceMap
 }
 
+// newVSphereInstance creates the vCenter connection object of vcConfig.
+func newVSphereInstance(vcConfig *vcfg.VirtualCenterConfig) *VSphereInstance {
+	// the settings are validated when the config is read
+	tlsMinVersion, err := vcfg.ParseTLSMinVersion(vcConfig.TLSMinVersion)
+	if err != nil {
+		klog.Errorf("vCenter %s: %v", vcConfig.VCenterIP, err)
+	}
+	tlsCipherSuites, err := vcfg.ParseTLSCipherSuites(vcConfig.TLSCipherSuites)
+	if err != nil {
+		klog.Errorf("vCenter %s: %v", vcConfig.VCenterIP, err)
+	}
+	vSphereConn := vclib.VSphereConnection{
+		Username:          vcConfig.User,
+		Password:          vcConfig.Password,
+		Hostname:          vcConfig.VCenterIP,
+		Insecure:          vcConfig.InsecureFlag,
+		RoundTripperCount: vcConfig.RoundTripperCount,
+		Port:              vcConfig.VCenterPort,
+		CACert:            vcConfig.CAFile,
+		Thumbprint:        vcConfig.Thumbprint,
+		IdentitySource:    vcConfig.IdentitySource,
+		UserAgent:         vcConfig.UserAgent,
+		TLSMinVersion:     tlsMinVersion,
+		TLSCipherSuites:   tlsCipherSuites,
+	}
+	klog.Infof("vCenter %s sessions use user agent %q", vcConfig.VCenterIP, vSphereConn.GetUserAgent())
+	if tlsMinVersion != 0 || len(tlsCipherSuites) > 0 {
+		klog.Infof("vCenter %s connections use TLS minimum version %q and cipher suites %q",
+			vcConfig.VCenterIP, vcConfig.TLSMinVersion, vcConfig.TLSCipherSuites)
+	}
+	// the connections of the concurrent queries are pooled rather than
+	// opened again for each burst of node discoveries
+	vSphereConn.MaxIdleConnsPerHost = max(PoolSize, int(vcConfig.MaxConcurrentQueries))
+	vsi := &VSphereInstance{
+		Conn: &vSphereConn,
+		Cfg:  vcConfig,
+	}
+	if vcConfig.MaxConcurrentQueries > 0 {
+		klog.Infof("vCenter %s is queried by at most %d concurrent searches and property collections",
+			vcConfig.VCenterIP, vcConfig.MaxConcurrentQueries)
+		vsi.queries = make(chan struct{}, vcConfig.MaxConcurrentQueries)
+	}
+	if vcConfig.APIQPS > 0 {
+		burst := vcConfig.APIBurst
+		if burst == 0 {
+			burst = vcConfig.APIQPS
+		}
+		klog.Infof("vCenter %s is sent at most %d API requests per second, with bursts of %d",
+			vcConfig.VCenterIP, vcConfig.APIQPS, burst)
+		vSphereConn.RateLimiter = &meteredRateLimiter{
+			RateLimiter: flowcontrol.NewTokenBucketRateLimiter(float32(vcConfig.APIQPS), int(burst)),
+			waits:       rateLimitWaitMetric.WithLabelValues(vcConfig.VCenterIP),
+		}
+	}
+	return vsi
+}
+
 // InitializeSecretLister initializes the individual secret listers that are NOT
 // handled through the Default/Global lister tied to the default service account.
 func (connMgr *ConnectionManager) InitializeSecretLister() {
 	// For each vsi that has a Secret set createManagersPerTenant
-	for _, vInstance := range connMgr.VsphereInstanceMap {
-		klog.V(3).Infof("Checking vcServer=%s SecretRef=%s", vInstance.Cfg.VCenterIP, vInstance.Cfg.SecretRef)
+	for _, vInstance := range connMgr.Instances() {
+		logging.V(logging.ConnectionManager, 3).Infof("Checking vcServer=%s SecretRef=%s", vInstance.Cfg.VCenterIP, vInstance.Cfg.SecretRef)
 		if strings.EqualFold(vInstance.Cfg.SecretRef, vcfg.DefaultCredentialManager) {
-			klog.V(3).Infof("Skipping. vCenter %s is configured using global service account/secret.", vInstance.Cfg.VCenterIP)
+			logging.V(logging.ConnectionManager, 3).Infof("Skipping. vCenter %s is configured using global service account/secret.", vInstance.Cfg.VCenterIP)
 			continue
 		}
 
-		klog.V(3).Infof("Adding credMgr/informMgr for vcServer=%s", vInstance.Cfg.VCenterIP)
-		credsMgr, informMgr := connMgr.createManagersPerTenant(vInstance.Cfg.SecretName,
-			vInstance.Cfg.SecretNamespace, "", connMgr.client)
-		connMgr.credentialManagers[vInstance.Cfg.SecretRef] = credsMgr
-		connMgr.informerManagers[vInstance.Cfg.SecretRef] = informMgr
+		connMgr.initializeTenantSecretLister(vInstance)
+	}
+}
+
+// initializeTenantSecretLister initializes the secret lister of a vCenter
+// whose credentials are in a secret of its own.
+func (connMgr *ConnectionManager) initializeTenantSecretLister(vInstance *VSphereInstance) {
+	logging.V(logging.ConnectionManager, 3).Infof("Adding credMgr/informMgr for vcServer=%s", vInstance.Cfg.VCenterIP)
+	credsMgr, informMgr := connMgr.createManagersPerTenant(vInstance.Cfg.SecretName,
+		vInstance.Cfg.SecretNamespace, "", connMgr.client)
+	connMgr.credentialManagers[vInstance.Cfg.SecretRef] = credsMgr
+	connMgr.informerManagers[vInstance.Cfg.SecretRef] = informMgr
+	if informMgr != nil {
+		connMgr.watchCredentials(vInstance.Cfg.SecretRef, credsMgr, informMgr.GetSecretInformer(vInstance.Cfg.SecretNamespace))
 	}
 }
 
@@ -139,6 +212,9 @@ func (connMgr *ConnectionManager) Connect(ctx context.Context, vcInstance *VSphe
 	defer connMgr.Unlock()
 
 	err := vcInstance.Conn.Connect(ctx)
+	if err != nil && !vclib.IsInvalidCredentialsError(err) && hasEndpoints(vcInstance.Cfg) {
+		err = failover(ctx, vcInstance, err)
+	}
 	if err == nil {
 		return nil
 	}
@@ -148,7 +224,7 @@ func (connMgr *ConnectionManager) Connect(ctx context.Context, vcInstance *VSphe
 		return err
 	}
 
-	klog.V(2).Infof("Invalid credentials. Fetching credentials from secrets. vcServer=%s credentialHolder=%s",
+	logging.V(logging.ConnectionManager, 2).Infof("Invalid credentials. Fetching credentials from secrets. vcServer=%s credentialHolder=%s",
 		vcInstance.Cfg.VCenterIP, vcInstance.Cfg.SecretRef)
 
 	credMgr := connMgr.credentialManagers[vcInstance.Cfg.SecretRef]
@@ -167,7 +243,7 @@ func (connMgr *ConnectionManager) Connect(ctx context.Context, vcInstance *VSphe
 
 // Logout closes existing connections to remote vCenter endpoints.
 func (connMgr *ConnectionManager) Logout() {
-	for _, vsphereIns := range connMgr.VsphereInstanceMap {
+	for _, vsphereIns := range connMgr.Instances() {
 		connMgr.Lock()
 		c := vsphereIns.Conn.Client
 		connMgr.Unlock()
@@ -180,10 +256,10 @@ func (connMgr *ConnectionManager) Logout() {
 // Verify validates the configuration by attempting to connect to the
 // configured, remote vCenter endpoints.
 func (connMgr *ConnectionManager) Verify() error {
-	for _, vcInstance := range connMgr.VsphereInstanceMap {
+	for _, vcInstance := range connMgr.Instances() {
 		err := connMgr.Connect(context.Background(), vcInstance)
 		if err == nil {
-			klog.V(3).Infof("vCenter connect %s succeeded.", vcInstance.Cfg.VCenterIP)
+			logging.V(logging.ConnectionManager, 3).Infof("vCenter connect %s succeeded.", vcInstance.Cfg.VCenterIP)
 		} else {
 			klog.Errorf("vCenter %s failed. Err: %q", vcInstance.Cfg.VCenterIP, err)
 			return err
@@ -195,10 +271,10 @@ func (connMgr *ConnectionManager) Verify() error {
 // VerifyWithContext is the same as Verify but allows a Go Context
 // to control the lifecycle of the connection event.
 func (connMgr *ConnectionManager) VerifyWithContext(ctx context.Context) error {
-	for _, vcInstance := range connMgr.VsphereInstanceMap {
+	for _, vcInstance := range connMgr.Instances() {
 		err := connMgr.Connect(ctx, vcInstance)
 		if err == nil {
-			klog.V(3).Infof("vCenter connect %s succeeded.", vcInstance.Cfg.VCenterIP)
+			logging.V(logging.ConnectionManager, 3).Infof("vCenter connect %s succeeded.", vcInstance.Cfg.VCenterIP)
 		} else {
 			klog.Errorf("vCenter %s failed. Err: %q", vcInstance.Cfg.VCenterIP, err)
 			return err
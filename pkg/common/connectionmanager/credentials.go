@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	informerv1 "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/credentialmanager"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// watchCredentials renews the sessions of the vCenters using the credentials
+// of secretRef whenever their secret is added or updated, so that rotated
+// credentials are used without restarting the controller.
+func (connMgr *ConnectionManager) watchCredentials(secretRef string, credMgr *cm.CredentialManager, secretInformer informerv1.SecretInformer) {
+	if credMgr == nil || credMgr.SecretName == "" || secretInformer == nil {
+		return
+	}
+	isCredentialsSecret := func(obj interface{}) bool {
+		secret, ok := obj.(*corev1.Secret)
+		return ok && secret != nil &&
+			secret.GetName() == credMgr.SecretName && secret.GetNamespace() == credMgr.SecretNamespace
+	}
+	_, err := secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if isCredentialsSecret(obj) {
+				connMgr.refreshCredentials(context.Background(), secretRef, credMgr)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if isCredentialsSecret(obj) {
+				connMgr.refreshCredentials(context.Background(), secretRef, credMgr)
+			}
+		},
+	})
+	if err != nil {
+		klog.Errorf("Failed to watch secret %s/%s for credential changes: %v", credMgr.SecretNamespace, credMgr.SecretName, err)
+	}
+}
+
+// refreshCredentials reads the credentials of the vCenters using secretRef
+// and renews the sessions of those whose credentials changed. The calls in
+// flight complete on the replaced sessions, and a vCenter keeps its session if
+// the login with the new credentials fails.
+func (connMgr *ConnectionManager) refreshCredentials(ctx context.Context, secretRef string, credMgr *cm.CredentialManager) {
+	// the sessions are renewed without holding the lock, only taken to
+	// replace their clients, so that the logins do not block the connections
+	// to the other vCenters and the updates of the tenant configs
+	var instances []*VSphereInstance
+	connMgr.Lock()
+	for _, vsi := range connMgr.Instances() {
+		if strings.EqualFold(vsi.Cfg.SecretRef, secretRef) {
+			instances = append(instances, vsi)
+		}
+	}
+	connMgr.Unlock()
+
+	for _, vsi := range instances {
+		credentials, err := credMgr.GetCredential(vsi.Cfg.VCenterIP)
+		if err != nil {
+			klog.Warningf("Cannot refresh the credentials of vcServer=%s credentialHolder=%s: %v", vsi.Cfg.VCenterIP, secretRef, err)
+			continue
+		}
+
+		changed, err := vsi.Conn.RenewCredentials(ctx, credentials.User, credentials.Password, connMgr)
+		if err != nil {
+			klog.Errorf("Failed to renew the session of vcServer=%s with the updated credentials, keeping the current session: %v", vsi.Cfg.VCenterIP, err)
+			continue
+		}
+		if changed {
+			logging.V(logging.ConnectionManager, 2).Infof("Updated the credentials of vcServer=%s credentialHolder=%s", vsi.Cfg.VCenterIP, secretRef)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	cm "k8s.io/cloud-provider-vsphere/pkg/common/credentialmanager"
+)
+
+func TestWatchCredentials(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+	config.VirtualCenter[config.Global.VCenterIP].SecretRef = vcfg.DefaultCredentialManager
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+	vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+	ctx := context.Background()
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Fatalf("Connect err=%v", err)
+	}
+	client := vsi.Conn.Client
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "vsphere-creds"},
+		Data: map[string][]byte{
+			config.Global.VCenterIP + ".username": []byte(vsi.Conn.Username),
+			config.Global.VCenterIP + ".password": []byte(vsi.Conn.Password),
+		},
+	}
+	clientset := fake.NewSimpleClientset(secret)
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	secretInformer := factory.Core().V1().Secrets()
+	credMgr := cm.NewCredentialManager(secret.Name, secret.Namespace, "", secretInformer.Lister())
+	connMgr.watchCredentials(vcfg.DefaultCredentialManager, credMgr, secretInformer)
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+
+	// the session is kept while the credentials are unchanged
+	if vsi.Conn.Client != client {
+		t.Error("client should not be replaced by the initial secret")
+	}
+
+	secret = secret.DeepCopy()
+	secret.ResourceVersion = "2"
+	secret.Data[config.Global.VCenterIP+".username"] = []byte("rotated")
+	secret.Data[config.Global.VCenterIP+".password"] = []byte("rotated-pass")
+	if _, err := clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		connMgr.Lock()
+		defer connMgr.Unlock()
+		return vsi.Conn.Client != client, nil
+	})
+	if err != nil {
+		t.Fatal("client should be replaced after the credentials are rotated")
+	}
+	if vsi.Conn.Username != "rotated" || vsi.Conn.Password != "rotated-pass" {
+		t.Errorf("credentials should be rotated, actual username=%s", vsi.Conn.Username)
+	}
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Errorf("Connect with the rotated credentials err=%v", err)
+	}
+}
+
+func TestRefreshCredentialsWhileUpdatingInstances(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+	config.VirtualCenter[config.Global.VCenterIP].SecretRef = vcfg.DefaultCredentialManager
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+	vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "vsphere-creds"},
+		// the credentials of the vCenter are missing, it is skipped
+		Data: map[string][]byte{
+			"other.username": []byte(vsi.Conn.Username),
+			"other.password": []byte(vsi.Conn.Password),
+		},
+	}
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(secret), 0)
+	secretInformer := factory.Core().V1().Secrets()
+	credMgr := cm.NewCredentialManager(secret.Name, secret.Namespace, "", secretInformer.Lister())
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+
+	// the instances are replaced under the lock, as the tenant configs do
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			connMgr.Lock()
+			connMgr.VsphereInstanceMap = map[string]*VSphereInstance{config.Global.VCenterIP: vsi}
+			connMgr.Unlock()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		connMgr.refreshCredentials(context.Background(), vcfg.DefaultCredentialManager, credMgr)
+	}
+	<-done
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"strings"
+
+	klog "k8s.io/klog/v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+// getDatacenters returns the datacenters of the vCenter referenced by moid
+// and by name in its config, or all its datacenters if none is referenced.
+// A datacenter referenced both ways is returned once. The datacenters that
+// resolve are returned along with the error of the last one that does not.
+//
+// A name that no longer resolves, because the datacenter was renamed, falls
+// back to the moid it resolved to before, with a warning to reference the
+// datacenter by moid instead.
+func (cm *ConnectionManager) getDatacenters(ctx context.Context, vsi *VSphereInstance) ([]*vclib.Datacenter, error) {
+	names := splitDatacenters(vsi.Cfg.Datacenters)
+	moids := splitDatacenters(cm.listedDatacenterMoids(vsi))
+	if len(names) == 0 && len(moids) == 0 {
+		return vclib.GetAllDatacenter(ctx, vsi.Conn)
+	}
+
+	var datacenters []*vclib.Datacenter
+	found := make(map[string]bool)
+	add := func(dc *vclib.Datacenter) {
+		if moid := dc.Reference().Value; !found[moid] {
+			found[moid] = true
+			datacenters = append(datacenters, dc)
+		}
+	}
+
+	var lastErr error
+	var byMoid []*vclib.Datacenter
+	for _, moid := range moids {
+		dc, err := vclib.GetDatacenter(ctx, vsi.Conn, datacenterMoidRef(moid))
+		if err != nil {
+			klog.Errorf("Failed to find datacenter with moid %s in vc=%s: %v", moid, vsi.Cfg.VCenterIP, err)
+			lastErr = err
+			continue
+		}
+		byMoid = append(byMoid, dc)
+		add(dc)
+	}
+
+	for _, name := range names {
+		dc, err := vclib.GetDatacenter(ctx, vsi.Conn, name)
+		if err == nil {
+			cm.setDatacenterMoid(vsi, name, dc.Reference().Value)
+			add(dc)
+			continue
+		}
+
+		if moid, ok := cm.getDatacenterMoid(vsi, name); ok {
+			if dc, errMoid := vclib.GetDatacenter(ctx, vsi.Conn, datacenterMoidRef(moid)); errMoid == nil {
+				klog.Warningf("Datacenter %q in vc=%s no longer resolves, it was renamed to %q. Using it by its moid %s, add it to the datacenter moids of the config",
+					name, vsi.Cfg.VCenterIP, dc.Name(), moid)
+				add(dc)
+				continue
+			}
+		}
+
+		// a datacenter referenced by moid under another name may be the renamed one
+		if renamed := unlistedDatacenters(byMoid, names); len(renamed) > 0 {
+			klog.Warningf("Datacenter %q in vc=%s no longer resolves while datacenters %s referenced by moid are not listed by name. If it was renamed, remove %q from the datacenters of the config",
+				name, vsi.Cfg.VCenterIP, strings.Join(renamed, ", "), name)
+			continue
+		}
+		lastErr = err
+	}
+
+	return datacenters, lastErr
+}
+
+// findVMInUnlistedDatacenters searches the VM in the datacenters not listed
+// in the config of the vCenters whose unlisted datacenter policy accepts
+// them. With the rescope policy, the datacenter the VM is found in is listed
+// from then on, until restart.
+func (cm *ConnectionManager) findVMInUnlistedDatacenters(ctx context.Context, nodeID string, searchBy FindVM) *VMDiscoveryInfo {
+	for _, vsi := range cm.Instances() {
+		policy := vsi.Cfg.UnlistedDatacenterPolicy
+		if policy != vcfg.UnlistedDatacenterWarnAndAccept && policy != vcfg.UnlistedDatacenterRescope {
+			continue
+		}
+		if err := cm.Connect(ctx, vsi); err != nil {
+			klog.Errorf("Failed to connect to vc=%s: %v", vsi.Cfg.VCenterIP, err)
+			continue
+		}
+
+		datacenters, err := cm.getUnlistedDatacenters(ctx, vsi)
+		if err != nil {
+			klog.Errorf("Failed to list the datacenters of vc=%s: %v", vsi.Cfg.VCenterIP, err)
+			continue
+		}
+		for _, datacenter := range datacenters {
+			vm, err := findVMInDatacenter(ctx, vsi, datacenter, nodeID, searchBy)
+			if err != nil {
+				if err != vclib.ErrNoVMFound {
+					klog.Errorf("Error while looking for vm=%s(%s) in vc=%s and datacenter=%s: %v",
+						nodeID, searchBy, vsi.Cfg.VCenterIP, datacenter.Name(), err)
+				}
+				continue
+			}
+			info, err := newVMDiscoveryInfo(ctx, vsi, vm, datacenter, nodeID, searchBy)
+			if err != nil {
+				klog.Errorf("Error collecting properties for vm=%+v in vc=%s and datacenter=%s: %v",
+					vm, vsi.Cfg.VCenterIP, datacenter.Name(), err)
+				continue
+			}
+
+			moid := datacenter.Reference().Value
+			klog.Warningf("Found node %s in vc=%s and datacenter=%s (moid %s), which is not listed in the config. Accepting it as the unlisted datacenter policy is %s, add the datacenter to the config",
+				nodeID, vsi.Cfg.VCenterIP, datacenter.Name(), moid, policy)
+			unlistedDatacenterMetric.WithLabelValues(vsi.Cfg.VCenterIP, datacenter.Name(), policy).Inc()
+			if policy == vcfg.UnlistedDatacenterRescope {
+				cm.listDatacenterMoid(vsi, moid)
+				klog.Warningf("Added datacenter %s (moid %s) to the datacenters of vc=%s", datacenter.Name(), moid, vsi.Cfg.VCenterIP)
+			}
+			return info
+		}
+	}
+	return nil
+}
+
+// getUnlistedDatacenters returns the datacenters of the vCenter that are not
+// listed in its config, none if it lists no datacenter as all are used then.
+func (cm *ConnectionManager) getUnlistedDatacenters(ctx context.Context, vsi *VSphereInstance) ([]*vclib.Datacenter, error) {
+	if len(splitDatacenters(vsi.Cfg.Datacenters)) == 0 && len(splitDatacenters(cm.listedDatacenterMoids(vsi))) == 0 {
+		return nil, nil
+	}
+
+	all, err := vclib.GetAllDatacenter(ctx, vsi.Conn)
+	if err != nil {
+		return nil, err
+	}
+	// datacenters listed but not found are searched as unlisted ones
+	listed, _ := cm.getDatacenters(ctx, vsi)
+	found := make(map[string]bool)
+	for _, dc := range listed {
+		found[dc.Reference().Value] = true
+	}
+
+	var unlisted []*vclib.Datacenter
+	for _, dc := range all {
+		if !found[dc.Reference().Value] {
+			unlisted = append(unlisted, dc)
+		}
+	}
+	return unlisted, nil
+}
+
+// unlistedDatacenters returns the names of the datacenters missing from names.
+func unlistedDatacenters(datacenters []*vclib.Datacenter, names []string) []string {
+	var unlisted []string
+	for _, dc := range datacenters {
+		listed := false
+		for _, name := range names {
+			if dc.Name() == name || dc.InventoryPath == name {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			unlisted = append(unlisted, dc.Name())
+		}
+	}
+	return unlisted
+}
+
+func (cm *ConnectionManager) getDatacenterMoid(vsi *VSphereInstance, name string) (string, bool) {
+	cm.datacenterMoidsLock.Lock()
+	defer cm.datacenterMoidsLock.Unlock()
+
+	moid, ok := cm.datacenterMoids[vsi.Cfg.TenantRef+"/"+name]
+	return moid, ok
+}
+
+func (cm *ConnectionManager) setDatacenterMoid(vsi *VSphereInstance, name string, moid string) {
+	cm.datacenterMoidsLock.Lock()
+	defer cm.datacenterMoidsLock.Unlock()
+
+	if cm.datacenterMoids == nil {
+		cm.datacenterMoids = make(map[string]string)
+	}
+	cm.datacenterMoids[vsi.Cfg.TenantRef+"/"+name] = moid
+}
+
+func (cm *ConnectionManager) listedDatacenterMoids(vsi *VSphereInstance) string {
+	cm.datacenterMoidsLock.Lock()
+	defer cm.datacenterMoidsLock.Unlock()
+
+	return vsi.Cfg.DatacenterMoids
+}
+
+// listDatacenterMoid adds the moid to the datacenter moids of the vCenter.
+func (cm *ConnectionManager) listDatacenterMoid(vsi *VSphereInstance, moid string) {
+	cm.datacenterMoidsLock.Lock()
+	defer cm.datacenterMoidsLock.Unlock()
+
+	moids := splitDatacenters(vsi.Cfg.DatacenterMoids)
+	for _, listed := range moids {
+		if strings.TrimPrefix(listed, "Datacenter:") == moid {
+			return
+		}
+	}
+	vsi.Cfg.DatacenterMoids = strings.Join(append(moids, moid), ",")
+}
+
+// datacenterMoidRef returns the managed object reference of a datacenter
+// moid, which may be given as datacenter-3 or Datacenter:datacenter-3.
+func datacenterMoidRef(moid string) string {
+	if strings.Contains(moid, ":") {
+		return moid
+	}
+	return "Datacenter:" + moid
+}
+
+// splitDatacenters splits a comma separated list of datacenters.
+func splitDatacenters(datacenters string) []string {
+	var list []string
+	for _, dc := range strings.Split(datacenters, ",") {
+		if dc = strings.TrimSpace(dc); dc != "" {
+			list = append(list, dc)
+		}
+	}
+	return list
+}
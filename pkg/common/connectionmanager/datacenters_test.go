@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+func findSimDatacenter(t *testing.T, name string) *simulator.Datacenter {
+	for _, obj := range simulator.Map.All("Datacenter") {
+		if dc := obj.(*simulator.Datacenter); dc.Name == name {
+			return dc
+		}
+	}
+	t.Fatalf("datacenter %s not found", name)
+	return nil
+}
+
+func TestListAllVCandDCPairsByMoid(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+
+	dc0 := findSimDatacenter(t, "DC0")
+	dc1 := findSimDatacenter(t, "DC1")
+	// DC0 is referenced both by name and moid
+	vcConfig := config.VirtualCenter[config.Global.VCenterIP]
+	vcConfig.Datacenters = "DC0"
+	vcConfig.DatacenterMoids = dc0.Self.Value + ",Datacenter:" + dc1.Self.Value
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	items, err := connMgr.ListAllVCandDCPairs(context.Background())
+	if err != nil {
+		t.Fatalf("ListAllVCandDCPairs err=%v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("ListAllVCandDCPairs items should be 2 but count=%d", len(items))
+	}
+	names := map[string]bool{items[0].DataCenter.Name(): true, items[1].DataCenter.Name(): true}
+	if !names["DC0"] || !names["DC1"] {
+		t.Errorf("ListAllVCandDCPairs should return DC0 and DC1, got %v", names)
+	}
+}
+
+func TestListAllVCandDCPairsRenamedDatacenter(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	ctx := context.Background()
+	if _, err := connMgr.ListAllVCandDCPairs(ctx); err != nil {
+		t.Fatalf("ListAllVCandDCPairs err=%v", err)
+	}
+
+	vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+	dc0 := findSimDatacenter(t, "DC0")
+	task, err := object.NewDatacenter(vsi.Conn.Client, dc0.Self).Rename(ctx, "DC0-renamed")
+	if err != nil {
+		t.Fatalf("Rename err=%v", err)
+	}
+	if err = task.Wait(ctx); err != nil {
+		t.Fatalf("Rename err=%v", err)
+	}
+
+	// DC0 is still found by the moid it resolved to
+	items, err := connMgr.ListAllVCandDCPairs(ctx)
+	if err != nil {
+		t.Fatalf("ListAllVCandDCPairs err=%v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("ListAllVCandDCPairs items should be 2 but count=%d", len(items))
+	}
+	found := false
+	for _, item := range items {
+		if item.DataCenter.Name() == "DC0-renamed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListAllVCandDCPairs should return DC0-renamed")
+	}
+
+	// without a previous lookup, the renamed datacenter is an error unless referenced by moid
+	connMgr.datacenterMoids = nil
+	if _, err = connMgr.getDatacenters(ctx, vsi); err == nil {
+		t.Errorf("getDatacenters should fail to find DC0")
+	}
+	vsi.Cfg.DatacenterMoids = dc0.Self.Value
+	datacenters, err := connMgr.getDatacenters(ctx, vsi)
+	if err != nil {
+		t.Fatalf("getDatacenters err=%v", err)
+	}
+	if len(datacenters) != 2 {
+		t.Errorf("getDatacenters should return 2 datacenters, got %d", len(datacenters))
+	}
+}
+
+func TestSplitDatacenters(t *testing.T) {
+	datacenters := splitDatacenters(" DC0, ,DC1,")
+	if len(datacenters) != 2 || datacenters[0] != "DC0" || datacenters[1] != "DC1" {
+		t.Errorf("splitDatacenters unexpected %v", datacenters)
+	}
+	if ref := datacenterMoidRef("datacenter-2"); ref != "Datacenter:datacenter-2" {
+		t.Errorf("datacenterMoidRef unexpected %s", ref)
+	}
+	if ref := datacenterMoidRef("Datacenter:datacenter-2"); ref != "Datacenter:datacenter-2" {
+		t.Errorf("datacenterMoidRef unexpected %s", ref)
+	}
+}
+
+func TestFindVMInUnlistedDatacenters(t *testing.T) {
+	testCases := []struct {
+		policy         string
+		expectFound    bool
+		expectRescoped bool
+	}{
+		{policy: "", expectFound: false},
+		{policy: vcfg.UnlistedDatacenterReject, expectFound: false},
+		{policy: vcfg.UnlistedDatacenterWarnAndAccept, expectFound: true},
+		{policy: vcfg.UnlistedDatacenterRescope, expectFound: true, expectRescoped: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.policy, func(t *testing.T) {
+			config, cleanup := configFromEnvOrSim(true)
+			defer cleanup()
+
+			// only DC0 is listed
+			vcConfig := config.VirtualCenter[config.Global.VCenterIP]
+			vcConfig.Datacenters = "DC0"
+			vcConfig.UnlistedDatacenterPolicy = testCase.policy
+			dc1 := findSimDatacenter(t, "DC1")
+
+			connMgr := NewConnectionManager(config, nil, nil)
+			defer connMgr.Logout()
+
+			ctx := context.Background()
+			vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+			if err := connMgr.Connect(ctx, vsi); err != nil {
+				t.Fatalf("Connect err=%v", err)
+			}
+
+			unlisted, err := connMgr.getUnlistedDatacenters(ctx, vsi)
+			if err != nil {
+				t.Fatalf("getUnlistedDatacenters err=%v", err)
+			}
+			if len(unlisted) != 1 || unlisted[0].Name() != "DC1" {
+				t.Fatalf("getUnlistedDatacenters should return DC1, got %v", unlisted)
+			}
+
+			vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+			metric := unlistedDatacenterMetric.WithLabelValues(config.Global.VCenterIP, "DC1", testCase.policy)
+			before := testutil.ToFloat64(metric)
+
+			info := connMgr.findVMInUnlistedDatacenters(ctx, vm.Config.Uuid, FindVMByUUID)
+			if !testCase.expectFound {
+				if info != nil {
+					t.Errorf("findVMInUnlistedDatacenters should not search unlisted datacenters")
+				}
+				return
+			}
+			if info == nil || info.DataCenter.Name() != "DC1" {
+				t.Fatalf("VM should be found in DC1, got %+v", info)
+			}
+			if count := testutil.ToFloat64(metric) - before; count != 1 {
+				t.Errorf("unlisted datacenter metric should be incremented once, got %v", count)
+			}
+
+			rescoped := vcConfig.DatacenterMoids == dc1.Self.Value
+			if rescoped != testCase.expectRescoped {
+				t.Errorf("unexpected datacenter moids %q", vcConfig.DatacenterMoids)
+			}
+			if testCase.expectRescoped {
+				if unlisted, _ = connMgr.getUnlistedDatacenters(ctx, vsi); len(unlisted) != 0 {
+					t.Errorf("rescoped datacenter should be listed, got %v unlisted", len(unlisted))
+				}
+			}
+		})
+	}
+}
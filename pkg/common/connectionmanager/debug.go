@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"sort"
+)
+
+// ConnectionState is the state of the connection to a vCenter as reported
+// in debug dumps.
+type ConnectionState struct {
+	VCenter   string `json:"vcenter"`
+	Hostname  string `json:"hostname"`
+	Port      string `json:"port"`
+	UserAgent string `json:"userAgent"`
+	Connected bool   `json:"connected"`
+	// Busy is set while a connection attempt holds the connection manager
+	// lock, Connected is then unknown.
+	Busy bool `json:"busy"`
+}
+
+// ConnectionStates returns the state of the connections to the vCenters,
+// without waiting for ongoing connection attempts.
+func (connMgr *ConnectionManager) ConnectionStates() []ConnectionState {
+	locked := connMgr.TryLock()
+	if locked {
+		defer connMgr.Unlock()
+	}
+
+	instances := connMgr.Instances()
+	states := make([]ConnectionState, 0, len(instances))
+	for vcServer, vsphereIns := range instances {
+		state := ConnectionState{VCenter: vcServer, Busy: !locked}
+		if vsphereIns.Conn != nil {
+			state.Hostname = vsphereIns.Conn.Hostname
+			state.Port = vsphereIns.Conn.Port
+			state.UserAgent = vsphereIns.Conn.GetUserAgent()
+			state.Connected = locked && vsphereIns.Conn.Client != nil
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].VCenter < states[j].VCenter
+	})
+	return states
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	klog "k8s.io/klog/v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+// lookupSRV resolves the DNS SRV records of the vCenter endpoints.
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// hasEndpoints returns whether the vCenter has endpoints to fail over to.
+func hasEndpoints(cfg *vcfg.VirtualCenterConfig) bool {
+	return cfg.Endpoints != "" || cfg.EndpointSRV != ""
+}
+
+// candidateEndpoints returns the endpoints of the vCenter, as host:port, in
+// the order they are tried: its server followed by its endpoints, or by the
+// targets of its SRV record.
+func candidateEndpoints(ctx context.Context, cfg *vcfg.VirtualCenterConfig) ([]string, error) {
+	port := cfg.VCenterPort
+	if port == "" {
+		port = vcfg.DefaultVCenterPortStr
+	}
+	candidates := []string{net.JoinHostPort(cfg.VCenterIP, port)}
+
+	if cfg.EndpointSRV == "" {
+		endpoints, err := vcfg.ParseEndpoints(cfg.Endpoints, port)
+		if err != nil {
+			return nil, err
+		}
+		return append(candidates, endpoints...), nil
+	}
+
+	_, records, err := lookupSRV(ctx, "", "", cfg.EndpointSRV)
+	if err != nil {
+		return nil, err
+	}
+	// the records are sorted by priority and randomized by weight
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+		candidates = append(candidates, net.JoinHostPort(target, strconv.Itoa(int(record.Port))))
+	}
+	return candidates, nil
+}
+
+// failover connects the vCenter to the first reachable of its other
+// endpoints, after its current endpoint failed with err. The error of the
+// last endpoint tried is returned if none is reachable, and an endpoint
+// rejecting the credentials is kept for them to be fetched again.
+func failover(ctx context.Context, vsi *VSphereInstance, err error) error {
+	endpoints, lookupErr := candidateEndpoints(ctx, vsi.Cfg)
+	if lookupErr != nil {
+		klog.Errorf("Failed to find the endpoints of vCenter %s: %v", vsi.Cfg.VCenterIP, lookupErr)
+		return err
+	}
+
+	current := net.JoinHostPort(vsi.Conn.Hostname, vsi.Conn.Port)
+	failed := map[string]bool{current: true}
+	for _, endpoint := range endpoints {
+		if failed[endpoint] {
+			continue
+		}
+		host, port, splitErr := net.SplitHostPort(endpoint)
+		if splitErr != nil {
+			continue
+		}
+		klog.Warningf("vCenter %s endpoint %s is unreachable, failing over to %s: %v", vsi.Cfg.VCenterIP, current, endpoint, err)
+
+		// the session on the unreachable endpoint cannot be logged out
+		if vsi.Conn.Client != nil {
+			vsi.Conn.Client.CloseIdleConnections()
+			vsi.Conn.Client = nil
+		}
+		vsi.Conn.Hostname = host
+		vsi.Conn.Port = port
+		err = vsi.Conn.Connect(ctx)
+		if err == nil || vclib.IsInvalidCredentialsError(err) {
+			klog.Infof("vCenter %s failed over to endpoint %s", vsi.Cfg.VCenterIP, endpoint)
+			endpointFailoversMetric.WithLabelValues(vsi.Cfg.VCenterIP, endpoint).Inc()
+			return err
+		}
+		failed[endpoint] = true
+		current = endpoint
+	}
+	return err
+}
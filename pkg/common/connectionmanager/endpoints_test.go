@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// closedPort returns a local port nothing listens on.
+func closedPort(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return port
+}
+
+func TestConnectFailover(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	vcConfig := config.VirtualCenter[config.Global.VCenterIP]
+	simEndpoint := net.JoinHostPort(vcConfig.VCenterIP, vcConfig.VCenterPort)
+	vcConfig.VCenterPort = closedPort(t)
+	vcConfig.RoundTripperCount = 1
+	vcConfig.Endpoints = net.JoinHostPort(vcConfig.VCenterIP, closedPort(t)) + "," + simEndpoint
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	vsi := connMgr.VsphereInstanceMap[vcConfig.TenantRef]
+	if err := connMgr.Connect(context.Background(), vsi); err != nil {
+		t.Fatalf("Connect should fail over to the reachable endpoint: %v", err)
+	}
+	if endpoint := net.JoinHostPort(vsi.Conn.Hostname, vsi.Conn.Port); endpoint != simEndpoint {
+		t.Errorf("Connection should use endpoint %s but actual=%s", simEndpoint, endpoint)
+	}
+	if failovers := testutil.ToFloat64(endpointFailoversMetric.WithLabelValues(vcConfig.VCenterIP, simEndpoint)); failovers != 1 {
+		t.Errorf("Failovers should be counted once but actual=%v", failovers)
+	}
+
+	// the endpoint failed over to is kept
+	if err := connMgr.Connect(context.Background(), vsi); err != nil {
+		t.Fatal(err)
+	}
+	if failovers := testutil.ToFloat64(endpointFailoversMetric.WithLabelValues(vcConfig.VCenterIP, simEndpoint)); failovers != 1 {
+		t.Errorf("Reachable endpoint should not fail over but failovers=%v", failovers)
+	}
+}
+
+func TestConnectFailoverSRV(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	vcConfig := config.VirtualCenter[config.Global.VCenterIP]
+	simPort := vcConfig.VCenterPort
+	vcConfig.VCenterPort = closedPort(t)
+	vcConfig.RoundTripperCount = 1
+	vcConfig.EndpointSRV = "_vcenter._tcp.example.com"
+
+	defer func(lookup func(context.Context, string, string, string) (string, []*net.SRV, error)) {
+		lookupSRV = lookup
+	}(lookupSRV)
+	lookupSRV = func(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+		if name != vcConfig.EndpointSRV {
+			t.Errorf("Unexpected SRV record %s", name)
+		}
+		port, _ := net.LookupPort("tcp", simPort)
+		return name, []*net.SRV{{Target: vcConfig.VCenterIP + ".", Port: uint16(port)}}, nil
+	}
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	vsi := connMgr.VsphereInstanceMap[vcConfig.TenantRef]
+	if err := connMgr.Connect(context.Background(), vsi); err != nil {
+		t.Fatalf("Connect should fail over to the target of the SRV record: %v", err)
+	}
+	if vsi.Conn.Port != simPort {
+		t.Errorf("Connection should use port %s but actual=%s", simPort, vsi.Conn.Port)
+	}
+}
+
+func TestConnectWithoutReachableEndpoint(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	vcConfig := config.VirtualCenter[config.Global.VCenterIP]
+	vcConfig.VCenterPort = closedPort(t)
+	vcConfig.RoundTripperCount = 1
+	vcConfig.Endpoints = net.JoinHostPort(vcConfig.VCenterIP, closedPort(t))
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	if err := connMgr.Connect(context.Background(), connMgr.VsphereInstanceMap[vcConfig.TenantRef]); err == nil {
+		t.Error("Connect should fail when no endpoint is reachable")
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// keepAliveBackoff is the backoff of the logins of a session found inactive.
+var keepAliveBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// KeepAlive checks the sessions of the connected vCenters every period until
+// ctx is done, and logs in again with an exponential backoff when a session
+// is no longer active, rather than waiting for the next query to fail.
+func (connMgr *ConnectionManager) KeepAlive(ctx context.Context, period time.Duration) {
+	wait.UntilWithContext(ctx, connMgr.keepAlive, period)
+}
+
+// keepAlive checks the sessions of the connected vCenters once and updates
+// the active sessions metric. vCenters that were never connected to are
+// skipped, their session is opened by the first query.
+func (connMgr *ConnectionManager) keepAlive(ctx context.Context) {
+	connMgr.Lock()
+	current := connMgr.Instances()
+	instances := make(map[string]*VSphereInstance, len(current))
+	for tenantRef, vsi := range current {
+		if vsi.Conn.Client != nil {
+			instances[tenantRef] = vsi
+		}
+	}
+	connMgr.Unlock()
+
+	active := make(map[string]bool, len(instances))
+	for tenantRef, vsi := range instances {
+		active[tenantRef] = connMgr.keepSessionAlive(ctx, tenantRef, vsi)
+	}
+
+	sessionsActiveMetric.Reset()
+	for tenantRef, ok := range active {
+		if ok {
+			sessionsActiveMetric.WithLabelValues(tenantRef).Set(1)
+		} else {
+			sessionsActiveMetric.WithLabelValues(tenantRef).Set(0)
+		}
+	}
+}
+
+// keepSessionAlive returns whether the session of vsi is active, logging in
+// again if it is not.
+func (connMgr *ConnectionManager) keepSessionAlive(ctx context.Context, tenantRef string, vsi *VSphereInstance) bool {
+	ok, err := vsi.Conn.SessionIsActive(ctx)
+	if err == nil && ok {
+		logging.V(logging.ConnectionManager, 4).Infof("Session of vCenter %s is active", tenantRef)
+		return true
+	}
+	if err != nil {
+		klog.Warningf("Failed to check the session of vCenter %s, logging in again: %v", tenantRef, err)
+	} else {
+		klog.Warningf("Session of vCenter %s is not active, logging in again", tenantRef)
+	}
+
+	err = wait.ExponentialBackoffWithContext(ctx, keepAliveBackoff, func(ctx context.Context) (bool, error) {
+		if err := connMgr.Connect(ctx, vsi); err != nil {
+			logging.V(logging.ConnectionManager, 2).Infof("Failed to log in to vCenter %s: %v", tenantRef, err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		klog.Errorf("Failed to log in to vCenter %s again: %v", tenantRef, err)
+		return false
+	}
+	logging.V(logging.ConnectionManager, 2).Infof("Logged in to vCenter %s again", tenantRef)
+	return true
+}
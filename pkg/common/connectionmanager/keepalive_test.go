@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware/govmomi/session"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestKeepAlive(t *testing.T) {
+	defer func(backoff wait.Backoff) { keepAliveBackoff = backoff }(keepAliveBackoff)
+	keepAliveBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 2}
+
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	ctx := context.Background()
+	vcConfig := config.VirtualCenter[config.Global.VCenterIP]
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	vsi := connMgr.VsphereInstanceMap[vcConfig.TenantRef]
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Fatal(err)
+	}
+	connMgr.keepAlive(ctx)
+	if active := testutil.ToFloat64(sessionsActiveMetric.WithLabelValues(vcConfig.TenantRef)); active != 1 {
+		t.Errorf("Session should be active but actual=%v", active)
+	}
+
+	// a terminated session is logged in again
+	if err := session.NewManager(vsi.Conn.Client).Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	connMgr.keepAlive(ctx)
+	if ok, err := vsi.Conn.SessionIsActive(ctx); err != nil || !ok {
+		t.Errorf("Session should be logged in again, active=%v err=%v", ok, err)
+	}
+	if active := testutil.ToFloat64(sessionsActiveMetric.WithLabelValues(vcConfig.TenantRef)); active != 1 {
+		t.Errorf("Session should be active but actual=%v", active)
+	}
+
+	// a vCenter that cannot be logged in to again is reported inactive
+	if err := session.NewManager(vsi.Conn.Client).Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	vsi.Conn.Port = closedPort(t)
+	vsi.Conn.RoundTripperCount = 1
+	connMgr.keepAlive(ctx)
+	if active := testutil.ToFloat64(sessionsActiveMetric.WithLabelValues(vcConfig.TenantRef)); active != 0 {
+		t.Errorf("Session should not be active but actual=%v", active)
+	}
+}
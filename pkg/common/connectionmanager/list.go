@@ -25,15 +25,16 @@ import (
 	klog "k8s.io/klog/v2"
 
 	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // ListAllVCandDCPairs returns all VC/DC pairs
 func (cm *ConnectionManager) ListAllVCandDCPairs(ctx context.Context) ([]*ListDiscoveryInfo, error) {
-	klog.V(4).Infof("ListAllVCandDCPairs called")
+	logging.V(logging.ConnectionManager, 4).Infof("ListAllVCandDCPairs called")
 
 	listOfVCAndDCPairs := make([]*ListDiscoveryInfo, 0)
 
-	for _, vsi := range cm.VsphereInstanceMap {
+	for _, vsi := range cm.Instances() {
 		var datacenterObjs []*vclib.Datacenter
 
 		var err error
@@ -50,26 +51,9 @@ func (cm *ConnectionManager) ListAllVCandDCPairs(ctx context.Context) ([]*ListDi
 			continue
 		}
 
-		if vsi.Cfg.Datacenters == "" {
-			datacenterObjs, err = vclib.GetAllDatacenter(ctx, vsi.Conn)
-			if err != nil {
-				klog.Error("GetAllDatacenter error dc:", err)
-				continue
-			}
-		} else {
-			datacenters := strings.Split(vsi.Cfg.Datacenters, ",")
-			for _, dc := range datacenters {
-				dc = strings.TrimSpace(dc)
-				if dc == "" {
-					continue
-				}
-				datacenterObj, err := vclib.GetDatacenter(ctx, vsi.Conn, dc)
-				if err != nil {
-					klog.Error("GetDatacenter error dc:", err)
-					continue
-				}
-				datacenterObjs = append(datacenterObjs, datacenterObj)
-			}
+		datacenterObjs, err = cm.getDatacenters(ctx, vsi)
+		if err != nil {
+			klog.Error("GetDatacenter error dc:", err)
 		}
 
 		for _, datacenterObj := range datacenterObjs {
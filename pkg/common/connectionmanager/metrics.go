@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// unlistedDatacenterMetric counts the VMs found in a datacenter that is not
+// listed in the config, to help keeping the datacenter list up to date.
+var unlistedDatacenterMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "unlisted_datacenter_vms",
+		Help: "VMs found in a datacenter not listed in the config",
+	},
+	[]string{"vcenter", "datacenter", "policy"},
+)
+
+// queriesWaitingMetric is the number of queries waiting for the concurrency
+// limit of their vCenter, to help sizing max-concurrent-queries.
+var queriesWaitingMetric = metrics.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "vcenter_queries_waiting",
+		Help: "Queries to a vCenter waiting for its limit of concurrent queries",
+	},
+	[]string{"vcenter"},
+)
+
+// endpointFailoversMetric counts the failovers of a vCenter to another of
+// its endpoints, labeled with the endpoint connected to.
+var endpointFailoversMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "vcenter_endpoint_failovers",
+		Help: "Failovers of a vCenter to another of its endpoints",
+	},
+	[]string{"vcenter", "endpoint"},
+)
+
+// sessionsActiveMetric is whether the session of a vCenter is active, as
+// last checked by the session keep-alive.
+var sessionsActiveMetric = metrics.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "vcenter_sessions_active",
+		Help: "Whether the session of a vCenter is active, as last checked by the session keep-alive",
+	},
+	[]string{"vcenter"},
+)
+
+// vmPropertiesMetric counts the reads of the properties of VMs, labeled with
+// whether they were read from the VM property cache or queried.
+var vmPropertiesMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "vm_property_reads",
+		Help: "Reads of the properties of VMs, from the VM property cache or queried",
+	},
+	[]string{"vcenter", "source"},
+)
+
+// rateLimitWaitMetric is the time the API requests to a vCenter waited for
+// its rate limit, to help sizing api-qps and api-burst.
+var rateLimitWaitMetric = metrics.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "vcenter_api_rate_limit_wait_seconds",
+		Help:    "Time the API requests to a vCenter waited for its rate limit",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	},
+	[]string{"vcenter"},
+)
+
+// pinnedSearchesMetric counts the searches of VMs of nodes pinned to a
+// vCenter, labeled with whether the VM was found in the pinned vCenter or the
+// search fell back to the others, to help keeping the pinning up to date.
+var pinnedSearchesMetric = metrics.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "pinned_vm_searches",
+		Help: "Searches of VMs of nodes pinned to a vCenter, found there or falling back to the other vCenters",
+	},
+	[]string{"vcenter", "result"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(unlistedDatacenterMetric)
+	legacyregistry.RawMustRegister(queriesWaitingMetric)
+	legacyregistry.RawMustRegister(endpointFailoversMetric)
+	legacyregistry.RawMustRegister(sessionsActiveMetric)
+	legacyregistry.RawMustRegister(vmPropertiesMetric)
+	legacyregistry.RawMustRegister(rateLimitWaitMetric)
+	legacyregistry.RawMustRegister(pinnedSearchesMetric)
+}
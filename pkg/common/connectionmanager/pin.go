@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VCenterPin is the vCenter, and optionally its datacenter, the VM of a node
+// is searched in first.
+type VCenterPin struct {
+	// VCenter is the name of the VirtualCenter section of the vCenter, or
+	// its server address, "" if the node is not pinned.
+	VCenter string
+	// Datacenter is the datacenter of the vCenter, "" for all of them.
+	Datacenter string
+}
+
+// ParseVCenterPin parses a pin written as vcenter or vcenter/datacenter.
+func ParseVCenterPin(value string) VCenterPin {
+	vcenter, datacenter, _ := strings.Cut(strings.TrimSpace(value), "/")
+	return VCenterPin{VCenter: strings.TrimSpace(vcenter), Datacenter: strings.TrimSpace(datacenter)}
+}
+
+func (pin VCenterPin) String() string {
+	if pin.Datacenter == "" {
+		return fmt.Sprintf("vc=%s", pin.VCenter)
+	}
+	return fmt.Sprintf("vc=%s and datacenter=%s", pin.VCenter, pin.Datacenter)
+}
+
+// matches returns true if the vCenter is the pinned one.
+func (pin VCenterPin) matches(vsi *VSphereInstance) bool {
+	return vsi.Cfg.TenantRef == pin.VCenter || vsi.Cfg.VCenterIP == pin.VCenter
+}
+
+// contains is the vmSearchScope of the pinned vCenter and datacenter.
+func (pin VCenterPin) contains(vsi *VSphereInstance, datacenter string) bool {
+	return pin.matches(vsi) && (datacenter == "" || pin.Datacenter == "" || datacenter == pin.Datacenter)
+}
+
+// excludes is the vmSearchScope of every vCenter and datacenter but the
+// pinned ones.
+func (pin VCenterPin) excludes(vsi *VSphereInstance, datacenter string) bool {
+	if !pin.matches(vsi) {
+		return true
+	}
+	// the other datacenters of the pinned vCenter are still searched
+	return pin.Datacenter != "" && datacenter != pin.Datacenter
+}
+
+// hasPinnedInstance returns true if the pinned vCenter is configured.
+func (cm *ConnectionManager) hasPinnedInstance(pin VCenterPin) bool {
+	for _, vsi := range cm.Instances() {
+		if pin.matches(vsi) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// AcquireQuery waits until a query, such as a search or a property
+// collection, can be issued to the vCenter without exceeding its
+// MaxConcurrentQueries, and returns the function to call once the query is
+// done. An error is returned if ctx is done first.
+func (vsi *VSphereInstance) AcquireQuery(ctx context.Context) (func(), error) {
+	if vsi.queries == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case vsi.queries <- struct{}{}:
+		return vsi.releaseQuery, nil
+	default:
+	}
+
+	logging.V(logging.ConnectionManager, 4).Infof("Waiting for a query slot of vc=%s", vsi.Cfg.VCenterIP)
+	waiting := queriesWaitingMetric.WithLabelValues(vsi.Cfg.VCenterIP)
+	waiting.Inc()
+	defer waiting.Dec()
+	select {
+	case vsi.queries <- struct{}{}:
+		return vsi.releaseQuery, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (vsi *VSphereInstance) releaseQuery() {
+	<-vsi.queries
+}
+
+// meteredRateLimiter observes the time the API requests wait for the rate
+// limit of their vCenter.
+type meteredRateLimiter struct {
+	flowcontrol.RateLimiter
+	waits prometheus.Observer
+}
+
+func (l *meteredRateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := l.RateLimiter.Wait(ctx)
+	l.waits.Observe(time.Since(start).Seconds())
+	return err
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+func TestAcquireQuery(t *testing.T) {
+	unlimited := newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.1"})
+	for i := 0; i < 3; i++ {
+		if _, err := unlimited.AcquireQuery(context.Background()); err != nil {
+			t.Fatalf("unlimited queries should not wait: %v", err)
+		}
+	}
+
+	limited := newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.2", MaxConcurrentQueries: 1})
+	release, err := limited.AcquireQuery(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := limited.AcquireQuery(ctx); err == nil {
+		t.Fatal("query over the limit should wait until the context is done")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := limited.AcquireQuery(context.Background())
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting query should be issued once the previous one is done")
+	}
+}
+
+func TestAPIRateLimit(t *testing.T) {
+	unlimited := newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.1"})
+	if unlimited.Conn.RateLimiter != nil {
+		t.Error("API requests should not be rate limited without api-qps")
+	}
+
+	limited := newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.2", APIQPS: 1})
+	if limited.Conn.RateLimiter == nil {
+		t.Fatal("API requests should be rate limited with api-qps")
+	}
+	if !limited.Conn.RateLimiter.TryAccept() {
+		t.Fatal("the burst should default to api-qps")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limited.Conn.RateLimiter.Wait(ctx); err == nil {
+		t.Error("request over the rate limit should wait longer than the context")
+	}
+
+	burst := newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.3", APIQPS: 1, APIBurst: 3})
+	for i := 0; i < 3; i++ {
+		if !burst.Conn.RateLimiter.TryAccept() {
+			t.Fatalf("request %d should be accepted within the burst", i)
+		}
+	}
+}
+
+func TestConnectionPool(t *testing.T) {
+	vsi := newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.1"})
+	if vsi.Conn.MaxIdleConnsPerHost != PoolSize {
+		t.Errorf("Expected %d pooled connections by default, got %d", PoolSize, vsi.Conn.MaxIdleConnsPerHost)
+	}
+
+	vsi = newVSphereInstance(&vcfg.VirtualCenterConfig{VCenterIP: "10.0.0.2", MaxConcurrentQueries: 32})
+	if vsi.Conn.MaxIdleConnsPerHost != 32 {
+		t.Errorf("Expected a pooled connection per concurrent query, got %d", vsi.Conn.MaxIdleConnsPerHost)
+	}
+}
@@ -23,10 +23,11 @@ import (
 	"sync"
 	"time"
 
-	"github.com/vmware/govmomi/vim25/mo"
 	klog "k8s.io/klog/v2"
 
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // String returns the string representation of the FindVM constant.
@@ -45,13 +46,81 @@ func (f FindVM) String() string {
 
 // WhichVCandDCByNodeID finds the VC/DC combo that owns a particular VM
 func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID string, searchBy FindVM) (*VMDiscoveryInfo, error) {
+	return cm.WhichVCandDCByNodeIDPinned(ctx, nodeID, searchBy, VCenterPin{})
+}
+
+// WhichVCandDCByNodeIDPinned finds the VC/DC combo that owns a particular VM
+// like WhichVCandDCByNodeID, but first searches the vCenter, and datacenter
+// if any, the node is pinned to. The other vCenters and datacenters are only
+// searched if the VM is not found there.
+func (cm *ConnectionManager) WhichVCandDCByNodeIDPinned(ctx context.Context, nodeID string, searchBy FindVM, pin VCenterPin) (*VMDiscoveryInfo, error) {
 	if nodeID == "" {
-		klog.V(3).Info("WhichVCandDCByNodeID called but nodeID is empty")
+		logging.V(logging.ConnectionManager, 3).Info("WhichVCandDCByNodeID called but nodeID is empty")
 		return nil, errors.New("nodeID is empty")
 	}
+
+	myNodeID := nodeID
+	switch searchBy {
+	case FindVMByUUID:
+		logging.V(logging.ConnectionManager, 3).Info("WhichVCandDCByNodeID by UUID")
+		myNodeID = strings.TrimSpace(strings.ToLower(nodeID))
+	case FindVMByIP:
+		logging.V(logging.ConnectionManager, 3).Info("WhichVCandDCByNodeID by IP")
+	default:
+		logging.V(logging.ConnectionManager, 3).Info("WhichVCandDCByNodeID by Name")
+	}
+	logging.V(logging.ConnectionManager, 2).Info("WhichVCandDCByNodeID nodeID: ", myNodeID)
+
+	scope := allVMSearchScope
+	if pin.VCenter != "" {
+		if !cm.hasPinnedInstance(pin) {
+			klog.Warningf("Node %s is pinned to vc=%s, which is not configured, searching all vCenters", myNodeID, pin.VCenter)
+		} else {
+			if vmInfo, _ := cm.searchVM(ctx, myNodeID, searchBy, pin.contains); vmInfo != nil {
+				pinnedSearchesMetric.WithLabelValues(pin.VCenter, "found").Inc()
+				return vmInfo, nil
+			}
+			pinnedSearchesMetric.WithLabelValues(pin.VCenter, "fallback").Inc()
+			logging.V(logging.ConnectionManager, 2).Infof("Did not find node %s in its pinned %s, searching the other vCenters and datacenters",
+				myNodeID, pin)
+			scope = pin.excludes
+		}
+	}
+
+	vmInfo, globalErr := cm.searchVM(ctx, myNodeID, searchBy, scope)
+	if vmInfo != nil {
+		return vmInfo, nil
+	}
+
+	if vmInfo = cm.findVMInUnlistedDatacenters(ctx, myNodeID, searchBy); vmInfo != nil {
+		return vmInfo, nil
+	}
+
+	if globalErr != nil {
+		return nil, globalErr
+	}
+
+	logging.V(logging.ConnectionManager, 4).Infof("WhichVCandDCByNodeID: %q vm not found", myNodeID)
+	return nil, vclib.ErrNoVMFound
+}
+
+// vmSearchScope returns whether the datacenter of the vCenter is searched for
+// a VM. It is first called with an empty datacenter, to know whether any
+// datacenter of the vCenter is searched.
+type vmSearchScope func(vsi *VSphereInstance, datacenter string) bool
+
+// allVMSearchScope searches every datacenter of every vCenter.
+func allVMSearchScope(*VSphereInstance, string) bool {
+	return true
+}
+
+// searchVM searches the VM in the datacenters of the scope concurrently, and
+// returns the first VM found, or the last error other than not finding it.
+func (cm *ConnectionManager) searchVM(ctx context.Context, myNodeID string, searchBy FindVM, scope vmSearchScope) (*VMDiscoveryInfo, error) {
 	type vmSearch struct {
 		tenantRef  string
 		vc         string
+		vsi        *VSphereInstance
 		datacenter *vclib.Datacenter
 	}
 
@@ -63,19 +132,8 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 
 	queueChannel = make(chan *vmSearch, QueueSize)
 
-	myNodeID := nodeID
-	switch searchBy {
-	case FindVMByUUID:
-		klog.V(3).Info("WhichVCandDCByNodeID by UUID")
-		myNodeID = strings.TrimSpace(strings.ToLower(nodeID))
-	case FindVMByIP:
-		klog.V(3).Info("WhichVCandDCByNodeID by IP")
-	default:
-		klog.V(3).Info("WhichVCandDCByNodeID by Name")
-	}
-	klog.V(2).Info("WhichVCandDCByNodeID nodeID: ", myNodeID)
-
 	vmFound := false
+	var vmInfo *VMDiscoveryInfo
 	globalErr = nil
 
 	setGlobalErr := func(err error) {
@@ -84,9 +142,14 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 		globalErrMutex.Unlock()
 	}
 
-	setVMFound := func(found bool) {
+	// setVMFound keeps the VM found first, the VM may be found by several
+	// workers when vCenters share hosts
+	setVMFound := func(info *VMDiscoveryInfo) {
 		mutex.Lock()
-		vmFound = found
+		if !vmFound {
+			vmInfo = info
+			vmFound = true
+		}
 		mutex.Unlock()
 	}
 
@@ -98,13 +161,17 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 	}
 
 	go func() {
-		for _, vsi := range cm.VsphereInstanceMap {
+		for _, vsi := range cm.Instances() {
 			var datacenterObjs []*vclib.Datacenter
 
 			if getVMFound() {
 				break
 			}
 
+			if !scope(vsi, "") {
+				continue
+			}
+
 			var err error
 			for i := 0; i < NumConnectionAttempts; i++ {
 				err = cm.Connect(ctx, vsi)
@@ -120,28 +187,10 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 				continue
 			}
 
-			if vsi.Cfg.Datacenters == "" {
-				datacenterObjs, err = vclib.GetAllDatacenter(ctx, vsi.Conn)
-				if err != nil {
-					klog.Error("WhichVCandDCByNodeID error dc:", err)
-					setGlobalErr(err)
-					continue
-				}
-			} else {
-				datacenters := strings.Split(vsi.Cfg.Datacenters, ",")
-				for _, dc := range datacenters {
-					dc = strings.TrimSpace(dc)
-					if dc == "" {
-						continue
-					}
-					datacenterObj, err := vclib.GetDatacenter(ctx, vsi.Conn, dc)
-					if err != nil {
-						klog.Error("WhichVCandDCByNodeID error dc:", err)
-						setGlobalErr(err)
-						continue
-					}
-					datacenterObjs = append(datacenterObjs, datacenterObj)
-				}
+			datacenterObjs, err = cm.getDatacenters(ctx, vsi)
+			if err != nil {
+				klog.Error("WhichVCandDCByNodeID error dc:", err)
+				setGlobalErr(err)
 			}
 
 			for _, datacenterObj := range datacenterObjs {
@@ -149,10 +198,15 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 					break
 				}
 
-				klog.V(4).Infof("Finding node %s in vc=%s and datacenter=%s", myNodeID, vsi.Cfg.VCenterIP, datacenterObj.Name())
+				if !scope(vsi, datacenterObj.Name()) {
+					continue
+				}
+
+				logging.V(logging.ConnectionManager, 4).Infof("Finding node %s in vc=%s and datacenter=%s", myNodeID, vsi.Cfg.VCenterIP, datacenterObj.Name())
 				queueChannel <- &vmSearch{
 					tenantRef:  vsi.Cfg.TenantRef,
 					vc:         vsi.Cfg.VCenterIP,
+					vsi:        vsi,
 					datacenter: datacenterObj,
 				}
 			}
@@ -160,58 +214,35 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 		close(queueChannel)
 	}()
 
-	var vmInfo *VMDiscoveryInfo
 	for i := 0; i < PoolSize; i++ {
 		wg.Add(1)
 		go func() {
 			for res := range queueChannel {
-				var vm *vclib.VirtualMachine
-				var err error
-
-				switch searchBy {
-				case FindVMByUUID:
-					vm, err = res.datacenter.GetVMByUUID(ctx, myNodeID)
-				case FindVMByIP:
-					vm, err = res.datacenter.GetVMByIP(ctx, myNodeID)
-				default:
-					vm, err = res.datacenter.GetVMByDNSName(ctx, myNodeID)
-				}
-
+				vm, err := findVMInDatacenter(ctx, res.vsi, res.datacenter, myNodeID, searchBy)
 				if err != nil {
 					klog.Errorf("Error while looking for vm=%s(%s) in vc=%s and datacenter=%s: %v",
 						myNodeID, searchBy, res.vc, res.datacenter.Name(), err)
 					if err != vclib.ErrNoVMFound {
 						setGlobalErr(err)
 					} else {
-						klog.V(2).Infof("Did not find node %s in vc=%s and datacenter=%s",
+						logging.V(logging.ConnectionManager, 2).Infof("Did not find node %s in vc=%s and datacenter=%s",
 							myNodeID, res.vc, res.datacenter.Name())
 					}
 					continue
 				}
 
-				var oVM mo.VirtualMachine
-				err = vm.Properties(ctx, vm.Reference(), []string{"config", "summary", "guest"}, &oVM)
+				info, err := newVMDiscoveryInfo(ctx, res.vsi, vm, res.datacenter, myNodeID, searchBy)
 				if err != nil {
 					klog.Errorf("Error collecting properties for vm=%+v in vc=%s and datacenter=%s: %v",
 						vm, res.vc, res.datacenter.Name(), err)
 					continue
 				}
 
-				hostName := oVM.Guest.HostName
-				if searchBy == FindVMByIP {
-					klog.V(2).Infof("WhichVCandDCByNodeID by IP. Overriding VMName from=%s to to=%s", oVM.Guest.HostName, myNodeID)
-					hostName = myNodeID
-				}
+				logging.V(logging.ConnectionManager, 2).Infof("Found node %s as vm=%+v in vc=%s and datacenter=%s",
+					myNodeID, vm, res.vc, res.datacenter.Name())
+				logging.V(logging.ConnectionManager, 2).Infof("Hostname: %s, UUID: %s", info.NodeName, info.UUID)
 
-				UUID := strings.ToLower(strings.TrimSpace(oVM.Summary.Config.Uuid))
-
-				klog.V(2).Infof("Found node %s as vm=%+v in vc=%s and datacenter=%s",
-					nodeID, vm, res.vc, res.datacenter.Name())
-				klog.V(2).Infof("Hostname: %s, UUID: %s", hostName, UUID)
-
-				vmInfo = &VMDiscoveryInfo{TenantRef: res.tenantRef, DataCenter: res.datacenter, VM: vm, VcServer: res.vc,
-					UUID: UUID, NodeName: hostName}
-				setVMFound(true)
+				setVMFound(info)
 				break
 			}
 			wg.Done()
@@ -224,22 +255,88 @@ func (cm *ConnectionManager) WhichVCandDCByNodeID(ctx context.Context, nodeID st
 	if globalErr != nil {
 		return nil, *globalErr
 	}
+	return nil, nil
+}
+
+// findVMInDatacenter finds a VM in the datacenter of the vCenter by UUID, IP
+// or DNS name.
+func findVMInDatacenter(ctx context.Context, vsi *VSphereInstance, datacenter *vclib.Datacenter, nodeID string, searchBy FindVM) (*vclib.VirtualMachine, error) {
+	release, err := vsi.AcquireQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	klog.V(4).Infof("WhichVCandDCByNodeID: %q vm not found", myNodeID)
-	return nil, vclib.ErrNoVMFound
+	switch searchBy {
+	case FindVMByUUID:
+		return datacenter.GetVMByUUID(ctx, nodeID)
+	case FindVMByIP:
+		return findVMByIPInDatacenter(ctx, datacenter, nodeID, vsi.Cfg)
+	default:
+		return datacenter.GetVMByDNSName(ctx, nodeID)
+	}
+}
+
+// findVMByIPInDatacenter first searches the VMs of the networks and datastores
+// hinted in the config of the vCenter, if any, then the whole datacenter.
+func findVMByIPInDatacenter(ctx context.Context, datacenter *vclib.Datacenter, ip string, cfg *vcfg.VirtualCenterConfig) (*vclib.VirtualMachine, error) {
+	if cfg != nil {
+		networks := splitDatacenters(cfg.IPSearchNetworks)
+		datastores := splitDatacenters(cfg.IPSearchDatastores)
+		if len(networks) != 0 || len(datastores) != 0 {
+			vm, err := datacenter.GetVMByIPInScope(ctx, ip, networks, datastores)
+			if err == nil {
+				return vm, nil
+			}
+			if err != vclib.ErrNoVMFound {
+				klog.Warningf("Failed to search vm=%s in the networks %v and datastores %v of datacenter=%s, searching the datacenter: %v",
+					ip, networks, datastores, datacenter.Name(), err)
+			} else {
+				logging.V(logging.ConnectionManager, 3).Infof("vm=%s not found in the networks %v and datastores %v of datacenter=%s, searching the datacenter",
+					ip, networks, datastores, datacenter.Name())
+			}
+		}
+	}
+	return datacenter.GetVMByIP(ctx, ip)
+}
+
+// newVMDiscoveryInfo collects the properties of the VM of the vCenter found
+// for nodeID.
+func newVMDiscoveryInfo(ctx context.Context, vsi *VSphereInstance, vm *vclib.VirtualMachine,
+	datacenter *vclib.Datacenter, nodeID string, searchBy FindVM) (*VMDiscoveryInfo, error) {
+	oVM, err := vsi.VMProperties(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	// the guest is nil if none of its properties that are read is set
+	var hostName string
+	if oVM.Guest != nil {
+		hostName = oVM.Guest.HostName
+	}
+	if searchBy == FindVMByIP {
+		logging.V(logging.ConnectionManager, 2).Infof("WhichVCandDCByNodeID by IP. Overriding VMName from=%s to to=%s", hostName, nodeID)
+		hostName = nodeID
+	}
+
+	UUID := strings.ToLower(strings.TrimSpace(oVM.Summary.Config.Uuid))
+
+	return &VMDiscoveryInfo{TenantRef: vsi.Cfg.TenantRef, DataCenter: datacenter, VM: vm, VcServer: vsi.Cfg.VCenterIP,
+		UUID: UUID, NodeName: hostName}, nil
 }
 
 // WhichVCandDCByFCDId searches for an FCD using the provided ID.
 func (cm *ConnectionManager) WhichVCandDCByFCDId(ctx context.Context, fcdID string) (*FcdDiscoveryInfo, error) {
 	if fcdID == "" {
-		klog.V(3).Info("WhichVCandDCByFCDId called but fcdID is empty")
+		logging.V(logging.ConnectionManager, 3).Info("WhichVCandDCByFCDId called but fcdID is empty")
 		return nil, vclib.ErrNoDiskIDFound
 	}
-	klog.V(2).Info("WhichVCandDCByFCDId fcdID: ", fcdID)
+	logging.V(logging.ConnectionManager, 2).Info("WhichVCandDCByFCDId fcdID: ", fcdID)
 
 	type fcdSearch struct {
 		tenantRef  string
 		vc         string
+		vsi        *VSphereInstance
 		datacenter *vclib.Datacenter
 	}
 
@@ -274,7 +371,7 @@ func (cm *ConnectionManager) WhichVCandDCByFCDId(ctx context.Context, fcdID stri
 	}
 
 	go func() {
-		for _, vsi := range cm.VsphereInstanceMap {
+		for _, vsi := range cm.Instances() {
 			var datacenterObjs []*vclib.Datacenter
 
 			if getFCDFound() {
@@ -325,10 +422,11 @@ func (cm *ConnectionManager) WhichVCandDCByFCDId(ctx context.Context, fcdID stri
 					break
 				}
 
-				klog.V(4).Infof("Finding FCD %s in vc=%s and datacenter=%s", fcdID, vsi.Cfg.VCenterIP, datacenterObj.Name())
+				logging.V(logging.ConnectionManager, 4).Infof("Finding FCD %s in vc=%s and datacenter=%s", fcdID, vsi.Cfg.VCenterIP, datacenterObj.Name())
 				queueChannel <- &fcdSearch{
 					tenantRef:  vsi.Cfg.TenantRef,
 					vc:         vsi.Cfg.VCenterIP,
+					vsi:        vsi,
 					datacenter: datacenterObj,
 				}
 			}
@@ -341,21 +439,26 @@ func (cm *ConnectionManager) WhichVCandDCByFCDId(ctx context.Context, fcdID stri
 		wg.Add(1)
 		go func() {
 			for res := range queueChannel {
-
+				release, err := res.vsi.AcquireQuery(ctx)
+				if err != nil {
+					setGlobalErr(err)
+					continue
+				}
 				fcd, err := res.datacenter.DoesFirstClassDiskExist(ctx, fcdID)
+				release()
 				if err != nil {
 					klog.Errorf("Error while looking for FCD=%+v in vc=%s and datacenter=%s: %v",
 						fcd, res.vc, res.datacenter.Name(), err)
 					if err != vclib.ErrNoDiskIDFound {
 						setGlobalErr(err)
 					} else {
-						klog.V(2).Infof("Did not find FCD %s in vc=%s and datacenter=%s",
+						logging.V(logging.ConnectionManager, 2).Infof("Did not find FCD %s in vc=%s and datacenter=%s",
 							fcdID, res.vc, res.datacenter.Name())
 					}
 					continue
 				}
 
-				klog.V(2).Infof("Found FCD %s as vm=%+v in vc=%s and datacenter=%s",
+				logging.V(logging.ConnectionManager, 2).Infof("Found FCD %s as vm=%+v in vc=%s and datacenter=%s",
 					fcdID, fcd, res.vc, res.datacenter.Name())
 
 				fcdInfo = &FcdDiscoveryInfo{TenantRef: res.tenantRef, DataCenter: res.datacenter, FCDInfo: fcd, VcServer: res.vc}
@@ -373,6 +476,6 @@ func (cm *ConnectionManager) WhichVCandDCByFCDId(ctx context.Context, fcdID stri
 		return nil, *globalErr
 	}
 
-	klog.V(4).Infof("WhichVCandDCByFCDId: %q FCD not found", fcdID)
+	logging.V(logging.ConnectionManager, 4).Infof("WhichVCandDCByFCDId: %q FCD not found", fcdID)
 	return nil, vclib.ErrNoDiskIDFound
 }
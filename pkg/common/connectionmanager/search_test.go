@@ -22,7 +22,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
 	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
 )
 
@@ -90,6 +92,115 @@ func TestWhichVCandDCByNodeIdByName(t *testing.T) {
 	}
 }
 
+func TestWhichVCandDCByNodeIDPinned(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	UUID := vm.Config.Uuid
+	ctx := context.Background()
+
+	info, err := connMgr.WhichVCandDCByNodeID(ctx, UUID, FindVMByUUID)
+	if err != nil {
+		t.Fatalf("WhichVCandDCByNodeID err=%v", err)
+	}
+	vcenter := config.Global.VCenterIP
+	datacenter := info.DataCenter.Name()
+
+	testCases := []struct {
+		name   string
+		pin    VCenterPin
+		result string
+	}{
+		{name: "pinned vCenter", pin: VCenterPin{VCenter: vcenter}, result: "found"},
+		{name: "pinned datacenter", pin: ParseVCenterPin(vcenter + "/" + datacenter), result: "found"},
+		{name: "other datacenter", pin: VCenterPin{VCenter: vcenter, Datacenter: "other"}, result: "fallback"},
+		{name: "unknown vCenter", pin: VCenterPin{VCenter: "vc.example.com"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var metric float64
+			if testCase.result != "" {
+				metric = testutil.ToFloat64(pinnedSearchesMetric.WithLabelValues(testCase.pin.VCenter, testCase.result))
+			}
+
+			info, err := connMgr.WhichVCandDCByNodeIDPinned(ctx, UUID, FindVMByUUID, testCase.pin)
+			if err != nil {
+				t.Fatalf("WhichVCandDCByNodeIDPinned err=%v", err)
+			}
+			if !strings.EqualFold(UUID, info.UUID) {
+				t.Errorf("expected VM %s, found %s", UUID, info.UUID)
+			}
+			// the simulator finds the VM by UUID in any datacenter
+			if testCase.result == "found" && testCase.pin.Datacenter != "" && info.DataCenter.Name() != testCase.pin.Datacenter {
+				t.Errorf("expected VM in the pinned datacenter %s, found it in %s", testCase.pin.Datacenter, info.DataCenter.Name())
+			}
+
+			if testCase.result != "" {
+				if count := testutil.ToFloat64(pinnedSearchesMetric.WithLabelValues(testCase.pin.VCenter, testCase.result)) - metric; count != 1 {
+					t.Errorf("expected 1 pinned search with result %s, got %v", testCase.result, count)
+				}
+			}
+		})
+	}
+}
+
+func TestWhichVCandDCByNodeIdByIPInScope(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+
+	// setup
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	// the search index of the simulator only knows the primary IP
+	primaryIP := "10.20.30.40"
+	secondaryIP := "10.20.30.41"
+	portgroup := simulator.Map.Get(vm.Network[0]).(*simulator.DistributedVirtualPortgroup)
+	// the simulator does not track the VMs of a portgroup
+	portgroup.Vm = append(portgroup.Vm, vm.Reference())
+	network := portgroup.Name
+	vm.Guest.IpAddress = primaryIP
+	vm.Guest.Net = []types.GuestNicInfo{{Network: network, IpAddress: []string{primaryIP, secondaryIP}}}
+	UUID := vm.Config.Uuid
+	datastore := vm.Datastore[0].Value
+
+	testCases := []struct {
+		name       string
+		ip         string
+		networks   string
+		datastores string
+	}{
+		{name: "network name", ip: secondaryIP, networks: network},
+		{name: "datastore moid", ip: secondaryIP, datastores: datastore},
+		{name: "fallback to the datacenter", ip: primaryIP, datastores: "datastore-0"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, vcConfig := range config.VirtualCenter {
+				vcConfig.IPSearchNetworks = testCase.networks
+				vcConfig.IPSearchDatastores = testCase.datastores
+			}
+			connMgr := NewConnectionManager(config, nil, nil)
+			defer connMgr.Logout()
+
+			info, err := connMgr.WhichVCandDCByNodeID(context.Background(), testCase.ip, FindVMByIP)
+			if err != nil {
+				t.Fatalf("WhichVCandDCByNodeID err=%v", err)
+			}
+			if !strings.EqualFold(UUID, info.UUID) {
+				t.Errorf("VM UUID mismatch %s=%s", UUID, info.UUID)
+			}
+			if info.NodeName != testCase.ip {
+				t.Errorf("VM name mismatch %s=%s", testCase.ip, info.NodeName)
+			}
+		})
+	}
+}
+
 func TestWhichVCandDCByFCDId(t *testing.T) {
 	config, cleanup := configFromEnvOrSim(true)
 	defer cleanup()
@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// ListTagsInCategory returns the names of the tags of the given category
+// attached to the managed object, which unlike the zone lookup does not
+// search its ancestors.
+func (cm *ConnectionManager) ListTagsInCategory(ctx context.Context, tenantRef string,
+	moRef types.ManagedObjectReference, category string) ([]string, error) {
+
+	vsi := cm.Instances()[tenantRef]
+	if vsi == nil {
+		klog.Errorf("Unable to find Connection for tenantRef=%s", tenantRef)
+		return nil, ErrConnectionNotFound
+	}
+
+	var names []string
+	err := withTagsClient(ctx, vsi.Conn, func(c *rest.Client) error {
+		client := tags.NewManager(c)
+
+		cat, err := client.GetCategory(ctx, category)
+		if err != nil {
+			return err
+		}
+		attached, err := client.GetAttachedTags(ctx, moRef)
+		if err != nil {
+			return err
+		}
+		for _, tag := range attached {
+			if tag.CategoryID == cat.ID {
+				names = append(names, tag.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.V(logging.ConnectionManager, 4).Infof("Found tags %v of category %s attached to %s", names, category, moRef)
+	return names, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+)
+
+func TestListTagsInCategory(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	ctx := context.Background()
+	vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+
+	restClient := rest.NewClient(vsi.Conn.Client)
+	user := url.UserPassword(vsi.Conn.Username, vsi.Conn.Password)
+	if err := restClient.Login(ctx, user); err != nil {
+		t.Fatalf("Rest login failed. err=%v", err)
+	}
+	m := tags.NewManager(restClient)
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	attach := func(category string, names ...string) {
+		categoryID, err := m.CreateCategory(ctx, &tags.Category{Name: category, Cardinality: "MULTIPLE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			tagID, err := m.CreateTag(ctx, &tags.Tag{CategoryID: categoryID, Name: name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.AttachTag(ctx, tagID, vm); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	attach("k8s-labels", "rack=r12", "gpu")
+	attach("other", "ignored")
+
+	names, err := connMgr.ListTagsInCategory(ctx, config.Global.VCenterIP, vm.Reference(), "k8s-labels")
+	if err != nil {
+		t.Fatalf("ListTagsInCategory failed: %v", err)
+	}
+	sort.Strings(names)
+	if expected := []string{"gpu", "rack=r12"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected tags %v, but got %v", expected, names)
+	}
+
+	if _, err := connMgr.ListTagsInCategory(ctx, "missing", vm.Reference(), "k8s-labels"); err != ErrConnectionNotFound {
+		t.Errorf("expected ErrConnectionNotFound, but got %v", err)
+	}
+}
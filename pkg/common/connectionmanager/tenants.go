@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	informerv1 "k8s.io/client-go/informers/core/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// tenantConfig is a vCenter added from a tenant config.
+type tenantConfig struct {
+	// namespace/name/key of the ConfigMap holding the tenant config
+	source string
+	// copy of the vCenter config, as the config of the instance is updated
+	// by the rescope unlisted datacenter policy
+	cfg vcfg.VirtualCenterConfig
+}
+
+// WatchTenantConfigs adds the vCenters of the tenant configs held in the
+// ConfigMaps of configMapInformer to the vCenters of the cloud config, and
+// reconciles them whenever a ConfigMap is added, updated or deleted.
+func (connMgr *ConnectionManager) WatchTenantConfigs(configMapInformer informerv1.ConfigMapInformer) {
+	lister := configMapInformer.Lister()
+	reconcile := func(interface{}) {
+		connMgr.reconcileTenantConfigs(lister)
+	}
+	_, err := configMapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: reconcile,
+		UpdateFunc: func(_, obj interface{}) {
+			reconcile(obj)
+		},
+		DeleteFunc: reconcile,
+	})
+	if err != nil {
+		klog.Errorf("Failed to watch the tenant configs: %v", err)
+	}
+}
+
+// IsTenantVCenter returns whether the vCenter of tenantRef was added from a
+// tenant config rather than from the cloud config.
+func (connMgr *ConnectionManager) IsTenantVCenter(tenantRef string) bool {
+	connMgr.tenantConfigsLock.Lock()
+	defer connMgr.tenantConfigsLock.Unlock()
+
+	_, ok := connMgr.tenantConfigs[tenantRef]
+	return ok
+}
+
+// reconcileTenantConfigs reads the tenant configs of the ConfigMaps of lister
+// and adds, updates or removes the vCenters that changed since the previous
+// reconciliation. The sessions of the vCenters that are updated or removed are
+// logged out. A tenant config that cannot be read keeps the vCenters it added
+// before, and a vCenter of the cloud config is never replaced.
+func (connMgr *ConnectionManager) reconcileTenantConfigs(lister listerv1.ConfigMapLister) {
+	connMgr.tenantConfigsLock.Lock()
+	defer connMgr.tenantConfigsLock.Unlock()
+
+	configMaps, err := lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list the tenant configs: %v", err)
+		return
+	}
+	sort.Slice(configMaps, func(i, j int) bool {
+		return configMaps[i].Namespace+"/"+configMaps[i].Name < configMaps[j].Namespace+"/"+configMaps[j].Name
+	})
+
+	current := connMgr.Instances()
+
+	desired := make(map[string]*tenantConfig)
+	add := func(tenantRef string, tc *tenantConfig) {
+		if _, ok := current[tenantRef]; ok && connMgr.tenantConfigs[tenantRef] == nil {
+			klog.Warningf("Ignoring vCenter %s of tenant config %s, it is a vCenter of the cloud config", tenantRef, tc.source)
+			return
+		}
+		if other, ok := desired[tenantRef]; ok {
+			klog.Warningf("Ignoring vCenter %s of tenant config %s, it is already added by %s", tenantRef, tc.source, other.source)
+			return
+		}
+		desired[tenantRef] = tc
+	}
+	for _, configMap := range configMaps {
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			source := configMap.Namespace + "/" + configMap.Name + "/" + key
+			cfg, err := vcfg.ReadTenantConfig([]byte(configMap.Data[key]))
+			if err != nil {
+				klog.Errorf("Failed to read tenant config %s, keeping its vCenters: %v", source, err)
+				for tenantRef, tc := range connMgr.tenantConfigs {
+					if tc.source == source {
+						add(tenantRef, tc)
+					}
+				}
+				continue
+			}
+			for tenantRef, vcConfig := range cfg.VirtualCenter {
+				add(tenantRef, &tenantConfig{source: source, cfg: *vcConfig})
+			}
+		}
+	}
+
+	var removed []*VSphereInstance
+	connMgr.Lock()
+	current = connMgr.Instances()
+	instances := make(map[string]*VSphereInstance, len(current))
+	for tenantRef, vsi := range current {
+		instances[tenantRef] = vsi
+	}
+	for tenantRef := range connMgr.tenantConfigs {
+		if _, ok := desired[tenantRef]; !ok {
+			logging.V(logging.ConnectionManager, 2).Infof("Removing vCenter %s of the tenant configs", tenantRef)
+			removed = append(removed, instances[tenantRef])
+			delete(instances, tenantRef)
+		}
+	}
+	for tenantRef, tc := range desired {
+		if previous, ok := connMgr.tenantConfigs[tenantRef]; ok {
+			if reflect.DeepEqual(previous.cfg, tc.cfg) {
+				continue
+			}
+			logging.V(logging.ConnectionManager, 2).Infof("Updating vCenter %s of tenant config %s", tenantRef, tc.source)
+			removed = append(removed, instances[tenantRef])
+		} else {
+			logging.V(logging.ConnectionManager, 2).Infof("Adding vCenter %s of tenant config %s", tenantRef, tc.source)
+		}
+		vcConfig := tc.cfg
+		vsi := newVSphereInstance(&vcConfig)
+		if vcConfig.SecretName != "" && connMgr.credentialManagers[vcConfig.SecretRef] == nil {
+			connMgr.initializeTenantSecretLister(vsi)
+		}
+		instances[tenantRef] = vsi
+	}
+	connMgr.setInstances(instances)
+	connMgr.Unlock()
+	connMgr.tenantConfigs = desired
+
+	for _, vsi := range removed {
+		if vsi == nil {
+			continue
+		}
+		vsi.vmProperties.stop()
+		connMgr.Lock()
+		c := vsi.Conn.Client
+		connMgr.Unlock()
+		if c != nil {
+			vsi.Conn.Logout(context.Background())
+		}
+	}
+}
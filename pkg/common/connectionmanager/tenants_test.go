@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+func tenantConfigMap(name, config string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: name},
+		Data:       map[string]string{"vsphere.conf": config},
+	}
+}
+
+func TestReconcileTenantConfigs(t *testing.T) {
+	connMgr := NewConnectionManager(&vcfg.Config{
+		VirtualCenter: map[string]*vcfg.VirtualCenterConfig{
+			"static": {TenantRef: "static", VCenterIP: "10.0.0.1", User: "user", Password: "password"},
+		},
+	}, nil, nil)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	lister := listerv1.NewConfigMapLister(indexer)
+
+	tenantA := tenantConfigMap("tenant-a", `
+vcenter:
+  tenant-a:
+    server: 10.1.0.1
+    user: user-a
+    password: password-a
+  static:
+    server: 10.1.0.2
+    user: user-a
+    password: password-a
+`)
+	if err := indexer.Add(tenantA); err != nil {
+		t.Fatal(err)
+	}
+	connMgr.reconcileTenantConfigs(lister)
+
+	static := connMgr.VsphereInstanceMap["static"]
+	if static == nil || static.Cfg.VCenterIP != "10.0.0.1" {
+		t.Fatalf("vCenter of the cloud config should not be replaced, got %+v", static)
+	}
+	added := connMgr.VsphereInstanceMap["tenant-a"]
+	if added == nil || added.Cfg.VCenterIP != "10.1.0.1" {
+		t.Fatalf("vCenter of the tenant config should be added, got %+v", added)
+	}
+	if !connMgr.IsTenantVCenter("tenant-a") || connMgr.IsTenantVCenter("static") {
+		t.Error("only the vCenter of the tenant config should be a tenant vCenter")
+	}
+
+	// an unchanged tenant config keeps the connection of its vCenter
+	connMgr.reconcileTenantConfigs(lister)
+	if connMgr.VsphereInstanceMap["tenant-a"] != added {
+		t.Error("unchanged vCenter should keep its instance")
+	}
+
+	// a tenant config that cannot be read keeps its vCenters
+	if err := indexer.Update(tenantConfigMap("tenant-a", "vcenter: [")); err != nil {
+		t.Fatal(err)
+	}
+	connMgr.reconcileTenantConfigs(lister)
+	if connMgr.VsphereInstanceMap["tenant-a"] != added {
+		t.Error("vCenter of an invalid tenant config should be kept")
+	}
+
+	if err := indexer.Update(tenantConfigMap("tenant-a", `
+vcenter:
+  tenant-a:
+    server: 10.1.0.1
+    port: 8443
+    user: user-a
+    password: password-a
+`)); err != nil {
+		t.Fatal(err)
+	}
+	connMgr.reconcileTenantConfigs(lister)
+	updated := connMgr.VsphereInstanceMap["tenant-a"]
+	if updated == added || updated.Conn.Port != "8443" {
+		t.Errorf("updated vCenter should be replaced, got port %s", updated.Conn.Port)
+	}
+
+	if err := indexer.Delete(tenantA); err != nil {
+		t.Fatal(err)
+	}
+	connMgr.reconcileTenantConfigs(lister)
+	if _, ok := connMgr.VsphereInstanceMap["tenant-a"]; ok {
+		t.Error("vCenter of the deleted tenant config should be removed")
+	}
+	if connMgr.VsphereInstanceMap["static"] != static {
+		t.Error("vCenter of the cloud config should be kept")
+	}
+}
+
+func TestReconcileTenantConfigsWhileDiscovering(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	lister := listerv1.NewConfigMapLister(indexer)
+
+	// the tenant vCenter is the simulator as well, so that the discoveries
+	// search it while it is added and removed
+	tenantA := tenantConfigMap("tenant-a", fmt.Sprintf(`
+vcenter:
+  tenant-a:
+    server: %s
+    port: %s
+    user: %s
+    password: %s
+    insecureFlag: true
+    datacenters:
+      - DC0
+      - DC1
+`, config.Global.VCenterIP, config.Global.VCenterPort, config.Global.User, config.Global.Password))
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	ctx := context.Background()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				_ = indexer.Add(tenantA)
+			} else {
+				_ = indexer.Delete(tenantA)
+			}
+			connMgr.reconcileTenantConfigs(lister)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		if _, err := connMgr.WhichVCandDCByNodeID(ctx, vm.Config.Uuid, FindVMByUUID); err != nil {
+			t.Errorf("WhichVCandDCByNodeID err=%v", err)
+		}
+		_ = connMgr.ConnectionStates()
+	}
+	close(stop)
+	<-done
+}
@@ -33,20 +33,42 @@ type ConnectionManager struct {
 	// The k8s client init from the cloud provider service account
 	client clientset.Interface
 
-	// Maps the VC server to VSphereInstance
+	// Maps the VC server to VSphereInstance. The vCenters of the tenant
+	// configs replace the map instead of modifying it, read it with
+	// Instances.
 	VsphereInstanceMap map[string]*VSphereInstance
+	// Guards VsphereInstanceMap, never held while taking another lock
+	instancesLock sync.RWMutex
 	// CredentialManager per VC
 	// The global CredentialManager will have an entry in this map with the key of "Global"
 	credentialManagers map[string]*cm.CredentialManager
 	// InformerManagers per VC
 	// The global InformerManager will have an entry in this map with the key of "Global"
 	informerManagers map[string]*k8s.InformerManager
+
+	// Maps the datacenter names of each VC to the moid they last resolved
+	// to, to keep finding renamed datacenters
+	datacenterMoids map[string]string
+	// Guards datacenterMoids and the DatacenterMoids of the VC configs,
+	// which grow with the rescope unlisted datacenter policy
+	datacenterMoidsLock sync.Mutex
+
+	// Maps the tenant ref of the vCenters added from tenant configs to the
+	// config they were added with
+	tenantConfigs map[string]*tenantConfig
+	// Guards tenantConfigs and serializes their reconciliations
+	tenantConfigsLock sync.Mutex
 }
 
 // VSphereInstance represents a vSphere instance where one or more kubernetes nodes are running.
 type VSphereInstance struct {
 	Conn *vclib.VSphereConnection
 	Cfg  *vcfg.VirtualCenterConfig
+
+	// Semaphore of the queries in flight, nil if they are not limited
+	queries chan struct{}
+	// Properties of the VMs, when watched
+	vmProperties vmPropertyCache
 }
 
 // VMDiscoveryInfo contains VM info about a discovered VM
@@ -0,0 +1,263 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+
+	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
+)
+
+// VMDiscoveryProperties are the properties of the VMs read to discover
+// nodes, rather than their whole config, summary and guest, which are large
+// on clusters with many VMs.
+var VMDiscoveryProperties = []string{
+	"config.createDate",
+	"config.extraConfig",
+	"config.instanceUuid",
+	"guest.hostName",
+	"guest.net",
+	"summary.config.guestId",
+	"summary.config.memorySizeMB",
+	"summary.config.numCpu",
+	"summary.config.uuid",
+}
+
+// vmCacheWatchPeriod is the period at which the watches of the vCenters not
+// watched yet, or whose watch failed, are started.
+var vmCacheWatchPeriod = 30 * time.Second
+
+// vmPropertyCache holds the VMDiscoveryProperties of the VMs of the
+// datacenters of a vCenter, kept up to date by a property collector watching
+// them.
+type vmPropertyCache struct {
+	sync.RWMutex
+
+	// Maps the VMs to their properties, only complete once synced
+	vms    map[types.ManagedObjectReference]*mo.VirtualMachine
+	synced bool
+	// Cancels the watch of the VMs, nil if they are not watched
+	cancel context.CancelFunc
+}
+
+// CacheVMProperties watches the properties of the VMs of the connected
+// vCenters until ctx is done, so that discovering a node reads the
+// properties of its VM from the cache rather than querying vCenter, which
+// matters on clusters with many nodes. The watch of a vCenter is started once
+// it is connected to, and restarted if it fails, until then the properties
+// are queried.
+func (connMgr *ConnectionManager) CacheVMProperties(ctx context.Context) {
+	wait.UntilWithContext(ctx, connMgr.startVMPropertyWatches, vmCacheWatchPeriod)
+}
+
+// startVMPropertyWatches starts the watches of the connected vCenters that
+// are not watched.
+func (connMgr *ConnectionManager) startVMPropertyWatches(ctx context.Context) {
+	connMgr.Lock()
+	defer connMgr.Unlock()
+
+	for tenantRef, vsi := range connMgr.Instances() {
+		if vsi.Conn.Client == nil {
+			continue
+		}
+		c := &vsi.vmProperties
+		c.Lock()
+		if c.cancel == nil {
+			logging.V(logging.ConnectionManager, 2).Infof("Watching the VM properties of vCenter %s", tenantRef)
+			watchCtx, cancel := context.WithCancel(ctx)
+			c.cancel = cancel
+			go connMgr.watchVMProperties(watchCtx, tenantRef, vsi)
+		}
+		c.Unlock()
+	}
+}
+
+// watchVMProperties updates the VM property cache of vsi until ctx is done or
+// the watch fails, after which the cache is cleared.
+func (connMgr *ConnectionManager) watchVMProperties(ctx context.Context, tenantRef string, vsi *VSphereInstance) {
+	err := connMgr.watchVMs(ctx, vsi)
+	if err != nil && ctx.Err() == nil {
+		klog.Warningf("Failed to watch the VM properties of vCenter %s, querying them until the watch is restarted: %v", tenantRef, err)
+	}
+
+	c := &vsi.vmProperties
+	c.Lock()
+	defer c.Unlock()
+	c.vms = nil
+	c.synced = false
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// watchVMs watches the VMDiscoveryProperties of the VMs of vsi through
+// container views of its datacenters, or of its root folder if it lists
+// none, applying the updates to the cache. The VMs of the datacenters listed
+// later on, or not found, are not cached and their properties are queried.
+func (connMgr *ConnectionManager) watchVMs(ctx context.Context, vsi *VSphereInstance) error {
+	connMgr.Lock()
+	client := vsi.Conn.Client
+	connMgr.Unlock()
+	if client == nil {
+		return nil
+	}
+
+	containers := []types.ManagedObjectReference{client.ServiceContent.RootFolder}
+	if len(splitDatacenters(vsi.Cfg.Datacenters)) > 0 || len(splitDatacenters(connMgr.listedDatacenterMoids(vsi))) > 0 {
+		datacenters, err := connMgr.getDatacenters(ctx, vsi)
+		if len(datacenters) == 0 {
+			return err
+		}
+		if err != nil {
+			klog.Warningf("Not watching the VM properties of the datacenters of vCenter %s that are not found: %v", vsi.Cfg.VCenterIP, err)
+		}
+		containers = containers[:0]
+		for _, datacenter := range datacenters {
+			containers = append(containers, datacenter.Reference())
+		}
+	}
+
+	m := view.NewManager(client)
+	filter := new(property.WaitFilter)
+	for _, container := range containers {
+		v, err := m.CreateContainerView(ctx, container, []string{"VirtualMachine"}, true)
+		if err != nil {
+			return err
+		}
+		// the view is destroyed even if ctx is done
+		defer func() {
+			_ = v.Destroy(context.Background())
+		}()
+		filter.Spec.ObjectSet = append(filter.Spec.ObjectSet, types.ObjectSpec{
+			Obj:  v.Reference(),
+			Skip: types.NewBool(true),
+			SelectSet: []types.BaseSelectionSpec{&types.TraversalSpec{
+				Type: "ContainerView",
+				Path: "view",
+			}},
+		})
+	}
+	filter.Spec.PropSet = []types.PropertySpec{{Type: "VirtualMachine", PathSet: VMDiscoveryProperties}}
+
+	pc, err := property.DefaultCollector(client).Create(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = pc.Destroy(context.Background())
+	}()
+
+	return property.WaitForUpdatesEx(ctx, pc, filter, func(updates []types.ObjectUpdate) bool {
+		vsi.vmProperties.apply(updates, !filter.Truncated)
+		return false
+	})
+}
+
+// apply applies the updates of the watch to the cache, which is synced once
+// the initial updates are no longer truncated.
+func (c *vmPropertyCache) apply(updates []types.ObjectUpdate, complete bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.vms == nil {
+		c.vms = make(map[types.ManagedObjectReference]*mo.VirtualMachine)
+	}
+	for _, update := range updates {
+		switch update.Kind {
+		case types.ObjectUpdateKindEnter, types.ObjectUpdateKindModify:
+			// the cached VMs are shared with the callers of get, so they
+			// are replaced rather than modified, along with the structs
+			// the changes of the watched properties are applied to. The
+			// changes of a watched property replace it as a whole.
+			vm := &mo.VirtualMachine{}
+			if cached, ok := c.vms[update.Obj]; ok {
+				*vm = *cached
+				if vm.Config != nil {
+					config := *vm.Config
+					vm.Config = &config
+				}
+				if vm.Guest != nil {
+					guest := *vm.Guest
+					vm.Guest = &guest
+				}
+			}
+			vm.Self = update.Obj
+			mo.ApplyPropertyChange(vm, update.ChangeSet)
+			c.vms[update.Obj] = vm
+		case types.ObjectUpdateKindLeave:
+			delete(c.vms, update.Obj)
+		}
+	}
+	if complete {
+		c.synced = true
+	}
+}
+
+// get returns the cached properties of vm, false if the cache is not synced
+// yet. The returned properties must not be modified.
+func (c *vmPropertyCache) get(vm types.ManagedObjectReference) (*mo.VirtualMachine, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if !c.synced {
+		return nil, false
+	}
+	oVM, ok := c.vms[vm]
+	return oVM, ok
+}
+
+// stop stops the watch of the VMs, if they are watched.
+func (c *vmPropertyCache) stop() {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// VMProperties returns the VMDiscoveryProperties of vm, read from the VM
+// property cache of the vCenter when it is watched, else queried.
+func (vsi *VSphereInstance) VMProperties(ctx context.Context, vm *vclib.VirtualMachine) (*mo.VirtualMachine, error) {
+	if oVM, ok := vsi.vmProperties.get(vm.Reference()); ok {
+		vmPropertiesMetric.WithLabelValues(vsi.Cfg.VCenterIP, "cache").Inc()
+		return oVM, nil
+	}
+
+	release, err := vsi.AcquireQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	vmPropertiesMetric.WithLabelValues(vsi.Cfg.VCenterIP, "query").Inc()
+	var oVM mo.VirtualMachine
+	if err := vm.Properties(ctx, vm.Reference(), VMDiscoveryProperties, &oVM); err != nil {
+		return nil, err
+	}
+	return &oVM, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionmanager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestCacheVMProperties(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+	vm.Guest.HostName = strings.ToLower(vm.Name)
+	UUID := vm.Config.Uuid
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the watches start once the vCenter is connected to
+	info, err := connMgr.WhichVCandDCByNodeID(ctx, UUID, FindVMByUUID)
+	if err != nil {
+		t.Fatalf("WhichVCandDCByNodeID err=%v", err)
+	}
+	vsi := connMgr.VsphereInstanceMap[info.TenantRef]
+	connMgr.startVMPropertyWatches(ctx)
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		_, ok := vsi.vmProperties.get(vm.Self)
+		return ok, nil
+	})
+	if err != nil {
+		t.Fatalf("VM properties should be cached: %v", err)
+	}
+
+	reads := testutil.ToFloat64(vmPropertiesMetric.WithLabelValues(info.VcServer, "cache"))
+	oVM, err := vsi.VMProperties(ctx, info.VM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oVM.Guest.HostName != vm.Guest.HostName || oVM.Summary.Config.Uuid != UUID {
+		t.Errorf("Cached properties should be the ones of the VM but actual=%s %s", oVM.Guest.HostName, oVM.Summary.Config.Uuid)
+	}
+	if cached := testutil.ToFloat64(vmPropertiesMetric.WithLabelValues(info.VcServer, "cache")); cached != reads+1 {
+		t.Errorf("Properties should be read from the cache, reads=%v", cached-reads)
+	}
+
+	// updates of the properties are applied to the cache
+	simulator.Map.Update(simulator.SpoofContext(), vm, []types.PropertyChange{{Name: "guest.hostName", Val: "renamed"}})
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		oVM, _ := vsi.vmProperties.get(vm.Self)
+		return oVM != nil && oVM.Guest.HostName == "renamed", nil
+	})
+	if err != nil {
+		t.Errorf("Updated properties should be cached: %v", err)
+	}
+
+	// a stopped watch clears the cache, the properties are queried
+	vsi.vmProperties.stop()
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		_, ok := vsi.vmProperties.get(vm.Self)
+		return !ok, nil
+	})
+	if err != nil {
+		t.Errorf("Stopped watch should clear the cache: %v", err)
+	}
+	queried := testutil.ToFloat64(vmPropertiesMetric.WithLabelValues(info.VcServer, "query"))
+	if _, err := vsi.VMProperties(ctx, info.VM); err != nil {
+		t.Fatal(err)
+	}
+	if actual := testutil.ToFloat64(vmPropertiesMetric.WithLabelValues(info.VcServer, "query")); actual != queried+1 {
+		t.Errorf("Properties should be queried, queries=%v", actual-queried)
+	}
+}
+
+func TestCacheVMPropertiesOfListedDatacenters(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(true)
+	defer cleanup()
+	config.VirtualCenter[config.Global.VCenterIP].Datacenters = "DC0"
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Fatal(err)
+	}
+	var listed, unlisted *simulator.VirtualMachine
+	for _, obj := range simulator.Map.All("VirtualMachine") {
+		vm := obj.(*simulator.VirtualMachine)
+		if strings.HasPrefix(vm.Name, "DC0_") {
+			listed = vm
+		} else {
+			unlisted = vm
+		}
+	}
+
+	connMgr.startVMPropertyWatches(ctx)
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		_, ok := vsi.vmProperties.get(listed.Self)
+		return ok, nil
+	})
+	if err != nil {
+		t.Fatalf("VM properties of the listed datacenter should be cached: %v", err)
+	}
+	if _, ok := vsi.vmProperties.get(unlisted.Self); ok {
+		t.Errorf("VM properties of the unlisted datacenter %s should not be cached", unlisted.Name)
+	}
+
+	// only the properties read to discover the nodes are cached
+	oVM, _ := vsi.vmProperties.get(listed.Self)
+	if oVM.Summary.Config.Uuid != listed.Config.Uuid || oVM.Config == nil || oVM.Config.InstanceUuid != listed.Config.InstanceUuid {
+		t.Errorf("Discovery properties should be cached, got %+v", oVM.Summary.Config)
+	}
+	if oVM.Config.Hardware.NumCPU != 0 || oVM.Summary.Runtime.PowerState != "" || oVM.Summary.Config.Name != "" {
+		t.Error("Properties not read to discover the nodes should not be cached")
+	}
+}
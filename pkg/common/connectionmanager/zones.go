@@ -34,6 +34,7 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 
 	vclib "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+	"k8s.io/cloud-provider-vsphere/pkg/util/logging"
 )
 
 // Well-known keys for k/v maps
@@ -49,10 +50,10 @@ const (
 // WhichVCandDCByZone gets the corresponding VC+DC combo that supports the availability zone
 func (cm *ConnectionManager) WhichVCandDCByZone(ctx context.Context,
 	zoneLabel string, regionLabel string, zoneLooking string, regionLooking string) (*ZoneDiscoveryInfo, error) {
-	klog.V(4).Infof("WhichVCandDCByZone called with zone: %s and region: %s", zoneLooking, regionLooking)
+	logging.V(logging.ConnectionManager, 4).Infof("WhichVCandDCByZone called with zone: %s and region: %s", zoneLooking, regionLooking)
 
 	// Need at least one VC
-	numOfVCs := len(cm.VsphereInstanceMap)
+	numOfVCs := len(cm.Instances())
 	if numOfVCs == 0 {
 		err := ErrMustHaveAtLeastOneVCDC
 		klog.Errorf("%v", err)
@@ -70,9 +71,10 @@ func (cm *ConnectionManager) WhichVCandDCByZone(ctx context.Context,
 
 func (cm *ConnectionManager) getDIFromSingleVC(ctx context.Context,
 	zoneLabel string, regionLabel string, zoneLooking string, regionLooking string) (*ZoneDiscoveryInfo, error) {
-	klog.V(4).Infof("getDIFromSingleVC called with zone: %s and region: %s", zoneLooking, regionLooking)
+	logging.V(logging.ConnectionManager, 4).Infof("getDIFromSingleVC called with zone: %s and region: %s", zoneLooking, regionLooking)
 
-	if len(cm.VsphereInstanceMap) != 1 {
+	instances := cm.Instances()
+	if len(instances) != 1 {
 		err := ErrUnsupportedConfiguration
 		klog.Errorf("%v", err)
 		return nil, err
@@ -82,7 +84,7 @@ func (cm *ConnectionManager) getDIFromSingleVC(ctx context.Context,
 
 	// Get first vSphere Instance
 	var tmpVsi *VSphereInstance
-	for _, tmpVsi = range cm.VsphereInstanceMap {
+	for _, tmpVsi = range instances {
 		break //Grab the first one because there is only one
 	}
 
@@ -126,7 +128,7 @@ func (cm *ConnectionManager) getDIFromSingleVC(ctx context.Context,
 
 func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 	zoneLabel string, regionLabel string, zoneLooking string, regionLooking string) (*ZoneDiscoveryInfo, error) {
-	klog.V(4).Infof("getDIFromMultiVCorDC called with zone: %s and region: %s", zoneLooking, regionLooking)
+	logging.V(logging.ConnectionManager, 4).Infof("getDIFromMultiVCorDC called with zone: %s and region: %s", zoneLooking, regionLooking)
 
 	if len(zoneLabel) == 0 || len(regionLabel) == 0 || len(zoneLooking) == 0 || len(regionLooking) == 0 {
 		err := ErrMultiVCRequiresZones
@@ -172,12 +174,16 @@ func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 	}
 
 	go func() {
-		for _, vsi := range cm.VsphereInstanceMap {
+		for _, vsi := range cm.Instances() {
 			var datacenterObjs []*vclib.Datacenter
 
 			if getZoneFound() {
 				break
 			}
+			if vsi.Cfg.Region != "" && !strings.EqualFold(vsi.Cfg.Region, regionLooking) {
+				logging.V(logging.ConnectionManager, 4).Infof("Skipping vc=%s of region %s", vsi.Cfg.VCenterIP, vsi.Cfg.Region)
+				continue
+			}
 
 			var err error
 			for i := 0; i < NumConnectionAttempts; i++ {
@@ -194,28 +200,10 @@ func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 				continue
 			}
 
-			if vsi.Cfg.Datacenters == "" {
-				datacenterObjs, err = vclib.GetAllDatacenter(ctx, vsi.Conn)
-				if err != nil {
-					klog.Error("getDIFromMultiVCorDC error dc:", err)
-					setGlobalErr(err)
-					continue
-				}
-			} else {
-				datacenters := strings.Split(vsi.Cfg.Datacenters, ",")
-				for _, dc := range datacenters {
-					dc = strings.TrimSpace(dc)
-					if dc == "" {
-						continue
-					}
-					datacenterObj, err := vclib.GetDatacenter(ctx, vsi.Conn, dc)
-					if err != nil {
-						klog.Error("getDIFromMultiVCorDC error dc:", err)
-						setGlobalErr(err)
-						continue
-					}
-					datacenterObjs = append(datacenterObjs, datacenterObj)
-				}
+			datacenterObjs, err = cm.getDatacenters(ctx, vsi)
+			if err != nil {
+				klog.Error("getDIFromMultiVCorDC error dc:", err)
+				setGlobalErr(err)
 			}
 
 			for _, datacenterObj := range datacenterObjs {
@@ -233,7 +221,7 @@ func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 				}
 
 				for _, host := range hostList {
-					klog.V(3).Infof("Finding zone in vc=%s and datacenter=%s for host: %s", vsi.Cfg.VCenterIP, datacenterObj.Name(), host.Name())
+					logging.V(logging.ConnectionManager, 3).Infof("Finding zone in vc=%s and datacenter=%s for host: %s", vsi.Cfg.VCenterIP, datacenterObj.Name(), host.Name())
 					queueChannel <- &zoneSearch{
 						tenantRef:  vsi.Cfg.TenantRef,
 						vc:         vsi.Cfg.VCenterIP,
@@ -252,7 +240,7 @@ func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 		go func() {
 			for res := range queueChannel {
 
-				klog.V(3).Infof("Checking zones for host: %s", res.host.Name())
+				logging.V(logging.ConnectionManager, 3).Infof("Checking zones for host: %s", res.host.Name())
 				result, err := cm.LookupZoneByMoref(ctx, res.tenantRef, res.host.Reference(), zoneLabel, regionLabel)
 				if err != nil {
 					klog.Errorf("Failed to find zone: %s and region: %s for host %s", zoneLabel, regionLabel, res.host.Name())
@@ -261,7 +249,7 @@ func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 
 				if !strings.EqualFold(result[ZoneLabel], zoneLooking) ||
 					!strings.EqualFold(result[RegionLabel], regionLooking) {
-					klog.V(4).Infof("Does not match region: %s and zone: %s", result[RegionLabel], result[ZoneLabel])
+					logging.V(logging.ConnectionManager, 4).Infof("Does not match region: %s and zone: %s", result[RegionLabel], result[ZoneLabel])
 					continue
 				}
 
@@ -286,10 +274,39 @@ func (cm *ConnectionManager) getDIFromMultiVCorDC(ctx context.Context,
 		return nil, *globalErr
 	}
 
-	klog.V(4).Infof("getDIFromMultiVCorDC: zone: %s and region: %s not found", zoneLabel, regionLabel)
+	logging.V(logging.ConnectionManager, 4).Infof("getDIFromMultiVCorDC: zone: %s and region: %s not found", zoneLabel, regionLabel)
 	return nil, vclib.ErrNoZoneRegionFound
 }
 
+// zoneCategories returns the tag categories of the zone and the region looked
+// up for the VMs of the vCenter, and the region the vCenter is mapped to. The
+// zone category of the vCenter overrides zoneLabel, and the region category is
+// not looked up for a vCenter mapped to a region.
+func (vsi *VSphereInstance) zoneCategories(zoneLabel, regionLabel string) (string, string, string) {
+	if vsi.Cfg.ZoneCategoryOverride != "" {
+		zoneLabel = vsi.Cfg.ZoneCategoryOverride
+	}
+	if vsi.Cfg.Region != "" {
+		regionLabel = ""
+	}
+	return zoneLabel, regionLabel, vsi.Cfg.Region
+}
+
+// HasVCenterTopology returns true if a vCenter is mapped to a region or has its
+// own zone category, so that the topology of the nodes is looked up even if the
+// zone and region categories of the Labels section are not both set.
+func (cm *ConnectionManager) HasVCenterTopology() bool {
+	cm.Lock()
+	defer cm.Unlock()
+
+	for _, vsi := range cm.Instances() {
+		if vsi.Cfg.Region != "" || vsi.Cfg.ZoneCategoryOverride != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func withTagsClient(ctx context.Context, connection *vclib.VSphereConnection, f func(c *rest.Client) error) error {
 	c := rest.NewClient(connection.Client)
 	signer, err := connection.Signer(ctx, connection.Client)
@@ -320,14 +337,28 @@ func (cm *ConnectionManager) LookupZoneByMoref(ctx context.Context, tenantRef st
 
 	result := make(map[string]string)
 
-	vsi := cm.VsphereInstanceMap[tenantRef]
+	vsi := cm.Instances()[tenantRef]
 	if vsi == nil {
 		err := ErrConnectionNotFound
 		klog.Errorf("Unable to find Connection for tenantRef=%s", tenantRef)
 		return nil, err
 	}
 
-	err := withTagsClient(ctx, vsi.Conn, func(c *rest.Client) error {
+	// the region of a vCenter mapped to a region is not looked up
+	zoneLabel, regionLabel, region := vsi.zoneCategories(zoneLabel, regionLabel)
+	if region != "" {
+		result[RegionLabel] = region
+	}
+
+	// the zone lookups of the nodes share the query slots of the vCenter
+	// with their discovery
+	release, err := vsi.AcquireQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	err = withTagsClient(ctx, vsi.Conn, func(c *rest.Client) error {
 		client := tags.NewManager(c)
 
 		pc := vsi.Conn.Client.ServiceContent.PropertyCollector
@@ -341,7 +372,7 @@ func (cm *ConnectionManager) LookupZoneByMoref(ctx context.Context, tenantRef st
 		// search the hierarchy, example order: ["Host", "Cluster", "Datacenter", "Folder"]
 		for i := range objects {
 			obj := objects[len(objects)-1-i]
-			klog.V(4).Infof("Name: %s, Type: %s", obj.Self.Value, obj.Self.Type)
+			logging.V(logging.ConnectionManager, 4).Infof("Name: %s, Type: %s", obj.Self.Value, obj.Self.Type)
 			tags, err := client.ListAttachedTags(ctx, obj)
 			if err != nil {
 				klog.Errorf("Cannot list attached tags. Err: %v", err)
@@ -360,13 +391,13 @@ func (cm *ConnectionManager) LookupZoneByMoref(ctx context.Context, tenantRef st
 				}
 
 				found := func() {
-					klog.V(2).Infof("Found %s tag (%s) attached to %s", category.Name, tag.Name, moRef)
+					logging.V(logging.ConnectionManager, 2).Infof("Found %s tag (%s) attached to %s", category.Name, tag.Name, moRef)
 				}
 				switch {
 				case category.Name == zoneLabel:
 					result[ZoneLabel] = tag.Name
 					found()
-				case category.Name == regionLabel:
+				case regionLabel != "" && category.Name == regionLabel:
 					result[RegionLabel] = tag.Name
 					found()
 				}
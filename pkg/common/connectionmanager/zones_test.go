@@ -18,9 +18,11 @@ package connectionmanager
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vapi/rest"
@@ -260,4 +262,68 @@ func TestLookupZoneByMoref(t *testing.T) {
 	if !strings.EqualFold("k8s-zone-US-east", zone) {
 		t.Errorf("Region value mismatch k8s-zone-US-east != %s", zone)
 	}
+
+	// the lookup waits for a query slot of the vCenter
+	vsi.queries = make(chan struct{}, 1)
+	vsi.queries <- struct{}{}
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := connMgr.LookupZoneByMoref(waitCtx, config.Global.VCenterIP, myHost.Reference(), config.Labels.Zone, config.Labels.Region); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the lookup over the limit of concurrent queries to wait, got %v", err)
+	}
+}
+
+func TestLookupZoneByMorefVCenterTopology(t *testing.T) {
+	config, cleanup := configFromEnvOrSim(false)
+	defer cleanup()
+
+	connMgr := NewConnectionManager(config, nil, nil)
+	defer connMgr.Logout()
+
+	ctx := context.Background()
+
+	vsi := connMgr.VsphereInstanceMap[config.Global.VCenterIP]
+	if connMgr.HasVCenterTopology() {
+		t.Error("no vCenter should be mapped to a region yet")
+	}
+	vsi.Cfg.Region = "site-a"
+	vsi.Cfg.ZoneCategoryOverride = "k8s-rack"
+	if !connMgr.HasVCenterTopology() {
+		t.Error("vCenter should be mapped to a region")
+	}
+
+	if err := connMgr.Connect(ctx, vsi); err != nil {
+		t.Fatalf("Failed to Connect to vSphere: %s", err)
+	}
+	restClient := rest.NewClient(vsi.Conn.Client)
+	user := url.UserPassword(vsi.Conn.Username, vsi.Conn.Password)
+	if err := restClient.Login(ctx, user); err != nil {
+		t.Fatalf("Rest login failed. err=%v", err)
+	}
+	m := tags.NewManager(restClient)
+
+	// the host is only tagged with the zone category of the vCenter
+	myHost := simulator.Map.Any("HostSystem").(*simulator.HostSystem)
+	rackID, err := m.CreateCategory(ctx, &tags.Category{Name: "k8s-rack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rackID, err = m.CreateTag(ctx, &tags.Tag{CategoryID: rackID, Name: "rack-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.AttachTag(ctx, rackID, myHost); err != nil {
+		t.Fatal(err)
+	}
+
+	kv, err := connMgr.LookupZoneByMoref(ctx, config.Global.VCenterIP, myHost.Reference(), config.Labels.Zone, config.Labels.Region)
+	if err != nil {
+		t.Fatalf("LookupZoneByMoref failed err=%v", err)
+	}
+	if kv[RegionLabel] != "site-a" {
+		t.Errorf("Region should be the region of the vCenter but actual=%s", kv[RegionLabel])
+	}
+	if kv[ZoneLabel] != "rack-1" {
+		t.Errorf("Zone should be the tag of the zone category of the vCenter but actual=%s", kv[ZoneLabel])
+	}
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CloudConfigChecksumAnnotation holds the checksum of the cloud config
+	// the cloud provider runs with.
+	CloudConfigChecksumAnnotation = "vsphere.k8s.io/cloud-config-checksum"
+
+	checksumKindConfigMap = "configmap"
+	checksumKindLease     = "lease"
+)
+
+// ParseChecksumObject splits an object reference of the form
+// <kind>/<namespace>/<name>, the kind being configmap or lease.
+func ParseChecksumObject(object string) (kind, namespace, name string, err error) {
+	parts := strings.Split(object, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid object %q, must be <kind>/<namespace>/<name>", object)
+	}
+	kind = strings.ToLower(parts[0])
+	if kind != checksumKindConfigMap && kind != checksumKindLease {
+		return "", "", "", fmt.Errorf("invalid object kind %q, must be %s or %s", parts[0], checksumKindConfigMap, checksumKindLease)
+	}
+	return kind, parts[1], parts[2], nil
+}
+
+// AnnotateConfigChecksum sets the CloudConfigChecksumAnnotation of the
+// ConfigMap or Lease referenced as <kind>/<namespace>/<name>, so that
+// automation can restart the cloud provider only when the effective cloud
+// config changes. A missing Lease is created, a missing ConfigMap is an
+// error.
+func AnnotateConfigChecksum(ctx context.Context, client clientset.Interface, object, checksum string) error {
+	return annotateObject(ctx, client, object, map[string]string{CloudConfigChecksumAnnotation: checksum})
+}
+
+// annotateObject sets the annotations of the ConfigMap or Lease referenced as
+// <kind>/<namespace>/<name>, creating a missing Lease. The object is not
+// updated if it already has the annotations.
+func annotateObject(ctx context.Context, client clientset.Interface, object string, annotations map[string]string) error {
+	kind, namespace, name, err := ParseChecksumObject(object)
+	if err != nil {
+		return err
+	}
+
+	if kind == checksumKindConfigMap {
+		configMaps := client.CoreV1().ConfigMaps(namespace)
+		configMap, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !setAnnotations(&configMap.ObjectMeta, annotations) {
+			return nil
+		}
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	}
+
+	leases := client.CoordinationV1().Leases(namespace)
+	lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+		setAnnotations(&lease.ObjectMeta, annotations)
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !setAnnotations(&lease.ObjectMeta, annotations) {
+		return nil
+	}
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// setAnnotations sets the annotations of meta and returns whether any of them
+// changed.
+func setAnnotations(meta *metav1.ObjectMeta, annotations map[string]string) bool {
+	changed := false
+	for key, value := range annotations {
+		if current, ok := meta.Annotations[key]; ok && current == value {
+			continue
+		}
+		metav1.SetMetaDataAnnotation(meta, key, value)
+		changed = true
+	}
+	return changed
+}
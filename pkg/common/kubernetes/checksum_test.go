@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseChecksumObject(t *testing.T) {
+	kind, namespace, name, err := ParseChecksumObject("ConfigMap/kube-system/vsphere-cloud-config")
+	if err != nil || kind != "configmap" || namespace != "kube-system" || name != "vsphere-cloud-config" {
+		t.Errorf("Unexpected %q %q %q %v", kind, namespace, name, err)
+	}
+
+	for _, object := range []string{"kube-system/vsphere-cloud-config", "secret/kube-system/name", "lease//name"} {
+		if _, _, _, err := ParseChecksumObject(object); err == nil {
+			t.Errorf("Should fail on %q", object)
+		}
+	}
+}
+
+func TestAnnotateConfigChecksum(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "vsphere-cloud-config",
+			Namespace:   "kube-system",
+			Annotations: map[string]string{"other": "value"},
+		},
+	})
+
+	if err := AnnotateConfigChecksum(ctx, client, "configmap/kube-system/vsphere-cloud-config", "abc"); err != nil {
+		t.Fatalf("Failed to annotate ConfigMap: %s", err)
+	}
+	cm, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, "vsphere-cloud-config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Annotations[CloudConfigChecksumAnnotation] != "abc" || cm.Annotations["other"] != "value" {
+		t.Errorf("Unexpected ConfigMap annotations %v", cm.Annotations)
+	}
+
+	if err := AnnotateConfigChecksum(ctx, client, "configmap/kube-system/missing", "abc"); err == nil {
+		t.Error("Should fail on a missing ConfigMap")
+	}
+
+	for _, checksum := range []string{"abc", "def"} {
+		if err := AnnotateConfigChecksum(ctx, client, "lease/kube-system/vsphere-cloud-config", checksum); err != nil {
+			t.Fatalf("Failed to annotate Lease: %s", err)
+		}
+		lease, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "vsphere-cloud-config", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lease.Annotations[CloudConfigChecksumAnnotation] != checksum {
+			t.Errorf("Unexpected Lease annotations %v", lease.Annotations)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+)
+
+// CloudConfigReloadAnnotation holds the ConfigReloadStatus, as JSON, of the
+// last load of the cloud config.
+const CloudConfigReloadAnnotation = "vsphere.k8s.io/cloud-config-reload"
+
+// ConfigReloadResult is the result of loading the cloud config.
+type ConfigReloadResult string
+
+const (
+	// ConfigReloadApplied is the result of a cloud config that is fully
+	// applied.
+	ConfigReloadApplied ConfigReloadResult = "Applied"
+	// ConfigReloadPartial is the result of a cloud config that is applied
+	// without some of its sections, which could not be read.
+	ConfigReloadPartial ConfigReloadResult = "Partial"
+	// ConfigReloadRejected is the result of a cloud config that could not be
+	// applied, the cloud provider does not run with it.
+	ConfigReloadRejected ConfigReloadResult = "Rejected"
+)
+
+// ConfigReloadStatus is the outcome of loading the cloud config, so that
+// GitOps pipelines can verify that a config change took effect.
+type ConfigReloadStatus struct {
+	// Result is whether the cloud config was applied
+	Result ConfigReloadResult `json:"result"`
+	// Reason explains a partial or rejected result
+	Reason string `json:"reason,omitempty"`
+	// Checksum is the checksum of the loaded cloud config, empty if it could
+	// not be read
+	Checksum string `json:"checksum,omitempty"`
+	// Time is the time the cloud config was loaded
+	Time metav1.Time `json:"time"`
+}
+
+// configReloadMetric is 1 for the result of the last load of the cloud
+// config, 0 for the other results.
+var configReloadMetric = metrics.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cloud_config_reload_result",
+		Help: "Result of the last load of the cloud config, 1 for the current result and 0 for the others",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	legacyregistry.RawMustRegister(configReloadMetric)
+}
+
+// RecordConfigReload sets the metric of the result of the last load of the
+// cloud config.
+func RecordConfigReload(status ConfigReloadStatus) {
+	for _, result := range []ConfigReloadResult{ConfigReloadApplied, ConfigReloadPartial, ConfigReloadRejected} {
+		value := 0.0
+		if result == status.Result {
+			value = 1
+		}
+		configReloadMetric.WithLabelValues(string(result)).Set(value)
+	}
+}
+
+// AnnotateConfigReloadStatus sets the CloudConfigReloadAnnotation of the
+// ConfigMap or Lease referenced as <kind>/<namespace>/<name> to the status.
+// The CloudConfigChecksumAnnotation is also set unless the cloud config was
+// rejected, as it holds the checksum of the config the cloud provider runs
+// with. A missing Lease is created, a missing ConfigMap is an error.
+func AnnotateConfigReloadStatus(ctx context.Context, client clientset.Interface, object string, status ConfigReloadStatus) error {
+	b, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{CloudConfigReloadAnnotation: string(b)}
+	if status.Result != ConfigReloadRejected && status.Checksum != "" {
+		annotations[CloudConfigChecksumAnnotation] = status.Checksum
+	}
+	return annotateObject(ctx, client, object, annotations)
+}
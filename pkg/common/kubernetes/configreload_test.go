@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAnnotateConfigReloadStatus(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	object := "lease/kube-system/vsphere-cloud-config"
+
+	read := func() (map[string]string, ConfigReloadStatus) {
+		t.Helper()
+		lease, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "vsphere-cloud-config", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var status ConfigReloadStatus
+		if err := json.Unmarshal([]byte(lease.Annotations[CloudConfigReloadAnnotation]), &status); err != nil {
+			t.Fatal(err)
+		}
+		return lease.Annotations, status
+	}
+
+	applied := ConfigReloadStatus{Result: ConfigReloadApplied, Checksum: "abc", Time: metav1.Now()}
+	if err := AnnotateConfigReloadStatus(ctx, client, object, applied); err != nil {
+		t.Fatal(err)
+	}
+	annotations, status := read()
+	if status.Result != ConfigReloadApplied || annotations[CloudConfigChecksumAnnotation] != "abc" {
+		t.Errorf("Unexpected annotations of the applied config %v", annotations)
+	}
+
+	// a rejected config keeps the checksum of the config the cloud provider
+	// runs with
+	rejected := ConfigReloadStatus{Result: ConfigReloadRejected, Reason: "invalid YAML", Checksum: "def", Time: metav1.Now()}
+	if err := AnnotateConfigReloadStatus(ctx, client, object, rejected); err != nil {
+		t.Fatal(err)
+	}
+	annotations, status = read()
+	if status.Result != ConfigReloadRejected || status.Reason != "invalid YAML" || status.Checksum != "def" {
+		t.Errorf("Unexpected status of the rejected config %+v", status)
+	}
+	if annotations[CloudConfigChecksumAnnotation] != "abc" {
+		t.Errorf("Checksum of the rejected config should not be published, got %v", annotations)
+	}
+}
+
+func TestRecordConfigReload(t *testing.T) {
+	RecordConfigReload(ConfigReloadStatus{Result: ConfigReloadPartial})
+	expected := `
+# HELP cloudprovider_vsphere_cloud_config_reload_result Result of the last load of the cloud config, 1 for the current result and 0 for the others
+# TYPE cloudprovider_vsphere_cloud_config_reload_result gauge
+cloudprovider_vsphere_cloud_config_reload_result{result="Applied"} 0
+cloudprovider_vsphere_cloud_config_reload_result{result="Partial"} 1
+cloudprovider_vsphere_cloud_config_reload_result{result="Rejected"} 0
+`
+	if err := testutil.CollectAndCompare(configReloadMetric, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	informerv1 "k8s.io/client-go/informers/core/v1"
@@ -118,6 +119,19 @@ func (im *InformerManager) getSecretInformer(namespace string) informerv1.Secret
 	return secretInformer
 }
 
+// NewConfigMapInformer creates an informer of the ConfigMaps of namespace
+// matching labelSelector, which is started by the next call to Listen.
+func (im *InformerManager) NewConfigMapInformer(namespace, labelSelector string) informerv1.ConfigMapInformer {
+	factory := informers.NewSharedInformerFactoryWithOptions(im.client, noResyncPeriodFunc(),
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		}))
+	im.namespacedInformerFactories[namespace+"/"+labelSelector] = factory
+
+	return factory.Core().V1().ConfigMaps()
+}
+
 // AddNodeListener hooks up add, update, delete callbacks
 func (im *InformerManager) AddNodeListener(add, remove func(obj interface{}), update func(oldObj, newObj interface{})) {
 	if im.nodeInformer == nil {
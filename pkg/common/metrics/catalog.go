@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const markdownHeader = `# Metrics
+
+<!-- This file is generated by pkg/common/metrics/gen. DO NOT EDIT. -->
+
+All metrics exposed by the vSphere cloud provider are prefixed with ` + "`" + Prefix + "`" + `.
+
+| Name | Type | Labels | Description |
+|------|------|--------|-------------|
+`
+
+// WriteMarkdown writes the descriptors as a markdown table.
+func WriteMarkdown(w io.Writer, descs []Descriptor) error {
+	if _, err := io.WriteString(w, markdownHeader); err != nil {
+		return err
+	}
+	for _, d := range descs {
+		labels := make([]string, len(d.Labels))
+		for i, l := range d.Labels {
+			labels[i] = "`" + l + "`"
+		}
+		if _, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n",
+			d.Name, d.Type, strings.Join(labels, ", "), d.Help); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the descriptors as an indented JSON array.
+func WriteJSON(w io.Writer, descs []Descriptor) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(descs)
+}
@@ -0,0 +1,26 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics exposed by the vSphere
+// cloud provider. Metrics created through this package are named under the
+// cloudprovider_vsphere_ prefix and are recorded in a catalog from which
+// docs/book/metrics.md and docs/book/metrics.json are generated.
+//
+// Packages defining metrics must be imported by ./gen so they show up in
+// the catalog.
+package metrics
+
+//go:generate go run ./gen -markdown ../../../docs/book/metrics.md -json ../../../docs/book/metrics.json
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gen writes the markdown and JSON catalog of the metrics defined through
+// k8s.io/cloud-provider-vsphere/pkg/common/metrics.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
+
+	// packages defining metrics
+	_ "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere"
+	_ "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphere/loadbalancer"
+	_ "k8s.io/cloud-provider-vsphere/pkg/cloudprovider/vsphereparavirtual"
+	_ "k8s.io/cloud-provider-vsphere/pkg/common/connectionmanager"
+	_ "k8s.io/cloud-provider-vsphere/pkg/common/vclib"
+)
+
+func main() {
+	markdownPath := flag.String("markdown", "metrics.md", "path of the generated markdown catalog")
+	jsonPath := flag.String("json", "metrics.json", "path of the generated JSON catalog")
+	flag.Parse()
+
+	descs := metrics.Catalog()
+
+	var md bytes.Buffer
+	if err := metrics.WriteMarkdown(&md, descs); err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(*markdownPath, md.Bytes(), 0644); err != nil {
+		fail(err)
+	}
+
+	var js bytes.Buffer
+	if err := metrics.WriteJSON(&js, descs); err != nil {
+		fail(err)
+	}
+	if err := os.WriteFile(*jsonPath, js.Bytes(), 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	os.Exit(1)
+}
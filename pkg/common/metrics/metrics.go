@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Namespace is the metric namespace shared by every metric exposed by
+	// this binary.
+	Namespace = "cloudprovider"
+	// Subsystem is the metric subsystem shared by every metric exposed by
+	// this binary.
+	Subsystem = "vsphere"
+	// Prefix is the fully qualified prefix of every metric name.
+	Prefix = Namespace + "_" + Subsystem + "_"
+)
+
+// Metric types as reported in the catalog
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Descriptor describes a single metric exposed by this binary.
+type Descriptor struct {
+	// Name is the fully qualified metric name.
+	Name string `json:"name"`
+	// Type is one of counter, gauge or histogram.
+	Type string `json:"type"`
+	// Help is the help text of the metric.
+	Help string `json:"help"`
+	// Labels are the variable label names of the metric.
+	Labels []string `json:"labels,omitempty"`
+}
+
+var (
+	catalogLock sync.Mutex
+	catalog     = make(map[string]Descriptor)
+)
+
+// record adds the metric to the catalog. Defining the same metric twice is a
+// programming error and panics, the same way a duplicate registration does.
+func record(name, metricType, help string, labels []string) {
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+
+	if _, ok := catalog[name]; ok {
+		panic("duplicate metric definition: " + name)
+	}
+	catalog[name] = Descriptor{
+		Name:   name,
+		Type:   metricType,
+		Help:   help,
+		Labels: append([]string(nil), labels...),
+	}
+}
+
+// Catalog returns the descriptors of all metrics defined through this
+// package, sorted by name.
+func Catalog() []Descriptor {
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+
+	descs := make([]Descriptor, 0, len(catalog))
+	for _, d := range catalog {
+		descs = append(descs, d)
+	}
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].Name < descs[j].Name
+	})
+	return descs
+}
+
+// NewCounterVec creates a CounterVec under the cloudprovider_vsphere_ prefix
+// and records it in the catalog. opts.Namespace and opts.Subsystem are
+// overwritten.
+func NewCounterVec(opts prometheus.CounterOpts, labels []string) *prometheus.CounterVec {
+	opts.Namespace, opts.Subsystem = Namespace, Subsystem
+	record(prometheus.BuildFQName(Namespace, Subsystem, opts.Name), TypeCounter, opts.Help, labels)
+	return prometheus.NewCounterVec(opts, labels)
+}
+
+// NewGaugeVec creates a GaugeVec under the cloudprovider_vsphere_ prefix
+// and records it in the catalog. opts.Namespace and opts.Subsystem are
+// overwritten.
+func NewGaugeVec(opts prometheus.GaugeOpts, labels []string) *prometheus.GaugeVec {
+	opts.Namespace, opts.Subsystem = Namespace, Subsystem
+	record(prometheus.BuildFQName(Namespace, Subsystem, opts.Name), TypeGauge, opts.Help, labels)
+	return prometheus.NewGaugeVec(opts, labels)
+}
+
+// NewHistogramVec creates a HistogramVec under the cloudprovider_vsphere_
+// prefix and records it in the catalog. opts.Namespace and opts.Subsystem
+// are overwritten.
+func NewHistogramVec(opts prometheus.HistogramOpts, labels []string) *prometheus.HistogramVec {
+	opts.Namespace, opts.Subsystem = Namespace, Subsystem
+	record(prometheus.BuildFQName(Namespace, Subsystem, opts.Name), TypeHistogram, opts.Help, labels)
+	return prometheus.NewHistogramVec(opts, labels)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsArePrefixed(t *testing.T) {
+	c := NewCounterVec(prometheus.CounterOpts{Name: "test_prefixed_total", Help: "test counter"}, []string{"a"})
+	c.WithLabelValues("x").Inc()
+
+	expected := `
+# HELP cloudprovider_vsphere_test_prefixed_total test counter
+# TYPE cloudprovider_vsphere_test_prefixed_total counter
+cloudprovider_vsphere_test_prefixed_total{a="x"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	NewGaugeVec(prometheus.GaugeOpts{Name: "test_catalog_b", Help: "gauge"}, []string{"x", "y"})
+	NewHistogramVec(prometheus.HistogramOpts{Name: "test_catalog_a", Help: "histogram"}, nil)
+
+	var a, b *Descriptor
+	descs := Catalog()
+	for i := range descs {
+		switch descs[i].Name {
+		case Prefix + "test_catalog_a":
+			a = &descs[i]
+		case Prefix + "test_catalog_b":
+			b = &descs[i]
+		}
+		if i > 0 && descs[i-1].Name >= descs[i].Name {
+			t.Errorf("catalog is not sorted: %q >= %q", descs[i-1].Name, descs[i].Name)
+		}
+	}
+	if a == nil || b == nil {
+		t.Fatalf("expected both metrics in catalog, got %v", descs)
+	}
+	if a.Type != TypeHistogram || len(a.Labels) != 0 {
+		t.Errorf("unexpected descriptor %+v", *a)
+	}
+	if b.Type != TypeGauge || strings.Join(b.Labels, ",") != "x,y" {
+		t.Errorf("unexpected descriptor %+v", *b)
+	}
+}
+
+func TestDuplicateDefinitionPanics(t *testing.T) {
+	NewCounterVec(prometheus.CounterOpts{Name: "test_duplicate", Help: "dup"}, nil)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate definition to panic")
+		}
+	}()
+	NewCounterVec(prometheus.CounterOpts{Name: "test_duplicate", Help: "dup"}, nil)
+}
+
+func TestWriteCatalog(t *testing.T) {
+	descs := []Descriptor{
+		{Name: Prefix + "foo_total", Type: TypeCounter, Help: "foo help", Labels: []string{"a", "b"}},
+	}
+
+	var md bytes.Buffer
+	if err := WriteMarkdown(&md, descs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "| `cloudprovider_vsphere_foo_total` | counter | `a`, `b` | foo help |") {
+		t.Errorf("unexpected markdown:\n%s", md.String())
+	}
+
+	var js bytes.Buffer
+	if err := WriteJSON(&js, descs); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Descriptor
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].Name != descs[0].Name || decoded[0].Type != TypeCounter {
+		t.Errorf("unexpected JSON round trip: %+v", decoded)
+	}
+}
@@ -22,12 +22,14 @@ import (
 	"encoding/pem"
 	"net"
 	neturl "net/url"
+	"strings"
 	"sync"
 
 	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/sts"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
+	"k8s.io/client-go/util/flowcontrol"
 	klog "k8s.io/klog/v2"
 )
 
@@ -44,15 +46,58 @@ type VSphereConnection struct {
 	Port              string
 	CACert            string
 	Thumbprint        string
+	IdentitySource    string
+	UserAgent         string
 	Insecure          bool
 	RoundTripperCount uint
-	credentialsLock   sync.Mutex
+	// TLSMinVersion is the minimum TLS version, 0 for the Go default.
+	TLSMinVersion uint16
+	// TLSCipherSuites are the cipher suites up to TLS 1.2, nil for the Go
+	// defaults.
+	TLSCipherSuites []uint16
+	// RateLimiter delays the API requests of the clients of the connection,
+	// nil for no limit. It is shared by the clients replacing each other,
+	// so that reconnecting does not reset it.
+	RateLimiter flowcontrol.RateLimiter
+	// MaxIdleConnsPerHost is the number of idle HTTP connections to vCenter
+	// kept open for the next API requests, 0 for the Go default of 2.
+	MaxIdleConnsPerHost int
+	credentialsLock     sync.Mutex
+	tlsLogOnce          sync.Once
 }
 
 var (
 	clientLock sync.Mutex
+
+	// Version is set by the main program to the version of the application
+	Version string
+	// ClusterName is set by the main program to the name of the cluster
+	ClusterName string
 )
 
+// DefaultUserAgent returns the user agent naming the cloud provider, its
+// version and the cluster it runs in, e.g.
+// "k8s-cloud-provider-vsphere/v1.32.0 (cluster prod)".
+func DefaultUserAgent() string {
+	userAgent := userAgentName
+	if Version != "" {
+		userAgent += "/" + Version
+	}
+	if ClusterName != "" {
+		userAgent += " (cluster " + ClusterName + ")"
+	}
+	return userAgent
+}
+
+// GetUserAgent returns the user agent sent to vCenter, which also identifies
+// the sessions of the connection in the vCenter session list.
+func (connection *VSphereConnection) GetUserAgent() string {
+	if connection.UserAgent != "" {
+		return connection.UserAgent
+	}
+	return DefaultUserAgent()
+}
+
 // Connect makes connection to vCenter and sets VSphereConnection.Client.
 // If connection.Client is already set, it obtains the existing user session.
 // if user session is not valid, connection.Client will be set to the new client.
@@ -79,6 +124,9 @@ func (connection *VSphereConnection) Connect(ctx context.Context) error {
 		return nil
 	}
 	klog.Warning("Creating new client session since the existing session is not valid or not authenticated")
+	// Release the connections of the client being replaced, they would
+	// otherwise stay open until they time out.
+	connection.Client.CloseIdleConnections()
 
 	connection.Client, err = connection.NewClient(ctx)
 	if err != nil {
@@ -88,15 +136,57 @@ func (connection *VSphereConnection) Connect(ctx context.Context) error {
 	return nil
 }
 
+// SessionIsActive reports whether the session of connection.Client is still
+// authenticated. Like SessionManager.SessionIsActive it fails once the session
+// is terminated or timed out, but it does not require the
+// Sessions.ValidateSession privilege, and it does not depend on the session
+// cached by the manager logging in, which is not connection.Client's.
+func (connection *VSphereConnection) SessionIsActive(ctx context.Context) (bool, error) {
+	clientLock.Lock()
+	client := connection.Client
+	clientLock.Unlock()
+	if client == nil {
+		return false, nil
+	}
+	userSession, err := session.NewManager(client).UserSession(ctx)
+	if err != nil {
+		return false, err
+	}
+	return userSession != nil, nil
+}
+
+// qualifiedUsername returns the username qualified with the configured
+// identity source, unless the username already names a domain.
+func (connection *VSphereConnection) qualifiedUsername() string {
+	if connection.IdentitySource == "" || strings.ContainsAny(connection.Username, "@\\") {
+		return connection.Username
+	}
+	return connection.Username + "@" + connection.IdentitySource
+}
+
+// usesCertificate reports whether the username value is a PEM encoded certificate.
+func (connection *VSphereConnection) usesCertificate() bool {
+	b, _ := pem.Decode([]byte(connection.Username))
+	return b != nil
+}
+
 // Signer returns an sts.Signer for use with SAML token auth if connection is configured for such.
-// Returns nil if username/password auth is configured for the connection.
+// Returns nil if plain username/password auth is configured for the connection.
 func (connection *VSphereConnection) Signer(ctx context.Context, client *vim25.Client) (*sts.Signer, error) {
 	// TODO: Add separate fields for certificate and private-key.
 	// For now we can leave the config structs and validation as-is and
 	// decide to use LoginByToken if the username value is PEM encoded.
-	b, _ := pem.Decode([]byte(connection.Username))
-	if b == nil {
-		return nil, nil
+	if !connection.usesCertificate() {
+		if connection.IdentitySource == "" {
+			return nil, nil
+		}
+		// Federated accounts are not found by the default domain selection
+		// of SessionManager.Login, request a bearer token for the
+		// qualified user from the STS instead.
+		return connection.issueToken(ctx, client, sts.TokenRequest{
+			Userinfo:    neturl.UserPassword(connection.qualifiedUsername(), connection.Password),
+			Delegatable: true,
+		})
 	}
 
 	cert, err := tls.X509KeyPair([]byte(connection.Username), []byte(connection.Password))
@@ -105,17 +195,20 @@ func (connection *VSphereConnection) Signer(ctx context.Context, client *vim25.C
 		return nil, err
 	}
 
+	return connection.issueToken(ctx, client, sts.TokenRequest{
+		Certificate: &cert,
+		Delegatable: true,
+	})
+}
+
+// issueToken requests a SAML token from the vCenter STS.
+func (connection *VSphereConnection) issueToken(ctx context.Context, client *vim25.Client, req sts.TokenRequest) (*sts.Signer, error) {
 	tokens, err := sts.NewClient(ctx, client)
 	if err != nil {
 		klog.Errorf("Failed to create STS client. err: %+v", err)
 		return nil, err
 	}
 
-	req := sts.TokenRequest{
-		Certificate: &cert,
-		Delegatable: true,
-	}
-
 	signer, err := tokens.Issue(ctx, req)
 	if err != nil {
 		klog.Errorf("Failed to issue SAML token. err: %+v", err)
@@ -125,8 +218,8 @@ func (connection *VSphereConnection) Signer(ctx context.Context, client *vim25.C
 	return signer, nil
 }
 
-// login calls SessionManager.LoginByToken if certificate and private key are configured,
-// otherwise calls SessionManager.Login with user and password.
+// login calls SessionManager.LoginByToken if certificate and private key or an
+// identity source are configured, otherwise calls SessionManager.Login with user and password.
 func (connection *VSphereConnection) login(ctx context.Context, client *vim25.Client) error {
 	m := session.NewManager(client)
 	connection.credentialsLock.Lock()
@@ -142,7 +235,11 @@ func (connection *VSphereConnection) login(ctx context.Context, client *vim25.Cl
 		return m.Login(ctx, neturl.UserPassword(connection.Username, connection.Password))
 	}
 
-	klog.V(3).Infof("SessionManager.LoginByToken with certificate %q", connection.Username)
+	if !connection.usesCertificate() {
+		klog.V(3).Infof("SessionManager.LoginByToken with username %q", connection.qualifiedUsername())
+	} else {
+		klog.V(3).Infof("SessionManager.LoginByToken with certificate %q", connection.Username)
+	}
 
 	header := soap.Header{Security: signer}
 
@@ -166,6 +263,7 @@ func (connection *VSphereConnection) NewClient(ctx context.Context) (*vim25.Clie
 	}
 
 	sc := soap.NewClient(url, connection.Insecure)
+	sc.UserAgent = connection.GetUserAgent()
 
 	if ca := connection.CACert; ca != "" {
 		if err := sc.SetRootCAs(ca); err != nil {
@@ -176,12 +274,19 @@ func (connection *VSphereConnection) NewClient(ctx context.Context) (*vim25.Clie
 	tpHost := connection.Hostname + ":" + connection.Port
 	sc.SetThumbprint(tpHost, connection.Thumbprint)
 
+	t := sc.DefaultTransport()
+	t.TLSClientConfig.MinVersion = connection.TLSMinVersion
+	t.TLSClientConfig.CipherSuites = connection.TLSCipherSuites
+	t.DialTLSContext = connection.verifyTLS(t.DialTLSContext)
+	if connection.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = connection.MaxIdleConnsPerHost
+	}
+
 	client, err := vim25.NewClient(ctx, sc)
 	if err != nil {
 		klog.Errorf("Failed to create new client. err: %+v", err)
 		return nil, err
 	}
-	client.UserAgent = userAgentName
 	err = connection.login(ctx, client)
 	if err != nil {
 		return nil, err
@@ -190,6 +295,10 @@ func (connection *VSphereConnection) NewClient(ctx context.Context) (*vim25.Clie
 	if connection.RoundTripperCount == 0 {
 		connection.RoundTripperCount = RoundTripperDefaultCount
 	}
+	// every attempt of a retried request waits for the rate limiter
+	if connection.RateLimiter != nil {
+		client.RoundTripper = &rateLimitedRoundTripper{RoundTripper: client.RoundTripper, limiter: connection.RateLimiter}
+	}
 	client.RoundTripper = vim25.Retry(client.RoundTripper, vim25.TemporaryNetworkError(int(connection.RoundTripperCount)))
 	return client, nil
 }
@@ -202,3 +311,58 @@ func (connection *VSphereConnection) UpdateCredentials(username string, password
 	connection.Username = username
 	connection.Password = password
 }
+
+// RenewCredentials updates username and password and, if they changed while
+// a session is active, replaces the client by one logged in with them. The
+// calls in flight complete on the replaced client, whose session expires on
+// its own. The client is kept if the login fails. RenewCredentials returns
+// whether the credentials changed.
+//
+// The login does not hold any lock. The client is replaced while holding
+// clientLocker, which guards the reads of connection.Client by the caller and
+// is taken before clientLock, as when the caller connects.
+func (connection *VSphereConnection) RenewCredentials(ctx context.Context, username string, password string, clientLocker sync.Locker) (bool, error) {
+	connection.credentialsLock.Lock()
+	changed := connection.Username != username || connection.Password != password
+	connection.credentialsLock.Unlock()
+	if !changed {
+		return false, nil
+	}
+	connection.UpdateCredentials(username, password)
+
+	clientLock.Lock()
+	connected := connection.Client != nil
+	clientLock.Unlock()
+	if !connected {
+		return true, nil
+	}
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		return true, err
+	}
+
+	clientLocker.Lock()
+	clientLock.Lock()
+	previous := connection.Client
+	connection.Client = client
+	clientLock.Unlock()
+	clientLocker.Unlock()
+	if previous != nil {
+		previous.CloseIdleConnections()
+	}
+	return true, nil
+}
+
+// rateLimitedRoundTripper waits for the rate limiter of the connection before
+// each API request.
+type rateLimitedRoundTripper struct {
+	soap.RoundTripper
+	limiter flowcontrol.RateLimiter
+}
+
+func (rt *rateLimitedRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	if err := rt.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return rt.RoundTripper.RoundTrip(ctx, req, res)
+}
@@ -30,6 +30,10 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	_ "github.com/vmware/govmomi/lookup/simulator"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	_ "github.com/vmware/govmomi/sts/simulator"
 
 	"k8s.io/cloud-provider-vsphere/pkg/common/vclib"
 	"k8s.io/cloud-provider-vsphere/pkg/common/vclib/fixtures"
@@ -157,6 +161,64 @@ func TestWithValidThumbprint(t *testing.T) {
 	verifyConnectionWasMade()
 }
 
+func TestWithTLSMinVersion(t *testing.T) {
+	handler, verifyConnectionWasMade := getRequestVerifier(t)
+
+	server, thumbprint :=
+		createTestServer(t, fixtures.CaCertPath, fixtures.ServerCertPath, fixtures.ServerKeyPath, handler)
+	server.TLS.MaxVersion = tls.VersionTLS12
+	server.StartTLS()
+	u := mustParseUrl(t, server.URL)
+
+	connection := &vclib.VSphereConnection{
+		Hostname:      u.Hostname(),
+		Port:          u.Port(),
+		Thumbprint:    thumbprint,
+		TLSMinVersion: tls.VersionTLS13,
+	}
+
+	_, err := connection.NewClient(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Fatalf("Expected protocol version error, got '%v'", err)
+	}
+
+	connection = &vclib.VSphereConnection{
+		Hostname:        u.Hostname(),
+		Port:            u.Port(),
+		Thumbprint:      thumbprint,
+		TLSMinVersion:   tls.VersionTLS12,
+		TLSCipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	}
+
+	// Ignoring error here, because we only care about the TLS connection
+	connection.NewClient(context.Background())
+
+	verifyConnectionWasMade()
+}
+
+func TestCheckTLSConnectionState(t *testing.T) {
+	suites := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+	tests := []struct {
+		name  string
+		state tls.ConnectionState
+		valid bool
+	}{
+		{"allowed", tls.ConnectionState{Version: tls.VersionTLS12, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, true},
+		{"below minimum version", tls.ConnectionState{Version: tls.VersionTLS11, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, false},
+		{"cipher suite not allowed", tls.ConnectionState{Version: tls.VersionTLS12, CipherSuite: tls.TLS_RSA_WITH_AES_128_GCM_SHA256}, false},
+		{"TLS 1.3 cipher suite", tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256}, true},
+	}
+	for _, test := range tests {
+		err := vclib.CheckTLSConnectionState(test.state, tls.VersionTLS12, suites)
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
 func TestWithInvalidCaCertPath(t *testing.T) {
 	connection := &vclib.VSphereConnection{
 		Hostname: "should-not-matter",
@@ -184,6 +246,103 @@ func TestInvalidCaCert(t *testing.T) {
 	}
 }
 
+func TestLoginWithIdentitySource(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	model.Service.TLS = new(tls.Config)
+	model.Service.RegisterEndpoints = true
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	connection := &vclib.VSphereConnection{
+		Hostname:       server.URL.Hostname(),
+		Port:           server.URL.Port(),
+		Insecure:       true,
+		Username:       "user",
+		Password:       "pass",
+		IdentitySource: "corp.example.com",
+	}
+
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatalf("Expected login by token to succeed, got: %s", err)
+	}
+
+	userSession, err := session.NewManager(client).UserSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userSession == nil {
+		t.Fatal("Expected an authenticated session")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	defer func(version, clusterName string) {
+		vclib.Version, vclib.ClusterName = version, clusterName
+	}(vclib.Version, vclib.ClusterName)
+	vclib.Version = "v1.32.0"
+	vclib.ClusterName = "prod"
+
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	password, _ := server.URL.User.Password()
+	testCases := []struct {
+		name      string
+		userAgent string
+		expected  string
+	}{
+		{
+			name:     "default",
+			expected: "k8s-cloud-provider-vsphere/v1.32.0 (cluster prod)",
+		},
+		{
+			name:      "configured",
+			userAgent: "cpi-prod-eu",
+			expected:  "cpi-prod-eu",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			connection := &vclib.VSphereConnection{
+				Hostname:  server.URL.Hostname(),
+				Port:      server.URL.Port(),
+				Insecure:  true,
+				Username:  server.URL.User.Username(),
+				Password:  password,
+				UserAgent: testCase.userAgent,
+			}
+
+			ctx := context.Background()
+			client, err := connection.NewClient(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			userSession, err := session.NewManager(client).UserSession(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if userSession.UserAgent != testCase.expected {
+				t.Errorf("Expected the session user agent to be %q, got %q", testCase.expected, userSession.UserAgent)
+			}
+		})
+	}
+}
+
 func verifyWrappedX509UnkownAuthorityErr(t *testing.T, err error) {
 	urlErr, ok := err.(*url.Error)
 	if !ok {
@@ -222,3 +381,62 @@ func mustParseUrl(t *testing.T, i string) *url.URL {
 	}
 	return u
 }
+
+// countingRateLimiter counts the requests waiting for it, and fails them
+// once err is set.
+type countingRateLimiter struct {
+	waits int
+	err   error
+}
+
+func (l *countingRateLimiter) TryAccept() bool { return true }
+func (l *countingRateLimiter) Accept()         {}
+func (l *countingRateLimiter) Stop()           {}
+func (l *countingRateLimiter) QPS() float32    { return 1 }
+func (l *countingRateLimiter) Wait(context.Context) error {
+	l.waits++
+	return l.err
+}
+
+func TestRateLimiter(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	password, _ := server.URL.User.Password()
+	limiter := &countingRateLimiter{}
+	connection := &vclib.VSphereConnection{
+		Hostname:            server.URL.Hostname(),
+		Port:                server.URL.Port(),
+		Insecure:            true,
+		Username:            server.URL.User.Username(),
+		Password:            password,
+		RateLimiter:         limiter,
+		MaxIdleConnsPerHost: 16,
+	}
+
+	ctx := context.Background()
+	client, err := connection.NewClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.NewManager(client).UserSession(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := client.DefaultTransport().MaxIdleConnsPerHost; n != 16 {
+		t.Errorf("Expected 16 pooled connections, got %d", n)
+	}
+	if limiter.waits != 1 {
+		t.Errorf("Expected the request to wait for the rate limiter once, got %d", limiter.waits)
+	}
+
+	limiter.err = context.DeadlineExceeded
+	if _, err := session.NewManager(client).UserSession(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the request to fail with the error of the rate limiter, got %v", err)
+	}
+}
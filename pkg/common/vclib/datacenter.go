@@ -112,6 +112,153 @@ func (dc *Datacenter) GetVMByIP(ctx context.Context, ipAddy string) (*VirtualMac
 	return &virtualMachine, nil
 }
 
+// GetVMByIPInScope gets the VM object with the given IP address among the VMs
+// connected to the given networks or stored on the given datastores, which
+// are names or managed object IDs, such as dvportgroup-42 or datastore-12.
+// Only the VMs of the hinted objects are searched, instead of the search index
+// of the whole datacenter. It returns ErrNoVMFound if none of them has the IP.
+func (dc *Datacenter) GetVMByIPInScope(ctx context.Context, ipAddy string, networks []string, datastores []string) (*VirtualMachine, error) {
+	ipAddy = strings.ToLower(strings.TrimSpace(ipAddy))
+	scope, err := dc.ipSearchScope(ctx, networks, datastores)
+	if err != nil {
+		klog.Errorf("Failed to find the networks and datastores to search VM IP %s in. err: %+v", ipAddy, err)
+		return nil, err
+	}
+	if len(scope) == 0 {
+		return nil, ErrNoVMFound
+	}
+
+	pc := property.DefaultCollector(dc.Client())
+	var content []types.ObjectContent
+	if err := pc.Retrieve(ctx, scope, []string{"vm"}, &content); err != nil {
+		klog.Errorf("Failed to list the VMs to search VM IP %s in. err: %+v", ipAddy, err)
+		return nil, err
+	}
+	seen := make(map[types.ManagedObjectReference]bool)
+	var vmRefs []types.ManagedObjectReference
+	for _, oc := range content {
+		for _, prop := range oc.PropSet {
+			refs, ok := prop.Val.(types.ArrayOfManagedObjectReference)
+			if !ok {
+				continue
+			}
+			for _, ref := range refs.ManagedObjectReference {
+				if !seen[ref] {
+					seen[ref] = true
+					vmRefs = append(vmRefs, ref)
+				}
+			}
+		}
+	}
+	if len(vmRefs) == 0 {
+		return nil, ErrNoVMFound
+	}
+
+	var vms []mo.VirtualMachine
+	if err := pc.Retrieve(ctx, vmRefs, []string{"guest.ipAddress", "guest.net"}, &vms); err != nil {
+		klog.Errorf("Failed to get the IPs of the VMs to search VM IP %s in. err: %+v", ipAddy, err)
+		return nil, err
+	}
+	for _, vm := range vms {
+		if !guestHasIP(vm.Guest, ipAddy) {
+			continue
+		}
+		// a managed object ID may name an object of another datacenter
+		inDatacenter, err := dc.contains(ctx, vm.Reference())
+		if err != nil {
+			return nil, err
+		}
+		if !inDatacenter {
+			continue
+		}
+		virtualMachine := VirtualMachine{object.NewVirtualMachine(dc.Client(), vm.Reference()), dc}
+		return &virtualMachine, nil
+	}
+	return nil, ErrNoVMFound
+}
+
+// ipSearchScope resolves the networks and datastores given by name or managed
+// object ID. Names not found in the datacenter are skipped, as they may name
+// objects of another datacenter of the vCenter.
+func (dc *Datacenter) ipSearchScope(ctx context.Context, networks []string, datastores []string) ([]types.ManagedObjectReference, error) {
+	finder := find.NewFinder(dc.Client(), false)
+	finder.SetDatacenter(dc.Datacenter)
+
+	var scope []types.ManagedObjectReference
+	for _, network := range networks {
+		if ref, ok := moidReference(network, map[string]string{"dvportgroup-": "DistributedVirtualPortgroup", "network-": "Network"}); ok {
+			scope = append(scope, ref)
+			continue
+		}
+		n, err := finder.Network(ctx, network)
+		if err != nil {
+			if _, ok := err.(*find.NotFoundError); ok {
+				continue
+			}
+			return nil, err
+		}
+		scope = append(scope, n.Reference())
+	}
+	for _, datastore := range datastores {
+		if ref, ok := moidReference(datastore, map[string]string{"datastore-": "Datastore"}); ok {
+			scope = append(scope, ref)
+			continue
+		}
+		ds, err := finder.Datastore(ctx, datastore)
+		if err != nil {
+			if _, ok := err.(*find.NotFoundError); ok {
+				continue
+			}
+			return nil, err
+		}
+		scope = append(scope, ds.Reference())
+	}
+	return scope, nil
+}
+
+// moidReference returns the reference of a managed object ID starting with
+// one of the prefixes, mapped to the type of the object.
+func moidReference(moid string, kinds map[string]string) (types.ManagedObjectReference, bool) {
+	for prefix, kind := range kinds {
+		if strings.HasPrefix(moid, prefix) {
+			return types.ManagedObjectReference{Type: kind, Value: moid}, true
+		}
+	}
+	return types.ManagedObjectReference{}, false
+}
+
+// contains returns whether the datacenter is an ancestor of the object.
+func (dc *Datacenter) contains(ctx context.Context, ref types.ManagedObjectReference) (bool, error) {
+	ancestors, err := mo.Ancestors(ctx, dc.Client(), dc.Client().ServiceContent.PropertyCollector, ref)
+	if err != nil {
+		return false, err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.Reference() == dc.Reference() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// guestHasIP returns whether the guest reports the IP address.
+func guestHasIP(guest *types.GuestInfo, ipAddy string) bool {
+	if guest == nil {
+		return false
+	}
+	if strings.EqualFold(guest.IpAddress, ipAddy) {
+		return true
+	}
+	for _, nic := range guest.Net {
+		for _, ip := range nic.IpAddress {
+			if strings.EqualFold(ip, ipAddy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // GetVMByDNSName gets the VM object from the given dns name
 func (dc *Datacenter) GetVMByDNSName(ctx context.Context, dnsName string) (*VirtualMachine, error) {
 	s := object.NewSearchIndex(dc.Client())
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vclib
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	klog "k8s.io/klog/v2"
+)
+
+type dialTLSFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// verifyTLS wraps the TLS dial of the soap client, rejecting connections
+// which negotiated a TLS version or cipher suite that is not allowed. The
+// thumbprint verification of govmomi dials without the TLS settings of the
+// transport, so they are checked after the handshake. The negotiated settings
+// are logged on the first connection.
+func (connection *VSphereConnection) verifyTLS(dial dialTLSFunc) dialTLSFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		tlsConn, ok := conn.(*tls.Conn)
+		if !ok {
+			return conn, nil
+		}
+		state := tlsConn.ConnectionState()
+		if err := CheckTLSConnectionState(state, connection.TLSMinVersion, connection.TLSCipherSuites); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("host %q: %w", addr, err)
+		}
+		connection.tlsLogOnce.Do(func() {
+			klog.Infof("vCenter %s negotiated %s with cipher suite %s", connection.Hostname,
+				tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+		})
+		return conn, nil
+	}
+}
+
+// CheckTLSConnectionState checks that a connection negotiated at least the
+// minimum TLS version and, below TLS 1.3, one of the cipher suites. A zero
+// minimum version or empty cipher suites allow any.
+func CheckTLSConnectionState(state tls.ConnectionState, minVersion uint16, cipherSuites []uint16) error {
+	if state.Version < minVersion {
+		return fmt.Errorf("negotiated %s is below the minimum version %s",
+			tls.VersionName(state.Version), tls.VersionName(minVersion))
+	}
+	if len(cipherSuites) == 0 || state.Version >= tls.VersionTLS13 {
+		return nil
+	}
+	for _, suite := range cipherSuites {
+		if suite == state.CipherSuite {
+			return nil
+		}
+	}
+	return fmt.Errorf("negotiated cipher suite %s is not allowed", tls.CipherSuiteName(state.CipherSuite))
+}
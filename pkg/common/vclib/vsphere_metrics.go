@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/cloud-provider-vsphere/pkg/common/metrics"
 )
 
 // Cloud Provider API constants
@@ -43,34 +45,34 @@ const (
 )
 
 // vsphereAPIMetric is for recording latency of Single API Call.
-var vsphereAPIMetric = prometheus.NewHistogramVec(
+var vsphereAPIMetric = metrics.NewHistogramVec(
 	prometheus.HistogramOpts{
-		Name: "cloudprovider_vsphere_api_request_duration_seconds",
+		Name: "api_request_duration_seconds",
 		Help: "Latency of vsphere api call",
 	},
 	[]string{"request"},
 )
 
-var vsphereAPIErrorMetric = prometheus.NewCounterVec(
+var vsphereAPIErrorMetric = metrics.NewCounterVec(
 	prometheus.CounterOpts{
-		Name: "cloudprovider_vsphere_api_request_errors",
+		Name: "api_request_errors",
 		Help: "vsphere Api errors",
 	},
 	[]string{"request"},
 )
 
 // vsphereOperationMetric is for recording latency of vSphere Operation which invokes multiple APIs to get the task done.
-var vsphereOperationMetric = prometheus.NewHistogramVec(
+var vsphereOperationMetric = metrics.NewHistogramVec(
 	prometheus.HistogramOpts{
-		Name: "cloudprovider_vsphere_operation_duration_seconds",
+		Name: "operation_duration_seconds",
 		Help: "Latency of vsphere operation call",
 	},
 	[]string{"operation"},
 )
 
-var vsphereOperationErrorMetric = prometheus.NewCounterVec(
+var vsphereOperationErrorMetric = metrics.NewCounterVec(
 	prometheus.CounterOpts{
-		Name: "cloudprovider_vsphere_operation_errors",
+		Name: "operation_errors",
 		Help: "vsphere operation errors",
 	},
 	[]string{"operation"},
@@ -21,9 +21,14 @@ import (
 	"os"
 	"strconv"
 
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	klog "k8s.io/klog/v2"
 )
 
+func init() {
+	vcfg.RegisterConfigSchema(NsxtConfigINI{}, NsxtConfigYAML{})
+}
+
 // FromEnv initializes the provided configuration object with values
 // obtained from environment variables. If an environment variable is set
 // for a property that's already initialized, the environment variable's value
@@ -46,6 +51,20 @@ func (cfg *Config) FromEnv() error {
 		}
 		cfg.InsecureFlag = InsecureFlag
 	}
+	if v := os.Getenv("NSXT_TLS_MIN_VERSION"); v != "" {
+		if _, err := vcfg.ParseTLSMinVersion(v); err != nil {
+			klog.Errorf("Failed to parse NSXT_TLS_MIN_VERSION: %s", err)
+			return fmt.Errorf("Failed to parse NSXT_TLS_MIN_VERSION: %s", err)
+		}
+		cfg.TLSMinVersion = v
+	}
+	if v := os.Getenv("NSXT_TLS_CIPHER_SUITES"); v != "" {
+		if _, err := vcfg.ParseTLSCipherSuites(v); err != nil {
+			klog.Errorf("Failed to parse NSXT_TLS_CIPHER_SUITES: %s", err)
+			return fmt.Errorf("Failed to parse NSXT_TLS_CIPHER_SUITES: %s", err)
+		}
+		cfg.TLSCipherSuites = v
+	}
 	if v := os.Getenv("NSXT_CLIENT_AUTH_CERT_FILE"); v != "" {
 		cfg.ClientAuthCertFile = v
 	}
@@ -61,6 +80,15 @@ func (cfg *Config) FromEnv() error {
 	if v := os.Getenv("NSXT_SECRET_NAMESPACE"); v != "" {
 		cfg.SecretNamespace = v
 	}
+	if v := os.Getenv("NSXT_SECRET_USERNAME_KEY"); v != "" {
+		cfg.SecretUsernameKey = v
+	}
+	if v := os.Getenv("NSXT_SECRET_PASSWORD_KEY"); v != "" {
+		cfg.SecretPasswordKey = v
+	}
+	if v := os.Getenv("NSXT_SECRET_CA_KEY"); v != "" {
+		cfg.SecretCAKey = v
+	}
 
 	return nil
 }
@@ -98,3 +126,19 @@ func ReadNsxtConfig(configData []byte) (*Config, error) {
 	klog.Info("NSXT Config initialized")
 	return cfg, nil
 }
+
+// UsernameKey returns the key of the NSX-T username in the secret.
+func (cfg *Config) UsernameKey() string {
+	if cfg.SecretUsernameKey != "" {
+		return cfg.SecretUsernameKey
+	}
+	return UsernameKeyInSecret
+}
+
+// PasswordKey returns the key of the NSX-T password in the secret.
+func (cfg *Config) PasswordKey() string {
+	if cfg.SecretPasswordKey != "" {
+		return cfg.SecretPasswordKey
+	}
+	return PasswordKeyInSecret
+}
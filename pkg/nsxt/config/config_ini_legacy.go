@@ -21,6 +21,8 @@ import (
 	"fmt"
 
 	"gopkg.in/gcfg.v1"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 )
 
 /*
@@ -37,6 +39,8 @@ func (nci *NsxtConfigINI) CreateConfig() *Config {
 	cfg.Host = nci.NSXT.Host
 	cfg.InsecureFlag = nci.NSXT.InsecureFlag
 	cfg.RemoteAuth = nci.NSXT.RemoteAuth
+	cfg.TLSMinVersion = nci.NSXT.TLSMinVersion
+	cfg.TLSCipherSuites = nci.NSXT.TLSCipherSuites
 	cfg.VMCAccessToken = nci.NSXT.VMCAccessToken
 	cfg.VMCAuthHost = nci.NSXT.VMCAuthHost
 	cfg.ClientAuthCertFile = nci.NSXT.ClientAuthCertFile
@@ -44,6 +48,9 @@ func (nci *NsxtConfigINI) CreateConfig() *Config {
 	cfg.CAFile = nci.NSXT.CAFile
 	cfg.SecretName = nci.NSXT.SecretName
 	cfg.SecretNamespace = nci.NSXT.SecretNamespace
+	cfg.SecretUsernameKey = nci.NSXT.SecretUsernameKey
+	cfg.SecretPasswordKey = nci.NSXT.SecretPasswordKey
+	cfg.SecretCAKey = nci.NSXT.SecretCAKey
 
 	return cfg
 }
@@ -80,6 +87,18 @@ func (cfg *NsxtINI) validateConfig() error {
 	if cfg.Host == "" {
 		return errors.New("host is empty")
 	}
+	if cfg.SecretName == "" && (cfg.SecretUsernameKey != "" || cfg.SecretPasswordKey != "" || cfg.SecretCAKey != "") {
+		return errors.New("secret name is required if secret keys are provided")
+	}
+	if cfg.SecretCAKey != "" && cfg.CAFile != "" {
+		return errors.New("ca file and secret ca key are mutually exclusive")
+	}
+	if _, err := vcfg.ParseTLSMinVersion(cfg.TLSMinVersion); err != nil {
+		return err
+	}
+	if _, err := vcfg.ParseTLSCipherSuites(cfg.TLSCipherSuites); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -131,6 +131,27 @@ func TestINIValidateSecretConfig(t *testing.T) {
 			},
 			expectedErrMessage: "host is empty",
 		},
+		{
+			name: "secret keys without secret",
+			cfg: &NsxtINI{
+				User:              "admin",
+				Password:          "secret",
+				Host:              "server",
+				SecretUsernameKey: "nsxt.username",
+			},
+			expectedErrMessage: "secret name is required if secret keys are provided",
+		},
+		{
+			name: "ca file and secret ca key",
+			cfg: &NsxtINI{
+				SecretName:      "secret-name",
+				SecretNamespace: "secret-ns",
+				Host:            "server",
+				CAFile:          "ca-file",
+				SecretCAKey:     "nsxt.ca",
+			},
+			expectedErrMessage: "ca file and secret ca key are mutually exclusive",
+		},
 		{
 			name: "valid config",
 			cfg: &NsxtINI{
@@ -208,6 +229,7 @@ client-auth-key-file = client-key-file
 ca-file = ca-file
 secret-name = secret-name
 secret-namespace = secret-ns
+secret-password-key = nsxt.password
 	`
 	config, err := ReadConfigINI([]byte(contents))
 	if err != nil {
@@ -232,4 +254,6 @@ secret-namespace = secret-ns
 	assertEquals("NSXT.ca-file", config.CAFile, "ca-file")
 	assertEquals("NSXT.secret-name", config.SecretName, "secret-name")
 	assertEquals("NSXT.secret-namespace", config.SecretNamespace, "secret-ns")
+	assertEquals("NSXT.secret-username-key", config.UsernameKey(), "username")
+	assertEquals("NSXT.secret-password-key", config.PasswordKey(), "nsxt.password")
 }
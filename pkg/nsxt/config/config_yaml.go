@@ -19,8 +19,11 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 )
 
 /*
@@ -38,6 +41,8 @@ func (ncy *NsxtConfigYAML) CreateConfig() *Config {
 	cfg.Host = ncy.NSXT.Host
 	cfg.InsecureFlag = ncy.NSXT.InsecureFlag
 	cfg.RemoteAuth = ncy.NSXT.RemoteAuth
+	cfg.TLSMinVersion = ncy.NSXT.TLSMinVersion
+	cfg.TLSCipherSuites = strings.Join(ncy.NSXT.TLSCipherSuites, ",")
 	cfg.VMCAccessToken = ncy.NSXT.VMCAccessToken
 	cfg.VMCAuthHost = ncy.NSXT.VMCAuthHost
 	cfg.ClientAuthCertFile = ncy.NSXT.ClientAuthCertFile
@@ -45,6 +50,9 @@ func (ncy *NsxtConfigYAML) CreateConfig() *Config {
 	cfg.CAFile = ncy.NSXT.CAFile
 	cfg.SecretName = ncy.NSXT.SecretName
 	cfg.SecretNamespace = ncy.NSXT.SecretNamespace
+	cfg.SecretUsernameKey = ncy.NSXT.SecretUsernameKey
+	cfg.SecretPasswordKey = ncy.NSXT.SecretPasswordKey
+	cfg.SecretCAKey = ncy.NSXT.SecretCAKey
 
 	return cfg
 }
@@ -81,6 +89,18 @@ func (cfg *NsxtYAML) validateConfig() error {
 	if cfg.Host == "" {
 		return errors.New("host is empty")
 	}
+	if cfg.SecretName == "" && (cfg.SecretUsernameKey != "" || cfg.SecretPasswordKey != "" || cfg.SecretCAKey != "") {
+		return errors.New("secret name is required if secret keys are provided")
+	}
+	if cfg.SecretCAKey != "" && cfg.CAFile != "" {
+		return errors.New("ca file and secret ca key are mutually exclusive")
+	}
+	if _, err := vcfg.ParseTLSMinVersion(cfg.TLSMinVersion); err != nil {
+		return err
+	}
+	if _, err := vcfg.ParseTLSCipherSuites(strings.Join(cfg.TLSCipherSuites, ",")); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -133,6 +133,27 @@ func TestYAMLValidateSecretConfig(t *testing.T) {
 			},
 			expectedErrMessage: "host is empty",
 		},
+		{
+			name: "secret keys without secret",
+			cfg: &NsxtYAML{
+				User:              "admin",
+				Password:          "secret",
+				Host:              "server",
+				SecretUsernameKey: "nsxt.username",
+			},
+			expectedErrMessage: "secret name is required if secret keys are provided",
+		},
+		{
+			name: "ca file and secret ca key",
+			cfg: &NsxtYAML{
+				SecretName:      "secret-name",
+				SecretNamespace: "secret-ns",
+				Host:            "server",
+				CAFile:          "ca-file",
+				SecretCAKey:     "nsxt.ca",
+			},
+			expectedErrMessage: "ca file and secret ca key are mutually exclusive",
+		},
 		{
 			name: "valid config",
 			cfg: &NsxtYAML{
@@ -210,6 +231,8 @@ nsxt:
   caFile: ca-file
   secretName: secret-name
   secretNamespace: secret-ns
+  secretUsernameKey: nsxt.username
+  secretPasswordKey: nsxt.password
 `
 	config, err := ReadConfigYAML([]byte(contents))
 	if err != nil {
@@ -234,4 +257,38 @@ nsxt:
 	assertEquals("NSXT.caFile", config.CAFile, "ca-file")
 	assertEquals("NSXT.secretName", config.SecretName, "secret-name")
 	assertEquals("NSXT.secretNamespace", config.SecretNamespace, "secret-ns")
+	assertEquals("NSXT.secretUsernameKey", config.UsernameKey(), "nsxt.username")
+	assertEquals("NSXT.secretPasswordKey", config.PasswordKey(), "nsxt.password")
+}
+
+func TestReadYAMLConfigTLSSettings(t *testing.T) {
+	contents := `
+nsxt:
+  user: admin
+  password: secret
+  host: nsxt-server
+  tlsMinVersion: "1.2"
+  tlsCipherSuites:
+    - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+    - TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+`
+	config, err := ReadConfigYAML([]byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1.2", config.TLSMinVersion)
+	assert.Equal(t, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", config.TLSCipherSuites)
+
+	for _, invalid := range []string{"tlsMinVersion: TLS12", "tlsCipherSuites: [TLS_RSA_WITH_RC4_128_SHA]"} {
+		contents := `
+nsxt:
+  user: admin
+  password: secret
+  host: nsxt-server
+  ` + invalid + `
+`
+		if _, err := ReadConfigYAML([]byte(contents)); err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
 }
@@ -0,0 +1,34 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
+)
+
+// Redacted returns a copy of the config whose password and VMC access token
+// are replaced by vcfg.RedactedValue, to be logged or dumped.
+func (cfg *Config) Redacted() *Config {
+	redacted := *cfg
+	if redacted.Password != "" {
+		redacted.Password = vcfg.RedactedValue
+	}
+	if redacted.VMCAccessToken != "" {
+		redacted.VMCAccessToken = vcfg.RedactedValue
+	}
+	return &redacted
+}
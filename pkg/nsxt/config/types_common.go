@@ -28,10 +28,20 @@ type Config struct {
 	InsecureFlag bool
 	// RemoteAuth is to be set to true if NSX-T uses remote authentication (authentication done through the vIDM).
 	RemoteAuth bool
+	// TLSMinVersion is the minimum TLS version of the connections to NSX-T, 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string
+	// TLSCipherSuites are the comma separated TLS cipher suites of the connections to NSX-T up to TLS 1.2, by their IANA names.
+	TLSCipherSuites string
 	// SecretName is the secret name for NSX-T username and password
 	SecretName string
 	// SecretNamespace is the secret namespace for NSX-T username and password
 	SecretNamespace string
+	// SecretUsernameKey is the key of the NSX-T username in the secret, "username" by default
+	SecretUsernameKey string
+	// SecretPasswordKey is the key of the NSX-T password in the secret, "password" by default
+	SecretPasswordKey string
+	// SecretCAKey is the key of the PEM encoded CA certificates of NSX-T in the secret, if any
+	SecretCAKey string
 
 	VMCAccessToken     string
 	VMCAuthHost        string
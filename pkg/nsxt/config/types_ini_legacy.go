@@ -33,10 +33,20 @@ type NsxtINI struct {
 	InsecureFlag bool `gcfg:"insecure-flag"`
 	// RemoteAuth is to be set to true if NSX-T uses remote authentication (authentication done through the vIDM).
 	RemoteAuth bool `gcfg:"remote-auth"`
+	// TLSMinVersion is the minimum TLS version of the connections to NSX-T, 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string `gcfg:"tls-min-version"`
+	// TLSCipherSuites are the comma separated TLS cipher suites of the connections to NSX-T up to TLS 1.2, by their IANA names.
+	TLSCipherSuites string `gcfg:"tls-cipher-suites"`
 	// SecretName is the secret name for NSX-T username and password
 	SecretName string `gcfg:"secret-name"`
 	// SecretNamespace is the secret namespace for NSX-T username and password
 	SecretNamespace string `gcfg:"secret-namespace"`
+	// SecretUsernameKey is the key of the NSX-T username in the secret, "username" by default
+	SecretUsernameKey string `gcfg:"secret-username-key"`
+	// SecretPasswordKey is the key of the NSX-T password in the secret, "password" by default
+	SecretPasswordKey string `gcfg:"secret-password-key"`
+	// SecretCAKey is the key of the PEM encoded CA certificates of NSX-T in the secret, if any
+	SecretCAKey string `gcfg:"secret-ca-key"`
 
 	VMCAccessToken     string `gcfg:"vmc-access-token"`
 	VMCAuthHost        string `gcfg:"vmc-auth-host"`
@@ -41,10 +41,20 @@ type NsxtYAML struct {
 	InsecureFlag bool `yaml:"insecureFlag"`
 	// RemoteAuth is to be set to true if NSX-T uses remote authentication (authentication done through the vIDM).
 	RemoteAuth bool `yaml:"remoteAuth"`
+	// TLSMinVersion is the minimum TLS version of the connections to NSX-T, 1.0, 1.1, 1.2 or 1.3.
+	TLSMinVersion string `yaml:"tlsMinVersion"`
+	// TLSCipherSuites are the TLS cipher suites of the connections to NSX-T up to TLS 1.2, by their IANA names.
+	TLSCipherSuites []string `yaml:"tlsCipherSuites"`
 	// SecretName is the secret name for NSX-T username and password
 	SecretName string `yaml:"secretName"`
 	// SecretNamespace is the secret namespace for NSX-T username and password
 	SecretNamespace string `yaml:"secretNamespace"`
+	// SecretUsernameKey is the key of the NSX-T username in the secret, "username" by default
+	SecretUsernameKey string `yaml:"secretUsernameKey"`
+	// SecretPasswordKey is the key of the NSX-T password in the secret, "password" by default
+	SecretPasswordKey string `yaml:"secretPasswordKey"`
+	// SecretCAKey is the key of the PEM encoded CA certificates of NSX-T in the secret, if any
+	SecretCAKey string `yaml:"secretCAKey"`
 
 	VMCAccessToken     string `yaml:"vmcAccessToken"`
 	VMCAuthHost        string `yaml:"vmcAuthHost"`
@@ -26,6 +26,8 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/core"
@@ -34,15 +36,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	vcfg "k8s.io/cloud-provider-vsphere/pkg/common/config"
 	"k8s.io/cloud-provider-vsphere/pkg/nsxt/config"
-	nsxtcfg "k8s.io/cloud-provider-vsphere/pkg/nsxt/config"
 	klog "k8s.io/klog/v2"
 )
 
 // ConnectorManager manages NSXT connection
 type ConnectorManager struct {
-	config    *config.Config
-	connector client.Connector
+	config     *config.Config
+	connector  client.Connector
+	tlsLogOnce sync.Once
+	// secretCAs are the CA certificates of the secret NSX-T is verified
+	// against if SecretCAKey is set, nil until the secret is read
+	secretCAs atomic.Pointer[x509.CertPool]
 }
 
 type remoteBasicAuthHeaderProcessor struct {
@@ -98,6 +104,14 @@ func NewConnectorManager(nsxtConfig *config.Config) (*ConnectorManager, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cm.verifiesSecretCA() {
+		// the CA certificates of the secret are rotated, NSX-T is verified
+		// against the current ones in VerifyConnection instead
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if err := cm.applyTLSSettings(tlsConfig); err != nil {
+		return nil, err
+	}
 	httpClient := http.Client{
 		Transport: &http.Transport{
 			Proxy:           http.ProxyFromEnvironment,
@@ -151,6 +165,67 @@ func getConnectorTLSConfig(insecure bool, clientCertFile string, clientKeyFile s
 	return &tlsConfig, nil
 }
 
+// applyTLSSettings sets the configured TLS minimum version and cipher suites
+// and logs the negotiated settings on the first connection.
+func (cm *ConnectorManager) applyTLSSettings(tlsConfig *tls.Config) error {
+	minVersion, err := vcfg.ParseTLSMinVersion(cm.config.TLSMinVersion)
+	if err != nil {
+		return err
+	}
+	cipherSuites, err := vcfg.ParseTLSCipherSuites(cm.config.TLSCipherSuites)
+	if err != nil {
+		return err
+	}
+	tlsConfig.MinVersion = minVersion
+	tlsConfig.CipherSuites = cipherSuites
+	if minVersion != 0 || len(cipherSuites) > 0 {
+		klog.Infof("NSX-T %s connections use TLS minimum version %q and cipher suites %q",
+			cm.config.Host, cm.config.TLSMinVersion, cm.config.TLSCipherSuites)
+	}
+
+	tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+		if cm.verifiesSecretCA() {
+			if err := cm.verifySecretCA(state); err != nil {
+				return err
+			}
+		}
+		cm.tlsLogOnce.Do(func() {
+			klog.Infof("NSX-T %s negotiated %s with cipher suite %s", cm.config.Host,
+				tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+		})
+		return nil
+	}
+	return nil
+}
+
+// verifiesSecretCA returns true if NSX-T is verified against the CA
+// certificates of the secret.
+func (cm *ConnectorManager) verifiesSecretCA() bool {
+	return cm.config.SecretCAKey != "" && !cm.config.InsecureFlag
+}
+
+// verifySecretCA verifies the certificate NSX-T presented against the CA
+// certificates of the secret.
+func (cm *ConnectorManager) verifySecretCA(state tls.ConnectionState) error {
+	roots := cm.secretCAs.Load()
+	if roots == nil {
+		return fmt.Errorf("CA certificates of NSX-T secret %s/%s not read yet", cm.config.SecretNamespace, cm.config.SecretName)
+	}
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("NSX-T presented no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       state.ServerName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
 type jwtToken struct {
 	IDToken      string `json:"id_token"`
 	TokenType    string `json:"token_type"`
@@ -262,14 +337,14 @@ func (cm *ConnectorManager) secretDeleted(obj interface{}) {
 
 // updateConnectorContext updates security context of connector
 func (cm *ConnectorManager) updateConnectorContext(secret *corev1.Secret) {
-	var username, password string
-	for key, value := range secret.Data {
-		if key == nsxtcfg.UsernameKeyInSecret {
-			username = string(value)
-		}
-		if key == nsxtcfg.PasswordKeyInSecret {
-			password = string(value)
-		}
+	if cm.config.SecretCAKey != "" {
+		cm.updateSecretCAs(secret)
+	}
+	username := string(secret.Data[cm.config.UsernameKey()])
+	password := string(secret.Data[cm.config.PasswordKey()])
+	if username == "" && password == "" && cm.config.SecretCAKey != "" {
+		// the secret only holds the CA certificates
+		return
 	}
 	if username == "" || password == "" {
 		klog.Warningf("NSXT username and password should be both provided in secret")
@@ -283,6 +358,18 @@ func (cm *ConnectorManager) updateConnectorContext(secret *corev1.Secret) {
 	cm.connector.SetSecurityContext(securityCtx)
 }
 
+// updateSecretCAs replaces the CA certificates NSX-T is verified against by
+// the ones of the secret
+func (cm *ConnectorManager) updateSecretCAs(secret *corev1.Secret) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data[cm.config.SecretCAKey]) {
+		klog.Warningf("NSXT secret %s/%s has no CA certificate in key %s", secret.Namespace, secret.Name, cm.config.SecretCAKey)
+		return
+	}
+	klog.V(6).Infof("Updating CA certificates for NSXT connection")
+	cm.secretCAs.Store(roots)
+}
+
 // resetConnectorContext resets security context of connector
 func (cm *ConnectorManager) resetConnectorContext() {
 	klog.V(6).Infof("Resetting security context for NSXT connection")
@@ -0,0 +1,46 @@
+/*
+ Copyright 2026 The Kubernetes Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package nsxt
+
+import (
+	"fmt"
+)
+
+// ListAll collects the results of a paginated list or search call, following
+// the cursors until the last page. The result count of the pages is not used,
+// as it drifts when objects are created or deleted while the pages are read.
+// The errors of list are returned as is.
+func ListAll[T any](list func(cursor *string) ([]T, *string, error)) ([]T, error) {
+	var all []T
+	var cursor *string
+	seen := map[string]bool{}
+	for {
+		results, next, err := list(cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, results...)
+		if next == nil || *next == "" || len(results) == 0 {
+			return all, nil
+		}
+		if seen[*next] {
+			return nil, fmt.Errorf("list returned cursor %s twice", *next)
+		}
+		seen[*next] = true
+		cursor = next
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debugdump collects snapshots of internal state, such as caches,
+// connection states and in-flight reconciles, and logs them as JSON on
+// demand to diagnose hangs without restarting the process.
+package debugdump
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Source returns a snapshot of the state of a component, which must be
+// encodable as JSON. Sources are called while the process may be hanging,
+// so they must not block on locks or remote calls.
+type Source func() interface{}
+
+var (
+	sourcesLock sync.Mutex
+	sources     = make(map[string]Source)
+)
+
+// Register adds the source to the dump under name, replacing the source
+// previously registered under the same name.
+func Register(name string, source Source) {
+	sourcesLock.Lock()
+	defer sourcesLock.Unlock()
+
+	sources[name] = source
+}
+
+// Dump is the state of the process at a point in time.
+type Dump struct {
+	Time       time.Time              `json:"time"`
+	Goroutines int                    `json:"goroutines"`
+	Sources    map[string]interface{} `json:"sources"`
+}
+
+// Collect calls every registered source.
+func Collect() Dump {
+	sourcesLock.Lock()
+	registered := make(map[string]Source, len(sources))
+	for name, source := range sources {
+		registered[name] = source
+	}
+	sourcesLock.Unlock()
+
+	d := Dump{
+		Time:       time.Now(),
+		Goroutines: runtime.NumGoroutine(),
+		Sources:    make(map[string]interface{}, len(registered)),
+	}
+	for name, source := range registered {
+		d.Sources[name] = source()
+	}
+	return d
+}
+
+// DumpOnSignal logs the dump as JSON each time one of the signals is
+// received, until stop is closed.
+func DumpOnSignal(stop <-chan struct{}, signals ...os.Signal) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+	go func() {
+		defer signal.Stop(c)
+		for {
+			select {
+			case <-stop:
+				return
+			case sig := <-c:
+				b, err := json.Marshal(Collect())
+				if err != nil {
+					klog.Errorf("Failed to encode debug dump on %s: %v", sig, err)
+					continue
+				}
+				klog.Infof("Debug dump on %s: %s", sig, b)
+			}
+		}
+	}()
+}
+
+// HandlerPath is the path Handler is served at. It exposes the effective
+// config and the discovered nodes, so it must only be served with
+// authentication.
+const HandlerPath = "/debug/dump"
+
+// Handler responds with the dump as JSON, or with the snapshot of the single
+// source named by the query parameter source.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{} = Collect()
+		if name := r.URL.Query().Get("source"); name != "" {
+			sourcesLock.Lock()
+			source, ok := sources[name]
+			sourcesLock.Unlock()
+			if !ok {
+				http.Error(w, "unknown source "+name, http.StatusNotFound)
+				return
+			}
+			body = source()
+		}
+		b, err := json.MarshalIndent(body, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		_, _ = w.Write(b)
+	})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugdump
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	Register("test", func() interface{} {
+		return map[string]int{"first": 1}
+	})
+	Register("test", func() interface{} {
+		return map[string]int{"second": 2}
+	})
+
+	d := Collect()
+	if d.Goroutines == 0 {
+		t.Error("Goroutines should be counted")
+	}
+
+	b, err := json.Marshal(d.Sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"test":{"second":2}`) {
+		t.Errorf("Unexpected sources %s", b)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	Register("handler", func() interface{} {
+		return map[string]int{"answer": 42}
+	})
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/dump", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"handler": {`) {
+		t.Errorf("Unexpected dump %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/dump?source=handler", nil))
+	if recorder.Code != http.StatusOK || strings.TrimSpace(recorder.Body.String()) != "{\n  \"answer\": 42\n}" {
+		t.Errorf("Unexpected source %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/dump?source=unknown", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Unknown source should not be found, got %d", recorder.Code)
+	}
+}
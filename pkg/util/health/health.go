@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health serves /healthz and /readyz endpoints reporting the
+// connectivity to the backends of the cloud provider, such as vCenter, NSX-T
+// and the supervisor apiserver, so that the probes of the pod detect backend
+// outages.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Check returns an error if the backend it checks is not healthy. A check
+// still running once ctx is done is reported as failed.
+type Check func(ctx context.Context) error
+
+// checkTimeout bounds the duration of each check
+var checkTimeout = 10 * time.Second
+
+var (
+	checksLock sync.Mutex
+	checks     = make(map[string]Check)
+)
+
+// Register adds the check under name, replacing the check previously
+// registered under the same name. Checks are registered once the cloud
+// provider is initialized, the endpoints report healthy until then.
+func Register(name string, check Check) {
+	checksLock.Lock()
+	defer checksLock.Unlock()
+
+	checks[name] = check
+}
+
+// result is the outcome of a named check.
+type result struct {
+	name string
+	err  error
+}
+
+// run calls every registered check, sorted by name.
+func run(ctx context.Context) []result {
+	checksLock.Lock()
+	names := make([]string, 0, len(checks))
+	registered := make(map[string]Check, len(checks))
+	for name, check := range checks {
+		names = append(names, name)
+		registered[name] = check
+	}
+	checksLock.Unlock()
+	sort.Strings(names)
+
+	results := make([]result, 0, len(names))
+	for _, name := range names {
+		results = append(results, result{name: name, err: runCheck(ctx, registered[name])})
+	}
+	return results
+}
+
+// runCheck calls the check with a timeout, which also applies to checks
+// blocked on a lock held by a hanging call.
+func runCheck(ctx context.Context, check Check) error {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handler runs the checks and responds with "ok", or with status 500 and the
+// failed checks. The query parameter verbose lists every check.
+func handler(endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var report strings.Builder
+		failed := false
+		for _, res := range run(r.Context()) {
+			if res.err != nil {
+				failed = true
+				klog.Warningf("%s check %s failed: %v", endpoint, res.name, res.err)
+				fmt.Fprintf(&report, "[-]%s failed: %v\n", res.name, res.err)
+			} else {
+				fmt.Fprintf(&report, "[+]%s ok\n", res.name)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "%s%s check failed\n", report.String(), endpoint)
+			return
+		}
+		if _, verbose := r.URL.Query()["verbose"]; verbose {
+			fmt.Fprintf(w, "%s%s check passed\n", report.String(), endpoint)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}
+}
+
+// Handler returns the handler of the /healthz and /readyz endpoints, which
+// both run every registered check.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", handler("healthz"))
+	mux.Handle("/readyz", handler("readyz"))
+	return mux
+}
+
+// Serve serves the endpoints on address until stop is closed. They are served
+// without authentication, so nothing but the health checks may be served
+// there.
+func Serve(address string, stop <-chan struct{}) {
+	server := &http.Server{
+		Addr:              address,
+		Handler:           Handler(),
+		ReadHeaderTimeout: checkTimeout,
+	}
+	go func() {
+		<-stop
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Errorf("Failed to shut down the health endpoints: %v", err)
+		}
+	}()
+	go func() {
+		klog.Infof("Serving /healthz and /readyz on %s", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("Failed to serve the health endpoints on %s: %v", address, err)
+		}
+	}()
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, path string) (int, string) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestHandler(t *testing.T) {
+	defer func(timeout time.Duration) { checkTimeout = timeout }(checkTimeout)
+	checkTimeout = 50 * time.Millisecond
+
+	// no checks are registered before the cloud provider is initialized
+	if code, body := get(t, "/readyz"); code != http.StatusOK || body != "ok" {
+		t.Errorf("readyz without checks should be ok, got %d %q", code, body)
+	}
+
+	t.Cleanup(func() {
+		checksLock.Lock()
+		delete(checks, "backend")
+		checksLock.Unlock()
+	})
+	var backendErr error
+	Register("backend", func(context.Context) error { return backendErr })
+	if code, body := get(t, "/healthz?verbose"); code != http.StatusOK || !strings.Contains(body, "[+]backend ok") {
+		t.Errorf("healthz should list the passed check, got %d %q", code, body)
+	}
+
+	backendErr = errors.New("connection refused")
+	for _, path := range []string{"/healthz", "/readyz"} {
+		code, body := get(t, path)
+		if code != http.StatusInternalServerError || !strings.Contains(body, "[-]backend failed: connection refused") {
+			t.Errorf("%s should report the failed check, got %d %q", path, code, body)
+		}
+	}
+
+	// a check ignoring its timeout fails when it times out
+	backendErr = nil
+	Register("backend", func(ctx context.Context) error {
+		time.Sleep(2 * checkTimeout)
+		return nil
+	})
+	if code, body := get(t, "/readyz"); code != http.StatusInternalServerError || !strings.Contains(body, "deadline exceeded") {
+		t.Errorf("readyz should report the timed out check, got %d %q", code, body)
+	}
+}
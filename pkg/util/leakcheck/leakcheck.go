@@ -0,0 +1,258 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leakcheck samples resource counters such as goroutines, heap and
+// vCenter sessions over the lifetime of a soak test and reports the counters
+// that grow monotonically.
+package leakcheck
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultWarmup is the default number of initial samples ignored by Check.
+	DefaultWarmup = 2
+	// DefaultWindows is the default number of windows the samples are split into.
+	DefaultWindows = 4
+	// DefaultTolerance is the default relative growth ignored by Check.
+	DefaultTolerance = 0.1
+
+	// DurationEnv is the environment variable holding the duration of a soak test.
+	DurationEnv = "SOAK_DURATION"
+)
+
+// Probe returns the current value of a resource counter.
+type Probe func(ctx context.Context) (float64, error)
+
+// Detector periodically samples a set of probes and detects the ones that
+// grow monotonically.
+//
+// The samples following the warm-up are split into windows, and the minimum
+// of each window is taken so transient spikes (in-flight requests, garbage
+// not yet collected) are ignored. A counter leaks if the window minimums
+// strictly increase and the last one exceeds the first by more than the
+// tolerance.
+type Detector struct {
+	// Warmup is the number of initial samples ignored by Check.
+	Warmup int
+	// Windows is the number of windows the samples are split into.
+	Windows int
+	// Tolerance is the relative growth between the first and the last window
+	// below which growth is ignored.
+	Tolerance float64
+
+	lock    sync.Mutex
+	names   []string
+	probes  map[string]Probe
+	samples map[string][]float64
+}
+
+// New returns a Detector with the default warm-up, windows and tolerance.
+func New() *Detector {
+	return &Detector{
+		Warmup:    DefaultWarmup,
+		Windows:   DefaultWindows,
+		Tolerance: DefaultTolerance,
+		probes:    make(map[string]Probe),
+		samples:   make(map[string][]float64),
+	}
+}
+
+// Add registers a probe under the given name.
+func (d *Detector) Add(name string, probe Probe) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if _, ok := d.probes[name]; !ok {
+		d.names = append(d.names, name)
+	}
+	d.probes[name] = probe
+}
+
+// Sample records the current value of every probe.
+func (d *Detector) Sample(ctx context.Context) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, name := range d.names {
+		v, err := d.probes[name](ctx)
+		if err != nil {
+			return fmt.Errorf("sampling %s: %w", name, err)
+		}
+		d.samples[name] = append(d.samples[name], v)
+	}
+	return nil
+}
+
+// Run samples every interval until the context is done. Sampling errors are
+// logged and do not stop the run.
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Sample(ctx); err != nil && ctx.Err() == nil {
+			klog.Warningf("leakcheck: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Samples returns a copy of the values recorded for the named probe.
+func (d *Detector) Samples(name string) []float64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return append([]float64(nil), d.samples[name]...)
+}
+
+// Check returns an error naming every probe whose samples grow monotonically.
+// Probes without enough samples to fill every window are not checked.
+func (d *Detector) Check() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var leaks []string
+	for _, name := range d.names {
+		mins := d.windowMinimums(d.samples[name])
+		if len(mins) == 0 {
+			continue
+		}
+		if growsMonotonically(mins, d.Tolerance) {
+			leaks = append(leaks, fmt.Sprintf("%s grew monotonically %v", name, mins))
+		}
+	}
+	if len(leaks) > 0 {
+		return fmt.Errorf("leak detected: %s", strings.Join(leaks, "; "))
+	}
+	return nil
+}
+
+// Report returns a human readable summary of the recorded samples.
+func (d *Detector) Report() string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var b strings.Builder
+	for _, name := range d.names {
+		s := d.samples[name]
+		if len(s) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: samples=%d first=%g last=%g windows=%v\n",
+			name, len(s), s[0], s[len(s)-1], d.windowMinimums(s))
+	}
+	return b.String()
+}
+
+// windowMinimums returns the minimum of each window of the samples following
+// the warm-up, or nil if there are fewer samples than windows.
+func (d *Detector) windowMinimums(samples []float64) []float64 {
+	windows := d.Windows
+	if windows < 2 {
+		windows = 2
+	}
+	if d.Warmup < len(samples) {
+		samples = samples[d.Warmup:]
+	} else {
+		samples = nil
+	}
+	if len(samples) < windows {
+		return nil
+	}
+
+	size := len(samples) / windows
+	mins := make([]float64, windows)
+	for i := range mins {
+		end := (i + 1) * size
+		if i == windows-1 {
+			end = len(samples)
+		}
+		mins[i] = math.Inf(1)
+		for _, v := range samples[i*size : end] {
+			mins[i] = math.Min(mins[i], v)
+		}
+	}
+	return mins
+}
+
+func growsMonotonically(mins []float64, tolerance float64) bool {
+	for i := 1; i < len(mins); i++ {
+		if mins[i] <= mins[i-1] {
+			return false
+		}
+	}
+	first, last := mins[0], mins[len(mins)-1]
+	return last-first > math.Abs(first)*tolerance
+}
+
+// Goroutines returns a probe counting the goroutines of the process.
+func Goroutines() Probe {
+	return func(context.Context) (float64, error) {
+		return float64(runtime.NumGoroutine()), nil
+	}
+}
+
+// HeapInuse returns a probe reporting the bytes in in-use heap spans after
+// a garbage collection.
+func HeapInuse() Probe {
+	return func(context.Context) (float64, error) {
+		var m runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&m)
+		return float64(m.HeapInuse), nil
+	}
+}
+
+// Sessions returns a probe counting the sessions open on the vCenter the
+// client is connected to, including the session of the client itself.
+func Sessions(client *vim25.Client) Probe {
+	return func(ctx context.Context) (float64, error) {
+		var sm mo.SessionManager
+		pc := property.DefaultCollector(client)
+		if err := pc.RetrieveOne(ctx, *client.ServiceContent.SessionManager, []string{"sessionList"}, &sm); err != nil {
+			return 0, err
+		}
+		return float64(len(sm.SessionList)), nil
+	}
+}
+
+// Duration returns the soak duration set in the SOAK_DURATION environment
+// variable, or def if it is not set.
+func Duration(def time.Duration) (time.Duration, error) {
+	v := os.Getenv(DurationEnv)
+	if v == "" {
+		return def, nil
+	}
+	return time.ParseDuration(v)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leakcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func sequence(values ...float64) Probe {
+	i := 0
+	return func(context.Context) (float64, error) {
+		v := values[i]
+		i++
+		return v, nil
+	}
+}
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		name    string
+		samples []float64
+		leaks   bool
+	}{
+		{
+			name:    "flat",
+			samples: []float64{1, 9, 10, 10, 10, 10, 10, 10, 10, 10},
+		},
+		{
+			name:    "noisy but bounded",
+			samples: []float64{1, 9, 10, 14, 11, 13, 10, 15, 10, 12},
+		},
+		{
+			name:    "steady growth",
+			samples: []float64{1, 9, 10, 11, 12, 13, 14, 15, 16, 17},
+			leaks:   true,
+		},
+		{
+			name:    "growth within tolerance",
+			samples: []float64{1, 9, 100, 100, 101, 101, 102, 102, 103, 103},
+		},
+		{
+			name:    "growth that levels off",
+			samples: []float64{1, 9, 10, 12, 14, 16, 16, 16, 16, 16},
+		},
+		{
+			name:    "too few samples",
+			samples: []float64{1, 2, 3, 4, 5},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			d := New()
+			d.Add("counter", sequence(testCase.samples...))
+			for range testCase.samples {
+				if err := d.Sample(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := d.Check()
+			if testCase.leaks && err == nil {
+				t.Errorf("expected a leak to be detected\n%s", d.Report())
+			}
+			if !testCase.leaks && err != nil {
+				t.Errorf("unexpected leak: %v", err)
+			}
+		})
+	}
+}
+
+func TestGoroutines(t *testing.T) {
+	d := New()
+	d.Add("goroutines", Goroutines())
+
+	stop := make(chan struct{})
+	defer close(stop)
+	for i := 0; i < 10; i++ {
+		if err := d.Sample(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		go func() { <-stop }()
+	}
+
+	if err := d.Check(); err == nil {
+		t.Errorf("expected leaking goroutines to be detected\n%s", d.Report())
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	login := func() *vim25.Client {
+		c, err := vim25.NewClient(ctx, soap.NewClient(server.URL, true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = session.NewManager(c).Login(ctx, server.URL.User); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	probe := Sessions(login())
+	before, err := probe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login()
+
+	after, err := probe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after != before+1 {
+		t.Errorf("expected %g sessions, got %g", before+1, after)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	t.Setenv(DurationEnv, "")
+	d, err := Duration(time.Minute)
+	if err != nil || d != time.Minute {
+		t.Errorf("expected default duration, got %s (%v)", d, err)
+	}
+
+	t.Setenv(DurationEnv, "90s")
+	d, err = Duration(time.Minute)
+	if err != nil || d != 90*time.Second {
+		t.Errorf("expected 90s, got %s (%v)", d, err)
+	}
+}
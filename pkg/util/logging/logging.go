@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging overrides the klog verbosity per module, so that a module
+// can log at a higher or lower verbosity than the rest of the process, for
+// instance to trace the NSX-T load balancer at V(6) without the node
+// discovery logs of that level.
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+)
+
+// Modules whose verbosity can be overridden.
+const (
+	NodeManager       = "nodemanager"
+	LoadBalancer      = "loadbalancer"
+	Paravirtual       = "paravirtual"
+	ConnectionManager = "connectionmanager"
+)
+
+var modules = map[string]bool{
+	NodeManager:       true,
+	LoadBalancer:      true,
+	Paravirtual:       true,
+	ConnectionManager: true,
+}
+
+var (
+	verbosityLock sync.RWMutex
+	verbosity     = make(map[string]klog.Level)
+)
+
+// ValidateModuleVerbosity checks that the modules exist and the levels are
+// not negative.
+func ValidateModuleVerbosity(levels map[string]int) error {
+	for module, level := range levels {
+		if !modules[module] {
+			names := make([]string, 0, len(modules))
+			for name := range modules {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown logging module %q, expected one of %s", module, strings.Join(names, ", "))
+		}
+		if level < 0 {
+			return fmt.Errorf("invalid verbosity %d for logging module %q", level, module)
+		}
+	}
+	return nil
+}
+
+// SetModuleVerbosity replaces the verbosity overrides. Modules without an
+// override log at the verbosity of klog.
+func SetModuleVerbosity(levels map[string]int) error {
+	if err := ValidateModuleVerbosity(levels); err != nil {
+		return err
+	}
+
+	verbosityLock.Lock()
+	defer verbosityLock.Unlock()
+
+	verbosity = make(map[string]klog.Level, len(levels))
+	for module, level := range levels {
+		verbosity[module] = klog.Level(level)
+		klog.Infof("Logging module %s at verbosity %d", module, level)
+	}
+	return nil
+}
+
+func moduleVerbosity(module string) (klog.Level, bool) {
+	verbosityLock.RLock()
+	defer verbosityLock.RUnlock()
+
+	level, ok := verbosity[module]
+	return level, ok
+}
+
+// V is klog.V for the module: it reports whether logs of the level are
+// enabled, using the verbosity override of the module if it has one.
+func V(module string, level klog.Level) klog.Verbose {
+	if v, ok := moduleVerbosity(module); ok {
+		if level <= v {
+			return klog.V(0)
+		}
+		return klog.Verbose{}
+	}
+	return klog.VDepth(1, level)
+}
+
+// Logger returns a klog backed logr.Logger for the module, using the
+// verbosity override of the module if it has one.
+func Logger(module string) logr.Logger {
+	return logr.New(&moduleSink{
+		module: module,
+		// skip the frame of moduleSink
+		sink: klog.NewKlogr().WithCallDepth(1).GetSink(),
+	})
+}
+
+// moduleSink filters the logs of a klog sink with the verbosity override of
+// a module.
+type moduleSink struct {
+	module string
+	sink   logr.LogSink
+}
+
+var _ logr.CallDepthLogSink = &moduleSink{}
+
+func (s *moduleSink) Init(info logr.RuntimeInfo) {}
+
+func (s *moduleSink) Enabled(level int) bool {
+	if v, ok := moduleVerbosity(s.module); ok {
+		return klog.Level(level) <= v
+	}
+	return s.sink.Enabled(level)
+}
+
+func (s *moduleSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	// klog checks the level again, logs enabled by the override go out at 0
+	if _, ok := moduleVerbosity(s.module); ok {
+		level = 0
+	}
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *moduleSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *moduleSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &moduleSink{module: s.module, sink: s.sink.WithValues(keysAndValues...)}
+}
+
+func (s *moduleSink) WithName(name string) logr.LogSink {
+	return &moduleSink{module: s.module, sink: s.sink.WithName(name)}
+}
+
+func (s *moduleSink) WithCallDepth(depth int) logr.LogSink {
+	if sink, ok := s.sink.(logr.CallDepthLogSink); ok {
+		return &moduleSink{module: s.module, sink: sink.WithCallDepth(depth)}
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"k8s.io/klog/v2"
+)
+
+func TestValidateModuleVerbosity(t *testing.T) {
+	if err := ValidateModuleVerbosity(map[string]int{NodeManager: 2, LoadBalancer: 6}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateModuleVerbosity(map[string]int{"nsx": 6}); err == nil {
+		t.Error("Unknown module should be rejected")
+	}
+	if err := ValidateModuleVerbosity(map[string]int{NodeManager: -1}); err == nil {
+		t.Error("Negative verbosity should be rejected")
+	}
+}
+
+func TestModuleVerbosity(t *testing.T) {
+	fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(fs)
+	_ = fs.Set("v", "2")
+	_ = fs.Set("logtostderr", "false")
+	defer func() {
+		_ = fs.Set("v", "0")
+		_ = fs.Set("logtostderr", "true")
+	}()
+
+	if err := SetModuleVerbosity(map[string]int{LoadBalancer: 6, NodeManager: 0}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = SetModuleVerbosity(nil)
+	}()
+
+	var buf bytes.Buffer
+	klog.SetOutput(&buf)
+	defer klog.SetOutput(nil)
+
+	V(LoadBalancer, 6).Info("lb-trace")
+	V(NodeManager, 2).Info("node-discovery")
+	V(ConnectionManager, 2).Info("connection")
+	V(ConnectionManager, 4).Info("connection-debug")
+	Logger(LoadBalancer).WithName("test").V(6).Info("logr-lb-trace")
+	Logger(Paravirtual).V(4).Info("logr-paravirtual-debug")
+	klog.Flush()
+
+	out := buf.String()
+	for _, expected := range []string{"lb-trace", "connection", "logr-lb-trace"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %s to be logged, got %s", expected, out)
+		}
+	}
+	for _, unexpected := range []string{"node-discovery", "connection-debug", "logr-paravirtual-debug"} {
+		if strings.Contains(out, unexpected) {
+			t.Errorf("Expected %s not to be logged, got %s", unexpected, out)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if !strings.Contains(line, "logging_test.go") {
+			t.Errorf("Expected the caller to be logged, got %s", line)
+		}
+	}
+}
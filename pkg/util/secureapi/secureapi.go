@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secureapi serves read-only endpoints exposing the internal state of
+// the cloud provider, such as the node mappings, behind the authentication and
+// authorization of the secure port of the cloud controller manager. Every
+// request is authenticated with a TokenReview and authorized with a
+// SubjectAccessReview of its non-resource URL, so that RBAC grants access to
+// each endpoint separately.
+package secureapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/options"
+	genericcontrollermanager "k8s.io/controller-manager/app"
+	"k8s.io/klog/v2"
+)
+
+var (
+	handlersLock sync.Mutex
+	handlers     = make(map[string]http.Handler)
+)
+
+// Register adds the handler under pattern, as understood by http.ServeMux,
+// replacing the handler previously registered under the same pattern.
+// Handlers are registered once the cloud provider is initialized, the
+// endpoints are not found until then.
+func Register(pattern string, handler http.Handler) {
+	handlersLock.Lock()
+	defer handlersLock.Unlock()
+
+	handlers[pattern] = handler
+}
+
+// Handler dispatches the requests to the registered handlers. Only GET
+// requests are served.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		mux := http.NewServeMux()
+		handlersLock.Lock()
+		for pattern, h := range handlers {
+			mux.Handle(pattern, h)
+		}
+		handlersLock.Unlock()
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Serve serves the registered handlers on address, with the certificates of
+// secureServing, until stop is closed. The requests are authenticated and
+// authorized the same way as those of the secure port of the cloud controller
+// manager.
+func Serve(address string, stop <-chan struct{}, secureServing *server.SecureServingInfo,
+	authentication *server.AuthenticationInfo, authorization *server.AuthorizationInfo) error {
+	if secureServing == nil {
+		return fmt.Errorf("the secure port of the cloud controller manager is disabled")
+	}
+	if authentication == nil || authentication.Authenticator == nil {
+		return fmt.Errorf("the authentication of the secure port is not configured")
+	}
+	if authorization == nil || authorization.Authorizer == nil {
+		return fmt.Errorf("the authorization of the secure port is not configured")
+	}
+
+	listener, _, err := options.CreateListener("tcp", address, net.ListenConfig{})
+	if err != nil {
+		return err
+	}
+	serving := *secureServing
+	serving.Listener = listener
+
+	if _, _, err := serving.Serve(securedHandler(authentication, authorization), 0, stop); err != nil {
+		return err
+	}
+	klog.Infof("Serving the secure API on %s", address)
+	return nil
+}
+
+// securedHandler wraps Handler in the handler chain of the secure port of the
+// cloud controller manager. The requests are authorized as non-resource
+// requests of their path with the verb get.
+func securedHandler(authentication *server.AuthenticationInfo, authorization *server.AuthorizationInfo) http.Handler {
+	return genericcontrollermanager.BuildHandlerChain(Handler(), authorization, authentication)
+}
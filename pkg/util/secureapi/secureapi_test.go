@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secureapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/server"
+)
+
+func TestSecuredHandler(t *testing.T) {
+	t.Cleanup(func() {
+		handlersLock.Lock()
+		delete(handlers, "/vsphere/v1/nodes/")
+		handlersLock.Unlock()
+	})
+	Register("/vsphere/v1/nodes/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	}))
+
+	authentication := &server.AuthenticationInfo{
+		Authenticator: authenticator.RequestFunc(func(r *http.Request) (*authenticator.Response, bool, error) {
+			if r.Header.Get("Authorization") != "Bearer dashboard" {
+				return nil, false, nil
+			}
+			return &authenticator.Response{User: &user.DefaultInfo{Name: "dashboard"}}, true, nil
+		}),
+	}
+	var attributes authorizer.Attributes
+	authorization := &server.AuthorizationInfo{
+		Authorizer: authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+			attributes = a
+			if a.GetPath() == "/vsphere/v1/nodes/node-1" {
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionNoOpinion, "", nil
+		}),
+	}
+	handler := securedHandler(authentication, authorization)
+
+	get := func(method, path, token string) (int, string) {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code, recorder.Body.String()
+	}
+
+	if code, _ := get(http.MethodGet, "/vsphere/v1/nodes/node-1", ""); code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated request should be rejected, got %d", code)
+	}
+	if code, body := get(http.MethodGet, "/vsphere/v1/nodes/node-1", "dashboard"); code != http.StatusOK || body != "/vsphere/v1/nodes/node-1" {
+		t.Errorf("authorized request should be served, got %d %q", code, body)
+	}
+	if attributes.IsResourceRequest() || attributes.GetVerb() != "get" || attributes.GetUser().GetName() != "dashboard" {
+		t.Errorf("request should be authorized as a non-resource get of the user, got %+v", attributes)
+	}
+	if code, _ := get(http.MethodGet, "/vsphere/v1/nodes/node-2", "dashboard"); code != http.StatusForbidden {
+		t.Errorf("unauthorized request should be forbidden, got %d", code)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/vsphere/v1/unknown", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unregistered path should not be found, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/vsphere/v1/unknown", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("only get should be allowed, got %d", recorder.Code)
+	}
+}
@@ -61,6 +61,10 @@ var (
 	// chartFolder is the folder to store vsphere-cpi chart for testing
 	chartFolder string
 
+	// manifestPath is the release manifest the CPI is installed from instead
+	// of the helm chart, for example releases/v1.32/vsphere-cloud-controller-manager.yaml
+	manifestPath string
+
 	// clusterctlConfig is the file which tests will use as a clusterctl config.
 	// If it is not set, a local clusterctl repository (including a clusterctl config) will be created automatically.
 	clusterctlConfig string
@@ -92,7 +96,8 @@ var (
 	// helm install configurations
 	namespace = "kube-system"
 
-	// helm install expectation
+	// helm install expectation, the name of the DaemonSet of the manifest
+	// when the CPI is installed from a manifest
 	daemonsetName = "vsphere-cpi"
 )
 
@@ -101,6 +106,7 @@ func init() {
 	flag.StringVar(&artifactFolder, "e2e.artifacts-folder", "", "folder where e2e test artifact should be stored")
 	flag.StringVar(&clusterctlConfig, "e2e.clusterctl-config", "", "file which tests will use as a clusterctl config. If it is not set, a local clusterctl repository (including a clusterctl config) will be created automatically.")
 	flag.StringVar(&chartFolder, "e2e.chart-folder", "", "folder where the helm chart for e2e should be stored")
+	flag.StringVar(&manifestPath, "e2e.manifest", "", "if set, the CPI is installed from this release manifest instead of the helm chart, for example releases/v1.32/vsphere-cloud-controller-manager.yaml")
 	flag.StringVar(&image, "e2e.image", "gcr.io/k8s-staging-cloud-pv-vsphere/cloud-provider-vsphere", "the cloud-controller-manager image to be tested, for example, gcr.io/k8s-staging-cloud-pv-vsphere/cloud-provider-vsphere")
 	flag.StringVar(&version, "e2e.version", "dev", "the cloud-controller-manager version to be tested, for example, v1.22.3-76-g6f4fa01")
 	flag.BoolVar(&useExistingCluster, "e2e.use-existing-cluster", false,
@@ -254,34 +260,52 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 		vsphereframework.LoadImagesFunc(ctx)(proxy.GetWorkloadCluster(ctx, workloadKubeconfigNamespace, workloadName))
 	})
 
-	By("Install dev vsphere cpi using helm on workload cluster", func() {
-		cmdName := "helm"
-		cmdArgs := []string{
-			"install", "vsphere-cpi", "vsphere-cpi/vsphere-cpi",
-			"--namespace", namespace,
-			"--set", "config.enabled=true",
-			"--set", "config.name=cloud-config",
-			"--set", "config.vcenter=" + e2eConfig.GetVariable("VSPHERE_SERVER"),
-			"--set", "config.username=" + e2eConfig.GetVariable("VSPHERE_USERNAME"),
-			"--set", "config.password=" + e2eConfig.GetVariable("VSPHERE_PASSWORD"),
-			"--set", "config.datacenter=" + e2eConfig.GetVariable("VSPHERE_DATACENTER"),
-			"--set", "config.region=" + "",
-			"--set", "config.zone=" + "",
-			"--set", "daemonset.image=" + image,
-			"--set", "daemonset.tag=" + version,
-			"--set", "securityContext.enabled=false",
-		}
-
-		// Create the command
-		cmd := exec.Command(cmdName, cmdArgs...)
-		cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", workloadKubeconfig))
-
-		// Capture the output (stdout and stderr)
-		output, err := cmd.CombinedOutput()
-		Expect(err).NotTo(HaveOccurred())
+	if manifestPath == "" {
+		By("Install dev vsphere cpi using helm on workload cluster", func() {
+			cmdName := "helm"
+			cmdArgs := []string{
+				"install", "vsphere-cpi", "vsphere-cpi/vsphere-cpi",
+				"--namespace", namespace,
+				"--set", "config.enabled=true",
+				"--set", "config.name=cloud-config",
+				"--set", "config.vcenter=" + e2eConfig.GetVariable("VSPHERE_SERVER"),
+				"--set", "config.username=" + e2eConfig.GetVariable("VSPHERE_USERNAME"),
+				"--set", "config.password=" + e2eConfig.GetVariable("VSPHERE_PASSWORD"),
+				"--set", "config.datacenter=" + e2eConfig.GetVariable("VSPHERE_DATACENTER"),
+				"--set", "config.region=" + "",
+				"--set", "config.zone=" + "",
+				"--set", "daemonset.image=" + image,
+				"--set", "daemonset.tag=" + version,
+				"--set", "securityContext.enabled=false",
+			}
+
+			// Create the command
+			cmd := exec.Command(cmdName, cmdArgs...)
+			cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", workloadKubeconfig))
+
+			// Capture the output (stdout and stderr)
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+
+			klog.Infof("Command output: %s\n", string(output))
+		})
+	} else {
+		By("Install dev vsphere cpi from the release manifest on workload cluster", func() {
+			rendered, err := renderManifest(manifestPath, manifestConfig{
+				server:     e2eConfig.GetVariable("VSPHERE_SERVER"),
+				username:   e2eConfig.GetVariable("VSPHERE_USERNAME"),
+				password:   e2eConfig.GetVariable("VSPHERE_PASSWORD"),
+				datacenter: e2eConfig.GetVariable("VSPHERE_DATACENTER"),
+				image:      image + ":" + version,
+			})
+			Expect(err).NotTo(HaveOccurred())
 
-		klog.Infof("Command output: %s\n", string(output))
-	})
+			workloadProxy := proxy.GetWorkloadCluster(ctx, workloadKubeconfigNamespace, workloadName)
+			Expect(workloadProxy.CreateOrUpdate(ctx, rendered.resources)).To(Succeed())
+			daemonsetName = rendered.daemonSetName
+			cloudConfigName = rendered.cloudConfigName
+		})
+	}
 
 	By("Watching vsphere-cpi daemonset logs", func() {
 		workloadProxy := proxy.GetWorkloadCluster(ctx, workloadKubeconfigNamespace, workloadName)
@@ -309,6 +333,11 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 
 var _ = SynchronizedAfterSuite(func() {}, func() {
 	// after all parallel test cases finish
+	if workloadClientset != nil {
+		By("Collect workload cluster logs to artifacts", func() {
+			collectMachineLogs(ctx, workloadClientset, filepath.Join(artifactFolder, "clusters", workloadName))
+		})
+	}
 	if !skipCleanup {
 		By("Dump all resources to artifacts", func() {
 			framework.DumpAllResources(ctx, framework.DumpAllResourcesInput{
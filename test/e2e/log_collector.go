@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// machineLogsTimeout bounds the collection of the artifacts of a machine,
+// so that an unreachable node or vCenter does not hang the suite
+const machineLogsTimeout = 2 * time.Minute
+
+// cloudConfigName is the name of the config map holding the cloud-config
+// rendered by the helm chart, or the one of the release manifest when the
+// CPI is installed from a manifest
+var cloudConfigName = "cloud-config"
+
+// sensitiveConfigLine matches the lines of a cloud-config holding
+// credentials, in YAML or INI format
+var sensitiveConfigLine = regexp.MustCompile(`(?im)^(\s*"?[\w.-]*(password|secret|token|user)[\w.-]*"?\s*[:=]\s*).+$`)
+
+// collectMachineLogs stores the artifacts needed to diagnose a failure of the
+// workload cluster under outputPath: the redacted cloud-config, the
+// description of the Services and, for every machine, the description of its
+// Node, the logs of the vsphere-cpi pods running on it and the state of its
+// VM. Machines are collected in parallel, each within machineLogsTimeout.
+// Failures are logged only, as the collection must not fail the suite.
+func collectMachineLogs(ctx context.Context, clientset kubernetes.Interface, outputPath string) {
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		klog.Errorf("Failed to create log folder %s: %v", outputPath, err)
+		return
+	}
+
+	if err := collectCloudConfig(ctx, clientset, filepath.Join(outputPath, "cloud-config.yaml")); err != nil {
+		klog.Errorf("Failed to collect the cloud-config: %v", err)
+	}
+	if err := kubectlDescribe(ctx, filepath.Join(outputPath, "services.txt"), "services", "--all-namespaces"); err != nil {
+		klog.Errorf("Failed to describe the services: %v", err)
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.Errorf("Failed to list the nodes: %v", err)
+		return
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "component=cloud-controller-manager",
+	})
+	if err != nil {
+		klog.Errorf("Failed to list the vsphere-cpi pods: %v", err)
+		pods = &corev1.PodList{}
+	}
+
+	var wg sync.WaitGroup
+	for _, node := range nodes.Items {
+		wg.Add(1)
+		go func(node corev1.Node) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, machineLogsTimeout)
+			defer cancel()
+			collectNodeLogs(ctx, clientset, node, pods.Items, filepath.Join(outputPath, "machines", node.Name))
+		}(node)
+	}
+	wg.Wait()
+}
+
+// collectNodeLogs stores the artifacts of a machine in outputPath
+func collectNodeLogs(ctx context.Context, clientset kubernetes.Interface, node corev1.Node, pods []corev1.Pod, outputPath string) {
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		klog.Errorf("Failed to create log folder %s: %v", outputPath, err)
+		return
+	}
+
+	if err := kubectlDescribe(ctx, filepath.Join(outputPath, "node.txt"), "node", node.Name); err != nil {
+		klog.Errorf("Failed to describe node %s: %v", node.Name, err)
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != node.Name {
+			continue
+		}
+		if err := collectPodLogs(ctx, clientset, pod, outputPath); err != nil {
+			klog.Errorf("Failed to collect the logs of pod %s on node %s: %v", pod.Name, node.Name, err)
+		}
+	}
+	if vsphere != nil {
+		if err := collectVMState(ctx, node.Name, filepath.Join(outputPath, "vm.txt")); err != nil {
+			klog.Errorf("Failed to collect the VM state of node %s: %v", node.Name, err)
+		}
+	}
+}
+
+// collectPodLogs stores the logs of the containers of a pod, and of their
+// previous instance if they restarted
+func collectPodLogs(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod, outputPath string) error {
+	for _, status := range pod.Status.ContainerStatuses {
+		previous := []bool{false}
+		if status.RestartCount > 0 {
+			previous = append(previous, true)
+		}
+		for _, prev := range previous {
+			logs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: status.Name,
+				Previous:  prev,
+			}).DoRaw(ctx)
+			if err != nil {
+				return err
+			}
+			name := fmt.Sprintf("%s-%s.log", pod.Name, status.Name)
+			if prev {
+				name = fmt.Sprintf("%s-%s-previous.log", pod.Name, status.Name)
+			}
+			if err := os.WriteFile(filepath.Join(outputPath, name), logs, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectCloudConfig stores the cloud-config of the CPI, without credentials
+func collectCloudConfig(ctx context.Context, clientset kubernetes.Interface, file string) error {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, cloudConfigName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	var content strings.Builder
+	for key, value := range configMap.Data {
+		fmt.Fprintf(&content, "# %s\n%s\n", key, redactCloudConfig(value))
+	}
+	return os.WriteFile(file, []byte(content.String()), 0644)
+}
+
+// redactCloudConfig replaces the values of the credentials in a cloud-config
+func redactCloudConfig(config string) string {
+	return sensitiveConfigLine.ReplaceAllString(config, "${1}<redacted>")
+}
+
+// collectVMState stores the state of the VM of a node, as seen by the
+// cloud provider when it discovers the node
+func collectVMState(ctx context.Context, name string, file string) error {
+	vm, err := vsphere.Finder.VirtualMachine(ctx, name)
+	if err != nil {
+		return err
+	}
+	var o mo.VirtualMachine
+	if err := vm.Properties(ctx, vm.Reference(), []string{"summary", "guest"}, &o); err != nil {
+		return err
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "Path: %s\n", vm.InventoryPath)
+	fmt.Fprintf(&content, "UUID: %s\n", o.Summary.Config.Uuid)
+	fmt.Fprintf(&content, "Instance UUID: %s\n", o.Summary.Config.InstanceUuid)
+	fmt.Fprintf(&content, "Power state: %s\n", o.Summary.Runtime.PowerState)
+	if o.Guest != nil {
+		fmt.Fprintf(&content, "Tools running status: %s\n", o.Guest.ToolsRunningStatus)
+		fmt.Fprintf(&content, "Guest hostname: %s\n", o.Guest.HostName)
+		for _, nic := range o.Guest.Net {
+			fmt.Fprintf(&content, "NIC %s network %q: %s\n", nic.MacAddress, nic.Network, strings.Join(nic.IpAddress, ", "))
+		}
+	}
+	return os.WriteFile(file, []byte(content.String()), 0644)
+}
+
+// kubectlDescribe stores the output of kubectl describe on the workload
+// cluster in file
+func kubectlDescribe(ctx context.Context, file string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "kubectl", append([]string{"describe"}, args...)...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", workloadKubeconfig))
+	output, err := cmd.CombinedOutput()
+	if werr := os.WriteFile(file, output, 0644); werr != nil {
+		return werr
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cluster-api/util/yaml"
+)
+
+// manifestConfig is the vCenter the CPI installed from a release manifest
+// connects to, taken from the e2e config
+type manifestConfig struct {
+	server     string
+	username   string
+	password   string
+	datacenter string
+	// image of the cloud-controller-manager, with its tag
+	image string
+}
+
+// renderedManifest is a release manifest configured for the e2e vCenter
+type renderedManifest struct {
+	resources []byte
+	// names of the DaemonSet and of the cloud-config ConfigMap of the manifest
+	daemonSetName   string
+	cloudConfigName string
+}
+
+// renderManifest reads the release manifest at path, like
+// releases/v1.32/vsphere-cloud-controller-manager.yaml, and configures it as
+// the helm install does: the Secret holds the credentials of the vCenter, the
+// cloud-config only names the vCenter and its datacenter, and the DaemonSet
+// runs the image under test without the pod security context. The RBAC and the
+// rest of the DaemonSet are kept as they are in the manifest, so that they are
+// exercised by the suite.
+func renderManifest(path string, cfg manifestConfig) (*renderedManifest, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := yaml.ToUnstructured(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", path, err)
+	}
+
+	rendered := &renderedManifest{}
+	var secret, configMap *unstructured.Unstructured
+	for i := range objs {
+		o := &objs[i]
+		switch o.GetKind() {
+		case "Secret":
+			secret = o
+		case "ConfigMap":
+			configMap = o
+		case "DaemonSet":
+			rendered.daemonSetName = o.GetName()
+			if err := setManifestImage(o, cfg.image); err != nil {
+				return nil, err
+			}
+			unstructured.RemoveNestedField(o.Object, "spec", "template", "spec", "securityContext")
+		}
+	}
+	if secret == nil || configMap == nil || rendered.daemonSetName == "" {
+		return nil, fmt.Errorf("manifest %s must hold a Secret, a ConfigMap and a DaemonSet", path)
+	}
+
+	if err := unstructured.SetNestedStringMap(secret.Object, map[string]string{
+		cfg.server + ".username": cfg.username,
+		cfg.server + ".password": cfg.password,
+	}, "stringData"); err != nil {
+		return nil, err
+	}
+
+	rendered.cloudConfigName = configMap.GetName()
+	cloudConfig := fmt.Sprintf(`global:
+  port: 443
+  insecureFlag: true
+  secretName: %s
+  secretNamespace: %s
+vcenter:
+  %s:
+    server: %s
+    datacenters:
+      - %s
+`, secret.GetName(), secret.GetNamespace(), cfg.server, cfg.server, cfg.datacenter)
+	if err := unstructured.SetNestedStringMap(configMap.Object, map[string]string{
+		"vsphere.conf": cloudConfig,
+	}, "data"); err != nil {
+		return nil, err
+	}
+
+	if rendered.resources, err = yaml.FromUnstructured(objs); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// setManifestImage sets the image of the containers of a DaemonSet
+func setManifestImage(daemonSet *unstructured.Unstructured, image string) error {
+	containers, _, err := unstructured.NestedSlice(daemonSet.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("DaemonSet %s has no container", daemonSet.GetName())
+	}
+	for _, c := range containers {
+		if container, ok := c.(map[string]interface{}); ok {
+			container["image"] = image
+		}
+	}
+	return unstructured.SetNestedSlice(daemonSet.Object, containers, "spec", "template", "spec", "containers")
+}